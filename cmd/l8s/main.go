@@ -1,16 +1,30 @@
 package main
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"l8s/pkg/cli"
+	"l8s/pkg/config"
 	"l8s/pkg/errors"
 	"l8s/pkg/logging"
+	"l8s/pkg/progress"
 	"github.com/spf13/cobra"
 )
 
+// Set with -ldflags by the Makefile
+var (
+	Version   = "dev"
+	BuildTime = ""
+)
+
+// logLevel is the terminal log level, lowered by --verbose once flags are parsed
+var logLevel = new(slog.LevelVar)
+
 func main() {
 	// Initialize logging
 	initLogging()
@@ -26,7 +40,39 @@ Each container is a fully-featured Linux environment with development tools,
 accessible via SSH using key-based authentication.`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if verbose, _ := cmd.Flags().GetBool("verbose"); verbose && logLevel.Level() > slog.LevelDebug {
+				logLevel.Set(slog.LevelDebug)
+			}
+			if legacy, err := config.MigrateLegacy(); err != nil {
+				return err
+			} else if legacy != "" {
+				fmt.Fprintf(os.Stderr, "Moved l8s configuration from %s to %s\n", legacy, config.ConfigDir())
+			}
+			if migration, err := config.Migrate(config.GetConfigPath()); err != nil {
+				return err
+			} else if migration != nil {
+				fmt.Fprintf(os.Stderr, "Upgraded %s from version %d to %d (original saved as %s)\n",
+					config.GetConfigPath(), migration.From, migration.To, migration.Backup)
+				for _, change := range migration.Changes {
+					fmt.Fprintf(os.Stderr, "  - %s\n", change)
+				}
+			}
+			if connection, _ := cmd.Flags().GetString("connection"); connection != "" {
+				if err := config.SetFlagOverride("active_connection", connection, "--connection"); err != nil {
+					return err
+				}
+			}
+			mode, _ := cmd.Flags().GetString("progress")
+			return progress.Configure(mode)
+		},
 	}
+	rootCmd.PersistentFlags().String("connection", "",
+		"Use this connection for a single command without changing the active one")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false,
+		"Show debug logs for this invocation")
+	rootCmd.PersistentFlags().String("progress", progress.ModeText,
+		"Progress output: text, or json to emit JSON-line progress events on stderr")
 
 	// Define command groups for better organization
 	rootCmd.AddGroup(
@@ -60,33 +106,85 @@ accessible via SSH using key-based authentication.`,
 		factory.StartCmd(),
 		factory.StopCmd(),
 		factory.RemoveCmd(),
+		factory.UndoRemoveCmd(),
+		factory.ProtectCmd(),
+		factory.UnprotectCmd(),
+		factory.LabelCmd(),
+		factory.NoteCmd(),
 		factory.RebuildCmd(),
 		factory.RebuildAllCmd(),
 		factory.InfoCmd(),
+		factory.InspectCmd(),
+		factory.DriftCmd(),
+		factory.HistoryCmd(),
+		factory.WhichCmd(),
+		factory.CdCmd(),
+		factory.DuCmd(),
+		factory.GcCmd(),
+		factory.CloneCmd(),
+		factory.AdoptCmd(),
+		factory.DisownCmd(),
+		factory.MigratePrefixCmd(),
+		factory.MountCmd(),
+		factory.UmountCmd(),
+		factory.MountListCmd(),
+		factory.GroupCmd(),
+		factory.OpenCmd(),
+		factory.BackupCmd(),
 		factory.BuildCmd(),
+		factory.ScanCmd(),
+		factory.SecurityCmd(),
 		factory.RemoteCmd(),
 		factory.ExecCmd(),
 		factory.PasteCmd(),
 		factory.TeamCmd(),
 		factory.PushCmd(),
 		factory.PullCmd(),
+		factory.FetchCmd(),
 		factory.StatusCmd(),
 		factory.ConnectionCmd(),
+		factory.SSHConfigCmd(),
+		factory.IngressCmd(),
+		factory.ConfigCmd(),
+		factory.StatsCmd(),
+		factory.CICmd(),
+		factory.ReviewCmd(),
+		factory.SparseCmd(),
+		factory.AgentCmd(),
+		factory.NotifyCmd(),
+		factory.TailCmd(),
+		factory.ServeCmd(),
+		factory.VersionCmd(Version, BuildTime),
 		factory.InstallZSHPluginCmd(),
+		factory.InstallShellPluginCmd(),
+		factory.ShellEnvCmd(),
+		factory.DocsCmd(),
+		factory.EmbedCmd(),
 		factory.AudioCmd(),
 	)
+	rootCmd.AddCommand(factory.HelpTopicCmds()...)
 
-	if err := rootCmd.Execute(); err != nil {
+	start := time.Now()
+	executed, err := rootCmd.ExecuteC()
+	cli.RecordUsage(executed, time.Since(start), err)
+	logCommand(executed, time.Since(start), err)
+	if err != nil {
 		errors.PrintError(err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
 
+// initLogging logs to stderr at L8S_LOG_LEVEL (trace adds every remote call,
+// SSH command and git invocation; --verbose lowers it to debug later) and
+// everything, including debug, to l8s.log in the state directory.
+// L8S_LOG_FILE moves the file, or disables it when set to "off".
 func initLogging() {
 	// Get log level from environment
 	level := slog.LevelInfo
 	if envLevel := os.Getenv("L8S_LOG_LEVEL"); envLevel != "" {
 		switch strings.ToLower(envLevel) {
+		case "trace":
+			level = logging.LevelTrace
 		case "debug":
 			level = slog.LevelDebug
 		case "warn", "warning":
@@ -102,14 +200,48 @@ func initLogging() {
 		format = strings.ToLower(envFormat)
 	}
 
+	logFile := filepath.Join(config.StateDir(), "l8s.log")
+	if envFile, ok := os.LookupEnv("L8S_LOG_FILE"); ok {
+		logFile = envFile
+		if strings.EqualFold(envFile, "off") {
+			logFile = ""
+		}
+	}
+
 	// Create logger configuration
+	levelName := strings.ToLower(level.String())
+	if level == logging.LevelTrace {
+		levelName = "trace"
+	}
 	cfg := logging.Config{
-		Level:  strings.ToLower(level.String()),
-		Format: format,
-		Output: "stderr",
+		Level:    levelName,
+		LevelVar: logLevel,
+		Format:   format,
+		Output:   "stderr",
+		File:     logFile,
 	}
 
-	// Create and set logger
-	logger, _ := logging.NewLogger(cfg)
+	// Create and set logger; a log file that cannot be opened is not fatal
+	logger, err := logging.NewLogger(cfg)
 	logging.SetDefault(logger)
-}
\ No newline at end of file
+	if err != nil {
+		logging.Debug("file logging disabled", logging.WithError(err))
+	}
+}
+
+// logCommand records how an invocation ended, mainly for the log file
+func logCommand(cmd *cobra.Command, d time.Duration, err error) {
+	if cmd == nil {
+		return
+	}
+	attrs := []any{
+		logging.WithField("command", cmd.CommandPath()),
+		logging.WithField("args", os.Args[1:]),
+		logging.WithField("duration", d.String()),
+	}
+	if err != nil {
+		logging.Debug("command failed", append(attrs, logging.WithError(err))...)
+		return
+	}
+	logging.Debug("command finished", attrs...)
+}
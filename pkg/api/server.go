@@ -0,0 +1,520 @@
+// Package api serves an authenticated HTTP control API for l8s containers so
+// dashboards and chatops bots can manage them through the same container
+// manager the CLI uses.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"l8s/pkg/container"
+	"l8s/pkg/logging"
+	"l8s/pkg/notify"
+)
+
+// Manager is the subset of container management the API exposes
+type Manager interface {
+	CreateContainer(ctx context.Context, name, sshKey string, opts container.CreateOptions) (*container.Container, error)
+	ListContainers(ctx context.Context) ([]*container.Container, error)
+	GetContainerInfo(ctx context.Context, name string) (*container.Container, error)
+	StartContainer(ctx context.Context, name string) error
+	StopContainer(ctx context.Context, name string) error
+	RemoveContainer(ctx context.Context, name string, removeVolumes bool) error
+	TrashContainer(ctx context.Context, name string) error
+	ExecContainerOutput(ctx context.Context, name string, cmd []string) (string, error)
+	RemoteVersion(ctx context.Context) (*container.RemoteVersion, error)
+	GetActivity(ctx context.Context, name string) (*container.Activity, error)
+}
+
+// Options configures a Server
+type Options struct {
+	Token      string // Bearer token every request must carry
+	Prefix     string // Container name prefix, stripped from names in responses
+	Connection string // Active connection, used when a request names none
+	SSHKey     string // Public key installed when a create request has none
+
+	// Connections lists every configured connection. Requests select one
+	// with ?connection=<name>; Connect opens managers for those that are
+	// not active.
+	Connections []Connection
+	Connect     func(name string) (Manager, error)
+
+	// RemoveGuard returns the guard applied before removals on a connection,
+	// as l8s remove applies it; Force and KeepVolumes are set per request
+	RemoveGuard func(connection string) container.RemoveGuard
+	// Notify, if set, fires the configured webhooks for an event
+	Notify func(event, connection, name string)
+}
+
+// maxRequestBody caps JSON request bodies
+const maxRequestBody = 64 << 10
+
+// Connection describes a configured Podman host
+type Connection struct {
+	Name        string `json:"name"`
+	Address     string `json:"address"`
+	Description string `json:"description,omitempty"`
+	Active      bool   `json:"active"`
+}
+
+// Container is the API representation of a container
+type Container struct {
+	Name       string            `json:"name"`      // Short name used in API paths
+	Container  string            `json:"container"` // Full Podman container name
+	Connection string            `json:"connection"`
+	Status     string            `json:"status"`
+	Health     string            `json:"health,omitempty"`
+	SSHPort    int               `json:"ssh_port"`
+	WebPort    int               `json:"web_port"`
+	Image      string            `json:"image,omitempty"`
+	Owner      string            `json:"owner,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Idle       *int64            `json:"idle_seconds,omitempty"` // Seconds without interactive use; listed running containers only
+}
+
+// Status describes the server and the Podman host behind it
+type Status struct {
+	Connection string `json:"connection"`
+	Podman     string `json:"podman,omitempty"`
+	Containers int    `json:"containers"`
+	Running    int    `json:"running"`
+}
+
+// CreateRequest is the body of POST /v1/containers
+type CreateRequest struct {
+	Name   string `json:"name"`
+	SSHKey string `json:"ssh_key,omitempty"`
+}
+
+// ExecRequest is the body of POST /v1/containers/{name}/exec
+type ExecRequest struct {
+	Command []string `json:"command"`
+}
+
+// ExecResponse holds the combined output of an exec request
+type ExecResponse struct {
+	Output string `json:"output"`
+}
+
+// errorResponse is returned with every non-2xx status
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server implements the control API
+type Server struct {
+	mgr    Manager
+	opts   Options
+	logger *slog.Logger
+	mux    *http.ServeMux
+
+	// mu serializes changes so concurrent creates cannot pick the same ports
+	mu sync.Mutex
+
+	// managers caches connections opened through Options.Connect
+	managersMu sync.Mutex
+	managers   map[string]Manager
+}
+
+// NewServer returns a Server backed by mgr for the active connection
+func NewServer(mgr Manager, opts Options) *Server {
+	s := &Server{
+		mgr:      mgr,
+		opts:     opts,
+		logger:   logging.Default(),
+		mux:      http.NewServeMux(),
+		managers: make(map[string]Manager),
+	}
+	s.mux.HandleFunc("GET /v1/status", s.handleStatus)
+	s.mux.HandleFunc("GET /v1/connections", s.handleConnections)
+	s.mux.HandleFunc("GET /v1/containers", s.handleList)
+	s.mux.HandleFunc("POST /v1/containers", s.handleCreate)
+	s.mux.HandleFunc("GET /v1/containers/{name}", s.handleGet)
+	s.mux.HandleFunc("DELETE /v1/containers/{name}", s.handleRemove)
+	s.mux.HandleFunc("POST /v1/containers/{name}/start", s.handleStart)
+	s.mux.HandleFunc("POST /v1/containers/{name}/stop", s.handleStop)
+	s.mux.HandleFunc("POST /v1/containers/{name}/exec", s.handleExec)
+	return s
+}
+
+// Handle registers an additional handler, such as a UI, on the server's mux.
+// Paths under /v1/ require the API token; other paths are served as is.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// ServeHTTP authenticates API requests and dispatches them
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	if strings.HasPrefix(r.URL.Path, "/v1/") && !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="l8s"`)
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid API token"))
+		s.logger.Warn("rejected API request",
+			logging.WithField("method", r.Method),
+			logging.WithField("path", r.URL.Path),
+			logging.WithField("remote", r.RemoteAddr))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+	s.logger.Debug("API request",
+		logging.WithField("method", r.Method),
+		logging.WithField("path", r.URL.Path),
+		logging.WithField("duration", time.Since(start)))
+}
+
+// authorized checks the bearer token in constant time
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || s.opts.Token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	mgr, conn, ok := s.manager(w, r)
+	if !ok {
+		return
+	}
+	containers, err := mgr.ListContainers(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	status := Status{Connection: conn, Containers: len(containers)}
+	for _, c := range containers {
+		if c.Status == "running" {
+			status.Running++
+		}
+	}
+	if v, err := mgr.RemoteVersion(r.Context()); err == nil {
+		status.Podman = v.Podman
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) handleConnections(w http.ResponseWriter, r *http.Request) {
+	connections := s.opts.Connections
+	if connections == nil {
+		connections = []Connection{{Name: s.opts.Connection, Active: true}}
+	}
+	writeJSON(w, http.StatusOK, connections)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	mgr, conn, ok := s.manager(w, r)
+	if !ok {
+		return
+	}
+	containers, err := mgr.ListContainers(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	result := make([]Container, len(containers))
+	for i, c := range containers {
+		result[i] = s.toAPI(c, conn)
+	}
+	s.addIdle(r.Context(), mgr, result)
+	writeJSON(w, http.StatusOK, result)
+}
+
+// addIdle fills in idle time for running containers, querying them in
+// parallel and leaving it unset for any that do not answer in time
+func (s *Server) addIdle(ctx context.Context, mgr Manager, containers []Container) {
+	var wg sync.WaitGroup
+	now := time.Now()
+	for i := range containers {
+		if containers[i].Status != "running" {
+			continue
+		}
+		wg.Add(1)
+		go func(c *Container) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			activity, err := mgr.GetActivity(ctx, c.Name)
+			if err != nil {
+				return
+			}
+			idle := int64(activity.Idle(now).Seconds())
+			c.Idle = &idle
+		}(&containers[i])
+	}
+	wg.Wait()
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	mgr, conn, ok := s.manager(w, r)
+	if !ok {
+		return
+	}
+	var req CreateRequest
+	if err := decodeBody(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	name := s.shortName(req.Name)
+	if name == "" {
+		writeError(w, http.StatusBadRequest, errors.New("name is required"))
+		return
+	}
+	key := req.SSHKey
+	if key == "" {
+		key = s.opts.SSHKey
+	}
+	if key == "" {
+		writeError(w, http.StatusBadRequest, errors.New("ssh_key is required"))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := mgr.GetContainerInfo(r.Context(), name); err == nil {
+		writeError(w, http.StatusConflict, fmt.Errorf("container '%s' already exists", name))
+		return
+	}
+	c, err := mgr.CreateContainer(r.Context(), name, key, container.CreateOptions{})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.logger.Info("container created via API",
+		logging.WithField("container", c.Name),
+		logging.WithField("connection", conn))
+	// Report the state after startup rather than as first created
+	if started, err := mgr.GetContainerInfo(r.Context(), name); err == nil {
+		c = started
+	}
+	writeJSON(w, http.StatusCreated, s.toAPI(c, conn))
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	mgr, conn, ok := s.manager(w, r)
+	if !ok {
+		return
+	}
+	c, ok := s.lookup(w, r, mgr)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.toAPI(c, conn))
+}
+
+func (s *Server) handleRemove(w http.ResponseWriter, r *http.Request) {
+	mgr, conn, ok := s.manager(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := s.lookup(w, r, mgr); !ok {
+		return
+	}
+	name := s.shortName(r.PathValue("name"))
+
+	// Volumes go to the trash unless the request keeps or deletes them
+	query := r.URL.Query()
+	keepVolumes := query.Get("volumes") == "false"
+	trash := !keepVolumes && query.Get("trash") != "false"
+	var guard container.RemoveGuard
+	if s.opts.RemoveGuard != nil {
+		guard = s.opts.RemoveGuard(conn)
+	}
+	guard.Force = query.Get("force") == "true"
+	guard.ForceProtected = guard.Force
+	guard.KeepVolumes = keepVolumes || trash
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := container.CheckRemove(r.Context(), mgr, name, guard); err != nil {
+		var refused *container.RemoveRefusedError
+		if errors.As(err, &refused) {
+			writeError(w, http.StatusConflict, fmt.Errorf("%w; pass force=true to remove anyway", err))
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	var err error
+	if trash {
+		err = mgr.TrashContainer(r.Context(), name)
+	} else {
+		err = mgr.RemoveContainer(r.Context(), name, !keepVolumes)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.logger.Info("container removed via API",
+		logging.WithField("container", name),
+		logging.WithField("connection", conn),
+		logging.WithField("trash", trash))
+	if s.opts.Notify != nil {
+		s.opts.Notify(notify.EventRemove, conn, name)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleStart(w http.ResponseWriter, r *http.Request) {
+	s.changeState(w, r, Manager.StartContainer)
+}
+
+func (s *Server) handleStop(w http.ResponseWriter, r *http.Request) {
+	s.changeState(w, r, Manager.StopContainer)
+}
+
+// changeState runs a start or stop and responds with the updated container
+func (s *Server) changeState(w http.ResponseWriter, r *http.Request, fn func(Manager, context.Context, string) error) {
+	mgr, conn, ok := s.manager(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := s.lookup(w, r, mgr); !ok {
+		return
+	}
+	name := s.shortName(r.PathValue("name"))
+
+	s.mu.Lock()
+	err := fn(mgr, r.Context(), name)
+	s.mu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	c, err := mgr.GetContainerInfo(r.Context(), name)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.toAPI(c, conn))
+}
+
+func (s *Server) handleExec(w http.ResponseWriter, r *http.Request) {
+	mgr, _, ok := s.manager(w, r)
+	if !ok {
+		return
+	}
+	var req ExecRequest
+	if err := decodeBody(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if len(req.Command) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("command is required"))
+		return
+	}
+	if _, ok := s.lookup(w, r, mgr); !ok {
+		return
+	}
+	output, err := mgr.ExecContainerOutput(r.Context(), s.shortName(r.PathValue("name")), req.Command)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ExecResponse{Output: output})
+}
+
+// errUnknownConnection is returned for connections that are not configured
+var errUnknownConnection = errors.New("connection not found")
+
+// manager returns the manager for the connection a request selects, writing
+// an error response if it is unknown or cannot be reached
+func (s *Server) manager(w http.ResponseWriter, r *http.Request) (Manager, string, bool) {
+	name := r.URL.Query().Get("connection")
+	mgr, conn, err := s.managerFor(name)
+	if errors.Is(err, errUnknownConnection) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("connection '%s' not found", name))
+		return nil, "", false
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return nil, "", false
+	}
+	return mgr, conn, true
+}
+
+// managerFor returns the manager for a connection, opening it on first use.
+// An empty name selects the active connection.
+func (s *Server) managerFor(name string) (Manager, string, error) {
+	if name == "" || name == s.opts.Connection {
+		return s.mgr, s.opts.Connection, nil
+	}
+	known := false
+	for _, c := range s.opts.Connections {
+		known = known || c.Name == name
+	}
+	if !known || s.opts.Connect == nil {
+		return nil, "", errUnknownConnection
+	}
+
+	s.managersMu.Lock()
+	defer s.managersMu.Unlock()
+	if mgr, ok := s.managers[name]; ok {
+		return mgr, name, nil
+	}
+	mgr, err := s.opts.Connect(name)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to connect to '%s': %w", name, err)
+	}
+	s.managers[name] = mgr
+	return mgr, name, nil
+}
+
+// lookup fetches the container named in the path, writing a 404 if it does
+// not exist
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request, mgr Manager) (*container.Container, bool) {
+	name := s.shortName(r.PathValue("name"))
+	c, err := mgr.GetContainerInfo(r.Context(), name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("container '%s' not found", name))
+		return nil, false
+	}
+	return c, true
+}
+
+// shortName accepts either the short or the full container name
+func (s *Server) shortName(name string) string {
+	if s.opts.Prefix == "" {
+		return name
+	}
+	return strings.TrimPrefix(name, s.opts.Prefix+"-")
+}
+
+func (s *Server) toAPI(c *container.Container, connection string) Container {
+	return Container{
+		Name:       s.shortName(c.Name),
+		Container:  c.Name,
+		Connection: connection,
+		Status:     c.Status,
+		Health:     c.Health,
+		SSHPort:    c.SSHPort,
+		WebPort:    c.WebPort,
+		Image:      c.Image,
+		Owner:      c.Labels[container.LabelOwner],
+		CreatedAt:  c.CreatedAt,
+		Labels:     c.Labels,
+	}
+}
+
+// decodeBody reads a JSON request body no larger than maxRequestBody
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) error {
+	return json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
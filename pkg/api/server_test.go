@@ -0,0 +1,227 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"l8s/pkg/container"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubManager keeps containers in memory, keyed by full name
+type stubManager struct {
+	containers map[string]*container.Container
+	execs      [][]string
+	trashed    []string
+}
+
+func newStubManager() *stubManager {
+	return &stubManager{containers: map[string]*container.Container{
+		"dev-web": {Name: "dev-web", Status: "running", SSHPort: 2200, WebPort: 3000,
+			Labels: map[string]string{container.LabelOwner: "alice"}},
+	}}
+}
+
+func (m *stubManager) CreateContainer(ctx context.Context, name, sshKey string, opts container.CreateOptions) (*container.Container, error) {
+	c := &container.Container{Name: "dev-" + name, Status: "running", SSHPort: 2201}
+	m.containers[c.Name] = c
+	return c, nil
+}
+
+func (m *stubManager) ListContainers(ctx context.Context) ([]*container.Container, error) {
+	var result []*container.Container
+	for _, c := range m.containers {
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+func (m *stubManager) GetContainerInfo(ctx context.Context, name string) (*container.Container, error) {
+	c, ok := m.containers["dev-"+name]
+	if !ok {
+		return nil, fmt.Errorf("no such container")
+	}
+	return c, nil
+}
+
+func (m *stubManager) StartContainer(ctx context.Context, name string) error {
+	m.containers["dev-"+name].Status = "running"
+	return nil
+}
+
+func (m *stubManager) StopContainer(ctx context.Context, name string) error {
+	m.containers["dev-"+name].Status = "exited"
+	return nil
+}
+
+func (m *stubManager) RemoveContainer(ctx context.Context, name string, removeVolumes bool) error {
+	delete(m.containers, "dev-"+name)
+	return nil
+}
+
+func (m *stubManager) TrashContainer(ctx context.Context, name string) error {
+	m.trashed = append(m.trashed, name)
+	delete(m.containers, "dev-"+name)
+	return nil
+}
+
+func (m *stubManager) ExecContainerOutput(ctx context.Context, name string, cmd []string) (string, error) {
+	m.execs = append(m.execs, cmd)
+	return "hello\n", nil
+}
+
+func (m *stubManager) RemoteVersion(ctx context.Context) (*container.RemoteVersion, error) {
+	return &container.RemoteVersion{Podman: "5.2.0"}, nil
+}
+
+func (m *stubManager) GetActivity(ctx context.Context, name string) (*container.Activity, error) {
+	return &container.Activity{LastActive: time.Now().Add(-90 * time.Minute)}, nil
+}
+
+func request(t *testing.T, h http.Handler, method, path, body, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServerRequiresToken(t *testing.T) {
+	srv := NewServer(newStubManager(), Options{Token: "secret", Prefix: "dev"})
+
+	assert.Equal(t, http.StatusUnauthorized, request(t, srv, "GET", "/v1/containers", "", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, request(t, srv, "GET", "/v1/containers", "", "wrong").Code)
+	assert.Equal(t, http.StatusOK, request(t, srv, "GET", "/v1/containers", "", "secret").Code)
+
+	// An unset token locks the API rather than opening it
+	open := NewServer(newStubManager(), Options{Prefix: "dev"})
+	assert.Equal(t, http.StatusUnauthorized, request(t, open, "GET", "/v1/containers", "", "").Code)
+}
+
+func TestServerContainerLifecycle(t *testing.T) {
+	mgr := newStubManager()
+	srv := NewServer(mgr, Options{Token: "t", Prefix: "dev", Connection: "team", SSHKey: "ssh-ed25519 AAAA"})
+
+	rec := request(t, srv, "GET", "/v1/containers/web", "", "t")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got Container
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "web", got.Name)
+	assert.Equal(t, "dev-web", got.Container)
+	assert.Equal(t, "alice", got.Owner)
+
+	assert.Equal(t, http.StatusNotFound, request(t, srv, "GET", "/v1/containers/missing", "", "t").Code)
+
+	rec = request(t, srv, "POST", "/v1/containers", `{"name": "api"}`, "t")
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, http.StatusConflict, request(t, srv, "POST", "/v1/containers", `{"name": "api"}`, "t").Code)
+	assert.Equal(t, http.StatusBadRequest, request(t, srv, "POST", "/v1/containers", `{}`, "t").Code)
+
+	rec = request(t, srv, "POST", "/v1/containers/dev-api/stop", "", "t")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "exited", got.Status)
+
+	rec = request(t, srv, "POST", "/v1/containers/web/exec", `{"command": ["echo", "hello"]}`, "t")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"output": "hello\n"}`, rec.Body.String())
+	assert.Equal(t, [][]string{{"echo", "hello"}}, mgr.execs)
+
+	rec = request(t, srv, "GET", "/v1/status", "", "t")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"connection": "team", "podman": "5.2.0", "containers": 2, "running": 1}`, rec.Body.String())
+
+	assert.Equal(t, http.StatusNoContent, request(t, srv, "DELETE", "/v1/containers/api", "", "t").Code)
+	assert.NotContains(t, mgr.containers, "dev-api")
+	assert.Equal(t, []string{"api"}, mgr.trashed)
+}
+
+func TestServerRemoveGuards(t *testing.T) {
+	mgr := newStubManager()
+	mgr.containers["dev-pet"] = &container.Container{Name: "dev-pet", Status: "running",
+		Labels: map[string]string{container.LabelProtected: "true"}}
+	var events []string
+	srv := NewServer(mgr, Options{
+		Token:       "t",
+		Prefix:      "dev",
+		Connection:  "team",
+		RemoveGuard: func(connection string) container.RemoveGuard {
+			return container.RemoveGuard{IsMine: func(c *container.Container) bool { return false }}
+		},
+		Notify:      func(event, connection, name string) { events = append(events, event+" "+connection+" "+name) },
+	})
+
+	// Protected and other users' containers need force=true
+	rec := request(t, srv, "DELETE", "/v1/containers/pet", "", "t")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "is protected")
+	rec = request(t, srv, "DELETE", "/v1/containers/web", "", "t")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "created by alice")
+	assert.Empty(t, events)
+
+	assert.Equal(t, http.StatusNoContent, request(t, srv, "DELETE", "/v1/containers/pet?force=true", "", "t").Code)
+	assert.Equal(t, []string{"pet"}, mgr.trashed)
+	assert.Equal(t, []string{"remove team pet"}, events)
+}
+
+func TestServerLimitsRequestBody(t *testing.T) {
+	srv := NewServer(newStubManager(), Options{Token: "t", Prefix: "dev", SSHKey: "ssh-ed25519 AAAA"})
+	body := `{"name": "big", "ssh_key": "` + strings.Repeat("A", maxRequestBody) + `"}`
+	assert.Equal(t, http.StatusBadRequest, request(t, srv, "POST", "/v1/containers", body, "t").Code)
+}
+
+func TestServerConnections(t *testing.T) {
+	team := newStubManager()
+	team.containers["dev-shared"] = &container.Container{Name: "dev-shared", Status: "exited"}
+	var opened []string
+	srv := NewServer(newStubManager(), Options{
+		Token:      "t",
+		Prefix:     "dev",
+		Connection: "home",
+		Connections: []Connection{
+			{Name: "home", Address: "10.0.0.1", Active: true},
+			{Name: "team", Address: "10.0.0.2"},
+		},
+		Connect: func(name string) (Manager, error) {
+			opened = append(opened, name)
+			return team, nil
+		},
+	})
+
+	rec := request(t, srv, "GET", "/v1/connections", "", "t")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"team"`)
+
+	rec = request(t, srv, "GET", "/v1/containers?connection=team", "", "t")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list []Container
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list, 2)
+	for _, c := range list {
+		assert.Equal(t, "team", c.Connection)
+		if c.Status == "running" {
+			require.NotNil(t, c.Idle)
+			assert.InDelta(t, 90*60, *c.Idle, 5)
+		} else {
+			assert.Nil(t, c.Idle, "stopped containers have no idle time")
+		}
+	}
+
+	request(t, srv, "POST", "/v1/containers/shared/start?connection=team", "", "t")
+	assert.Equal(t, "running", team.containers["dev-shared"].Status)
+	assert.Equal(t, []string{"team"}, opened, "managers are reused")
+
+	assert.Equal(t, http.StatusNotFound, request(t, srv, "GET", "/v1/containers?connection=nope", "", "t").Code)
+}
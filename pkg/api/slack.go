@@ -0,0 +1,307 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"l8s/pkg/container"
+	"l8s/pkg/logging"
+)
+
+// slackMaxSkew rejects signed requests older than Slack's replay window
+const slackMaxSkew = 5 * time.Minute
+
+// slackHelp is shown for /l8s help and unknown commands
+const slackHelp = "Usage:\n" +
+	"• `/l8s list [connection]` – containers with status, owner and idle time\n" +
+	"• `/l8s stop <name> [connection]` – stop a container\n" +
+	"• `/l8s start <name> [connection]` – start a container\n" +
+	"• `/l8s whoami` – the l8s owner your Slack account maps to"
+
+// slackVerbs holds the progress and done wording for each state change
+var slackVerbs = map[string][2]string{
+	"stop":  {"Stopping", "stopped"},
+	"start": {"Starting", "started"},
+}
+
+// SlackOptions configures the Slack slash command bridge
+type SlackOptions struct {
+	SigningSecret string            // From the Slack app's Basic Information page
+	Users         map[string]string // Slack user ID to l8s owner (the l8s.owner label)
+	Admins        []string          // Slack user IDs allowed to act on any container
+}
+
+// slackBridge maps Slack slash commands onto the server's managers
+type slackBridge struct {
+	server *Server
+	opts   SlackOptions
+	client *http.Client
+	now    func() time.Time
+}
+
+// slackMessage is a slash command response
+type slackMessage struct {
+	ResponseType string `json:"response_type"` // ephemeral or in_channel
+	Text         string `json:"text"`
+}
+
+// EnableSlack serves Slack slash commands at POST /slack/command. Requests
+// are authenticated with Slack's request signature rather than the API token.
+func (s *Server) EnableSlack(opts SlackOptions) {
+	b := &slackBridge{
+		server: s,
+		opts:   opts,
+		client: &http.Client{Timeout: 10 * time.Second},
+		now:    time.Now,
+	}
+	s.mux.HandleFunc("POST /slack/command", b.handleCommand)
+}
+
+func (b *slackBridge) handleCommand(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 64<<10))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := b.verify(r.Header, body); err != nil {
+		b.server.logger.Warn("rejected Slack request",
+			logging.WithError(err),
+			logging.WithField("remote", r.RemoteAddr))
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	userID := form.Get("user_id")
+	owner, admin := b.opts.Users[userID], b.isAdmin(userID)
+	if owner == "" && !admin {
+		writeSlack(w, fmt.Sprintf("Your Slack account (%s) is not mapped to an l8s owner. Ask an admin to add it under serve.slack.users.", userID))
+		return
+	}
+
+	args := strings.Fields(form.Get("text"))
+	if len(args) == 0 {
+		writeSlack(w, slackHelp)
+		return
+	}
+	b.server.logger.Info("Slack command",
+		logging.WithField("user", userID),
+		logging.WithField("owner", owner),
+		logging.WithField("text", form.Get("text")))
+
+	switch args[0] {
+	case "list", "ls":
+		writeSlack(w, b.list(r.Context(), optionalArg(args, 1)))
+	case "stop", "start":
+		if len(args) < 2 {
+			writeSlack(w, fmt.Sprintf("Usage: `/l8s %s <name> [connection]`", args[0]))
+			return
+		}
+		b.changeState(w, form.Get("response_url"), args[0], args[1], optionalArg(args, 2), owner, admin)
+	case "whoami":
+		switch {
+		case owner == "":
+			writeSlack(w, "You are an l8s admin and may manage any container")
+		case admin:
+			writeSlack(w, fmt.Sprintf("You act as l8s owner *%s* and may manage any container", owner))
+		default:
+			writeSlack(w, fmt.Sprintf("You act as l8s owner *%s*", owner))
+		}
+	default:
+		writeSlack(w, slackHelp)
+	}
+}
+
+// verify checks the X-Slack-Signature HMAC over the timestamp and body
+func (b *slackBridge) verify(header http.Header, body []byte) error {
+	if b.opts.SigningSecret == "" {
+		return errors.New("no Slack signing secret configured")
+	}
+	ts, err := strconv.ParseInt(header.Get("X-Slack-Request-Timestamp"), 10, 64)
+	if err != nil {
+		return errors.New("missing request timestamp")
+	}
+	if skew := b.now().Sub(time.Unix(ts, 0)); skew > slackMaxSkew || skew < -slackMaxSkew {
+		return errors.New("request timestamp too old")
+	}
+	mac := hmac.New(sha256.New, []byte(b.opts.SigningSecret))
+	fmt.Fprintf(mac, "v0:%d:%s", ts, body)
+	want := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(want), []byte(header.Get("X-Slack-Signature"))) {
+		return errors.New("invalid request signature")
+	}
+	return nil
+}
+
+func (b *slackBridge) isAdmin(userID string) bool {
+	for _, admin := range b.opts.Admins {
+		if admin == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// list renders the containers on a connection, running ones first
+func (b *slackBridge) list(ctx context.Context, connection string) string {
+	mgr, conn, err := b.server.managerFor(connection)
+	if err != nil {
+		return fmt.Sprintf("Cannot list containers on '%s': %v", connection, err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 2500*time.Millisecond)
+	defer cancel()
+	containers, err := mgr.ListContainers(ctx)
+	if err != nil {
+		return fmt.Sprintf("Cannot list containers on '%s': %v", conn, err)
+	}
+	if len(containers) == 0 {
+		return fmt.Sprintf("No containers on *%s*", conn)
+	}
+
+	result := make([]Container, len(containers))
+	for i, c := range containers {
+		result[i] = b.server.toAPI(c, conn)
+	}
+	b.server.addIdle(ctx, mgr, result)
+	sort.SliceStable(result, func(i, j int) bool {
+		return (result[i].Status == "running") && (result[j].Status != "running")
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Containers on *%s*:\n", conn)
+	for _, c := range result {
+		owner := c.Owner
+		if owner == "" {
+			owner = "unknown"
+		}
+		idle := ""
+		if c.Idle != nil {
+			idle = ", idle " + formatIdle(time.Duration(*c.Idle)*time.Second)
+		}
+		fmt.Fprintf(&sb, "• `%s` %s, owner %s%s\n", c.Name, c.Status, owner, idle)
+	}
+	return sb.String()
+}
+
+// changeState authorizes a start or stop, acknowledges it at once (Slack
+// expects a reply within three seconds) and posts the outcome to responseURL
+func (b *slackBridge) changeState(w http.ResponseWriter, responseURL, action, name, connection, owner string, admin bool) {
+	mgr, conn, err := b.server.managerFor(connection)
+	if err != nil {
+		writeSlack(w, fmt.Sprintf("Cannot reach '%s': %v", connection, err))
+		return
+	}
+	name = b.server.shortName(name)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	c, err := mgr.GetContainerInfo(ctx, name)
+	cancel()
+	if err != nil {
+		writeSlack(w, fmt.Sprintf("Container `%s` not found on *%s*", name, conn))
+		return
+	}
+	if !admin && c.Labels[container.LabelOwner] != owner {
+		writeSlack(w, fmt.Sprintf("`%s` belongs to %s; only its owner or an l8s admin can %s it",
+			name, ownerOrUnknown(c.Labels[container.LabelOwner]), action))
+		return
+	}
+
+	writeSlack(w, fmt.Sprintf("%s `%s` on *%s*…", slackVerbs[action][0], name, conn))
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		change := mgr.StartContainer
+		if action == "stop" {
+			change = mgr.StopContainer
+		}
+		b.server.mu.Lock()
+		err := change(ctx, name)
+		b.server.mu.Unlock()
+
+		text := fmt.Sprintf("`%s` on *%s* %s", name, conn, slackVerbs[action][1])
+		if err != nil {
+			text = fmt.Sprintf("Failed to %s `%s` on *%s*: %v", action, name, conn, err)
+			b.server.logger.Warn("Slack command failed",
+				logging.WithField("action", action),
+				logging.WithField("container", name),
+				logging.WithError(err))
+		}
+		if err := b.respond(ctx, responseURL, text); err != nil {
+			b.server.logger.Warn("failed to post Slack response", logging.WithError(err))
+		}
+	}()
+}
+
+// respond posts a follow-up message to a slash command's response URL
+func (b *slackBridge) respond(ctx context.Context, responseURL, text string) error {
+	if responseURL == "" {
+		return nil
+	}
+	data, err := json.Marshal(slackMessage{ResponseType: "ephemeral", Text: text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack returned %s", resp.Status)
+	}
+	return nil
+}
+
+func writeSlack(w http.ResponseWriter, text string) {
+	writeJSON(w, http.StatusOK, slackMessage{ResponseType: "ephemeral", Text: text})
+}
+
+// formatIdle matches the IDLE column of 'l8s list'
+func formatIdle(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "active"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func optionalArg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+func ownerOrUnknown(owner string) string {
+	if owner == "" {
+		return "an unknown owner"
+	}
+	return owner
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"l8s/pkg/container"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSigningSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+// slackRequest sends a slash command signed the way Slack signs them
+func slackRequest(t *testing.T, h http.Handler, form url.Values, secret string) slackMessage {
+	t.Helper()
+	body := form.Encode()
+	ts := time.Now().Unix()
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%d:%s", ts, body)
+
+	req := httptest.NewRequest(http.MethodPost, "/slack/command", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", fmt.Sprint(ts))
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var msg slackMessage
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &msg))
+	} else {
+		msg.Text = fmt.Sprintf("HTTP %d", rec.Code)
+	}
+	return msg
+}
+
+func newSlackServer(mgr Manager) *Server {
+	srv := NewServer(mgr, Options{Token: "t", Prefix: "dev", Connection: "home"})
+	srv.EnableSlack(SlackOptions{
+		SigningSecret: testSigningSecret,
+		Users:         map[string]string{"UALICE": "alice", "UBOB": "bob"},
+		Admins:        []string{"UADMIN"},
+	})
+	return srv
+}
+
+func TestSlackRejectsUnsignedRequests(t *testing.T) {
+	srv := newSlackServer(newStubManager())
+	form := url.Values{"user_id": {"UALICE"}, "text": {"list"}}
+
+	assert.Equal(t, "HTTP 401", slackRequest(t, srv, form, "wrong secret").Text)
+	assert.Contains(t, slackRequest(t, srv, form, testSigningSecret).Text, "Containers on *home*")
+}
+
+func TestSlackCommands(t *testing.T) {
+	mgr := newStubManager()
+	mgr.containers["dev-api"] = &container.Container{Name: "dev-api", Status: "exited",
+		Labels: map[string]string{container.LabelOwner: "bob"}}
+	srv := newSlackServer(mgr)
+	send := func(user, text string) string {
+		return slackRequest(t, srv, url.Values{"user_id": {user}, "text": {text}}, testSigningSecret).Text
+	}
+
+	assert.Contains(t, send("USTRANGER", "list"), "not mapped to an l8s owner")
+	assert.Contains(t, send("UALICE", ""), "Usage:")
+
+	list := send("UBOB", "list")
+	assert.Contains(t, list, "• `web` running, owner alice, idle 1h")
+	assert.Contains(t, list, "• `api` exited, owner bob")
+	assert.Less(t, strings.Index(list, "`web`"), strings.Index(list, "`api`"), "running containers first")
+
+	assert.Contains(t, send("UBOB", "stop web"), "belongs to alice")
+	assert.Equal(t, "running", mgr.containers["dev-web"].Status)
+	assert.Contains(t, send("UALICE", "stop missing"), "not found")
+	assert.Contains(t, send("UALICE", "list nowhere"), "connection not found")
+	assert.Contains(t, send("UADMIN", "whoami"), "l8s admin")
+}
+
+func TestSlackStopPostsResult(t *testing.T) {
+	responses := make(chan string, 1)
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		responses <- string(body)
+	}))
+	defer slack.Close()
+
+	mgr := newStubManager()
+	srv := newSlackServer(mgr)
+	msg := slackRequest(t, srv, url.Values{
+		"user_id":      {"UALICE"},
+		"text":         {"stop web"},
+		"response_url": {slack.URL},
+	}, testSigningSecret)
+	assert.Equal(t, "Stopping `web` on *home*…", msg.Text)
+
+	select {
+	case body := <-responses:
+		assert.Contains(t, body, "`web` on *home* stopped")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no follow-up posted to the response URL")
+	}
+	assert.Equal(t, "exited", mgr.containers["dev-web"].Status)
+}
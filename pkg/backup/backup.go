@@ -0,0 +1,140 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimestampFormat is the layout used to embed snapshot times in file names
+const TimestampFormat = "20060102T150405Z"
+
+// Entry describes a single volume snapshot held in a store
+type Entry struct {
+	Container string
+	Volume    string
+	Timestamp time.Time
+	Size      int64
+	Path      string // Store-relative path, e.g. dev-myproject/20250101T120000Z_home.tar
+}
+
+// Store is a destination that volume snapshots can be written to
+type Store interface {
+	// Put writes a snapshot read from r to the store-relative path
+	Put(ctx context.Context, path string, r io.Reader) error
+	// List returns all snapshots held in the store
+	List(ctx context.Context) ([]Entry, error)
+	// Delete removes a snapshot from the store
+	Delete(ctx context.Context, entry Entry) error
+	// String returns a human readable description of the destination
+	String() string
+}
+
+// NewStore returns a Store for the given destination. Supported forms are
+// a local path, s3://bucket/prefix, and ssh://[user@]host/path.
+func NewStore(destination string) (Store, error) {
+	switch {
+	case destination == "":
+		return nil, fmt.Errorf("no backup destination configured\nSet backup.destination in your config or pass --destination")
+	case strings.HasPrefix(destination, "s3://"):
+		return newS3Store(destination)
+	case strings.HasPrefix(destination, "ssh://"):
+		return newSSHStore(destination)
+	case strings.Contains(destination, "://"):
+		return nil, fmt.Errorf("unsupported backup destination '%s' (use a local path, s3:// or ssh://)", destination)
+	default:
+		return newLocalStore(destination), nil
+	}
+}
+
+// FileName returns the store-relative path for a snapshot of volume
+func FileName(container, volume string, ts time.Time) string {
+	return path.Join(container, fmt.Sprintf("%s_%s.tar", ts.UTC().Format(TimestampFormat), volume))
+}
+
+// ParseFileName reverses FileName, returning false for unrelated files
+func ParseFileName(p string) (Entry, bool) {
+	container, file := path.Split(p)
+	container = strings.TrimSuffix(container, "/")
+	if container == "" || !strings.HasSuffix(file, ".tar") {
+		return Entry{}, false
+	}
+
+	stamp, volume, ok := strings.Cut(strings.TrimSuffix(file, ".tar"), "_")
+	if !ok || volume == "" {
+		return Entry{}, false
+	}
+
+	ts, err := time.Parse(TimestampFormat, stamp)
+	if err != nil {
+		return Entry{}, false
+	}
+
+	return Entry{
+		Container: path.Base(container),
+		Volume:    volume,
+		Timestamp: ts,
+		Path:      p,
+	}, true
+}
+
+// SortEntries orders entries by container, newest snapshot first
+func SortEntries(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Container != entries[j].Container {
+			return entries[i].Container < entries[j].Container
+		}
+		if !entries[i].Timestamp.Equal(entries[j].Timestamp) {
+			return entries[i].Timestamp.After(entries[j].Timestamp)
+		}
+		return entries[i].Volume < entries[j].Volume
+	})
+}
+
+// SelectForPruning returns the entries that fall outside the retention window.
+// Retention counts snapshots (all volumes taken at the same time) per container,
+// keeping the newest keep snapshots. A keep of zero or less disables pruning.
+func SelectForPruning(entries []Entry, keep int) []Entry {
+	if keep <= 0 {
+		return nil
+	}
+
+	// Collect distinct snapshot times per container
+	snapshots := make(map[string][]time.Time)
+	for _, e := range entries {
+		times := snapshots[e.Container]
+		found := false
+		for _, t := range times {
+			if t.Equal(e.Timestamp) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			snapshots[e.Container] = append(times, e.Timestamp)
+		}
+	}
+
+	// Determine the oldest snapshot time to keep for each container
+	cutoff := make(map[string]time.Time)
+	for container, times := range snapshots {
+		if len(times) <= keep {
+			continue
+		}
+		sort.Slice(times, func(i, j int) bool { return times[i].After(times[j]) })
+		cutoff[container] = times[keep-1]
+	}
+
+	var prune []Entry
+	for _, e := range entries {
+		if c, ok := cutoff[e.Container]; ok && e.Timestamp.Before(c) {
+			prune = append(prune, e)
+		}
+	}
+	SortEntries(prune)
+	return prune
+}
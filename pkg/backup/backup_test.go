@@ -0,0 +1,119 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileNameRoundTrip(t *testing.T) {
+	ts := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	name := FileName("dev-myproject", "home", ts)
+	assert.Equal(t, "dev-myproject/20250304T050607Z_home.tar", name)
+
+	entry, ok := ParseFileName(name)
+	require.True(t, ok)
+	assert.Equal(t, "dev-myproject", entry.Container)
+	assert.Equal(t, "home", entry.Volume)
+	assert.True(t, ts.Equal(entry.Timestamp))
+
+	_, ok = ParseFileName("dev-myproject/notes.txt")
+	assert.False(t, ok)
+	_, ok = ParseFileName("20250304T050607Z_home.tar")
+	assert.False(t, ok)
+}
+
+func TestNewStore(t *testing.T) {
+	tests := []struct {
+		destination string
+		wantErr     bool
+	}{
+		{destination: "/var/backups/l8s"},
+		{destination: "~/l8s-backups"},
+		{destination: "ssh://backup@nas.local/srv/l8s"},
+		{destination: "ssh://nas.local", wantErr: true},
+		{destination: "ftp://example.com/backups", wantErr: true},
+		{destination: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.destination, func(t *testing.T) {
+			_, err := NewStore(tt.destination)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSelectForPruning(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	var entries []Entry
+	for d := 1; d <= 4; d++ {
+		for _, vol := range []string{"home", "workspace"} {
+			entries = append(entries, Entry{Container: "dev-a", Volume: vol, Timestamp: day(d)})
+		}
+	}
+	entries = append(entries, Entry{Container: "dev-b", Volume: "home", Timestamp: day(1)})
+
+	prune := SelectForPruning(entries, 2)
+	require.Len(t, prune, 4)
+	for _, e := range prune {
+		assert.Equal(t, "dev-a", e.Container)
+		assert.True(t, e.Timestamp.Before(day(3)))
+	}
+
+	assert.Empty(t, SelectForPruning(entries, 0))
+	assert.Empty(t, SelectForPruning(entries, 10))
+}
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store := newLocalStore(t.TempDir())
+	ts := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.Put(ctx, FileName("dev-a", "home", ts), strings.NewReader("data")))
+	require.NoError(t, os.WriteFile(filepath.Join(store.root, "README"), []byte("x"), 0644))
+
+	entries, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(4), entries[0].Size)
+
+	require.NoError(t, store.Delete(ctx, entries[0]))
+	entries, err = store.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// A missing root is simply empty
+	missing := newLocalStore(filepath.Join(t.TempDir(), "missing"))
+	entries, err = missing.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestInstallSchedule(t *testing.T) {
+	crontab := "0 1 * * * other-job\n0 2 * * * l8s backup run " + cronMarker + "\n"
+	orig := crontabFunc
+	defer func() { crontabFunc = orig }()
+	crontabFunc = func(input *string) (string, error) {
+		if input != nil {
+			crontab = *input
+		}
+		return crontab, nil
+	}
+
+	require.NoError(t, InstallSchedule("30 3 * * *", "/usr/local/bin/l8s backup run"))
+	assert.Equal(t, "0 1 * * * other-job\n30 3 * * * /usr/local/bin/l8s backup run "+cronMarker+"\n", crontab)
+
+	require.NoError(t, RemoveSchedule())
+	assert.Equal(t, "0 1 * * * other-job\n", crontab)
+}
@@ -0,0 +1,85 @@
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// cronMarker tags the crontab line managed by l8s
+const cronMarker = "# l8s-backup"
+
+// crontabFunc reads and writes the user's crontab; replaced in tests
+var crontabFunc = func(input *string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return "", fmt.Errorf("cron is not available on Windows; schedule 'l8s backup run' with Task Scheduler instead")
+	}
+	if input == nil {
+		output, err := exec.Command("crontab", "-l").Output()
+		if err != nil {
+			// crontab -l exits non-zero when no crontab exists yet
+			if _, ok := err.(*exec.ExitError); ok {
+				return "", nil
+			}
+			return "", err
+		}
+		return string(output), nil
+	}
+	cmd := exec.Command("crontab", "-")
+	cmd.Stdin = strings.NewReader(*input)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return "", nil
+}
+
+// InstallSchedule adds or replaces the l8s backup entry in the user's crontab
+func InstallSchedule(schedule, command string) error {
+	current, err := crontabFunc(nil)
+	if err != nil {
+		return fmt.Errorf("failed to read crontab: %w", err)
+	}
+
+	updated := stripScheduleLine(current)
+	updated += fmt.Sprintf("%s %s %s\n", schedule, command, cronMarker)
+
+	if _, err := crontabFunc(&updated); err != nil {
+		return fmt.Errorf("failed to write crontab: %w", err)
+	}
+	return nil
+}
+
+// RemoveSchedule deletes the l8s backup entry from the user's crontab
+func RemoveSchedule() error {
+	current, err := crontabFunc(nil)
+	if err != nil {
+		return fmt.Errorf("failed to read crontab: %w", err)
+	}
+
+	updated := stripScheduleLine(current)
+	if updated == current {
+		return nil
+	}
+
+	if _, err := crontabFunc(&updated); err != nil {
+		return fmt.Errorf("failed to write crontab: %w", err)
+	}
+	return nil
+}
+
+// stripScheduleLine removes any l8s-managed line from crontab content
+func stripScheduleLine(content string) string {
+	var buf bytes.Buffer
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if line == "" || strings.HasSuffix(strings.TrimRight(line, "\n"), cronMarker) {
+			continue
+		}
+		buf.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			buf.WriteString("\n")
+		}
+	}
+	return buf.String()
+}
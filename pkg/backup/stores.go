@@ -0,0 +1,248 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// localStore keeps snapshots in a directory on this machine
+type localStore struct {
+	root string
+}
+
+func newLocalStore(root string) *localStore {
+	if strings.HasPrefix(root, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			root = filepath.Join(home, root[2:])
+		}
+	}
+	return &localStore{root: root}
+}
+
+func (s *localStore) Put(ctx context.Context, p string, r io.Reader) error {
+	dest := filepath.Join(s.root, filepath.FromSlash(p))
+	if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	// Write to a temp file first so a failed export never looks like a snapshot
+	tmp := dest + ".partial"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	return os.Rename(tmp, dest)
+}
+
+func (s *localStore) List(ctx context.Context) ([]Entry, error) {
+	var entries []Entry
+	err := filepath.WalkDir(s.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == s.root {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return nil
+		}
+		entry, ok := ParseFileName(filepath.ToSlash(rel))
+		if !ok {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			entry.Size = info.Size()
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups in %s: %w", s.root, err)
+	}
+	SortEntries(entries)
+	return entries, nil
+}
+
+func (s *localStore) Delete(ctx context.Context, entry Entry) error {
+	return os.Remove(filepath.Join(s.root, filepath.FromSlash(entry.Path)))
+}
+
+func (s *localStore) String() string {
+	return s.root
+}
+
+// s3Store uploads snapshots with the aws CLI so existing credentials are reused
+type s3Store struct {
+	bucket string
+	prefix string
+}
+
+func newS3Store(destination string) (*s3Store, error) {
+	u, err := url.Parse(destination)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 destination '%s' (expected s3://bucket/prefix)", destination)
+	}
+	if _, err := exec.LookPath("aws"); err != nil {
+		return nil, fmt.Errorf("S3 backups require the aws CLI in PATH")
+	}
+	return &s3Store{bucket: u.Host, prefix: strings.Trim(u.Path, "/")}, nil
+}
+
+func (s *s3Store) uri(p string) string {
+	return "s3://" + path.Join(s.bucket, s.prefix, p)
+}
+
+func (s *s3Store) Put(ctx context.Context, p string, r io.Reader) error {
+	cmd := exec.CommandContext(ctx, "aws", "s3", "cp", "-", s.uri(p))
+	cmd.Stdin = r
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to upload %s: %w\nOutput: %s", s.uri(p), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (s *s3Store) List(ctx context.Context) ([]Entry, error) {
+	output, err := exec.CommandContext(ctx, "aws", "s3", "ls", "--recursive", s.uri("")+"/").Output()
+	if err != nil {
+		// aws exits non-zero for an empty prefix
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) == 0 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list %s: %w", s.uri(""), err)
+	}
+
+	// Lines look like: 2025-01-01 12:00:00   12345 prefix/dev-foo/20250101T120000Z_home.tar
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		key := strings.TrimPrefix(fields[3], s.prefix+"/")
+		entry, ok := ParseFileName(key)
+		if !ok {
+			continue
+		}
+		entry.Size, _ = strconv.ParseInt(fields[2], 10, 64)
+		entries = append(entries, entry)
+	}
+	SortEntries(entries)
+	return entries, nil
+}
+
+func (s *s3Store) Delete(ctx context.Context, entry Entry) error {
+	if output, err := exec.CommandContext(ctx, "aws", "s3", "rm", s.uri(entry.Path)).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to delete %s: %w\nOutput: %s", s.uri(entry.Path), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (s *s3Store) String() string {
+	return s.uri("")
+}
+
+// sshStore streams snapshots to a directory on another host over ssh
+type sshStore struct {
+	target string // [user@]host
+	root   string
+}
+
+func newSSHStore(destination string) (*sshStore, error) {
+	u, err := url.Parse(destination)
+	if err != nil || u.Host == "" || u.Path == "" {
+		return nil, fmt.Errorf("invalid SSH destination '%s' (expected ssh://[user@]host/path)", destination)
+	}
+	target := u.Host
+	if u.User != nil {
+		target = u.User.Username() + "@" + u.Host
+	}
+	return &sshStore{target: target, root: u.Path}, nil
+}
+
+func (s *sshStore) run(ctx context.Context, stdin io.Reader, script string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "ssh", s.target, script)
+	cmd.Stdin = stdin
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+func (s *sshStore) Put(ctx context.Context, p string, r io.Reader) error {
+	dest := path.Join(s.root, p)
+	script := fmt.Sprintf("mkdir -p %s && cat > %s.partial && mv %s.partial %s",
+		shellQuote(path.Dir(dest)), shellQuote(dest), shellQuote(dest), shellQuote(dest))
+	if _, err := s.run(ctx, r, script); err != nil {
+		return fmt.Errorf("failed to upload to %s:%s: %w", s.target, dest, err)
+	}
+	return nil
+}
+
+func (s *sshStore) List(ctx context.Context) ([]Entry, error) {
+	script := fmt.Sprintf("cd %s 2>/dev/null && find . -type f -name '*.tar' -printf '%%s %%P\\n'", shellQuote(s.root))
+	output, err := s.run(ctx, nil, script+" || true")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups on %s: %w", s.target, err)
+	}
+
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		size, rel, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		entry, ok := ParseFileName(rel)
+		if !ok {
+			continue
+		}
+		entry.Size, _ = strconv.ParseInt(size, 10, 64)
+		entries = append(entries, entry)
+	}
+	SortEntries(entries)
+	return entries, nil
+}
+
+func (s *sshStore) Delete(ctx context.Context, entry Entry) error {
+	if _, err := s.run(ctx, nil, "rm -f "+shellQuote(path.Join(s.root, entry.Path))); err != nil {
+		return fmt.Errorf("failed to delete %s on %s: %w", entry.Path, s.target, err)
+	}
+	return nil
+}
+
+func (s *sshStore) String() string {
+	return fmt.Sprintf("ssh://%s%s", s.target, s.root)
+}
+
+// shellQuote quotes s for use in a remote POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
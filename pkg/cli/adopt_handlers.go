@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"l8s/pkg/color"
+	"l8s/pkg/container"
+	"l8s/pkg/git"
+	"l8s/pkg/notify"
+
+	"github.com/spf13/cobra"
+)
+
+// adoptName picks the short name an adopted container gets: --name, else the
+// current worktree's container, else the podman name without the prefix
+func (f *CommandFactory) adoptName(cmd *cobra.Command, source string) (string, error) {
+	prefix := f.Config.ContainerPrefix + "-"
+	if name, _ := cmd.Flags().GetString("name"); name != "" {
+		return strings.TrimPrefix(name, prefix), nil
+	}
+	if f.GitClient.IsGitRepository(".") {
+		return f.worktreeContainer("adopt")
+	}
+	return strings.TrimPrefix(source, prefix), nil
+}
+
+// runAdopt handles the adopt command
+func (f *CommandFactory) runAdopt(cmd *cobra.Command, args []string) error {
+	source := args[0]
+	name, err := f.adoptName(cmd, source)
+	if err != nil {
+		return err
+	}
+	fullName := fmt.Sprintf("%s-%s", f.Config.ContainerPrefix, name)
+	backup := container.AdoptBackupName(source)
+
+	sshKey, err := f.localPublicKey()
+	if err != nil {
+		return err
+	}
+	if err := f.SSHClient.ValidatePublicKey(sshKey); err != nil {
+		return fmt.Errorf("invalid SSH public key: %w", err)
+	}
+	sshPort, _ := cmd.Flags().GetInt("ssh-port")
+
+	if force, _ := cmd.Flags().GetBool("force"); !force {
+		fmt.Printf("Adopt %s as %s?\n", source, fullName)
+		fmt.Printf("  %s is stopped and kept as %s\n", source, backup)
+		fmt.Printf("  /home/%s/.ssh/authorized_keys is replaced with your key\n", f.Config.ContainerUser)
+		fmt.Print("Continue? (y/N): ")
+		response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			return err
+		}
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	ctx := context.Background()
+	color.Printf("🎳 {cyan}Adopting container:{reset} {bold}%s{reset} → {bold}%s{reset}\n", source, fullName)
+
+	cont, err := f.ContainerMgr.AdoptContainer(ctx, source, name, container.AdoptOptions{
+		SSHPort: sshPort,
+		SSHKey:  sshKey,
+	})
+	if err != nil {
+		return withQuotaHint(fmt.Errorf("failed to adopt container: %w", err), f.Config.ActiveConnection)
+	}
+
+	color.Printf("{green}✓{reset} SSH port: {bold}%d{reset}\n", cont.SSHPort)
+	if cont.WebPort > 0 {
+		color.Printf("{green}✓{reset} Web port: {bold}%d{reset}\n", cont.WebPort)
+	}
+	color.Printf("{green}✓{reset} Original kept as {bold}%s{reset}; remove it with 'podman rm %s' once you are happy\n", backup, backup)
+	f.notifyEvent(notify.EventCreate, name)
+	f.syncIngress()
+
+	// Map the current repository to the container
+	if repoRoot, err := f.GitClient.GetRepositoryRoot("."); err == nil {
+		remoteURL := fmt.Sprintf("%s:/workspace/project", fullName)
+		if err := f.GitClient.AddRemote(repoRoot, name, remoteURL); err != nil {
+			color.Printf("{yellow}!{reset} Could not add git remote '%s': %v\n", name, err)
+		} else {
+			f.recordRepo(name, repoRoot)
+			color.Printf("{green}✓{reset} Git remote '{bold}%s{reset}' added\n", name)
+		}
+		if current, err := f.worktreeContainer("adopt"); err == nil && current != name {
+			if err := git.SetWorktreeContainer(repoRoot, name); err != nil {
+				color.Printf("{yellow}!{reset} Could not link this worktree to %s: %v\n", fullName, err)
+			} else {
+				color.Printf("{green}✓{reset} Worktree linked to {bold}%s{reset}\n", fullName)
+			}
+		}
+	}
+
+	color.Printf("\n{cyan}Connect with:{reset}\n")
+	color.Printf("- {bold}ssh %s{reset}\n", fullName)
+
+	return nil
+}
+
+// runDisown handles the disown command
+func (f *CommandFactory) runDisown(cmd *cobra.Command, args []string) error {
+	name := strings.TrimPrefix(args[0], f.Config.ContainerPrefix+"-")
+	fullName := fmt.Sprintf("%s-%s", f.Config.ContainerPrefix, name)
+
+	ctx := context.Background()
+	force, _ := cmd.Flags().GetBool("force")
+	if err := f.checkOwner(ctx, name, force); err != nil {
+		return err
+	}
+
+	if !force {
+		fmt.Printf("Release %s from l8s? The container and its volumes are kept for use with podman,\n", fullName)
+		fmt.Print("but l8s stops listing, rebuilding and backing it up. Continue? (y/N): ")
+		response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			return err
+		}
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	if err := f.ContainerMgr.DisownContainer(ctx, name); err != nil {
+		return fmt.Errorf("failed to disown container: %w", err)
+	}
+	color.Printf("{green}✓{reset} l8s labels removed; the container keeps its name, ports and volumes\n")
+	color.Printf("{green}✓{reset} SSH config entry removed\n")
+
+	if repoRoot, err := f.GitClient.GetRepositoryRoot("."); err == nil {
+		if err := f.GitClient.RemoveRemote(repoRoot, name); err == nil {
+			color.Printf("{green}✓{reset} Git remote '{bold}%s{reset}' removed\n", name)
+		}
+	}
+	unpinWorktree(name)
+	f.syncIngress()
+
+	color.Printf("\nManage it with {bold}podman container inspect %s{reset}, or take it back with {bold}l8s adopt %s{reset}\n", fullName, fullName)
+	return nil
+}
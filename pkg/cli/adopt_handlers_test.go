@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"testing"
+
+	"l8s/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdoptName(t *testing.T) {
+	gitClient := new(MockGitClientEnhanced)
+	gitClient.On("IsGitRepository", ".").Return(false)
+	f := &CommandFactory{Config: &config.Config{ContainerPrefix: "dev"}, GitClient: gitClient}
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("name", "", "")
+		assert.NoError(t, cmd.Flags().Parse(args))
+		return cmd
+	}
+
+	name, err := f.adoptName(newCmd(), "my-devbox")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-devbox", name)
+
+	name, err = f.adoptName(newCmd(), "dev-api")
+	assert.NoError(t, err)
+	assert.Equal(t, "api", name)
+
+	name, err = f.adoptName(newCmd("--name", "dev-api"), "my-devbox")
+	assert.NoError(t, err)
+	assert.Equal(t, "api", name)
+}
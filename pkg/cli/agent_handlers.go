@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"l8s/pkg/color"
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+
+	"github.com/spf13/cobra"
+)
+
+// runAgentInit sets up an agent's workspace in the current worktree's container
+func (f *CommandFactory) runAgentInit(cmd *cobra.Command, args []string) error {
+	agent := args[0]
+	if err := container.ValidateAgentName(agent); err != nil {
+		return err
+	}
+	name, err := f.worktreeContainer("agent init")
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	initCmd := []string{"su", "-", f.Config.ContainerUser, "-c", container.AgentInitScript(agent)}
+	if err := f.ContainerMgr.ExecContainer(ctx, name, initCmd); err != nil {
+		return fmt.Errorf("failed to create agent workspace: %w", err)
+	}
+	dir := container.AgentDir(agent)
+	color.Printf("{green}✓{reset} Workspace {bold}%s{reset}\n", dir)
+	for _, sub := range container.AgentSubdirs {
+		color.Printf("  {dim}%s/{reset}\n", path.Join(dir, sub))
+	}
+	color.Printf("{green}✓{reset} %s links to the clipboard directory\n", container.AgentClipboardLink(agent))
+
+	installed, err := f.ContainerMgr.InstallAgentSettings(ctx, name, agent)
+	switch {
+	case err != nil:
+		color.Printf("{yellow}!{reset} Could not install settings: %v\n", err)
+	case installed:
+		color.Printf("{green}✓{reset} Installed settings into {bold}~/.%s{reset} from your dotfiles\n", agent)
+	default:
+		color.Printf("{dim}No .%s directory in your dotfiles; no settings installed{reset}\n", agent)
+	}
+
+	color.Printf("\nFiles the agent writes to {bold}%s/artifacts{reset} can be fetched with\n", dir)
+	color.Printf("'{bold}l8s agent artifacts pull %s{reset}'\n", agent)
+	return nil
+}
+
+// runAgentArtifactsPull copies an agent's artifacts from the container to the host
+func (f *CommandFactory) runAgentArtifactsPull(cmd *cobra.Command, args []string) error {
+	agent := args[0]
+	if err := container.ValidateAgentName(agent); err != nil {
+		return err
+	}
+	if f.Config.Runtime == config.RuntimeFake {
+		return fmt.Errorf("agent artifacts pull needs SSH into the container, which the fake runtime does not provide")
+	}
+	name, err := f.worktreeContainer("agent artifacts pull")
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	artifactsDir := path.Join(container.AgentDir(agent), "artifacts")
+	if err := f.ContainerMgr.ExecContainer(ctx, name, []string{"test", "-d", artifactsDir}); err != nil {
+		return fmt.Errorf("agent '%s' has no workspace in this container; run 'l8s agent init %s' first", agent, agent)
+	}
+
+	outputDir, _ := cmd.Flags().GetString("output")
+	if outputDir == "" {
+		outputDir = filepath.Join("l8s-artifacts", agent)
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	// Hidden files too; patterns that match nothing are skipped
+	return f.collectArtifacts(ctx, name, artifactsDir, []string{"*", ".[!.]*"}, outputDir)
+}
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/juju/ansiterm"
+	"github.com/spf13/cobra"
+	"l8s/pkg/backup"
+	"l8s/pkg/color"
+)
+
+// backupStore resolves the destination from the --destination flag or config
+func (f *CommandFactory) backupStore(cmd *cobra.Command) (backup.Store, error) {
+	destination, _ := cmd.Flags().GetString("destination")
+	if destination == "" {
+		destination = f.Config.Backup.Destination
+	}
+	return backup.NewStore(destination)
+}
+
+// runBackupRun snapshots the volumes of the named containers (or all of them)
+func (f *CommandFactory) runBackupRun(cmd *cobra.Command, args []string) error {
+	store, err := f.backupStore(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	names := make([]string, 0, len(args))
+	for _, arg := range args {
+		names = append(names, strings.TrimPrefix(arg, f.Config.ContainerPrefix+"-"))
+	}
+	if len(names) == 0 {
+		containers, err := f.ContainerMgr.ListContainers(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list containers: %w", err)
+		}
+		for _, c := range containers {
+			names = append(names, strings.TrimPrefix(c.Name, f.Config.ContainerPrefix+"-"))
+		}
+	}
+	if len(names) == 0 {
+		fmt.Println("No l8s containers found")
+		return nil
+	}
+
+	// One timestamp per run so all volumes of a container form a single snapshot
+	ts := time.Now().UTC().Truncate(time.Second)
+	color.Printf("{cyan}→{reset} Backing up %d container(s) to {bold}%s{reset}\n", len(names), store)
+
+	var failed []string
+	for _, name := range names {
+		entries, err := f.ContainerMgr.BackupContainer(ctx, name, store, ts)
+		if err != nil {
+			color.Printf("{red}✗{reset} %s-%s: %v\n", f.Config.ContainerPrefix, name, err)
+			failed = append(failed, name)
+			continue
+		}
+		color.Printf("{green}✓{reset} %s-%s (%d volumes)\n", f.Config.ContainerPrefix, name, len(entries))
+	}
+
+	if keep := f.Config.Backup.Retention; keep > 0 {
+		if err := f.pruneBackups(ctx, store, keep); err != nil {
+			color.Printf("{yellow}!{reset} Retention pruning failed: %v\n", err)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("backup failed for %d container(s): %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// runBackupList shows the snapshots held at the backup destination
+func (f *CommandFactory) runBackupList(cmd *cobra.Command, args []string) error {
+	store, err := f.backupStore(cmd)
+	if err != nil {
+		return err
+	}
+
+	entries, err := store.List(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		want := f.Config.ContainerPrefix + "-" + strings.TrimPrefix(args[0], f.Config.ContainerPrefix+"-")
+		filtered := entries[:0]
+		for _, e := range entries {
+			if e.Container == want {
+				filtered = append(filtered, e)
+			}
+		}
+		entries = filtered
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No backups found in %s\n", store)
+		return nil
+	}
+
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	if os.Getenv("NO_COLOR") == "" {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			color.Bold("CONTAINER"),
+			color.Bold("VOLUME"),
+			color.Bold("TAKEN"),
+			color.Bold("SIZE"))
+	} else {
+		fmt.Fprintln(w, "CONTAINER\tVOLUME\tTAKEN\tSIZE")
+	}
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			e.Container,
+			e.Volume,
+			e.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			formatBytes(e.Size))
+	}
+	return w.Flush()
+}
+
+// runBackupPrune deletes snapshots outside the retention window
+func (f *CommandFactory) runBackupPrune(cmd *cobra.Command, args []string) error {
+	store, err := f.backupStore(cmd)
+	if err != nil {
+		return err
+	}
+
+	keep, _ := cmd.Flags().GetInt("keep")
+	if keep == 0 {
+		keep = f.Config.Backup.Retention
+	}
+	if keep <= 0 {
+		return fmt.Errorf("no retention configured\nSet backup.retention in your config or pass --keep")
+	}
+
+	return f.pruneBackups(context.Background(), store, keep)
+}
+
+// pruneBackups removes all but the newest keep snapshots of each container
+func (f *CommandFactory) pruneBackups(ctx context.Context, store backup.Store, keep int) error {
+	entries, err := store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	prune := backup.SelectForPruning(entries, keep)
+	if len(prune) == 0 {
+		color.Printf("{green}✓{reset} Nothing to prune (keeping %d per container)\n", keep)
+		return nil
+	}
+
+	for _, e := range prune {
+		if err := store.Delete(ctx, e); err != nil {
+			return err
+		}
+	}
+	color.Printf("{green}✓{reset} Pruned %d old backup file(s) (keeping %d per container)\n", len(prune), keep)
+	return nil
+}
+
+// runBackupSchedule installs or removes the crontab entry for scheduled backups
+func (f *CommandFactory) runBackupSchedule(cmd *cobra.Command, args []string) error {
+	if remove, _ := cmd.Flags().GetBool("remove"); remove {
+		if err := backup.RemoveSchedule(); err != nil {
+			return err
+		}
+		color.Printf("{green}✓{reset} Backup schedule removed\n")
+		return nil
+	}
+
+	schedule := f.Config.Backup.Schedule
+	if schedule == "" {
+		return fmt.Errorf("no backup schedule configured\nSet backup.schedule in your config, e.g.:\n\nbackup:\n  schedule: \"0 3 * * *\"\n  destination: ~/l8s-backups\n  retention: 7")
+	}
+	if _, err := backup.NewStore(f.Config.Backup.Destination); err != nil {
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate l8s binary: %w", err)
+	}
+
+	if err := backup.InstallSchedule(schedule, executable+" backup run"); err != nil {
+		return err
+	}
+	color.Printf("{green}✓{reset} Scheduled backups ({bold}%s{reset}) to %s\n", schedule, f.Config.Backup.Destination)
+	return nil
+}
+
+// formatBytes renders a byte count in human readable units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"l8s/pkg/container"
+)
+
+// defaultParallel is how many containers bulk operations work on at once
+const defaultParallel = 4
+
+// bulkResult is the outcome of a bulk operation on one container
+type bulkResult struct {
+	name   string
+	output string
+	err    error
+}
+
+// selectContainers returns the containers a bulk operation applies to: every
+// container when all is set, otherwise those matching the --filter specs
+func (f *CommandFactory) selectContainers(ctx context.Context, all bool, filterSpecs []string) ([]*container.Container, error) {
+	if !all && len(filterSpecs) == 0 {
+		return nil, fmt.Errorf("specify container names, --all or --filter")
+	}
+	filters, err := parseListFilters(filterSpecs)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := f.ContainerMgr.ListContainers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	return filterContainers(containers, filters), nil
+}
+
+// shortNames strips the container prefix from each container name
+func (f *CommandFactory) shortNames(containers []*container.Container) []string {
+	names := make([]string, len(containers))
+	for i, c := range containers {
+		names[i] = strings.TrimPrefix(c.Name, f.Config.ContainerPrefix+"-")
+	}
+	return names
+}
+
+// runBulk calls fn for each name with at most parallel calls in flight.
+// done, if set, is called as each one finishes; results keep the input order.
+func runBulk(names []string, parallel int, fn func(name string) (string, error), done func(bulkResult)) []bulkResult {
+	if parallel < 1 {
+		parallel = 1
+	}
+	results := make([]bulkResult, len(names))
+	sem := make(chan struct{}, parallel)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			output, err := fn(name)
+			result := bulkResult{name: name, output: output, err: err}
+			results[i] = result
+			if done != nil {
+				mu.Lock()
+				done(result)
+				mu.Unlock()
+			}
+		}(i, name)
+	}
+	wg.Wait()
+	return results
+}
+
+// failedResults returns the results that ended in an error
+func failedResults(results []bulkResult) []bulkResult {
+	var failed []bulkResult
+	for _, r := range results {
+		if r.err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// prefixLines prepends prefix to every line of output
+func prefixLines(prefix, output string) string {
+	output = strings.TrimRight(output, "\n")
+	if output == "" {
+		return ""
+	}
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
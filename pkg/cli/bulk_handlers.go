@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/juju/ansiterm"
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+	"l8s/pkg/container"
+	"l8s/pkg/notify"
+)
+
+// isBulk reports whether a start, stop or remove invocation targets several
+// containers: more than one name, or --all / --filter
+func isBulk(cmd *cobra.Command, args []string) bool {
+	all, _ := cmd.Flags().GetBool("all")
+	filterSpecs, _ := cmd.Flags().GetStringArray("filter")
+	return len(args) > 1 || all || len(filterSpecs) > 0
+}
+
+// bulkTargets resolves the short names a bulk operation applies to. Named
+// containers are used as given; otherwise --all / --filter select from the
+// containers for which want returns true.
+func (f *CommandFactory) bulkTargets(ctx context.Context, cmd *cobra.Command, args []string, want func(*container.Container) bool) ([]string, error) {
+	all, _ := cmd.Flags().GetBool("all")
+	filterSpecs, _ := cmd.Flags().GetStringArray("filter")
+	if len(args) > 0 {
+		if all || len(filterSpecs) > 0 {
+			return nil, fmt.Errorf("container names cannot be combined with --all or --filter")
+		}
+		names := make([]string, len(args))
+		for i, arg := range args {
+			names[i] = strings.TrimPrefix(arg, f.Config.ContainerPrefix+"-")
+		}
+		return names, nil
+	}
+
+	containers, err := f.selectContainers(ctx, all, filterSpecs)
+	if err != nil {
+		return nil, err
+	}
+	var selected []*container.Container
+	for _, c := range containers {
+		if want(c) {
+			selected = append(selected, c)
+		}
+	}
+	return f.shortNames(selected), nil
+}
+
+// printBulkSummary prints a NAME/RESULT table and returns an error if any
+// operation failed
+func printBulkSummary(cmd *cobra.Command, verb string, results []bulkResult) error {
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	if os.Getenv("NO_COLOR") == "" {
+		fmt.Fprintf(w, "%s\t%s\n", color.Bold("NAME"), color.Bold("RESULT"))
+	} else {
+		fmt.Fprintln(w, "NAME\tRESULT")
+	}
+	for _, r := range results {
+		result := "✓ " + verb
+		if r.err != nil {
+			// Keep the table to one line per container
+			result = "✗ " + strings.SplitN(r.err.Error(), "\n", 2)[0]
+		} else if r.output != "" {
+			result = r.output
+		}
+		fmt.Fprintf(w, "%s\t%s\n", r.name, result)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed := failedResults(results); len(failed) > 0 {
+		return fmt.Errorf("%d of %d container(s) failed", len(failed), len(results))
+	}
+	return nil
+}
+
+// runBulkStartStop starts or stops several containers concurrently
+func (f *CommandFactory) runBulkStartStop(cmd *cobra.Command, args []string, start bool) error {
+	ctx := context.Background()
+	verb := "stopped"
+	op := f.ContainerMgr.StopContainer
+	want := func(c *container.Container) bool { return c.Status == "running" }
+	if start {
+		verb = "started"
+		op = f.ContainerMgr.StartContainer
+		want = func(c *container.Container) bool { return c.Status != "running" }
+	}
+
+	names, err := f.bulkTargets(ctx, cmd, args, want)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Println("No containers to change")
+		return nil
+	}
+
+	parallel, _ := cmd.Flags().GetInt("parallel")
+	results := runBulk(names, parallel, func(name string) (string, error) {
+		return "", op(ctx, name)
+	}, nil)
+	return printBulkSummary(cmd, verb, results)
+}
+
+// runBulkRemove removes several containers after a single confirmation.
+// Protection, ownership and unsaved work are checked for each container as
+// for a single remove; containers failing a check are skipped.
+func (f *CommandFactory) runBulkRemove(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	names, err := f.bulkTargets(ctx, cmd, args, func(*container.Container) bool { return true })
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Println("No containers to remove")
+		return nil
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	forceProtected, _ := cmd.Flags().GetBool("force-protected")
+	keepVolumes, _ := cmd.Flags().GetBool("keep-volumes")
+	trash := f.Config.RemoveToTrash
+	if cmd.Flags().Changed("trash") {
+		trash, _ = cmd.Flags().GetBool("trash")
+	}
+	trash = trash && !keepVolumes
+
+	if !force {
+		fmt.Printf("About to remove %d container(s):\n", len(names))
+		for _, name := range names {
+			fmt.Printf("  %s-%s\n", f.Config.ContainerPrefix, name)
+		}
+		prompt := "Remove them"
+		if trash {
+			prompt += " (volumes kept in trash)"
+		} else if !keepVolumes {
+			prompt += " and their volumes"
+		}
+		fmt.Print(prompt + "? (y/N): ")
+		response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			return err
+		}
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	// Remotes are removed from the current repository when a container's own
+	// is gone
+	repoRoot, _ := f.GitClient.GetRepositoryRoot(".")
+	guard := f.removeGuard(force, forceProtected, keepVolumes || trash)
+	guard.SkipUnsaved, _ = cmd.Flags().GetBool("skip-unsaved-check")
+
+	parallel, _ := cmd.Flags().GetInt("parallel")
+	results := runBulk(names, parallel, func(name string) (string, error) {
+		if err := f.checkRemove(ctx, name, guard); err != nil {
+			return "", err
+		}
+
+		// Looked up first: the worktree label goes with the container
+		repo := f.remoteRepo(ctx, name, repoRoot)
+		if trash {
+			if err := f.ContainerMgr.TrashContainer(ctx, name); err != nil {
+				return "", err
+			}
+		} else if err := f.ContainerMgr.RemoveContainer(ctx, name, !keepVolumes); err != nil {
+			return "", err
+		}
+		if repo != "" {
+			_ = f.GitClient.RemoveRemote(repo, name)
+		}
+		f.forgetRepo(name)
+		f.forgetProtection(name)
+		f.notifyEvent(notify.EventRemove, name)
+
+		switch {
+		case trash:
+			return "✓ removed, volumes in trash", nil
+		case keepVolumes:
+			return "✓ removed, volumes kept", nil
+		}
+		return "", nil
+	}, nil)
+
+	f.syncIngress()
+	if trash {
+		f.purgeExpiredTrash(ctx)
+	}
+	return printBulkSummary(cmd, "removed", results)
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunBulk(t *testing.T) {
+	var inFlight, peak int32
+	names := []string{"a", "b", "c", "d", "e"}
+
+	results := runBulk(names, 2, func(name string) (string, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		if name == "c" {
+			return "", errors.New("boom")
+		}
+		return "ok " + name, nil
+	}, nil)
+
+	assert.LessOrEqual(t, peak, int32(2))
+	assert.Len(t, results, 5)
+	assert.Equal(t, "ok a", results[0].output)
+	failed := failedResults(results)
+	assert.Len(t, failed, 1)
+	assert.Equal(t, "c", failed[0].name)
+}
+
+func TestPrefixLines(t *testing.T) {
+	assert.Equal(t, "app | one\napp | two\n", prefixLines("app | ", "one\ntwo\n"))
+	assert.Equal(t, "", prefixLines("app | ", ""))
+}
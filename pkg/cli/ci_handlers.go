@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"l8s/pkg/color"
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+
+	"github.com/spf13/cobra"
+)
+
+// ciBranch is the branch the ref under test is pushed to in the container
+const ciBranch = "l8s-ci"
+
+// ciCollectScript archives the paths and globs given as arguments, relative
+// to the working directory, as a gzipped tar on stdout. Patterns that match
+// nothing are skipped so one missing artifact does not lose the rest.
+const ciCollectScript = `for p in "$@"; do for f in $p; do [ -e "$f" ] && printf '%s\0' "$f"; done; done | tar -czf - --null -T -`
+
+// taskError is a CI task that exited non-zero; l8s exits with the same status
+type taskError struct {
+	code int
+}
+
+func (e *taskError) Error() string {
+	return fmt.Sprintf("task failed with exit code %d", e.code)
+}
+
+func (e *taskError) ExitCode() int {
+	return e.code
+}
+
+// runCICreateEphemeral creates a throwaway container at a ref, runs the task
+// in it, copies its log and artifacts back and removes the container again
+func (f *CommandFactory) runCICreateEphemeral(cmd *cobra.Command, args []string) (err error) {
+	if f.Config.Runtime == config.RuntimeFake {
+		return fmt.Errorf("ci create-ephemeral needs SSH into the container, which the fake runtime does not provide")
+	}
+	repoRoot, err := f.GitClient.GetRepositoryRoot(".")
+	if err != nil {
+		return fmt.Errorf("l8s ci must be run from within a git repository")
+	}
+
+	ref, _ := cmd.Flags().GetString("ref")
+	sha, err := f.GitClient.ResolveRef(repoRoot, ref)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	if name == "" {
+		name = ciContainerName(sha)
+	}
+	ctx := context.Background()
+	if existing, err := f.ContainerMgr.GetContainerInfo(ctx, name); err == nil && existing != nil {
+		return fmt.Errorf("container '%s' already exists", name)
+	}
+
+	outputDir, _ := cmd.Flags().GetString("output")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	sshKey, err := f.localPublicKey()
+	if err != nil {
+		return err
+	}
+
+	color.Printf("🎳 {cyan}Creating ephemeral container:{reset} {bold}%s-%s{reset} at %s\n",
+		f.Config.ContainerPrefix, name, shortSHA(sha))
+	if _, err := f.ContainerMgr.CreateContainer(ctx, name, sshKey, container.CreateOptions{}); err != nil {
+		return withQuotaHint(err, f.Config.ActiveConnection)
+	}
+
+	keep, _ := cmd.Flags().GetBool("keep")
+	keepOnFailure, _ := cmd.Flags().GetBool("keep-on-failure")
+	defer func() {
+		if keep || (keepOnFailure && err != nil) {
+			color.Printf("{yellow}!{reset} Keeping container {bold}%s{reset}; remove it with 'l8s remove %s'\n", name, name)
+			return
+		}
+		color.Printf("{cyan}→{reset} Removing container {bold}%s{reset}...\n", name)
+		_ = f.GitClient.RemoveRemote(repoRoot, name)
+		if rmErr := f.ContainerMgr.RemoveContainer(context.Background(), name, true); rmErr != nil {
+			color.Printf("{red}✗{reset} Failed to remove container: %v\n", rmErr)
+		}
+	}()
+
+	remoteURL := fmt.Sprintf("%s-%s:/workspace/project", f.Config.ContainerPrefix, name)
+	if err := f.GitClient.AddRemote(repoRoot, name, remoteURL); err != nil {
+		return fmt.Errorf("failed to add git remote: %w", err)
+	}
+	if err := f.ContainerMgr.WaitForSSH(ctx, name); err != nil {
+		color.Printf("{yellow}!{reset} SSH is not ready yet, pushing anyway: %v\n", err)
+	}
+	color.Printf("{cyan}→{reset} Pushing {bold}%s{reset} (%s) to container...\n", ref, shortSHA(sha))
+	if err := pushWithRetry(func() error { return f.GitClient.PushRef(repoRoot, sha, name, ciBranch) }); err != nil {
+		return fmt.Errorf("failed to push %s: %w", ref, err)
+	}
+	checkoutCmd := []string{"su", "-", f.Config.ContainerUser, "-c",
+		"cd /workspace/project && git checkout -q " + ciBranch}
+	if err := f.ContainerMgr.ExecContainer(ctx, name, checkoutCmd); err != nil {
+		return fmt.Errorf("failed to check out %s in container: %w", ref, err)
+	}
+
+	taskErr := f.runCITask(ctx, name, args, filepath.Join(outputDir, "task.log"))
+
+	// Artifacts are collected even when the task fails; they often explain why
+	if artifacts, _ := cmd.Flags().GetStringArray("artifact"); len(artifacts) > 0 {
+		if err := f.collectArtifacts(ctx, name, "", artifacts, outputDir); err != nil {
+			color.Printf("{red}✗{reset} Failed to collect artifacts: %v\n", err)
+			if taskErr == nil {
+				return err
+			}
+		}
+	}
+	if taskErr != nil {
+		return taskErr
+	}
+	color.Printf("{green}✓{reset} Task succeeded; log and artifacts in {bold}%s{reset}\n", outputDir)
+	return nil
+}
+
+// runCITask runs the task over SSH, teeing its output to logPath
+func (f *CommandFactory) runCITask(ctx context.Context, name string, command []string, logPath string) error {
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return fmt.Errorf("failed to create task log: %w", err)
+	}
+	defer logFile.Close()
+
+	color.Printf("{cyan}→{reset} Running {bold}%s{reset}\n", strings.Join(command, " "))
+	start := time.Now()
+	err = f.ContainerMgr.SSHIntoContainer(ctx, name, container.SSHOptions{
+		Command: command,
+		Stdout:  io.MultiWriter(os.Stdout, logFile),
+		Stderr:  io.MultiWriter(os.Stderr, logFile),
+	})
+	elapsed := time.Since(start).Round(time.Second)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		color.Printf("{red}✗{reset} Task exited with code %d after %s\n", exitErr.ExitCode(), elapsed)
+		return &taskError{code: exitErr.ExitCode()}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run task: %w", err)
+	}
+	color.Printf("{green}✓{reset} Task finished in %s\n", elapsed)
+	return nil
+}
+
+// collectArtifacts streams the files matching patterns in workdir (the
+// project when empty) out of the container as a tarball and unpacks it into
+// outputDir
+func (f *CommandFactory) collectArtifacts(ctx context.Context, name, workdir string, patterns []string, outputDir string) error {
+	archive, err := os.CreateTemp("", "l8s-artifacts-*.tar.gz")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	color.Printf("{cyan}→{reset} Collecting artifacts: %s\n", strings.Join(patterns, ", "))
+	var stderr strings.Builder
+	err = f.ContainerMgr.SSHIntoContainer(ctx, name, container.SSHOptions{
+		Command: append([]string{"sh", "-c", ciCollectScript, "sh"}, patterns...),
+		Stdout:  archive,
+		Stderr:  &stderr,
+		WorkDir: workdir,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	extract := exec.Command("tar", "-xzf", archive.Name(), "-C", outputDir)
+	if output, err := extract.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to unpack artifacts: %w\nOutput: %s", err, string(output))
+	}
+	listing, err := exec.Command("tar", "-tzf", archive.Name()).Output()
+	if err != nil || len(strings.TrimSpace(string(listing))) == 0 {
+		color.Printf("{yellow}!{reset} No artifacts matched\n")
+		return nil
+	}
+	color.Printf("{green}✓{reset} Collected %d artifact path(s) into {bold}%s{reset}\n",
+		len(strings.Split(strings.TrimSpace(string(listing)), "\n")), outputDir)
+	return nil
+}
+
+// ciContainerName names an ephemeral container after the commit under test,
+// with a random suffix so concurrent runs of the same commit do not collide
+func ciContainerName(sha string) string {
+	suffix := make([]byte, 2)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("ci-%s-%s", shortSHA(sha), hex.EncodeToString(suffix))
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"l8s/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCIContainerName(t *testing.T) {
+	sha := "0123456789abcdef0123456789abcdef01234567"
+	name := ciContainerName(sha)
+	assert.Regexp(t, regexp.MustCompile(`^ci-0123456-[0-9a-f]{4}$`), name)
+}
+
+func TestTaskErrorExitCode(t *testing.T) {
+	err := fmt.Errorf("ci: %w", &taskError{code: 3})
+	assert.Equal(t, 3, errors.ExitCode(err))
+	assert.EqualError(t, err, "ci: task failed with exit code 3")
+}
@@ -1,16 +1,45 @@
 package cli
 
 import (
+	"archive/tar"
+	"bytes"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 )
 
+// powershell runs a PowerShell script, used for clipboard access on Windows.
+// Under WSL the Windows binary must be called with its .exe suffix.
+func powershell(script string) *exec.Cmd {
+	name := "powershell"
+	if isWSL() {
+		name = "powershell.exe"
+	}
+	return exec.Command(name, "-NoProfile", "-NonInteractive", "-Command", script)
+}
+
+// psQuote quotes a string as a PowerShell single-quoted literal
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
 // extractClipboardContent detects clipboard type and extracts content to a temporary file
-// Returns: content type ("png" or "txt"), path to temp file, error
-func extractClipboardContent() (string, string, error) {
+// Returns: content type ("files", "png", "md" or "txt"), path to temp file, error.
+// Rich text becomes markdown unless plain is set or it has no formatting.
+func extractClipboardContent(plain bool) (string, string, error) {
+	// Copied files come first: file managers also put their icons and names on the clipboard
+	if paths := clipboardFiles(); len(paths) > 0 {
+		path, err := extractFilesFromClipboard(paths)
+		if err != nil {
+			return "", "", err
+		}
+		return "files", path, nil
+	}
+
 	// First check if there's an image in clipboard
 	if hasImageInClipboard() {
 		path, err := extractImageFromClipboard()
@@ -20,6 +49,16 @@ func extractClipboardContent() (string, string, error) {
 		return "png", path, nil
 	}
 
+	if !plain {
+		if md, formatted := rtfToMarkdown(clipboardRTF()); formatted {
+			tempFile := filepath.Join(os.TempDir(), "l8s-clipboard.md")
+			if err := os.WriteFile(tempFile, []byte(md), 0644); err != nil {
+				return "", "", fmt.Errorf("failed to write clipboard markdown to file: %w", err)
+			}
+			return "md", tempFile, nil
+		}
+	}
+
 	// Check for text content
 	if hasTextInClipboard() {
 		path, err := extractTextFromClipboard()
@@ -29,11 +68,131 @@ func extractClipboardContent() (string, string, error) {
 		return "txt", path, nil
 	}
 
-	return "", "", fmt.Errorf("no image or text found in clipboard")
+	return "", "", fmt.Errorf("no files, image or text found in clipboard")
+}
+
+// clipboardFiles lists the files copied to the clipboard, e.g. in Finder or Explorer
+func clipboardFiles() []string {
+	var cmd *exec.Cmd
+	if usesWindowsClipboard() {
+		cmd = powershell("[Console]::OutputEncoding = [Text.Encoding]::UTF8; " +
+			"Get-Clipboard -Format FileDropList | ForEach-Object { $_.FullName }")
+	} else {
+		cmd = exec.Command("osascript", "-l", "JavaScript", "-e", `ObjC.import("AppKit");
+var urls = $.NSPasteboard.generalPasteboard.readObjectsForClassesOptions($([$.NSURL]), $({NSPasteboardURLReadingFileURLsOnlyKey: true}));
+var paths = [];
+for (var i = 0; i < urls.count; i++) paths.push(urls.objectAtIndex(i).path.js);
+paths.join("\n");`)
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			// Under WSL, Explorer's paths need translating back
+			paths = append(paths, linuxPath(line))
+		}
+	}
+	return paths
+}
+
+// extractFilesFromClipboard archives the copied files, with directories
+// and their contents, to a temporary tar file
+func extractFilesFromClipboard(paths []string) (string, error) {
+	tempFile := filepath.Join(os.TempDir(), "l8s-clipboard.tar")
+	file, err := os.Create(tempFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to create clipboard archive: %w", err)
+	}
+	defer file.Close()
+	if err := writeFileArchive(file, paths); err != nil {
+		return "", fmt.Errorf("failed to archive clipboard files: %w", err)
+	}
+	return tempFile, file.Close()
+}
+
+// writeFileArchive writes paths to w as a tar archive, each under its base
+// name. Directories are included recursively and symlinks are kept as links.
+func writeFileArchive(w io.Writer, paths []string) error {
+	tw := tar.NewWriter(w)
+	for _, root := range paths {
+		root = filepath.Clean(root)
+		parent := filepath.Dir(root)
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			var link string
+			if info.Mode()&os.ModeSymlink != 0 {
+				if link, err = os.Readlink(path); err != nil {
+					return err
+				}
+			}
+			header, err := tar.FileInfoHeader(info, link)
+			if err != nil {
+				return err
+			}
+			name, err := filepath.Rel(parent, path)
+			if err != nil {
+				return err
+			}
+			header.Name = filepath.ToSlash(name)
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(tw, f)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// clipboardRTF returns the clipboard's rich text, or "" if it has none
+func clipboardRTF() string {
+	if usesWindowsClipboard() {
+		output, err := powershell("[Console]::OutputEncoding = [Text.Encoding]::UTF8; Add-Type -AssemblyName System.Windows.Forms; " +
+			"if ([System.Windows.Forms.Clipboard]::ContainsText('Rtf')) { [System.Windows.Forms.Clipboard]::GetText('Rtf') }").Output()
+		if err != nil {
+			return ""
+		}
+		return string(output)
+	}
+
+	// AppleScript prints the data as «data RTF 7B5C72...»
+	output, err := exec.Command("osascript", "-e", "the clipboard as «class RTF »").Output()
+	if err != nil {
+		return ""
+	}
+	data := strings.TrimSpace(string(output))
+	data = strings.TrimPrefix(data, "«data RTF ")
+	data = strings.TrimSuffix(data, "»")
+	rtf, err := hex.DecodeString(data)
+	if err != nil {
+		return ""
+	}
+	return string(rtf)
 }
 
 // hasImageInClipboard checks if clipboard contains an image
 func hasImageInClipboard() bool {
+	if usesWindowsClipboard() {
+		output, err := powershell("Add-Type -AssemblyName System.Windows.Forms; [System.Windows.Forms.Clipboard]::ContainsImage()").Output()
+		return err == nil && strings.TrimSpace(string(output)) == "True"
+	}
+
 	// Use osascript to check clipboard info
 	cmd := exec.Command("osascript", "-e", "clipboard info")
 	output, err := cmd.Output()
@@ -53,6 +212,16 @@ func extractImageFromClipboard() (string, error) {
 	// Create temp file for image
 	tempFile := filepath.Join(os.TempDir(), "l8s-clipboard.png")
 
+	if usesWindowsClipboard() {
+		// PowerShell writes the file, so under WSL it needs the Windows view of the path
+		script := fmt.Sprintf("Add-Type -AssemblyName System.Windows.Forms, System.Drawing; "+
+			"[System.Windows.Forms.Clipboard]::GetImage().Save(%s, [System.Drawing.Imaging.ImageFormat]::Png)", psQuote(windowsPath(tempFile)))
+		if output, err := powershell(script).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to extract image from clipboard: %s", strings.TrimSpace(string(output)))
+		}
+		return tempFile, nil
+	}
+
 	// AppleScript to extract PNG from clipboard
 	script := fmt.Sprintf(`
 		set thePNG to the clipboard as «class PNGf»
@@ -72,8 +241,7 @@ func extractImageFromClipboard() (string, error) {
 // hasTextInClipboard checks if clipboard contains text
 func hasTextInClipboard() bool {
 	// pbpaste will return non-empty output if there's text
-	cmd := exec.Command("pbpaste")
-	output, err := cmd.Output()
+	output, err := textClipboardCommand().Output()
 	if err != nil {
 		return false
 	}
@@ -85,12 +253,14 @@ func extractTextFromClipboard() (string, error) {
 	// Create temp file for text
 	tempFile := filepath.Join(os.TempDir(), "l8s-clipboard.txt")
 
-	// Use pbpaste to get text content
-	cmd := exec.Command("pbpaste")
-	output, err := cmd.Output()
+	output, err := textClipboardCommand().Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to extract text from clipboard: %w", err)
 	}
+	if usesWindowsClipboard() {
+		// Containers expect Unix line endings
+		output = []byte(strings.ReplaceAll(string(output), "\r\n", "\n"))
+	}
 
 	// Write to temp file
 	if err := os.WriteFile(tempFile, output, 0644); err != nil {
@@ -98,4 +268,29 @@ func extractTextFromClipboard() (string, error) {
 	}
 
 	return tempFile, nil
-}
\ No newline at end of file
+}
+
+// textClipboardCommand prints the clipboard text: pbpaste on macOS,
+// Get-Clipboard on Windows and WSL
+func textClipboardCommand() *exec.Cmd {
+	if usesWindowsClipboard() {
+		// Console output defaults to the OEM code page, which mangles non-ASCII text
+		return powershell("[Console]::OutputEncoding = [Text.Encoding]::UTF8; Get-Clipboard -Raw")
+	}
+	return exec.Command("pbpaste")
+}
+
+// setHostClipboard replaces the clipboard with text: pbcopy on macOS,
+// Set-Clipboard on Windows and WSL
+func setHostClipboard(text []byte) error {
+	cmd := exec.Command("pbcopy")
+	if usesWindowsClipboard() {
+		// Read stdin as UTF-8 for the same reason Get-Clipboard writes it
+		cmd = powershell("[Console]::InputEncoding = [Text.Encoding]::UTF8; Set-Clipboard -Value ([Console]::In.ReadToEnd())")
+	}
+	cmd.Stdin = bytes.NewReader(text)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to set clipboard: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -1,11 +1,16 @@
 package cli
 
 import (
+	"archive/tar"
+	"bytes"
+	"io"
 	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExtractClipboardContent(t *testing.T) {
@@ -49,7 +54,7 @@ func TestExtractClipboardContent(t *testing.T) {
 			tt.setup()
 			defer tt.cleanup()
 
-			contentType, path, err := extractClipboardContent()
+			contentType, path, err := extractClipboardContent(false)
 			
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -105,4 +110,48 @@ func TestExtractTextFromClipboard(t *testing.T) {
 
 	// This test would need text in clipboard
 	t.Skip("Requires text in clipboard")
-}
\ No newline at end of file
+}
+func TestIsWSLKernel(t *testing.T) {
+	assert.True(t, isWSLKernel("5.15.153.1-microsoft-standard-WSL2\n"))
+	assert.True(t, isWSLKernel("4.4.0-19041-Microsoft"))
+	assert.False(t, isWSLKernel("6.8.0-45-generic"))
+}
+
+func TestPSQuote(t *testing.T) {
+	assert.Equal(t, `'C:\Temp\l8s-clipboard.png'`, psQuote(`C:\Temp\l8s-clipboard.png`))
+	assert.Equal(t, `'\\wsl.localhost\Jo''s Ubuntu\tmp'`, psQuote(`\\wsl.localhost\Jo's Ubuntu\tmp`))
+}
+
+func TestWriteFileArchive(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "logs", "old"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logs", "old", "a.log"), []byte("log"), 0644))
+	require.NoError(t, os.Symlink("notes.txt", filepath.Join(dir, "link")))
+
+	var buf bytes.Buffer
+	paths := []string{filepath.Join(dir, "notes.txt"), filepath.Join(dir, "logs"), filepath.Join(dir, "link")}
+	require.NoError(t, writeFileArchive(&buf, paths))
+
+	contents := map[string]string{}
+	tr := tar.NewReader(&buf)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		contents[header.Name] = string(data) + header.Linkname
+	}
+	assert.Equal(t, map[string]string{
+		"notes.txt":      "hello",
+		"logs":           "",
+		"logs/old":       "",
+		"logs/old/a.log": "log",
+		"link":           "notes.txt",
+	}, contents)
+
+	assert.Error(t, writeFileArchive(&bytes.Buffer{}, []string{filepath.Join(dir, "missing")}))
+}
@@ -0,0 +1,349 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/juju/ansiterm"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+	"l8s/pkg/color"
+	"l8s/pkg/config"
+)
+
+// runConfigValidate strictly checks a config file, reporting unknown keys
+// and validation errors
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	path := config.GetConfigPath()
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w\n\nRun 'l8s init' to create one", err)
+	}
+
+	problems := config.Check(data)
+	if len(problems) == 0 {
+		color.Printf("{green}✓{reset} %s is valid\n", path)
+		return nil
+	}
+
+	for _, p := range problems {
+		color.Printf("{red}✗{reset} %s\n", p)
+	}
+	return fmt.Errorf("%s has %d problem(s)", path, len(problems))
+}
+
+// runConfigShow prints the config file, or with --effective every resolved
+// setting along with where it came from
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	path := config.GetConfigPath()
+
+	if effective, _ := cmd.Flags().GetBool("effective"); !effective {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w\n\nRun 'l8s init' to create one", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "# %s\n%s", path, data)
+		return nil
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w\n\nRun 'l8s config validate' for details", err)
+	}
+	settings, err := cfg.Settings()
+	if err != nil {
+		return err
+	}
+
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	if os.Getenv("NO_COLOR") == "" {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", color.Bold("KEY"), color.Bold("VALUE"), color.Bold("SOURCE"))
+	} else {
+		fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	}
+	for _, s := range settings {
+		value := s.Value
+		if value == "" {
+			value = `""`
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Key, value, s.Source)
+	}
+	return w.Flush()
+}
+
+// readConfigFile returns the config file contents, or nothing if it does not exist yet
+func readConfigFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return data, nil
+}
+
+// problemsError formats config problems as a single error
+func problemsError(problems []config.Problem) error {
+	lines := make([]string, len(problems))
+	for i, p := range problems {
+		lines[i] = "  " + p.String()
+	}
+	return fmt.Errorf("invalid configuration:\n%s", strings.Join(lines, "\n"))
+}
+
+// runConfigSet changes a single setting, refusing values that fail validation
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key, value := args[0], args[1]
+	path := config.GetConfigPath()
+
+	data, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	updated, err := config.SetValue(data, key, value)
+	if err != nil {
+		return err
+	}
+	if problems := config.Check(updated); len(problems) > 0 {
+		return problemsError(problems)
+	}
+	if err := config.WriteFile(path, updated); err != nil {
+		return err
+	}
+
+	if config.IsSecret(key) {
+		value = "********"
+	}
+	color.Printf("{green}✓{reset} Set {bold}%s{reset} = %s\n", key, value)
+	return nil
+}
+
+// editorCommand returns the user's editor from $VISUAL or $EDITOR, falling
+// back to vi (notepad on Windows)
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(env); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// editFile opens path in the user's editor. $EDITOR may include arguments,
+// e.g. "code --wait"; without sh on Windows they are split on whitespace.
+func editFile(path string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		fields := strings.Fields(editorCommand())
+		return exec.Command(fields[0], append(fields[1:], path)...)
+	}
+	return exec.Command("sh", "-c", editorCommand()+` "$1"`, "sh", path)
+}
+
+// runConfigEdit opens the config file in an editor and only saves it once
+// the edited version validates
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	path := config.GetConfigPath()
+
+	data, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Edit a copy so an invalid result never replaces the working config
+	tmpDir, err := os.MkdirTemp("", "l8s-config-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpPath := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		editCmd := editFile(tmpPath)
+		editCmd.Stdin = os.Stdin
+		editCmd.Stdout = os.Stdout
+		editCmd.Stderr = os.Stderr
+		if err := editCmd.Run(); err != nil {
+			return fmt.Errorf("editor failed: %w", err)
+		}
+
+		edited, err := os.ReadFile(tmpPath)
+		if err != nil {
+			return fmt.Errorf("failed to read edited config: %w", err)
+		}
+		if string(edited) == string(data) {
+			fmt.Println("No changes made")
+			return nil
+		}
+
+		problems := config.Check(edited)
+		if len(problems) == 0 {
+			if err := config.WriteFile(path, edited); err != nil {
+				return err
+			}
+			color.Printf("{green}✓{reset} Saved %s\n", path)
+			return nil
+		}
+
+		for _, p := range problems {
+			color.Printf("{red}✗{reset} %s\n", p)
+		}
+		fmt.Printf("Re-open the editor to fix these? [Y/n]: ")
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "" && response != "y" && response != "yes" {
+			return fmt.Errorf("changes discarded; %s was not modified", path)
+		}
+	}
+}
+
+// runConfigEnv lists the L8S_* override variables and any that are set
+func runConfigEnv(cmd *cobra.Command, args []string) error {
+	vars := config.EnvVars()
+	names := make([]string, 0, len(vars))
+	for env := range vars {
+		names = append(names, env)
+	}
+	sort.Strings(names)
+
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	if os.Getenv("NO_COLOR") == "" {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", color.Bold("VARIABLE"), color.Bold("KEY"), color.Bold("VALUE"))
+	} else {
+		fmt.Fprintln(w, "VARIABLE\tKEY\tVALUE")
+	}
+	for _, env := range names {
+		value, ok := os.LookupEnv(env)
+		switch {
+		case !ok:
+			value = "-"
+		case config.IsSecret(vars[env]):
+			value = "********"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", env, vars[env], value)
+	}
+	return w.Flush()
+}
+
+// runConfigPath prints where l8s keeps its files
+func runConfigPath(cmd *cobra.Command, args []string) error {
+	paths := [][2]string{
+		{"Config file", config.GetConfigPath()},
+		{"Config directory", config.ConfigDir()},
+		{"SSH CA", filepath.Join(config.ConfigDir(), "ca")},
+		{"Known hosts", filepath.Join(config.ConfigDir(), "known_hosts")},
+		{"Dotfiles", filepath.Join(config.ConfigDir(), "dotfiles")},
+		{"Cache directory", config.CacheDir()},
+		{"State directory", config.StateDir()},
+		{"Data directory", config.DataDir()},
+	}
+
+	// Configured locations win over the defaults
+	if cfg, err := config.Load(config.GetConfigPath()); err == nil {
+		if cfg.CAPrivateKeyPath != "" {
+			paths[2][1] = filepath.Dir(cfg.CAPrivateKeyPath)
+		}
+		if cfg.KnownHostsPath != "" {
+			paths[3][1] = cfg.KnownHostsPath
+		}
+		if cfg.DotfilesPath != "" {
+			paths[4][1] = cfg.DotfilesPath
+		}
+	}
+
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	for _, p := range paths {
+		fmt.Fprintf(w, "%s:\t%s\n", p[0], p[1])
+	}
+	if legacy := config.LegacyConfigDir(); legacy != config.ConfigDir() {
+		if _, err := os.Stat(legacy); err == nil {
+			fmt.Fprintf(w, "Legacy directory:\t%s\n", legacy)
+		}
+	}
+	return w.Flush()
+}
+
+// runConfigEncrypt stores a setting encrypted with age, or in the OS keychain
+// with --keychain, so config.yaml can be shared without leaking it
+func runConfigEncrypt(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	path := config.GetConfigPath()
+
+	secret := ""
+	if len(args) == 2 {
+		secret = args[1]
+	} else {
+		fmt.Fprintf(os.Stderr, "Value for %s: ", key)
+		input, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return fmt.Errorf("failed to read value: %w", err)
+		}
+		secret = string(input)
+	}
+	if secret == "" {
+		return fmt.Errorf("refusing to store an empty value")
+	}
+
+	data, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	// The identity location may itself be configured
+	cfg := config.DefaultConfig()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	var stored string
+	if keychain, _ := cmd.Flags().GetBool("keychain"); keychain {
+		stored, err = config.StoreKeychain(key, secret)
+	} else {
+		stored, err = cfg.EncryptAge(secret)
+	}
+	if err != nil {
+		return err
+	}
+
+	updated, err := config.SetValue(data, key, stored)
+	if err != nil {
+		return err
+	}
+	if problems := config.Check(updated); len(problems) > 0 {
+		return problemsError(problems)
+	}
+	if err := config.WriteFile(path, updated); err != nil {
+		return err
+	}
+
+	if strings.HasPrefix(stored, config.KeychainPrefix) {
+		color.Printf("{green}✓{reset} Stored {bold}%s{reset} in the OS keychain\n", key)
+	} else {
+		color.Printf("{green}✓{reset} Encrypted {bold}%s{reset} with %s\n", key, cfg.AgeIdentityPath())
+		color.Printf("{dim}Keep that identity file out of any repository that syncs config.yaml{reset}\n")
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"l8s/pkg/config"
+	"l8s/pkg/git"
 	"l8s/pkg/ssh"
 )
 
@@ -66,6 +67,9 @@ func (c *ConnectionShowCommand) Execute(ctx context.Context) error {
 	if c.config.SSHKeyPath != "" {
 		fmt.Printf("  SSH Key: %s\n", c.config.SSHKeyPath)
 	}
+	if conn.ProxyJump != "" {
+		fmt.Printf("  Proxy Jump: %s\n", conn.ProxyJump)
+	}
 	if conn.Description != "" {
 		fmt.Printf("  Description: %s\n", conn.Description)
 	}
@@ -117,7 +121,7 @@ func (c *ConnectionSwitchCommand) Execute(ctx context.Context) error {
 		
 		if !c.dryRun {
 			for _, container := range updates {
-				err := c.updateSSHConfigEntry(sshConfigPath, container, newConn.Address)
+				err := c.updateSSHConfigEntry(sshConfigPath, container, newConn.Address, newConn.ProxyJump)
 				if err != nil {
 					fmt.Printf("  ✗ %s: %v\n", container, err)
 				} else {
@@ -133,6 +137,24 @@ func (c *ConnectionSwitchCommand) Execute(ctx context.Context) error {
 		}
 	}
 	
+	// ssh:// remotes name the host directly, so they do not follow the SSH entries
+	remoteUpdates, err := findRemoteUpdates(currentAddress, newConn.Address)
+	if err != nil {
+		return fmt.Errorf("failed to read repository index: %w", err)
+	}
+	if len(remoteUpdates) > 0 {
+		fmt.Printf("Updating %d git remotes:\n", len(remoteUpdates))
+		for _, update := range remoteUpdates {
+			if c.dryRun {
+				fmt.Printf("  Would update %s (%s): %s → %s\n", update.Remote, update.Repo, update.URL, update.NewURL)
+			} else if err := git.SetRemoteURL(update.Repo, update.Remote, update.NewURL); err != nil {
+				fmt.Printf("  ✗ %s (%s): %v\n", update.Remote, update.Repo, err)
+			} else {
+				fmt.Printf("  ✓ %s (%s): %s\n", update.Remote, update.Repo, update.NewURL)
+			}
+		}
+	}
+	
 	if !c.dryRun {
 		// Update active connection in config
 		if err := c.config.SetActiveConnection(c.targetConnection); err != nil {
@@ -200,8 +222,9 @@ func (c *ConnectionSwitchCommand) findSSHConfigUpdates(configPath, oldHost, newH
 	return containers, nil
 }
 
-// updateSSHConfigEntry updates the HostName field for a specific SSH config entry
-func (c *ConnectionSwitchCommand) updateSSHConfigEntry(configPath, container, newHost string) error {
+// updateSSHConfigEntry updates the HostName field for a specific SSH config entry,
+// and sets its ProxyJump to newJump, dropping it when newJump is empty
+func (c *ConnectionSwitchCommand) updateSSHConfigEntry(configPath, container, newHost, newJump string) error {
 	// Read the entire SSH config
 	content, err := os.ReadFile(configPath)
 	if err != nil {
@@ -225,6 +248,12 @@ func (c *ConnectionSwitchCommand) updateSSHConfigEntry(configPath, container, ne
 			// Preserve original indentation
 			indent := strings.TrimSuffix(line, trimmed)
 			updatedLines = append(updatedLines, indent+"HostName "+newHost)
+			if newJump != "" {
+				updatedLines = append(updatedLines, indent+"ProxyJump "+newJump)
+			}
+		} else if inTargetBlock && strings.HasPrefix(trimmed, "ProxyJump ") {
+			// Replaced along with HostName
+			continue
 		} else {
 			updatedLines = append(updatedLines, line)
 		}
@@ -301,4 +330,58 @@ func ValidateSSHConfigsMatchConnection(activeAddress string) error {
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+
+// autoSelectConnection applies the auto_connect rules unless --connection or
+// L8S_CONNECTION already chose a connection. The match is used for this
+// command only, or with update_ssh_config made the active connection with
+// SSH entries rewritten as 'l8s connection switch' does.
+func autoSelectConnection(cfg *config.Config) error {
+	if len(cfg.AutoConnect.Rules) == 0 || cfg.Overridden("active_connection") {
+		return nil
+	}
+	rule, ok := cfg.AutoConnect.Pick(func(rule config.AutoConnectRule) bool {
+		return rule.Matches(config.AutoConnectTimeout)
+	})
+	if !ok || rule.Connection == cfg.ActiveConnection {
+		return nil
+	}
+	if !cfg.AutoConnect.UpdateSSHConfig {
+		cfg.UseConnection(rule.Connection)
+		return nil
+	}
+
+	currentAddress, err := cfg.GetActiveAddress()
+	if err != nil {
+		return err
+	}
+	newConn := cfg.Connections[rule.Connection]
+	switcher := &ConnectionSwitchCommand{config: cfg, targetConnection: rule.Connection}
+	sshConfigPath := filepath.Join(getHomeDirFunc(), ".ssh", "config")
+	updates, err := switcher.findSSHConfigUpdates(sshConfigPath, currentAddress, newConn.Address)
+	if err != nil {
+		return fmt.Errorf("failed to read SSH config: %w", err)
+	}
+	for _, container := range updates {
+		if err := switcher.updateSSHConfigEntry(sshConfigPath, container, newConn.Address, newConn.ProxyJump); err != nil {
+			return fmt.Errorf("failed to update SSH config for %s: %w", container, err)
+		}
+	}
+	remoteUpdates, err := findRemoteUpdates(currentAddress, newConn.Address)
+	if err != nil {
+		return fmt.Errorf("failed to read repository index: %w", err)
+	}
+	for _, update := range remoteUpdates {
+		if err := git.SetRemoteURL(update.Repo, update.Remote, update.NewURL); err != nil {
+			return fmt.Errorf("failed to update git remote '%s' in %s: %w", update.Remote, update.Repo, err)
+		}
+	}
+	previous := cfg.ActiveConnection
+	if err := cfg.SetActiveConnection(rule.Connection); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	// stderr keeps machine-readable output clean
+	fmt.Fprintf(os.Stderr, "Switched Podman connection from '%s' to '%s' (auto_connect), updating %d SSH entries and %d git remotes\n",
+		previous, rule.Connection, len(updates), len(remoteUpdates))
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"l8s/pkg/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -205,6 +206,7 @@ func TestConnectionSwitchCommand_UpdateSSHConfigEntry(t *testing.T) {
 		original    string
 		container   string
 		newHost     string
+		newJump     string
 		expected    string
 	}{
 		{
@@ -242,6 +244,31 @@ Host github.com
   HostName vpn.example.com
   Port 2202
   User dev`,
+		},
+		{
+			name: "add proxy jump",
+			original: `Host dev-myproject
+    HostName 192.168.1.100
+    Port 2201`,
+			container: "dev-myproject",
+			newHost:   "10.0.0.50",
+			newJump:   "me@bastion.example.com",
+			expected: `Host dev-myproject
+    HostName 10.0.0.50
+    ProxyJump me@bastion.example.com
+    Port 2201`,
+		},
+		{
+			name: "drop proxy jump",
+			original: `Host dev-myproject
+    HostName 10.0.0.50
+    ProxyJump me@bastion.example.com
+    Port 2201`,
+			container: "dev-myproject",
+			newHost:   "192.168.1.100",
+			expected: `Host dev-myproject
+    HostName 192.168.1.100
+    Port 2201`,
 		},
 		{
 			name: "no change for non-matching container",
@@ -273,7 +300,7 @@ Host dev-project2
 			require.NoError(t, err)
 			
 			cmd := &ConnectionSwitchCommand{}
-			err = cmd.updateSSHConfigEntry(configPath, tt.container, tt.newHost)
+			err = cmd.updateSSHConfigEntry(configPath, tt.container, tt.newHost, tt.newJump)
 			require.NoError(t, err)
 			
 			content, err := os.ReadFile(configPath)
@@ -362,3 +389,43 @@ Host dev-webapp
 	})
 }
 
+
+func TestAutoSelectConnection_UpdateSSHConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv("XDG_STATE_HOME", tmpDir)
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "l8s"), 0755))
+	require.NoError(t, os.WriteFile(config.GetConfigPath(), []byte("active_connection: public\n"), 0644))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".ssh"), 0700))
+	sshConfigPath := filepath.Join(tmpDir, ".ssh", "config")
+	require.NoError(t, os.WriteFile(sshConfigPath, []byte(`Host dev-project1
+    HostName server.example.com
+    Port 2201`), 0600))
+
+	oldGetHomeDir := getHomeDirFunc
+	defer func() { getHomeDirFunc = oldGetHomeDir }()
+	getHomeDirFunc = func() string { return tmpDir }
+
+	cfg := &config.Config{
+		ActiveConnection: "public",
+		Connections: map[string]config.ConnectionConfig{
+			"public":    {Address: "server.example.com"},
+			"tailscale": {Address: "100.64.0.5"},
+		},
+		AutoConnect: config.AutoConnectConfig{
+			// A rule without conditions always matches
+			Rules:           []config.AutoConnectRule{{Connection: "tailscale"}},
+			UpdateSSHConfig: true,
+		},
+	}
+	require.NoError(t, autoSelectConnection(cfg))
+	assert.Equal(t, "tailscale", cfg.ActiveConnection)
+
+	written, err := os.ReadFile(config.GetConfigPath())
+	require.NoError(t, err)
+	assert.Contains(t, string(written), "active_connection: tailscale")
+	entries, err := ParseSSHConfig(sshConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, "100.64.0.5", entries["dev-project1"])
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+// docsTarget prepares the root command and the output directory for the
+// docs generators. The generated-by footer is left out so that packaging
+// builds are reproducible.
+func docsTarget(cmd *cobra.Command, dir string) (*cobra.Command, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	root := cmd.Root()
+	root.DisableAutoGenTag = true
+	return root, nil
+}
+
+// runDocsMan writes a man page per command to args[0] in section 1, and the
+// help topics in section 7. The date comes from SOURCE_DATE_EPOCH when set.
+func (f *CommandFactory) runDocsMan(cmd *cobra.Command, args []string) error {
+	root, err := docsTarget(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	header := &doc.GenManHeader{Manual: "l8s Manual", Source: "l8s"}
+	if err := doc.GenManTree(root, header, args[0]); err != nil {
+		return fmt.Errorf("failed to generate man pages: %w", err)
+	}
+
+	for _, topic := range root.Commands() {
+		if !topic.IsAdditionalHelpTopicCommand() {
+			continue
+		}
+		// Standalone, so the page is named after its file and not l8s(7)
+		name := strings.ReplaceAll(topic.CommandPath(), " ", "-")
+		page := &cobra.Command{Use: name, Short: topic.Short, Long: topic.Long, DisableAutoGenTag: true}
+		if err := writeDoc(filepath.Join(args[0], name+".7"), func(w *os.File) error {
+			return doc.GenMan(page, &doc.GenManHeader{Section: "7", Manual: "l8s Manual", Source: "l8s"}, w)
+		}); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Man pages written to %s\n", args[0])
+	return nil
+}
+
+// runDocsMarkdown writes a markdown page per command and help topic to args[0]
+func (f *CommandFactory) runDocsMarkdown(cmd *cobra.Command, args []string) error {
+	root, err := docsTarget(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	if err := doc.GenMarkdownTree(root, args[0]); err != nil {
+		return fmt.Errorf("failed to generate markdown: %w", err)
+	}
+
+	for _, topic := range root.Commands() {
+		if !topic.IsAdditionalHelpTopicCommand() {
+			continue
+		}
+		name := strings.ReplaceAll(topic.CommandPath(), " ", "_")
+		if err := writeDoc(filepath.Join(args[0], name+".md"), func(w *os.File) error {
+			return doc.GenMarkdown(topic, w)
+		}); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Markdown written to %s\n", args[0])
+	return nil
+}
+
+// writeDoc creates path and fills it with gen
+func writeDoc(path string, gen func(*os.File) error) error {
+	w, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := gen(w); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return w.Close()
+}
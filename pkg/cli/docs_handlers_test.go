@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// docsTestRoot is a small command tree with the docs command and help topics
+func docsTestRoot(f *LazyCommandFactory) *cobra.Command {
+	root := &cobra.Command{Use: "l8s"}
+	root.AddCommand(&cobra.Command{Use: "create", Short: "Create a container", Run: func(*cobra.Command, []string) {}})
+	docs := f.DocsCmd()
+	docs.GroupID = "" // groups only exist on the real root
+	root.AddCommand(docs)
+	root.AddCommand(f.HelpTopicCmds()...)
+	return root
+}
+
+func TestHelpTopicCmds(t *testing.T) {
+	for _, topic := range NewLazyCommandFactory().HelpTopicCmds() {
+		assert.True(t, topic.IsAdditionalHelpTopicCommand(), topic.Name())
+		assert.NotEmpty(t, topic.Short, topic.Name())
+		assert.NotEmpty(t, topic.Long, topic.Name())
+	}
+}
+
+func TestRunDocs(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	dir := t.TempDir()
+
+	root := docsTestRoot(NewLazyCommandFactory())
+	root.SetArgs([]string{"docs", "man", filepath.Join(dir, "man")})
+	require.NoError(t, root.Execute())
+	for _, name := range []string{"l8s.1", "l8s-create.1", "l8s-docs-man.1", "l8s-networking.7", "l8s-connections.7", "l8s-dotfiles.7"} {
+		assert.FileExists(t, filepath.Join(dir, "man", name))
+	}
+	page, err := os.ReadFile(filepath.Join(dir, "man", "l8s-networking.7"))
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "Nov 2023")
+	assert.Contains(t, string(page), "ssh_port_ranges")
+	assert.NotContains(t, string(page), "Auto generated")
+
+	root = docsTestRoot(NewLazyCommandFactory())
+	root.SetArgs([]string{"docs", "markdown", filepath.Join(dir, "md")})
+	require.NoError(t, root.Execute())
+	for _, name := range []string{"l8s.md", "l8s_create.md", "l8s_dotfiles.md"} {
+		assert.FileExists(t, filepath.Join(dir, "md", name))
+	}
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"strings"
+
+	"l8s/pkg/color"
+	"l8s/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+// runDrift shows the packages and files a rebuild of a container would lose
+func (f *CommandFactory) runDrift(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	color.Printf("{cyan}→{reset} Comparing {bold}%s-%s{reset} with its image...\n", f.Config.ContainerPrefix, name)
+	drift, err := f.ContainerMgr.Drift(context.Background(), name)
+	if err != nil {
+		return err
+	}
+
+	declared := make(map[string]bool, len(drift.Declared))
+	for _, pkg := range drift.Declared {
+		declared[pkg] = true
+	}
+	var adhoc []string
+	for _, pkg := range drift.Added {
+		if !declared[pkg] {
+			adhoc = append(adhoc, pkg)
+		}
+	}
+
+	if len(adhoc) > 0 {
+		color.Printf("\n{bold}Packages installed since the image{reset} {dim}(lost on rebuild){reset}\n")
+		for _, pkg := range adhoc {
+			color.Printf("  {green}+{reset} %s\n", pkg)
+		}
+	}
+	if len(drift.Declared) > 0 {
+		color.Printf("\n{bold}Packages from %s{reset} {dim}(installed again on rebuild){reset}\n", config.RepoConfigFile)
+		for _, pkg := range drift.Declared {
+			color.Printf("  {green}+{reset} %s\n", pkg)
+		}
+	}
+	if len(drift.Removed) > 0 {
+		color.Printf("\n{bold}Packages removed from the image{reset} {dim}(back after rebuild){reset}\n")
+		for _, pkg := range drift.Removed {
+			color.Printf("  {red}-{reset} %s\n", pkg)
+		}
+	}
+	if len(drift.Files) > 0 {
+		color.Printf("\n{bold}Changed files{reset} {dim}(lost on rebuild){reset}\n")
+		for _, change := range drift.Files {
+			switch change.Kind {
+			case "A":
+				color.Printf("  {green}A{reset} %s\n", change.Path)
+			case "D":
+				color.Printf("  {red}D{reset} %s\n", change.Path)
+			default:
+				color.Printf("  {yellow}%s{reset} %s\n", change.Kind, change.Path)
+			}
+		}
+	}
+
+	if drift.Empty() {
+		color.Printf("{green}✓{reset} No drift from %s; a rebuild loses nothing\n", drift.Image)
+		return nil
+	}
+	if len(adhoc) > 0 {
+		color.Printf("\n{dim}Declare packages under 'packages' in %s to keep them: %s{reset}\n", config.RepoConfigFile, strings.Join(adhoc, " "))
+	}
+	return nil
+}
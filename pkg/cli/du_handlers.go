@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"l8s/pkg/color"
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+
+	"github.com/juju/ansiterm"
+	"github.com/spf13/cobra"
+)
+
+// runDu summarizes the storage l8s uses on the remote host
+func (f *CommandFactory) runDu(cmd *cobra.Command, args []string) error {
+	report, err := f.ContainerMgr.DiskUsage(context.Background())
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+
+	var total int64
+	w := ansiterm.NewTabWriter(out, 0, 0, 3, ' ', 0)
+	duHeader(w, "IMAGE", "SIZE", "OWN", "CONTAINERS")
+	for _, image := range report.Images {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", image.Name, formatBytes(image.Size), formatBytes(image.UniqueSize), image.Containers)
+		total += image.UniqueSize
+	}
+	if len(report.Images) == 0 {
+		fmt.Fprintf(w, "%s\t-\t-\t-\n", f.Config.BaseImage)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	w = ansiterm.NewTabWriter(out, 0, 0, 3, ' ', 0)
+	duHeader(w, "CONTAINER", "STATUS", "LAYER", "HOME", "WORKSPACE", "TOTAL")
+	for _, c := range report.Containers {
+		sizes := map[string]string{"-home": "-", "-workspace": "-"}
+		for _, v := range c.Volumes {
+			sizes[strings.TrimPrefix(v.Name, c.Name)] = formatBytes(v.Size)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", c.Name, c.Status, formatBytes(c.Layer), sizes["-home"], sizes["-workspace"], formatBytes(c.Total()))
+		total += c.Total()
+	}
+	for _, v := range report.Shared {
+		fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t%s\n", v.Name, "shared", formatBytes(v.Size))
+		total += v.Size
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	reclaimable := report.Reclaimable()
+	if reclaimable > 0 || len(report.Dangling)+len(report.Unused)+len(report.Trash) > 0 {
+		color.Printf("\n{bold}Reclaimable{reset}\n")
+		if n := len(report.Dangling); n > 0 {
+			fmt.Printf("  %d dangling image(s): %s\n", n, formatBytes(sumImages(report.Dangling)))
+		}
+		if n := len(report.Unused); n > 0 {
+			fmt.Printf("  %d unused volume(s): %s\n", n, formatBytes(sumVolumes(report.Unused)))
+		}
+		if n := len(report.Trash); n > 0 {
+			fmt.Printf("  %d trashed volume(s): %s, purged %d days after removal\n",
+				n, formatBytes(sumVolumes(report.Trash)), int(f.trashRetention().Hours()/24))
+		}
+	}
+	total += reclaimable
+
+	color.Printf("\n{bold}Total:{reset} %s", formatBytes(total))
+	if reclaimable > 0 {
+		color.Printf(", %s reclaimable", formatBytes(reclaimable))
+	}
+	fmt.Println()
+
+	if prune, _ := cmd.Flags().GetBool("prune"); prune {
+		f.printPruneCommands(report)
+	} else if reclaimable > 0 {
+		color.Printf("{dim}Run 'l8s du --prune' for the commands that free it{reset}\n")
+	}
+	return nil
+}
+
+// printPruneCommands suggests how to free the reclaimable space
+func (f *CommandFactory) printPruneCommands(report *container.DiskReport) {
+	commands := f.pruneCommands(report)
+	if len(commands) == 0 {
+		color.Printf("{green}✓{reset} Nothing to prune\n")
+		return
+	}
+	color.Printf("\n{bold}To free space:{reset}\n")
+	for _, command := range commands {
+		fmt.Printf("  %s\n", command)
+	}
+}
+
+// pruneCommands returns the commands that free the reclaimable space. l8s
+// does not run them: unused volumes may hold work someone still wants.
+func (f *CommandFactory) pruneCommands(report *container.DiskReport) []string {
+	remote := "podman"
+	if conn, err := f.Config.GetActiveConnection(); err == nil && f.Config.Runtime != config.RuntimeFake {
+		remote = fmt.Sprintf("ssh %s@%s sudo podman", f.Config.RemoteUser, conn.Address)
+	}
+
+	var commands []string
+	if len(report.Dangling) > 0 {
+		commands = append(commands, remote+" image prune -f")
+	}
+	if len(report.Unused) > 0 {
+		names := make([]string, len(report.Unused))
+		for i, v := range report.Unused {
+			names[i] = v.Name
+		}
+		commands = append(commands, remote+" volume rm "+strings.Join(names, " "))
+	}
+	if len(report.Trash) > 0 {
+		commands = append(commands, "l8s undo-remove   # review the trash; lower trash_retention_days to purge sooner")
+	}
+	return commands
+}
+
+// duHeader writes a table header, bold unless NO_COLOR is set
+func duHeader(w *ansiterm.TabWriter, headers ...string) {
+	if os.Getenv("NO_COLOR") == "" {
+		for i, h := range headers {
+			headers[i] = color.Bold("%s", h)
+		}
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+}
+
+// sumImages adds up the space deleting images frees
+func sumImages(images []container.ImageUsage) int64 {
+	var total int64
+	for _, image := range images {
+		total += image.UniqueSize
+	}
+	return total
+}
+
+// sumVolumes adds up the size of volumes
+func sumVolumes(volumes []container.VolumeUsage) int64 {
+	var total int64
+	for _, v := range volumes {
+		total += v.Size
+	}
+	return total
+}
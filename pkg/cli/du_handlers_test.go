@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunDu(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	mgr := new(MockContainerManagerWithGit)
+	mgr.On("DiskUsage", mock.Anything).Return(&container.DiskReport{
+		Images: []container.ImageUsage{{Name: "localhost/l8s-fedora:latest", Size: 2 << 30, UniqueSize: 2 << 30, Containers: 1}},
+		Containers: []container.ContainerDisk{{
+			Name: "dev-myproject", Status: "running", Layer: 1 << 20,
+			Volumes: []container.VolumeUsage{{Name: "dev-myproject-home", Size: 3 << 20}},
+		}},
+		Unused: []container.VolumeUsage{{Name: "dev-gone-home", Size: 1 << 30}},
+	}, nil)
+	f := &CommandFactory{
+		Config: &config.Config{
+			ContainerPrefix:  "dev",
+			RemoteUser:       "podman",
+			ActiveConnection: "home",
+			Connections:      map[string]config.ConnectionConfig{"home": {Address: "10.0.0.1"}},
+		},
+		ContainerMgr: mgr,
+	}
+
+	cmd := &cobra.Command{Use: "du"}
+	cmd.Flags().Bool("prune", false, "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	require.NoError(t, f.runDu(cmd, nil))
+	assert.Regexp(t, `localhost/l8s-fedora:latest\s+2.0 GiB\s+2.0 GiB\s+1`, out.String())
+	assert.Regexp(t, `dev-myproject\s+running\s+1.0 MiB\s+3.0 MiB\s+-\s+4.0 MiB`, out.String())
+
+	assert.Empty(t, f.pruneCommands(&container.DiskReport{}))
+	assert.Equal(t, []string{
+		"ssh podman@10.0.0.1 sudo podman image prune -f",
+		"ssh podman@10.0.0.1 sudo podman volume rm dev-gone-home dev-old-workspace",
+	}, f.pruneCommands(&container.DiskReport{
+		Dangling: []container.ImageUsage{{ID: "abc"}},
+		Unused:   []container.VolumeUsage{{Name: "dev-gone-home"}, {Name: "dev-old-workspace"}},
+	}))
+}
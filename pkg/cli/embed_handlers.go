@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"l8s/pkg/color"
+	"l8s/pkg/embed"
+
+	"github.com/juju/ansiterm"
+	"github.com/spf13/cobra"
+)
+
+// selectAssets returns the assets at or under any of paths, or all of them
+// for none; a path that matches nothing is an error
+func selectAssets(assets []embed.Asset, paths []string) ([]embed.Asset, error) {
+	if len(paths) == 0 {
+		return assets, nil
+	}
+	var selected []embed.Asset
+	for _, p := range paths {
+		p = strings.Trim(filepath.ToSlash(p), "/")
+		found := false
+		for _, a := range assets {
+			if a.Path == p || strings.HasPrefix(a.Path, p+"/") {
+				selected = append(selected, a)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no embedded file or directory '%s'; see 'l8s embed list'", p)
+		}
+	}
+	return selected, nil
+}
+
+// printAssetSignature reports whether the embedded assets match the release signature
+func printAssetSignature() {
+	switch err := embed.Verify(); {
+	case errors.Is(err, embed.ErrUnsigned):
+		color.Printf("{dim}Development build: embedded files are not signed{reset}\n")
+	case err != nil:
+		color.Printf("{yellow}!{reset} %v\n", err)
+	default:
+		color.Printf("{green}✓{reset} Embedded files match the release signature\n")
+	}
+}
+
+// runEmbedList lists the files built into l8s, or prints their checksums
+func (f *CommandFactory) runEmbedList(cmd *cobra.Command, args []string) error {
+	assets, err := embed.Assets()
+	if err != nil {
+		return err
+	}
+	if assets, err = selectAssets(assets, args); err != nil {
+		return err
+	}
+
+	if checksums, _ := cmd.Flags().GetBool("checksums"); checksums {
+		// Nothing else on stdout, so the output works with sha256sum -c
+		_, err := cmd.OutOrStdout().Write(embed.Manifest(assets))
+		return err
+	}
+
+	printAssetSignature()
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	if os.Getenv("NO_COLOR") == "" {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", color.Bold("PATH"), color.Bold("SIZE"), color.Bold("SHA256"))
+	} else {
+		fmt.Fprintln(w, "PATH\tSIZE\tSHA256")
+	}
+	for _, a := range assets {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", a.Path, formatBytes(int64(len(a.Data))), a.Sum()[:12])
+	}
+	return w.Flush()
+}
+
+// runEmbedExtract writes the files built into l8s to a directory
+func (f *CommandFactory) runEmbedExtract(cmd *cobra.Command, args []string) error {
+	dest := args[0]
+	assets, err := embed.Assets()
+	if err != nil {
+		return err
+	}
+	if assets, err = selectAssets(assets, args[1:]); err != nil {
+		return err
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	if !force {
+		for _, a := range assets {
+			target := filepath.Join(dest, filepath.FromSlash(a.Path))
+			if _, err := os.Lstat(target); err == nil {
+				return fmt.Errorf("%s already exists; use --force to overwrite", target)
+			}
+		}
+	}
+
+	printAssetSignature()
+	for _, a := range assets {
+		target := filepath.Join(dest, filepath.FromSlash(a.Path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		mode := os.FileMode(0644)
+		if a.Executable() {
+			mode = 0755
+		}
+		if err := os.WriteFile(target, a.Data, mode); err != nil {
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+	}
+	color.Printf("{green}✓{reset} Extracted %d files to {bold}%s{reset}\n", len(assets), dest)
+	color.Printf("{dim}Check them with: %s | (cd %s && sha256sum -c){reset}\n",
+		strings.Join(append([]string{"l8s embed list --checksums"}, args[1:]...), " "), dest)
+	return nil
+}
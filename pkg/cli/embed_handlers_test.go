@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"l8s/pkg/embed"
+)
+
+func TestSelectAssets(t *testing.T) {
+	assets := []embed.Asset{
+		{Path: "containers/Containerfile"},
+		{Path: "dotfiles/.config/nvim/init.lua"},
+		{Path: "dotfiles/.zshrc"},
+	}
+
+	all, err := selectAssets(assets, nil)
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	selected, err := selectAssets(assets, []string{"dotfiles/.config/", "containers/Containerfile"})
+	require.NoError(t, err)
+	assert.Equal(t, []embed.Asset{{Path: "dotfiles/.config/nvim/init.lua"}, {Path: "containers/Containerfile"}}, selected)
+
+	// A prefix only matches whole path elements
+	_, err = selectAssets(assets, []string{"dotfiles/.z"})
+	assert.ErrorContains(t, err, "no embedded file or directory 'dotfiles/.z'")
+}
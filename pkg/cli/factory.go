@@ -2,6 +2,8 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"l8s/pkg/config"
 	"l8s/pkg/container"
@@ -25,9 +27,9 @@ func NewCommandFactory() (*CommandFactory, error) {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	podmanClient, err := container.NewPodmanClient()
+	podmanClient, err := newPodmanClient(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create podman client: %w", err)
+		return nil, err
 	}
 
 	// Get the remote host from active connection
@@ -35,11 +37,57 @@ func NewCommandFactory() (*CommandFactory, error) {
 	if activeAddr, err := cfg.GetActiveAddress(); err == nil {
 		remoteHost = activeAddr
 	}
-	
-	containerConfig := container.Config{
-		SSHPortStart:     cfg.SSHPortStart,
+
+	return &CommandFactory{
+		Config:       cfg,
+		ContainerMgr: container.NewManager(podmanClient, newContainerConfig(cfg, remoteHost)),
+		GitClient:    &gitClientAdapter{},
+		SSHClient:    &sshClientAdapter{},
+	}, nil
+}
+
+// newPodmanClient connects to the configured runtime on the active connection
+func newPodmanClient(cfg *config.Config) (container.PodmanClient, error) {
+	if cfg.Runtime == config.RuntimeFake {
+		// Each connection simulates a separate host
+		return container.NewFakePodmanClient(filepath.Join(config.StateDir(), "fake-runtime", cfg.ActiveConnection+".json")), nil
+	}
+	client, err := container.NewPodmanClientFor(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create podman client: %w", err)
+	}
+	return client, nil
+}
+
+// newConnectionManager returns a container manager for the named connection
+// rather than the active one
+func newConnectionManager(cfg *config.Config, name string) (ContainerManager, error) {
+	connCfg := *cfg
+	connCfg.ActiveConnection = name
+	address, err := connCfg.GetActiveAddress()
+	if err != nil {
+		return nil, err
+	}
+	client, err := newPodmanClient(&connCfg)
+	if err != nil {
+		return nil, err
+	}
+	return container.NewManager(client, newContainerConfig(&connCfg, address)), nil
+}
+
+// newContainerConfig maps the l8s configuration to container manager
+// settings for the given remote host. Both factories use it, so settings
+// added here reach every command.
+func newContainerConfig(cfg *config.Config, remoteHost string) container.Config {
+	// Sizes were checked by config validation
+	memoryLimit, _ := config.ParseSize(cfg.ContainerMemory)
+
+	return container.Config{
+		SSHPortStart:     cfg.FirstSSHPort(),
+		SSHPortRanges:    containerPortRanges(cfg.SSHRanges()),
 		WebPortStart:     cfg.WebPortStart,
 		BaseImage:        cfg.BaseImage,
+		BaseFlavor:       cfg.BaseFlavor,
 		ContainerPrefix:  cfg.ContainerPrefix,
 		ContainerUser:    cfg.ContainerUser,
 		DotfilesPath:     cfg.DotfilesPath,
@@ -47,15 +95,25 @@ func NewCommandFactory() (*CommandFactory, error) {
 		CAPublicKeyPath:  cfg.CAPublicKeyPath,
 		KnownHostsPath:   cfg.KnownHostsPath,
 		RemoteHost:       remoteHost,
+		ProxyJump:        cfg.ActiveProxyJump(),
 		GitHubToken:      cfg.GitHubToken,
+		Memory:           memoryLimit,
+		CPUs:             cfg.ContainerCPUs,
+		Mounts:           containerMounts(cfg.Mounts),
+		Runtime:          containerRuntime(cfg),
+		Quota:            containerQuota(cfg),
+		SSHGuard: container.SSHGuard{
+			MaxAuthTries:   cfg.SSHGuard.MaxAuthTries,
+			LoginGraceTime: cfg.SSHGuard.LoginGraceTime,
+			MaxStartups:    cfg.SSHGuard.MaxStartups,
+		},
+		ExecEnv:         config.ExecEnvNames(cfg.ExecEnv),
+		PushGuard:       containerPushGuard(cfg.PushGuard),
+		Proxy:           containerProxy(cfg.Proxy),
+		ImageVariants:   containerImageVariants(cfg),
+		PersistPackages: cfg.PersistPackages,
+		Simulated:       cfg.Runtime == config.RuntimeFake,
 	}
-
-	return &CommandFactory{
-		Config:       cfg,
-		ContainerMgr: container.NewManager(podmanClient, containerConfig),
-		GitClient:    &gitClientAdapter{},
-		SSHClient:    &sshClientAdapter{},
-	}, nil
 }
 
 // NewTestCommandFactory creates a factory with mock dependencies
@@ -254,10 +312,38 @@ func (g *gitClientAdapter) PushBranch(repoPath, branch, remoteName string, force
 	return git.PushBranch(repoPath, branch, remoteName, force)
 }
 
+func (g *gitClientAdapter) PushShallow(repoPath, branch, remoteName string, depth int) error {
+	return git.PushShallow(repoPath, branch, remoteName, depth)
+}
+
 func (g *gitClientAdapter) InitRepository(repoPath string, allowPush bool, defaultBranch string) error {
 	return git.InitRepository(repoPath, allowPush, defaultBranch)
 }
 
+func (g *gitClientAdapter) HasCommit(repoPath, sha string) bool {
+	return git.HasCommit(repoPath, sha)
+}
+
+func (g *gitClientAdapter) ResolveRef(repoPath, ref string) (string, error) {
+	return git.ResolveRef(repoPath, ref)
+}
+
+func (g *gitClientAdapter) PushRef(repoPath, ref, remoteName, branch string) error {
+	return git.PushRef(repoPath, ref, remoteName, branch)
+}
+
+func (g *gitClientAdapter) CreateBranch(repoPath, branch, startPoint string) error {
+	return git.CreateBranch(repoPath, branch, startPoint)
+}
+
+func (g *gitClientAdapter) ListWorktrees(repoPath string) ([]git.Worktree, error) {
+	return git.ListWorktrees(repoPath)
+}
+
+func (g *gitClientAdapter) AheadBehind(repoPath, a, b string) (int, int, error) {
+	return git.AheadBehind(repoPath, a, b)
+}
+
 // sshClientAdapter adapts the ssh package functions to the SSHClient interface
 type sshClientAdapter struct{}
 
@@ -287,4 +373,64 @@ func (s *sshClientAdapter) IsPortAvailable(port int) bool {
 
 func (s *sshClientAdapter) ValidatePublicKey(key string) error {
 	return ssh.ValidatePublicKey(key)
-}
\ No newline at end of file
+}
+
+// containerQuota converts the active connection's quota for the container manager
+func containerQuota(cfg *config.Config) container.Quota {
+	q := cfg.ActiveQuota()
+	maxMemory, _ := config.ParseSize(q.MaxMemory)
+	return container.Quota{
+		MaxContainers: q.MaxContainers,
+		MaxMemory:     maxMemory,
+		MaxCPUs:       q.MaxCPUs,
+		PortBudget:    q.PortBudget,
+	}
+}
+
+// containerPortRanges converts port ranges to first and last port pairs
+func containerPortRanges(ranges []config.PortRange) [][2]int {
+	converted := make([][2]int, len(ranges))
+	for i, r := range ranges {
+		converted[i] = [2]int{r.First, r.Last}
+	}
+	return converted
+}
+
+// containerPushGuard maps push_guard to the hook settings; origin is blocked
+// unless allowed
+func containerPushGuard(pg config.PushGuardConfig) container.PushGuard {
+	guard := container.PushGuard{Disabled: pg.Disabled, Protected: pg.ProtectedBranches()}
+	if !pg.AllowOrigin {
+		guard.BlockedRemotes = []string{"origin"}
+	}
+	return guard
+}
+
+// containerProxy resolves the proxy for new containers against this shell's environment
+func containerProxy(pc config.ProxyConfig) container.Proxy {
+	resolved := pc.Resolve(os.Getenv)
+	return container.Proxy{HTTP: resolved.HTTP, HTTPS: resolved.HTTPS, NoProxy: resolved.NoProxy}
+}
+
+// containerImageVariants converts configured image variants for the container manager
+func containerImageVariants(cfg *config.Config) map[string]container.ImageVariant {
+	variants := make(map[string]container.ImageVariant, len(cfg.ImageVariants))
+	for name, v := range cfg.ImageVariants {
+		variants[name] = container.ImageVariant{Image: cfg.VariantImage(name), Containerfile: v.Containerfile}
+	}
+	return variants
+}
+
+// containerPackages converts packages declared in .l8s.yaml for the container manager
+func containerPackages(pc config.PackagesConfig) container.Packages {
+	return container.Packages{Dnf: pc.Dnf, Apt: pc.Apt, Pip: pc.Pip, Npm: pc.Npm, Cargo: pc.Cargo}
+}
+
+// containerMounts converts configured mounts for the container manager
+func containerMounts(mounts []config.MountConfig) []container.Mount {
+	converted := make([]container.Mount, len(mounts))
+	for i, m := range mounts {
+		converted[i] = container.Mount{Source: m.Source, Target: m.Target, ReadOnly: m.ReadOnly}
+	}
+	return converted
+}
@@ -2,10 +2,14 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"l8s/pkg/config"
 	"l8s/pkg/container"
+	"l8s/pkg/embed"
 	"github.com/spf13/cobra"
 )
 
@@ -36,21 +40,34 @@ func (f *LazyCommandFactory) defaultInitializer() error {
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w\n\nRun 'l8s init' to configure l8s for your remote server", err)
 	}
+	// Simulated hosts have nothing to probe
+	if cfg.Runtime != config.RuntimeFake {
+		if err := autoSelectConnection(cfg); err != nil {
+			return err
+		}
+	}
 	
-	// Validate that SSH configs match the active connection
+	// Validate that SSH configs match the active connection. A one-off
+	// --connection (L8S_CONNECTION, auto_connect) override deliberately leaves SSH
+	// entries pointing at the configured connection, so skip the check.
 	address, err := cfg.GetActiveAddress()
 	if err != nil {
 		return fmt.Errorf("failed to get active connection: %w", err)
 	}
 	
-	if err := ValidateSSHConfigsMatchConnection(address); err != nil {
+	if cfg.Overridden("active_connection") {
+		// stderr keeps machine-readable output (list -q, inspect) clean
+		fmt.Fprintf(os.Stderr, "Using connection '%s' (%s) for this command\n", cfg.ActiveConnection, address)
+	} else if cfg.Runtime == config.RuntimeFake {
+		// Simulated containers have no SSH entries to check
+	} else if err := ValidateSSHConfigsMatchConnection(address); err != nil {
 		return fmt.Errorf("SSH configs don't match active connection '%s': %w\n\nRun 'l8s connection switch %s' to fix this",
 			cfg.ActiveConnection, err, cfg.ActiveConnection)
 	}
 
-	podmanClient, err := container.NewPodmanClient()
+	podmanClient, err := newPodmanClient(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create podman client: %w", err)
+		return err
 	}
 
 	// Get the remote host from active connection
@@ -59,22 +76,8 @@ func (f *LazyCommandFactory) defaultInitializer() error {
 		remoteHost = activeAddr
 	}
 	
-	containerConfig := container.Config{
-		SSHPortStart:     cfg.SSHPortStart,
-		WebPortStart:     cfg.WebPortStart,
-		BaseImage:        cfg.BaseImage,
-		ContainerPrefix:  cfg.ContainerPrefix,
-		ContainerUser:    cfg.ContainerUser,
-		DotfilesPath:     cfg.DotfilesPath,
-		CAPrivateKeyPath: cfg.CAPrivateKeyPath,
-		CAPublicKeyPath:  cfg.CAPublicKeyPath,
-		KnownHostsPath:   cfg.KnownHostsPath,
-		RemoteHost:       remoteHost,
-		GitHubToken:      cfg.GitHubToken,
-	}
-
 	f.Config = cfg
-	f.ContainerMgr = container.NewManager(podmanClient, containerConfig)
+	f.ContainerMgr = container.NewManager(podmanClient, newContainerConfig(cfg, remoteHost))
 	f.GitClient = &gitClientAdapter{}
 	f.SSHClient = &sshClientAdapter{}
 
@@ -91,9 +94,23 @@ func (f *LazyCommandFactory) ensureInitialized() error {
 	return f.initError
 }
 
+// addRuntimeFlags adds create flags that override the configured runtime options
+func addRuntimeFlags(cmd *cobra.Command) {
+	cmd.Flags().String("shm-size", "", "Size of /dev/shm, e.g. 2g (browsers and test runners need more than 64m)")
+	cmd.Flags().StringArray("tmpfs", nil, "Mount a tmpfs at path[:size], e.g. /tmp:4g; repeatable")
+	cmd.Flags().StringArray("ulimit", nil, "Set a resource limit as name=soft[:hard], e.g. nofile=1048576; repeatable")
+	cmd.Flags().StringArray("sysctl", nil, "Set a namespaced kernel parameter as key=value; repeatable")
+	cmd.Flags().StringArrayP("publish", "p", nil, "Publish container ports as [host:]container[/udp], ranges allowed, e.g. 60000-60010:60000-60010/udp; repeatable")
+	cmd.Flags().Bool("ptrace", false, "Allow debuggers such as gdb and strace (adds SYS_PTRACE)")
+	cmd.Flags().StringArray("cap-add", nil, "Add a Linux capability, e.g. PERFMON for perf; repeatable")
+	cmd.Flags().Bool("seccomp-unconfined", false, "Run without the default seccomp filter")
+	cmd.Flags().StringArray("selinux-opt", nil, "Set an SELinux label option, e.g. disable or type:spc_t; repeatable")
+	cmd.Flags().Bool("nested-containers", false, "Allow podman and buildah to run inside the container")
+	cmd.Flags().Bool("systemd", false, "Run systemd as PID 1 so services can be managed with systemctl")
+}
+
 // CreateCmd returns the create command with lazy initialization
 func (f *LazyCommandFactory) CreateCmd() *cobra.Command {
-	var dotfilesPath string
 	var branch string
 	
 	cmd := &cobra.Command{
@@ -104,7 +121,52 @@ func (f *LazyCommandFactory) CreateCmd() *cobra.Command {
 
 The container name is automatically generated from the repository name and worktree path.
 The container will be initialized with an empty git repository configured to receive pushes.
-The current branch (or specified branch) will be pushed to populate the container.
+The current branch (or specified branch) will be pushed to populate the container once
+its SSH server is ready, retrying with backoff. Use --skip-push to leave it empty, or
+--shallow[=N] to push only recent history for large repositories.
+
+With --ticket JIRA-123 the container is named <repo>-jira-123 instead and the
+branch comes from the ticket.branch template (default: the ticket ID), created
+at HEAD if it does not exist yet. The ticket is recorded on the container and
+shown by list --wide and info, and this worktree keeps using the ticket's
+container until it is removed.
+
+With --subdir services/api the container's working tree is a sparse checkout of
+that monorepo directory and shells and commands start in it. Every pushed commit
+still carries the full tree, so combine it with --shallow to keep the transfer
+small.
+
+With --sparse docs --sparse libs/common (repeatable) only those directories and
+the files at the top of the repository are checked out, while the full history
+is pushed. Change the selection later with 'l8s sparse set'.
+
+With --origin (or origin.mirror in the config) the container's repository gets
+an origin remote pointing at this repository's origin, so tools inside can fetch
+upstream branches. SSH remotes authenticate with your SSH agent, which 'l8s ssh'
+forwards; HTTPS remotes use github_token.
+
+The container's git user.name and user.email are copied from the host. A
+repository can override them in its .l8s.yaml, e.g. to commit with an open
+source identity:
+
+  git:
+    name: Jane Doe
+    email: jane@oss.example.org
+
+With signing.mode set in the config, commits and tags made in the container are
+SSH-signed and a signed test commit is made to check it works.
+
+Runtime flags such as --shm-size, --ulimit or --ptrace override the matching config
+settings for this container and are kept when it is rebuilt.
+
+--publish makes more container ports reachable on the host beyond SSH and web,
+over TCP or UDP and as ranges, e.g. for mosh, WebRTC or game servers:
+
+  l8s create -p 60000-60010/udp -p 8443:443
+
+Published host ports are fixed, so clones do not get them and --blue-green
+rebuilds are refused.
+
 A git remote will be added to your local repository for easy code synchronization.`,
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -112,13 +174,6 @@ A git remote will be added to your local repository for easy code synchronizatio
 				return err
 			}
 			
-			// Set CLI dotfiles path if provided
-			if dotfilesPath != "" {
-				if cm, ok := f.ContainerMgr.(*container.Manager); ok {
-					cm.SetCLIDotfilesPath(dotfilesPath)
-				}
-			}
-			
 			// Delegate to the original factory implementation
 			origFactory := &CommandFactory{
 				Config:       f.Config,
@@ -130,20 +185,40 @@ A git remote will be added to your local repository for easy code synchronizatio
 		},
 	}
 	
-	cmd.Flags().StringVar(&dotfilesPath, "dotfiles-path", "", "Path to dotfiles directory to copy to the container")
+	cmd.Flags().String("dotfiles-path", "", "Path to dotfiles directory to copy to the container")
 	cmd.Flags().StringVar(&branch, "branch", "", "Git branch to push to the container (defaults to current branch)")
+	cmd.Flags().Bool("skip-push", false, "Create the container with an empty repository instead of pushing a branch")
+	cmd.Flags().Int("shallow", 0, "Push only the last N commits (--shallow alone pushes 1) and mirror origin, which 'l8s fetch --unshallow' deepens the history from")
+	cmd.Flags().Lookup("shallow").NoOptDefVal = "1"
+	cmd.Flags().String("ticket", "", "Name the container and branch after a ticket ID, e.g. JIRA-123")
+	cmd.Flags().String("subdir", "", "Check out only this monorepo subdirectory and start sessions in it")
+	cmd.Flags().StringArray("sparse", nil, "Check out only this directory (repeatable); history is still pushed in full")
+	cmd.Flags().Bool("origin", false, "Add this repository's origin remote to the container (default from origin.mirror)")
+	cmd.Flags().Bool("no-origin", false, "Do not add an origin remote even if origin.mirror is set")
+	cmd.Flags().String("image-variant", "", "Create from an image variant in image_variants instead of the base image")
+	addRuntimeFlags(cmd)
 	
 	return cmd
 }
 
 // SSHCmd returns the ssh command with lazy initialization
 func (f *LazyCommandFactory) SSHCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "ssh",
 		Short:   "SSH into the container for the current worktree",
 		GroupID: "working",
-		Args:    cobra.NoArgs,
+		Long: `Open a login shell in /workspace/project of the container for the current
+worktree. Anything after -- is run instead of the shell, and -L/-R/-D set up
+port forwards exactly as they would with ssh.`,
+		Example: `  l8s ssh
+  l8s ssh -- make test
+  l8s ssh -L 8080:localhost:8080
+  l8s ssh -D 1080`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// Everything after -- is the remote command
+			if cmd.ArgsLenAtDash() != 0 && len(args) > 0 {
+				return fmt.Errorf("unexpected argument %q; put the remote command after --", args[0])
+			}
 			if err := f.ensureInitialized(); err != nil {
 				return err
 			}
@@ -156,16 +231,46 @@ func (f *LazyCommandFactory) SSHCmd() *cobra.Command {
 			return origFactory.runSSH(cmd, args)
 		},
 	}
+
+	cmd.Flags().StringArrayP("local-forward", "L", nil, "Forward a local port, e.g. 8080:localhost:8080 (repeatable)")
+	cmd.Flags().StringArrayP("remote-forward", "R", nil, "Forward a remote port back to this machine (repeatable)")
+	cmd.Flags().StringArrayP("dynamic-forward", "D", nil, "Open a SOCKS proxy on a local port (repeatable)")
+	cmd.Flags().BoolP("tty", "t", false, "Allocate a TTY for the remote command")
+
+	return cmd
 }
 
 // ListCmd returns the list command with lazy initialization
 func (f *LazyCommandFactory) ListCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "list",
 		Short:   "List all l8s containers",
+		Long: `List the containers on the active connection.
+
+With --all-connections every configured connection is queried at once and a
+CONNECTION column shows where each container runs. Each connection gets
+--timeout to connect and answer; connections that do not are reported after
+the containers that were listed, so one unreachable host does not hold up the
+rest.`,
+		Example: `  l8s list
+  l8s list --all-connections --timeout 5s`,
 		GroupID: "container",
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if all, _ := cmd.Flags().GetBool("all-connections"); all {
+				// Connections are dialled by the listing with a timeout each, so
+				// an unreachable active connection cannot hang initialization
+				cfg, err := config.Load(config.GetConfigPath())
+				if err != nil {
+					return fmt.Errorf("failed to load config: %w\n\nRun 'l8s init' to configure l8s for your remote server", err)
+				}
+				origFactory := &CommandFactory{
+					Config:    cfg,
+					GitClient: &gitClientAdapter{},
+					SSHClient: &sshClientAdapter{},
+				}
+				return origFactory.runList(cmd, args)
+			}
 			if err := f.ensureInitialized(); err != nil {
 				return err
 			}
@@ -178,15 +283,37 @@ func (f *LazyCommandFactory) ListCmd() *cobra.Command {
 			return origFactory.runList(cmd, args)
 		},
 	}
+
+	cmd.Flags().StringArray("filter", nil, "Filter containers, e.g. status=running or label=ticket=ABC-123 (repeatable)")
+	cmd.Flags().String("sort", "", "Sort by name, created or port")
+	cmd.Flags().BoolP("wide", "w", false, "Show image, connection, owner, health, ticket and note columns")
+	cmd.Flags().Bool("mine", false, "Only show containers created by you")
+	cmd.Flags().String("owner", "", "Only show containers created by the given user")
+	cmd.Flags().BoolP("quiet", "q", false, "Only print container names")
+	cmd.Flags().Bool("all-connections", false, "List containers on every configured connection")
+	cmd.Flags().Duration("timeout", defaultConnectionTimeout, "How long to wait for each connection with --all-connections")
+
+	return cmd
+}
+
+// addBulkFlags adds the flags shared by commands that act on many containers
+func addBulkFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("all", false, "Apply to every container")
+	cmd.Flags().StringArray("filter", nil, "Apply to containers matching key=value (status, owner, label, group, ticket); repeatable")
+	cmd.Flags().Int("parallel", defaultParallel, "Containers to work on at once")
 }
 
 // StartCmd returns the start command with lazy initialization
 func (f *LazyCommandFactory) StartCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "start <name>",
-		Short:   "Start a stopped container",
+	cmd := &cobra.Command{
+		Use:   "start [name...]",
+		Short: "Start stopped containers",
+		Long: `Start one or more stopped containers. With --all or --filter every matching
+stopped container is started, several at a time, followed by a summary.`,
+		Example: `  l8s start myproject
+  l8s start api web worker
+  l8s start --filter label=team=web`,
 		GroupID: "container",
-		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := f.ensureInitialized(); err != nil {
 				return err
@@ -200,15 +327,21 @@ func (f *LazyCommandFactory) StartCmd() *cobra.Command {
 			return origFactory.runStart(cmd, args)
 		},
 	}
+	addBulkFlags(cmd)
+	return cmd
 }
 
 // StopCmd returns the stop command with lazy initialization
 func (f *LazyCommandFactory) StopCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "stop <name>",
-		Short:   "Stop a running container",
+	cmd := &cobra.Command{
+		Use:   "stop [name...]",
+		Short: "Stop running containers",
+		Long: `Stop one or more running containers. With --all or --filter every matching
+running container is stopped, several at a time, followed by a summary.`,
+		Example: `  l8s stop myproject
+  l8s stop --all
+  l8s stop --filter owner=alice`,
 		GroupID: "container",
-		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := f.ensureInitialized(); err != nil {
 				return err
@@ -222,15 +355,35 @@ func (f *LazyCommandFactory) StopCmd() *cobra.Command {
 			return origFactory.runStop(cmd, args)
 		},
 	}
+	addBulkFlags(cmd)
+	return cmd
 }
 
 // RemoveCmd returns the remove command with lazy initialization
 func (f *LazyCommandFactory) RemoveCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "remove",
-		Short:   "Remove the container for the current worktree",
+		Use:   "remove [name...]",
+		Short: "Remove the container for the current worktree",
+		Long: `Remove the container for the current worktree.
+
+Given container names, --all or --filter, removes each matching container
+instead after a single confirmation. Protected containers, containers owned by
+someone else and containers with unsaved work are skipped and reported unless
+the corresponding --force flag is given. A container whose work cannot be
+checked, for example because it is stopped, counts as having unsaved work.
+
+Commits found in a container are looked for in the repository it was created
+from. When that repository is gone, --skip-unsaved-check removes the container
+without the check while still asking for confirmation and checking its owner.
+
+--name removes a single container without needing its worktree, so containers
+left behind by deleted worktrees can be cleaned up from anywhere. Combine it
+with --connection to reach a container on another host.`,
+		Example: `  l8s remove
+  l8s remove old-spike hack-week-demo
+  l8s remove --name myrepo-a3f2d1 --connection cloud
+  l8s remove --filter label=event=hackweek --trash`,
 		GroupID: "repo-maintenance",
-		Args:    cobra.NoArgs,
 		Aliases: []string{"rm"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := f.ensureInitialized(); err != nil {
@@ -246,8 +399,13 @@ func (f *LazyCommandFactory) RemoveCmd() *cobra.Command {
 		},
 	}
 	
-	cmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+	cmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt and remove even with unpushed work or another owner")
 	cmd.Flags().Bool("keep-volumes", false, "Keep volumes when removing container")
+	cmd.Flags().Bool("trash", false, "Move volumes to trash so the container can be restored with 'l8s undo-remove'")
+	cmd.Flags().Bool("force-protected", false, "Remove the container even if it is protected")
+	cmd.Flags().Bool("skip-unsaved-check", false, "Remove without checking the container for unsaved work")
+	cmd.Flags().String("name", "", "Remove this container instead of the current worktree's; works outside a git repository")
+	addBulkFlags(cmd)
 	
 	return cmd
 }
@@ -274,13 +432,21 @@ func (f *LazyCommandFactory) InfoCmd() *cobra.Command {
 	}
 }
 
-// BuildCmd returns the build command with lazy initialization
-func (f *LazyCommandFactory) BuildCmd() *cobra.Command {
+// DriftCmd returns the drift command with lazy initialization
+func (f *LazyCommandFactory) DriftCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "build",
-		Short:   "Build or rebuild the base container image",
+		Use:   "drift <name>",
+		Short: "Show what a rebuild would lose from a container",
+		Long: `Compare a running container with the image a rebuild would create it from:
+packages installed or removed since it was created, and changed files under
+/etc, /usr/local, /opt and /root. The home directory and /workspace are volumes
+and survive rebuilds, so they are not compared.
+
+Packages declared in the repository's .l8s.yaml are installed again on
+rebuild and are listed separately.`,
+		Example: `  l8s drift myrepo-a3f2d1`,
 		GroupID: "container",
-		Args:    cobra.NoArgs,
+		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := f.ensureInitialized(); err != nil {
 				return err
@@ -291,66 +457,27 @@ func (f *LazyCommandFactory) BuildCmd() *cobra.Command {
 				GitClient:    f.GitClient,
 				SSHClient:    f.SSHClient,
 			}
-			return origFactory.runBuild(cmd, args)
+			return origFactory.runDrift(cmd, args)
 		},
 	}
 }
 
-// RemoteCmd returns the remote command with subcommands and lazy initialization
-func (f *LazyCommandFactory) RemoteCmd() *cobra.Command {
+// DuCmd returns the du command with lazy initialization
+func (f *LazyCommandFactory) DuCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "remote",
-		Short:   "Manage git remotes for containers",
-		GroupID: "container",
-	}
-
-	cmd.AddCommand(
-		&cobra.Command{
-			Use:   "add <name>",
-			Short: "Add git remote for existing container",
-			Args:  cobra.ExactArgs(1),
-			RunE: func(cmd *cobra.Command, args []string) error {
-				if err := f.ensureInitialized(); err != nil {
-					return err
-				}
-				origFactory := &CommandFactory{
-					Config:       f.Config,
-					ContainerMgr: f.ContainerMgr,
-					GitClient:    f.GitClient,
-					SSHClient:    f.SSHClient,
-				}
-				return origFactory.runRemoteAdd(cmd, args)
-			},
-		},
-		&cobra.Command{
-			Use:   "remove <name>",
-			Short: "Remove git remote for container",
-			Args:  cobra.ExactArgs(1),
-			RunE: func(cmd *cobra.Command, args []string) error {
-				if err := f.ensureInitialized(); err != nil {
-					return err
-				}
-				origFactory := &CommandFactory{
-					Config:       f.Config,
-					ContainerMgr: f.ContainerMgr,
-					GitClient:    f.GitClient,
-					SSHClient:    f.SSHClient,
-				}
-				return origFactory.runRemoteRemove(cmd, args)
-			},
-		},
-	)
-
-	return cmd
-}
+		Use:   "du",
+		Short: "Show the disk space l8s uses on the remote host",
+		Long: `Summarize the storage l8s uses on the remote host: the base image and image
+variants, each container's writable layer and volumes, caches shared by
+containers, and what could be freed: dangling images left by rebuilds,
+volumes no container uses and the trash.
 
-// ExecCmd returns the exec command with lazy initialization
-func (f *LazyCommandFactory) ExecCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "exec <command> [args...]",
-		Short:   "Execute command in the container for the current worktree",
-		GroupID: "working",
-		Args:    cobra.MinimumNArgs(1),
+--prune prints the commands that free the reclaimable space. Nothing is
+deleted by l8s du itself.`,
+		Example: `  l8s du
+  l8s du --prune`,
+		GroupID: "container",
+		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := f.ensureInitialized(); err != nil {
 				return err
@@ -361,83 +488,69 @@ func (f *LazyCommandFactory) ExecCmd() *cobra.Command {
 				GitClient:    f.GitClient,
 				SSHClient:    f.SSHClient,
 			}
-			return origFactory.runExec(cmd, args)
-		},
-	}
-}
-
-// InitCmd returns the init command without lazy initialization
-func (f *LazyCommandFactory) InitCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "init",
-		Short:   "Initialize l8s configuration",
-		GroupID: "setup",
-		Long: `Initialize l8s configuration by setting up remote server connection details.
-	
-l8s ONLY supports remote container management for security isolation.
-You'll need:
-- A remote server with Podman installed
-- SSH access to the server
-- SSH key authentication configured`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			// Init doesn't need dependencies, create a minimal factory
-			origFactory := &CommandFactory{}
-			return origFactory.runInit(cmd, args)
+			return origFactory.runDu(cmd, args)
 		},
 	}
+	cmd.Flags().Bool("prune", false, "Print the commands that free reclaimable space")
+	return cmd
 }
 
-// RebuildCmd returns the rebuild command with lazy initialization
-func (f *LazyCommandFactory) RebuildCmd() *cobra.Command {
+// GcCmd returns the gc command with lazy initialization
+func (f *LazyCommandFactory) GcCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "rebuild",
-		Short:   "Rebuild the container for the current worktree while preserving data",
-		GroupID: "repo-maintenance",
-		Long: `Rebuild recreates a container with the latest base image while preserving:
-- All workspace and home directory data (volumes)
-- SSH port assignment
-- Container name and configuration`,
-		Args: cobra.NoArgs,
+		Use:   "gc",
+		Short: "Remove what failed or interrupted creates left behind",
+		Long: `Find and remove the remnants of creates and rebuilds that failed or were
+interrupted before they could clean up:
+
+  - containers that never started
+  - blue/green replacement containers that were never swapped in
+  - container volumes with no container (including ones kept by
+    'l8s remove --keep-volumes')
+  - SSH config entries for containers of the active connection that no
+    longer exist
+  - git remotes of the current repository for containers that no longer exist
+
+Everything found is listed before anything is removed. The trash is left
+alone; see 'l8s undo-remove'.`,
+		Example: `  l8s gc --dry-run
+  l8s gc --force`,
+		GroupID: "container",
+		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := f.ensureInitialized(); err != nil {
 				return err
 			}
-			
-			// Get flags
-			build, _ := cmd.Flags().GetBool("build")
-			skipBuild, _ := cmd.Flags().GetBool("skip-build")
-			
-			// Validate mutually exclusive flags
-			if build && skipBuild {
-				return fmt.Errorf("--build and --skip-build are mutually exclusive")
-			}
-			
 			origFactory := &CommandFactory{
 				Config:       f.Config,
 				ContainerMgr: f.ContainerMgr,
 				GitClient:    f.GitClient,
 				SSHClient:    f.SSHClient,
 			}
-			return origFactory.runRebuild(cmd, args)
+			return origFactory.runGc(cmd, args)
 		},
 	}
-	
-	cmd.Flags().Bool("build", false, "Build image before rebuilding")
-	cmd.Flags().Bool("skip-build", false, "Skip build and use existing image")
-	
+	cmd.Flags().Bool("dry-run", false, "Only list what would be removed")
+	cmd.Flags().BoolP("force", "f", false, "Remove without asking")
 	return cmd
 }
 
-// RebuildAllCmd returns the rebuild-all command with lazy initialization
-func (f *LazyCommandFactory) RebuildAllCmd() *cobra.Command {
+// HistoryCmd returns the history command with lazy initialization
+func (f *LazyCommandFactory) HistoryCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "rebuild-all",
-		Short:   "Rebuild all containers with updated image",
+		Use:   "history <name>",
+		Short: "Show the commands run in a container with l8s exec",
+		Long: `Show the commands run in a container with 'l8s exec', oldest first, with when
+they ran, how long they took and whether they failed. Useful for working out
+what was set up by hand in a long-lived container before rebuilding it.
+
+The history is kept on this machine, in the l8s state directory, and outlives
+the container. Values passed through exec_env are not recorded.`,
+		Example: `  l8s history myrepo-a3f2d1
+  l8s history myrepo-a3f2d1 -n 20
+  l8s history myrepo-a3f2d1 --clear`,
 		GroupID: "container",
-		Long: `Rebuild all containers with the latest base image while preserving their data.
-		
-This is useful after updating the base image or when you want to refresh all containers.`,
-		Args: cobra.NoArgs,
+		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := f.ensureInitialized(); err != nil {
 				return err
@@ -448,55 +561,83 @@ This is useful after updating the base image or when you want to refresh all con
 				GitClient:    f.GitClient,
 				SSHClient:    f.SSHClient,
 			}
-			return origFactory.runRebuildAll(cmd, args)
+			return origFactory.runHistory(cmd, args)
 		},
 	}
-	
-	cmd.Flags().Bool("force", false, "Skip confirmation prompt")
-	cmd.Flags().Bool("build", false, "Build image before rebuilding")
-	cmd.Flags().Bool("skip-build", false, "Skip build and use existing image")
-	
+	cmd.Flags().IntP("lines", "n", 0, "Show only the last n commands")
+	cmd.Flags().Bool("clear", false, "Delete the container's history")
 	return cmd
 }
 
-// PasteCmd returns the paste command with lazy initialization
-func (f *LazyCommandFactory) PasteCmd() *cobra.Command {
+// WhichCmd returns the which command with lazy initialization
+func (f *LazyCommandFactory) WhichCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "paste [name]",
-		Short:   "Paste clipboard content to container",
-		GroupID: "working",
-		Long: `Paste clipboard content (image or text) from your local machine to the container for the current worktree.
-Content is saved to /tmp/claude-clipboard/ in the container.
+		Use:   "which <name>",
+		Short: "Print the local worktree a container belongs to",
+		Long: `Print the path of the repository or worktree on this machine that a container
+was created from, or whose git remote points at it.
 
-Without a custom name, files are saved as clipboard.png or clipboard.txt (replacing any existing default files).
-With a custom name, files are saved as clipboard-<name>.png or clipboard-<name>.txt (preserving existing files).`,
-		Args: cobra.RangeArgs(0, 1),
+Containers created here are answered from the l8s state directory without
+contacting the host; for others the worktree recorded in the container's labels
+is used.`,
+		Example: `  l8s which myrepo-a3f2d1
+  cd "$(l8s which myrepo-a3f2d1)"`,
+		GroupID: "container",
+		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := f.ensureInitialized(); err != nil {
-				return err
+			cfg, err := config.Load(config.GetConfigPath())
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w\n\nRun 'l8s init' to configure l8s for your remote server", err)
 			}
-			origFactory := &CommandFactory{
-				Config:       f.Config,
-				ContainerMgr: f.ContainerMgr,
-				GitClient:    f.GitClient,
-				SSHClient:    f.SSHClient,
+			origFactory := &CommandFactory{Config: cfg}
+			if origFactory.containerRepo(args[0], nil) == "" {
+				// Only containers created elsewhere need their labels
+				if err := f.ensureInitialized(); err != nil {
+					return err
+				}
+				origFactory.Config = f.Config
+				origFactory.ContainerMgr = f.ContainerMgr
 			}
-			return origFactory.runPaste(cmd, args)
+			return origFactory.runWhich(cmd, args)
 		},
 	}
 }
 
-// PushCmd returns the push command with lazy initialization
-func (f *LazyCommandFactory) PushCmd() *cobra.Command {
+// CdCmd creates the cd command. The l8s shell function from 'l8s shellenv'
+// handles cd in the shell itself; this only explains how to load it.
+func (f *LazyCommandFactory) CdCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "push",
-		Short:   "Push current branch to container (fast-forward only)",
-		GroupID: "working",
-		Long: `Push the current git branch to the container for this worktree.
-		
-The push will fail if it would overwrite changes in the container (non-fast-forward).
-Use 'l8s pull' first if the container has diverged.`,
-		Args: cobra.NoArgs,
+		Use:   "cd <name>",
+		Short: "Change to the local worktree of a container (needs l8s shellenv)",
+		Long: `Change the shell's directory to the worktree a container belongs to, as
+printed by 'l8s which'. A program cannot change its shell's directory, so this
+is done by the l8s shell function that 'l8s shellenv' defines.`,
+		Example: `  eval "$(l8s shellenv)"
+  l8s cd myrepo-a3f2d1`,
+		GroupID: "container",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			origFactory := &CommandFactory{}
+			return origFactory.runCd(cmd, args)
+		},
+	}
+}
+
+// InspectCmd returns the inspect command with lazy initialization
+func (f *LazyCommandFactory) InspectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect <name>",
+		Short: "Print the raw podman inspect JSON for a container",
+		Long: `Print the full podman inspect document for a container, for debugging mounts,
+namespaces and port bindings without SSH access to the remote podman CLI.
+
+--format takes a Go template evaluated against the document, as with
+podman inspect. The json, join, lower and upper functions are available.`,
+		Example: `  l8s inspect myproject
+  l8s inspect myproject --format '{{.State.Status}}'
+  l8s inspect myproject --format '{{json .NetworkSettings.Ports}}'`,
+		GroupID: "container",
+		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := f.ensureInitialized(); err != nil {
 				return err
@@ -507,22 +648,23 @@ Use 'l8s pull' first if the container has diverged.`,
 				GitClient:    f.GitClient,
 				SSHClient:    f.SSHClient,
 			}
-			return origFactory.runPush(cmd, args)
+			return origFactory.runInspect(cmd, args)
 		},
 	}
+	cmd.Flags().StringP("format", "f", "", "Format the output using a Go template")
+	return cmd
 }
 
-// PullCmd returns the pull command with lazy initialization
-func (f *LazyCommandFactory) PullCmd() *cobra.Command {
+// CloneCmd returns the clone command with lazy initialization
+func (f *LazyCommandFactory) CloneCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "pull",
-		Short:   "Pull changes from container (fast-forward only)",
-		GroupID: "working",
-		Long: `Pull changes from the container to your local worktree.
-		
-The pull will fail if it would overwrite local changes (non-fast-forward).
-Resolve conflicts manually if your local branch has diverged.`,
-		Args: cobra.NoArgs,
+		Use:     "clone <source> <new-name>",
+		Short:   "Duplicate a container and its volumes into a new container",
+		GroupID: "container",
+		Long: `Clone copies the home and workspace volumes of an existing container into a
+new container with its own ports and SSH config entry. Use it to try risky
+changes against a copy of real state without touching the original.`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := f.ensureInitialized(); err != nil {
 				return err
@@ -533,19 +675,33 @@ Resolve conflicts manually if your local branch has diverged.`,
 				GitClient:    f.GitClient,
 				SSHClient:    f.SSHClient,
 			}
-			return origFactory.runPull(cmd, args)
+			return origFactory.runClone(cmd, args)
 		},
 	}
 }
 
-// StatusCmd returns the status command with lazy initialization
-func (f *LazyCommandFactory) StatusCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "status",
-		Short:   "Show status of container for current worktree",
-		GroupID: "working",
-		Long:    `Display the status and information about the container associated with the current git worktree.`,
-		Args:    cobra.NoArgs,
+// AdoptCmd returns the adopt command with lazy initialization
+func (f *LazyCommandFactory) AdoptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "adopt <podman-container>",
+		Short: "Bring a container l8s did not create under l8s management",
+		Long: `Take over a container made by hand on the remote host so l8s can manage it.
+
+Podman cannot relabel a container, so adopt commits the container's filesystem
+to an image and creates an l8s container from it. Volumes mounted at the
+user's home and /workspace are copied into l8s volumes; other volumes and bind
+mounts are mounted again. The original is stopped and kept, renamed with a
+-pre-l8s suffix, until you remove it; if adoption fails it is put back.
+
+The container must have /usr/sbin/sshd and the container_user account. Its
+published SSH port is kept unless --ssh-port is given; otherwise a free port
+is allocated. The name defaults to the current worktree's container, or to the
+podman name outside a git repository. An SSH config entry and, inside a
+repository, a git remote are added as for 'l8s create'.`,
+		Example: `  l8s adopt my-old-devbox
+  l8s adopt my-old-devbox --name api --ssh-port 2210`,
+		GroupID: "container",
+		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := f.ensureInitialized(); err != nil {
 				return err
@@ -556,17 +712,1036 @@ func (f *LazyCommandFactory) StatusCmd() *cobra.Command {
 				GitClient:    f.GitClient,
 				SSHClient:    f.SSHClient,
 			}
-			return origFactory.runStatus(cmd, args)
+			return origFactory.runAdopt(cmd, args)
 		},
 	}
+	cmd.Flags().String("name", "", "Name for the adopted container")
+	cmd.Flags().Int("ssh-port", 0, "Host port for SSH instead of the published one")
+	cmd.Flags().BoolP("force", "f", false, "Adopt without asking")
+	return cmd
 }
 
-// ConnectionCmd returns the connection command with subcommands
-func (f *LazyCommandFactory) ConnectionCmd() *cobra.Command {
+// DisownCmd returns the disown command with lazy initialization
+func (f *LazyCommandFactory) DisownCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "connection",
-		Short:   "Manage Podman connection configurations",
-		GroupID: "setup",
+		Use:   "disown <name>",
+		Short: "Release a container from l8s without removing it",
+		Long: `Stop managing a container with l8s while leaving it on the remote host.
+
+The container keeps its name, ports, volumes and filesystem, but loses its l8s
+labels: l8s no longer lists, rebuilds, backs up or removes it. Its SSH config
+entry is removed, and so is its git remote when run from a repository.
+
+Podman cannot remove labels, so the container is committed to an image and
+recreated from it. 'l8s adopt' takes a disowned container back.`,
+		Example: `  l8s disown myrepo-a3f2d1`,
+		GroupID: "container",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runDisown(cmd, args)
+		},
+	}
+	cmd.Flags().BoolP("force", "f", false, "Disown without asking, even a container owned by someone else")
+	return cmd
+}
+
+// MigratePrefixCmd returns the migrate-prefix command with lazy initialization
+func (f *LazyCommandFactory) MigratePrefixCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-prefix <old> <new>",
+		Short: "Move all containers to a new name prefix",
+		Long: `Rename every l8s container from <old>-<name> to <new>-<name> and set
+container_prefix to <new>.
+
+Changing container_prefix on its own leaves existing containers behind under
+the old prefix. This command moves them: volumes are copied to their new names,
+each container is recreated under its new name with the same ports, labels and
+filesystem, and the originals are removed. Running containers are restarted.
+SSH config entries and the current repository's git remotes are updated.
+
+If any container fails to move, container_prefix is left unchanged and the
+error lists what remains; run the command again to finish.`,
+		Example: `  l8s migrate-prefix dev work`,
+		GroupID: "container",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runMigratePrefix(cmd, args)
+		},
+	}
+	cmd.Flags().BoolP("force", "f", false, "Migrate without asking")
+	return cmd
+}
+
+// BackupCmd returns the backup command with subcommands and lazy initialization
+func (f *LazyCommandFactory) BackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "backup",
+		Short:   "Back up container volumes to local, S3 or SSH storage",
+		GroupID: "container",
+		Long: `Snapshot the home and workspace volumes of containers to a backup destination.
+
+The destination and policy come from the backup section of the config:
+
+  backup:
+    schedule: "0 3 * * *"          # cron expression used by 'l8s backup schedule'
+    destination: ~/l8s-backups     # local path, s3://bucket/prefix or ssh://host/path
+    retention: 7                   # snapshots kept per container`,
+	}
+
+	// run wraps a handler with lazy initialization
+	run := func(handler func(*CommandFactory, *cobra.Command, []string) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return handler(origFactory, cmd, args)
+		}
+	}
+
+	runCmd := &cobra.Command{
+		Use:   "run [name...]",
+		Short: "Back up the named containers, or all containers",
+		RunE:  run((*CommandFactory).runBackupRun),
+	}
+	runCmd.Flags().String("destination", "", "Override the configured backup destination")
+
+	listCmd := &cobra.Command{
+		Use:   "list [name]",
+		Short: "List backups at the destination",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  run((*CommandFactory).runBackupList),
+	}
+	listCmd.Flags().String("destination", "", "Override the configured backup destination")
+
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete backups outside the retention window",
+		Args:  cobra.NoArgs,
+		RunE:  run((*CommandFactory).runBackupPrune),
+	}
+	pruneCmd.Flags().String("destination", "", "Override the configured backup destination")
+	pruneCmd.Flags().Int("keep", 0, "Snapshots to keep per container (defaults to backup.retention)")
+
+	scheduleCmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Install the configured backup schedule into your crontab",
+		Args:  cobra.NoArgs,
+		RunE:  run((*CommandFactory).runBackupSchedule),
+	}
+	scheduleCmd.Flags().Bool("remove", false, "Remove the scheduled backup entry")
+
+	cmd.AddCommand(runCmd, listCmd, pruneCmd, scheduleCmd)
+
+	return cmd
+}
+
+// IngressCmd returns the ingress command with subcommands and lazy initialization
+func (f *LazyCommandFactory) IngressCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "ingress",
+		Short:   "Serve container web ports at <name>.<domain> through a reverse proxy",
+		GroupID: "setup",
+		Long: `Run a Caddy reverse proxy on the active connection's host that routes
+<name>.<domain> to each container's web port, so there are no port numbers to
+remember. A wildcard DNS record for *.<domain> must point at the host.
+
+Settings live under the connection in the config:
+
+  connections:
+    work:
+      ingress:
+        enabled: true
+        domain: dev.example.com
+        tls: internal                  # off (default), internal or auto
+
+Routes are updated automatically when containers are created or removed.`,
+	}
+
+	// run wraps a handler with lazy initialization
+	run := func(handler func(*CommandFactory, *cobra.Command, []string) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return handler(origFactory, cmd, args)
+		}
+	}
+
+	enableCmd := &cobra.Command{
+		Use:   "enable",
+		Short: "Start the reverse proxy on the remote host",
+		Args:  cobra.NoArgs,
+		RunE:  run((*CommandFactory).runIngressEnable),
+	}
+	enableCmd.Flags().String("domain", "", "Domain containers are served under, e.g. dev.example.com")
+	enableCmd.Flags().String("tls", "off", "TLS mode: off, internal (self-signed) or auto (Let's Encrypt)")
+
+	disableCmd := &cobra.Command{
+		Use:   "disable",
+		Short: "Stop and remove the reverse proxy",
+		Args:  cobra.NoArgs,
+		RunE:  run((*CommandFactory).runIngressDisable),
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the proxy state and its routes",
+		Args:  cobra.NoArgs,
+		RunE:  run((*CommandFactory).runIngressStatus),
+	}
+
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Regenerate routes from the current containers",
+		Args:  cobra.NoArgs,
+		RunE:  run((*CommandFactory).runIngressSync),
+	}
+
+	cmd.AddCommand(enableCmd, disableCmd, statusCmd, syncCmd)
+
+	return cmd
+}
+
+// GroupCmd returns the group command with subcommands and lazy initialization
+func (f *LazyCommandFactory) GroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "group",
+		Short:   "Manage multi-container projects as a unit",
+		GroupID: "container",
+		Long: `Group containers that make up one project, such as an API and a frontend, so
+they can be started, stopped and checked together. A container belongs to at
+most one group. Groups can also be selected with --filter group=<name>.`,
+	}
+
+	// run wraps a handler with lazy initialization
+	run := func(handler func(*CommandFactory, *cobra.Command, []string) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return handler(origFactory, cmd, args)
+		}
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create <group> <name...>",
+		Short: "Add containers to a group, creating it if needed",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  run((*CommandFactory).runGroupCreate),
+	}
+
+	removeCmd := &cobra.Command{
+		Use:   "remove <group> [name...]",
+		Short: "Take containers out of a group, or dissolve it (containers are kept)",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  run((*CommandFactory).runGroupRemove),
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List groups and their containers",
+		Args:  cobra.NoArgs,
+		RunE:  run((*CommandFactory).runGroupList),
+	}
+
+	startCmd := &cobra.Command{
+		Use:   "start <group>",
+		Short: "Start every container in a group",
+		Args:  cobra.ExactArgs(1),
+		RunE: run(func(f *CommandFactory, cmd *cobra.Command, args []string) error {
+			return f.runGroupStartStop(cmd, args, true)
+		}),
+	}
+	startCmd.Flags().Int("parallel", defaultParallel, "Containers to start at once")
+
+	stopCmd := &cobra.Command{
+		Use:   "stop <group>",
+		Short: "Stop every container in a group",
+		Args:  cobra.ExactArgs(1),
+		RunE: run(func(f *CommandFactory, cmd *cobra.Command, args []string) error {
+			return f.runGroupStartStop(cmd, args, false)
+		}),
+	}
+	stopCmd.Flags().Int("parallel", defaultParallel, "Containers to stop at once")
+
+	statusCmd := &cobra.Command{
+		Use:   "status <group>",
+		Short: "Show the state and ports of a group's containers",
+		Args:  cobra.ExactArgs(1),
+		RunE:  run((*CommandFactory).runGroupStatus),
+	}
+
+	cmd.AddCommand(createCmd, removeCmd, listCmd, startCmd, stopCmd, statusCmd)
+
+	return cmd
+}
+
+// UndoRemoveCmd returns the undo-remove command with lazy initialization
+func (f *LazyCommandFactory) UndoRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "undo-remove [name]",
+		Short:   "Restore a container removed with --trash",
+		GroupID: "container",
+		Long: `Recreate a container from volumes that were moved to the trash by
+'l8s remove --trash' (or remove_to_trash in the config). Trashed volumes are
+purged after trash_retention_days. Without a name, lists the trash.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runUndoRemove(cmd, args)
+		},
+	}
+}
+
+// ProtectCmd returns the protect command with lazy initialization
+func (f *LazyCommandFactory) ProtectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "protect <name>",
+		Short:   "Protect a container from remove and rebuild-all",
+		GroupID: "container",
+		Long: `Mark a long-lived container as protected. 'l8s remove' and 'l8s rebuild-all'
+refuse to touch protected containers unless --force-protected is given.
+
+Protection is recorded in the l8s state directory on this machine, so the
+container keeps running untouched. The API served by 'l8s serve' on this
+machine honors it too.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runProtect(cmd, args)
+		},
+	}
+}
+
+// UnprotectCmd returns the unprotect command with lazy initialization
+func (f *LazyCommandFactory) UnprotectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "unprotect <name>",
+		Short:   "Remove protection from a container",
+		GroupID: "container",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runUnprotect(cmd, args)
+		},
+	}
+}
+
+// LabelCmd returns the label command with subcommands and lazy initialization
+func (f *LazyCommandFactory) LabelCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "label",
+		Short:   "Manage container labels",
+		GroupID: "container",
+		Long: `Tag containers with key=value labels (project, ticket, owner, ...) and
+slice listings with 'l8s list --filter label=key=value'.
+
+Labels cannot be changed on a running container, so set and unset recreate
+the container with the same volumes and ports.`,
+	}
+
+	// run wraps a handler with lazy initialization
+	run := func(handler func(*CommandFactory, *cobra.Command, []string) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return handler(origFactory, cmd, args)
+		}
+	}
+
+	setCmd := &cobra.Command{
+		Use:   "set <name> key=value...",
+		Short: "Add or update labels on a container",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  run((*CommandFactory).runLabelSet),
+	}
+
+	unsetCmd := &cobra.Command{
+		Use:   "unset <name> key...",
+		Short: "Remove labels from a container",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  run((*CommandFactory).runLabelUnset),
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list [name]",
+		Short: "Show labels of a container, or of all containers",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  run((*CommandFactory).runLabelList),
+	}
+
+	cmd.AddCommand(setCmd, unsetCmd, listCmd)
+
+	return cmd
+}
+
+// NoteCmd returns the note command with lazy initialization
+func (f *LazyCommandFactory) NoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "note <name> [text...]",
+		Short:   "Show or set a short description of a container",
+		GroupID: "container",
+		Long: `Attach a free-form note to a container to remember what it is for.
+The note is shown by 'l8s info' and 'l8s list --wide'.
+
+Without text, prints the current note. Setting a note recreates the
+container with the same volumes and ports.`,
+		Example: `  l8s note payments "Stripe webhook retries spike, branch fix/retry"
+  l8s note payments
+  l8s note payments --clear`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runNote(cmd, args)
+		},
+	}
+
+	cmd.Flags().Bool("clear", false, "Remove the note")
+
+	return cmd
+}
+
+// MountCmd returns the mount command with lazy initialization
+func (f *LazyCommandFactory) MountCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "mount <name> [localpath]",
+		Short:   "Mount a container's /workspace/project locally over SSHFS",
+		Long: `Mount a container's /workspace/project on this machine over SSHFS so editors
+and GUI tools can browse its files directly. Defaults to ~/l8s-mounts/<name>.
+
+Requires sshfs (and macFUSE on macOS).`,
+		GroupID: "working",
+		Args:    cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runMount(cmd, args)
+		},
+	}
+}
+
+// UmountCmd returns the umount command with lazy initialization
+func (f *LazyCommandFactory) UmountCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "umount <name> [localpath]",
+		Short:   "Unmount a directory mounted with l8s mount",
+		GroupID: "working",
+		Args:    cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runUmount(cmd, args)
+		},
+	}
+}
+
+// MountListCmd returns the mount-list command with lazy initialization
+func (f *LazyCommandFactory) MountListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "mount-list",
+		Short:   "Show shared host mounts and the containers using them",
+		GroupID: "container",
+		Long: `Show host directories and shared volumes mounted into containers, such as
+read-only models, datasets or package mirrors, and which containers use each.
+
+Mounts are declared under 'mounts' in the l8s config or a repository's
+.l8s.yaml and attached when a container is created:
+
+  mounts:
+    - source: /srv/models
+      target: /models
+      read_only: true`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runMountList(cmd, args)
+		},
+	}
+}
+
+// OpenCmd returns the open command with lazy initialization
+func (f *LazyCommandFactory) OpenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "open [name] [port]",
+		Short: "Open a container's web port in the browser",
+		Long: `Open a container's web port in the default browser. The container defaults
+to the one for the current worktree and the port to its web port (3000, or
+'web').
+
+The published host port is used when it is reachable from this machine.
+Otherwise, or for any other container port, a temporary SSH tunnel is opened
+to localhost and kept up until you press Ctrl+C.`,
+		Example: `  l8s open
+  l8s open myproject
+  l8s open myproject 8080`,
+		GroupID: "working",
+		Args:    cobra.RangeArgs(0, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runOpen(cmd, args)
+		},
+	}
+	cmd.Flags().Bool("tunnel", false, "Always use an SSH tunnel, even if the host port is reachable")
+	return cmd
+}
+
+// BuildCmd returns the build command with lazy initialization
+func (f *LazyCommandFactory) BuildCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "build",
+		Short:   "Build or rebuild the base container image",
+		GroupID: "container",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runBuild(cmd, args)
+		},
+	}
+	cmd.Flags().Bool("scan", false, "Scan the built image for vulnerabilities")
+	cmd.Flags().String("severity", "CRITICAL", "Comma separated severities to report with --scan")
+	cmd.Flags().String("flavor", "", "Distribution to build from: "+strings.Join(embed.Flavors, ", ")+" (default base_flavor)")
+	cmd.Flags().String("variant", "", "Build an image variant from image_variants instead of the base image")
+	cmd.Flags().Bool("no-cache", false, "Rebuild every layer instead of reusing cached ones")
+	cmd.Flags().Bool("refresh", false, "Rebuild the layers with packages and tools to pick up their latest versions")
+	cmd.Flags().BoolP("quiet", "q", false, "Show a summary instead of the build output, which is still saved to the build log")
+	return cmd
+}
+
+// ScanCmd returns the scan command with lazy initialization
+func (f *LazyCommandFactory) ScanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scan [name]",
+		Short: "Scan a container's image, or the base image, for vulnerabilities",
+		Long: `Scan an image on the remote host with trivy, run as a container there, and
+report known vulnerabilities. Without a name the configured base image is
+scanned. Exits non-zero when anything at the requested severities is found.`,
+		GroupID: "container",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runScan(cmd, args)
+		},
+	}
+	cmd.Flags().String("severity", "CRITICAL", "Comma separated severities to report (CRITICAL, HIGH, MEDIUM, LOW, UNKNOWN)")
+	return cmd
+}
+
+// SecurityCmd returns the security command with subcommands and lazy initialization
+func (f *LazyCommandFactory) SecurityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "security",
+		Short:   "Check container hardening",
+		GroupID: "container",
+	}
+
+	// run wraps a handler with lazy initialization
+	run := func(handler func(*CommandFactory, *cobra.Command, []string) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return handler(origFactory, cmd, args)
+		}
+	}
+
+	auditCmd := &cobra.Command{
+		Use:   "audit <name>",
+		Short: "Verify sshd runs under an init with root and password logins disabled",
+		Long: `Check a running container's PID 1 and effective sshd configuration (sshd -T):
+sshd must not be PID 1, root, password and keyboard-interactive logins must be
+disabled, and only the container user may log in. Exits non-zero when any
+check fails; rebuilding the container regenerates its sshd configuration.`,
+		Args: cobra.ExactArgs(1),
+		RunE: run((*CommandFactory).runSecurityAudit),
+	}
+
+	cmd.AddCommand(auditCmd)
+	return cmd
+}
+
+// RemoteCmd returns the remote command with subcommands and lazy initialization
+func (f *LazyCommandFactory) RemoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remote",
+		Short:   "Manage git remotes for containers",
+		GroupID: "container",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "add <name>",
+			Short: "Add git remote for existing container",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if err := f.ensureInitialized(); err != nil {
+					return err
+				}
+				origFactory := &CommandFactory{
+					Config:       f.Config,
+					ContainerMgr: f.ContainerMgr,
+					GitClient:    f.GitClient,
+					SSHClient:    f.SSHClient,
+				}
+				return origFactory.runRemoteAdd(cmd, args)
+			},
+		},
+		&cobra.Command{
+			Use:   "remove <name>",
+			Short: "Remove git remote for container",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if err := f.ensureInitialized(); err != nil {
+					return err
+				}
+				origFactory := &CommandFactory{
+					Config:       f.Config,
+					ContainerMgr: f.ContainerMgr,
+					GitClient:    f.GitClient,
+					SSHClient:    f.SSHClient,
+				}
+				return origFactory.runRemoteRemove(cmd, args)
+			},
+		},
+	)
+
+	return cmd
+}
+
+// ExecCmd returns the exec command with lazy initialization
+func (f *LazyCommandFactory) ExecCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec <command> [args...]",
+		Short: "Execute command in the container for the current worktree",
+		Long: `Execute a command in the container for the current worktree.
+
+With --all or --filter the command runs in every matching running container
+instead, several at a time. Each line of output is prefixed with the container
+name and failures are summarised at the end. Put the command after -- so its
+flags are not taken as l8s flags.`,
+		Example: `  l8s exec make test
+  l8s exec --all -- npm install -g pnpm
+  l8s exec --filter label=team=web -- git status --short`,
+		GroupID: "working",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runExec(cmd, args)
+		},
+	}
+	cmd.Flags().Bool("all", false, "Run in every running container")
+	cmd.Flags().StringArray("filter", nil, "Run in running containers matching key=value (status, owner, label, group, ticket); repeatable")
+	cmd.Flags().Int("parallel", defaultParallel, "Containers to run in at once with --all or --filter")
+	return cmd
+}
+
+// InitCmd returns the init command without lazy initialization
+func (f *LazyCommandFactory) InitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "init",
+		Short:   "Initialize l8s configuration",
+		GroupID: "setup",
+		Long: `Initialize l8s configuration by setting up remote server connection details.
+	
+l8s ONLY supports remote container management for security isolation.
+You'll need:
+- A remote server with Podman installed
+- SSH access to the server
+- SSH key authentication configured`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Init doesn't need dependencies, create a minimal factory
+			origFactory := &CommandFactory{}
+			return origFactory.runInit(cmd, args)
+		},
+	}
+}
+
+// RebuildCmd returns the rebuild command with lazy initialization
+func (f *LazyCommandFactory) RebuildCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rebuild",
+		Short:   "Rebuild the container for the current worktree while preserving data",
+		GroupID: "repo-maintenance",
+		Long: `Rebuild recreates a container with the latest base image while preserving:
+- All workspace and home directory data (volumes)
+- SSH port assignment
+- Container name and configuration
+
+With --blue-green, the replacement container is started alongside the old one
+on a temporary port. Once SSH is reachable and the optional --health-cmd passes
+inside it, the SSH config is switched over and the old container is removed.
+If any check fails the original container keeps running untouched.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			
+			// Get flags
+			build, _ := cmd.Flags().GetBool("build")
+			skipBuild, _ := cmd.Flags().GetBool("skip-build")
+			
+			// Validate mutually exclusive flags
+			if build && skipBuild {
+				return fmt.Errorf("--build and --skip-build are mutually exclusive")
+			}
+			blueGreen, _ := cmd.Flags().GetBool("blue-green")
+			if healthCmd, _ := cmd.Flags().GetString("health-cmd"); healthCmd != "" && !blueGreen {
+				return fmt.Errorf("--health-cmd requires --blue-green")
+			}
+			
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runRebuild(cmd, args)
+		},
+	}
+	
+	cmd.Flags().Bool("build", false, "Build image before rebuilding")
+	cmd.Flags().Bool("skip-build", false, "Skip build and use existing image")
+	cmd.Flags().Bool("blue-green", false, "Start and verify the replacement before removing the old container")
+	cmd.Flags().String("health-cmd", "", "Command that must succeed in the replacement before cutover (requires --blue-green)")
+	
+	return cmd
+}
+
+// RebuildAllCmd returns the rebuild-all command with lazy initialization
+func (f *LazyCommandFactory) RebuildAllCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rebuild-all",
+		Short:   "Rebuild all containers with updated image",
+		GroupID: "container",
+		Long: `Rebuild all containers with the latest base image while preserving their data.
+		
+This is useful after updating the base image or when you want to refresh all containers.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runRebuildAll(cmd, args)
+		},
+	}
+	
+	cmd.Flags().Bool("force", false, "Skip confirmation prompt")
+	cmd.Flags().Bool("build", false, "Build image before rebuilding")
+	cmd.Flags().Bool("skip-build", false, "Skip build and use existing image")
+	cmd.Flags().Bool("force-protected", false, "Also rebuild protected containers")
+	
+	return cmd
+}
+
+// PasteCmd returns the paste command with lazy initialization
+func (f *LazyCommandFactory) PasteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "paste [name]",
+		Short:   "Paste clipboard content to container",
+		GroupID: "working",
+		Long: `Paste clipboard content (image or text) from your local machine to the container for the current worktree.
+Content is saved to /tmp/claude-clipboard/ in the container, which is the
+clipboard directory of Claude's workspace after 'l8s agent init claude'.
+
+Without a custom name, files are saved as clipboard.png or clipboard.txt (replacing any existing default files).
+With a custom name, files are saved as clipboard-<name>.png or clipboard-<name>.txt (preserving existing files).
+
+Files copied in Finder or Explorer are unpacked into files/ (files-<name>/ with a
+custom name), directories included. Rich text is saved as clipboard.md with its
+bold, italic, links and lists converted to markdown; --plain pastes the plain
+text instead. Pastes larger than paste_max_size (default 10m) ask for
+confirmation first; --force skips it.
+
+With --watch, paste keeps running and pastes the clipboard again each time it
+changes, once it has held steady for --debounce. --sync-back also copies
+/tmp/claude-clipboard/to-host.txt to your clipboard whenever something in the
+container writes it.`,
+		Args: cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runPaste(cmd, args)
+		},
+	}
+
+	cmd.Flags().Bool("plain", false, "Paste rich text as plain text instead of markdown")
+	cmd.Flags().BoolP("force", "f", false, "Paste content larger than paste_max_size without asking")
+	cmd.Flags().BoolP("watch", "w", false, "Keep pasting the clipboard whenever it changes")
+	cmd.Flags().Duration("interval", 500*time.Millisecond, "How often --watch checks the clipboard")
+	cmd.Flags().Duration("debounce", time.Second, "How long the clipboard must be unchanged before --watch pastes it")
+	cmd.Flags().Bool("sync-back", false, "With --watch, copy /tmp/claude-clipboard/to-host.txt to the clipboard when it changes")
+
+	return cmd
+}
+
+// PushCmd returns the push command with lazy initialization
+func (f *LazyCommandFactory) PushCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "push",
+		Short:   "Push current branch to container (fast-forward only)",
+		GroupID: "working",
+		Long: `Push the current git branch to the container for this worktree.
+		
+The push will fail if it would overwrite changes in the container (non-fast-forward).
+Use 'l8s pull' first if the container has diverged.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runPush(cmd, args)
+		},
+	}
+}
+
+// PullCmd returns the pull command with lazy initialization
+func (f *LazyCommandFactory) PullCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "pull",
+		Short:   "Pull changes from container (fast-forward only)",
+		GroupID: "working",
+		Long: `Pull changes from the container to your local worktree.
+		
+The pull will fail if it would overwrite local changes (non-fast-forward).
+Resolve conflicts manually if your local branch has diverged.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runPull(cmd, args)
+		},
+	}
+}
+
+// FetchCmd returns the fetch command with lazy initialization
+func (f *LazyCommandFactory) FetchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "fetch",
+		Short:   "Deepen the history of a container created with --shallow",
+		GroupID: "working",
+		Long: `Fetch missing history into a container created with 'l8s create --shallow'.
+
+The history is fetched inside the container from its origin remote, which
+create --shallow mirrors from this repository unless --no-origin is given.`,
+		Example: `  l8s fetch --unshallow
+  l8s fetch --deepen 50`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runFetch(cmd, args)
+		},
+	}
+
+	cmd.Flags().Bool("unshallow", false, "Fetch the complete history")
+	cmd.Flags().Int("deepen", 0, "Fetch N more commits of history")
+
+	return cmd
+}
+
+// StatusCmd returns the status command with lazy initialization
+func (f *LazyCommandFactory) StatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "status",
+		Short:   "Show status of container for current worktree",
+		GroupID: "working",
+		Long: `Display the status and information about the container associated with the current git worktree.
+
+With --all, list every worktree of the repository with its branch, its
+container and whether the container's checkout matches the worktree: in sync,
+commits to push or pull, diverged, or uncommitted changes in the container.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runStatus(cmd, args)
+		},
+	}
+	cmd.Flags().BoolP("all", "a", false, "Show every worktree of the repository and its container")
+	return cmd
+}
+
+// ConnectionCmd returns the connection command with subcommands
+func (f *LazyCommandFactory) ConnectionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "connection",
+		Short:   "Manage Podman connection configurations",
+		GroupID: "setup",
 		Long:    "Manage multiple Podman connection configurations for different network access scenarios",
 	}
 	
@@ -581,39 +1756,282 @@ func (f *LazyCommandFactory) ConnectionCmd() *cobra.Command {
 			return (&ConnectionListCommand{config: f.Config}).Execute(cmd.Context())
 		},
 	})
-	
-	// Show subcommand
+	
+	// Show subcommand
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "Show current Podman connection details",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			return (&ConnectionShowCommand{config: f.Config}).Execute(cmd.Context())
+		},
+	})
+	
+	// Switch subcommand
+	var dryRun bool
+	switchCmd := &cobra.Command{
+		Use:   "switch <name>",
+		Short: "Switch to a different Podman connection",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			return (&ConnectionSwitchCommand{
+				config:           f.Config,
+				targetConnection: args[0],
+				dryRun:           dryRun,
+			}).Execute(cmd.Context())
+		},
+	}
+	switchCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would change without making changes")
+	cmd.AddCommand(switchCmd)
+	
+	return cmd
+}
+
+// SSHConfigCmd returns the ssh-config command with subcommands and lazy initialization
+func (f *LazyCommandFactory) SSHConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "ssh-config",
+		Short:   "Maintain the SSH client files l8s writes to",
+		GroupID: "setup",
+	}
+
+	// run wraps a handler with lazy initialization
+	run := func(handler func(*CommandFactory, *cobra.Command, []string) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return handler(origFactory, cmd, args)
+		}
+	}
+
+	pruneCmd := &cobra.Command{
+		Use:   "prune-known-hosts",
+		Short: "Remove known_hosts entries for removed containers",
+		Long: `Remove host keys recorded for container SSH ports that no container on the
+active connection uses any more, from ~/.ssh/known_hosts and the l8s-managed
+known_hosts file. Hashed entries are matched too. CA trust lines for an old CA
+key or a connection that is no longer configured are removed as well. Other
+hosts are never touched.`,
+		Args: cobra.NoArgs,
+		RunE: run((*CommandFactory).runSSHConfigPruneKnownHosts),
+	}
+	pruneCmd.Flags().Bool("dry-run", false, "Show what would be removed without changing any file")
+
+	cmd.AddCommand(pruneCmd)
+	return cmd
+}
+
+// ConfigCmd returns the config command. Its subcommands read the config file
+// directly so they keep working when it is invalid.
+func (f *LazyCommandFactory) ConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "config",
+		Short:   "Inspect, validate and change the l8s configuration",
+		GroupID: "setup",
+		Long: `Inspect, validate and change the l8s configuration.
+
+Settings are resolved in this order, highest precedence first:
+  1. command line flags
+  2. L8S_* environment variables (see 'l8s config env')
+  3. the config file ($XDG_CONFIG_HOME/l8s/config.yaml, see 'l8s config path')
+  4. built-in defaults`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "validate [path]",
+		Short: "Check the config file for unknown keys and invalid values",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runConfigValidate,
+	})
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the config file",
+		Args:  cobra.NoArgs,
+		RunE:  runConfigShow,
+	}
+	showCmd.Flags().Bool("effective", false, "Show every resolved setting and where it came from")
+	cmd.AddCommand(showCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Change a single setting, e.g. base_image or backup.retention",
+		Example: `  l8s config set base_image localhost/l8s-fedora:v2
+  l8s config set connections.home.quota.max_containers 5`,
+		Args: cobra.ExactArgs(2),
+		RunE: runConfigSet,
+	})
+
+	encryptCmd := &cobra.Command{
+		Use:   "encrypt <key> [value]",
+		Short: "Store a secret setting encrypted with age or in the OS keychain",
+		Long: `Store a secret setting, such as github_token, encrypted so config.yaml can be
+synced in a dotfiles repository. By default the value is encrypted with age
+(https://age-encryption.org) to the identity at age_identity (generated on
+first use); with --keychain it is kept in the macOS keychain or Secret Service
+instead. The value is prompted for when not given.`,
+		Example: `  l8s config encrypt github_token
+  l8s config encrypt github_token --keychain`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: runConfigEncrypt,
+	}
+	encryptCmd.Flags().Bool("keychain", false, "Store the value in the OS keychain instead of encrypting it with age")
+	cmd.AddCommand(encryptCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "path",
+		Short: "Print where l8s keeps its config, CA, cache and state",
+		Args:  cobra.NoArgs,
+		RunE:  runConfigPath,
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "env",
+		Short: "List the L8S_* variables that override settings",
+		Args:  cobra.NoArgs,
+		RunE:  runConfigEnv,
+	})
+
 	cmd.AddCommand(&cobra.Command{
-		Use:   "show",
-		Short: "Show current Podman connection details",
+		Use:   "edit",
+		Short: "Open the config file in $EDITOR and validate it before saving",
+		Args:  cobra.NoArgs,
+		RunE:  runConfigEdit,
+	})
+
+	return cmd
+}
+
+// StatsCmd creates the stats command
+func (f *LazyCommandFactory) StatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show locally recorded statistics",
+		Long: `Show statistics l8s keeps on this machine.
+
+--usage lists how often each command ran and how long it took, slowest
+overall first, to find the operations worth speeding up. Recording is off
+until usage_stats is enabled; data stays in the l8s state directory and is
+never sent anywhere.`,
+		Example: `  l8s config set usage_stats true
+  l8s stats --usage
+  l8s stats --usage --reset`,
+		GroupID: "setup",
+		Args:    cobra.NoArgs,
+		RunE:    runStats,
+	}
+	cmd.Flags().Bool("usage", false, "Show command counts and durations")
+	cmd.Flags().Bool("reset", false, "Clear the selected statistics")
+	return cmd
+}
+
+// ServeCmd creates the serve command
+func (f *LazyCommandFactory) ServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve an HTTP API for managing containers",
+		Long: `Serve a JSON API over HTTP so web dashboards and chatops bots can list,
+create, start, stop, remove and exec into containers. Requests go through the
+same container manager as the CLI, on the active connection unless they add
+?connection=<name>.
+
+The root path serves a dashboard listing containers on every connection with
+their status, owner, ports and idle time, with buttons to start, stop and
+remove them. It asks for the API token in the browser; --dashboard=false
+serves the API alone.
+
+With serve.slack.signing_secret set, POST /slack/command accepts Slack slash
+commands (/l8s list, /l8s stop <name>, /l8s start <name>). Slack users listed
+in serve.slack.users act as the mapped l8s owner and may only stop or start
+their own containers; serve.slack.admins may act on any.
+
+Every request under /v1/ must carry "Authorization: Bearer <token>" with the
+token from serve.token (or L8S_SERVE_TOKEN). Without --tls-cert the API is
+plain HTTP, so keep it on loopback or behind a TLS proxy.
+
+DELETE applies the same checks as l8s remove: protected containers, containers
+created by someone else and, when volumes are deleted, unsaved work are
+refused with 409 Conflict unless ?force=true is given. Volumes go to the trash
+by default; ?trash=false deletes them and ?volumes=false keeps them.
+
+Endpoints:
+  GET    /v1/status
+  GET    /v1/connections
+  GET    /v1/containers
+  POST   /v1/containers                {"name": "...", "ssh_key": "..."}
+  GET    /v1/containers/{name}
+  DELETE /v1/containers/{name}         ?trash=false, ?volumes=false, ?force=true
+  POST   /v1/containers/{name}/start
+  POST   /v1/containers/{name}/stop
+  POST   /v1/containers/{name}/exec    {"command": ["ls", "-la"]}`,
+		Example: `  l8s config set serve.token "$(openssl rand -hex 32)"
+  l8s serve --listen :8080 --tls-cert cert.pem --tls-key key.pem
+  curl -H "Authorization: Bearer $TOKEN" http://127.0.0.1:8080/v1/containers`,
+		GroupID: "setup",
+		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := f.ensureInitialized(); err != nil {
 				return err
 			}
-			return (&ConnectionShowCommand{config: f.Config}).Execute(cmd.Context())
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runServe(cmd, args)
 		},
-	})
-	
-	// Switch subcommand
-	var dryRun bool
-	switchCmd := &cobra.Command{
-		Use:   "switch <name>",
-		Short: "Switch to a different Podman connection",
-		Args:  cobra.ExactArgs(1),
+	}
+	cmd.Flags().String("listen", defaultServeListen, "Address to listen on (overrides serve.listen)")
+	cmd.Flags().String("tls-cert", "", "Serve HTTPS with this certificate file")
+	cmd.Flags().String("tls-key", "", "Private key for --tls-cert")
+	cmd.Flags().Bool("dashboard", true, "Serve the web dashboard at /")
+	return cmd
+}
+
+// VersionCmd creates the version command. version and buildTime are set
+// at link time (see the Makefile).
+func (f *LazyCommandFactory) VersionCmd(version, buildTime string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show the l8s version",
+		Long: `Show the l8s version. With --remote, also connect to the active connection
+and show its Podman and API versions, warning when the Podman major version is
+outside the range l8s is tested against.`,
+		Example: `  l8s version
+  l8s version --remote`,
+		GroupID: "setup",
+		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			printVersion(version, buildTime)
+			if remote, _ := cmd.Flags().GetBool("remote"); !remote {
+				return nil
+			}
 			if err := f.ensureInitialized(); err != nil {
 				return err
 			}
-			return (&ConnectionSwitchCommand{
-				config:           f.Config,
-				targetConnection: args[0],
-				dryRun:           dryRun,
-			}).Execute(cmd.Context())
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runRemoteVersion(cmd, args)
 		},
 	}
-	switchCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would change without making changes")
-	cmd.AddCommand(switchCmd)
-	
+	cmd.Flags().Bool("remote", false, "Also show the remote Podman version and compatibility")
 	return cmd
 }
 
@@ -630,7 +2048,9 @@ This command will:
   2. Update your .zshrc to load the plugin
 
 Prerequisites:
-  - Oh My Zsh must be installed (https://ohmyz.sh/)`,
+  - Oh My Zsh must be installed (https://ohmyz.sh/)
+
+For zsh without Oh My Zsh, bash or fish, use install-shell-plugin.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// InstallZSHPlugin doesn't need dependencies, create a minimal factory
 			origFactory := &CommandFactory{}
@@ -639,6 +2059,144 @@ Prerequisites:
 	}
 }
 
+// InstallShellPluginCmd creates the install-shell-plugin command
+func (f *LazyCommandFactory) InstallShellPluginCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install-shell-plugin",
+		Short: "Install l8s completion and shell functions for bash, zsh or fish",
+		Long: `Install what 'l8s shellenv' prints as a plugin: completion for l8s commands,
+flags and container names, and 'l8s cd <name>'. Works with any zsh, Oh My Zsh
+or not.
+
+  bash: written to ~/.local/share/l8s/shell/l8s.bash and sourced from ~/.bashrc
+  zsh:  written to ~/.local/share/l8s/shell/l8s.zsh and sourced from ~/.zshrc
+  fish: written to ~/.config/fish/conf.d/l8s.fish, which fish loads itself
+
+The shell defaults to the one in $SHELL.`,
+		Example: `  l8s install-shell-plugin
+  l8s install-shell-plugin --shell fish`,
+		GroupID: "setup",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Only reads the config for the container prefix, if there is one
+			origFactory := &CommandFactory{}
+			return origFactory.runInstallShellPlugin(cmd, args)
+		},
+	}
+	cmd.Flags().String("shell", "", "Shell to install for: bash, zsh or fish (default from $SHELL)")
+	return cmd
+}
+
+// ShellEnvCmd creates the shellenv command
+func (f *LazyCommandFactory) ShellEnvCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shellenv [bash|zsh|fish]",
+		Short: "Print shell functions and completion for l8s",
+		Long: `Print shell code that defines an l8s function adding 'l8s cd <name>', which
+changes to the local worktree of a container, and completion for l8s commands,
+flags and container names. Without an argument the shell is taken from $SHELL.
+
+Unlike install-zsh-plugin this needs no Oh My Zsh; zsh users should load it
+after compinit.`,
+		Example: `  # ~/.bashrc
+  eval "$(l8s shellenv bash)"
+  # ~/.zshrc
+  eval "$(l8s shellenv zsh)"
+  # ~/.config/fish/config.fish
+  l8s shellenv fish | source`,
+		GroupID:   "setup",
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Only reads the config for the container prefix, if there is one
+			origFactory := &CommandFactory{}
+			return origFactory.runShellEnv(cmd, args)
+		},
+	}
+}
+
+// DocsCmd creates the docs command for generating reference documentation
+func (f *LazyCommandFactory) DocsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "docs",
+		Short:   "Generate man pages or markdown for every l8s command",
+		GroupID: "setup",
+		Long: `Generate reference documentation for every l8s command and help topic, for
+distribution packages or a documentation site. Man pages go in section 1,
+help topics such as 'l8s help networking' in section 7.
+
+Set SOURCE_DATE_EPOCH for reproducible man page dates.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "man <dir>",
+		Short:   "Write man pages to a directory",
+		Example: `  l8s docs man ./man && man ./man/l8s-create.1`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Docs come from the command tree and need no configuration
+			origFactory := &CommandFactory{}
+			return origFactory.runDocsMan(cmd, args)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:     "markdown <dir>",
+		Short:   "Write a markdown page per command to a directory",
+		Example: `  l8s docs markdown ./docs/cli`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			origFactory := &CommandFactory{}
+			return origFactory.runDocsMarkdown(cmd, args)
+		},
+	})
+	return cmd
+}
+
+// EmbedCmd creates the embed command for inspecting the files built into l8s
+func (f *LazyCommandFactory) EmbedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "embed",
+		Short:   "Inspect the Containerfiles, dotfiles and scripts built into l8s",
+		GroupID: "setup",
+		Long: `List or extract the files built into l8s: the Containerfiles and build
+context used by 'l8s build', the dotfiles copied into containers, the shell
+integration and the dashboard.
+
+Release builds carry a signature of these files, checked before any of them
+are used; development builds are unsigned.`,
+	}
+
+	list := &cobra.Command{
+		Use:   "list [path...]",
+		Short: "List embedded files with their sizes and checksums",
+		Example: `  l8s embed list
+  l8s embed list dotfiles/.config/nvim
+  l8s embed list --checksums > SHA256SUMS`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Embedded files need no configuration
+			origFactory := &CommandFactory{}
+			return origFactory.runEmbedList(cmd, args)
+		},
+	}
+	list.Flags().Bool("checksums", false, "Print only checksums in sha256sum format, as signed by releases")
+	cmd.AddCommand(list)
+
+	extract := &cobra.Command{
+		Use:   "extract <dir> [path...]",
+		Short: "Write embedded files to a directory",
+		Example: `  l8s embed extract /tmp/l8s-files
+  l8s embed extract . containers/Containerfile dotfiles/.zshrc`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			origFactory := &CommandFactory{}
+			return origFactory.runEmbedExtract(cmd, args)
+		},
+	}
+	extract.Flags().Bool("force", false, "Overwrite files that already exist")
+	cmd.AddCommand(extract)
+	return cmd
+}
+
 // TeamCmd creates the team command for managing dtach sessions
 func (f *LazyCommandFactory) TeamCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -769,4 +2327,261 @@ func (f *LazyCommandFactory) AudioCmd() *cobra.Command {
 	)
 
 	return cmd
-}
\ No newline at end of file
+}
+// CICmd returns the ci command with subcommands for running tasks in CI
+func (f *LazyCommandFactory) CICmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "ci",
+		Short:   "Run tasks in throwaway containers",
+		GroupID: "working",
+		Long:    `Run tasks against a commit in short-lived containers, for CI pipelines and pre-merge checks.`,
+	}
+
+	createEphemeral := &cobra.Command{
+		Use:   "create-ephemeral [flags] -- <command> [args...]",
+		Short: "Run a task at a ref in a container that is removed afterwards",
+		Long: `Create a container, push the given ref to it, run the command in
+/workspace/project and remove the container and its volumes again.
+
+The task's output is streamed and saved to task.log in the output directory.
+Paths and globs given with --artifact, relative to the project, are copied
+back into the output directory whether or not the task succeeds. l8s exits
+with the task's exit code, so it can stand in for the task in a CI job.`,
+		Example: `  l8s ci create-ephemeral -- make test
+  l8s ci create-ephemeral --ref origin/main --artifact coverage.out --artifact 'dist/*' -- make release
+  l8s ci create-ephemeral --keep-on-failure --output /tmp/ci -- npm test`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runCICreateEphemeral(cmd, args)
+		},
+	}
+	createEphemeral.Flags().String("ref", "HEAD", "Branch, tag or commit to run the task against")
+	createEphemeral.Flags().String("name", "", "Container name (default ci-<commit>-<random>)")
+	createEphemeral.Flags().StringArray("artifact", nil, "Path or glob to copy back after the task; repeatable")
+	createEphemeral.Flags().StringP("output", "o", "l8s-artifacts", "Directory for task.log and artifacts")
+	createEphemeral.Flags().Bool("keep", false, "Keep the container after the task")
+	createEphemeral.Flags().Bool("keep-on-failure", false, "Keep the container if the task or setup fails")
+
+	cmd.AddCommand(createEphemeral)
+	return cmd
+}
+
+// ReviewCmd creates the review command
+func (f *LazyCommandFactory) ReviewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review <pr-number>",
+		Short: "Create a container with a GitHub pull request checked out",
+		Long: `Look up a pull request in the GitHub repository of the origin remote,
+create a container, fetch the pull request's head inside it and check it out
+under the pull request's branch name.
+
+The container is named <repo>-pr<number> and is independent of the current
+worktree, so reviewing does not disturb your own container. github_token is
+used to read private repositories and to fetch inside the container; it is
+not stored in the container's git config.`,
+		Example: `  l8s review 128
+  l8s review 128 --name api-caching`,
+		GroupID: "working",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runReview(cmd, args)
+		},
+	}
+	cmd.Flags().String("name", "", "Container name (default <repo>-pr<number>)")
+	return cmd
+}
+
+// SparseCmd creates the sparse command
+func (f *LazyCommandFactory) SparseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "sparse",
+		Short:   "Show or change which directories the container checks out",
+		GroupID: "working",
+		Long: `Manage the sparse checkout of the current worktree's container. Only the
+listed directories and the files at the top of the repository are in the
+container's working tree; history and pushes are unaffected.
+
+Start one with 'l8s create --sparse <dir>' or 'l8s create --subdir <dir>'.`,
+	}
+
+	run := func(fn func(*CommandFactory, *cobra.Command, []string) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return fn(origFactory, cmd, args)
+		}
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get",
+		Short: "List the checked out directories",
+		Args:  cobra.NoArgs,
+		RunE:  run((*CommandFactory).runSparseGet),
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <dir>...",
+		Short: "Check out only these directories",
+		Long: `Replace the container's checked out directories. Files that leave the
+working tree are removed from it, new ones are checked out. The --subdir
+directory of the container is always kept.`,
+		Example: `  l8s sparse set services/api libs/common`,
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    run((*CommandFactory).runSparseSet),
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "disable",
+		Short: "Check out the whole tree again",
+		Args:  cobra.NoArgs,
+		RunE:  run((*CommandFactory).runSparseDisable),
+	})
+	return cmd
+}
+
+// AgentCmd creates the agent command
+func (f *LazyCommandFactory) AgentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "agent",
+		Short:   "Set up workspaces for coding agents in the container",
+		GroupID: "working",
+		Long: `Give coding agents in the current worktree's container a standard workspace
+under /workspace/agents/<name>:
+
+  clipboard/   content pasted with 'l8s paste' (also at /tmp/<name>-clipboard)
+  artifacts/   files the agent produces for you, fetched with 'artifacts pull'
+  scratch/     throwaway work kept out of the repository
+
+The workspace is on the workspace volume, so it survives rebuilds.`,
+	}
+
+	run := func(fn func(*CommandFactory, *cobra.Command, []string) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return fn(origFactory, cmd, args)
+		}
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "init <name>",
+		Short: "Create an agent's workspace and install its settings",
+		Long: `Create /workspace/agents/<name> with clipboard, artifacts and scratch
+directories, link /tmp/<name>-clipboard to the clipboard directory and install
+~/.<name> from your dotfiles (for example ~/.claude from the embedded ones).
+Running it again is safe.`,
+		Example: `  l8s agent init claude`,
+		Args:    cobra.ExactArgs(1),
+		RunE:    run((*CommandFactory).runAgentInit),
+	})
+
+	artifacts := &cobra.Command{
+		Use:   "artifacts",
+		Short: "Work with files agents produce",
+	}
+	pull := &cobra.Command{
+		Use:     "pull <name>",
+		Short:   "Copy an agent's artifacts to this machine",
+		Example: `  l8s agent artifacts pull claude -o ./review`,
+		Args:    cobra.ExactArgs(1),
+		RunE:    run((*CommandFactory).runAgentArtifactsPull),
+	}
+	pull.Flags().StringP("output", "o", "", "Directory to copy into (default l8s-artifacts/<name>)")
+	artifacts.AddCommand(pull)
+	cmd.AddCommand(artifacts)
+	return cmd
+}
+
+// NotifyCmd creates the notify command
+func (f *LazyCommandFactory) NotifyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "notify",
+		Short:   "Show notifications from the container on this machine",
+		GroupID: "working",
+		Long: `Forward notifications from the current worktree's container to this machine's
+desktop while it runs. Inside the container, long-running jobs call l8s-notify:
+
+  make test && l8s-notify "tests passed" || l8s-notify -t FAILED "tests failed"
+
+Messages travel over SSH through /tmp/l8s-notify.sock in the container and are
+shown with osascript on macOS, a toast on Windows and WSL, and notify-send on
+Linux. l8s-notify is part of the base image; rebuild containers created from
+older images to get it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runNotify(cmd, args)
+		},
+	}
+}
+
+// TailCmd creates the tail command
+func (f *LazyCommandFactory) TailCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tail <name> <path|journal|journal:unit>...",
+		Short: "Follow log files in a container",
+		Long: `Follow files or journald logs inside a container without opening an SSH
+session. Lines from every source are interleaved as they arrive, each behind
+a colored prefix naming its file or unit.
+
+Relative paths are taken from /workspace/project. "journal" follows the whole
+journal and "journal:<unit>" one systemd unit; both need a container created
+with --systemd.`,
+		Example: `  l8s tail myproject logs/app.log logs/worker.log
+  l8s tail myproject /var/log/sshd.log journal:postgresql -n 50`,
+		GroupID: "working",
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.ensureInitialized(); err != nil {
+				return err
+			}
+			origFactory := &CommandFactory{
+				Config:       f.Config,
+				ContainerMgr: f.ContainerMgr,
+				GitClient:    f.GitClient,
+				SSHClient:    f.SSHClient,
+			}
+			return origFactory.runTail(cmd, args)
+		},
+	}
+	cmd.Flags().IntP("lines", "n", 10, "Lines of existing output to show from each source")
+	return cmd
+}
@@ -3,10 +3,14 @@ package cli
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
+	"time"
 
+	"l8s/pkg/backup"
 	"l8s/pkg/config"
 	"l8s/pkg/container"
+	"l8s/pkg/git"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -84,7 +88,7 @@ func TestLazyCommandFactory(t *testing.T) {
 // Mock implementations for testing
 type MockContainerManager struct{}
 
-func (m *MockContainerManager) CreateContainer(ctx context.Context, name, sshKey string) (*container.Container, error) {
+func (m *MockContainerManager) CreateContainer(ctx context.Context, name, sshKey string, opts container.CreateOptions) (*container.Container, error) {
 	return &container.Container{Name: name}, nil
 }
 
@@ -120,7 +124,7 @@ func (m *MockContainerManager) ExecContainerWithInput(ctx context.Context, name
 	return nil
 }
 
-func (m *MockContainerManager) SSHIntoContainer(ctx context.Context, name string) error {
+func (m *MockContainerManager) SSHIntoContainer(ctx context.Context, name string, opts container.SSHOptions) error {
 	return nil
 }
 
@@ -128,6 +132,102 @@ func (m *MockContainerManager) RebuildContainer(ctx context.Context, name string
 	return nil
 }
 
+func (m *MockContainerManager) BlueGreenRebuildContainer(ctx context.Context, name, healthCmd string) error {
+	return nil
+}
+
+func (m *MockContainerManager) CloneContainer(ctx context.Context, source, name string) (*container.Container, error) {
+	return nil, nil
+}
+
+func (m *MockContainerManager) AdoptContainer(ctx context.Context, source, name string, opts container.AdoptOptions) (*container.Container, error) {
+	return nil, nil
+}
+
+func (m *MockContainerManager) DisownContainer(ctx context.Context, name string) error {
+	return nil
+}
+
+func (m *MockContainerManager) MigratePrefix(ctx context.Context, name, oldPrefix, newPrefix string) (*container.Container, error) {
+	return nil, nil
+}
+
+func (m *MockContainerManager) BackupContainer(ctx context.Context, name string, store backup.Store, ts time.Time) ([]backup.Entry, error) {
+	return nil, nil
+}
+
+func (m *MockContainerManager) TrashContainer(ctx context.Context, name string) error {
+	return nil
+}
+
+func (m *MockContainerManager) ListTrash(ctx context.Context) ([]*container.TrashedContainer, error) {
+	return nil, nil
+}
+
+func (m *MockContainerManager) RestoreContainer(ctx context.Context, name string) (*container.Container, error) {
+	return nil, nil
+}
+
+func (m *MockContainerManager) PurgeTrash(ctx context.Context, retention time.Duration) ([]*container.TrashedContainer, error) {
+	return nil, nil
+}
+
+func (m *MockContainerManager) ExecContainerOutput(ctx context.Context, name string, command []string) (string, error) {
+	return "", nil
+}
+
+func (m *MockContainerManager) UpdateLabels(ctx context.Context, name string, set map[string]string, unset []string) error {
+	return nil
+}
+
+func (m *MockContainerManager) GetActivity(ctx context.Context, name string) (*container.Activity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockContainerManager) InspectContainer(ctx context.Context, name string) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockContainerManager) WaitForSSH(ctx context.Context, name string) error {
+	return nil
+}
+
+func (m *MockContainerManager) AuditSecurity(ctx context.Context, name string) ([]container.SecurityCheck, error) {
+	return nil, nil
+}
+
+func (m *MockContainerManager) SSHAuthStats(ctx context.Context, name string) (*container.SSHAuthStats, error) {
+	return &container.SSHAuthStats{}, nil
+}
+
+func (m *MockContainerManager) RemoteVersion(ctx context.Context) (*container.RemoteVersion, error) {
+	return &container.RemoteVersion{}, nil
+}
+
+func (m *MockContainerManager) InstallAgentSettings(ctx context.Context, name, agent string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockContainerManager) ExecContainerStream(ctx context.Context, name string, command []string, stdout, stderr io.Writer) error {
+	return nil
+}
+
+func (m *MockContainerManager) Drift(ctx context.Context, name string) (*container.Drift, error) {
+	return &container.Drift{}, nil
+}
+
+func (m *MockContainerManager) DiskUsage(ctx context.Context) (*container.DiskReport, error) {
+	return &container.DiskReport{}, nil
+}
+
+func (m *MockContainerManager) FindRemnants(ctx context.Context) (*container.Remnants, error) {
+	return &container.Remnants{}, nil
+}
+
+func (m *MockContainerManager) RemoveRemnants(ctx context.Context, remnants *container.Remnants) error {
+	return nil
+}
+
 type MockGitClient struct{}
 
 func (m *MockGitClient) CloneRepository(repoPath, gitURL, branch string) error {
@@ -174,6 +274,34 @@ func (m *MockGitClient) ValidateGitURL(gitURL string) error {
 	return nil
 }
 
+func (m *MockGitClient) PushShallow(repoPath, branch, remoteName string, depth int) error {
+	return nil
+}
+
+func (m *MockGitClient) HasCommit(repoPath, sha string) bool {
+	return true
+}
+
+func (m *MockGitClient) ResolveRef(repoPath, ref string) (string, error) {
+	return "0123456789abcdef0123456789abcdef01234567", nil
+}
+
+func (m *MockGitClient) PushRef(repoPath, ref, remoteName, branch string) error {
+	return nil
+}
+
+func (m *MockGitClient) CreateBranch(repoPath, branch, startPoint string) error {
+	return nil
+}
+
+func (m *MockGitClient) ListWorktrees(repoPath string) ([]git.Worktree, error) {
+	return []git.Worktree{{Path: repoPath, Branch: "main"}}, nil
+}
+
+func (m *MockGitClient) AheadBehind(repoPath, a, b string) (int, int, error) {
+	return 0, 0, nil
+}
+
 type MockSSHClient struct{}
 
 func (m *MockSSHClient) ReadPublicKey(keyPath string) (string, error) {
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+)
+
+// runFetch deepens the history of a container created with --shallow by
+// fetching from its origin remote, since the host cannot push missing history
+// into a shallow repository
+func (f *CommandFactory) runFetch(cmd *cobra.Command, args []string) error {
+	unshallow, _ := cmd.Flags().GetBool("unshallow")
+	deepen, _ := cmd.Flags().GetInt("deepen")
+	if unshallow == (deepen > 0) {
+		return fmt.Errorf("specify exactly one of --unshallow or --deepen N")
+	}
+
+	fullName, err := GetContainerNameFromWorktree(f.Config.ContainerPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to determine container: %w", err)
+	}
+	shortName := fullName[len(f.Config.ContainerPrefix)+1:]
+	ctx := context.Background()
+
+	inProject := func(script string) []string {
+		return []string{"su", "-", f.Config.ContainerUser, "-c", "cd /workspace/project && " + script}
+	}
+
+	out, err := f.ContainerMgr.ExecContainerOutput(ctx, shortName, inProject("git rev-parse --is-shallow-repository"))
+	if err != nil {
+		return fmt.Errorf("failed to inspect repository in %s: %w", fullName, err)
+	}
+	if strings.TrimSpace(out) != "true" {
+		color.Printf("{green}✓{reset} %s already has the full history\n", fullName)
+		return nil
+	}
+	if _, err := f.ContainerMgr.ExecContainerOutput(ctx, shortName, inProject("git remote get-url origin")); err != nil {
+		return fmt.Errorf("%s has no origin remote to fetch history from\nRecreate it without --shallow to push the full history", fullName)
+	}
+
+	fetch := "git fetch --unshallow origin"
+	if deepen > 0 {
+		fetch = fmt.Sprintf("git fetch --deepen=%d origin", deepen)
+	}
+	color.Printf("{cyan}→{reset} Fetching history into {bold}%s{reset} from origin...\n", fullName)
+	if err := f.ContainerMgr.ExecContainer(ctx, shortName, inProject(fetch)); err != nil {
+		return fmt.Errorf("failed to fetch history: %w", err)
+	}
+	color.Printf("{green}✓{reset} History updated\n")
+	return nil
+}
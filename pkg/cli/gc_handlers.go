@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"l8s/pkg/color"
+	"l8s/pkg/container"
+	"l8s/pkg/ssh"
+
+	"github.com/spf13/cobra"
+)
+
+// hostRemnants are entries on this machine that point at containers which no
+// longer exist
+type hostRemnants struct {
+	SSHHosts []string // Host entries in ~/.ssh/config
+	Remotes  []string // Remotes of the current repository
+}
+
+// findHostRemnants finds SSH config entries for the active connection and
+// git remotes of the repository at repoRoot (if any) whose container is not
+// in live
+func (f *CommandFactory) findHostRemnants(sshConfigPath, repoRoot string, live map[string]bool) (*hostRemnants, error) {
+	remnants := &hostRemnants{}
+	prefix := f.Config.ContainerPrefix + "-"
+
+	// Entries for other connections' containers are left alone
+	if address, err := f.Config.GetActiveAddress(); err == nil {
+		hosts, err := ssh.ListSSHConfigHosts(sshConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, host := range hosts {
+			if strings.HasPrefix(host.Name, prefix) && host.HostName == address && !live[host.Name] {
+				remnants.SSHHosts = append(remnants.SSHHosts, host.Name)
+			}
+		}
+	}
+
+	if repoRoot != "" {
+		remotes, err := f.GitClient.ListRemotes(repoRoot)
+		if err != nil {
+			return nil, err
+		}
+		for name, url := range remotes {
+			fullName := prefix + name
+			if url == fullName+":/workspace/project" && !live[fullName] {
+				remnants.Remotes = append(remnants.Remotes, name)
+			}
+		}
+		sort.Strings(remnants.Remotes)
+	}
+	return remnants, nil
+}
+
+// runGc finds and removes what failed or interrupted creates and rebuilds
+// left behind
+func (f *CommandFactory) runGc(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	remnants, err := f.ContainerMgr.FindRemnants(ctx)
+	if err != nil {
+		return err
+	}
+	containers, err := f.ContainerMgr.ListContainers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+	live := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		live[c.Name] = true
+	}
+	for _, c := range remnants.Containers {
+		delete(live, c.Name)
+	}
+
+	repoRoot, _ := f.GitClient.GetRepositoryRoot(".")
+	sshConfigPath := filepath.Join(ssh.GetHomeDir(), ".ssh", "config")
+	host, err := f.findHostRemnants(sshConfigPath, repoRoot, live)
+	if err != nil {
+		return err
+	}
+
+	if remnants.Empty() && len(host.SSHHosts) == 0 && len(host.Remotes) == 0 {
+		color.Printf("{green}✓{reset} Nothing to clean up\n")
+		return nil
+	}
+	printRemnants(remnants, host)
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		return nil
+	}
+	if force, _ := cmd.Flags().GetBool("force"); !force {
+		fmt.Print("\nRemove them? (y/N): ")
+		response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			return err
+		}
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	failed := false
+	if err := f.ContainerMgr.RemoveRemnants(ctx, remnants); err != nil {
+		color.Printf("{red}✗{reset} %v\n", err)
+		failed = true
+	}
+	for _, name := range host.SSHHosts {
+		if err := ssh.RemoveSSHConfigEntry(sshConfigPath, name); err != nil {
+			color.Printf("{red}✗{reset} SSH config entry %s: %v\n", name, err)
+			failed = true
+		}
+	}
+	for _, name := range host.Remotes {
+		if err := f.GitClient.RemoveRemote(repoRoot, name); err != nil {
+			color.Printf("{red}✗{reset} Git remote %s: %v\n", name, err)
+			failed = true
+		}
+	}
+	if failed {
+		return fmt.Errorf("some remnants could not be removed")
+	}
+	color.Printf("{green}✓{reset} Cleaned up\n")
+	return nil
+}
+
+// printRemnants lists what l8s gc found
+func printRemnants(remnants *container.Remnants, host *hostRemnants) {
+	if len(remnants.Containers) > 0 {
+		color.Printf("{bold}Containers{reset}\n")
+		for _, c := range remnants.Containers {
+			color.Printf("  %s {dim}(%s){reset}\n", c.Name, c.Reason)
+		}
+	}
+	if len(remnants.Volumes) > 0 {
+		color.Printf("{bold}Volumes without a container{reset}\n")
+		for _, v := range remnants.Volumes {
+			fmt.Printf("  %s\n", v)
+		}
+	}
+	if len(host.SSHHosts) > 0 {
+		color.Printf("{bold}SSH config entries{reset}\n")
+		for _, name := range host.SSHHosts {
+			fmt.Printf("  Host %s\n", name)
+		}
+	}
+	if len(host.Remotes) > 0 {
+		color.Printf("{bold}Git remotes{reset}\n")
+		for _, name := range host.Remotes {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+}
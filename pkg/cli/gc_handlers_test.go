@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"l8s/pkg/config"
+	"l8s/pkg/ssh"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindHostRemnants(t *testing.T) {
+	sshConfigPath := filepath.Join(t.TempDir(), "config")
+	content := ssh.GenerateSSHConfigEntry("dev-live", 2200, "dev", "dev", "10.0.0.1", "") + "\n" +
+		ssh.GenerateSSHConfigEntry("dev-gone", 2201, "dev", "dev", "10.0.0.1", "") + "\n" +
+		ssh.GenerateSSHConfigEntry("dev-elsewhere", 2202, "dev", "dev", "10.0.0.2", "") + "\n" +
+		"Host github.com\n    User git\n"
+	require.NoError(t, os.WriteFile(sshConfigPath, []byte(content), 0600))
+
+	git := new(MockGitClientEnhanced)
+	git.On("ListRemotes", "/repo").Return(map[string]string{
+		"origin": "git@github.com:user/repo.git",
+		"live":   "dev-live:/workspace/project",
+		"gone":   "dev-gone:/workspace/project",
+	}, nil)
+	f := &CommandFactory{
+		Config: &config.Config{
+			ContainerPrefix:  "dev",
+			ActiveConnection: "home",
+			Connections:      map[string]config.ConnectionConfig{"home": {Address: "10.0.0.1"}},
+		},
+		GitClient: git,
+	}
+
+	remnants, err := f.findHostRemnants(sshConfigPath, "/repo", map[string]bool{"dev-live": true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev-gone"}, remnants.SSHHosts)
+	assert.Equal(t, []string{"gone"}, remnants.Remotes)
+
+	remnants, err = f.findHostRemnants(sshConfigPath, "", map[string]bool{"dev-live": true, "dev-gone": true})
+	require.NoError(t, err)
+	assert.Empty(t, remnants.SSHHosts)
+	assert.Empty(t, remnants.Remotes)
+}
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/juju/ansiterm"
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+	"l8s/pkg/container"
+)
+
+// groupMembers returns the containers in a group, sorted by name
+func (f *CommandFactory) groupMembers(ctx context.Context, group string) ([]*container.Container, error) {
+	containers, err := f.ContainerMgr.ListContainers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	members := filterContainers(containers, []listFilter{{key: "group", value: group}})
+	if len(members) == 0 {
+		return nil, fmt.Errorf("group '%s' has no containers\nCreate it with: l8s group create %s <name...>", group, group)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+	return members, nil
+}
+
+// runGroupCreate puts containers into a group, moving them out of any other
+func (f *CommandFactory) runGroupCreate(cmd *cobra.Command, args []string) error {
+	group := args[0]
+	if !labelKeyPattern.MatchString(group) {
+		return fmt.Errorf("invalid group name '%s': use letters, digits, '.', '_' or '-'", group)
+	}
+
+	ctx := context.Background()
+	names := make([]string, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		name := strings.TrimPrefix(arg, f.Config.ContainerPrefix+"-")
+		c, err := f.ContainerMgr.GetContainerInfo(ctx, name)
+		if err != nil {
+			return fmt.Errorf("container '%s' not found: %w", name, err)
+		}
+		switch current := c.Labels[container.LabelGroup]; current {
+		case group:
+			color.Printf("{dim}%s is already in %s{reset}\n", c.Name, group)
+			continue
+		case "":
+		default:
+			color.Printf("{yellow}!{reset} Moving %s from group %s\n", c.Name, current)
+		}
+		names = append(names, name)
+	}
+
+	set := map[string]string{container.LabelGroup: group}
+	for _, name := range names {
+		color.Printf("{cyan}→{reset} Recreating {bold}%s-%s{reset} to update labels (volumes and ports are kept)...\n", f.Config.ContainerPrefix, name)
+		if err := f.ContainerMgr.UpdateLabels(ctx, name, set, nil); err != nil {
+			return fmt.Errorf("failed to add %s to group: %w", name, err)
+		}
+	}
+	color.Printf("{green}✓{reset} Group {bold}%s{reset} has %d new member(s)\n", group, len(names))
+	return nil
+}
+
+// runGroupRemove takes containers out of a group, or dissolves the group
+// when no containers are named. The containers themselves are kept.
+func (f *CommandFactory) runGroupRemove(cmd *cobra.Command, args []string) error {
+	group := args[0]
+	ctx := context.Background()
+
+	members, err := f.groupMembers(ctx, group)
+	if err != nil {
+		return err
+	}
+	names := f.shortNames(members)
+	if len(args) > 1 {
+		inGroup := make(map[string]bool, len(names))
+		for _, name := range names {
+			inGroup[name] = true
+		}
+		names = names[:0]
+		for _, arg := range args[1:] {
+			name := strings.TrimPrefix(arg, f.Config.ContainerPrefix+"-")
+			if !inGroup[name] {
+				return fmt.Errorf("%s-%s is not in group %s", f.Config.ContainerPrefix, name, group)
+			}
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		color.Printf("{cyan}→{reset} Recreating {bold}%s-%s{reset} to update labels (volumes and ports are kept)...\n", f.Config.ContainerPrefix, name)
+		if err := f.ContainerMgr.UpdateLabels(ctx, name, nil, []string{container.LabelGroup}); err != nil {
+			return fmt.Errorf("failed to remove %s from group: %w", name, err)
+		}
+	}
+	color.Printf("{green}✓{reset} Removed %d container(s) from group {bold}%s{reset}\n", len(names), group)
+	return nil
+}
+
+// runGroupList shows every group and its members
+func (f *CommandFactory) runGroupList(cmd *cobra.Command, args []string) error {
+	containers, err := f.ContainerMgr.ListContainers(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	groups := make(map[string][]string)
+	for _, c := range containers {
+		if group := c.Labels[container.LabelGroup]; group != "" {
+			groups[group] = append(groups[group], strings.TrimPrefix(c.Name, f.Config.ContainerPrefix+"-"))
+		}
+	}
+	if len(groups) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No groups defined")
+		return nil
+	}
+
+	names := make([]string, 0, len(groups))
+	for group := range groups {
+		names = append(names, group)
+	}
+	sort.Strings(names)
+
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	if os.Getenv("NO_COLOR") == "" {
+		fmt.Fprintf(w, "%s\t%s\n", color.Bold("GROUP"), color.Bold("CONTAINERS"))
+	} else {
+		fmt.Fprintln(w, "GROUP\tCONTAINERS")
+	}
+	for _, group := range names {
+		sort.Strings(groups[group])
+		fmt.Fprintf(w, "%s\t%s\n", group, strings.Join(groups[group], ", "))
+	}
+	return w.Flush()
+}
+
+// runGroupStartStop starts or stops every container in a group
+func (f *CommandFactory) runGroupStartStop(cmd *cobra.Command, args []string, start bool) error {
+	ctx := context.Background()
+	members, err := f.groupMembers(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	verb, op := "stopped", f.ContainerMgr.StopContainer
+	if start {
+		verb, op = "started", f.ContainerMgr.StartContainer
+	}
+	var names []string
+	for _, c := range members {
+		if (c.Status == "running") != start {
+			names = append(names, strings.TrimPrefix(c.Name, f.Config.ContainerPrefix+"-"))
+		}
+	}
+	if len(names) == 0 {
+		color.Printf("{green}✓{reset} All containers in %s are already %s\n", args[0], verb)
+		return nil
+	}
+
+	parallel, _ := cmd.Flags().GetInt("parallel")
+	results := runBulk(names, parallel, func(name string) (string, error) {
+		return "", op(ctx, name)
+	}, nil)
+	return printBulkSummary(cmd, verb, results)
+}
+
+// runGroupStatus shows the state and ports of every container in a group
+func (f *CommandFactory) runGroupStatus(cmd *cobra.Command, args []string) error {
+	members, err := f.groupMembers(context.Background(), args[0])
+	if err != nil {
+		return err
+	}
+
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	if os.Getenv("NO_COLOR") == "" {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", color.Bold("NAME"), color.Bold("STATUS"), color.Bold("SSH PORT"), color.Bold("WEB PORT"))
+	} else {
+		fmt.Fprintln(w, "NAME\tSTATUS\tSSH PORT\tWEB PORT")
+	}
+	running := 0
+	for _, c := range members {
+		if c.Status == "running" {
+			running++
+		}
+		webPort := "-"
+		if c.WebPort > 0 {
+			webPort = fmt.Sprintf("%d", c.WebPort)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", c.Name, formatStatus(c.Status), c.SSHPort, webPort)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "\n%d of %d running\n", running, len(members))
+	return nil
+}
@@ -6,19 +6,51 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/juju/ansiterm"
 	"github.com/spf13/cobra"
 	"l8s/pkg/color"
 	"l8s/pkg/config"
+	"l8s/pkg/container"
 	"l8s/pkg/embed"
+	"l8s/pkg/git"
+	"l8s/pkg/notify"
+	"l8s/pkg/progress"
 	"l8s/pkg/ssh"
 )
 
+// initialPushAttempts is how many times create tries the first push
+const initialPushAttempts = 4
+
+// pushRetryDelay is the wait before the first push retry; it doubles after each attempt
+var pushRetryDelay = 2 * time.Second
+
+// pushWithRetry runs push, retrying with backoff while the container's
+// sshd finishes starting
+func pushWithRetry(push func() error) error {
+	delay := pushRetryDelay
+	var err error
+	for attempt := 1; attempt <= initialPushAttempts; attempt++ {
+		if err = push(); err == nil {
+			return nil
+		}
+		if attempt < initialPushAttempts {
+			color.Printf("{yellow}!{reset} Push attempt %d/%d failed, retrying in %s...\n", attempt, initialPushAttempts, delay)
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	return err
+}
+
 // runCreate handles the create command
 func (f *CommandFactory) runCreate(cmd *cobra.Command, args []string) error {
 	// Get repository root to support running from subdirectories
@@ -36,8 +68,25 @@ func (f *CommandFactory) runCreate(cmd *cobra.Command, args []string) error {
 	// fullName is like "dev-myrepo-a3f2d1", shortName is "myrepo-a3f2d1"
 	shortName := fullName[len(f.Config.ContainerPrefix)+1:]
 
+	// A ticket names the container and the branch
+	ticket, _ := cmd.Flags().GetString("ticket")
+	if ticket != "" {
+		if err := f.Config.Ticket.Check(ticket); err != nil {
+			return err
+		}
+		repoName, err := git.GetRepositoryName(repoRoot)
+		if err != nil {
+			return fmt.Errorf("failed to get repository name: %w", err)
+		}
+		shortName = TicketContainerName(repoName, ticket)
+		fullName = f.Config.ContainerPrefix + "-" + shortName
+	}
+
+	// Ctrl-C cancels the create, which then removes what it made so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Check if container already exists
-	ctx := context.Background()
 	existingContainer, err := f.ContainerMgr.GetContainerInfo(ctx, shortName)
 	if err == nil && existingContainer != nil {
 		return fmt.Errorf("container '%s' already exists for this worktree\nUse 'l8s ssh' to connect or 'l8s rm' to remove it first", fullName)
@@ -45,6 +94,12 @@ func (f *CommandFactory) runCreate(cmd *cobra.Command, args []string) error {
 
 	// Get branch from flag or use current branch
 	branch, _ := cmd.Flags().GetString("branch")
+	newBranch := false
+	if branch == "" && ticket != "" {
+		branch = f.Config.Ticket.BranchFor(ticket)
+		_, err := f.GitClient.ResolveRef(repoRoot, "refs/heads/"+branch)
+		newBranch = err != nil
+	}
 	if branch == "" {
 		currentBranch, err := f.GitClient.GetCurrentBranch(repoRoot)
 		if err != nil {
@@ -52,21 +107,16 @@ func (f *CommandFactory) runCreate(cmd *cobra.Command, args []string) error {
 		}
 		branch = currentBranch
 	}
+	// The branch reaches a shell in the container, and ticket ids and
+	// --branch come from the user
+	if err := git.ValidateBranchName(branch); err != nil {
+		return err
+	}
 
 	// Find SSH key
-	sshKey := f.Config.SSHPublicKey
-	if sshKey == "" {
-		key, err := f.SSHClient.FindSSHPublicKey()
-		if err != nil {
-			return fmt.Errorf("no SSH public key found in ~/.ssh/")
-		}
-		sshKey = key
-	} else {
-		key, err := f.SSHClient.ReadPublicKey(sshKey)
-		if err != nil {
-			return fmt.Errorf("failed to read SSH public key: %w", err)
-		}
-		sshKey = key
+	sshKey, err := f.localPublicKey()
+	if err != nil {
+		return err
 	}
 
 	// Validate SSH key
@@ -74,12 +124,66 @@ func (f *CommandFactory) runCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid SSH public key: %w", err)
 	}
 
+	// Settings for this container from the repository and the flags
+	repoConfig, err := config.LoadRepoConfig(repoRoot)
+	if err != nil {
+		return err
+	}
+	dotfilesPath, _ := cmd.Flags().GetString("dotfiles-path")
+	opts := container.CreateOptions{
+		DotfilesPath: dotfilesPath,
+		Mounts:       containerMounts(repoConfig.Mounts),
+		Ticket:       ticket,
+		Worktree:     repoRoot,
+		GitIdentity:  container.GitIdentity{Name: repoConfig.Git.Name, Email: repoConfig.Git.Email},
+		Packages:     containerPackages(repoConfig.Packages),
+		Toolchain:    repoConfig.RepoToolchain(repoRoot),
+	}
+	if !opts.Packages.Empty() {
+		color.Printf("{cyan}→{reset} Packages from %s will be installed: {bold}%s{reset}\n", config.RepoConfigFile, opts.Packages)
+	}
+	if variant, _ := cmd.Flags().GetString("image-variant"); variant != "" {
+		if err := checkImageVariant(f.Config, variant); err != nil {
+			return err
+		}
+		opts.ImageVariant = variant
+		color.Printf("{cyan}→{reset} Using image variant {bold}%s{reset} (%s)\n", variant, f.Config.VariantImage(variant))
+	}
+
+	// Runtime flags override the configured defaults for this container
+	runtimeOpts, changed, err := f.createRuntimeOptions(cmd)
+	if err != nil {
+		return err
+	}
+	if changed {
+		opts.Runtime = &runtimeOpts
+	}
+	if opts.Subdir, err = createSubdir(cmd, repoRoot); err != nil {
+		return err
+	}
+	if opts.Sparse, err = createSparseDirs(cmd, repoRoot); err != nil {
+		return err
+	}
+	opts.OriginURL, opts.OriginAuth = f.createOrigin(cmd, repoRoot)
+	if opts.Signing, opts.SigningKey, err = f.createSigning(); err != nil {
+		return err
+	}
+
 	// Create container with empty git URL
 	color.Printf("🎳 {cyan}Creating container:{reset} {bold}%s{reset}\n", fullName)
 
-	cont, err := f.ContainerMgr.CreateContainer(ctx, shortName, sshKey)
+	cont, err := f.ContainerMgr.CreateContainer(ctx, shortName, sshKey, opts)
 	if err != nil {
-		return err
+		return withQuotaHint(err, f.Config.ActiveConnection)
+	}
+
+	// The ticket's branch starts at HEAD; created only now so a failed create leaves no trace
+	if newBranch {
+		if err := f.GitClient.CreateBranch(repoRoot, branch, "HEAD"); err != nil {
+			_ = f.ContainerMgr.RemoveContainer(context.WithoutCancel(ctx), shortName, true)
+			return err
+		}
+		color.Printf("{green}✓{reset} Created branch {bold}%s{reset} at HEAD\n", branch)
 	}
 
 	// Add git remote to local repository
@@ -88,29 +192,53 @@ func (f *CommandFactory) runCreate(cmd *cobra.Command, args []string) error {
 		// If we fail to add the remote, try to clean up the container
 		color.Printf("{red}✗{reset} Failed to add git remote: %v\n", err)
 		color.Printf("{yellow}!{reset} Cleaning up container...\n")
-		_ = f.ContainerMgr.RemoveContainer(ctx, shortName, true)
+		_ = f.ContainerMgr.RemoveContainer(context.WithoutCancel(ctx), shortName, true)
 		return fmt.Errorf("failed to add git remote: %w", err)
 	}
+	f.recordRepo(shortName, repoRoot)
 
-	// Push the branch to the container
-	color.Printf("{cyan}→{reset} Pushing {bold}%s{reset} branch to container...\n", branch)
-	if err := f.GitClient.PushBranch(repoRoot, branch, shortName, false); err != nil {
-		// If push fails, clean up remote but keep container (user might want to debug)
-		color.Printf("{red}✗{reset} Failed to push code: %v\n", err)
-		_ = f.GitClient.RemoveRemote(repoRoot, shortName)
-		color.Printf("{yellow}!{reset} Container created but code push failed\n")
-		color.Printf("{yellow}!{reset} You may need to manually push or remove the container\n")
-		return fmt.Errorf("failed to push initial code: %w", err)
+	skipPush, _ := cmd.Flags().GetBool("skip-push")
+	var op *progress.Operation
+	if f.Config.Runtime == config.RuntimeFake {
+		// There is no sshd to push to
+		skipPush = true
+		color.Printf("{yellow}!{reset} Fake runtime: skipping initial push\n")
+	} else if skipPush {
+		color.Printf("{yellow}!{reset} Skipping initial push; the container has an empty repository\n")
+	} else {
+		// Push the branch to the container once sshd is accepting connections
+		op = progress.Start("push", 2)
+		op.Step("push", "Pushing "+branch)
+		if err := f.ContainerMgr.WaitForSSH(ctx, shortName); err != nil {
+			color.Printf("{yellow}!{reset} SSH is not ready yet, pushing anyway: %v\n", err)
+		}
+		push := func() error { return f.GitClient.PushBranch(repoRoot, branch, shortName, false) }
+		if shallow, _ := cmd.Flags().GetInt("shallow"); shallow > 0 {
+			color.Printf("{cyan}→{reset} Pushing last {bold}%d{reset} commit(s) of {bold}%s{reset} to container...\n", shallow, branch)
+			push = func() error { return f.GitClient.PushShallow(repoRoot, branch, shortName, shallow) }
+		} else {
+			color.Printf("{cyan}→{reset} Pushing {bold}%s{reset} branch to container...\n", branch)
+		}
+		if err := pushWithRetry(push); err != nil {
+			op.Done(err)
+			// If push fails, clean up remote but keep container (user might want to debug)
+			color.Printf("{red}✗{reset} Failed to push code: %v\n", err)
+			_ = f.GitClient.RemoveRemote(repoRoot, shortName)
+			color.Printf("{yellow}!{reset} Container created but code push failed\n")
+			color.Printf("{yellow}!{reset} You may need to manually push or remove the container\n")
+			return fmt.Errorf("failed to push initial code: %w", err)
+		}
 	}
 
 	// Replicate origin remote to container if it exists in host repo
-	// This enables GitHub CLI (gh) to work automatically
+	// This enables GitHub CLI (gh) to work automatically. A mirrored origin
+	// was already added with its credentials when the repository was set up.
 	hostRemotes, err := f.GitClient.ListRemotes(repoRoot)
-	if err == nil {
-		if originURL, exists := hostRemotes["origin"]; exists {
+	if err == nil && opts.OriginURL == "" {
+		if hostOrigin, exists := hostRemotes["origin"]; exists {
 			color.Printf("{cyan}→{reset} Adding origin remote to container for GitHub CLI support...\n")
 			addRemoteCmd := []string{"su", "-", f.Config.ContainerUser, "-c",
-				fmt.Sprintf("cd /workspace/project && git remote add origin %s", originURL)}
+				fmt.Sprintf("cd /workspace/project && git remote add origin %s", hostOrigin)}
 			if err := f.ContainerMgr.ExecContainer(ctx, shortName, addRemoteCmd); err != nil {
 				// Non-fatal - gh CLI just won't work automatically
 				color.Printf("{yellow}!{reset} Could not add origin remote to container (gh CLI may require -R flag)\n")
@@ -119,12 +247,48 @@ func (f *CommandFactory) runCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Checkout the branch in the container so it matches what we pushed
-	color.Printf("{cyan}→{reset} Checking out {bold}%s{reset} branch in container...\n", branch)
-	checkoutCmd := []string{"su", "-", f.Config.ContainerUser, "-c",
-		fmt.Sprintf("cd /workspace/project && git checkout %s", branch)}
-	if err := f.ContainerMgr.ExecContainer(ctx, shortName, checkoutCmd); err != nil {
-		// Non-fatal, but warn the user
-		color.Printf("{yellow}!{reset} Warning: Failed to checkout branch in container: %v\n", err)
+	if !skipPush {
+		op.Step("checkout", "Checking out "+branch)
+		color.Printf("{cyan}→{reset} Checking out {bold}%s{reset} branch in container...\n", branch)
+		checkoutCmd := []string{"su", "-", f.Config.ContainerUser, "-c",
+			"cd /workspace/project && git checkout "+container.ShellQuote(branch)}
+		if err := f.ContainerMgr.ExecContainer(ctx, shortName, checkoutCmd); err != nil {
+			// Non-fatal, but warn the user
+			color.Printf("{yellow}!{reset} Warning: Failed to checkout branch in container: %v\n", err)
+		}
+		op.Done(nil)
+	}
+
+	// The toolchain reads its definition from the checked out repository
+	if opts.Toolchain != "" && !skipPush {
+		color.Printf("{cyan}→{reset} Setting up the {bold}%s{reset} environment (the first run can take a while)...\n", opts.Toolchain)
+		setupCmd := []string{"su", "-", f.Config.ContainerUser, "-c", container.ToolchainSetupScript(opts.Toolchain)}
+		if err := f.ContainerMgr.ExecContainer(ctx, shortName, setupCmd); err != nil {
+			color.Printf("{yellow}!{reset} Could not set up the %s environment: %v\n", opts.Toolchain, err)
+		} else {
+			color.Printf("{green}✓{reset} %s environment ready\n", opts.Toolchain)
+		}
+	}
+
+	// Prove commits made in the container will be signed
+	if opts.Signing.Mode != "" && f.Config.Runtime != config.RuntimeFake {
+		if skipPush {
+			if err := f.ContainerMgr.WaitForSSH(ctx, shortName); err != nil {
+				color.Printf("{yellow}!{reset} SSH is not ready yet: %v\n", err)
+			}
+		}
+		if err := f.verifySigning(ctx, shortName); err != nil {
+			color.Printf("{yellow}!{reset} Commit signing is configured but a test commit could not be signed: %v\n", err)
+		} else {
+			color.Printf("{green}✓{reset} Commit signing verified ({bold}%s{reset})\n", signingDescription(opts.Signing.Mode))
+		}
+	}
+
+	// Keep 'l8s ssh' and friends in this worktree pointed at the ticket's container
+	if ticket != "" {
+		if err := git.SetWorktreeContainer(repoRoot, shortName); err != nil {
+			color.Printf("{yellow}!{reset} Could not link this worktree to %s: %v\n", fullName, err)
+		}
 	}
 
 	// Display success message
@@ -132,6 +296,23 @@ func (f *CommandFactory) runCreate(cmd *cobra.Command, args []string) error {
 	color.Printf("{green}✓{reset} Git remote '{bold}%s{reset}' added\n", shortName)
 	color.Printf("{green}✓{reset} Pushed {bold}%s{reset} branch (HEAD: %s) to container\n", branch, getShortCommitHash())
 	color.Printf("{green}✓{reset} Container ready with your code\n")
+	if ticket != "" {
+		color.Printf("{green}✓{reset} Ticket: {bold}%s{reset}%s\n", ticket, ticketLinkSuffix(f.Config.Ticket.LinkFor(ticket)))
+	}
+	if opts.GitIdentity != (container.GitIdentity{}) {
+		color.Printf("{green}✓{reset} Git identity: {bold}%s{reset} (from %s)\n", formatGitIdentity(opts.GitIdentity), config.RepoConfigFile)
+	}
+	if opts.OriginURL != "" {
+		color.Printf("{green}✓{reset} Origin: {bold}%s{reset} (%s)\n", opts.OriginURL, originAuthDescription(opts.OriginAuth))
+	}
+	if dirs := append(nonEmpty(opts.Subdir), opts.Sparse...); len(dirs) > 0 {
+		color.Printf("{green}✓{reset} Working tree limited to {bold}%s{reset} (sparse checkout)\n", strings.Join(dirs, ", "))
+	}
+	f.notifyEvent(notify.EventCreate, shortName)
+	f.syncIngress()
+	if in, err := f.activeIngress(); err == nil && in.Enabled {
+		color.Printf("{green}✓{reset} Web: {bold}%s{reset}\n", ingressURL(in, shortName))
+	}
 
 	color.Printf("\n{cyan}Connection options:{reset}\n")
 	color.Printf("- {bold}l8s ssh{reset} (from this worktree)\n")
@@ -142,6 +323,111 @@ func (f *CommandFactory) runCreate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// createSubdir returns the cleaned --subdir of create, checking that it is a
+// directory of the repository
+func createSubdir(cmd *cobra.Command, repoRoot string) (string, error) {
+	flag, _ := cmd.Flags().GetString("subdir")
+	return repoDir(repoRoot, flag)
+}
+
+// createSparseDirs returns the cleaned --sparse directories of create
+func createSparseDirs(cmd *cobra.Command, repoRoot string) ([]string, error) {
+	flags, _ := cmd.Flags().GetStringArray("sparse")
+	var dirs []string
+	for _, flag := range flags {
+		dir, err := repoDir(repoRoot, flag)
+		if err != nil {
+			return nil, err
+		}
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, nil
+}
+
+// createOrigin returns the host repository's origin URL and how the container
+// authenticates with it when create should mirror it, or "" otherwise.
+// Shallow containers mirror it by default, since 'l8s fetch' deepens their
+// history from origin.
+func (f *CommandFactory) createOrigin(cmd *cobra.Command, repoRoot string) (string, string) {
+	mirror := f.Config.Origin.Mirror
+	if shallow, _ := cmd.Flags().GetInt("shallow"); shallow > 0 {
+		mirror = true
+	}
+	if cmd.Flags().Changed("origin") {
+		mirror, _ = cmd.Flags().GetBool("origin")
+	}
+	if noOrigin, _ := cmd.Flags().GetBool("no-origin"); noOrigin {
+		mirror = false
+	}
+	if !mirror {
+		return "", ""
+	}
+	remotes, err := f.GitClient.ListRemotes(repoRoot)
+	if err != nil || remotes["origin"] == "" {
+		color.Printf("{yellow}!{reset} This repository has no origin remote to mirror\n")
+		return "", ""
+	}
+	url := remotes["origin"]
+	auth := f.Config.Origin.AuthFor(url)
+	switch {
+	case auth == config.OriginAuthToken && f.Config.GitHubToken == "":
+		color.Printf("{yellow}!{reset} origin uses token credentials but github_token is not set; fetching private repositories will fail\n")
+	case auth == config.OriginAuthAgent && os.Getenv("SSH_AUTH_SOCK") == "":
+		color.Printf("{yellow}!{reset} origin uses the forwarded SSH agent but no agent is running (SSH_AUTH_SOCK is unset)\n")
+	}
+	return url, auth
+}
+
+// formatGitIdentity renders an identity override as "Name <email>", leaving
+// out the parts kept from the host
+func formatGitIdentity(identity container.GitIdentity) string {
+	switch {
+	case identity.Name == "":
+		return "<" + identity.Email + ">"
+	case identity.Email == "":
+		return identity.Name
+	}
+	return identity.Name + " <" + identity.Email + ">"
+}
+
+// originAuthDescription explains a config.OriginAuth* mode
+func originAuthDescription(auth string) string {
+	if auth == config.OriginAuthToken {
+		return "credentials from github_token"
+	}
+	return "credentials from the forwarded SSH agent"
+}
+
+// repoDir cleans a repository relative directory, checking that it exists
+func repoDir(repoRoot, dir string) (string, error) {
+	cleaned, err := container.CleanSubdir(dir)
+	if err != nil || cleaned == "" {
+		return "", err
+	}
+	if info, err := os.Stat(filepath.Join(repoRoot, filepath.FromSlash(cleaned))); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("'%s' is not a directory in the repository", cleaned)
+	}
+	return cleaned, nil
+}
+
+// nonEmpty returns s as a one element slice, or nil if it is empty
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
+
+// ticketLinkSuffix formats a ticket URL to follow the ticket ID
+func ticketLinkSuffix(link string) string {
+	if link == "" {
+		return ""
+	}
+	return " (" + link + ")"
+}
+
 // getShortCommitHash returns the short commit hash of HEAD
 func getShortCommitHash() string {
 	cmd := exec.Command("git", "rev-parse", "--short", "HEAD")
@@ -167,20 +453,95 @@ func (f *CommandFactory) runSSH(cmd *cobra.Command, args []string) error {
 	// Remove prefix for the short name
 	shortName := fullName[len(f.Config.ContainerPrefix)+1:]
 
+	localForwards, _ := cmd.Flags().GetStringArray("local-forward")
+	remoteForwards, _ := cmd.Flags().GetStringArray("remote-forward")
+	dynamicForwards, _ := cmd.Flags().GetStringArray("dynamic-forward")
+	tty, _ := cmd.Flags().GetBool("tty")
+
+	opts := container.SSHOptions{
+		LocalForwards:   localForwards,
+		RemoteForwards:  remoteForwards,
+		DynamicForwards: dynamicForwards,
+		Command:         args,
+		TTY:             tty,
+	}
+
 	ctx := context.Background()
-	return f.ContainerMgr.SSHIntoContainer(ctx, shortName)
+	return f.ContainerMgr.SSHIntoContainer(ctx, shortName, opts)
 }
 
 // runList handles the list command
 func (f *CommandFactory) runList(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-	containers, err := f.ContainerMgr.ListContainers(ctx)
+	filterSpecs, _ := cmd.Flags().GetStringArray("filter")
+	sortKey, _ := cmd.Flags().GetString("sort")
+	wide, _ := cmd.Flags().GetBool("wide")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	mine, _ := cmd.Flags().GetBool("mine")
+	allConnections, _ := cmd.Flags().GetBool("all-connections")
+	if owner, _ := cmd.Flags().GetString("owner"); owner != "" {
+		filterSpecs = append(filterSpecs, "owner="+owner)
+	}
+
+	filters, err := parseListFilters(filterSpecs)
 	if err != nil {
 		return err
 	}
 
+	ctx := context.Background()
+	var listings []connectionListing
+	if allConnections {
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		listings = f.listConnections(ctx, timeout, func(name string) (ContainerManager, error) {
+			return newConnectionManager(f.Config, name)
+		})
+	} else {
+		containers, err := f.ContainerMgr.ListContainers(ctx)
+		if err != nil {
+			return err
+		}
+		listings = []connectionListing{{name: f.Config.ActiveConnection, f: f, containers: containers}}
+	}
+
+	// from maps each container to the factory for its connection
+	from := make(map[*container.Container]*CommandFactory)
+	var containers []*container.Container
+	var failed []connectionListing
+	for _, l := range listings {
+		if l.err != nil {
+			failed = append(failed, l)
+			continue
+		}
+		for _, c := range filterContainers(l.containers, filters) {
+			if mine && !l.f.isMine(c) {
+				continue
+			}
+			from[c] = l.f
+			containers = append(containers, c)
+		}
+	}
+	if len(failed) > 0 && len(failed) == len(listings) {
+		return fmt.Errorf("no connection answered: %s", formatConnectionErrors(failed))
+	}
+	if err := sortContainers(containers, sortKey); err != nil {
+		return err
+	}
+
+	// Names only, for scripting
+	if quiet {
+		for _, c := range containers {
+			fmt.Fprintln(cmd.OutOrStdout(), c.Name)
+		}
+		printConnectionErrors(failed)
+		return nil
+	}
+
 	if len(containers) == 0 {
-		fmt.Fprintln(cmd.OutOrStdout(), "No l8s containers found")
+		if len(filters) > 0 || mine {
+			fmt.Fprintln(cmd.OutOrStdout(), "No containers match the filter")
+		} else {
+			fmt.Fprintln(cmd.OutOrStdout(), "No l8s containers found")
+		}
+		printConnectionErrors(failed)
 		return nil
 	}
 
@@ -192,27 +553,52 @@ func (f *CommandFactory) runList(cmd *cobra.Command, args []string) error {
 	if root, err := f.GitClient.GetRepositoryRoot("."); err == nil {
 		repoRoot = root
 	}
+	remotes, _ := f.GitClient.ListRemotes(repoRoot)
 
 	// Create color-aware table writer using juju/ansiterm
 	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
 
+	// Idle status is looked up on each container's own connection
+	idle := make(map[*container.Container]string, len(containers))
+	for _, l := range listings {
+		if l.err != nil {
+			continue
+		}
+		var own []*container.Container
+		for _, c := range l.containers {
+			if from[c] != nil {
+				own = append(own, c)
+			}
+		}
+		cells := l.f.idleColumns(ctx, own)
+		for _, c := range own {
+			idle[c] = cells[c.Name]
+		}
+	}
+
+	// Wide output already has a CONNECTION column
+	connectionColumn := allConnections && !wide
+
+	headers := []string{"", "NAME", "STATUS", "IDLE", "SSH PORT", "WEB PORT", "GIT REMOTE", "CREATED"}
+	if connectionColumn {
+		headers = slices.Insert(headers, 2, "CONNECTION")
+	}
+	if wide {
+		headers = append(headers, "IMAGE", "CONNECTION", "OWNER", "HEALTH", "TICKET", "PORTS", "NOTE")
+	}
+
 	// Print header in bold
 	if os.Getenv("NO_COLOR") == "" {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-			color.Bold(""),
-			color.Bold("NAME"),
-			color.Bold("STATUS"),
-			color.Bold("SSH PORT"),
-			color.Bold("WEB PORT"),
-			color.Bold("GIT REMOTE"),
-			color.Bold("CREATED"))
-	} else {
-		fmt.Fprintln(w, "\tNAME\tSTATUS\tSSH PORT\tWEB PORT\tGIT REMOTE\tCREATED")
+		for i, h := range headers {
+			headers[i] = color.Bold("%s", h)
+		}
 	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
 
 	for _, c := range containers {
+		cf := from[c]
+
 		// Check if git remote exists for this container
-		remotes, _ := f.GitClient.ListRemotes(repoRoot)
 		containerName := strings.TrimPrefix(c.Name, f.Config.ContainerPrefix+"-")
 		_, hasRemote := remotes[containerName]
 		gitRemote := formatGitStatus(hasRemote)
@@ -222,7 +608,8 @@ func (f *CommandFactory) runList(cmd *cobra.Command, args []string) error {
 
 		// Mark the current worktree's container with an arrow
 		marker := " "
-		if expectedContainerName != "" && c.Name == expectedContainerName {
+		if expectedContainerName != "" && c.Name == expectedContainerName &&
+			cf.Config.ActiveConnection == f.Config.ActiveConnection {
 			marker = "→"
 		}
 
@@ -231,15 +618,23 @@ func (f *CommandFactory) runList(cmd *cobra.Command, args []string) error {
 			webPort = fmt.Sprintf("%d", c.WebPort)
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n",
+		row := []string{
 			marker,
 			c.Name,
 			status,
-			c.SSHPort,
+			idle[c],
+			fmt.Sprintf("%d", c.SSHPort),
 			webPort,
 			gitRemote,
 			created,
-		)
+		}
+		if connectionColumn {
+			row = slices.Insert(row, 2, cf.Config.ActiveConnection)
+		}
+		if wide {
+			row = append(row, cf.wideColumns(ctx, c)...)
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
 	}
 
 	if expectedContainerName != "" {
@@ -252,6 +647,7 @@ func (f *CommandFactory) runList(cmd *cobra.Command, args []string) error {
 	if err := w.Flush(); err != nil {
 		return err
 	}
+	printConnectionErrors(failed)
 
 	// Show audio tunnel status
 	fmt.Fprintln(cmd.OutOrStdout())
@@ -266,6 +662,9 @@ func (f *CommandFactory) runList(cmd *cobra.Command, args []string) error {
 
 // runStart handles the start command
 func (f *CommandFactory) runStart(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 || isBulk(cmd, args) {
+		return f.runBulkStartStop(cmd, args, true)
+	}
 	name := args[0]
 
 	ctx := context.Background()
@@ -280,6 +679,9 @@ func (f *CommandFactory) runStart(cmd *cobra.Command, args []string) error {
 
 // runStop handles the stop command
 func (f *CommandFactory) runStop(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 || isBulk(cmd, args) {
+		return f.runBulkStartStop(cmd, args, false)
+	}
 	name := args[0]
 
 	ctx := context.Background()
@@ -294,28 +696,48 @@ func (f *CommandFactory) runStop(cmd *cobra.Command, args []string) error {
 
 // runRemove handles the remove command
 func (f *CommandFactory) runRemove(cmd *cobra.Command, args []string) error {
-	// Check if we're in a git repository
-	if !f.GitClient.IsGitRepository(".") {
-		return fmt.Errorf("l8s remove must be run from within a git repository\nThis command requires a git worktree to determine the target container.")
+	target, _ := cmd.Flags().GetString("name")
+	if target != "" && (len(args) > 0 || isBulk(cmd, args)) {
+		return fmt.Errorf("--name cannot be combined with container names, --all or --filter")
 	}
 
-	// Generate container name from worktree
-	fullName, err := GetContainerNameFromWorktree(f.Config.ContainerPrefix)
+	// Named containers, --all and --filter bypass the worktree
+	if len(args) > 0 || isBulk(cmd, args) {
+		return f.runBulkRemove(cmd, args)
+	}
+
+	name, err := f.removeTarget(target)
 	if err != nil {
-		return fmt.Errorf("failed to determine container: %w", err)
+		return err
 	}
-	// Remove prefix for the short name
-	name := fullName[len(f.Config.ContainerPrefix)+1:]
+	inRepo := f.GitClient.IsGitRepository(".")
 
 	// Get flags
 	force, _ := cmd.Flags().GetBool("force")
 	keepVolumes, _ := cmd.Flags().GetBool("keep-volumes")
+	trash := f.Config.RemoveToTrash
+	if cmd.Flags().Changed("trash") {
+		trash, _ = cmd.Flags().GetBool("trash")
+	}
+	trash = trash && !keepVolumes
+
+	ctx := context.Background()
+
+	// Refuse to destroy work that exists only inside the container
+	forceProtected, _ := cmd.Flags().GetBool("force-protected")
+	guard := f.removeGuard(force, forceProtected, keepVolumes || trash)
+	guard.SkipUnsaved, _ = cmd.Flags().GetBool("skip-unsaved-check")
+	if err := f.checkRemove(ctx, name, guard); err != nil {
+		return err
+	}
 
 	// Confirm removal unless --force is specified
 	if !force {
 		reader := bufio.NewReader(os.Stdin)
 		prompt := fmt.Sprintf("Remove container %s-%s", f.Config.ContainerPrefix, name)
-		if !keepVolumes {
+		if trash {
+			prompt += " (volumes kept in trash)"
+		} else if !keepVolumes {
 			prompt += " and volumes"
 		}
 		prompt += "? (y/N): "
@@ -333,15 +755,33 @@ func (f *CommandFactory) runRemove(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	ctx := context.Background()
-
-	// Remove git remote
-	currentDir, err := os.Getwd()
-	if err == nil {
+	// Remove git remote from the repository it was added to, which need not
+	// be the current one; an orphan removed by --name may have no repository
+	fallback := ""
+	if currentDir, err := os.Getwd(); err == nil && inRepo {
+		fallback = currentDir
+	}
+	if repo := f.remoteRepo(ctx, name, fallback); repo != "" {
 		// Try to remove remote, but don't fail if it doesn't exist
-		_ = f.GitClient.RemoveRemote(currentDir, name)
+		_ = f.GitClient.RemoveRemote(repo, name)
 		color.Printf("{green}✓{reset} Git remote removed\n")
 	}
+	f.forgetRepo(name)
+	f.forgetProtection(name)
+
+	// Move to trash instead of deleting volumes
+	if trash {
+		if err := f.ContainerMgr.TrashContainer(ctx, name); err != nil {
+			return err
+		}
+		color.Printf("{green}✓{reset} Container removed\n")
+		color.Printf("{green}✓{reset} Volumes moved to trash (restore with 'l8s undo-remove %s')\n", name)
+		unpinWorktree(name)
+		f.notifyEvent(notify.EventRemove, name)
+		f.syncIngress()
+		f.purgeExpiredTrash(ctx)
+		return nil
+	}
 
 	// Remove container
 	removeVolumes := !keepVolumes
@@ -351,6 +791,9 @@ func (f *CommandFactory) runRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	color.Printf("{green}✓{reset} Container removed\n")
+	unpinWorktree(name)
+	f.notifyEvent(notify.EventRemove, name)
+	f.syncIngress()
 	if removeVolumes {
 		color.Printf("{green}✓{reset} Volumes removed\n")
 	} else {
@@ -360,6 +803,29 @@ func (f *CommandFactory) runRemove(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// removeTarget resolves the short name of the container a single remove
+// applies to: the --name value when given, otherwise the current worktree's
+// container. --name works outside a git repository so containers whose
+// worktree is gone can still be cleaned up.
+func (f *CommandFactory) removeTarget(target string) (string, error) {
+	if target != "" {
+		return strings.TrimPrefix(target, f.Config.ContainerPrefix+"-"), nil
+	}
+
+	// Check if we're in a git repository
+	if !f.GitClient.IsGitRepository(".") {
+		return "", fmt.Errorf("l8s remove must be run from within a git repository\nThis command requires a git worktree to determine the target container.\nUse 'l8s remove --name <container>' to remove a container whose worktree is gone.")
+	}
+
+	// Generate container name from worktree
+	fullName, err := GetContainerNameFromWorktree(f.Config.ContainerPrefix)
+	if err != nil {
+		return "", fmt.Errorf("failed to determine container: %w", err)
+	}
+	// Remove prefix for the short name
+	return fullName[len(f.Config.ContainerPrefix)+1:], nil
+}
+
 // runInfo handles the info command
 func (f *CommandFactory) runInfo(cmd *cobra.Command, args []string) error {
 	name := args[0]
@@ -371,11 +837,49 @@ func (f *CommandFactory) runInfo(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Container: %s\n", cont.Name)
+	if note := cont.Labels[container.LabelNote]; note != "" {
+		fmt.Printf("Note: %s\n", note)
+	}
+	if ticket := cont.Labels[container.LabelTicket]; ticket != "" {
+		fmt.Printf("Ticket: %s%s\n", ticket, ticketLinkSuffix(f.Config.Ticket.LinkFor(ticket)))
+	}
+	if subdir := cont.Labels[container.LabelSubdir]; subdir != "" {
+		fmt.Printf("Subdirectory: %s (sparse checkout)\n", subdir)
+	}
+	if worktree := f.containerRepo(name, cont.Labels); worktree != "" {
+		fmt.Printf("Worktree: %s\n", worktree)
+	}
+	if identity := container.GitIdentityFromLabels(cont.Labels); identity != (container.GitIdentity{}) {
+		fmt.Printf("Git identity: %s (from %s)\n", formatGitIdentity(identity), config.RepoConfigFile)
+	}
+	if mode := cont.Labels[container.LabelSigning]; mode != "" {
+		fmt.Printf("Commit signing: %s\n", signingDescription(mode))
+	}
+	if auth := cont.Labels[container.LabelOrigin]; auth != "" {
+		fmt.Printf("Origin: mirrored, %s\n", originAuthDescription(auth))
+	}
 	fmt.Printf("Status: %s\n", cont.Status)
+	if cont.Status == "running" && !cont.StartedAt.IsZero() {
+		fmt.Printf("Started: %s (up %s)\n", cont.StartedAt.Format(time.RFC3339), formatUptime(time.Since(cont.StartedAt)))
+	}
+	if cont.RestartCount > 0 {
+		fmt.Printf("Restarts: %d\n", cont.RestartCount)
+	}
+	if !cont.FinishedAt.IsZero() {
+		description, crashed := describeLastStop(cont)
+		if crashed {
+			color.Printf("Last Stop: %s {red}(%s){reset}\n", cont.FinishedAt.Format(time.RFC3339), description)
+		} else {
+			fmt.Printf("Last Stop: %s (%s)\n", cont.FinishedAt.Format(time.RFC3339), description)
+		}
+	}
 	fmt.Printf("SSH Port: %d\n", cont.SSHPort)
 	if cont.WebPort > 0 {
 		fmt.Printf("Web Port: %d (container:3000)\n", cont.WebPort)
 	}
+	for _, p := range container.ParsePortMappings(cont.Labels[container.LabelPorts]) {
+		fmt.Printf("Published Port: %s/%s (container:%s)\n", p.HostPorts(), p.Protocol, p.ContainerPorts())
+	}
 	// Check if git remote exists
 	remotes, _ := f.GitClient.ListRemotes(".")
 	containerName := strings.TrimPrefix(cont.Name, f.Config.ContainerPrefix+"-")
@@ -385,6 +889,48 @@ func (f *CommandFactory) runInfo(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Git Remote: (none)\n")
 	}
 	fmt.Printf("Created: %s\n", cont.CreatedAt.Format(time.RFC3339))
+	for _, mount := range container.ParseMounts(cont.Labels[container.LabelMounts]) {
+		fmt.Printf("Mount: %s -> %s (%s)\n", mount.Source, mount.Target, mountMode(mount))
+	}
+	if size, err := strconv.ParseInt(cont.Labels[container.LabelShmSize], 10, 64); err == nil {
+		fmt.Printf("Shm Size: %s\n", formatBytes(size))
+	}
+	for _, t := range container.ParseTmpfs(cont.Labels[container.LabelTmpfs]) {
+		if t.Size > 0 {
+			fmt.Printf("Tmpfs: %s (%s)\n", t.Path, formatBytes(t.Size))
+		} else {
+			fmt.Printf("Tmpfs: %s\n", t.Path)
+		}
+	}
+	if ulimits := cont.Labels[container.LabelUlimits]; ulimits != "" {
+		fmt.Printf("Ulimits: %s\n", ulimits)
+	}
+	if sysctls := cont.Labels[container.LabelSysctls]; sysctls != "" {
+		fmt.Printf("Sysctls: %s\n", sysctls)
+	}
+	if caps := cont.Labels[container.LabelCapAdd]; caps != "" {
+		fmt.Printf("Capabilities: %s\n", caps)
+	}
+	if seccomp := cont.Labels[container.LabelSeccomp]; seccomp != "" {
+		fmt.Printf("Seccomp: %s\n", seccomp)
+	}
+	if selinux := cont.Labels[container.LabelSELinux]; selinux != "" {
+		fmt.Printf("SELinux: %s\n", selinux)
+	}
+	if cont.Labels[container.LabelNested] == "true" {
+		fmt.Printf("Nested Containers: enabled\n")
+	}
+	if cont.Labels[container.LabelSystemd] == "true" {
+		fmt.Printf("Init: systemd\n")
+	}
+	if cont.Status == "running" {
+		if stats, err := f.ContainerMgr.SSHAuthStats(ctx, containerName); err == nil {
+			fmt.Printf("SSH Auth Failures: %d\n", stats.Failures)
+			if stats.Fail2ban {
+				fmt.Printf("Fail2ban: %d banned now, %d total\n", stats.Banned, stats.TotalBanned)
+			}
+		}
+	}
 
 	// Audio tunnel status (global, not per-container)
 	if isAudioTunnelConnected() {
@@ -413,20 +959,127 @@ func (f *CommandFactory) runInfo(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runClone handles the clone command
+func (f *CommandFactory) runClone(cmd *cobra.Command, args []string) error {
+	source := strings.TrimPrefix(args[0], f.Config.ContainerPrefix+"-")
+	name := strings.TrimPrefix(args[1], f.Config.ContainerPrefix+"-")
+	fullName := fmt.Sprintf("%s-%s", f.Config.ContainerPrefix, name)
+
+	ctx := context.Background()
+	color.Printf("🎳 {cyan}Cloning container:{reset} {bold}%s-%s{reset} → {bold}%s{reset}\n", f.Config.ContainerPrefix, source, fullName)
+
+	cont, err := f.ContainerMgr.CloneContainer(ctx, source, name)
+	if err != nil {
+		return withQuotaHint(fmt.Errorf("failed to clone container: %w", err), f.Config.ActiveConnection)
+	}
+
+	color.Printf("{green}✓{reset} SSH port: {bold}%d{reset}\n", cont.SSHPort)
+	if cont.WebPort > 0 {
+		color.Printf("{green}✓{reset} Web port: {bold}%d{reset}\n", cont.WebPort)
+	}
+	f.notifyEvent(notify.EventCreate, name)
+	f.syncIngress()
+
+	// Add a git remote for the clone when run from a repository
+	if repoRoot, err := f.GitClient.GetRepositoryRoot("."); err == nil {
+		remoteURL := fmt.Sprintf("%s:/workspace/project", fullName)
+		if err := f.GitClient.AddRemote(repoRoot, name, remoteURL); err != nil {
+			color.Printf("{yellow}!{reset} Could not add git remote '%s': %v\n", name, err)
+		} else {
+			f.recordRepo(name, repoRoot)
+			color.Printf("{green}✓{reset} Git remote '{bold}%s{reset}' added\n", name)
+		}
+	}
+
+	color.Printf("\n{cyan}Connect with:{reset}\n")
+	color.Printf("- {bold}ssh %s{reset}\n", fullName)
+
+	return nil
+}
+
 // runBuild handles the build command
 func (f *CommandFactory) runBuild(cmd *cobra.Command, args []string) error {
-	fmt.Println("Building l8s base image...")
+	flavor, _ := cmd.Flags().GetString("flavor")
+	if flavor != "" {
+		if _, err := embed.ContainerfileFor(flavor); err != nil {
+			return err
+		}
+		if cm, ok := f.ContainerMgr.(*container.Manager); ok {
+			cm.SetBuildFlavor(flavor)
+		}
+	} else {
+		flavor = f.Config.BaseFlavor
+	}
+	if flavor == "" {
+		flavor = embed.DefaultFlavor
+	}
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	if noCache && refresh {
+		return fmt.Errorf("--no-cache and --refresh are mutually exclusive")
+	}
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	cm, isManager := f.ContainerMgr.(*container.Manager)
+	if isManager {
+		cm.SetBuildOptions(container.BuildOptions{NoCache: noCache, Refresh: refresh, Quiet: quiet})
+	}
+	image := f.Config.BaseImage
+	if variant, _ := cmd.Flags().GetString("variant"); variant != "" {
+		if err := checkImageVariant(f.Config, variant); err != nil {
+			return err
+		}
+		if isManager {
+			cm.SetImageVariant(variant)
+		}
+		image = f.Config.VariantImage(variant)
+		fmt.Printf("Building l8s image variant %s (%s) as %s...\n", variant, flavor, image)
+	} else {
+		fmt.Printf("Building l8s base image (%s)...\n", flavor)
+	}
 
 	ctx := context.Background()
+	start := time.Now()
 	err := f.ContainerMgr.BuildImage(ctx, "") // Empty string since we no longer use containerfile param
 	if err != nil {
 		return err
 	}
 
-	color.Printf("{green}✓{reset} Image built successfully\n")
+	color.Printf("{green}✓{reset} Image built successfully in %s\n", time.Since(start).Round(time.Second))
+	if isManager {
+		stats := cm.LastBuildStats()
+		if stats.Steps > 0 {
+			color.Printf("{dim}%d of %d steps from the layer cache{reset}\n", stats.Cached, stats.Steps)
+		}
+		if stats.LogPath != "" {
+			color.Printf("{dim}Build log: %s{reset}\n", stats.LogPath)
+		}
+	}
+
+	if scanAfter, _ := cmd.Flags().GetBool("scan"); scanAfter {
+		found, err := f.scanImage(cmd, image)
+		if err != nil {
+			return err
+		}
+		if found > 0 {
+			color.Printf("{yellow}!{reset} %d vulnerabilities found in %s\n", found, image)
+		}
+	}
 	return nil
 }
 
+// checkImageVariant returns an error naming the configured variants if
+// variant is not one of them
+func checkImageVariant(cfg *config.Config, variant string) error {
+	if _, ok := cfg.ImageVariants[variant]; ok {
+		return nil
+	}
+	names := config.VariantNames(cfg.ImageVariants)
+	if len(names) == 0 {
+		return fmt.Errorf("unknown image variant '%s': no image_variants are configured", variant)
+	}
+	return fmt.Errorf("unknown image variant '%s', configured: %s", variant, strings.Join(names, ", "))
+}
+
 // runRemoteAdd handles the remote add command
 func (f *CommandFactory) runRemoteAdd(cmd *cobra.Command, args []string) error {
 	name := args[0]
@@ -449,6 +1102,7 @@ func (f *CommandFactory) runRemoteAdd(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	f.recordRepo(name, currentDir)
 
 	color.Printf("{green}✓{reset} Git remote '{bold}%s{reset}' added\n", name)
 	return nil
@@ -476,6 +1130,12 @@ func (f *CommandFactory) runRemoteRemove(cmd *cobra.Command, args []string) erro
 
 // runExec handles the exec command
 func (f *CommandFactory) runExec(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	filterSpecs, _ := cmd.Flags().GetStringArray("filter")
+	if all || len(filterSpecs) > 0 {
+		return f.runExecAll(cmd, args, all, filterSpecs)
+	}
+
 	// Check if we're in a git repository
 	if !f.GitClient.IsGitRepository(".") {
 		return fmt.Errorf("l8s exec must be run from within a git repository\nThis command requires a git worktree to determine the target container.")
@@ -490,10 +1150,83 @@ func (f *CommandFactory) runExec(cmd *cobra.Command, args []string) error {
 	name := fullName[len(f.Config.ContainerPrefix)+1:]
 
 	// The command is all the arguments
-	command := args
+	command := withExecEnv(f.Config.ExecEnv, args)
 
 	ctx := context.Background()
-	return f.ContainerMgr.ExecContainer(ctx, name, command)
+	started := time.Now()
+	err = f.ContainerMgr.ExecContainer(ctx, name, command)
+	f.recordExec(name, args, started, err)
+	return err
+}
+
+// withExecEnv prefixes command with env setting the exec_env variables;
+// unlike ssh sessions, podman exec passes nothing from this machine
+func withExecEnv(entries, command []string) []string {
+	pairs := config.ResolveExecEnv(entries, os.Environ())
+	if len(pairs) == 0 {
+		return command
+	}
+	return append(append([]string{"env"}, pairs...), command...)
+}
+
+// runExecAll runs a command in every running container selected by --all or
+// --filter, prefixing each line of output with the container name
+func (f *CommandFactory) runExecAll(cmd *cobra.Command, args []string, all bool, filterSpecs []string) error {
+	ctx := context.Background()
+	containers, err := f.selectContainers(ctx, all, filterSpecs)
+	if err != nil {
+		return err
+	}
+
+	var running []*container.Container
+	for _, c := range containers {
+		if c.Status == "running" {
+			running = append(running, c)
+		}
+	}
+	if skipped := len(containers) - len(running); skipped > 0 {
+		color.Printf("{dim}Skipping %d container(s) that are not running{reset}\n", skipped)
+	}
+	if len(running) == 0 {
+		return fmt.Errorf("no running containers match")
+	}
+
+	names := f.shortNames(running)
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+
+	parallel, _ := cmd.Flags().GetInt("parallel")
+	out := cmd.OutOrStdout()
+	command := withExecEnv(f.Config.ExecEnv, args)
+	results := runBulk(names, parallel, func(name string) (string, error) {
+		started := time.Now()
+		output, err := f.ContainerMgr.ExecContainerOutput(ctx, name, command)
+		f.recordExec(name, args, started, err)
+		return output, err
+	}, func(r bulkResult) {
+		prefix := fmt.Sprintf("%-*s | ", width, r.name)
+		if os.Getenv("NO_COLOR") == "" {
+			prefix = color.Cyan + prefix + color.Reset
+		}
+		fmt.Fprint(out, prefixLines(prefix, r.output))
+		if r.err != nil {
+			fmt.Fprint(out, prefixLines(prefix, "✗ "+r.err.Error()))
+		}
+	})
+
+	failed := failedResults(results)
+	fmt.Println()
+	if len(failed) == 0 {
+		color.Printf("{green}✓{reset} Succeeded in %d container(s)\n", len(results))
+		return nil
+	}
+	color.Printf("{red}✗{reset} Failed in %d of %d container(s):\n", len(failed), len(results))
+	for _, r := range failed {
+		fmt.Printf("  %s: %v\n", r.name, r.err)
+	}
+	return fmt.Errorf("command failed in %d container(s)", len(failed))
 }
 
 // runPaste handles the paste command
@@ -508,9 +1241,9 @@ func (f *CommandFactory) runPaste(cmd *cobra.Command, args []string) error {
 		customName = args[0]
 	}
 
-	// Check platform - only macOS supported initially
-	if runtime.GOOS != "darwin" {
-		return fmt.Errorf("paste command is currently only supported on macOS")
+	// Check platform - clipboard access is implemented for macOS, Windows and WSL
+	if runtime.GOOS != "darwin" && !usesWindowsClipboard() {
+		return fmt.Errorf("paste command is currently only supported on macOS, Windows and WSL")
 	}
 
 	ctx := context.Background()
@@ -532,13 +1265,58 @@ func (f *CommandFactory) runPaste(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("container '%s' is not running (status: %s)", containerName, cont.Status)
 	}
 
-	// Detect clipboard content type and extract content
-	clipboardType, localPath, err := extractClipboardContent()
+	if watch, _ := cmd.Flags().GetBool("watch"); watch {
+		return f.watchClipboard(cmd, containerName, customName)
+	}
+
+	plain, _ := cmd.Flags().GetBool("plain")
+	clipboardType, content, err := readClipboard(plain)
 	if err != nil {
-		return fmt.Errorf("failed to extract clipboard: %w", err)
+		return err
+	}
+	if limit := f.Config.PasteLimit(); int64(len(content)) > limit {
+		if force, _ := cmd.Flags().GetBool("force"); !force {
+			reader := bufio.NewReader(os.Stdin)
+			fmt.Printf("The clipboard holds %s, more than paste_max_size (%s). Paste anyway? [y/N]: ",
+				formatBytes(int64(len(content))), formatBytes(limit))
+			response, err := reader.ReadString('\n')
+			if err != nil {
+				return err
+			}
+			response = strings.TrimSpace(strings.ToLower(response))
+			if response != "y" && response != "yes" {
+				fmt.Println("Paste cancelled")
+				return nil
+			}
+		}
+	}
+	destPath, err := f.sendClipboard(ctx, containerName, customName, clipboardType, content)
+	if err != nil {
+		return err
+	}
+	color.Printf("{green}✓{reset} Pasted to %s\n", destPath)
+	return nil
+}
+
+// readClipboard returns the host clipboard's content and its type, "files"
+// (a tar archive), "png", "md" or "txt"
+func readClipboard(plain bool) (string, []byte, error) {
+	clipboardType, localPath, err := extractClipboardContent(plain)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to extract clipboard: %w", err)
 	}
 	defer os.Remove(localPath) // Clean up temp file
 
+	content, err := os.ReadFile(localPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read clipboard content: %w", err)
+	}
+	return clipboardType, content, nil
+}
+
+// sendClipboard writes clipboard content to /tmp/claude-clipboard in the
+// container and returns the path it was written to
+func (f *CommandFactory) sendClipboard(ctx context.Context, containerName, customName, clipboardType string, content []byte) (string, error) {
 	// Determine destination filename
 	var destFilename string
 	if customName != "" {
@@ -550,29 +1328,38 @@ func (f *CommandFactory) runPaste(cmd *cobra.Command, args []string) error {
 
 	// Ensure directory exists in container
 	if err := f.ContainerMgr.ExecContainer(ctx, containerName, []string{"mkdir", "-p", "/tmp/claude-clipboard"}); err != nil {
-		return fmt.Errorf("failed to create clipboard directory: %w", err)
+		return "", fmt.Errorf("failed to create clipboard directory: %w", err)
 	}
 
 	// If using default name, remove old default files
 	if customName == "" {
-		// Remove both default files since we're replacing with new one
-		f.ContainerMgr.ExecContainer(ctx, containerName, []string{"rm", "-f", "/tmp/claude-clipboard/clipboard.png"})
-		f.ContainerMgr.ExecContainer(ctx, containerName, []string{"rm", "-f", "/tmp/claude-clipboard/clipboard.txt"})
+		// Remove all default files since we're replacing with new one
+		f.ContainerMgr.ExecContainer(ctx, containerName, []string{"rm", "-rf",
+			"/tmp/claude-clipboard/clipboard.png", "/tmp/claude-clipboard/clipboard.txt",
+			"/tmp/claude-clipboard/clipboard.md", "/tmp/claude-clipboard/files"})
 	}
 
-	// Read local file content
-	content, err := os.ReadFile(localPath)
-	if err != nil {
-		return fmt.Errorf("failed to read clipboard content: %w", err)
+	// Copied files are unpacked into a directory of their own
+	if clipboardType == "files" {
+		destPath = "/tmp/claude-clipboard/files"
+		if customName != "" {
+			destPath += "-" + customName
+		}
+		f.ContainerMgr.ExecContainer(ctx, containerName, []string{"rm", "-rf", destPath})
+		if err := f.ContainerMgr.ExecContainer(ctx, containerName, []string{"mkdir", "-p", destPath}); err != nil {
+			return "", fmt.Errorf("failed to create clipboard directory: %w", err)
+		}
+		if err := f.ContainerMgr.ExecContainerWithInput(ctx, containerName, []string{"tar", "-xf", "-", "--no-same-owner", "-C", destPath}, content); err != nil {
+			return "", fmt.Errorf("failed to paste files to container: %w", err)
+		}
+		return destPath + "/", nil
 	}
 
 	// Transfer content to container
 	if err := f.ContainerMgr.ExecContainerWithInput(ctx, containerName, []string{"tee", destPath}, content); err != nil {
-		return fmt.Errorf("failed to paste to container: %w", err)
+		return "", fmt.Errorf("failed to paste to container: %w", err)
 	}
-
-	color.Printf("{green}✓{reset} Pasted to %s\n", destPath)
-	return nil
+	return destPath, nil
 }
 
 // formatDuration formats a duration in a human-readable way
@@ -589,6 +1376,38 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dd ago", int(d.Hours()/24))
 }
 
+// formatUptime returns d in its two largest units, e.g. 3d 4h or 12m
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm", minutes)
+	default:
+		return "less than a minute"
+	}
+}
+
+// describeLastStop explains why a container last stopped, and whether it
+// crashed rather than being stopped
+func describeLastStop(cont *container.Container) (string, bool) {
+	switch {
+	case cont.OOMKilled:
+		return fmt.Sprintf("killed for running out of memory, exit code %d", cont.ExitCode), true
+	case cont.StoppedByUser:
+		return fmt.Sprintf("stopped, exit code %d", cont.ExitCode), false
+	case cont.ExitCode != 0:
+		return fmt.Sprintf("crashed, exit code %d", cont.ExitCode), true
+	default:
+		return "exited, exit code 0", false
+	}
+}
+
 // formatStatus returns a colored status string
 func formatStatus(status string) string {
 	if os.Getenv("NO_COLOR") != "" {
@@ -624,7 +1443,7 @@ func formatGitStatus(hasGit bool) string {
 
 // isAudioTunnelConnected checks if the audio SSH tunnel is currently running
 func isAudioTunnelConnected() bool {
-	controlPath := filepath.Join(os.Getenv("HOME"), ".ssh", "control-*@l8s-audio:*")
+	controlPath := filepath.Join(ssh.GetHomeDir(), ".ssh", "control-*@l8s-audio:*")
 	matches, _ := filepath.Glob(controlPath)
 	return len(matches) > 0
 }
@@ -654,7 +1473,10 @@ func (f *CommandFactory) runInit(cmd *cobra.Command, args []string) error {
 	if address == "" {
 		return fmt.Errorf("server address is required")
 	}
-	connCfg.Address = address
+	if err := config.ValidateAddress(address); err != nil {
+		return err
+	}
+	connCfg.Address = config.NormalizeAddress(address)
 	connCfg.Description = "Default connection"
 
 	// Prompt for host configuration (same for all connections)
@@ -888,10 +1710,10 @@ fi
 			cfg.RemoteUser,
 			cfg.AudioPort,
 			cfg.KnownHostsPath,
-		)
+		) + ssh.ProxyJumpConfig(connCfg.ProxyJump)
 
 		// Add to SSH config (AddSSHConfigEntry handles duplicates)
-		sshConfigPath := filepath.Join(os.Getenv("HOME"), ".ssh", "config")
+		sshConfigPath := filepath.Join(ssh.GetHomeDir(), ".ssh", "config")
 		if err := ssh.AddSSHConfigEntry(sshConfigPath, audioConfig); err != nil {
 			color.Printf("{yellow}⚠{reset} Failed to add l8s-audio SSH config: %v\n", err)
 		} else {
@@ -980,6 +1802,11 @@ func (f *CommandFactory) runRebuild(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("--build and --skip-build are mutually exclusive")
 	}
 
+	if blueGreen, _ := cmd.Flags().GetBool("blue-green"); blueGreen {
+		healthCmd, _ := cmd.Flags().GetString("health-cmd")
+		return f.HandleBlueGreenRebuild(name, build, skipBuild, healthCmd)
+	}
+
 	return f.HandleRebuild(name, build, skipBuild)
 }
 
@@ -993,7 +1820,60 @@ func (f *CommandFactory) HandleRebuild(name string, build, skipBuild bool) error
 		return fmt.Errorf("container '%s' not found: %w", name, err)
 	}
 
-	// Step 2: Handle image build decision
+	// Step 2: Build image if requested
+	if err := f.maybeBuildImage(ctx, build, skipBuild); err != nil {
+		return err
+	}
+
+	// Step 3: Execute rebuild
+	color.Printf("🎳 {cyan}Rebuilding container:{reset} {bold}%s-%s{reset}\n", f.Config.ContainerPrefix, name)
+
+	if err := f.ContainerMgr.RebuildContainer(ctx, name); err != nil {
+		return fmt.Errorf("failed to rebuild container: %w", err)
+	}
+
+	// Step 4: Display success information
+	color.Printf("{green}✓{reset} Container rebuilt successfully!\n")
+	f.notifyEvent(notify.EventRebuild, name)
+	fmt.Printf("\nConnect with:\n")
+	fmt.Printf("  ssh %s-%s\n", f.Config.ContainerPrefix, name)
+
+	return nil
+}
+
+// HandleBlueGreenRebuild rebuilds a container by starting a verified replacement
+// before the original is removed
+func (f *CommandFactory) HandleBlueGreenRebuild(name string, build, skipBuild bool, healthCmd string) error {
+	ctx := context.Background()
+
+	if _, err := f.ContainerMgr.GetContainerInfo(ctx, name); err != nil {
+		return fmt.Errorf("container '%s' not found: %w", name, err)
+	}
+
+	if err := f.maybeBuildImage(ctx, build, skipBuild); err != nil {
+		return err
+	}
+
+	color.Printf("🎳 {cyan}Rebuilding container (blue/green):{reset} {bold}%s-%s{reset}\n", f.Config.ContainerPrefix, name)
+	if healthCmd != "" {
+		color.Printf("{dim}Health check: %s{reset}\n", healthCmd)
+	}
+
+	if err := f.ContainerMgr.BlueGreenRebuildContainer(ctx, name, healthCmd); err != nil {
+		return fmt.Errorf("failed to rebuild container: %w\nThe original container was left running.", err)
+	}
+
+	color.Printf("{green}✓{reset} Container rebuilt and cut over successfully!\n")
+	f.notifyEvent(notify.EventRebuild, name)
+	fmt.Printf("\nConnect with:\n")
+	fmt.Printf("  ssh %s-%s\n", f.Config.ContainerPrefix, name)
+
+	return nil
+}
+
+// maybeBuildImage builds the base image when requested, prompting if neither flag was given
+func (f *CommandFactory) maybeBuildImage(ctx context.Context, build, skipBuild bool) error {
+	// Handle image build decision
 	var shouldBuild bool
 	if !build && !skipBuild {
 		// Interactive prompt when no flags specified
@@ -1009,7 +1889,7 @@ func (f *CommandFactory) HandleRebuild(name string, build, skipBuild bool) error
 		shouldBuild = build
 	}
 
-	// Step 3: Build image if requested
+	// Build image if requested
 	if shouldBuild {
 		fmt.Println("Building l8s base image...")
 		if err := f.ContainerMgr.BuildImage(ctx, ""); err != nil {
@@ -1018,18 +1898,6 @@ func (f *CommandFactory) HandleRebuild(name string, build, skipBuild bool) error
 		color.Printf("{green}✓{reset} Image built successfully\n")
 	}
 
-	// Step 4: Execute rebuild
-	color.Printf("🎳 {cyan}Rebuilding container:{reset} {bold}%s-%s{reset}\n", f.Config.ContainerPrefix, name)
-
-	if err := f.ContainerMgr.RebuildContainer(ctx, name); err != nil {
-		return fmt.Errorf("failed to rebuild container: %w", err)
-	}
-
-	// Step 5: Display success information
-	color.Printf("{green}✓{reset} Container rebuilt successfully!\n")
-	fmt.Printf("\nConnect with:\n")
-	fmt.Printf("  ssh %s-%s\n", f.Config.ContainerPrefix, name)
-
 	return nil
 }
 
@@ -1078,7 +1946,7 @@ func (f *CommandFactory) runPush(cmd *cobra.Command, args []string) error {
 	shortName := fullName[len(f.Config.ContainerPrefix)+1:]
 	color.Printf("{cyan}→{reset} Updating working directory in container...\n")
 	checkoutCmd := []string{"su", "-", f.Config.ContainerUser, "-c",
-		fmt.Sprintf("cd /workspace/project && git checkout %s && git reset --hard HEAD", branch)}
+		fmt.Sprintf("cd /workspace/project && git checkout %s && git reset --hard HEAD", container.ShellQuote(branch))}
 	if err := f.ContainerMgr.ExecContainer(ctx, shortName, checkoutCmd); err != nil {
 		color.Printf("{yellow}!{reset} Warning: Failed to update working directory: %v\n", err)
 		color.Printf("{yellow}!{reset} Container may need manual 'git checkout %s' and 'git reset --hard HEAD'\n", branch)
@@ -1147,6 +2015,10 @@ func (f *CommandFactory) runPull(cmd *cobra.Command, args []string) error {
 
 // runStatus handles the status command
 func (f *CommandFactory) runStatus(cmd *cobra.Command, args []string) error {
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		return f.runStatusAll(cmd)
+	}
+
 	// Get repository root to support running from subdirectories
 	repoRoot, err := f.GitClient.GetRepositoryRoot(".")
 	if err != nil {
@@ -1216,12 +2088,30 @@ func (f *CommandFactory) runRebuildAll(cmd *cobra.Command, args []string) error
 	force, _ := cmd.Flags().GetBool("force")
 	build, _ := cmd.Flags().GetBool("build")
 	skipBuild, _ := cmd.Flags().GetBool("skip-build")
+	forceProtected, _ := cmd.Flags().GetBool("force-protected")
 
 	// Validate mutually exclusive flags
 	if build && skipBuild {
 		return fmt.Errorf("--build and --skip-build are mutually exclusive")
 	}
 
+	// Leave protected containers alone unless explicitly overridden
+	if !forceProtected {
+		var unprotected []*container.Container
+		for _, c := range containers {
+			if f.isProtected(c) {
+				color.Printf("{yellow}!{reset} Skipping protected container %s\n", c.Name)
+				continue
+			}
+			unprotected = append(unprotected, c)
+		}
+		containers = unprotected
+		if len(containers) == 0 {
+			fmt.Println("No containers to rebuild")
+			return nil
+		}
+	}
+
 	// Confirm if not forced
 	if !force {
 		reader := bufio.NewReader(os.Stdin)
@@ -1275,6 +2165,7 @@ func (f *CommandFactory) runRebuildAll(cmd *cobra.Command, args []string) error
 			failedContainers = append(failedContainers, container.Name)
 		} else {
 			color.Printf("{green}✓{reset} Successfully rebuilt %s\n", container.Name)
+			f.notifyEvent(notify.EventRebuild, containerName)
 			successCount++
 		}
 	}
@@ -1469,9 +2360,12 @@ fi
 	// Execute via SSH
 	// Pass script directly - SSH runs remote commands through a shell
 	color.Printf("{cyan}→{reset} Connecting to {bold}%s@%s{reset}...\n", remoteUser, remoteHost)
-	sshCmd := exec.CommandContext(ctx, "ssh",
-		fmt.Sprintf("%s@%s", remoteUser, remoteHost),
-		setupScript)
+	var sshArgs []string
+	if conn.ProxyJump != "" {
+		sshArgs = append(sshArgs, "-J", conn.ProxyJump)
+	}
+	sshArgs = append(sshArgs, fmt.Sprintf("%s@%s", remoteUser, remoteHost), setupScript)
+	sshCmd := exec.CommandContext(ctx, "ssh", sshArgs...)
 	sshCmd.Stdout = os.Stdout
 	sshCmd.Stderr = os.Stderr
 
@@ -1481,14 +2375,18 @@ fi
 
 	color.Printf("{green}✓{reset} Host prepared for audio tunneling on {bold}%s{reset} (port %d)\n", remoteHost, audioPort)
 
-	// Add l8s-audio SSH config entry (for users who ran init before audio support)
+	// Add l8s-audio SSH config entry (for users who ran init before audio
+	// support), pointing at the configured connection like container entries
+	if configured, err := f.Config.ConfiguredConnection(); err == nil {
+		conn = configured
+	}
 	audioConfig := ssh.GenerateAudioSSHConfigEntry(
-		remoteHost,
+		conn.Address,
 		remoteUser,
 		audioPort,
 		f.Config.KnownHostsPath,
-	)
-	sshConfigPath := filepath.Join(os.Getenv("HOME"), ".ssh", "config")
+	) + ssh.ProxyJumpConfig(conn.ProxyJump)
+	sshConfigPath := filepath.Join(ssh.GetHomeDir(), ".ssh", "config")
 	if err := ssh.AddSSHConfigEntry(sshConfigPath, audioConfig); err != nil {
 		color.Printf("{yellow}⚠{reset} Failed to add l8s-audio SSH config: %v\n", err)
 	} else {
@@ -1587,10 +2485,16 @@ load-module module-native-protocol-tcp auth-anonymous=1
 
 // runAudioConnect starts the audio SSH tunnel to the remote host
 func (f *CommandFactory) runAudioConnect(ctx context.Context) error {
+	// The tunnel relies on ssh -f and a ControlMaster socket, neither of
+	// which Windows OpenSSH supports
+	if runtime.GOOS == "windows" {
+		return fmt.Errorf("audio tunnel is not supported on Windows hosts")
+	}
+
 	fmt.Println("Starting audio tunnel...")
 
 	// Check if tunnel is already running by checking control socket
-	controlPath := filepath.Join(os.Getenv("HOME"), ".ssh", "control-*@l8s-audio:*")
+	controlPath := filepath.Join(ssh.GetHomeDir(), ".ssh", "control-*@l8s-audio:*")
 	matches, _ := filepath.Glob(controlPath)
 	if len(matches) > 0 {
 		color.Printf("{yellow}!{reset} Audio tunnel already connected\n")
@@ -3,12 +3,19 @@ package cli
 import (
 	"context"
 	"errors"
+	"io"
+	"os"
 	"testing"
+	"time"
 
+	"l8s/pkg/backup"
 	"l8s/pkg/config"
 	"l8s/pkg/container"
+	"l8s/pkg/git"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // Enhanced mock for testing create command
@@ -16,8 +23,8 @@ type MockContainerManagerWithGit struct {
 	mock.Mock
 }
 
-func (m *MockContainerManagerWithGit) CreateContainer(ctx context.Context, name, sshKey string) (*container.Container, error) {
-	args := m.Called(ctx, name, sshKey)
+func (m *MockContainerManagerWithGit) CreateContainer(ctx context.Context, name, sshKey string, opts container.CreateOptions) (*container.Container, error) {
+	args := m.Called(ctx, name, sshKey, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -70,8 +77,8 @@ func (m *MockContainerManagerWithGit) ExecContainerWithInput(ctx context.Context
 	return args.Error(0)
 }
 
-func (m *MockContainerManagerWithGit) SSHIntoContainer(ctx context.Context, name string) error {
-	args := m.Called(ctx, name)
+func (m *MockContainerManagerWithGit) SSHIntoContainer(ctx context.Context, name string, opts container.SSHOptions) error {
+	args := m.Called(ctx, name, opts)
 	return args.Error(0)
 }
 
@@ -80,6 +87,171 @@ func (m *MockContainerManagerWithGit) RebuildContainer(ctx context.Context, name
 	return args.Error(0)
 }
 
+func (m *MockContainerManagerWithGit) BlueGreenRebuildContainer(ctx context.Context, name, healthCmd string) error {
+	args := m.Called(ctx, name, healthCmd)
+	return args.Error(0)
+}
+
+func (m *MockContainerManagerWithGit) CloneContainer(ctx context.Context, source, name string) (*container.Container, error) {
+	args := m.Called(ctx, source, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*container.Container), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) AdoptContainer(ctx context.Context, source, name string, opts container.AdoptOptions) (*container.Container, error) {
+	args := m.Called(ctx, source, name, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*container.Container), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) DisownContainer(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockContainerManagerWithGit) MigratePrefix(ctx context.Context, name, oldPrefix, newPrefix string) (*container.Container, error) {
+	args := m.Called(ctx, name, oldPrefix, newPrefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*container.Container), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) BackupContainer(ctx context.Context, name string, store backup.Store, ts time.Time) ([]backup.Entry, error) {
+	args := m.Called(ctx, name, store, ts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]backup.Entry), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) TrashContainer(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockContainerManagerWithGit) ListTrash(ctx context.Context) ([]*container.TrashedContainer, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*container.TrashedContainer), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) RestoreContainer(ctx context.Context, name string) (*container.Container, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*container.Container), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) PurgeTrash(ctx context.Context, retention time.Duration) ([]*container.TrashedContainer, error) {
+	args := m.Called(ctx, retention)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*container.TrashedContainer), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) ExecContainerOutput(ctx context.Context, name string, command []string) (string, error) {
+	args := m.Called(ctx, name, command)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) UpdateLabels(ctx context.Context, name string, set map[string]string, unset []string) error {
+	args := m.Called(ctx, name, set, unset)
+	return args.Error(0)
+}
+
+func (m *MockContainerManagerWithGit) GetActivity(ctx context.Context, name string) (*container.Activity, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*container.Activity), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) InspectContainer(ctx context.Context, name string) ([]byte, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) WaitForSSH(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockContainerManagerWithGit) AuditSecurity(ctx context.Context, name string) ([]container.SecurityCheck, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]container.SecurityCheck), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) SSHAuthStats(ctx context.Context, name string) (*container.SSHAuthStats, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*container.SSHAuthStats), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) RemoteVersion(ctx context.Context) (*container.RemoteVersion, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*container.RemoteVersion), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) InstallAgentSettings(ctx context.Context, name, agent string) (bool, error) {
+	args := m.Called(ctx, name, agent)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) ExecContainerStream(ctx context.Context, name string, command []string, stdout, stderr io.Writer) error {
+	args := m.Called(ctx, name, command, stdout, stderr)
+	return args.Error(0)
+}
+
+func (m *MockContainerManagerWithGit) Drift(ctx context.Context, name string) (*container.Drift, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*container.Drift), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) DiskUsage(ctx context.Context) (*container.DiskReport, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*container.DiskReport), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) FindRemnants(ctx context.Context) (*container.Remnants, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*container.Remnants), args.Error(1)
+}
+
+func (m *MockContainerManagerWithGit) RemoveRemnants(ctx context.Context, remnants *container.Remnants) error {
+	args := m.Called(ctx, remnants)
+	return args.Error(0)
+}
+
 // Enhanced mock for git operations
 type MockGitClientEnhanced struct {
 	mock.Mock
@@ -138,16 +310,60 @@ func (m *MockGitClientEnhanced) PushBranch(repoPath, branch, remoteName string,
 	return args.Error(0)
 }
 
+func (m *MockGitClientEnhanced) PushShallow(repoPath, branch, remoteName string, depth int) error {
+	args := m.Called(repoPath, branch, remoteName, depth)
+	return args.Error(0)
+}
+
 func (m *MockGitClientEnhanced) InitRepository(repoPath string, allowPush bool, defaultBranch string) error {
 	args := m.Called(repoPath, allowPush, defaultBranch)
 	return args.Error(0)
 }
 
+func (m *MockGitClientEnhanced) HasCommit(repoPath, sha string) bool {
+	args := m.Called(repoPath, sha)
+	return args.Bool(0)
+}
+
+func (m *MockGitClientEnhanced) ResolveRef(repoPath, ref string) (string, error) {
+	args := m.Called(repoPath, ref)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockGitClientEnhanced) PushRef(repoPath, ref, remoteName, branch string) error {
+	args := m.Called(repoPath, ref, remoteName, branch)
+	return args.Error(0)
+}
+
+func (m *MockGitClientEnhanced) CreateBranch(repoPath, branch, startPoint string) error {
+	args := m.Called(repoPath, branch, startPoint)
+	return args.Error(0)
+}
+
+func (m *MockGitClientEnhanced) ListWorktrees(repoPath string) ([]git.Worktree, error) {
+	args := m.Called(repoPath)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]git.Worktree), args.Error(1)
+}
+
+func (m *MockGitClientEnhanced) AheadBehind(repoPath, a, b string) (int, int, error) {
+	args := m.Called(repoPath, a, b)
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
 func TestCreateCommandNewFlow(t *testing.T) {
+	// Retry immediately so push failures don't slow the tests
+	oldDelay := pushRetryDelay
+	pushRetryDelay = 0
+	defer func() { pushRetryDelay = oldDelay }()
+
 	tests := []struct {
 		name            string
 		args            []string
 		branch          string
+		skipPush        bool
 		isGitRepo       bool
 		currentBranch   string
 		setupMocks      func(*LazyCommandFactory, *MockContainerManagerWithGit, *MockGitClientEnhanced)
@@ -168,7 +384,7 @@ func TestCreateCommandNewFlow(t *testing.T) {
 				cm.On("GetContainerInfo", mock.Anything, mock.AnythingOfType("string")).Return(nil, errors.New("not found")).Once()
 
 				// Create container with deterministic name
-				cm.On("CreateContainer", mock.Anything, mock.AnythingOfType("string"), "mock-ssh-key").
+				cm.On("CreateContainer", mock.Anything, mock.AnythingOfType("string"), "mock-ssh-key", mock.Anything).
 					Return(&container.Container{
 						Name:    "project-e3af8a",
 						SSHPort: 2222,
@@ -177,7 +393,8 @@ func TestCreateCommandNewFlow(t *testing.T) {
 				// Add git remote with deterministic name
 				gc.On("AddRemote", "/workspace/project", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
 
-				// Push initial code
+				// Wait for sshd, then push initial code
+				cm.On("WaitForSSH", mock.Anything, mock.AnythingOfType("string")).Return(nil)
 				gc.On("PushBranch", "/workspace/project", "main", mock.AnythingOfType("string"), false).Return(nil)
 
 				// List remotes to check for origin
@@ -203,7 +420,7 @@ func TestCreateCommandNewFlow(t *testing.T) {
 				cm.On("GetContainerInfo", mock.Anything, mock.AnythingOfType("string")).Return(nil, errors.New("not found")).Once()
 
 				// Create container
-				cm.On("CreateContainer", mock.Anything, mock.AnythingOfType("string"), "mock-ssh-key").
+				cm.On("CreateContainer", mock.Anything, mock.AnythingOfType("string"), "mock-ssh-key", mock.Anything).
 					Return(&container.Container{
 						Name:    "project-e3af8a",
 						SSHPort: 2222,
@@ -212,7 +429,8 @@ func TestCreateCommandNewFlow(t *testing.T) {
 				// Add git remote
 				gc.On("AddRemote", "/workspace/project", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
 
-				// Push specified branch
+				// Wait for sshd, then push specified branch
+				cm.On("WaitForSSH", mock.Anything, mock.AnythingOfType("string")).Return(nil)
 				gc.On("PushBranch", "/workspace/project", "feature", mock.AnythingOfType("string"), false).Return(nil)
 
 				// List remotes to check for origin
@@ -234,6 +452,18 @@ func TestCreateCommandNewFlow(t *testing.T) {
 			wantErr:     true,
 			errContains: "must be run from within a git repository",
 		},
+		{
+			name:      "create with an invalid branch name",
+			args:      []string{},
+			isGitRepo: true,
+			branch:    "-f main",
+			setupMocks: func(f *LazyCommandFactory, cm *MockContainerManagerWithGit, gc *MockGitClientEnhanced) {
+				gc.On("GetRepositoryRoot", ".").Return("/workspace/project", nil)
+				cm.On("GetContainerInfo", mock.Anything, mock.AnythingOfType("string")).Return(nil, errors.New("not found")).Once()
+			},
+			wantErr:     true,
+			errContains: "invalid branch name",
+		},
 		{
 			name:          "create with git remote add failure",
 			args:          []string{},
@@ -246,7 +476,7 @@ func TestCreateCommandNewFlow(t *testing.T) {
 				// Check if container already exists
 				cm.On("GetContainerInfo", mock.Anything, mock.AnythingOfType("string")).Return(nil, errors.New("not found")).Once()
 
-				cm.On("CreateContainer", mock.Anything, mock.AnythingOfType("string"), "mock-ssh-key").
+				cm.On("CreateContainer", mock.Anything, mock.AnythingOfType("string"), "mock-ssh-key", mock.Anything).
 					Return(&container.Container{
 						Name:    "project-e3af8a",
 						SSHPort: 2222,
@@ -274,7 +504,7 @@ func TestCreateCommandNewFlow(t *testing.T) {
 				// Check if container already exists
 				cm.On("GetContainerInfo", mock.Anything, mock.AnythingOfType("string")).Return(nil, errors.New("not found")).Once()
 
-				cm.On("CreateContainer", mock.Anything, mock.AnythingOfType("string"), "mock-ssh-key").
+				cm.On("CreateContainer", mock.Anything, mock.AnythingOfType("string"), "mock-ssh-key", mock.Anything).
 					Return(&container.Container{
 						Name:    "project-e3af8a",
 						SSHPort: 2222,
@@ -282,7 +512,8 @@ func TestCreateCommandNewFlow(t *testing.T) {
 
 				gc.On("AddRemote", "/workspace/project", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
 
-				// Push fails
+				// Push fails on every attempt
+				cm.On("WaitForSSH", mock.Anything, mock.AnythingOfType("string")).Return(nil)
 				gc.On("PushBranch", "/workspace/project", "main", mock.AnythingOfType("string"), false).
 					Return(errors.New("failed to push"))
 
@@ -292,6 +523,56 @@ func TestCreateCommandNewFlow(t *testing.T) {
 			wantErr:     true,
 			errContains: "failed to push",
 		},
+		{
+			name:          "create retries push while sshd starts",
+			args:          []string{},
+			isGitRepo:     true,
+			currentBranch: "main",
+			setupMocks: func(f *LazyCommandFactory, cm *MockContainerManagerWithGit, gc *MockGitClientEnhanced) {
+				gc.On("GetRepositoryRoot", ".").Return("/workspace/project", nil)
+				gc.On("GetCurrentBranch", "/workspace/project").Return("main", nil)
+				cm.On("GetContainerInfo", mock.Anything, mock.AnythingOfType("string")).Return(nil, errors.New("not found")).Once()
+				cm.On("CreateContainer", mock.Anything, mock.AnythingOfType("string"), "mock-ssh-key", mock.Anything).
+					Return(&container.Container{
+						Name:    "project-e3af8a",
+						SSHPort: 2222,
+					}, nil)
+				gc.On("AddRemote", "/workspace/project", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+
+				// Readiness probe times out and the first push is refused
+				cm.On("WaitForSSH", mock.Anything, mock.AnythingOfType("string")).Return(errors.New("timeout"))
+				gc.On("PushBranch", "/workspace/project", "main", mock.AnythingOfType("string"), false).
+					Return(errors.New("connection refused")).Once()
+				gc.On("PushBranch", "/workspace/project", "main", mock.AnythingOfType("string"), false).Return(nil).Once()
+
+				gc.On("ListRemotes", "/workspace/project").Return(map[string]string{}, nil)
+				cm.On("ExecContainer", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]string")).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:          "create with skip-push",
+			args:          []string{},
+			skipPush:      true,
+			isGitRepo:     true,
+			currentBranch: "main",
+			setupMocks: func(f *LazyCommandFactory, cm *MockContainerManagerWithGit, gc *MockGitClientEnhanced) {
+				gc.On("GetRepositoryRoot", ".").Return("/workspace/project", nil)
+				gc.On("GetCurrentBranch", "/workspace/project").Return("main", nil)
+				cm.On("GetContainerInfo", mock.Anything, mock.AnythingOfType("string")).Return(nil, errors.New("not found")).Once()
+				cm.On("CreateContainer", mock.Anything, mock.AnythingOfType("string"), "mock-ssh-key", mock.Anything).
+					Return(&container.Container{
+						Name:    "project-e3af8a",
+						SSHPort: 2222,
+					}, nil)
+				gc.On("AddRemote", "/workspace/project", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+
+				// No readiness wait, push or checkout; only origin replication
+				gc.On("ListRemotes", "/workspace/project").Return(map[string]string{"origin": "https://github.com/user/repo.git"}, nil)
+				cm.On("ExecContainer", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]string")).Return(nil).Once()
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -315,6 +596,9 @@ func TestCreateCommandNewFlow(t *testing.T) {
 			if tt.branch != "" {
 				cmd.Flags().Set("branch", tt.branch)
 			}
+			if tt.skipPush {
+				cmd.Flags().Set("skip-push", "true")
+			}
 			
 			// Execute command
 			err := cmd.RunE(cmd, tt.args)
@@ -499,4 +783,152 @@ func TestHandleRebuild(t *testing.T) {
 			mockMgr.AssertExpectations(t)
 		})
 	}
-}
\ No newline at end of file
+}
+func TestCreateOrigin(t *testing.T) {
+	gitClient := new(MockGitClientEnhanced)
+	gitClient.On("ListRemotes", "/repo").Return(map[string]string{"origin": "https://github.com/acme/api.git"}, nil)
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("origin", false, "")
+		cmd.Flags().Bool("no-origin", false, "")
+		cmd.Flags().Int("shallow", 0, "")
+		assert.NoError(t, cmd.Flags().Parse(args))
+		return cmd
+	}
+
+	f := &CommandFactory{Config: &config.Config{GitHubToken: "ghp_x"}, GitClient: gitClient}
+	url, _ := f.createOrigin(newCmd(), "/repo")
+	assert.Empty(t, url)
+
+	url, auth := f.createOrigin(newCmd("--origin"), "/repo")
+	assert.Equal(t, "https://github.com/acme/api.git", url)
+	assert.Equal(t, config.OriginAuthToken, auth)
+
+	// Shallow containers fetch their history from origin
+	url, _ = f.createOrigin(newCmd("--shallow=5"), "/repo")
+	assert.Equal(t, "https://github.com/acme/api.git", url)
+	url, _ = f.createOrigin(newCmd("--shallow=5", "--origin=false"), "/repo")
+	assert.Empty(t, url)
+
+	f.Config.Origin = config.OriginConfig{Mirror: true, Auth: config.OriginAuthAgent}
+	url, auth = f.createOrigin(newCmd(), "/repo")
+	assert.Equal(t, "https://github.com/acme/api.git", url)
+	assert.Equal(t, config.OriginAuthAgent, auth)
+
+	url, _ = f.createOrigin(newCmd("--no-origin"), "/repo")
+	assert.Empty(t, url)
+}
+
+func TestFormatUptime(t *testing.T) {
+	assert.Equal(t, "less than a minute", formatUptime(30*time.Second))
+	assert.Equal(t, "12m", formatUptime(12*time.Minute))
+	assert.Equal(t, "2h 5m", formatUptime(2*time.Hour+5*time.Minute))
+	assert.Equal(t, "3d 4h", formatUptime(76*time.Hour+30*time.Minute))
+}
+
+func TestDescribeLastStop(t *testing.T) {
+	tests := []struct {
+		cont        container.Container
+		description string
+		crashed     bool
+	}{
+		{container.Container{ExitCode: 137, OOMKilled: true}, "killed for running out of memory, exit code 137", true},
+		{container.Container{ExitCode: 143, StoppedByUser: true}, "stopped, exit code 143", false},
+		{container.Container{ExitCode: 1}, "crashed, exit code 1", true},
+		{container.Container{}, "exited, exit code 0", false},
+	}
+	for _, tt := range tests {
+		description, crashed := describeLastStop(&tt.cont)
+		assert.Equal(t, tt.description, description)
+		assert.Equal(t, tt.crashed, crashed)
+	}
+}
+
+func TestRemoveTarget(t *testing.T) {
+	gitClient := new(MockGitClientEnhanced)
+	gitClient.On("IsGitRepository", ".").Return(false)
+	f := &CommandFactory{Config: &config.Config{ContainerPrefix: "dev"}, GitClient: gitClient}
+
+	name, err := f.removeTarget("dev-myrepo-a3f2d1")
+	assert.NoError(t, err)
+	assert.Equal(t, "myrepo-a3f2d1", name)
+
+	name, err = f.removeTarget("myrepo-a3f2d1")
+	assert.NoError(t, err)
+	assert.Equal(t, "myrepo-a3f2d1", name)
+
+	_, err = f.removeTarget("")
+	assert.ErrorContains(t, err, "--name")
+}
+
+func TestRemoveByNameOutsideRepo(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	worktree := t.TempDir()
+	gitCmd := func(args string) []string {
+		return []string{"su", "-", "dev", "-c", "cd /workspace/project && git " + args}
+	}
+
+	// answer feeds the confirmation prompt
+	answer := func(t *testing.T, text string) {
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		_, _ = w.WriteString(text)
+		w.Close()
+		stdin := os.Stdin
+		os.Stdin = r
+		t.Cleanup(func() { os.Stdin = stdin })
+	}
+	run := func(t *testing.T, labels map[string]string, flags ...string) (*MockContainerManagerWithGit, error) {
+		mgr := new(MockContainerManagerWithGit)
+		mgr.On("GetContainerInfo", mock.Anything, "myrepo-a3f2d1").
+			Return(&container.Container{Name: "dev-myrepo-a3f2d1", Labels: labels}, nil)
+		mgr.On("ExecContainerOutput", mock.Anything, "myrepo-a3f2d1", gitCmd("status --porcelain")).Return("", nil)
+		mgr.On("ExecContainerOutput", mock.Anything, "myrepo-a3f2d1",
+			gitCmd("for-each-ref --format='%(refname:short) %(objectname)' refs/heads")).Return("main aaa111\n", nil)
+		mgr.On("ExecContainerOutput", mock.Anything, "myrepo-a3f2d1",
+			gitCmd("log --format='%H %h %s' -n 100 aaa111")).Return("aaa111 aaa Work\n", nil)
+		mgr.On("RemoveContainer", mock.Anything, "myrepo-a3f2d1", true).Return(nil)
+		gitClient := new(MockGitClientEnhanced)
+		gitClient.On("IsGitRepository", ".").Return(false)
+		gitClient.On("HasCommit", worktree, "aaa111").Return(true)
+		gitClient.On("RemoveRemote", worktree, "myrepo-a3f2d1").Return(nil)
+
+		factory := &LazyCommandFactory{
+			Config:       &config.Config{ContainerPrefix: "dev", ContainerUser: "dev"},
+			ContainerMgr: mgr,
+			GitClient:    gitClient,
+		}
+		cmd := factory.RemoveCmd()
+		require.NoError(t, cmd.Flags().Set("name", "myrepo-a3f2d1"))
+		for _, flag := range flags {
+			require.NoError(t, cmd.Flags().Set(flag, "true"))
+		}
+		return mgr, cmd.RunE(cmd, nil)
+	}
+
+	t.Run("commits are found in the worktree the container was created from", func(t *testing.T) {
+		answer(t, "y\n")
+		mgr, err := run(t, map[string]string{container.LabelWorktree: worktree})
+		require.NoError(t, err)
+		mgr.AssertCalled(t, "RemoveContainer", mock.Anything, "myrepo-a3f2d1", true)
+	})
+
+	t.Run("a deleted worktree leaves commits unsaved", func(t *testing.T) {
+		mgr, err := run(t, map[string]string{})
+		assert.ErrorContains(t, err, "aaa Work")
+		mgr.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("--skip-unsaved-check still asks for confirmation", func(t *testing.T) {
+		answer(t, "n\n")
+		mgr, err := run(t, map[string]string{}, "skip-unsaved-check")
+		require.NoError(t, err)
+		mgr.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything, mock.Anything)
+
+		answer(t, "y\n")
+		mgr, err = run(t, map[string]string{}, "skip-unsaved-check")
+		require.NoError(t, err)
+		mgr.AssertCalled(t, "RemoveContainer", mock.Anything, "myrepo-a3f2d1", true)
+	})
+}
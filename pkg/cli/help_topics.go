@@ -0,0 +1,175 @@
+package cli
+
+import "github.com/spf13/cobra"
+
+// helpTopics are long-form guides shown by 'l8s help <topic>' and shipped as
+// section 7 man pages by 'l8s docs man'. A command without RunE or
+// subcommands is what cobra treats as a help topic.
+var helpTopics = []struct {
+	name, short, long string
+}{
+	{
+		name:  "connections",
+		short: "How l8s reaches Podman hosts, and switching between them",
+		long: `CONNECTIONS
+
+A connection is one way of reaching the Podman host: its address, and
+optionally a bastion. Several can be configured for the same host, e.g. one
+over Tailscale and one over the public address, and one is active:
+
+  active_connection: tailscale
+  connections:
+    tailscale:
+      address: 100.64.0.5
+    public:
+      address: dev.example.com
+      description: From anywhere
+    office:
+      address: 2001:db8::5                  # IPv6 literals work, with or without brackets
+      proxy_jump: admin@bastion.example.com # As for ssh -J; comma separated for several hops
+
+'l8s connection list' and 'l8s connection show' print them. For a single
+command, '--connection <name>' or L8S_CONNECTION picks another one without
+changing the config.
+
+SWITCHING
+
+'l8s connection switch <name>' makes another connection active. Besides the
+config, it rewrites what points at the old address:
+
+  - the HostName (and ProxyJump) of every container entry l8s wrote to
+    ~/.ssh/config
+  - ssh://user@host:port/ git remotes naming the old address, in every
+    repository l8s added a container remote to. Remotes through the SSH host
+    alias (dev-myproject:/workspace/project) follow the SSH entries.
+
+Use --dry-run to see the changes first.
+
+CHOOSING AUTOMATICALLY
+
+auto_connect picks the connection at startup. Rules are tried in order and
+the first whose conditions hold wins; 'reachable' (host[:port], port 22 by
+default) must accept a TCP connection within a second, and 'interface' must
+be up. A rule without conditions always matches:
+
+  auto_connect:
+    update_ssh_config: true   # switch for good; otherwise only for the command
+    rules:
+      - connection: tailscale
+        reachable: 100.64.0.5
+      - connection: public
+
+A connection chosen with --connection or L8S_CONNECTION is never overridden.
+
+SEE ALSO
+
+l8s help networking, l8s connection, l8s list --all-connections`,
+	},
+	{
+		name:  "networking",
+		short: "Ports, SSH access, web ports and published ports",
+		long: `NETWORKING
+
+Every container gets two ports on the host: one for SSH (container port 22)
+and one for its web port (container port 3000).
+
+SSH PORTS
+
+SSH ports are taken from ssh_port_start (default 2200) onwards, port_range
+ports in all (default 1000). Web ports sit at the same offset from
+web_port_start (default 3000). When the host firewall only opens scattered
+windows, list them instead; they are used in order:
+
+  ssh_port_ranges: ["2200-2299", "4400-4499"]
+
+A connection's quota.port_budget limits how many SSH ports l8s may use there.
+
+SSH ACCESS
+
+'l8s create' adds a Host entry named <prefix>-<name> to ~/.ssh/config, so
+plain ssh, scp, rsync and editors work too:
+
+  ssh dev-myproject
+  code --remote ssh-remote+dev-myproject /workspace/project
+
+Entries use connection multiplexing, so later connections open instantly.
+Host keys are signed by the l8s SSH certificate authority, so there are no
+host key prompts. 'l8s ssh -L', '-R' and '-D' forward ports as ssh does.
+
+MORE PORTS
+
+'l8s create --publish' (-p) publishes more container ports, TCP or UDP,
+single or ranges, e.g. for mosh or WebRTC:
+
+  l8s create -p 8080 -p 9000:9090 -p 60000-60010/udp
+
+WEB PORTS
+
+'l8s open' opens a container's web port in the browser. 'l8s ingress' serves
+every container at <name>.<domain> through a reverse proxy on the host, so
+there are no port numbers to remember.
+
+BASTIONS AND IPV6
+
+Connection addresses may be IPv6 literals, and hosts behind a bastion take
+proxy_jump. Both are honored by the Podman connection, builds and the SSH
+entries l8s writes; see 'l8s help connections'.
+
+AUDIO
+
+With audio_enabled, the l8s-audio SSH entry forwards audio_port (default 4713)
+on the host back to PulseAudio on this machine.
+
+SEE ALSO
+
+l8s help connections, l8s create, l8s open, l8s ingress, l8s ssh`,
+	},
+	{
+		name:  "dotfiles",
+		short: "Which dotfiles are copied into containers, and how to use your own",
+		long: `DOTFILES
+
+'l8s create' and 'l8s rebuild' copy a set of dotfiles into the home directory
+of the container user. The first of these that is set is used:
+
+  1. --dotfiles-path on 'l8s create'
+  2. the L8S_DOTFILES environment variable
+  3. dotfiles_path in the config
+  4. the dotfiles directory in the l8s config directory
+     (~/.config/l8s/dotfiles; 'l8s config path' shows where it is)
+  5. the defaults built into l8s
+
+Only files and directories whose names start with a dot are copied, so a
+dotfiles repository with a README and install scripts can be used as is.
+With your own dotfiles, the git name and email configured on this machine are
+applied in the container as well.
+
+STARTING FROM THE DEFAULTS
+
+The built-in dotfiles set up zsh, bash, git, tmux, neovim and the Claude
+statusline. To customize them, extract them and edit the copies:
+
+  l8s embed list dotfiles
+  l8s embed extract /tmp/l8s-files dotfiles
+  mv /tmp/l8s-files/dotfiles ~/.config/l8s/dotfiles
+
+Existing containers pick up changes with 'l8s rebuild'.
+
+SEE ALSO
+
+l8s embed, l8s create, l8s rebuild, l8s config path`,
+	},
+}
+
+// HelpTopicCmds returns the help topics, for 'l8s help <topic>'
+func (f *LazyCommandFactory) HelpTopicCmds() []*cobra.Command {
+	cmds := make([]*cobra.Command, 0, len(helpTopics))
+	for _, topic := range helpTopics {
+		cmds = append(cmds, &cobra.Command{
+			Use:   topic.name,
+			Short: topic.short,
+			Long:  topic.long,
+		})
+	}
+	return cmds
+}
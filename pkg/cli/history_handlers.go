@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"l8s/pkg/color"
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+	"l8s/pkg/history"
+	"l8s/pkg/logging"
+
+	"github.com/juju/ansiterm"
+	"github.com/spf13/cobra"
+)
+
+// execHistory returns the local history of commands run in a container with
+// 'l8s exec'. Containers on different connections can share a name, so each
+// connection has its own directory.
+func (f *CommandFactory) execHistory(name string) *history.Log {
+	fullName := f.Config.ContainerPrefix + "-" + name
+	return history.NewLog(filepath.Join(config.StateDir(), history.DirName, f.Config.ActiveConnection, fullName+".jsonl"))
+}
+
+// recordExec adds a command run in a container to its history. The command
+// is recorded as typed, without the exec_env values. Failures to record are
+// only logged.
+func (f *CommandFactory) recordExec(name string, command []string, started time.Time, runErr error) {
+	entry := history.Entry{Time: started, Command: command, Duration: time.Since(started), Failed: runErr != nil}
+	if err := f.execHistory(name).Append(entry); err != nil {
+		logging.Debug("failed to record exec history", logging.WithError(err), logging.WithField("container", name))
+	}
+}
+
+// runHistory prints the commands run in a container with 'l8s exec'
+func (f *CommandFactory) runHistory(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	log := f.execHistory(name)
+
+	if clear, _ := cmd.Flags().GetBool("clear"); clear {
+		if err := log.Clear(); err != nil {
+			return err
+		}
+		color.Printf("{green}✓{reset} History of {bold}%s-%s{reset} cleared\n", f.Config.ContainerPrefix, name)
+		return nil
+	}
+
+	entries, err := log.Entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No commands recorded for %s-%s\n", f.Config.ContainerPrefix, name)
+		return nil
+	}
+	if lines, _ := cmd.Flags().GetInt("lines"); lines > 0 && lines < len(entries) {
+		entries = entries[len(entries)-lines:]
+	}
+
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	if os.Getenv("NO_COLOR") == "" {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", color.Bold("TIME"), color.Bold("TOOK"), color.Bold("COMMAND"))
+	} else {
+		fmt.Fprintln(w, "TIME\tTOOK\tCOMMAND")
+	}
+	for _, e := range entries {
+		command := formatCommand(e.Command)
+		if e.Failed {
+			if os.Getenv("NO_COLOR") == "" {
+				command = color.Red + "✗" + color.Reset + " " + command
+			} else {
+				command = "✗ " + command
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", e.Time.Local().Format("2006-01-02 15:04:05"), roundDuration(e.Duration), command)
+	}
+	return w.Flush()
+}
+
+// formatCommand joins a command's arguments so it can be pasted into a shell
+func formatCommand(command []string) string {
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = container.ShellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"l8s/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecHistory(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	f := &CommandFactory{Config: &config.Config{ContainerPrefix: "dev", ActiveConnection: "home"}}
+
+	f.recordExec("myproject", []string{"dnf", "install", "-y", "jq"}, time.Now().Add(-2*time.Second), nil)
+	f.recordExec("myproject", []string{"sh", "-c", "echo 'hi' > /etc/motd"}, time.Now(), errors.New("exit status 1"))
+	f.recordExec("other", []string{"ls"}, time.Now(), nil)
+	assert.FileExists(t, filepath.Join(state, "l8s", "history", "home", "dev-myproject.jsonl"))
+
+	newCmd := func() (*cobra.Command, *bytes.Buffer) {
+		cmd := &cobra.Command{Use: "history"}
+		cmd.Flags().IntP("lines", "n", 0, "")
+		cmd.Flags().Bool("clear", false, "")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		return cmd, &out
+	}
+
+	cmd, out := newCmd()
+	require.NoError(t, f.runHistory(cmd, []string{"myproject"}))
+	assert.Contains(t, out.String(), "COMMAND")
+	assert.Regexp(t, `2s\s+dnf install -y jq`, out.String())
+	assert.Contains(t, out.String(), `✗ sh -c 'echo '\''hi'\'' > /etc/motd'`)
+	assert.NotContains(t, out.String(), "ls")
+
+	cmd, out = newCmd()
+	require.NoError(t, cmd.Flags().Set("lines", "1"))
+	require.NoError(t, f.runHistory(cmd, []string{"myproject"}))
+	assert.NotContains(t, out.String(), "jq")
+	assert.Contains(t, out.String(), "/etc/motd")
+
+	cmd, _ = newCmd()
+	require.NoError(t, cmd.Flags().Set("clear", "true"))
+	require.NoError(t, f.runHistory(cmd, []string{"myproject"}))
+	cmd, out = newCmd()
+	require.NoError(t, f.runHistory(cmd, []string{"myproject"}))
+	assert.Equal(t, "No commands recorded for dev-myproject\n", out.String())
+}
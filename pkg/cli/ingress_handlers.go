@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+	"l8s/pkg/config"
+	"l8s/pkg/ingress"
+)
+
+// ingressRemote returns the proxy controller for the active connection
+func (f *CommandFactory) ingressRemote() (ingress.Remote, error) {
+	address, err := f.Config.GetActiveAddress()
+	if err != nil {
+		return ingress.Remote{}, fmt.Errorf("failed to get active connection: %w", err)
+	}
+	return ingress.Remote{User: f.Config.RemoteUser, Address: address}, nil
+}
+
+// ingressRoutes maps every container with a web port to <name>.<domain>
+func (f *CommandFactory) ingressRoutes(ctx context.Context, domain string) ([]ingress.Route, error) {
+	containers, err := f.ContainerMgr.ListContainers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	var routes []ingress.Route
+	for _, c := range containers {
+		if c.WebPort == 0 {
+			continue
+		}
+		name := strings.TrimPrefix(c.Name, f.Config.ContainerPrefix+"-")
+		routes = append(routes, ingress.Route{Host: name + "." + domain, Port: c.WebPort})
+	}
+	return routes, nil
+}
+
+// ingressURL is the address a container is served at through the proxy
+func ingressURL(in config.IngressConfig, name string) string {
+	scheme := "http"
+	if in.TLS == "auto" || in.TLS == "internal" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s.%s", scheme, name, in.Domain)
+}
+
+// saveIngress records the active connection's ingress settings in the config file
+func (f *CommandFactory) saveIngress(in config.IngressConfig) error {
+	path := config.GetConfigPath()
+	data, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	prefix := "connections." + f.Config.ActiveConnection + ".ingress."
+	values := map[string]string{
+		"enabled": strconv.FormatBool(in.Enabled),
+		"domain":  in.Domain,
+		"tls":     in.TLS,
+	}
+	for _, key := range []string{"enabled", "domain", "tls"} {
+		if data, err = config.SetValue(data, prefix+key, values[key]); err != nil {
+			return err
+		}
+	}
+	return config.WriteFile(path, data)
+}
+
+// activeIngress returns the active connection's ingress settings
+func (f *CommandFactory) activeIngress() (config.IngressConfig, error) {
+	conn, err := f.Config.GetActiveConnection()
+	if err != nil {
+		return config.IngressConfig{}, err
+	}
+	return conn.Ingress, nil
+}
+
+// runIngressEnable starts the reverse proxy on the active connection's host
+func (f *CommandFactory) runIngressEnable(cmd *cobra.Command, args []string) error {
+	in, err := f.activeIngress()
+	if err != nil {
+		return err
+	}
+	if domain, _ := cmd.Flags().GetString("domain"); domain != "" {
+		in.Domain = domain
+	}
+	if cmd.Flags().Changed("tls") {
+		in.TLS, _ = cmd.Flags().GetString("tls")
+	}
+	in.Enabled = true
+	if in.Domain == "" {
+		return fmt.Errorf("no ingress domain configured\nRun: l8s ingress enable --domain dev.example.com")
+	}
+
+	ctx := context.Background()
+	routes, err := f.ingressRoutes(ctx, in.Domain)
+	if err != nil {
+		return err
+	}
+	remote, err := f.ingressRemote()
+	if err != nil {
+		return err
+	}
+
+	color.Printf("{cyan}→{reset} Starting ingress on %s...\n", remote.Address)
+	if err := remote.Enable(ingress.Caddyfile(routes, in.TLS)); err != nil {
+		return err
+	}
+	if err := f.saveIngress(in); err != nil {
+		return err
+	}
+
+	color.Printf("{green}✓{reset} Ingress enabled for {bold}*.%s{reset}\n", in.Domain)
+	color.Printf("{dim}Point a wildcard DNS record for *.%s at %s{reset}\n", in.Domain, remote.Address)
+	for _, r := range routes {
+		name := strings.TrimSuffix(r.Host, "."+in.Domain)
+		fmt.Printf("  %s → %s\n", ingressURL(in, name), name)
+	}
+	return nil
+}
+
+// runIngressDisable stops the reverse proxy
+func (f *CommandFactory) runIngressDisable(cmd *cobra.Command, args []string) error {
+	in, err := f.activeIngress()
+	if err != nil {
+		return err
+	}
+	remote, err := f.ingressRemote()
+	if err != nil {
+		return err
+	}
+	if err := remote.Disable(); err != nil {
+		return err
+	}
+	in.Enabled = false
+	if err := f.saveIngress(in); err != nil {
+		return err
+	}
+	color.Printf("{green}✓{reset} Ingress disabled on %s\n", remote.Address)
+	return nil
+}
+
+// runIngressStatus shows whether the proxy is running and the routes it serves
+func (f *CommandFactory) runIngressStatus(cmd *cobra.Command, args []string) error {
+	in, err := f.activeIngress()
+	if err != nil {
+		return err
+	}
+	if !in.Enabled {
+		color.Printf("{dim}Ingress is not enabled for %s (run 'l8s ingress enable --domain <domain>'){reset}\n", f.Config.ActiveConnection)
+		return nil
+	}
+
+	remote, err := f.ingressRemote()
+	if err != nil {
+		return err
+	}
+	running, err := remote.Running()
+	if err != nil {
+		return err
+	}
+	if running {
+		color.Printf("{green}✓{reset} Ingress running on %s for {bold}*.%s{reset}\n", remote.Address, in.Domain)
+	} else {
+		color.Printf("{red}✗{reset} Ingress is enabled but not running on %s (run 'l8s ingress enable')\n", remote.Address)
+	}
+
+	routes, err := f.ingressRoutes(context.Background(), in.Domain)
+	if err != nil {
+		return err
+	}
+	for _, r := range routes {
+		name := strings.TrimSuffix(r.Host, "."+in.Domain)
+		fmt.Printf("  %s → localhost:%d\n", ingressURL(in, name), r.Port)
+	}
+	return nil
+}
+
+// runIngressSync regenerates the proxy routes from the current containers
+func (f *CommandFactory) runIngressSync(cmd *cobra.Command, args []string) error {
+	in, err := f.activeIngress()
+	if err != nil {
+		return err
+	}
+	if !in.Enabled {
+		return fmt.Errorf("ingress is not enabled for %s", f.Config.ActiveConnection)
+	}
+	if err := f.applyIngress(in); err != nil {
+		return err
+	}
+	color.Printf("{green}✓{reset} Ingress routes updated\n")
+	return nil
+}
+
+// applyIngress pushes the current routes to the running proxy
+func (f *CommandFactory) applyIngress(in config.IngressConfig) error {
+	routes, err := f.ingressRoutes(context.Background(), in.Domain)
+	if err != nil {
+		return err
+	}
+	remote, err := f.ingressRemote()
+	if err != nil {
+		return err
+	}
+	return remote.Sync(ingress.Caddyfile(routes, in.TLS))
+}
+
+// syncIngress updates the proxy after containers are created or removed.
+// Problems are reported but never fail the command.
+func (f *CommandFactory) syncIngress() {
+	in, err := f.activeIngress()
+	if err != nil || !in.Enabled {
+		return
+	}
+	if err := f.applyIngress(in); err != nil {
+		color.Printf("{yellow}!{reset} Failed to update ingress routes: %v\n", err)
+	}
+}
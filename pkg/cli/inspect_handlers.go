@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// inspectFuncs are available in inspect --format templates, matching the
+// helpers podman and docker provide
+var inspectFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// formatInspect renders inspect JSON through a Go template such as
+// '{{.State.Status}}' or '{{json .Mounts}}'
+func formatInspect(w io.Writer, data []byte, format string) error {
+	tmpl, err := template.New("format").Funcs(inspectFuncs).Option("missingkey=error").Parse(format)
+	if err != nil {
+		return fmt.Errorf("invalid format template: %w", err)
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to decode inspect output: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, doc); err != nil {
+		return fmt.Errorf("failed to apply format template: %w", err)
+	}
+	if !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+		out.WriteByte('\n')
+	}
+	_, err = w.Write(out.Bytes())
+	return err
+}
+
+// runInspect prints the raw podman inspect document for a container
+func (f *CommandFactory) runInspect(cmd *cobra.Command, args []string) error {
+	name := strings.TrimPrefix(args[0], f.Config.ContainerPrefix+"-")
+
+	data, err := f.ContainerMgr.InspectContainer(context.Background(), name)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	if format, _ := cmd.Flags().GetString("format"); format != "" {
+		return formatInspect(cmd.OutOrStdout(), data, format)
+	}
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+	return err
+}
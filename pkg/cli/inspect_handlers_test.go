@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatInspect(t *testing.T) {
+	data := []byte(`{"Name": "dev-app", "State": {"Status": "running"}, "Mounts": [{"Destination": "/workspace"}], "Args": ["a", "b"]}`)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"{{.State.Status}}", "running\n"},
+		{"{{json .Mounts}}", "[{\"Destination\":\"/workspace\"}]\n"},
+		{"{{range .Mounts}}{{.Destination}}\n{{end}}", "/workspace\n"},
+		{"{{upper .Name}}", "DEV-APP\n"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		require.NoError(t, formatInspect(&out, data, tt.format), tt.format)
+		assert.Equal(t, tt.want, out.String(), tt.format)
+	}
+
+	var out bytes.Buffer
+	assert.Error(t, formatInspect(&out, data, "{{.State.Missing}}"))
+	assert.Error(t, formatInspect(&out, data, "{{.State"))
+}
@@ -2,23 +2,51 @@ package cli
 
 import (
 	"context"
+	"io"
+	"time"
 
+	"l8s/pkg/backup"
 	"l8s/pkg/container"
+	"l8s/pkg/git"
 )
 
 // ContainerManager defines the interface for container management operations
 type ContainerManager interface {
-	CreateContainer(ctx context.Context, name, sshKey string) (*container.Container, error)
+	CreateContainer(ctx context.Context, name, sshKey string, opts container.CreateOptions) (*container.Container, error)
 	ListContainers(ctx context.Context) ([]*container.Container, error)
 	RemoveContainer(ctx context.Context, name string, removeVolumes bool) error
 	StartContainer(ctx context.Context, name string) error
 	StopContainer(ctx context.Context, name string) error
 	GetContainerInfo(ctx context.Context, name string) (*container.Container, error)
+	InspectContainer(ctx context.Context, name string) ([]byte, error)
+	WaitForSSH(ctx context.Context, name string) error
+	AuditSecurity(ctx context.Context, name string) ([]container.SecurityCheck, error)
+	SSHAuthStats(ctx context.Context, name string) (*container.SSHAuthStats, error)
+	RemoteVersion(ctx context.Context) (*container.RemoteVersion, error)
 	ExecContainer(ctx context.Context, name string, cmd []string) error
+	ExecContainerOutput(ctx context.Context, name string, cmd []string) (string, error)
 	ExecContainerWithInput(ctx context.Context, name string, cmd []string, input []byte) error
-	SSHIntoContainer(ctx context.Context, name string) error
+	ExecContainerStream(ctx context.Context, name string, cmd []string, stdout, stderr io.Writer) error
+	SSHIntoContainer(ctx context.Context, name string, opts container.SSHOptions) error
 	BuildImage(ctx context.Context, containerfile string) error
 	RebuildContainer(ctx context.Context, name string) error
+	UpdateLabels(ctx context.Context, name string, set map[string]string, unset []string) error
+	GetActivity(ctx context.Context, name string) (*container.Activity, error)
+	BlueGreenRebuildContainer(ctx context.Context, name, healthCmd string) error
+	CloneContainer(ctx context.Context, source, name string) (*container.Container, error)
+	AdoptContainer(ctx context.Context, source, name string, opts container.AdoptOptions) (*container.Container, error)
+	DisownContainer(ctx context.Context, name string) error
+	MigratePrefix(ctx context.Context, name, oldPrefix, newPrefix string) (*container.Container, error)
+	BackupContainer(ctx context.Context, name string, store backup.Store, ts time.Time) ([]backup.Entry, error)
+	TrashContainer(ctx context.Context, name string) error
+	ListTrash(ctx context.Context) ([]*container.TrashedContainer, error)
+	RestoreContainer(ctx context.Context, name string) (*container.Container, error)
+	PurgeTrash(ctx context.Context, retention time.Duration) ([]*container.TrashedContainer, error)
+	InstallAgentSettings(ctx context.Context, name, agent string) (bool, error)
+	Drift(ctx context.Context, name string) (*container.Drift, error)
+	DiskUsage(ctx context.Context) (*container.DiskReport, error)
+	FindRemnants(ctx context.Context) (*container.Remnants, error)
+	RemoveRemnants(ctx context.Context, remnants *container.Remnants) error
 }
 
 // GitClient defines the interface for git operations
@@ -33,7 +61,14 @@ type GitClient interface {
 	IsGitRepository(path string) bool
 	GetRepositoryRoot(path string) (string, error)
 	PushBranch(repoPath, branch, remoteName string, force bool) error
+	PushShallow(repoPath, branch, remoteName string, depth int) error
 	InitRepository(repoPath string, allowPush bool, defaultBranch string) error
+	HasCommit(repoPath, sha string) bool
+	ResolveRef(repoPath, ref string) (string, error)
+	PushRef(repoPath, ref, remoteName, branch string) error
+	CreateBranch(repoPath, branch, startPoint string) error
+	ListWorktrees(repoPath string) ([]git.Worktree, error)
+	AheadBehind(repoPath, a, b string) (ahead, behind int, err error)
 }
 
 // SSHClient defines the interface for SSH operations
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/juju/ansiterm"
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+	"l8s/pkg/container"
+)
+
+// labelKeyPattern restricts user label keys to characters podman accepts unquoted
+var labelKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// userLabels returns the user-set labels of a container without their namespace prefix
+func userLabels(c *container.Container) map[string]string {
+	labels := make(map[string]string)
+	for k, v := range c.Labels {
+		if key, ok := strings.CutPrefix(k, container.LabelUserPrefix); ok {
+			labels[key] = v
+		}
+	}
+	return labels
+}
+
+// parseLabelAssignments parses key=value arguments into a label map
+func parseLabelAssignments(args []string) (map[string]string, error) {
+	labels := make(map[string]string, len(args))
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid label '%s': expected key=value", arg)
+		}
+		if !labelKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid label key '%s': use letters, digits, '.', '_' or '-'", key)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// runLabelSet adds or updates labels on a container
+func (f *CommandFactory) runLabelSet(cmd *cobra.Command, args []string) error {
+	name := strings.TrimPrefix(args[0], f.Config.ContainerPrefix+"-")
+	labels, err := parseLabelAssignments(args[1:])
+	if err != nil {
+		return err
+	}
+
+	set := make(map[string]string, len(labels))
+	for k, v := range labels {
+		set[container.LabelUserPrefix+k] = v
+	}
+
+	color.Printf("{cyan}→{reset} Recreating {bold}%s-%s{reset} to update labels (volumes and ports are kept)...\n", f.Config.ContainerPrefix, name)
+	if err := f.ContainerMgr.UpdateLabels(context.Background(), name, set, nil); err != nil {
+		return fmt.Errorf("failed to update container labels: %w", err)
+	}
+	color.Printf("{green}✓{reset} Set %d label(s)\n", len(labels))
+	return nil
+}
+
+// runLabelUnset removes labels from a container
+func (f *CommandFactory) runLabelUnset(cmd *cobra.Command, args []string) error {
+	name := strings.TrimPrefix(args[0], f.Config.ContainerPrefix+"-")
+
+	unset := make([]string, 0, len(args)-1)
+	for _, key := range args[1:] {
+		// Accept key=value too so set commands can be edited into unset ones
+		key, _, _ = strings.Cut(key, "=")
+		unset = append(unset, container.LabelUserPrefix+key)
+	}
+
+	color.Printf("{cyan}→{reset} Recreating {bold}%s-%s{reset} to update labels (volumes and ports are kept)...\n", f.Config.ContainerPrefix, name)
+	if err := f.ContainerMgr.UpdateLabels(context.Background(), name, nil, unset); err != nil {
+		return fmt.Errorf("failed to update container labels: %w", err)
+	}
+	color.Printf("{green}✓{reset} Removed %d label(s)\n", len(unset))
+	return nil
+}
+
+// runLabelList shows the user labels of one container, or of all containers
+func (f *CommandFactory) runLabelList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	var containers []*container.Container
+	if len(args) == 1 {
+		name := strings.TrimPrefix(args[0], f.Config.ContainerPrefix+"-")
+		c, err := f.ContainerMgr.GetContainerInfo(ctx, name)
+		if err != nil {
+			return fmt.Errorf("container '%s' not found: %w", name, err)
+		}
+		containers = append(containers, c)
+	} else {
+		all, err := f.ContainerMgr.ListContainers(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list containers: %w", err)
+		}
+		containers = all
+	}
+
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	if os.Getenv("NO_COLOR") == "" {
+		fmt.Fprintf(w, "%s\t%s\t%s\n",
+			color.Bold("CONTAINER"),
+			color.Bold("KEY"),
+			color.Bold("VALUE"))
+	} else {
+		fmt.Fprintln(w, "CONTAINER\tKEY\tVALUE")
+	}
+
+	found := false
+	for _, c := range containers {
+		labels := userLabels(c)
+		keys := make([]string, 0, len(labels))
+		for k := range labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", c.Name, k, labels[k])
+			found = true
+		}
+	}
+
+	if !found {
+		fmt.Fprintln(cmd.OutOrStdout(), "No labels set")
+		return nil
+	}
+	return w.Flush()
+}
+
+// runNote shows, sets or clears the free-form description of a container
+func (f *CommandFactory) runNote(cmd *cobra.Command, args []string) error {
+	name := strings.TrimPrefix(args[0], f.Config.ContainerPrefix+"-")
+	fullName := fmt.Sprintf("%s-%s", f.Config.ContainerPrefix, name)
+	clearNote, _ := cmd.Flags().GetBool("clear")
+	ctx := context.Background()
+
+	if len(args) == 1 && !clearNote {
+		c, err := f.ContainerMgr.GetContainerInfo(ctx, name)
+		if err != nil {
+			return fmt.Errorf("container '%s' not found: %w", name, err)
+		}
+		if note := c.Labels[container.LabelNote]; note != "" {
+			fmt.Fprintln(cmd.OutOrStdout(), note)
+		} else {
+			color.Printf("{dim}No note set for %s{reset}\n", fullName)
+		}
+		return nil
+	}
+	if len(args) > 1 && clearNote {
+		return fmt.Errorf("--clear does not take a note")
+	}
+
+	var set map[string]string
+	var unset []string
+	if clearNote {
+		unset = []string{container.LabelNote}
+	} else {
+		set = map[string]string{container.LabelNote: strings.Join(args[1:], " ")}
+	}
+
+	color.Printf("{cyan}→{reset} Recreating {bold}%s{reset} to update labels (volumes and ports are kept)...\n", fullName)
+	if err := f.ContainerMgr.UpdateLabels(ctx, name, set, unset); err != nil {
+		return fmt.Errorf("failed to update container labels: %w", err)
+	}
+	if clearNote {
+		color.Printf("{green}✓{reset} Note cleared\n")
+	} else {
+		color.Printf("{green}✓{reset} Note saved\n")
+	}
+	return nil
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
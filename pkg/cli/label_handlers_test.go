@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLabelAssignments(t *testing.T) {
+	labels, err := parseLabelAssignments([]string{"team=infra", "ticket=ABC-123", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "infra", "ticket": "ABC-123", "empty": ""}, labels)
+
+	_, err = parseLabelAssignments([]string{"team"})
+	assert.Error(t, err)
+
+	_, err = parseLabelAssignments([]string{"bad key=x"})
+	assert.Error(t, err)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 10))
+	assert.Equal(t, "exactly10!", truncate("exactly10!", 10))
+	assert.Equal(t, "a longer …", truncate("a longer note", 10))
+}
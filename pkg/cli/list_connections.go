@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"l8s/pkg/container"
+)
+
+// defaultConnectionTimeout is how long list --all-connections waits for each
+// connection to connect and answer
+const defaultConnectionTimeout = 10 * time.Second
+
+// connectionListing is what one connection returned for list, with a factory
+// bound to that connection for per-container lookups
+type connectionListing struct {
+	name       string
+	f          *CommandFactory
+	containers []*container.Container
+	err        error
+}
+
+// connectionFactory returns a copy of f that runs against the named
+// connection using mgr
+func (f *CommandFactory) connectionFactory(name string, mgr ContainerManager) *CommandFactory {
+	cfg := *f.Config
+	cfg.ActiveConnection = name
+	return &CommandFactory{
+		Config:       &cfg,
+		ContainerMgr: mgr,
+		GitClient:    f.GitClient,
+		SSHClient:    f.SSHClient,
+	}
+}
+
+// listConnections lists the containers on every configured connection at
+// once, sorted by connection name. Each connection gets timeout to connect
+// and answer; one that does not is reported in its listing's err and left
+// running in the background rather than holding up the others.
+func (f *CommandFactory) listConnections(ctx context.Context, timeout time.Duration, connect func(name string) (ContainerManager, error)) []connectionListing {
+	names := make([]string, 0, len(f.Config.Connections))
+	for name := range f.Config.Connections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	listings := make([]connectionListing, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			// Connecting does not take a context, so the wait is bounded here
+			done := make(chan connectionListing, 1)
+			go func() {
+				mgr, err := connect(name)
+				if err != nil {
+					done <- connectionListing{name: name, err: err}
+					return
+				}
+				containers, err := mgr.ListContainers(ctx)
+				done <- connectionListing{
+					name:       name,
+					f:          f.connectionFactory(name, mgr),
+					containers: containers,
+					err:        err,
+				}
+			}()
+
+			select {
+			case listings[i] = <-done:
+			case <-ctx.Done():
+				listings[i] = connectionListing{name: name, err: fmt.Errorf("no answer within %s", timeout)}
+			}
+		}(i, name)
+	}
+	wg.Wait()
+	return listings
+}
+
+// formatConnectionErrors joins the errors of failed listings for one message
+func formatConnectionErrors(failed []connectionListing) string {
+	parts := make([]string, len(failed))
+	for i, l := range failed {
+		parts[i] = fmt.Sprintf("%s: %v", l.name, l.err)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// printConnectionErrors reports connections missing from a partial listing.
+// stderr keeps list -q output clean.
+func printConnectionErrors(failed []connectionListing) {
+	for _, l := range failed {
+		fmt.Fprintf(os.Stderr, "! %s not listed: %v\n", l.name, l.err)
+	}
+}
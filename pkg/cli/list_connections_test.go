@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListConnections(t *testing.T) {
+	f := &CommandFactory{Config: &config.Config{
+		ActiveConnection: "home",
+		Connections: map[string]config.ConnectionConfig{
+			"home":  {Address: "home.example.com"},
+			"cloud": {Address: "cloud.example.com"},
+			"vpn":   {Address: "10.8.0.1"},
+		},
+	}}
+
+	home := new(MockContainerManagerWithGit)
+	home.On("ListContainers", mock.Anything).Return([]*container.Container{{Name: "dev-api"}}, nil)
+	hang := make(chan struct{})
+	defer close(hang)
+
+	connect := func(name string) (ContainerManager, error) {
+		switch name {
+		case "home":
+			return home, nil
+		case "vpn":
+			<-hang // an unreachable host never answers the SSH dial
+		}
+		return nil, errors.New("connection refused")
+	}
+
+	start := time.Now()
+	listings := f.listConnections(context.Background(), 50*time.Millisecond, connect)
+	assert.Less(t, time.Since(start), time.Second)
+
+	require.Len(t, listings, 3)
+	assert.Equal(t, "cloud", listings[0].name)
+	assert.ErrorContains(t, listings[0].err, "connection refused")
+
+	assert.Equal(t, "home", listings[1].name)
+	require.NoError(t, listings[1].err)
+	assert.Len(t, listings[1].containers, 1)
+	assert.Equal(t, "home", listings[1].f.Config.ActiveConnection)
+
+	assert.Equal(t, "vpn", listings[2].name)
+	assert.ErrorContains(t, listings[2].err, "no answer within")
+}
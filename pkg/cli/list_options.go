@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"l8s/pkg/container"
+)
+
+// listFilter is a single --filter key=value condition for l8s list
+type listFilter struct {
+	key   string
+	value string
+}
+
+// parseListFilters parses --filter arguments. Supported forms:
+//
+//	status=running     container is in the given state (stopped matches exited)
+//	owner=alice        container was created by the given local user
+//	label=key          container has the user label key
+//	label=key=value    container has the user label key set to value
+//	group=web          container belongs to the given group
+//	ticket=JIRA-123    container was created with 'l8s create --ticket'
+func parseListFilters(specs []string) ([]listFilter, error) {
+	filters := make([]listFilter, 0, len(specs))
+	for _, spec := range specs {
+		key, value, ok := strings.Cut(spec, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid filter '%s': expected key=value", spec)
+		}
+		switch key {
+		case "status", "owner", "label", "group", "ticket":
+		default:
+			return nil, fmt.Errorf("unknown filter '%s'\nSupported filters: status, owner, label, group, ticket", key)
+		}
+		filters = append(filters, listFilter{key: key, value: value})
+	}
+	return filters, nil
+}
+
+// matches reports whether a container satisfies the filter
+func (lf listFilter) matches(c *container.Container) bool {
+	switch lf.key {
+	case "status":
+		if lf.value == "stopped" {
+			return c.Status == "stopped" || c.Status == "exited"
+		}
+		return c.Status == lf.value
+	case "owner":
+		return c.Labels[container.LabelOwner] == lf.value
+	case "group":
+		return c.Labels[container.LabelGroup] == lf.value
+	case "ticket":
+		return strings.EqualFold(c.Labels[container.LabelTicket], lf.value)
+	case "label":
+		key, want, hasValue := strings.Cut(lf.value, "=")
+		got, ok := userLabels(c)[key]
+		return ok && (!hasValue || got == want)
+	}
+	return false
+}
+
+// filterContainers keeps the containers matching every filter
+func filterContainers(containers []*container.Container, filters []listFilter) []*container.Container {
+	if len(filters) == 0 {
+		return containers
+	}
+	var kept []*container.Container
+	for _, c := range containers {
+		ok := true
+		for _, lf := range filters {
+			if !lf.matches(c) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// sortContainers orders containers in place by name, created (newest first) or
+// SSH port. An empty key keeps the order returned by podman.
+func sortContainers(containers []*container.Container, key string) error {
+	var less func(a, b *container.Container) bool
+	switch key {
+	case "":
+		return nil
+	case "name":
+		less = func(a, b *container.Container) bool { return a.Name < b.Name }
+	case "created":
+		less = func(a, b *container.Container) bool { return a.CreatedAt.After(b.CreatedAt) }
+	case "port":
+		less = func(a, b *container.Container) bool { return a.SSHPort < b.SSHPort }
+	default:
+		return fmt.Errorf("invalid sort key '%s': use name, created or port", key)
+	}
+	sort.SliceStable(containers, func(i, j int) bool {
+		return less(containers[i], containers[j])
+	})
+	return nil
+}
+
+// wideColumns returns the extra IMAGE, CONNECTION, OWNER, HEALTH, TICKET, PORTS and NOTE cells for list --wide
+func (f *CommandFactory) wideColumns(ctx context.Context, c *container.Container) []string {
+	image, health := c.Image, c.Health
+
+	// Health is only reported by inspect, so look running containers up individually
+	if c.Status == "running" && health == "" {
+		name := strings.TrimPrefix(c.Name, f.Config.ContainerPrefix+"-")
+		if info, err := f.ContainerMgr.GetContainerInfo(ctx, name); err == nil {
+			health = info.Health
+			if image == "" {
+				image = info.Image
+			}
+		}
+	}
+
+	if image == "" {
+		image = "-"
+	}
+	if health == "" {
+		health = "-"
+	}
+	connection := f.Config.ActiveConnection
+	if connection == "" {
+		connection = "-"
+	}
+	owner := c.Labels[container.LabelOwner]
+	if owner == "" {
+		owner = "-"
+	}
+	ticket := c.Labels[container.LabelTicket]
+	if ticket == "" {
+		ticket = "-"
+	}
+	note := "-"
+	if n := c.Labels[container.LabelNote]; n != "" {
+		note = truncate(n, 40)
+	}
+	return []string{image, connection, owner, health, ticket, publishedPorts(c), note}
+}
+
+// publishedPorts formats a container's published host ports for list --wide,
+// e.g. 60000-60010/udp,8443/tcp
+func publishedPorts(c *container.Container) string {
+	mappings := container.ParsePortMappings(c.Labels[container.LabelPorts])
+	if len(mappings) == 0 {
+		return "-"
+	}
+	ports := make([]string, len(mappings))
+	for i, p := range mappings {
+		ports[i] = p.HostPorts() + "/" + p.Protocol
+	}
+	return strings.Join(ports, ",")
+}
+
+// idleColumns looks up the idle agent status of running containers in parallel
+// and returns the IDLE cell for each container name
+func (f *CommandFactory) idleColumns(ctx context.Context, containers []*container.Container) map[string]string {
+	cells := make(map[string]string, len(containers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	now := time.Now()
+	for _, c := range containers {
+		cells[c.Name] = "-"
+		if c.Status != "running" {
+			continue
+		}
+		wg.Add(1)
+		go func(c *container.Container) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			name := strings.TrimPrefix(c.Name, f.Config.ContainerPrefix+"-")
+			activity, err := f.ContainerMgr.GetActivity(ctx, name)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			cells[c.Name] = formatIdle(activity.Idle(now))
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+	return cells
+}
+
+// formatIdle renders an idle duration compactly for the IDLE column
+func formatIdle(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "active"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
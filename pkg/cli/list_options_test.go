@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"l8s/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListFilters(t *testing.T) {
+	containers := []*container.Container{
+		{Name: "dev-a", Status: "running", Labels: map[string]string{container.LabelUserPrefix + "team": "infra"}},
+		{Name: "dev-b", Status: "exited", Labels: map[string]string{container.LabelUserPrefix + "team": "web", container.LabelGroup: "shop"}},
+		{Name: "dev-c", Status: "running", Labels: map[string]string{"team": "infra", container.LabelOwner: "alice", container.LabelTicket: "JIRA-7"}},
+	}
+
+	tests := []struct {
+		name    string
+		specs   []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "no filters", specs: nil, want: []string{"dev-a", "dev-b", "dev-c"}},
+		{name: "label present", specs: []string{"label=team"}, want: []string{"dev-a", "dev-b"}},
+		{name: "label value", specs: []string{"label=team=infra"}, want: []string{"dev-a"}},
+		{name: "no match", specs: []string{"label=owner"}, want: nil},
+		{name: "status", specs: []string{"status=running"}, want: []string{"dev-a", "dev-c"}},
+		{name: "stopped matches exited", specs: []string{"status=stopped"}, want: []string{"dev-b"}},
+		{name: "owner", specs: []string{"owner=alice"}, want: []string{"dev-c"}},
+		{name: "group", specs: []string{"group=shop"}, want: []string{"dev-b"}},
+		{name: "ticket ignores case", specs: []string{"ticket=jira-7"}, want: []string{"dev-c"}},
+		{name: "combined", specs: []string{"status=running", "label=team"}, want: []string{"dev-a"}},
+		{name: "unknown key", specs: []string{"colour=red"}, wantErr: true},
+		{name: "missing value", specs: []string{"label"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := parseListFilters(tt.specs)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var names []string
+			for _, c := range filterContainers(containers, filters) {
+				names = append(names, c.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestSortContainers(t *testing.T) {
+	now := time.Now()
+	containers := []*container.Container{
+		{Name: "dev-b", SSHPort: 2201, CreatedAt: now.Add(-time.Hour)},
+		{Name: "dev-c", SSHPort: 2200, CreatedAt: now},
+		{Name: "dev-a", SSHPort: 2202, CreatedAt: now.Add(-2 * time.Hour)},
+	}
+
+	names := func() []string {
+		var out []string
+		for _, c := range containers {
+			out = append(out, c.Name)
+		}
+		return out
+	}
+
+	require.NoError(t, sortContainers(containers, "name"))
+	assert.Equal(t, []string{"dev-a", "dev-b", "dev-c"}, names())
+
+	require.NoError(t, sortContainers(containers, "created"))
+	assert.Equal(t, []string{"dev-c", "dev-b", "dev-a"}, names())
+
+	require.NoError(t, sortContainers(containers, "port"))
+	assert.Equal(t, []string{"dev-c", "dev-b", "dev-a"}, names())
+
+	assert.Error(t, sortContainers(containers, "size"))
+}
+
+func TestFormatIdle(t *testing.T) {
+	assert.Equal(t, "active", formatIdle(30*time.Second))
+	assert.Equal(t, "45m", formatIdle(45*time.Minute))
+	assert.Equal(t, "5h", formatIdle(5*time.Hour+10*time.Minute))
+	assert.Equal(t, "3d", formatIdle(80*time.Hour))
+}
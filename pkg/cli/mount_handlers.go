@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+)
+
+// macFUSEPath is where macFUSE installs its filesystem bundle
+const macFUSEPath = "/Library/Filesystems/macfuse.fs"
+
+// defaultMountPath returns ~/l8s-mounts/<name>
+func defaultMountPath(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, "l8s-mounts", name), nil
+}
+
+// mountPath resolves the local mount point from the optional argument
+func mountPath(name string, args []string) (string, error) {
+	if len(args) < 2 {
+		return defaultMountPath(name)
+	}
+	return filepath.Abs(args[1])
+}
+
+// errMountUnsupported is returned on hosts without FUSE
+var errMountUnsupported = errors.New("mounting is not supported on Windows hosts\nUse VS Code Remote-SSH or 'l8s ssh' to work with container files")
+
+// checkSSHFS verifies sshfs (and macFUSE on macOS) is installed
+func checkSSHFS() error {
+	if runtime.GOOS == "windows" {
+		return errMountUnsupported
+	}
+	if runtime.GOOS == "darwin" {
+		if _, err := os.Stat(macFUSEPath); err != nil {
+			return fmt.Errorf("macFUSE not found\nInstall it with: brew install --cask macfuse")
+		}
+	}
+	if _, err := exec.LookPath("sshfs"); err != nil {
+		if runtime.GOOS == "darwin" {
+			return fmt.Errorf("sshfs not found\nInstall it with: brew install gromgit/fuse/sshfs-mac")
+		}
+		return fmt.Errorf("sshfs not found\nInstall it with your package manager, e.g.: sudo apt install sshfs")
+	}
+	return nil
+}
+
+// parseMountPoints extracts mount points from `mount` output, which has the
+// form "<source> on <path> type ..." on Linux and "<source> on <path> (...)" on macOS
+func parseMountPoints(output string) []string {
+	var points []string
+	for _, line := range strings.Split(output, "\n") {
+		_, rest, ok := strings.Cut(line, " on ")
+		if !ok {
+			continue
+		}
+		if path, _, ok := strings.Cut(rest, " type "); ok {
+			points = append(points, path)
+		} else if path, _, ok := strings.Cut(rest, " ("); ok {
+			points = append(points, path)
+		}
+	}
+	return points
+}
+
+// isMounted reports whether path is currently a mount point
+func isMounted(path string) bool {
+	output, err := exec.Command("mount").Output()
+	if err != nil {
+		return false
+	}
+	for _, point := range parseMountPoints(string(output)) {
+		if point == path {
+			return true
+		}
+	}
+	return false
+}
+
+// runMount mounts the container's /workspace/project locally over SSHFS
+func (f *CommandFactory) runMount(cmd *cobra.Command, args []string) error {
+	name := strings.TrimPrefix(args[0], f.Config.ContainerPrefix+"-")
+	containerName := f.Config.ContainerPrefix + "-" + name
+
+	if err := checkSSHFS(); err != nil {
+		return err
+	}
+
+	info, err := f.ContainerMgr.GetContainerInfo(context.Background(), name)
+	if err != nil {
+		return fmt.Errorf("failed to get container info: %w", err)
+	}
+	if info.Status != "running" {
+		return fmt.Errorf("container '%s' is not running\nStart it with: l8s start %s", name, name)
+	}
+
+	path, err := mountPath(name, args)
+	if err != nil {
+		return err
+	}
+	if isMounted(path) {
+		color.Printf("{yellow}!{reset} %s is already mounted at %s\n", containerName, path)
+		return nil
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return fmt.Errorf("failed to create mount point: %w", err)
+	}
+
+	// The SSH config entry written at create time supplies port, user and key
+	options := "reconnect,ServerAliveInterval=15,ServerAliveCountMax=3,follow_symlinks"
+	if runtime.GOOS == "darwin" {
+		options += ",volname=" + containerName + ",defer_permissions,noappledouble"
+	}
+	sshfsCmd := exec.Command("sshfs", containerName+":/workspace/project", path, "-o", options)
+	sshfsCmd.Stdout = os.Stdout
+	sshfsCmd.Stderr = os.Stderr
+	if err := sshfsCmd.Run(); err != nil {
+		return fmt.Errorf("failed to mount %s: %w", containerName, err)
+	}
+
+	color.Printf("{green}✓{reset} Mounted %s:/workspace/project at {bold}%s{reset}\n", containerName, path)
+	if isWSL() {
+		color.Printf("  Windows path: {bold}%s{reset}\n", windowsPath(path))
+	}
+	color.Printf("{dim}Unmount with: l8s umount %s{reset}\n", name)
+	return nil
+}
+
+// runUmount unmounts a directory mounted by runMount
+func (f *CommandFactory) runUmount(cmd *cobra.Command, args []string) error {
+	name := strings.TrimPrefix(args[0], f.Config.ContainerPrefix+"-")
+	if runtime.GOOS == "windows" {
+		return errMountUnsupported
+	}
+
+	path, err := mountPath(name, args)
+	if err != nil {
+		return err
+	}
+	if !isMounted(path) {
+		return fmt.Errorf("nothing is mounted at %s", path)
+	}
+
+	var umountCmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		umountCmd = exec.Command("umount", path)
+	} else if _, err := exec.LookPath("fusermount3"); err == nil {
+		umountCmd = exec.Command("fusermount3", "-u", path)
+	} else {
+		umountCmd = exec.Command("fusermount", "-u", path)
+	}
+	umountCmd.Stdout = os.Stdout
+	umountCmd.Stderr = os.Stderr
+	if err := umountCmd.Run(); err != nil {
+		return fmt.Errorf("failed to unmount %s: %w\nClose any programs using the mount and try again", path, err)
+	}
+
+	// Only removes the mount point if it is empty, leaving user directories alone
+	_ = os.Remove(path)
+
+	color.Printf("{green}✓{reset} Unmounted %s\n", path)
+	return nil
+}
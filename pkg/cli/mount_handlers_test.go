@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMountPoints(t *testing.T) {
+	output := `/dev/sda1 on / type ext4 (rw,relatime)
+dev-app:/workspace/project on /home/me/l8s-mounts/app type fuse.sshfs (rw,nosuid,nodev)
+dev-web:/workspace/project on /Users/me/l8s mounts/web (macfuse, nodev, nosuid, mounted by me)
+garbage line
+`
+	assert.Equal(t, []string{
+		"/",
+		"/home/me/l8s-mounts/app",
+		"/Users/me/l8s mounts/web",
+	}, parseMountPoints(output))
+}
+
+func TestMountPath(t *testing.T) {
+	path, err := mountPath("app", []string{"app", "/tmp/app"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/app", path)
+
+	path, err = mountPath("app", []string{"app"})
+	assert.NoError(t, err)
+	assert.Contains(t, path, "l8s-mounts/app")
+}
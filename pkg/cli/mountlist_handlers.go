@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/juju/ansiterm"
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+	"l8s/pkg/container"
+)
+
+// mountMode describes how a mount is attached
+func mountMode(m container.Mount) string {
+	mode := "rw"
+	if m.ReadOnly {
+		mode = "ro"
+	}
+	if m.IsVolume() {
+		return mode + ", volume"
+	}
+	return mode
+}
+
+// runMountList shows host directories and shared volumes mounted into
+// containers and which containers use each one
+func (f *CommandFactory) runMountList(cmd *cobra.Command, args []string) error {
+	containers, err := f.ContainerMgr.ListContainers(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	users := make(map[container.Mount][]string)
+	for _, c := range containers {
+		for _, m := range container.ParseMounts(c.Labels[container.LabelMounts]) {
+			users[m] = append(users[m], strings.TrimPrefix(c.Name, f.Config.ContainerPrefix+"-"))
+		}
+	}
+	if len(users) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No shared mounts attached")
+		fmt.Fprintln(cmd.OutOrStdout(), "Declare them under 'mounts' in the config or .l8s.yaml")
+		return nil
+	}
+
+	mounts := make([]container.Mount, 0, len(users))
+	for m := range users {
+		mounts = append(mounts, m)
+	}
+	sort.Slice(mounts, func(i, j int) bool {
+		if mounts[i].Source != mounts[j].Source {
+			return mounts[i].Source < mounts[j].Source
+		}
+		return mounts[i].Target < mounts[j].Target
+	})
+
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	if os.Getenv("NO_COLOR") == "" {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", color.Bold("SOURCE"), color.Bold("TARGET"), color.Bold("MODE"), color.Bold("CONTAINERS"))
+	} else {
+		fmt.Fprintln(w, "SOURCE\tTARGET\tMODE\tCONTAINERS")
+	}
+	for _, m := range mounts {
+		names := users[m]
+		sort.Strings(names)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.Source, m.Target, mountMode(m), strings.Join(names, ", "))
+	}
+	return w.Flush()
+}
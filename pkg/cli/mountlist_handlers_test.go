@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+)
+
+func TestRunMountList(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	cm := new(MockContainerManagerWithGit)
+	cm.On("ListContainers", mock.Anything).Return([]*container.Container{
+		{Name: "dev-b", Labels: map[string]string{container.LabelMounts: "models:/models:ro,/scratch:/scratch"}},
+		{Name: "dev-a", Labels: map[string]string{container.LabelMounts: "models:/models:ro"}},
+		{Name: "dev-c", Labels: map[string]string{}},
+	}, nil)
+
+	f := &CommandFactory{Config: &config.Config{ContainerPrefix: "dev"}, ContainerMgr: cm}
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	require.NoError(t, f.runMountList(cmd, nil))
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+	assert.Regexp(t, `^/scratch\s+/scratch\s+rw\s+b$`, string(lines[1]))
+	assert.Regexp(t, `^models\s+/models\s+ro, volume\s+a, b$`, string(lines[2]))
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"l8s/pkg/color"
+	"l8s/pkg/container"
+	"l8s/pkg/notify"
+)
+
+// notifyEvent fires the configured webhooks for a container lifecycle event
+// on the active connection. Delivery problems are reported but never fail
+// the command.
+func (f *CommandFactory) notifyEvent(event, name string) {
+	f.notifyConnectionEvent(event, f.Config.ActiveConnection, name)
+}
+
+// notifyConnectionEvent fires the configured webhooks for an event on any
+// connection, such as one the API server acted on
+func (f *CommandFactory) notifyConnectionEvent(event, connection, name string) {
+	if len(f.Config.Webhooks) == 0 {
+		return
+	}
+
+	hooks := make([]notify.Hook, 0, len(f.Config.Webhooks))
+	for _, w := range f.Config.Webhooks {
+		hooks = append(hooks, w.Hook())
+	}
+
+	user := container.CurrentUser()
+	fullName := fmt.Sprintf("%s-%s", f.Config.ContainerPrefix, name)
+	e := notify.Event{
+		Event:      event,
+		Container:  fullName,
+		Connection: connection,
+		User:       user,
+		Text:       fmt.Sprintf("%s: %s ran %s on %s", connection, user, event, fullName),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := notify.New(hooks).Send(ctx, e); err != nil {
+		color.Printf("{yellow}!{reset} Webhook notification failed: %v\n", err)
+	}
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"l8s/pkg/color"
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+
+	"github.com/spf13/cobra"
+)
+
+// notifySocket is the socket in the container that l8s-notify writes to and
+// 'l8s notify' forwards to this machine
+const notifySocket = "/tmp/l8s-notify.sock"
+
+// runNotify shows notifications sent with l8s-notify in the current
+// worktree's container on the desktop, until interrupted
+func (f *CommandFactory) runNotify(cmd *cobra.Command, args []string) error {
+	if f.Config.Runtime == config.RuntimeFake {
+		return fmt.Errorf("notify needs SSH into the container, which the fake runtime does not provide")
+	}
+	name, err := f.worktreeContainer("notify")
+	if err != nil {
+		return err
+	}
+
+	// TCP rather than a local socket, which ssh on Windows cannot forward to
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to listen for notifications: %w", err)
+	}
+	defer listener.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go serveNotifications(listener, "l8s: "+name, func(title, message string) {
+		if output, err := desktopNotification(title, message).CombinedOutput(); err != nil {
+			color.Printf("{yellow}!{reset} Could not show notification: %s\n", strings.TrimSpace(string(output)))
+		}
+		color.Printf("{green}✓{reset} %s %s\n", time.Now().Format("15:04:05"), message)
+	})
+
+	sshDone := make(chan error, 1)
+	go func() {
+		sshDone <- f.ContainerMgr.SSHIntoContainer(ctx, name, container.SSHOptions{
+			RemoteForwards: []string{notifySocket + ":" + listener.Addr().String()},
+			ForwardOnly:    true,
+		})
+	}()
+
+	color.Printf("{cyan}→{reset} Forwarding notifications from {bold}%s{reset}, press Ctrl+C to stop\n", name)
+	color.Printf("{dim}In the container: l8s-notify [-t title] message{reset}\n")
+
+	select {
+	case err := <-sshDone:
+		if err == nil {
+			err = fmt.Errorf("connection closed")
+		}
+		return fmt.Errorf("notification forwarding ended: %w", err)
+	case <-ctx.Done():
+	}
+	color.Printf("\n{cyan}→{reset} Stopped forwarding notifications\n")
+	return nil
+}
+
+// serveNotifications reads "<title><TAB><message>" lines from connections to
+// listener and passes them to show, until the listener is closed. Lines
+// without a title get defaultTitle.
+func serveNotifications(listener net.Listener, defaultTitle string, show func(title, message string)) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				title, message, found := strings.Cut(scanner.Text(), "\t")
+				if !found {
+					title, message = "", title
+				}
+				if strings.TrimSpace(message) == "" {
+					continue
+				}
+				if strings.TrimSpace(title) == "" {
+					title = defaultTitle
+				}
+				show(title, message)
+			}
+		}()
+	}
+}
+
+// desktopNotification builds the command that shows a notification:
+// osascript on macOS, a toast through PowerShell on Windows and WSL, and
+// notify-send elsewhere
+func desktopNotification(title, message string) *exec.Cmd {
+	if usesWindowsClipboard() {
+		return powershell(fmt.Sprintf(`$ErrorActionPreference = 'Stop'
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$toast = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $toast.GetElementsByTagName('text')
+$text.Item(0).AppendChild($toast.CreateTextNode(%s)) > $null
+$text.Item(1).AppendChild($toast.CreateTextNode(%s)) > $null
+# Toasts need a registered app; PowerShell's own ID is always present
+$app = '{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe'
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($app).Show([Windows.UI.Notifications.ToastNotification]::new($toast))`,
+			psQuote(title), psQuote(message)))
+	}
+	if runtime.GOOS == "darwin" {
+		// Passed as arguments so the text needs no AppleScript quoting
+		return exec.Command("osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, message)
+	}
+	return exec.Command("notify-send", "--app-name=l8s", "--", title, message)
+}
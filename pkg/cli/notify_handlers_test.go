@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeNotifications(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 4)
+	go serveNotifications(listener, "l8s: myproject", func(title, message string) {
+		received <- title + "|" + message
+	})
+
+	for _, line := range []string{"\ttests passed\n", "FAILED\ttests failed\n", "no title\n", "\t\n"} {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		require.NoError(t, err)
+		fmt.Fprint(conn, line)
+		conn.Close()
+	}
+
+	var got []string
+	for len(got) < 3 {
+		select {
+		case msg := <-received:
+			got = append(got, msg)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only received %v", got)
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"l8s: myproject|tests passed",
+		"FAILED|tests failed",
+		"l8s: myproject|no title",
+	}, got)
+}
+
+func TestDesktopNotification(t *testing.T) {
+	if runtime.GOOS == "darwin" || usesWindowsClipboard() {
+		t.Skip("checks the notify-send command used on Linux")
+	}
+	cmd := desktopNotification("l8s: api", "-done")
+	assert.Equal(t, []string{"notify-send", "--app-name=l8s", "--", "l8s: api", "-done"}, cmd.Args)
+}
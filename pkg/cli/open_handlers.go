@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+)
+
+// containerWebPort is the port the container's web service listens on
+const containerWebPort = 3000
+
+// namedPorts maps port names accepted by l8s open to container ports
+var namedPorts = map[string]int{
+	"web": containerWebPort,
+}
+
+// parseContainerPort resolves a port argument (a name or number) to a container port
+func parseContainerPort(arg string) (int, error) {
+	if port, ok := namedPorts[strings.ToLower(arg)]; ok {
+		return port, nil
+	}
+	port, err := strconv.Atoi(arg)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port '%s': use a port number or 'web'", arg)
+	}
+	return port, nil
+}
+
+// browserCommand returns the command that opens url in the default browser
+func browserCommand(url string) *exec.Cmd {
+	switch {
+	case runtime.GOOS == "darwin":
+		return exec.Command("open", url)
+	case runtime.GOOS == "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	case isWSL():
+		return exec.Command("cmd.exe", "/c", "start", "", url)
+	default:
+		return exec.Command("xdg-open", url)
+	}
+}
+
+// openBrowser opens url, printing it instead if no browser can be started
+func openBrowser(url string) {
+	if err := browserCommand(url).Run(); err != nil {
+		color.Printf("{yellow}!{reset} Could not open a browser; visit {bold}%s{reset}\n", url)
+		return
+	}
+	color.Printf("{green}✓{reset} Opened {bold}%s{reset}\n", url)
+}
+
+// isReachable reports whether a TCP connection to address can be made
+func isReachable(address string, timeout time.Duration) bool {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// freeLocalPort returns preferred if it can be bound locally, otherwise any free port
+func freeLocalPort(preferred int) (int, error) {
+	if l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", preferred)); err == nil {
+		l.Close()
+		return preferred, nil
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free local port: %w", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// runOpen opens a container's web port in the browser, tunnelling over SSH
+// when the port is not published or not reachable from this machine
+func (f *CommandFactory) runOpen(cmd *cobra.Command, args []string) error {
+	var name string
+	if len(args) > 0 {
+		name = strings.TrimPrefix(args[0], f.Config.ContainerPrefix+"-")
+	} else {
+		fullName, err := GetContainerNameFromWorktree(f.Config.ContainerPrefix)
+		if err != nil {
+			return fmt.Errorf("no container given and %w", err)
+		}
+		name = strings.TrimPrefix(fullName, f.Config.ContainerPrefix+"-")
+	}
+	containerName := f.Config.ContainerPrefix + "-" + name
+
+	port := containerWebPort
+	if len(args) > 1 {
+		var err error
+		if port, err = parseContainerPort(args[1]); err != nil {
+			return err
+		}
+	}
+
+	cont, err := f.ContainerMgr.GetContainerInfo(context.Background(), name)
+	if err != nil {
+		return fmt.Errorf("failed to get container info: %w", err)
+	}
+	if cont.Status != "running" {
+		return fmt.Errorf("container '%s' is not running\nStart it with: l8s start %s", name, name)
+	}
+
+	forceTunnel, _ := cmd.Flags().GetBool("tunnel")
+	if port == containerWebPort && cont.WebPort > 0 && !forceTunnel {
+		address, err := f.Config.GetActiveAddress()
+		if err != nil {
+			return fmt.Errorf("failed to get active connection: %w", err)
+		}
+		hostPort := net.JoinHostPort(address, strconv.Itoa(cont.WebPort))
+		if isReachable(hostPort, 3*time.Second) {
+			openBrowser("http://" + hostPort)
+			return nil
+		}
+		color.Printf("{yellow}!{reset} %s is not reachable from here, tunnelling over SSH\n", hostPort)
+	}
+
+	return openTunnel(containerName, port)
+}
+
+// openTunnel forwards a local port to port inside the container over the
+// container's SSH config entry, opens it in the browser and keeps the tunnel
+// up until interrupted
+func openTunnel(containerName string, port int) error {
+	localPort, err := freeLocalPort(port)
+	if err != nil {
+		return err
+	}
+
+	forward := fmt.Sprintf("%d:localhost:%d", localPort, port)
+	sshCmd := exec.Command("ssh", "-N", "-o", "ExitOnForwardFailure=yes", "-L", forward, containerName)
+	sshCmd.Stderr = os.Stderr
+	if err := sshCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start SSH tunnel: %w", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- sshCmd.Wait() }()
+
+	// Wait for the forward to accept connections before opening the browser
+	local := fmt.Sprintf("127.0.0.1:%d", localPort)
+	deadline := time.Now().Add(15 * time.Second)
+	for !isReachable(local, time.Second) {
+		select {
+		case err := <-exited:
+			return fmt.Errorf("SSH tunnel to %s exited: %v", containerName, err)
+		case <-time.After(250 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			_ = sshCmd.Process.Kill()
+			return fmt.Errorf("timed out waiting for SSH tunnel to %s", containerName)
+		}
+	}
+
+	openBrowser(fmt.Sprintf("http://localhost:%d", localPort))
+	color.Printf("{dim}Tunnel localhost:%d → %s:%d is open; press Ctrl+C to close it{reset}\n", localPort, containerName, port)
+
+	// Ctrl+C reaches ssh too, which ends the tunnel
+	<-exited
+	return nil
+}
@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContainerPort(t *testing.T) {
+	port, err := parseContainerPort("web")
+	require.NoError(t, err)
+	assert.Equal(t, 3000, port)
+
+	port, err = parseContainerPort("8080")
+	require.NoError(t, err)
+	assert.Equal(t, 8080, port)
+
+	for _, arg := range []string{"http", "0", "70000"} {
+		_, err := parseContainerPort(arg)
+		assert.Error(t, err, arg)
+	}
+}
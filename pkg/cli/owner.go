@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"l8s/pkg/color"
+	"l8s/pkg/container"
+	"l8s/pkg/ssh"
+)
+
+// localPublicKey returns the SSH public key l8s installs into containers
+func (f *CommandFactory) localPublicKey() (string, error) {
+	if f.Config.SSHPublicKey == "" {
+		key, err := f.SSHClient.FindSSHPublicKey()
+		if err != nil {
+			return "", fmt.Errorf("no SSH public key found in ~/.ssh/")
+		}
+		return key, nil
+	}
+	key, err := f.SSHClient.ReadPublicKey(f.Config.SSHPublicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to read SSH public key: %w", err)
+	}
+	return key, nil
+}
+
+// isMine reports whether the current user created a container, matching on
+// either the local username or the SSH key fingerprint
+func (f *CommandFactory) isMine(c *container.Container) bool {
+	if owner := c.Labels[container.LabelOwner]; owner != "" && owner == container.CurrentUser() {
+		return true
+	}
+	ownerKey := c.Labels[container.LabelOwnerKey]
+	if ownerKey == "" || f.SSHClient == nil {
+		return false
+	}
+	key, err := f.localPublicKey()
+	if err != nil {
+		return false
+	}
+	fp, err := ssh.Fingerprint(key)
+	return err == nil && fp == ownerKey
+}
+
+// checkOwner refuses to remove a container created by someone else unless forced.
+// Containers created before ownership was recorded are treated as unowned.
+func (f *CommandFactory) checkOwner(ctx context.Context, name string, force bool) error {
+	cont, err := f.ContainerMgr.GetContainerInfo(ctx, name)
+	if err != nil {
+		return nil
+	}
+	owner := cont.Labels[container.LabelOwner]
+	if owner == "" || f.isMine(cont) {
+		return nil
+	}
+	if !force {
+		return fmt.Errorf("container %s was created by %s\nUse --force if you really mean to remove someone else's container", cont.Name, owner)
+	}
+	color.Printf("{yellow}!{reset} %s was created by {bold}%s{reset}\n", cont.Name, owner)
+	return nil
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckOwner(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		force   bool
+		wantErr bool
+	}{
+		{name: "no owner recorded", labels: map[string]string{}},
+		{name: "created by current user", labels: map[string]string{container.LabelOwner: container.CurrentUser()}},
+		{name: "created by someone else", labels: map[string]string{container.LabelOwner: "someone-else"}, wantErr: true},
+		{name: "someone else with force", labels: map[string]string{container.LabelOwner: "someone-else"}, force: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMgr := new(MockContainerManagerWithGit)
+			mockMgr.On("GetContainerInfo", mock.Anything, "shared").
+				Return(&container.Container{Name: "dev-shared", Labels: tt.labels}, nil)
+
+			factory := &CommandFactory{
+				Config:       &config.Config{ContainerPrefix: "dev"},
+				ContainerMgr: mockMgr,
+			}
+
+			err := factory.checkOwner(context.Background(), "shared", tt.force)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "someone-else")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -89,4 +90,42 @@ func TestPasteFileNaming(t *testing.T) {
 			assert.Equal(t, tt.wantFilename, destFilename)
 		})
 	}
-}
\ No newline at end of file
+}
+func TestChangeDebouncer(t *testing.T) {
+	start := time.Now()
+	d := &changeDebouncer{delay: time.Second}
+
+	// A new value is held back until it has been stable for the delay
+	assert.False(t, d.observe("a", start))
+	assert.False(t, d.observe("a", start.Add(500*time.Millisecond)))
+	assert.True(t, d.observe("a", start.Add(time.Second)))
+	assert.False(t, d.observe("a", start.Add(2*time.Second)), "sent values are not sent again")
+
+	// A value that keeps changing restarts the wait
+	assert.False(t, d.observe("b", start.Add(3*time.Second)))
+	assert.False(t, d.observe("c", start.Add(3500*time.Millisecond)))
+	assert.False(t, d.observe("c", start.Add(4*time.Second)))
+	assert.True(t, d.observe("c", start.Add(4500*time.Millisecond)))
+
+	// Marked values count as sent
+	d.mark("d")
+	assert.False(t, d.observe("d", start.Add(10*time.Second)))
+
+	// Going back to the sent value drops a pending change
+	assert.False(t, d.observe("e", start.Add(11*time.Second)))
+	assert.False(t, d.observe("d", start.Add(11500*time.Millisecond)))
+	assert.False(t, d.observe("e", start.Add(12*time.Second)))
+}
+
+func TestChangeDebouncerNoDelay(t *testing.T) {
+	d := &changeDebouncer{}
+	assert.True(t, d.observe("a", time.Now()))
+	assert.False(t, d.observe("a", time.Now()))
+}
+
+func TestPasteWatchFlags(t *testing.T) {
+	cmd := NewLazyCommandFactory().PasteCmd()
+	for _, flag := range []string{"watch", "interval", "debounce", "sync-back"} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), flag)
+	}
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"l8s/pkg/color"
+
+	"github.com/spf13/cobra"
+)
+
+// clipboardBackFile is the file in the container that paste --sync-back
+// copies to the host clipboard whenever it changes
+const clipboardBackFile = "/tmp/claude-clipboard/to-host.txt"
+
+// changeDebouncer reports when a polled value has changed and then held
+// steady for delay, so content copied in several steps is sent once
+type changeDebouncer struct {
+	delay   time.Duration
+	sent    string
+	pending string
+	since   time.Time
+}
+
+// observe records the value seen at now and reports whether it should be sent
+func (d *changeDebouncer) observe(value string, now time.Time) bool {
+	if value == d.sent {
+		d.pending = ""
+		return false
+	}
+	if value != d.pending {
+		d.pending, d.since = value, now
+	}
+	if now.Sub(d.since) < d.delay {
+		return false
+	}
+	d.sent, d.pending = value, ""
+	return true
+}
+
+// mark records value as already in sync, so it is not sent back where it came from
+func (d *changeDebouncer) mark(value string) {
+	d.sent, d.pending = value, ""
+}
+
+// clipboardFingerprint identifies clipboard content without keeping it around
+func clipboardFingerprint(content []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(content))
+}
+
+// watchClipboard pushes the host clipboard to the container whenever it
+// changes, and with --sync-back copies clipboardBackFile to the host, until interrupted
+func (f *CommandFactory) watchClipboard(cmd *cobra.Command, containerName, customName string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	debounce, _ := cmd.Flags().GetDuration("debounce")
+	syncBack, _ := cmd.Flags().GetBool("sync-back")
+	plain, _ := cmd.Flags().GetBool("plain")
+	force, _ := cmd.Flags().GetBool("force")
+	limit := f.Config.PasteLimit()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	toContainer := &changeDebouncer{delay: debounce}
+	toHost := &changeDebouncer{delay: debounce}
+	if syncBack {
+		// Whatever the file holds already was not written for this session
+		if content, err := f.ContainerMgr.ExecContainerOutput(ctx, containerName, []string{"cat", clipboardBackFile}); err == nil {
+			toHost.mark(clipboardFingerprint([]byte(content)))
+		}
+	}
+
+	color.Printf("{cyan}→{reset} Watching the clipboard for {bold}%s{reset}, press Ctrl+C to stop\n", containerName)
+	if syncBack {
+		color.Printf("{cyan}→{reset} Text written to %s in the container is copied to this clipboard\n", clipboardBackFile)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		now := time.Now()
+		// An empty clipboard is not an error worth reporting on every poll
+		if clipboardType, content, err := readClipboard(plain); err == nil && toContainer.observe(clipboardFingerprint(content), now) {
+			if size := int64(len(content)); size > limit && !force {
+				// Prompting would stall the watch; the debouncer keeps this from repeating
+				color.Printf("{yellow}!{reset} %s Skipped %s, more than paste_max_size (%s); use --force to paste it\n",
+					now.Format("15:04:05"), formatBytes(size), formatBytes(limit))
+			} else if destPath, err := f.sendClipboard(ctx, containerName, customName, clipboardType, content); err != nil {
+				color.Printf("{yellow}!{reset} %v\n", err)
+				toContainer.mark("") // retry on the next poll
+			} else {
+				color.Printf("{green}✓{reset} %s Pasted to %s\n", now.Format("15:04:05"), destPath)
+			}
+		}
+
+		if syncBack {
+			content, err := f.ContainerMgr.ExecContainerOutput(ctx, containerName, []string{"cat", clipboardBackFile})
+			if err == nil && toHost.observe(clipboardFingerprint([]byte(content)), now) {
+				if err := setHostClipboard([]byte(content)); err != nil {
+					color.Printf("{yellow}!{reset} %v\n", err)
+				} else {
+					// The host clipboard now holds what the container wrote; don't paste it back
+					toContainer.mark(clipboardFingerprint([]byte(content)))
+					color.Printf("{green}✓{reset} %s Copied %s to the clipboard\n", now.Format("15:04:05"), clipboardBackFile)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			color.Printf("\n{cyan}→{reset} Stopped watching\n")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"l8s/pkg/color"
+	"l8s/pkg/config"
+	"l8s/pkg/ssh"
+
+	"github.com/spf13/cobra"
+)
+
+// renamedRemoteURL returns url pointed at the new SSH host when it points at
+// one of the renamed hosts (old full name to new full name)
+func renamedRemoteURL(url string, hosts map[string]string) (string, bool) {
+	host, path, ok := strings.Cut(url, ":")
+	if !ok {
+		return "", false
+	}
+	newHost, ok := hosts[host]
+	if !ok {
+		return "", false
+	}
+	return newHost + ":" + path, true
+}
+
+// runMigratePrefix handles the migrate-prefix command
+func (f *CommandFactory) runMigratePrefix(cmd *cobra.Command, args []string) error {
+	oldPrefix, newPrefix := args[0], args[1]
+	if oldPrefix == newPrefix {
+		return fmt.Errorf("the old and new prefix are both '%s'", oldPrefix)
+	}
+
+	// Validate the new prefix before touching any container
+	path := config.GetConfigPath()
+	data, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	updated, err := config.SetValue(data, "container_prefix", newPrefix)
+	if err != nil {
+		return err
+	}
+	if problems := config.Check(updated); len(problems) > 0 {
+		return problemsError(problems)
+	}
+
+	ctx := context.Background()
+	containers, err := f.ContainerMgr.ListContainers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+	// Containers already on the new prefix were moved by an earlier run that
+	// stopped short of saving the config; their SSH entries and remotes are
+	// still updated below
+	var names []string
+	ports := make(map[string]int)    // short name to SSH port
+	hosts := make(map[string]string) // old full name to new full name
+	for _, c := range containers {
+		if name, ok := strings.CutPrefix(c.Name, oldPrefix+"-"); ok {
+			names = append(names, name)
+		} else if name, ok := strings.CutPrefix(c.Name, newPrefix+"-"); ok {
+			ports[name] = c.SSHPort
+			hosts[oldPrefix+"-"+name] = c.Name
+		}
+	}
+	sort.Strings(names)
+
+	if len(names) > 0 {
+		if force, _ := cmd.Flags().GetBool("force"); !force {
+			fmt.Printf("Move %d container(s) from %s- to %s-?\n", len(names), oldPrefix, newPrefix)
+			for _, name := range names {
+				fmt.Printf("  %s-%s → %s-%s\n", oldPrefix, name, newPrefix, name)
+			}
+			fmt.Println("Running containers are restarted and volumes are copied to their new names.")
+			fmt.Print("Continue? (y/N): ")
+			response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil {
+				return err
+			}
+			response = strings.TrimSpace(strings.ToLower(response))
+			if response != "y" && response != "yes" {
+				fmt.Println("Aborted")
+				return nil
+			}
+		}
+	}
+
+	sshConfigPath := filepath.Join(ssh.GetHomeDir(), ".ssh", "config")
+	var failed []string
+	for _, name := range names {
+		oldName := oldPrefix + "-" + name
+		newName := newPrefix + "-" + name
+		cont, err := f.ContainerMgr.MigratePrefix(ctx, name, oldPrefix, newPrefix)
+		if err != nil {
+			color.Printf("{red}✗{reset} %s: %v\n", oldName, err)
+			failed = append(failed, oldName)
+			continue
+		}
+		color.Printf("{green}✓{reset} %s → {bold}%s{reset}\n", oldName, newName)
+		ports[name] = cont.SSHPort
+		hosts[oldName] = newName
+		if err := ssh.RemoveSSHConfigEntry(sshConfigPath, oldName); err != nil {
+			color.Printf("{yellow}!{reset} Could not remove SSH config entry for %s: %v\n", oldName, err)
+		}
+	}
+
+	// Keep the old prefix while containers still use it, so a re-run picks
+	// them up
+	if len(failed) > 0 {
+		return fmt.Errorf("%d container(s) were not migrated: %s; container_prefix is still '%s', re-run to retry",
+			len(failed), strings.Join(failed, ", "), oldPrefix)
+	}
+	if err := config.WriteFile(path, updated); err != nil {
+		return err
+	}
+	color.Printf("{green}✓{reset} Set {bold}container_prefix{reset} = %s\n", newPrefix)
+	f.Config.ContainerPrefix = newPrefix
+
+	// SSH entries are written with the prefix from the config just saved
+	for name, port := range ports {
+		if err := ssh.AddSSHConfig(name, "", port, f.Config.ContainerUser); err != nil {
+			color.Printf("{yellow}!{reset} Could not add SSH config entry for %s-%s: %v\n", newPrefix, name, err)
+		}
+	}
+
+	if repoRoot, err := f.GitClient.GetRepositoryRoot("."); err == nil {
+		remotes, _ := f.GitClient.ListRemotes(repoRoot)
+		for remote, url := range remotes {
+			newURL, ok := renamedRemoteURL(url, hosts)
+			if !ok {
+				continue
+			}
+			err := f.GitClient.RemoveRemote(repoRoot, remote)
+			if err == nil {
+				err = f.GitClient.AddRemote(repoRoot, remote, newURL)
+			}
+			if err != nil {
+				color.Printf("{yellow}!{reset} Could not update git remote '%s': %v\n", remote, err)
+				continue
+			}
+			color.Printf("{green}✓{reset} Git remote '{bold}%s{reset}' now points at %s\n", remote, newURL)
+		}
+	}
+	f.syncIngress()
+
+	if len(ports) > 0 {
+		color.Printf("\nRun {bold}l8s remote add{reset} in other repositories to update their remotes\n")
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenamedRemoteURL(t *testing.T) {
+	hosts := map[string]string{"dev-api": "work-api"}
+
+	tests := []struct {
+		url    string
+		want   string
+		wantOK bool
+	}{
+		{url: "dev-api:/workspace/project", want: "work-api:/workspace/project", wantOK: true},
+		{url: "dev-api-v2:/workspace/project"},
+		{url: "git@github.com:org/api.git"},
+		{url: "https://github.com/org/api.git"},
+		{url: "/srv/git/api.git"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, ok := renamedRemoteURL(tt.url, hosts)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+	"l8s/pkg/logging"
+	"l8s/pkg/protect"
+)
+
+// protectedList returns the record of protected containers kept in the
+// state directory
+func protectedList() *protect.List {
+	return protect.NewList(filepath.Join(config.StateDir(), protect.FileName))
+}
+
+// isProtected reports whether a container is protected, as recorded by
+// l8s protect or, for containers protected by older versions, by its label
+func (f *CommandFactory) isProtected(c *container.Container) bool {
+	if c == nil {
+		return false
+	}
+	protected, ok, err := protectedList().Lookup(f.Config.ActiveConnection, c.Name)
+	if err != nil {
+		logging.Debug("failed to read protected containers", logging.WithError(err))
+	}
+	if ok {
+		return protected
+	}
+	return c.Labels[container.LabelProtected] == "true"
+}
+
+// forgetProtection drops the protection record of a removed container
+func (f *CommandFactory) forgetProtection(name string) {
+	if err := protectedList().Forget(f.Config.ActiveConnection, f.Config.ContainerPrefix+"-"+name); err != nil {
+		logging.Debug("failed to forget protection", logging.WithError(err), logging.WithField("container", name))
+	}
+}
+
+// runProtect marks a container as protected
+func (f *CommandFactory) runProtect(cmd *cobra.Command, args []string) error {
+	return f.setProtected(args[0], true)
+}
+
+// runUnprotect clears the protected mark from a container
+func (f *CommandFactory) runUnprotect(cmd *cobra.Command, args []string) error {
+	return f.setProtected(args[0], false)
+}
+
+// setProtected records whether a container is protected. The container
+// itself is left alone.
+func (f *CommandFactory) setProtected(arg string, protected bool) error {
+	name := strings.TrimPrefix(arg, f.Config.ContainerPrefix+"-")
+	fullName := fmt.Sprintf("%s-%s", f.Config.ContainerPrefix, name)
+
+	ctx := context.Background()
+	cont, err := f.ContainerMgr.GetContainerInfo(ctx, name)
+	if err != nil {
+		return fmt.Errorf("container '%s' not found: %w", name, err)
+	}
+
+	if f.isProtected(cont) == protected {
+		state := "not protected"
+		if protected {
+			state = "already protected"
+		}
+		color.Printf("{dim}%s is %s{reset}\n", fullName, state)
+		return nil
+	}
+
+	if err := protectedList().Set(f.Config.ActiveConnection, cont.Name, protected); err != nil {
+		return err
+	}
+
+	if protected {
+		color.Printf("{green}✓{reset} %s is now protected from remove and rebuild-all\n", fullName)
+	} else {
+		color.Printf("{green}✓{reset} %s is no longer protected\n", fullName)
+	}
+	return nil
+}
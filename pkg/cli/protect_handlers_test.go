@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"testing"
+
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetProtected(t *testing.T) {
+	t.Run("protect records the container without recreating it", func(t *testing.T) {
+		t.Setenv("XDG_STATE_HOME", t.TempDir())
+		pet := &container.Container{Name: "dev-pet", Labels: map[string]string{}}
+		mockMgr := new(MockContainerManagerWithGit)
+		mockMgr.On("GetContainerInfo", mock.Anything, "pet").Return(pet, nil)
+
+		factory := &CommandFactory{Config: &config.Config{ContainerPrefix: "dev", ActiveConnection: "home"}, ContainerMgr: mockMgr}
+		assert.NoError(t, factory.setProtected("dev-pet", true))
+		assert.True(t, factory.isProtected(pet))
+		mockMgr.AssertNotCalled(t, "UpdateLabels", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		// Protection is per connection
+		other := &CommandFactory{Config: &config.Config{ContainerPrefix: "dev", ActiveConnection: "work"}}
+		assert.False(t, other.isProtected(pet))
+
+		assert.NoError(t, factory.setProtected("pet", false))
+		assert.False(t, factory.isProtected(pet))
+	})
+
+	t.Run("unprotect overrides the label older versions set", func(t *testing.T) {
+		t.Setenv("XDG_STATE_HOME", t.TempDir())
+		pet := &container.Container{Name: "dev-pet", Labels: map[string]string{container.LabelProtected: "true"}}
+		mockMgr := new(MockContainerManagerWithGit)
+		mockMgr.On("GetContainerInfo", mock.Anything, "pet").Return(pet, nil)
+
+		factory := &CommandFactory{Config: &config.Config{ContainerPrefix: "dev"}, ContainerMgr: mockMgr}
+		assert.True(t, factory.isProtected(pet))
+		assert.NoError(t, factory.setProtected("pet", false))
+		assert.False(t, factory.isProtected(pet))
+		mockMgr.AssertNotCalled(t, "UpdateLabels", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
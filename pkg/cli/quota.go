@@ -0,0 +1,21 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"l8s/pkg/container"
+)
+
+// withQuotaHint explains how to get past a quota error or a full port range
+func withQuotaHint(err error, connection string) error {
+	var quotaErr *container.QuotaError
+	if errors.As(err, &quotaErr) {
+		return fmt.Errorf("%w\nRemove unused containers ('l8s list --mine') or raise connections.%s.quota in your config", err, connection)
+	}
+	var portErr *container.PortExhaustedError
+	if errors.As(err, &portErr) {
+		return fmt.Errorf("%w\nRemove unused containers ('l8s list', 'l8s gc') or raise port_range in your config ('l8s config set port_range 2000'), or add ranges to ssh_port_ranges", err)
+	}
+	return err
+}
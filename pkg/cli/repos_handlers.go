@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+	"l8s/pkg/git"
+	"l8s/pkg/logging"
+	"l8s/pkg/repos"
+
+	"github.com/spf13/cobra"
+)
+
+// repoIndex returns the local record of which repositories have remotes for
+// which containers
+func repoIndex() *repos.Index {
+	return repos.NewIndex(filepath.Join(config.StateDir(), repos.FileName))
+}
+
+// recordRepo notes that the remote for container name was added to repoRoot.
+// Failures to record are only logged.
+func (f *CommandFactory) recordRepo(name, repoRoot string) {
+	entry := repos.Entry{
+		Connection: f.Config.ActiveConnection,
+		Container:  f.Config.ContainerPrefix + "-" + name,
+		Path:       repoRoot,
+	}
+	if err := repoIndex().Record(entry); err != nil {
+		logging.Debug("failed to record repository", logging.WithError(err), logging.WithField("container", name))
+	}
+}
+
+// forgetRepo drops what was recorded for container name once it is removed
+func (f *CommandFactory) forgetRepo(name string) {
+	if err := repoIndex().Forget(f.Config.ActiveConnection, f.Config.ContainerPrefix+"-"+name); err != nil {
+		logging.Debug("failed to forget repository", logging.WithError(err), logging.WithField("container", name))
+	}
+}
+
+// containerRepo returns where on this machine the repository of container
+// name lives: the one its remote was added to here, or the worktree it was
+// created from as recorded in its labels
+func (f *CommandFactory) containerRepo(name string, labels map[string]string) string {
+	entry, ok, err := repoIndex().Lookup(f.Config.ActiveConnection, f.Config.ContainerPrefix+"-"+name)
+	if err != nil {
+		logging.Debug("failed to read repository index", logging.WithError(err))
+	}
+	if ok {
+		return entry.Path
+	}
+	return labels[container.LabelWorktree]
+}
+
+// remoteRepo returns the repository to remove container name's remote from:
+// its own when it still exists, otherwise fallback
+func (f *CommandFactory) remoteRepo(ctx context.Context, name, fallback string) string {
+	var labels map[string]string
+	if cont, err := f.ContainerMgr.GetContainerInfo(ctx, name); err == nil {
+		labels = cont.Labels
+	}
+	if path := f.containerRepo(name, labels); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return fallback
+}
+
+// runWhich prints the local repository of a container. Containers created on
+// this machine are answered from the local record without contacting the
+// host; ContainerMgr is only needed, and may be nil otherwise.
+func (f *CommandFactory) runWhich(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	fullName := f.Config.ContainerPrefix + "-" + name
+
+	path := f.containerRepo(name, nil)
+	if path == "" && f.ContainerMgr != nil {
+		cont, err := f.ContainerMgr.GetContainerInfo(context.Background(), name)
+		if err != nil {
+			return err
+		}
+		path = f.containerRepo(name, cont.Labels)
+	}
+	if path == "" {
+		return fmt.Errorf("no local repository is known for %s; it was created before l8s recorded worktrees, or its remote was never added here", fullName)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("%s was created from %s, which no longer exists", fullName, path)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), path)
+	return nil
+}
+
+// remoteUpdate is a git remote that names a host l8s is switching away from
+type remoteUpdate struct {
+	Repo   string
+	Remote string
+	URL    string
+	NewURL string
+}
+
+// switchedRemoteURL returns an ssh:// remote URL pointed at newHost when it
+// names oldHost, keeping the user, port and path. Remotes through SSH config
+// aliases (dev-name:/workspace/project) follow the SSH entries instead.
+func switchedRemoteURL(remoteURL, oldHost, newHost string) (string, bool) {
+	u, err := url.Parse(remoteURL)
+	if err != nil || u.Scheme != "ssh" || u.Hostname() != oldHost {
+		return "", false
+	}
+	if port := u.Port(); port != "" {
+		u.Host = net.JoinHostPort(newHost, port)
+	} else {
+		u.Host = config.URLHost(newHost)
+	}
+	return u.String(), true
+}
+
+// findRemoteUpdates lists the remotes of the known repositories that reach
+// containers through oldHost
+func findRemoteUpdates(oldHost, newHost string) ([]remoteUpdate, error) {
+	paths, err := repoIndex().Paths()
+	if err != nil {
+		return nil, err
+	}
+	var updates []remoteUpdate
+	for _, path := range paths {
+		remotes, err := git.ListRemotes(path)
+		if err != nil {
+			logging.Debug("skipping repository", logging.WithError(err), logging.WithField("path", path))
+			continue
+		}
+		for remote, remoteURL := range remotes {
+			if newURL, ok := switchedRemoteURL(remoteURL, oldHost, newHost); ok {
+				updates = append(updates, remoteUpdate{Repo: path, Remote: remote, URL: remoteURL, NewURL: newURL})
+			}
+		}
+	}
+	return updates, nil
+}
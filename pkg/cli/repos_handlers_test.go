@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+	"l8s/pkg/repos"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwitchedRemoteURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		newHost string
+		want    string
+		ok      bool
+	}{
+		{"ssh://dev@192.168.1.100:2201/workspace/project", "10.0.0.50", "ssh://dev@10.0.0.50:2201/workspace/project", true},
+		{"ssh://dev@192.168.1.100:2201/workspace/project", "2001:db8::5", "ssh://dev@[2001:db8::5]:2201/workspace/project", true},
+		{"ssh://dev@192.168.1.100/workspace/project", "10.0.0.50", "ssh://dev@10.0.0.50/workspace/project", true},
+		{"ssh://dev@192.168.1.1000:2201/workspace/project", "10.0.0.50", "", false},
+		{"dev-myproject:/workspace/project", "10.0.0.50", "", false},
+		{"https://192.168.1.100/user/repo.git", "10.0.0.50", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := switchedRemoteURL(tt.url, "192.168.1.100", tt.newHost)
+		assert.Equal(t, tt.ok, ok, tt.url)
+		assert.Equal(t, tt.want, got, tt.url)
+	}
+}
+
+func TestFindRemoteUpdates(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	repoPath := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"remote", "add", "myproject", "ssh://dev@192.168.1.100:2201/workspace/project"},
+		{"remote", "add", "other", "dev-other:/workspace/project"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		require.NoError(t, cmd.Run())
+	}
+	require.NoError(t, repoIndex().Record(repos.Entry{Connection: "home", Container: "dev-myproject", Path: repoPath}))
+
+	updates, err := findRemoteUpdates("192.168.1.100", "10.0.0.50")
+	require.NoError(t, err)
+	assert.Equal(t, []remoteUpdate{{
+		Repo:   repoPath,
+		Remote: "myproject",
+		URL:    "ssh://dev@192.168.1.100:2201/workspace/project",
+		NewURL: "ssh://dev@10.0.0.50:2201/workspace/project",
+	}}, updates)
+}
+
+func TestRunWhich(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := &config.Config{ActiveConnection: "home", ContainerPrefix: "dev"}
+	worktree := t.TempDir()
+
+	run := func(f *CommandFactory, name string) (string, error) {
+		var out bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+		err := f.runWhich(cmd, []string{name})
+		return strings.TrimSpace(out.String()), err
+	}
+
+	// Recorded here: no host needed
+	require.NoError(t, repoIndex().Record(repos.Entry{Connection: "home", Container: "dev-api", Path: worktree}))
+	path, err := run(&CommandFactory{Config: cfg}, "api")
+	require.NoError(t, err)
+	assert.Equal(t, worktree, path)
+
+	// Created elsewhere: the label says where
+	cm := new(MockContainerManagerWithGit)
+	cm.On("GetContainerInfo", mock.Anything, "web").Return(&container.Container{
+		Name:   "dev-web",
+		Labels: map[string]string{container.LabelWorktree: worktree},
+	}, nil)
+	cm.On("GetContainerInfo", mock.Anything, "old").Return(&container.Container{
+		Name:   "dev-old",
+		Labels: map[string]string{container.LabelWorktree: filepath.Join(worktree, "gone")},
+	}, nil)
+	cm.On("GetContainerInfo", mock.Anything, "bare").Return(&container.Container{Name: "dev-bare"}, nil)
+
+	path, err = run(&CommandFactory{Config: cfg, ContainerMgr: cm}, "web")
+	require.NoError(t, err)
+	assert.Equal(t, worktree, path)
+
+	_, err = run(&CommandFactory{Config: cfg, ContainerMgr: cm}, "old")
+	assert.ErrorContains(t, err, "which no longer exists")
+	_, err = run(&CommandFactory{Config: cfg, ContainerMgr: cm}, "bare")
+	assert.ErrorContains(t, err, "no local repository is known for dev-bare")
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"l8s/pkg/color"
+	"l8s/pkg/container"
+	"l8s/pkg/git"
+	"l8s/pkg/github"
+	"l8s/pkg/notify"
+
+	"github.com/spf13/cobra"
+)
+
+// runReview creates a container with a pull request checked out
+func (f *CommandFactory) runReview(cmd *cobra.Command, args []string) error {
+	number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil || number <= 0 {
+		return fmt.Errorf("invalid pull request number '%s'", args[0])
+	}
+	repoRoot, err := f.GitClient.GetRepositoryRoot(".")
+	if err != nil {
+		return fmt.Errorf("l8s review must be run from within a git repository\nThe pull request is looked up in the repository's origin remote.")
+	}
+	remotes, err := f.GitClient.ListRemotes(repoRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list git remotes: %w", err)
+	}
+	originURL, ok := remotes["origin"]
+	if !ok {
+		return fmt.Errorf("this repository has no origin remote to find pull requests in")
+	}
+	repo, err := github.ParseRemote(originURL)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	pr, err := github.NewClient(repo.APIURL(), f.Config.GitHubToken).GetPullRequest(ctx, repo, number)
+	if err != nil {
+		return err
+	}
+	author := pr.User.Login
+	if pr.Head.Repo != nil && pr.Head.Repo.FullName != repo.FullName() {
+		author = pr.Head.Repo.FullName
+	}
+	color.Printf("🎳 {cyan}Reviewing #%d:{reset} {bold}%s{reset} (%s by %s)\n", pr.Number, pr.Title, pr.State, author)
+	if pr.State != "open" {
+		color.Printf("{yellow}!{reset} Pull request #%d is %s\n", pr.Number, pr.State)
+	}
+
+	name, _ := cmd.Flags().GetString("name")
+	if name == "" {
+		repoName, err := git.GetRepositoryName(repoRoot)
+		if err != nil {
+			return fmt.Errorf("failed to get repository name: %w", err)
+		}
+		name = fmt.Sprintf("%s-pr%d", repoName, pr.Number)
+	}
+	fullName := f.Config.ContainerPrefix + "-" + name
+	if existing, err := f.ContainerMgr.GetContainerInfo(ctx, name); err == nil && existing != nil {
+		return fmt.Errorf("container '%s' already exists\nUse 'ssh %s' to connect or 'l8s remove %s' to start over", fullName, fullName, name)
+	}
+
+	sshKey, err := f.localPublicKey()
+	if err != nil {
+		return err
+	}
+	if err := f.SSHClient.ValidatePublicKey(sshKey); err != nil {
+		return fmt.Errorf("invalid SSH public key: %w", err)
+	}
+
+	color.Printf("🎳 {cyan}Creating container:{reset} {bold}%s{reset}\n", fullName)
+	cont, err := f.ContainerMgr.CreateContainer(ctx, name, sshKey, container.CreateOptions{})
+	if err != nil {
+		return withQuotaHint(err, f.Config.ActiveConnection)
+	}
+
+	branch := pr.Head.Ref
+	if branch == "" {
+		branch = fmt.Sprintf("pr-%d", pr.Number)
+	}
+	color.Printf("{cyan}→{reset} Fetching {bold}%s{reset} in container...\n", branch)
+	if err := f.ContainerMgr.ExecContainer(ctx, name, f.reviewCheckoutCmd(repo, originURL, pr.Number, branch)); err != nil {
+		color.Printf("{red}✗{reset} Failed to fetch pull request: %v\n", err)
+		color.Printf("{yellow}!{reset} Container created but the pull request is not checked out\n")
+		color.Printf("{yellow}!{reset} Fix it over 'ssh %s' or remove it with 'l8s remove %s'\n", fullName, name)
+		return fmt.Errorf("failed to check out pull request #%d: %w", pr.Number, err)
+	}
+
+	// A remote on the host lets the reviewer pull fixups made in the container
+	remoteURL := fmt.Sprintf("%s:/workspace/project", fullName)
+	if err := f.GitClient.AddRemote(repoRoot, name, remoteURL); err != nil {
+		color.Printf("{yellow}!{reset} Could not add git remote '%s': %v\n", name, err)
+	} else {
+		color.Printf("{green}✓{reset} Git remote '{bold}%s{reset}' added\n", name)
+	}
+
+	color.Printf("{green}✓{reset} SSH port: {bold}%d{reset}\n", cont.SSHPort)
+	color.Printf("{green}✓{reset} Checked out {bold}%s{reset} at %s\n", branch, shortSHA(pr.Head.SHA))
+	f.notifyEvent(notify.EventCreate, name)
+	f.syncIngress()
+	if in, err := f.activeIngress(); err == nil && in.Enabled {
+		color.Printf("{green}✓{reset} Web: {bold}%s{reset}\n", ingressURL(in, name))
+	}
+
+	color.Printf("\n{cyan}Connection options:{reset}\n")
+	color.Printf("- {bold}ssh %s{reset}\n", fullName)
+	color.Printf("- {bold}code --remote ssh-remote+%s /workspace/project{reset}\n", fullName)
+	color.Printf("- {bold}git fetch %s{reset} to bring review changes back\n", name)
+	if pr.HTMLURL != "" {
+		color.Printf("\nPull request: %s\n", pr.HTMLURL)
+	}
+	color.Printf("When done: {bold}l8s remove %s{reset}\n", name)
+	return nil
+}
+
+// reviewCheckoutCmd fetches refs/pull/<n>/head from the base repository,
+// which also covers pull requests from forks, and checks it out as branch.
+// The token is passed for this fetch only and is not stored in the container's
+// git config.
+func (f *CommandFactory) reviewCheckoutCmd(repo github.Repository, originURL string, number int, branch string) []string {
+	fetch := "git"
+	if f.Config.GitHubToken != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + f.Config.GitHubToken))
+		fetch += " -c " + container.ShellQuote("http.extraHeader=Authorization: Basic "+credentials)
+	}
+	script := strings.Join([]string{
+		"cd /workspace/project",
+		"git remote add origin " + container.ShellQuote(originURL),
+		fmt.Sprintf("%s fetch --quiet %s %s", fetch, container.ShellQuote(repo.CloneURL()),
+			container.ShellQuote(fmt.Sprintf("pull/%d/head:%s", number, branch))),
+		"git checkout --quiet " + container.ShellQuote(branch),
+	}, " && ")
+	return []string{"su", "-", f.Config.ContainerUser, "-c", script}
+}
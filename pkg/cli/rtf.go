@@ -0,0 +1,289 @@
+package cli
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// rtfSkipped lists RTF destinations that hold no document text
+var rtfSkipped = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true,
+	"pict": true, "header": true, "footer": true, "headerl": true, "headerr": true,
+	"footerl": true, "footerr": true, "listtable": true, "listoverridetable": true,
+	"rsidtbl": true, "generator": true, "themedata": true, "latentstyles": true,
+	"datastore": true, "xmlnstbl": true, "expandedcolortbl": true, "object": true,
+}
+
+// cp1252 maps the Windows-1252 bytes that differ from Latin-1, as written
+// by \'hh escapes in RTF from Word and TextEdit
+var cp1252 = map[byte]rune{
+	0x80: '€', 0x85: '…', 0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”',
+	0x95: '•', 0x96: '–', 0x97: '—', 0x99: '™',
+}
+
+var (
+	rtfHyperlink  = regexp.MustCompile(`HYPERLINK\s+"([^"]+)"`)
+	rtfBlankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// rtfGroup is the formatting state of one {...} group
+type rtfGroup struct {
+	bold, italic bool
+	skip         bool             // destination without document text
+	capture      *strings.Builder // field instruction being read
+	link         bool             // field result to close as a markdown link
+	unicodeSkip  int              // fallback characters after \u
+}
+
+// rtfToMarkdown converts rich text to markdown, keeping bold, italic, links,
+// list items and paragraphs. It reports whether the text had any of that
+// formatting, i.e. whether the markdown says more than the plain text.
+func rtfToMarkdown(rtf string) (string, bool) {
+	c := &rtfConverter{stack: []rtfGroup{{unicodeSkip: 1}}}
+	c.parse(rtf)
+	c.restyle(false, false)
+	md := strings.TrimSpace(rtfBlankLines.ReplaceAllString(c.out.String(), "\n\n"))
+	if md != "" {
+		md += "\n"
+	}
+	return md, c.formatted
+}
+
+type rtfConverter struct {
+	out       strings.Builder
+	stack     []rtfGroup
+	bold      bool // style already written to out
+	italic    bool
+	formatted bool
+	url       string // target of the field being read
+	pending   int    // \u fallback characters still to drop
+}
+
+func (c *rtfConverter) top() *rtfGroup {
+	return &c.stack[len(c.stack)-1]
+}
+
+func (c *rtfConverter) parse(rtf string) {
+	for i := 0; i < len(rtf); i++ {
+		ch := rtf[i]
+		switch ch {
+		case '{':
+			group := *c.top()
+			group.link = false
+			c.stack = append(c.stack, group)
+		case '}':
+			if len(c.stack) == 1 {
+				continue
+			}
+			group := *c.top()
+			c.stack = c.stack[:len(c.stack)-1]
+			if group.capture != nil {
+				if m := rtfHyperlink.FindStringSubmatch(group.capture.String()); m != nil {
+					c.url = m[1]
+				}
+			}
+			if group.link {
+				c.restyle(false, false)
+				c.out.WriteString("](" + c.url + ")")
+				c.url = ""
+			}
+		case '\\':
+			i = c.control(rtf, i+1)
+		case '\r', '\n':
+		default:
+			c.text(rune(ch))
+		}
+	}
+}
+
+// control handles the control word or symbol starting at rtf[i] and returns
+// the index of its last character
+func (c *rtfConverter) control(rtf string, i int) int {
+	if i >= len(rtf) {
+		return i
+	}
+	ch := rtf[i]
+	if !isASCIILetter(ch) {
+		switch ch {
+		case '\\', '{', '}':
+			c.text(rune(ch))
+		case '\'':
+			if i+2 < len(rtf) {
+				if b, err := strconv.ParseUint(rtf[i+1:i+3], 16, 8); err == nil {
+					r, ok := cp1252[byte(b)]
+					if !ok {
+						r = rune(b)
+					}
+					c.text(r)
+				}
+				return i + 2
+			}
+		case '*':
+			// Unknown destinations are skipped, except field instructions, which hold link targets
+			if strings.HasPrefix(rtf[i+1:], `\fldinst`) {
+				c.top().capture = &strings.Builder{}
+			} else {
+				c.top().skip = true
+			}
+		case '~':
+			c.text(' ')
+		case '_':
+			c.text('-')
+		case '\n', '\r':
+			// Cocoa writes paragraph breaks as an escaped newline
+			c.paragraph()
+		}
+		return i
+	}
+
+	start := i
+	for i < len(rtf) && isASCIILetter(rtf[i]) {
+		i++
+	}
+	word := rtf[start:i]
+	paramStart := i
+	if i < len(rtf) && rtf[i] == '-' {
+		i++
+	}
+	for i < len(rtf) && rtf[i] >= '0' && rtf[i] <= '9' {
+		i++
+	}
+	param, err := strconv.Atoi(rtf[paramStart:i])
+	hasParam := err == nil
+	if i < len(rtf) && rtf[i] == ' ' {
+		i++ // the delimiting space belongs to the control word
+	}
+
+	group := c.top()
+	switch {
+	case rtfSkipped[word]:
+		group.skip = true
+	case word == "fldinst":
+		if group.capture == nil {
+			group.capture = &strings.Builder{}
+		}
+	case word == "fldrslt":
+		if c.url != "" && !group.skip {
+			c.restyle(false, false)
+			c.write("[")
+			group.link = true
+			c.formatted = true
+		}
+	case word == "listtext" || word == "pntext":
+		if !group.skip {
+			c.restyle(false, false)
+			c.write("- ")
+			c.formatted = true
+			// The marker itself, a bullet or number, is replaced
+			group.skip = true
+		}
+	case word == "b":
+		group.bold = !hasParam || param != 0
+	case word == "i":
+		group.italic = !hasParam || param != 0
+	case word == "plain":
+		group.bold, group.italic = false, false
+	case word == "par" || word == "sect" || word == "page":
+		c.paragraph()
+	case word == "line":
+		c.write("\n")
+	case word == "tab":
+		c.text('\t')
+	case word == "emdash":
+		c.text('—')
+	case word == "endash":
+		c.text('–')
+	case word == "bullet":
+		c.text('•')
+	case word == "lquote":
+		c.text('‘')
+	case word == "rquote":
+		c.text('’')
+	case word == "ldblquote":
+		c.text('“')
+	case word == "rdblquote":
+		c.text('”')
+	case word == "uc" && hasParam:
+		group.unicodeSkip = param
+	case word == "u" && hasParam:
+		if param < 0 {
+			param += 65536
+		}
+		c.text(rune(param))
+		c.pending = group.unicodeSkip
+	}
+	return i - 1
+}
+
+// text writes a document character in the current group's style
+func (c *rtfConverter) text(r rune) {
+	group := c.top()
+	if c.pending > 0 {
+		c.pending--
+		return
+	}
+	if group.capture != nil {
+		group.capture.WriteRune(r)
+		return
+	}
+	if group.skip {
+		return
+	}
+	if r != ' ' && r != '\t' {
+		c.restyle(group.bold, group.italic)
+	}
+	c.out.WriteRune(r)
+}
+
+// write adds markdown that is not document text
+func (c *rtfConverter) write(s string) {
+	c.pending = 0
+	if !c.top().skip && c.top().capture == nil {
+		c.out.WriteString(s)
+	}
+}
+
+// paragraph ends the current paragraph, closing any emphasis
+func (c *rtfConverter) paragraph() {
+	if group := c.top(); !group.skip && group.capture == nil {
+		c.restyle(false, false)
+		c.write("\n\n")
+	}
+}
+
+// restyle closes and opens emphasis markers to match bold and italic.
+// Markers are closed before trailing spaces, which markdown requires.
+func (c *rtfConverter) restyle(bold, italic bool) {
+	if bold == c.bold && italic == c.italic {
+		return
+	}
+	var closing string
+	if c.italic && (!italic || c.bold != bold) {
+		closing += "*"
+		c.italic = false
+	}
+	if c.bold && !bold {
+		closing += "**"
+		c.bold = false
+	}
+	if closing != "" {
+		text := c.out.String()
+		trimmed := strings.TrimRight(text, " \t")
+		c.out.Reset()
+		c.out.WriteString(trimmed + closing + text[len(trimmed):])
+	}
+	if bold && !c.bold {
+		c.out.WriteString("**")
+		c.bold = true
+	}
+	if italic && !c.italic {
+		c.out.WriteString("*")
+		c.italic = true
+	}
+	c.formatted = true
+}
+
+func isASCIILetter(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
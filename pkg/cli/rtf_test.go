@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRTFToMarkdown(t *testing.T) {
+	tests := []struct {
+		name          string
+		rtf           string
+		want          string
+		wantFormatted bool
+	}{
+		{
+			name: "plain text",
+			rtf: `{\rtf1\ansi\ansicpg1252\cocoartf2709
+{\fonttbl\f0\fswiss\fcharset0 Helvetica;}
+{\colortbl;\red255\green255\blue255;}
+\pard\f0\fs24 \cf0 Just some text\
+Second line}`,
+			want:          "Just some text\n\nSecond line\n",
+			wantFormatted: false,
+		},
+		{
+			name:          "bold and italic",
+			rtf:           `{\rtf1\ansi Some {\b bold }and \i italic\i0  words\par}`,
+			want:          "Some **bold** and *italic* words\n",
+			wantFormatted: true,
+		},
+		{
+			name:          "toggle with parameter",
+			rtf:           `{\rtf1\ansi \b Title\b0\par Body\par}`,
+			want:          "**Title**\n\nBody\n",
+			wantFormatted: true,
+		},
+		{
+			name: "hyperlink",
+			rtf: `{\rtf1\ansi See {\field{\*\fldinst{HYPERLINK "https://example.com/docs"}}` +
+				`{\fldrslt \ul the docs}} here.}`,
+			want:          "See [the docs](https://example.com/docs) here.\n",
+			wantFormatted: true,
+		},
+		{
+			name: "cocoa list",
+			rtf: `{\rtf1\ansi{\*\listtable{\list\listtemplateid1{\listlevel\levelnfc23{\leveltext\leveltemplateid1\'01\uc0\u8226 ;}}}}
+\pard\tx220
+\ls1{\listtext	\uc0\u8226 	}first\
+{\listtext	\uc0\u8226 	}second}`,
+			want:          "- first\n\n- second\n",
+			wantFormatted: true,
+		},
+		{
+			name:          "escapes and unicode",
+			rtf:           `{\rtf1\ansi caf\'e9 \'93quoted\'94 \{braces\} back\\slash \u8364? euro}`,
+			want:          "café “quoted” {braces} back\\slash € euro\n",
+			wantFormatted: false,
+		},
+		{
+			name:          "skipped destinations",
+			rtf:           `{\rtf1{\info{\title Hidden}}{\*\generator Word;}Visible}`,
+			want:          "Visible\n",
+			wantFormatted: false,
+		},
+		{
+			name: "empty",
+			rtf:  "",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, formatted := rtfToMarkdown(tt.rtf)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantFormatted, formatted)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+)
+
+// containerRuntime converts the configured runtime options for the container
+// manager. Values were checked by config validation.
+func containerRuntime(cfg *config.Config) container.RuntimeOptions {
+	shmSize, _ := config.ParseSize(cfg.ShmSize)
+	opts := container.RuntimeOptions{ShmSize: shmSize}
+	for _, entry := range cfg.Tmpfs {
+		path, size, _ := config.ParseTmpfs(entry)
+		opts.Tmpfs = append(opts.Tmpfs, container.Tmpfs{Path: path, Size: size})
+	}
+	for name, value := range cfg.Ulimits {
+		soft, hard, _ := config.ParseUlimit(name, value)
+		opts.Ulimits = setUlimit(opts.Ulimits, container.Ulimit{Name: name, Soft: soft, Hard: hard})
+	}
+	if len(cfg.Sysctls) > 0 {
+		opts.Sysctls = make(map[string]string, len(cfg.Sysctls))
+		for k, v := range cfg.Sysctls {
+			opts.Sysctls[k] = v
+		}
+	}
+	for _, name := range cfg.CapAdd {
+		capability, _ := config.NormalizeCapability(name)
+		opts.CapAdd = appendUnique(opts.CapAdd, capability)
+	}
+	opts.SeccompUnconfined = cfg.SeccompUnconfined
+	opts.SELinuxOpts = append([]string(nil), cfg.SELinuxOpts...)
+	opts.Nested = cfg.NestedContainers
+	opts.Systemd = cfg.Systemd
+	opts.Fail2ban = cfg.SSHGuard.Fail2ban
+	return opts
+}
+
+// appendUnique appends value unless it is already present
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// setUlimit adds or replaces a ulimit, keeping the list sorted by name
+func setUlimit(ulimits []container.Ulimit, u container.Ulimit) []container.Ulimit {
+	for i := range ulimits {
+		if ulimits[i].Name == u.Name {
+			ulimits[i] = u
+			return ulimits
+		}
+	}
+	ulimits = append(ulimits, u)
+	sort.Slice(ulimits, func(i, j int) bool { return ulimits[i].Name < ulimits[j].Name })
+	return ulimits
+}
+
+// createRuntimeOptions applies 'l8s create' runtime flags over the configured
+// defaults, reporting whether any flag was given
+func (f *CommandFactory) createRuntimeOptions(cmd *cobra.Command) (container.RuntimeOptions, bool, error) {
+	opts := containerRuntime(f.Config)
+	changed := false
+
+	if cmd.Flags().Changed("shm-size") {
+		value, _ := cmd.Flags().GetString("shm-size")
+		size, err := config.ParseSize(value)
+		if err != nil {
+			return opts, false, fmt.Errorf("--shm-size: %w", err)
+		}
+		opts.ShmSize = size
+		changed = true
+	}
+
+	if cmd.Flags().Changed("tmpfs") {
+		entries, _ := cmd.Flags().GetStringArray("tmpfs")
+		opts.Tmpfs = nil
+		for _, entry := range entries {
+			path, size, err := config.ParseTmpfs(entry)
+			if err != nil {
+				return opts, false, fmt.Errorf("--tmpfs: %w", err)
+			}
+			opts.Tmpfs = append(opts.Tmpfs, container.Tmpfs{Path: path, Size: size})
+		}
+		changed = true
+	}
+
+	// --ulimit and --sysctl add to or override individual configured values
+	ulimitSpecs, _ := cmd.Flags().GetStringArray("ulimit")
+	for _, spec := range ulimitSpecs {
+		name, value, ok := strings.Cut(spec, "=")
+		if !ok {
+			return opts, false, fmt.Errorf("--ulimit: expected name=soft[:hard], got '%s'", spec)
+		}
+		soft, hard, err := config.ParseUlimit(name, value)
+		if err != nil {
+			return opts, false, fmt.Errorf("--ulimit: %w", err)
+		}
+		opts.Ulimits = setUlimit(opts.Ulimits, container.Ulimit{Name: name, Soft: soft, Hard: hard})
+		changed = true
+	}
+
+	sysctlSpecs, _ := cmd.Flags().GetStringArray("sysctl")
+	for _, spec := range sysctlSpecs {
+		key, value, ok := strings.Cut(spec, "=")
+		if !ok {
+			return opts, false, fmt.Errorf("--sysctl: expected key=value, got '%s'", spec)
+		}
+		if err := config.ValidateSysctl(key, value); err != nil {
+			return opts, false, fmt.Errorf("--sysctl: %w", err)
+		}
+		if opts.Sysctls == nil {
+			opts.Sysctls = make(map[string]string)
+		}
+		opts.Sysctls[key] = value
+		changed = true
+	}
+
+	publishSpecs, _ := cmd.Flags().GetStringArray("publish")
+	for _, spec := range publishSpecs {
+		mapping, err := container.ParsePortMapping(spec)
+		if err != nil {
+			return opts, false, fmt.Errorf("--publish: %w", err)
+		}
+		if err := f.checkPublishedPorts(opts.Ports, mapping); err != nil {
+			return opts, false, fmt.Errorf("--publish: %w", err)
+		}
+		opts.Ports = append(opts.Ports, mapping)
+		changed = true
+	}
+
+	// Debugger and profiler opt-ins add to the configured ones
+	capabilities, _ := cmd.Flags().GetStringArray("cap-add")
+	for _, name := range capabilities {
+		capability, err := config.NormalizeCapability(name)
+		if err != nil {
+			return opts, false, fmt.Errorf("--cap-add: %w", err)
+		}
+		opts.CapAdd = appendUnique(opts.CapAdd, capability)
+		changed = true
+	}
+	if ptrace, _ := cmd.Flags().GetBool("ptrace"); ptrace {
+		opts.CapAdd = appendUnique(opts.CapAdd, "SYS_PTRACE")
+		changed = true
+	}
+	if unconfined, _ := cmd.Flags().GetBool("seccomp-unconfined"); unconfined {
+		opts.SeccompUnconfined = true
+		changed = true
+	}
+	selinuxOpts, _ := cmd.Flags().GetStringArray("selinux-opt")
+	for _, opt := range selinuxOpts {
+		if err := config.ValidateSELinuxOpt(opt); err != nil {
+			return opts, false, fmt.Errorf("--selinux-opt: %w", err)
+		}
+		opts.SELinuxOpts = appendUnique(opts.SELinuxOpts, opt)
+		changed = true
+	}
+
+	if nested, _ := cmd.Flags().GetBool("nested-containers"); nested {
+		opts.Nested = true
+		changed = true
+	}
+
+	if systemd, _ := cmd.Flags().GetBool("systemd"); systemd {
+		opts.Systemd = true
+		changed = true
+	}
+
+	return opts, changed, nil
+}
+
+// checkPublishedPorts rejects a mapping whose host ports are already published
+// or fall in the ranges l8s allocates SSH and web ports from
+func (f *CommandFactory) checkPublishedPorts(published []container.PortMapping, mapping container.PortMapping) error {
+	for _, p := range published {
+		if p.Overlaps(mapping) {
+			return fmt.Errorf("%s overlaps %s", mapping, p)
+		}
+	}
+	first, last := mapping.HostPort, mapping.HostPort+mapping.Range-1
+	for _, r := range append(f.Config.SSHRanges(), f.Config.WebPortRange()) {
+		if first <= r.Last && r.First <= last {
+			return fmt.Errorf("host ports of %s overlap the l8s port range %s", mapping, r)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+)
+
+func TestCreateRuntimeOptions(t *testing.T) {
+	cfg := &config.Config{
+		SSHPortStart: 2200,
+		WebPortStart: 3000,
+		ShmSize:      "1g",
+		Tmpfs:        []string{"/cache"},
+		Ulimits:      map[string]string{"nofile": "65536", "nproc": "4096"},
+		Sysctls:      map[string]string{"net.core.somaxconn": "1024"},
+	}
+	f := &CommandFactory{Config: cfg}
+
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		addRuntimeFlags(cmd)
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+
+	opts, changed, err := f.createRuntimeOptions(newCmd())
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, containerRuntime(cfg), opts)
+	assert.Equal(t, []container.Ulimit{{Name: "nofile", Soft: 65536, Hard: 65536}, {Name: "nproc", Soft: 4096, Hard: 4096}}, opts.Ulimits)
+
+	opts, changed, err = f.createRuntimeOptions(newCmd(
+		"--shm-size", "2g", "--tmpfs", "/tmp:4g",
+		"--ulimit", "nofile=1048576", "--ulimit", "memlock=1:2",
+		"--sysctl", "net.ipv4.tcp_keepalive_time=60"))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, int64(2<<30), opts.ShmSize)
+	assert.Equal(t, []container.Tmpfs{{Path: "/tmp", Size: 4 << 30}}, opts.Tmpfs)
+	assert.Equal(t, []container.Ulimit{
+		{Name: "memlock", Soft: 1, Hard: 2},
+		{Name: "nofile", Soft: 1048576, Hard: 1048576},
+		{Name: "nproc", Soft: 4096, Hard: 4096},
+	}, opts.Ulimits)
+	assert.Equal(t, map[string]string{"net.core.somaxconn": "1024", "net.ipv4.tcp_keepalive_time": "60"}, opts.Sysctls)
+
+	cfg.CapAdd = []string{"cap_sys_ptrace"}
+	opts, changed, err = f.createRuntimeOptions(newCmd("--ptrace", "--cap-add", "PERFMON", "--seccomp-unconfined", "--selinux-opt", "disable"))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{"SYS_PTRACE", "PERFMON"}, opts.CapAdd)
+	assert.True(t, opts.SeccompUnconfined)
+	assert.Equal(t, []string{"disable"}, opts.SELinuxOpts)
+
+	opts, changed, err = f.createRuntimeOptions(newCmd("-p", "60000-60010/udp", "--publish", "8443:443"))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []container.PortMapping{
+		{HostPort: 60000, ContainerPort: 60000, Range: 11, Protocol: "udp"},
+		{HostPort: 8443, ContainerPort: 443, Range: 1, Protocol: "tcp"},
+	}, opts.Ports)
+
+	for _, args := range [][]string{{"--shm-size", "big"}, {"--tmpfs", "tmp"}, {"--ulimit", "nofile"}, {"--sysctl", "bad=1"}, {"--cap-add", "sys-ptrace"}, {"--selinux-opt", "type"}, {"-p", "2250:22"}, {"-p", "9000/sctp"}, {"-p", "9000-9010/udp", "-p", "9005/udp"}} {
+		_, _, err := f.createRuntimeOptions(newCmd(args...))
+		assert.Error(t, err, args)
+	}
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"l8s/pkg/color"
+	"l8s/pkg/container"
+)
+
+// containerGit runs a git command in the container's project as the container user
+func (f *CommandFactory) containerGit(ctx context.Context, name, gitArgs string) (string, error) {
+	cmd := []string{"su", "-", f.Config.ContainerUser, "-c",
+		"cd /workspace/project && git " + gitArgs}
+	return f.ContainerMgr.ExecContainerOutput(ctx, name, cmd)
+}
+
+// removeGuard describes the current user removing containers. Commits found
+// in a container count as saved when the repository it was created from, on
+// this machine, has them; the current directory plays no part, so the same
+// guard serves bulk removes and containers removed by --name.
+func (f *CommandFactory) removeGuard(force, forceProtected, keepVolumes bool) container.RemoveGuard {
+	var mu sync.Mutex
+	repos := make(map[string]string)
+	repoOf := func(c *container.Container) string {
+		mu.Lock()
+		defer mu.Unlock()
+		repo, ok := repos[c.Name]
+		if !ok {
+			repo = f.containerRepo(strings.TrimPrefix(c.Name, f.Config.ContainerPrefix+"-"), c.Labels)
+			if _, err := os.Stat(repo); repo == "" || err != nil {
+				repo = ""
+			}
+			repos[c.Name] = repo
+		}
+		return repo
+	}
+
+	return container.RemoveGuard{
+		ContainerUser:  f.Config.ContainerUser,
+		Force:          force,
+		ForceProtected: forceProtected,
+		KeepVolumes:    keepVolumes,
+		IsProtected:    f.isProtected,
+		IsMine:         f.isMine,
+		Saved: func(c *container.Container, sha string) bool {
+			repo := repoOf(c)
+			return repo != "" && f.GitClient.HasCommit(repo, sha)
+		},
+	}
+}
+
+// checkRemove applies the removal guards shared with the API to a container,
+// printing the ones that were overridden and explaining how to get past a
+// refusal
+func (f *CommandFactory) checkRemove(ctx context.Context, name string, guard container.RemoveGuard) error {
+	warnings, err := container.CheckRemove(ctx, f.ContainerMgr, name, guard)
+	var refused *container.RemoveRefusedError
+	if errors.As(err, &refused) {
+		switch refused.Guard {
+		case container.GuardProtected:
+			return fmt.Errorf("%s\nRun 'l8s unprotect %s' first, or pass --force-protected", refused.Reason, name)
+		case container.GuardOwner:
+			return fmt.Errorf("%s\nUse --force if you really mean to remove someone else's container", refused.Reason)
+		case container.GuardUnsaved:
+			return fmt.Errorf("%s\n\nRun 'l8s pull' to bring commits back, or use --force to remove anyway", refused.Reason)
+		case container.GuardUnchecked:
+			return fmt.Errorf("%s\n\nStart it with 'l8s start %s' and retry, use --trash to keep its volumes, or use --force to remove anyway", refused.Reason, name)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		color.Printf("{yellow}!{reset} %s\n", warning)
+	}
+	return nil
+}
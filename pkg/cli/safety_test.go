@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRemove(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	statusCmd := []string{"su", "-", "dev", "-c", "cd /workspace/project && git status --porcelain"}
+	newFactory := func(m *MockContainerManagerWithGit) *CommandFactory {
+		return &CommandFactory{
+			Config:       &config.Config{ContainerPrefix: "dev", ContainerUser: "dev"},
+			ContainerMgr: m,
+			GitClient:    new(MockGitClientEnhanced),
+		}
+	}
+	info := func(labels map[string]string) *container.Container {
+		return &container.Container{Name: "dev-myproject", Labels: labels}
+	}
+
+	t.Run("protected containers need --force-protected", func(t *testing.T) {
+		m := new(MockContainerManagerWithGit)
+		m.On("GetContainerInfo", mock.Anything, "myproject").
+			Return(info(map[string]string{container.LabelProtected: "true"}), nil)
+		f := newFactory(m)
+
+		err := f.checkRemove(context.Background(), "myproject", f.removeGuard(true, false, true))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is protected")
+		assert.Contains(t, err.Error(), "l8s unprotect myproject")
+
+		assert.NoError(t, f.checkRemove(context.Background(), "myproject", f.removeGuard(false, true, true)))
+	})
+
+	t.Run("someone else's container needs --force", func(t *testing.T) {
+		m := new(MockContainerManagerWithGit)
+		m.On("GetContainerInfo", mock.Anything, "myproject").
+			Return(info(map[string]string{container.LabelOwner: "someone-else"}), nil)
+		f := newFactory(m)
+
+		err := f.checkRemove(context.Background(), "myproject", f.removeGuard(false, false, true))
+		assert.ErrorContains(t, err, "created by someone-else")
+		assert.NoError(t, f.checkRemove(context.Background(), "myproject", f.removeGuard(true, false, true)))
+	})
+
+	t.Run("a container that cannot be checked needs --force", func(t *testing.T) {
+		m := new(MockContainerManagerWithGit)
+		m.On("GetContainerInfo", mock.Anything, "myproject").Return(info(map[string]string{}), nil)
+		m.On("ExecContainerOutput", mock.Anything, "myproject", statusCmd).
+			Return("", errors.New("container is not running"))
+		f := newFactory(m)
+
+		err := f.checkRemove(context.Background(), "myproject", f.removeGuard(false, false, false))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "could not check container dev-myproject")
+		assert.Contains(t, err.Error(), "l8s start myproject")
+
+		assert.NoError(t, f.checkRemove(context.Background(), "myproject", f.removeGuard(true, false, false)))
+	})
+
+	t.Run("uncommitted changes need --force", func(t *testing.T) {
+		m := new(MockContainerManagerWithGit)
+		m.On("GetContainerInfo", mock.Anything, "myproject").Return(info(map[string]string{}), nil)
+		m.On("ExecContainerOutput", mock.Anything, "myproject", statusCmd).Return(" M main.go\n", nil)
+		m.On("ExecContainerOutput", mock.Anything, "myproject", mock.Anything).Return("", nil)
+		f := newFactory(m)
+
+		err := f.checkRemove(context.Background(), "myproject", f.removeGuard(false, false, false))
+		assert.ErrorContains(t, err, "has work that would be lost")
+		assert.NoError(t, f.checkRemove(context.Background(), "myproject", f.removeGuard(true, false, false)))
+	})
+
+	t.Run("commits are looked up in the container's own repository", func(t *testing.T) {
+		t.Setenv("XDG_STATE_HOME", t.TempDir())
+		worktree := t.TempDir()
+		refsCmd := []string{"su", "-", "dev", "-c",
+			"cd /workspace/project && git for-each-ref --format='%(refname:short) %(objectname)' refs/heads"}
+
+		m := new(MockContainerManagerWithGit)
+		m.On("GetContainerInfo", mock.Anything, "myproject").
+			Return(info(map[string]string{container.LabelWorktree: worktree}), nil)
+		m.On("ExecContainerOutput", mock.Anything, "myproject", statusCmd).Return("", nil)
+		m.On("ExecContainerOutput", mock.Anything, "myproject", refsCmd).Return("main aaa111\n", nil)
+		g := new(MockGitClientEnhanced)
+		g.On("HasCommit", worktree, "aaa111").Return(true)
+		f := newFactory(m)
+		f.GitClient = g
+
+		assert.NoError(t, f.checkRemove(context.Background(), "myproject", f.removeGuard(false, false, false)))
+		g.AssertExpectations(t)
+	})
+
+	t.Run("commits are unsaved when the repository is not on this machine", func(t *testing.T) {
+		t.Setenv("XDG_STATE_HOME", t.TempDir())
+		m := new(MockContainerManagerWithGit)
+		m.On("GetContainerInfo", mock.Anything, "myproject").Return(info(map[string]string{}), nil)
+		m.On("ExecContainerOutput", mock.Anything, "myproject", statusCmd).Return("", nil)
+		m.On("ExecContainerOutput", mock.Anything, "myproject", mock.Anything).
+			Return("main aaa111\n", nil).Once()
+		m.On("ExecContainerOutput", mock.Anything, "myproject", mock.Anything).
+			Return("aaa111 aaa Work\n", nil).Once()
+		f := newFactory(m)
+
+		err := f.checkRemove(context.Background(), "myproject", f.removeGuard(false, false, false))
+		assert.ErrorContains(t, err, "aaa Work")
+	})
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/juju/ansiterm"
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+	"l8s/pkg/scan"
+)
+
+// scanImage scans image on the remote host and prints the findings,
+// returning how many were found
+func (f *CommandFactory) scanImage(cmd *cobra.Command, image string) (int, error) {
+	list, _ := cmd.Flags().GetString("severity")
+	severities, err := scan.ParseSeverities(list)
+	if err != nil {
+		return 0, err
+	}
+	address, err := f.Config.GetActiveAddress()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get active connection: %w", err)
+	}
+
+	color.Printf("{cyan}→{reset} Scanning {bold}%s{reset} for %s vulnerabilities...\n", image, list)
+	vulns, err := scan.Image(f.Config.RemoteUser, address, image, severities)
+	if err != nil {
+		return 0, err
+	}
+	if len(vulns) == 0 {
+		color.Printf("{green}✓{reset} No %s vulnerabilities found in %s\n", list, image)
+		return 0, nil
+	}
+
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	if os.Getenv("NO_COLOR") == "" {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", color.Bold("SEVERITY"), color.Bold("ID"), color.Bold("PACKAGE"), color.Bold("INSTALLED"), color.Bold("FIXED"))
+	} else {
+		fmt.Fprintln(w, "SEVERITY\tID\tPACKAGE\tINSTALLED\tFIXED")
+	}
+	for _, v := range vulns {
+		fixed := v.FixedVersion
+		if fixed == "" {
+			fixed = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", v.Severity, v.ID, v.Package, v.InstalledVersion, fixed)
+	}
+	if err := w.Flush(); err != nil {
+		return 0, err
+	}
+	return len(vulns), nil
+}
+
+// runScan scans a container's image, or the base image when no name is given
+func (f *CommandFactory) runScan(cmd *cobra.Command, args []string) error {
+	image := f.Config.BaseImage
+	if len(args) == 1 {
+		cont, err := f.ContainerMgr.GetContainerInfo(context.Background(), args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get container info: %w", err)
+		}
+		image = cont.Image
+	}
+
+	found, err := f.scanImage(cmd, image)
+	if err != nil {
+		return err
+	}
+	if found > 0 {
+		return fmt.Errorf("%d vulnerabilities found in %s\nRebuild the image with 'l8s build' to pick up fixed packages", found, image)
+	}
+	return nil
+}
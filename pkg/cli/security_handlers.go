@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+)
+
+// runSecurityAudit checks that a container runs sshd under an init with
+// root and password logins disabled and only the container user allowed
+func (f *CommandFactory) runSecurityAudit(cmd *cobra.Command, args []string) error {
+	name := strings.TrimPrefix(args[0], f.Config.ContainerPrefix+"-")
+	checks, err := f.ContainerMgr.AuditSecurity(context.Background(), name)
+	if err != nil {
+		return fmt.Errorf("failed to audit %s-%s: %w", f.Config.ContainerPrefix, name, err)
+	}
+
+	failed := 0
+	for _, check := range checks {
+		if check.OK {
+			color.Printf("{green}✓{reset} %s {dim}(%s){reset}\n", check.Name, check.Detail)
+		} else {
+			failed++
+			color.Printf("{red}✗{reset} %s: %s\n", check.Name, check.Detail)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d security checks failed", failed, len(checks))
+	}
+	color.Printf("\n{green}✓{reset} {bold}%s-%s{reset} passed all %d checks\n", f.Config.ContainerPrefix, name, len(checks))
+	return nil
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"testing"
+
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRunSecurityAudit(t *testing.T) {
+	passing := []container.SecurityCheck{
+		{Name: "runs under an init", OK: true, Detail: "PID 1 is catatonit"},
+		{Name: "root login disabled", OK: true, Detail: "permitrootlogin no"},
+	}
+	failing := append([]container.SecurityCheck{
+		{Name: "password authentication disabled", Detail: "passwordauthentication yes"},
+	}, passing...)
+
+	tests := []struct {
+		name    string
+		checks  []container.SecurityCheck
+		wantErr string
+	}{
+		{name: "all checks pass", checks: passing},
+		{name: "a check fails", checks: failing, wantErr: "1 of 3 security checks failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMgr := new(MockContainerManagerWithGit)
+			mockMgr.On("AuditSecurity", mock.Anything, "app").Return(tt.checks, nil)
+
+			factory := &CommandFactory{
+				Config:       &config.Config{ContainerPrefix: "dev"},
+				ContainerMgr: mockMgr,
+			}
+
+			err := factory.runSecurityAudit(&cobra.Command{}, []string{"dev-app"})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockMgr.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"l8s/pkg/api"
+	"l8s/pkg/color"
+	"l8s/pkg/container"
+	"l8s/pkg/embed"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultServeListen keeps the API on loopback unless told otherwise
+const defaultServeListen = "127.0.0.1:8080"
+
+// runServe serves the control API until interrupted
+func (f *CommandFactory) runServe(cmd *cobra.Command, args []string) error {
+	token := f.Config.Serve.Token
+	if token == "" {
+		return fmt.Errorf("no API token configured\n\nSet serve.token in the config (it may be age: or keychain: encrypted) or L8S_SERVE_TOKEN")
+	}
+
+	listen := f.Config.Serve.Listen
+	if cmd.Flags().Changed("listen") || listen == "" {
+		listen, _ = cmd.Flags().GetString("listen")
+	}
+	certFile, _ := cmd.Flags().GetString("tls-cert")
+	keyFile, _ := cmd.Flags().GetString("tls-key")
+	if (certFile == "") != (keyFile == "") {
+		return fmt.Errorf("--tls-cert and --tls-key must be used together")
+	}
+
+	// Create requests without a key install the server user's key
+	sshKey, _ := f.localPublicKey()
+	srv := api.NewServer(f.ContainerMgr, api.Options{
+		Token:       token,
+		Prefix:      f.Config.ContainerPrefix,
+		Connection:  f.Config.ActiveConnection,
+		SSHKey:      sshKey,
+		Connections: f.apiConnections(),
+		Connect: func(name string) (api.Manager, error) {
+			return newConnectionManager(f.Config, name)
+		},
+		RemoveGuard: func(connection string) container.RemoveGuard {
+			// Protection and repositories are recorded per connection
+			cfg := *f.Config
+			cfg.ActiveConnection = connection
+			on := *f
+			on.Config = &cfg
+			return on.removeGuard(false, false, false)
+		},
+		Notify:      f.notifyConnectionEvent,
+	})
+	if dashboard, _ := cmd.Flags().GetBool("dashboard"); dashboard {
+		srv.Handle("GET /{$}", http.HandlerFunc(serveDashboard))
+	}
+	slack := f.Config.Serve.Slack
+	if slack.SigningSecret != "" {
+		srv.EnableSlack(api.SlackOptions{
+			SigningSecret: slack.SigningSecret,
+			Users:         slack.Users,
+			Admins:        slack.Admins,
+		})
+	}
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", listen, err)
+	}
+	server := &http.Server{Handler: srv, ReadHeaderTimeout: 10 * time.Second}
+
+	scheme := "http"
+	if certFile != "" {
+		scheme = "https"
+	} else if !isLoopback(listener.Addr()) {
+		color.Printf("{yellow}!{reset} Serving without TLS on a non-loopback address; the API token is sent in clear text\n")
+	}
+	color.Printf("{green}✓{reset} Serving the l8s API on {bold}%s://%s{reset} for connection '{bold}%s{reset}'\n",
+		scheme, listener.Addr(), f.Config.ActiveConnection)
+	if dashboard, _ := cmd.Flags().GetBool("dashboard"); dashboard {
+		color.Printf("{green}✓{reset} Dashboard: {bold}%s://%s/{reset}\n", scheme, listener.Addr())
+	}
+	if slack.SigningSecret != "" {
+		color.Printf("{green}✓{reset} Slack slash commands: {bold}%s://%s/slack/command{reset} (%d users mapped)\n",
+			scheme, listener.Addr(), len(slack.Users))
+	}
+	color.Printf("{cyan}→{reset} Press Ctrl+C to stop\n")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	errc := make(chan error, 1)
+	go func() {
+		if certFile != "" {
+			errc <- server.ServeTLS(listener, certFile, keyFile)
+		} else {
+			errc <- server.Serve(listener)
+		}
+	}()
+
+	select {
+	case err := <-errc:
+		return fmt.Errorf("API server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	color.Printf("\n{cyan}→{reset} Shutting down...\n")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to shut down API server: %w", err)
+	}
+	return nil
+}
+
+// apiConnections lists the configured connections for the dashboard, the
+// active one first
+func (f *CommandFactory) apiConnections() []api.Connection {
+	connections := make([]api.Connection, 0, len(f.Config.Connections))
+	for name, conn := range f.Config.Connections {
+		connections = append(connections, api.Connection{
+			Name:        name,
+			Address:     conn.Address,
+			Description: conn.Description,
+			Active:      name == f.Config.ActiveConnection,
+		})
+	}
+	sort.Slice(connections, func(i, j int) bool {
+		if connections[i].Active != connections[j].Active {
+			return connections[i].Active
+		}
+		return connections[i].Name < connections[j].Name
+	})
+	return connections
+}
+
+// serveDashboard serves the embedded web UI. It holds no data itself; the
+// page asks for the API token and calls /v1/.
+func serveDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'")
+	w.Header().Set("X-Frame-Options", "DENY")
+	_, _ = w.Write(embed.Dashboard)
+}
+
+// isLoopback reports whether addr only accepts local connections
+func isLoopback(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	return ok && tcp.IP.IsLoopback()
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"l8s/pkg/api"
+	"l8s/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunServeRequiresToken(t *testing.T) {
+	f := NewTestCommandFactory(config.DefaultConfig(), new(MockContainerManagerWithGit), nil, nil)
+	cmd := &cobra.Command{}
+	cmd.Flags().String("listen", defaultServeListen, "")
+
+	err := f.runServe(cmd, nil)
+	assert.ErrorContains(t, err, "no API token configured")
+}
+
+func TestAPIConnections(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ActiveConnection = "work"
+	cfg.Connections = map[string]config.ConnectionConfig{
+		"work":   {Address: "10.0.0.2"},
+		"home":   {Address: "10.0.0.1", Description: "NAS"},
+		"backup": {Address: "10.0.0.3"},
+	}
+	f := NewTestCommandFactory(cfg, nil, nil, nil)
+
+	assert.Equal(t, []api.Connection{
+		{Name: "work", Address: "10.0.0.2", Active: true},
+		{Name: "backup", Address: "10.0.0.3"},
+		{Name: "home", Address: "10.0.0.1", Description: "NAS"},
+	}, f.apiConnections())
+}
+
+func TestServeDashboard(t *testing.T) {
+	rec := httptest.NewRecorder()
+	serveDashboard(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "/v1/connections")
+}
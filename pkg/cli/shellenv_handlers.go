@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"l8s/pkg/color"
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+	"l8s/pkg/embed"
+
+	"github.com/spf13/cobra"
+)
+
+// shellEnvScript returns what 'l8s shellenv' prints for shell. The container
+// prefix is taken from the config when there is one, so completion can offer
+// names without it.
+func shellEnvScript(shell string) ([]byte, error) {
+	script, err := embed.ShellEnv(shell)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := config.DefaultConfig().ContainerPrefix
+	if cfg, err := config.Load(config.GetConfigPath()); err == nil {
+		prefix = cfg.ContainerPrefix
+	}
+	header := fmt.Sprintf("_l8s_prefix=%s\n", container.ShellQuote(prefix))
+	if shell == "fish" {
+		header = fmt.Sprintf("set -g _l8s_prefix %s\n", container.ShellQuote(prefix))
+	}
+	return append([]byte(header), script...), nil
+}
+
+// loginShell is the shell named by $SHELL
+func loginShell() string {
+	return filepath.Base(os.Getenv("SHELL"))
+}
+
+// runShellEnv prints the shell functions and completion for the shell named
+// in args, or the login shell
+func (f *CommandFactory) runShellEnv(cmd *cobra.Command, args []string) error {
+	shell := loginShell()
+	if len(args) > 0 {
+		shell = args[0]
+	}
+	script, err := shellEnvScript(shell)
+	if err != nil {
+		return err
+	}
+	_, err = cmd.OutOrStdout().Write(script)
+	return err
+}
+
+// shellPluginPaths returns where the plugin for shell is written and the
+// startup file that has to source it; fish loads conf.d by itself, so it has
+// none
+func shellPluginPaths(shell string) (plugin, rcFile string, err error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	switch shell {
+	case "bash":
+		return filepath.Join(config.DataDir(), "shell", "l8s.bash"), filepath.Join(homeDir, ".bashrc"), nil
+	case "zsh":
+		zdotdir := os.Getenv("ZDOTDIR")
+		if zdotdir == "" {
+			zdotdir = homeDir
+		}
+		return filepath.Join(config.DataDir(), "shell", "l8s.zsh"), filepath.Join(zdotdir, ".zshrc"), nil
+	case "fish":
+		// fish reads conf.d under $XDG_CONFIG_HOME, like l8s itself
+		fishDir := filepath.Join(filepath.Dir(config.ConfigDir()), "fish")
+		return filepath.Join(fishDir, "conf.d", "l8s.fish"), "", nil
+	}
+	return "", "", fmt.Errorf("unsupported shell %q; use bash, zsh or fish", shell)
+}
+
+// runInstallShellPlugin writes the shellenv output for a shell to a file and
+// makes the shell load it at startup
+func (f *CommandFactory) runInstallShellPlugin(cmd *cobra.Command, args []string) error {
+	shell, _ := cmd.Flags().GetString("shell")
+	if shell == "" {
+		shell = loginShell()
+	}
+	plugin, rcFile, err := shellPluginPaths(shell)
+	if err != nil {
+		return err
+	}
+	script, err := shellEnvScript(shell)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(plugin), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(plugin), err)
+	}
+	if err := os.WriteFile(plugin, script, 0644); err != nil {
+		return fmt.Errorf("failed to write %s plugin: %w", shell, err)
+	}
+	color.Printf("{green}✓{reset} Installed l8s %s plugin to %s\n", shell, plugin)
+
+	if rcFile != "" {
+		rc, err := os.ReadFile(rcFile)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", rcFile, err)
+		}
+		if strings.Contains(string(rc), plugin) {
+			color.Printf("{green}✓{reset} Plugin already loaded by %s\n", rcFile)
+		} else {
+			quoted := container.ShellQuote(plugin)
+			addition := fmt.Sprintf("\n# l8s shell integration\n[ -f %s ] && source %s\n", quoted, quoted)
+			if err := os.WriteFile(rcFile, append(rc, addition...), 0644); err != nil {
+				return fmt.Errorf("failed to update %s: %w", rcFile, err)
+			}
+			color.Printf("{green}✓{reset} Added l8s plugin to %s\n", rcFile)
+		}
+	}
+
+	fmt.Println("\nRestart your shell to load it. Run this again after changing container_prefix.")
+	return nil
+}
+
+// runCd only runs when the shell functions are not loaded, since the l8s
+// function handles cd itself
+func (f *CommandFactory) runCd(cmd *cobra.Command, args []string) error {
+	return fmt.Errorf(`l8s cd needs the l8s shell functions; run 'l8s install-shell-plugin' or add this to
+your shell startup file:
+  bash: eval "$(l8s shellenv bash)"
+  zsh:  eval "$(l8s shellenv zsh)"
+  fish: l8s shellenv fish | source
+
+or change directory with: cd "$(l8s which %s)"`, args[0])
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunShellEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv("SHELL", "/usr/bin/fish")
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+		err := (&CommandFactory{}).runShellEnv(cmd, args)
+		return out.String(), err
+	}
+
+	// No config yet: the default prefix, and the shell from $SHELL
+	out, err := run()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "set -g _l8s_prefix dev\n"), out)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "l8s"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "l8s", "config.yaml"), []byte(`active_connection: default
+connections:
+  default:
+    address: 10.0.0.5
+remote_user: podman
+ssh_key_path: ~/.ssh/id_ed25519
+container_prefix: work
+`), 0644))
+	out, err = run("bash")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "_l8s_prefix=work\n"), out)
+	assert.Contains(t, out, "complete -o default -F _l8s_complete l8s")
+
+	_, err = run("tcsh")
+	assert.ErrorContains(t, err, `unsupported shell "tcsh"`)
+}
+
+func TestRunInstallShellPlugin(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("ZDOTDIR", "")
+
+	install := func(shell string) error {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("shell", shell, "")
+		return (&CommandFactory{}).runInstallShellPlugin(cmd, nil)
+	}
+
+	// Twice: the startup file only gets one source line
+	require.NoError(t, install("zsh"))
+	require.NoError(t, install("zsh"))
+	plugin := filepath.Join(home, ".local", "share", "l8s", "shell", "l8s.zsh")
+	script, err := os.ReadFile(plugin)
+	require.NoError(t, err)
+	assert.Contains(t, string(script), "compdef _l8s l8s")
+	zshrc, err := os.ReadFile(filepath.Join(home, ".zshrc"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(zshrc), "source "+plugin))
+
+	// fish loads conf.d itself
+	require.NoError(t, install("fish"))
+	script, err = os.ReadFile(filepath.Join(home, ".config", "fish", "conf.d", "l8s.fish"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(script), "set -g _l8s_prefix dev\n"))
+
+	assert.ErrorContains(t, install("tcsh"), `unsupported shell "tcsh"`)
+}
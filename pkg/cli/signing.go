@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"l8s/pkg/color"
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+)
+
+// createSigning reads the keys for the configured commit signing. In key mode
+// it also returns the private key to install in the container.
+func (f *CommandFactory) createSigning() (container.Signing, []byte, error) {
+	s := f.Config.Signing
+	if !s.Enabled() {
+		return container.Signing{}, nil, nil
+	}
+
+	var publicKey string
+	var privateKey []byte
+	var err error
+	switch s.Mode {
+	case config.SigningAgent:
+		if s.Key != "" {
+			publicKey, err = f.SSHClient.ReadPublicKey(s.Key)
+		} else {
+			publicKey, err = f.localPublicKey()
+		}
+		if os.Getenv("SSH_AUTH_SOCK") == "" {
+			color.Printf("{yellow}!{reset} Commit signing uses your SSH agent but none is running (SSH_AUTH_SOCK is unset)\n")
+		}
+	case config.SigningKey:
+		if privateKey, err = os.ReadFile(s.Key); err != nil {
+			return container.Signing{}, nil, fmt.Errorf("failed to read signing key: %w", err)
+		}
+		publicKey, err = f.SSHClient.ReadPublicKey(s.Key + ".pub")
+	}
+	if err != nil {
+		return container.Signing{}, nil, fmt.Errorf("failed to read signing public key: %w", err)
+	}
+	return container.Signing{Mode: s.Mode, PublicKey: strings.TrimSpace(publicKey)}, privateKey, nil
+}
+
+// verifySigning makes a signed test commit in the container over SSH, which
+// forwards the agent when signing relies on it
+func (f *CommandFactory) verifySigning(ctx context.Context, name string) error {
+	var output strings.Builder
+	err := f.ContainerMgr.SSHIntoContainer(ctx, name, container.SSHOptions{
+		Command: []string{"sh", "-c", container.SigningCheckScript},
+		Stdout:  &output,
+		Stderr:  &output,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(output.String()))
+	}
+	return nil
+}
+
+// signingDescription explains a config.Signing* mode
+func signingDescription(mode string) string {
+	if mode == config.SigningKey {
+		return "SSH key installed in the container"
+	}
+	return "SSH key in your forwarded agent"
+}
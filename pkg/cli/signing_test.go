@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSigning(t *testing.T) {
+	f := &CommandFactory{Config: &config.Config{}, SSHClient: &MockSSHClient{}}
+	signing, key, err := f.createSigning()
+	require.NoError(t, err)
+	assert.Equal(t, container.Signing{}, signing)
+	assert.Nil(t, key)
+
+	f.Config.Signing = config.SigningConfig{Mode: config.SigningAgent}
+	signing, key, err = f.createSigning()
+	require.NoError(t, err)
+	assert.Equal(t, container.Signing{Mode: "agent", PublicKey: "mock-ssh-key"}, signing)
+	assert.Nil(t, key)
+
+	keyPath := filepath.Join(t.TempDir(), "l8s_signing")
+	require.NoError(t, os.WriteFile(keyPath, []byte("private"), 0600))
+	f.Config.Signing = config.SigningConfig{Mode: config.SigningKey, Key: keyPath}
+	signing, key, err = f.createSigning()
+	require.NoError(t, err)
+	assert.Equal(t, container.Signing{Mode: "key", PublicKey: "mock-ssh-key"}, signing)
+	assert.Equal(t, []byte("private"), key)
+
+	f.Config.Signing.Key = filepath.Join(t.TempDir(), "missing")
+	_, _, err = f.createSigning()
+	assert.ErrorContains(t, err, "failed to read signing key")
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"l8s/pkg/color"
+	"l8s/pkg/container"
+
+	"github.com/spf13/cobra"
+)
+
+// runSparseGet prints the directories the container's working tree is limited to
+func (f *CommandFactory) runSparseGet(cmd *cobra.Command, args []string) error {
+	name, err := f.worktreeContainer("sparse get")
+	if err != nil {
+		return err
+	}
+	return f.sparseGet(context.Background(), name, cmd.OutOrStdout())
+}
+
+// sparseGet lists the sparse checkout directories of a container, one per line
+func (f *CommandFactory) sparseGet(ctx context.Context, name string, out io.Writer) error {
+	dirs, enabled, err := f.sparseDirs(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		color.Printf("{dim}Sparse checkout is off; the whole tree is checked out{reset}\n")
+		return nil
+	}
+	for _, dir := range dirs {
+		fmt.Fprintln(out, dir)
+	}
+	return nil
+}
+
+// sparseDirs reads the container's sparse checkout directories, reporting
+// whether sparse checkout is enabled at all
+func (f *CommandFactory) sparseDirs(ctx context.Context, name string) ([]string, bool, error) {
+	enabled, err := f.containerGit(ctx, name, "config --bool --default false core.sparseCheckout")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read sparse checkout state: %w", err)
+	}
+	if strings.TrimSpace(enabled) != "true" {
+		return nil, false, nil
+	}
+	list, err := f.containerGit(ctx, name, "sparse-checkout list")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list sparse checkout directories: %w", err)
+	}
+	var dirs []string
+	for _, line := range strings.Split(list, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			dirs = append(dirs, line)
+		}
+	}
+	return dirs, true, nil
+}
+
+// runSparseSet replaces the directories the container's working tree is limited to
+func (f *CommandFactory) runSparseSet(cmd *cobra.Command, args []string) error {
+	name, err := f.worktreeContainer("sparse set")
+	if err != nil {
+		return err
+	}
+	repoRoot, err := f.GitClient.GetRepositoryRoot(".")
+	if err != nil {
+		return err
+	}
+
+	var dirs []string
+	for _, arg := range args {
+		dir, err := container.CleanSubdir(arg)
+		if err != nil {
+			return err
+		}
+		if dir == "" {
+			return fmt.Errorf("'%s' is the repository root; use 'l8s sparse disable' to check out everything", arg)
+		}
+		// The container may be on a branch that has the directory when this worktree does not
+		if info, err := os.Stat(filepath.Join(repoRoot, filepath.FromSlash(dir))); err != nil || !info.IsDir() {
+			color.Printf("{yellow}!{reset} '%s' is not a directory in this worktree\n", dir)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	ctx := context.Background()
+	cont, err := f.ContainerMgr.GetContainerInfo(ctx, name)
+	if err != nil {
+		return err
+	}
+	return f.sparseSet(ctx, name, cont.Labels[container.LabelSubdir], dirs)
+}
+
+// sparseSet limits the container's working tree to dirs, keeping the
+// --subdir directory sessions start in
+func (f *CommandFactory) sparseSet(ctx context.Context, name, subdir string, dirs []string) error {
+	if subdir != "" && !slices.Contains(dirs, subdir) {
+		dirs = append([]string{subdir}, dirs...)
+		color.Printf("{dim}Keeping %s, the directory sessions start in{reset}\n", subdir)
+	}
+	if _, err := f.containerGit(ctx, name, container.SparseCheckoutArgs(dirs)); err != nil {
+		return fmt.Errorf("failed to update sparse checkout: %w", err)
+	}
+	color.Printf("{green}✓{reset} Working tree limited to {bold}%s{reset}\n", strings.Join(dirs, ", "))
+	return nil
+}
+
+// runSparseDisable checks out the whole tree in the container again
+func (f *CommandFactory) runSparseDisable(cmd *cobra.Command, args []string) error {
+	name, err := f.worktreeContainer("sparse disable")
+	if err != nil {
+		return err
+	}
+	if _, err := f.containerGit(context.Background(), name, "sparse-checkout disable"); err != nil {
+		return fmt.Errorf("failed to disable sparse checkout: %w", err)
+	}
+	color.Printf("{green}✓{reset} Whole tree checked out\n")
+	return nil
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"l8s/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func gitCmd(script string) []string {
+	return []string{"su", "-", "dev", "-c", "cd /workspace/project && git " + script}
+}
+
+func TestSparseGet(t *testing.T) {
+	mgr := new(MockContainerManagerWithGit)
+	mgr.On("ExecContainerOutput", mock.Anything, "app", gitCmd("config --bool --default false core.sparseCheckout")).Return("true\n", nil)
+	mgr.On("ExecContainerOutput", mock.Anything, "app", gitCmd("sparse-checkout list")).Return("libs/common\nservices/api\n", nil)
+	f := &CommandFactory{Config: &config.Config{ContainerUser: "dev"}, ContainerMgr: mgr}
+
+	var out bytes.Buffer
+	assert.NoError(t, f.sparseGet(context.Background(), "app", &out))
+	assert.Equal(t, "libs/common\nservices/api\n", out.String())
+}
+
+func TestSparseGetDisabled(t *testing.T) {
+	mgr := new(MockContainerManagerWithGit)
+	mgr.On("ExecContainerOutput", mock.Anything, "app", gitCmd("config --bool --default false core.sparseCheckout")).Return("false\n", nil)
+	f := &CommandFactory{Config: &config.Config{ContainerUser: "dev"}, ContainerMgr: mgr}
+
+	var out bytes.Buffer
+	assert.NoError(t, f.sparseGet(context.Background(), "app", &out))
+	assert.Empty(t, out.String())
+	mgr.AssertNumberOfCalls(t, "ExecContainerOutput", 1)
+}
+
+func TestSparseSetKeepsSubdir(t *testing.T) {
+	mgr := new(MockContainerManagerWithGit)
+	mgr.On("ExecContainerOutput", mock.Anything, "app", gitCmd("sparse-checkout set --cone -- services/api docs")).Return("", nil)
+	f := &CommandFactory{Config: &config.Config{ContainerUser: "dev"}, ContainerMgr: mgr}
+
+	assert.NoError(t, f.sparseSet(context.Background(), "app", "services/api", []string{"docs"}))
+	mgr.AssertExpectations(t)
+}
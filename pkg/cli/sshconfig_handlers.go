@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+	"l8s/pkg/config"
+	"l8s/pkg/ssh"
+)
+
+// knownHostsPruner describes the stale entries for the active connection
+func (f *CommandFactory) knownHostsPruner(ctx context.Context) (ssh.KnownHostsPruner, error) {
+	address, err := f.Config.GetActiveAddress()
+	if err != nil {
+		return ssh.KnownHostsPruner{}, fmt.Errorf("failed to get active connection: %w", err)
+	}
+	// Entries are only removed for ports no container uses, so a failed
+	// listing must not be mistaken for an empty one
+	containers, err := f.ContainerMgr.ListContainers(ctx)
+	if err != nil {
+		return ssh.KnownHostsPruner{}, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	// The SSH ranges already stop short of the web ports, which are never SSH hosts
+	p := ssh.KnownHostsPruner{
+		Host:        address,
+		Ports:       config.LimitPortRanges(f.Config.SSHRanges(), f.Config.ActiveQuota().PortBudget),
+		ActivePorts: make(map[int]bool, len(containers)),
+	}
+	for _, c := range containers {
+		p.ActivePorts[c.SSHPort] = true
+	}
+
+	if f.Config.CAPublicKeyPath != "" {
+		if data, err := os.ReadFile(f.Config.CAPublicKeyPath); err == nil {
+			p.CAKey = strings.TrimSpace(string(data))
+		}
+	}
+	for _, conn := range f.Config.Connections {
+		p.CAHosts = append(p.CAHosts, conn.Address)
+	}
+	return p, nil
+}
+
+// runSSHConfigPruneKnownHosts removes known_hosts entries for removed
+// containers from ~/.ssh/known_hosts and the l8s-managed known_hosts file
+func (f *CommandFactory) runSSHConfigPruneKnownHosts(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	pruner, err := f.knownHostsPruner(context.Background())
+	if err != nil {
+		return err
+	}
+
+	paths := []string{filepath.Join(ssh.GetHomeDir(), ".ssh", "known_hosts")}
+	if f.Config.KnownHostsPath != "" && f.Config.KnownHostsPath != paths[0] {
+		paths = append(paths, f.Config.KnownHostsPath)
+	}
+
+	total := 0
+	for _, path := range paths {
+		removed, err := pruner.Prune(path, dryRun)
+		if err != nil {
+			return err
+		}
+		total += len(removed)
+		for _, line := range removed {
+			fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", knownHostsSummary(line))
+		}
+		if len(removed) == 0 {
+			color.Printf("{dim}No stale entries in %s{reset}\n", path)
+		} else if dryRun {
+			color.Printf("{cyan}→{reset} Would remove %d stale entries from %s\n", len(removed), path)
+		} else {
+			color.Printf("{green}✓{reset} Removed %d stale entries from %s\n", len(removed), path)
+		}
+	}
+	if dryRun && total > 0 {
+		color.Printf("{dim}Run without --dry-run to remove them{reset}\n")
+	}
+	return nil
+}
+
+// knownHostsSummary shortens a known_hosts line to its names and key type
+func knownHostsSummary(line string) string {
+	fields := strings.Fields(line)
+	if fields[0] == "@cert-authority" && len(fields) >= 3 {
+		return fields[0] + " " + fields[1] + " " + fields[2]
+	}
+	if len(fields) >= 2 {
+		return fields[0] + " " + fields[1]
+	}
+	return line
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKnownHostsPruner(t *testing.T) {
+	cfg := &config.Config{
+		ActiveConnection: "lan",
+		Connections: map[string]config.ConnectionConfig{
+			"lan":       {Address: "10.0.0.5"},
+			"tailscale": {Address: "100.64.0.5", Quota: config.QuotaConfig{PortBudget: 10}},
+		},
+		SSHPortStart: 2200,
+		WebPortStart: 3000,
+	}
+
+	t.Run("ports of existing containers are active", func(t *testing.T) {
+		mockMgr := new(MockContainerManagerWithGit)
+		mockMgr.On("ListContainers", mock.Anything).Return([]*container.Container{
+			{Name: "dev-a", SSHPort: 2200},
+			{Name: "dev-b", SSHPort: 2203},
+		}, nil)
+
+		factory := &CommandFactory{Config: cfg, ContainerMgr: mockMgr}
+		p, err := factory.knownHostsPruner(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.5", p.Host)
+		assert.Equal(t, []config.PortRange{{First: 2200, Last: 2999}}, p.Ports, "web ports are not SSH ports")
+		assert.Equal(t, map[int]bool{2200: true, 2203: true}, p.ActivePorts)
+		assert.ElementsMatch(t, []string{"10.0.0.5", "100.64.0.5"}, p.CAHosts)
+	})
+
+	t.Run("port budget narrows the range", func(t *testing.T) {
+		budgeted := *cfg
+		budgeted.ActiveConnection = "tailscale"
+		mockMgr := new(MockContainerManagerWithGit)
+		mockMgr.On("ListContainers", mock.Anything).Return([]*container.Container{}, nil)
+
+		factory := &CommandFactory{Config: &budgeted, ContainerMgr: mockMgr}
+		p, err := factory.knownHostsPruner(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []config.PortRange{{First: 2200, Last: 2209}}, p.Ports)
+	})
+
+	t.Run("listing failure is an error", func(t *testing.T) {
+		mockMgr := new(MockContainerManagerWithGit)
+		mockMgr.On("ListContainers", mock.Anything).Return(nil, errors.New("connection refused"))
+
+		factory := &CommandFactory{Config: cfg, ContainerMgr: mockMgr}
+		_, err := factory.knownHostsPruner(context.Background())
+		assert.ErrorContains(t, err, "failed to list containers")
+	})
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/ansiterm"
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+	"l8s/pkg/config"
+	"l8s/pkg/logging"
+	"l8s/pkg/usage"
+)
+
+// usageStore returns the local usage statistics file
+func usageStore() *usage.Store {
+	return usage.NewStore(filepath.Join(config.StateDir(), usage.FileName))
+}
+
+// RecordUsage adds a finished command to the local usage statistics when
+// usage_stats is enabled. Failures to record are only logged.
+func RecordUsage(cmd *cobra.Command, d time.Duration, runErr error) {
+	if cmd == nil || !cmd.Runnable() || cmd == cmd.Root() {
+		return
+	}
+	cfg, err := config.Load(config.GetConfigPath())
+	if err != nil || !cfg.UsageStats {
+		return
+	}
+
+	name := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	if err := usageStore().Record(name, d, runErr != nil, time.Now()); err != nil {
+		logging.Debug("failed to record usage statistics", logging.WithError(err))
+	}
+}
+
+// runStats prints locally recorded statistics
+func runStats(cmd *cobra.Command, args []string) error {
+	showUsage, _ := cmd.Flags().GetBool("usage")
+	reset, _ := cmd.Flags().GetBool("reset")
+	if !showUsage {
+		return fmt.Errorf("choose the statistics to show, e.g. --usage")
+	}
+
+	store := usageStore()
+	if reset {
+		if err := store.Reset(); err != nil {
+			return err
+		}
+		color.Printf("{green}✓{reset} Usage statistics cleared\n")
+		return nil
+	}
+
+	entries, err := store.Entries()
+	if err != nil {
+		return err
+	}
+	if cfg, err := config.Load(config.GetConfigPath()); err == nil && !cfg.UsageStats {
+		color.Printf("{yellow}!{reset} Usage statistics are off; enable them with 'l8s config set usage_stats true'\n")
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No usage recorded yet")
+		return nil
+	}
+	return printUsage(cmd, entries)
+}
+
+// printUsage renders usage entries as a table, slowest overall first
+func printUsage(cmd *cobra.Command, entries []usage.Entry) error {
+	headers := []string{"COMMAND", "RUNS", "FAILED", "AVG", "MAX", "TOTAL", "LAST RUN"}
+	if os.Getenv("NO_COLOR") == "" {
+		for i, h := range headers {
+			headers[i] = color.Bold("%s", h)
+		}
+	}
+
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\n",
+			e.Command,
+			e.Count,
+			e.Failures,
+			roundDuration(e.Average()),
+			roundDuration(e.Max),
+			roundDuration(e.Total),
+			formatDuration(time.Since(e.LastRun)))
+	}
+	return w.Flush()
+}
+
+// roundDuration trims a duration to a precision worth reading
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(100 * time.Millisecond)
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"l8s/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordUsage(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("L8S_ACTIVE_CONNECTION", "home")
+	require.NoError(t, config.WriteFile(config.GetConfigPath(), []byte(
+		"connections:\n  home:\n    address: 10.0.0.1\nremote_user: podman\n")))
+
+	root := &cobra.Command{Use: "l8s"}
+	list := &cobra.Command{Use: "list", RunE: func(*cobra.Command, []string) error { return nil }}
+	root.AddCommand(list)
+
+	// Off by default
+	RecordUsage(list, time.Second, nil)
+	entries, err := usageStore().Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	t.Setenv("L8S_USAGE_STATS", "true")
+	RecordUsage(list, 2*time.Second, nil)
+	RecordUsage(root, time.Second, nil)
+	entries, err = usageStore().Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "list", entries[0].Command)
+
+	stats := &cobra.Command{Use: "stats"}
+	stats.Flags().Bool("usage", false, "")
+	stats.Flags().Bool("reset", false, "")
+	var out bytes.Buffer
+	stats.SetOut(&out)
+
+	assert.EqualError(t, runStats(stats, nil), "choose the statistics to show, e.g. --usage")
+
+	require.NoError(t, stats.Flags().Set("usage", "true"))
+	require.NoError(t, runStats(stats, nil))
+	assert.Contains(t, out.String(), "COMMAND")
+	assert.Regexp(t, `list\s+1\s+0\s+2s\s+2s\s+2s`, out.String())
+
+	require.NoError(t, stats.Flags().Set("reset", "true"))
+	require.NoError(t, runStats(stats, nil))
+	entries, err = usageStore().Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
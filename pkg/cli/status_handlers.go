@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"l8s/pkg/color"
+	"l8s/pkg/container"
+	"l8s/pkg/git"
+
+	"github.com/juju/ansiterm"
+	"github.com/spf13/cobra"
+)
+
+// containerHead is the checked out state of a container's repository
+type containerHead struct {
+	Branch      string
+	SHA         string
+	Uncommitted int
+}
+
+// containerHeadScript prints the branch, commit and number of changed files
+// of the container's repository, one per line
+const containerHeadScript = "cd /workspace/project && git rev-parse --abbrev-ref HEAD && git rev-parse HEAD && git status --porcelain | wc -l"
+
+// runStatusAll shows every worktree of the repository with its container
+// and how far the container has drifted from the worktree
+func (f *CommandFactory) runStatusAll(cmd *cobra.Command) error {
+	repoRoot, err := f.GitClient.GetRepositoryRoot(".")
+	if err != nil {
+		return fmt.Errorf("l8s status --all must be run from within a git repository")
+	}
+	worktrees, err := f.GitClient.ListWorktrees(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	containers, err := f.ContainerMgr.ListContainers(ctx)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]*container.Container, len(containers))
+	for _, c := range containers {
+		byName[c.Name] = c
+	}
+
+	// Map worktrees to containers and ask the running ones what they have checked out
+	names := make([]string, len(worktrees))
+	var running []string
+	for i, wt := range worktrees {
+		if wt.Bare {
+			continue
+		}
+		name, err := containerNameForWorktree(f.Config.ContainerPrefix, wt.Path)
+		if err != nil {
+			continue
+		}
+		names[i] = name
+		if c, ok := byName[name]; ok && c.Status == "running" {
+			running = append(running, strings.TrimPrefix(name, f.Config.ContainerPrefix+"-"))
+		}
+	}
+	heads := make(map[string]*containerHead)
+	for _, r := range runBulk(running, defaultParallel, func(name string) (string, error) {
+		return f.ContainerMgr.ExecContainerOutput(ctx, name,
+			[]string{"su", "-", f.Config.ContainerUser, "-c", containerHeadScript})
+	}, func(bulkResult) {}) {
+		if head, ok := parseContainerHead(r.output); ok && r.err == nil {
+			heads[f.Config.ContainerPrefix+"-"+r.name] = head
+		}
+	}
+
+	current, _ := git.GetWorktreeRoot()
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	headers := []string{"", "WORKTREE", "BRANCH", "CONTAINER", "STATUS", "SYNC"}
+	if os.Getenv("NO_COLOR") == "" {
+		for i, h := range headers {
+			headers[i] = color.Bold("%s", h)
+		}
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	for i, wt := range worktrees {
+		if wt.Bare {
+			continue
+		}
+		marker := " "
+		if samePath(wt.Path, current) {
+			marker = "→"
+		}
+		branch := wt.Branch
+		if branch == "" {
+			branch = "(detached " + shortSHA(wt.HEAD) + ")"
+		}
+
+		name, status, sync := "-", "-", "-"
+		if c, ok := byName[names[i]]; ok {
+			name, status = c.Name, formatStatus(c.Status)
+			sync = f.describeSync(repoRoot, wt, heads[c.Name])
+		} else if names[i] != "" {
+			name = color.Dim + names[i] + " (none)" + color.Reset
+			if os.Getenv("NO_COLOR") != "" {
+				name = names[i] + " (none)"
+			}
+		}
+		fmt.Fprintln(w, strings.Join([]string{marker, displayPath(wt.Path), branch, name, status, sync}, "\t"))
+	}
+	w.Flush()
+	return nil
+}
+
+// parseContainerHead parses the output of containerHeadScript
+func parseContainerHead(output string) (*containerHead, bool) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 {
+		return nil, false
+	}
+	head := &containerHead{Branch: strings.TrimSpace(lines[0]), SHA: strings.TrimSpace(lines[1])}
+	fmt.Sscan(lines[2], &head.Uncommitted)
+	return head, true
+}
+
+// describeSync summarises how the container's checkout relates to the worktree
+func (f *CommandFactory) describeSync(repoRoot string, wt git.Worktree, head *containerHead) string {
+	if head == nil {
+		return "-"
+	}
+	var parts []string
+	if head.Branch != wt.Branch && head.Branch != "HEAD" {
+		parts = append(parts, "on "+head.Branch)
+	}
+	switch {
+	case head.SHA == wt.HEAD:
+		parts = append(parts, "in sync")
+	case !f.GitClient.HasCommit(repoRoot, head.SHA):
+		parts = append(parts, "container has new commits (l8s pull)")
+	default:
+		ahead, behind, err := f.GitClient.AheadBehind(repoRoot, wt.HEAD, head.SHA)
+		switch {
+		case err != nil:
+			parts = append(parts, "unknown")
+		case ahead > 0 && behind > 0:
+			parts = append(parts, fmt.Sprintf("diverged (%d local, %d container)", ahead, behind))
+		case ahead > 0:
+			parts = append(parts, fmt.Sprintf("%d to push", ahead))
+		case behind > 0:
+			parts = append(parts, fmt.Sprintf("%d to pull", behind))
+		default:
+			parts = append(parts, "in sync")
+		}
+	}
+	if head.Uncommitted > 0 {
+		parts = append(parts, fmt.Sprintf("%d uncommitted", head.Uncommitted))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// displayPath shortens paths under the home directory to ~/...
+func displayPath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
+
+// samePath compares two paths after resolving symlinks
+func samePath(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if ra, err := filepath.EvalSymlinks(a); err == nil {
+		a = ra
+	}
+	if rb, err := filepath.EvalSymlinks(b); err == nil {
+		b = rb
+	}
+	return a == b
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"testing"
+
+	"l8s/pkg/git"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseContainerHead(t *testing.T) {
+	head, ok := parseContainerHead("main\nabc123\n       2\n")
+	assert.True(t, ok)
+	assert.Equal(t, &containerHead{Branch: "main", SHA: "abc123", Uncommitted: 2}, head)
+
+	_, ok = parseContainerHead("fatal: not a git repository\n")
+	assert.False(t, ok)
+}
+
+func TestDescribeSync(t *testing.T) {
+	gitClient := new(MockGitClientEnhanced)
+	gitClient.On("HasCommit", "/repo", "unknown").Return(false)
+	gitClient.On("HasCommit", "/repo", "old").Return(true)
+	gitClient.On("HasCommit", "/repo", "forked").Return(true)
+	gitClient.On("AheadBehind", "/repo", "local", "old").Return(2, 0, nil)
+	gitClient.On("AheadBehind", "/repo", "local", "forked").Return(1, 3, nil)
+	f := &CommandFactory{GitClient: gitClient}
+	wt := git.Worktree{Path: "/repo", HEAD: "local", Branch: "main"}
+
+	assert.Equal(t, "-", f.describeSync("/repo", wt, nil))
+	assert.Equal(t, "in sync", f.describeSync("/repo", wt, &containerHead{Branch: "main", SHA: "local"}))
+	assert.Equal(t, "in sync, 4 uncommitted", f.describeSync("/repo", wt, &containerHead{Branch: "main", SHA: "local", Uncommitted: 4}))
+	assert.Equal(t, "container has new commits (l8s pull)", f.describeSync("/repo", wt, &containerHead{Branch: "main", SHA: "unknown"}))
+	assert.Equal(t, "2 to push", f.describeSync("/repo", wt, &containerHead{Branch: "main", SHA: "old"}))
+	assert.Equal(t, "on spike, diverged (1 local, 3 container)", f.describeSync("/repo", wt, &containerHead{Branch: "spike", SHA: "forked"}))
+}
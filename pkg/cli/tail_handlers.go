@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"l8s/pkg/color"
+	"l8s/pkg/container"
+
+	"github.com/spf13/cobra"
+)
+
+// tailColors are cycled through for the sources' prefixes
+var tailColors = []string{color.Cyan, color.Green, color.Yellow, color.Magenta, color.Blue}
+
+// runTail follows files or journald logs in a container, interleaving their
+// lines behind a prefix per source, until interrupted or every source ends
+func (f *CommandFactory) runTail(cmd *cobra.Command, args []string) error {
+	name, sources := args[0], args[1:]
+	lines, _ := cmd.Flags().GetInt("lines")
+	if lines < 0 {
+		return fmt.Errorf("--lines cannot be negative")
+	}
+
+	commands := make([][]string, len(sources))
+	for i, source := range sources {
+		command, err := tailCommand(source, lines)
+		if err != nil {
+			return err
+		}
+		commands[i] = command
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cont, err := f.ContainerMgr.GetContainerInfo(ctx, name)
+	if err != nil {
+		return fmt.Errorf("container '%s' not found: %w", name, err)
+	}
+	if cont.Status != "running" {
+		return fmt.Errorf("container '%s' is not running (status: %s)", name, cont.Status)
+	}
+
+	var mu sync.Mutex
+	prefixes := tailPrefixes(sources)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, command := range commands {
+		stdout := &prefixWriter{mu: &mu, out: os.Stdout, prefix: prefixes[i]}
+		stderr := &prefixWriter{mu: &mu, out: os.Stderr, prefix: prefixes[i]}
+		wg.Add(1)
+		go func(source string, command []string) {
+			defer wg.Done()
+			err := f.ContainerMgr.ExecContainerStream(ctx, name, command, stdout, stderr)
+			stdout.Flush()
+			stderr.Flush()
+			if err != nil && ctx.Err() == nil {
+				fmt.Fprintf(stderr, "%s ended: %v\n", source, err)
+				stderr.Flush()
+			}
+		}(sources[i], command)
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// Followers left in the container exit on their next write once the
+	// attach streams are gone
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+// tailCommand returns the command that follows source in the container: a
+// file, relative paths being taken from the project directory, "journal" for
+// the whole journal or "journal:<unit>" for one systemd unit
+func tailCommand(source string, lines int) ([]string, error) {
+	n := strconv.Itoa(lines)
+	if source == "journal" {
+		return []string{"journalctl", "--no-pager", "-f", "-n", n}, nil
+	}
+	if unit, ok := strings.CutPrefix(source, "journal:"); ok {
+		if unit == "" {
+			return nil, fmt.Errorf("'journal:' needs a unit name, e.g. journal:sshd")
+		}
+		return []string{"journalctl", "--no-pager", "-f", "-n", n, "-u", unit}, nil
+	}
+	if source == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+	if !path.IsAbs(source) {
+		source = path.Join(container.ProjectDir, source)
+	}
+	// -F keeps following through log rotation and files that appear later
+	return []string{"tail", "-F", "-n", n, "--", source}, nil
+}
+
+// tailPrefixes labels each source with its file or unit name, or the whole
+// source where names clash, padded to the same width and colored
+func tailPrefixes(sources []string) []string {
+	labels := make([]string, len(sources))
+	count := map[string]int{}
+	for i, source := range sources {
+		label := strings.TrimPrefix(source, "journal:")
+		if !strings.HasPrefix(source, "journal") {
+			label = path.Base(source)
+		}
+		labels[i] = label
+		count[label]++
+	}
+	width := 0
+	for i, source := range sources {
+		if count[labels[i]] > 1 {
+			labels[i] = source
+		}
+		width = max(width, len(labels[i]))
+	}
+
+	prefixes := make([]string, len(sources))
+	for i, label := range labels {
+		prefixes[i] = fmt.Sprintf("%-*s | ", width, label)
+		if os.Getenv("NO_COLOR") == "" {
+			prefixes[i] = tailColors[i%len(tailColors)] + prefixes[i] + color.Reset
+		}
+	}
+	return prefixes
+}
+
+// prefixWriter writes whole lines to out behind a prefix; writers sharing
+// mu never interleave within a line
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a final line that has no newline yet
+func (w *prefixWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintf(w.out, "%s%s\n", w.prefix, bytes.TrimSuffix(line, []byte("\r")))
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"l8s/pkg/container"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailCommand(t *testing.T) {
+	tests := []struct {
+		source  string
+		want    []string
+		wantErr bool
+	}{
+		{source: "/var/log/app.log", want: []string{"tail", "-F", "-n", "10", "--", "/var/log/app.log"}},
+		{source: "logs/app.log", want: []string{"tail", "-F", "-n", "10", "--", "/workspace/project/logs/app.log"}},
+		{source: "journal", want: []string{"journalctl", "--no-pager", "-f", "-n", "10"}},
+		{source: "journal:nginx", want: []string{"journalctl", "--no-pager", "-f", "-n", "10", "-u", "nginx"}},
+		{source: "journal:", wantErr: true},
+		{source: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			got, err := tailCommand(tt.source, 10)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTailPrefixes(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	assert.Equal(t, []string{
+		"app.log | ",
+		"nginx   | ",
+	}, tailPrefixes([]string{"/var/log/app.log", "journal:nginx"}))
+
+	// Clashing file names fall back to the full source
+	assert.Equal(t, []string{
+		"a/out.log | ",
+		"b/out.log | ",
+		"journal   | ",
+	}, tailPrefixes([]string{"a/out.log", "b/out.log", "journal"}))
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := &prefixWriter{mu: &sync.Mutex{}, out: &out, prefix: "app | "}
+	w.Write([]byte("first li"))
+	assert.Empty(t, out.String(), "partial lines are held back")
+	w.Write([]byte("ne\r\nsecond\nthird"))
+	w.Flush()
+	assert.Equal(t, "app | first line\napp | second\napp | third\n", out.String())
+}
+
+func TestRunTail(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	mockMgr := new(MockContainerManagerWithGit)
+	mockMgr.On("GetContainerInfo", mock.Anything, "myproject").Return(&container.Container{Status: "running"}, nil)
+	mockMgr.On("ExecContainerStream", mock.Anything, "myproject", []string{"tail", "-F", "-n", "5", "--", "/var/log/app.log"}, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			io.WriteString(args.Get(3).(io.Writer), "started\nlistening")
+		}).Return(nil)
+	mockMgr.On("ExecContainerStream", mock.Anything, "myproject", []string{"journalctl", "--no-pager", "-f", "-n", "5", "-u", "db"}, mock.Anything, mock.Anything).
+		Return(nil)
+
+	f := &CommandFactory{ContainerMgr: mockMgr}
+	cmd := &cobra.Command{}
+	cmd.Flags().IntP("lines", "n", 10, "")
+	require.NoError(t, cmd.Flags().Set("lines", "5"))
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = w
+	err = f.runTail(cmd, []string{"myproject", "/var/log/app.log", "journal:db"})
+	os.Stdout = stdout
+	w.Close()
+	require.NoError(t, err)
+
+	output, _ := io.ReadAll(r)
+	assert.Equal(t, "app.log | started\napp.log | listening\n", string(output))
+	mockMgr.AssertExpectations(t)
+}
+
+func TestRunTailStoppedContainer(t *testing.T) {
+	mockMgr := new(MockContainerManagerWithGit)
+	mockMgr.On("GetContainerInfo", mock.Anything, "myproject").Return(&container.Container{Status: "exited"}, nil)
+
+	f := &CommandFactory{ContainerMgr: mockMgr}
+	cmd := &cobra.Command{}
+	cmd.Flags().IntP("lines", "n", 10, "")
+	err := f.runTail(cmd, []string{"myproject", "app.log"})
+	assert.ErrorContains(t, err, "not running")
+	mockMgr.AssertNotCalled(t, "ExecContainerStream", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
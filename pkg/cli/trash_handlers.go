@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/juju/ansiterm"
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+	"l8s/pkg/notify"
+)
+
+// trashRetention returns how long trashed volumes are kept
+func (f *CommandFactory) trashRetention() time.Duration {
+	days := f.Config.TrashRetentionDays
+	if days <= 0 {
+		days = 7
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// purgeExpiredTrash deletes trashed volumes past retention, reporting but not failing
+func (f *CommandFactory) purgeExpiredTrash(ctx context.Context) {
+	purged, err := f.ContainerMgr.PurgeTrash(ctx, f.trashRetention())
+	if err != nil {
+		color.Printf("{yellow}!{reset} Failed to purge expired trash: %v\n", err)
+		return
+	}
+	for _, t := range purged {
+		color.Printf("{dim}Purged %s from trash (removed %s){reset}\n", t.Name, t.TrashedAt.Format("2006-01-02"))
+	}
+}
+
+// runUndoRemove restores a trashed container, or lists the trash without a name
+func (f *CommandFactory) runUndoRemove(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Drop anything that has outlived the retention window first
+	f.purgeExpiredTrash(ctx)
+
+	if len(args) == 0 {
+		return f.listTrash(ctx, cmd)
+	}
+
+	name := strings.TrimPrefix(args[0], f.Config.ContainerPrefix+"-")
+	color.Printf("🎳 {cyan}Restoring container:{reset} {bold}%s-%s{reset}\n", f.Config.ContainerPrefix, name)
+
+	cont, err := f.ContainerMgr.RestoreContainer(ctx, name)
+	if err != nil {
+		return withQuotaHint(fmt.Errorf("failed to restore container: %w", err), f.Config.ActiveConnection)
+	}
+
+	color.Printf("{green}✓{reset} Container restored on SSH port {bold}%d{reset}\n", cont.SSHPort)
+	f.notifyEvent(notify.EventCreate, name)
+	f.syncIngress()
+
+	// Re-add the git remote when run from the worktree it belonged to
+	if repoRoot, err := f.GitClient.GetRepositoryRoot("."); err == nil {
+		remoteURL := fmt.Sprintf("%s-%s:/workspace/project", f.Config.ContainerPrefix, name)
+		if err := f.GitClient.AddRemote(repoRoot, name, remoteURL); err == nil {
+			color.Printf("{green}✓{reset} Git remote '{bold}%s{reset}' added\n", name)
+		}
+	}
+
+	return nil
+}
+
+// listTrash prints trashed containers with their purge dates
+func (f *CommandFactory) listTrash(ctx context.Context, cmd *cobra.Command) error {
+	trashed, err := f.ContainerMgr.ListTrash(ctx)
+	if err != nil {
+		return err
+	}
+	if len(trashed) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "Trash is empty")
+		return nil
+	}
+
+	retention := f.trashRetention()
+	w := ansiterm.NewTabWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	if os.Getenv("NO_COLOR") == "" {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", color.Bold("NAME"), color.Bold("REMOVED"), color.Bold("PURGED AFTER"))
+	} else {
+		fmt.Fprintln(w, "NAME\tREMOVED\tPURGED AFTER")
+	}
+	for _, t := range trashed {
+		fmt.Fprintf(w, "%s\t%s ago\t%s\n",
+			t.Name,
+			formatDuration(time.Since(t.TrashedAt)),
+			t.TrashedAt.Add(retention).Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"l8s/pkg/color"
+	"l8s/pkg/embed"
+)
+
+// printVersion prints the l8s build and the platform it runs on
+func printVersion(version, buildTime string) {
+	if buildTime != "" {
+		color.Printf("{bold}l8s:{reset}            %s {dim}(built %s){reset}\n", version, buildTime)
+	} else {
+		color.Printf("{bold}l8s:{reset}            %s\n", version)
+	}
+	color.Printf("{bold}Go:{reset}             %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	switch err := embed.Verify(); {
+	case errors.Is(err, embed.ErrUnsigned):
+		color.Printf("{bold}Embedded files:{reset} unsigned {dim}(development build){reset}\n")
+	case err != nil:
+		color.Printf("{bold}Embedded files:{reset} {yellow}%v{reset}\n", err)
+	default:
+		color.Printf("{bold}Embedded files:{reset} signed and verified\n")
+	}
+}
+
+// runRemoteVersion prints the remote Podman and API versions and whether
+// the remote is in the range l8s is tested against
+func (f *CommandFactory) runRemoteVersion(cmd *cobra.Command, args []string) error {
+	remote, err := f.ContainerMgr.RemoteVersion(context.Background())
+	if err != nil {
+		return err
+	}
+
+	color.Printf("{bold}Remote Podman:{reset}  %s {dim}(%s){reset}\n", remote.Podman, remote.OSArch)
+	color.Printf("{bold}API version:{reset}    %s {dim}(l8s requires %s or newer){reset}\n", remote.APIVersion, remote.MinAPIVersion)
+	if warning := remote.Warning(); warning != "" {
+		color.Printf("{yellow}!{reset} %s\n", warning)
+		return nil
+	}
+	color.Printf("{green}✓{reset} Compatible\n")
+	return nil
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"l8s/pkg/config"
+	"l8s/pkg/container"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRunRemoteVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  *container.RemoteVersion
+		err     error
+		wantErr string
+	}{
+		{
+			name:   "supported podman",
+			remote: &container.RemoteVersion{Podman: "5.2.1", APIVersion: "5.2.1", MinAPIVersion: "4.0.0", OSArch: "linux/amd64"},
+		},
+		{
+			// Untested versions only warn
+			name:   "untested podman",
+			remote: &container.RemoteVersion{Podman: "6.0.0", APIVersion: "6.0.0", MinAPIVersion: "4.0.0"},
+		},
+		{
+			name:    "unreachable",
+			err:     errors.New("failed to get remote Podman version: connection refused"),
+			wantErr: "failed to get remote Podman version: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMgr := new(MockContainerManagerWithGit)
+			mockMgr.On("RemoteVersion", mock.Anything).Return(tt.remote, tt.err)
+
+			factory := &CommandFactory{
+				Config:       &config.Config{ContainerPrefix: "dev"},
+				ContainerMgr: mockMgr,
+			}
+
+			err := factory.runRemoteVersion(&cobra.Command{}, nil)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockMgr.AssertExpectations(t)
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/hex"
 	"fmt"
 	"path/filepath"
+	"regexp"
+	"strings"
 	
 	"l8s/pkg/git"
 )
@@ -17,6 +19,17 @@ func GetContainerNameFromWorktree(prefix string) (string, error) {
 		return "", fmt.Errorf("not in a git repository: %w", err)
 	}
 	
+	return containerNameForWorktree(prefix, worktreePath)
+}
+
+// containerNameForWorktree returns the container name for the worktree at
+// worktreePath, which need not be the current one
+func containerNameForWorktree(prefix, worktreePath string) (string, error) {
+	// A worktree created for a ticket keeps using the ticket's container
+	if pinned := git.WorktreeContainer(worktreePath); pinned != "" {
+		return fmt.Sprintf("%s-%s", prefix, pinned), nil
+	}
+
 	// Get the repository name
 	repoName, err := git.GetRepositoryName(worktreePath)
 	if err != nil {
@@ -51,4 +64,35 @@ func GetExpectedContainerName(prefix string) string {
 		return ""
 	}
 	return name
-}
\ No newline at end of file
+}
+// unsafeNameChars matches runs of characters not allowed in container names
+var unsafeNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// TicketContainerName derives a container name (without prefix) from the
+// repository name and a ticket ID, e.g. myrepo-jira-123
+func TicketContainerName(repoName, ticket string) string {
+	slug := strings.Trim(unsafeNameChars.ReplaceAllString(strings.ToLower(ticket), "-"), "-")
+	return fmt.Sprintf("%s-%s", repoName, slug)
+}
+
+// unpinWorktree returns the current worktree to its path-derived container
+// name once the container it was pinned to is removed
+func unpinWorktree(name string) {
+	root, err := git.GetWorktreeRoot()
+	if err != nil || git.WorktreeContainer(root) != name {
+		return
+	}
+	_ = git.SetWorktreeContainer(root, "")
+}
+
+// worktreeContainer returns the short name of the current worktree's container
+func (f *CommandFactory) worktreeContainer(command string) (string, error) {
+	if !f.GitClient.IsGitRepository(".") {
+		return "", fmt.Errorf("l8s %s must be run from within a git repository\nThis command requires a git worktree to determine the target container.", command)
+	}
+	fullName, err := GetContainerNameFromWorktree(f.Config.ContainerPrefix)
+	if err != nil {
+		return "", fmt.Errorf("failed to determine container: %w", err)
+	}
+	return strings.TrimPrefix(fullName, f.Config.ContainerPrefix+"-"), nil
+}
@@ -0,0 +1,13 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketContainerName(t *testing.T) {
+	assert.Equal(t, "api-jira-123", TicketContainerName("api", "JIRA-123"))
+	assert.Equal(t, "api-482", TicketContainerName("api", "#482"))
+	assert.Equal(t, "api-team-42-fix", TicketContainerName("api", "Team_42 fix"))
+}
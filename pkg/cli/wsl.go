@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+var (
+	wslOnce     sync.Once
+	wslDetected bool
+)
+
+// isWSL reports whether l8s is running inside Windows Subsystem for Linux,
+// where the clipboard belongs to Windows and is reached through powershell.exe
+func isWSL() bool {
+	wslOnce.Do(func() {
+		if runtime.GOOS != "linux" {
+			return
+		}
+		if os.Getenv("WSL_DISTRO_NAME") != "" {
+			wslDetected = true
+			return
+		}
+		data, err := os.ReadFile("/proc/sys/kernel/osrelease")
+		wslDetected = err == nil && isWSLKernel(string(data))
+	})
+	return wslDetected
+}
+
+// isWSLKernel checks a kernel release string such as
+// "5.15.153.1-microsoft-standard-WSL2"
+func isWSLKernel(release string) bool {
+	release = strings.ToLower(release)
+	return strings.Contains(release, "microsoft") || strings.Contains(release, "wsl")
+}
+
+// usesWindowsClipboard reports whether clipboard access goes through PowerShell
+func usesWindowsClipboard() bool {
+	return runtime.GOOS == "windows" || isWSL()
+}
+
+// windowsPath translates a Linux path to the path Windows programs see, e.g.
+// \\wsl.localhost\Ubuntu\home\dev. Outside WSL, or if wslpath fails, the
+// path is returned unchanged.
+func windowsPath(path string) string {
+	if !isWSL() {
+		return path
+	}
+	output, err := exec.Command("wslpath", "-w", path).Output()
+	if err != nil {
+		return path
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// linuxPath translates a path from a Windows program, e.g. C:\Users\dev, to
+// the path it has under WSL. Outside WSL, or if wslpath fails, the path is
+// returned unchanged.
+func linuxPath(path string) string {
+	if !isWSL() {
+		return path
+	}
+	output, err := exec.Command("wslpath", "-u", path).Output()
+	if err != nil {
+		return path
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -17,6 +17,7 @@ const (
 	Cyan      = "\033[36m"
 	White     = "\033[37m"
 	BoldStyle = "\033[1m"
+	Dim       = "\033[2m"
 )
 
 // isColorEnabled checks if color output should be enabled
@@ -87,6 +88,7 @@ func Printf(format string, args ...interface{}) {
 		format = strings.ReplaceAll(format, "{yellow}", Yellow)
 		format = strings.ReplaceAll(format, "{cyan}", Cyan)
 		format = strings.ReplaceAll(format, "{bold}", BoldStyle)
+		format = strings.ReplaceAll(format, "{dim}", Dim)
 		format = strings.ReplaceAll(format, "{reset}", Reset)
 	} else {
 		// Remove color markers if colors are disabled
@@ -95,6 +97,7 @@ func Printf(format string, args ...interface{}) {
 		format = strings.ReplaceAll(format, "{yellow}", "")
 		format = strings.ReplaceAll(format, "{cyan}", "")
 		format = strings.ReplaceAll(format, "{bold}", "")
+		format = strings.ReplaceAll(format, "{dim}", "")
 		format = strings.ReplaceAll(format, "{reset}", "")
 	}
 	
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// NormalizeAddress returns a connection address in the form it is kept in:
+// trimmed, and with IPv6 literals unbracketed ("[2001:db8::5]" becomes
+// "2001:db8::5") so ssh, known_hosts entries and net.JoinHostPort get what
+// they expect
+func NormalizeAddress(address string) string {
+	address = strings.TrimSpace(address)
+	if strings.HasPrefix(address, "[") && strings.HasSuffix(address, "]") {
+		return address[1 : len(address)-1]
+	}
+	return address
+}
+
+// URLHost returns address as the host part of a URL or scp-style target,
+// bracketing IPv6 literals
+func URLHost(address string) string {
+	if strings.Contains(address, ":") {
+		return "[" + address + "]"
+	}
+	return address
+}
+
+// ValidateAddress checks a connection address: a hostname, an IPv4 address
+// or an IPv6 literal, bracketed or not, with an optional %zone
+func ValidateAddress(address string) error {
+	bare := NormalizeAddress(address)
+	if bare == "" {
+		return fmt.Errorf("address is empty")
+	}
+	if strings.ContainsAny(bare, "[]") {
+		return fmt.Errorf("invalid address '%s': unbalanced brackets", address)
+	}
+	if strings.Contains(bare, ":") {
+		if addr, err := netip.ParseAddr(bare); err != nil || !addr.Is6() {
+			return fmt.Errorf("invalid address '%s': only IPv6 literals may contain ':', and a port cannot be given here", address)
+		}
+		return nil
+	}
+	if strings.ContainsAny(bare, " /@") {
+		return fmt.Errorf("invalid address '%s': expected a hostname or IP address", address)
+	}
+	return nil
+}
+
+// normalizeAddresses rewrites every connection address with NormalizeAddress
+func (c *Config) normalizeAddresses() {
+	for name, conn := range c.Connections {
+		conn.Address = NormalizeAddress(conn.Address)
+		c.Connections[name] = conn
+	}
+}
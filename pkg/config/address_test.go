@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	assert.Equal(t, "2001:db8::5", NormalizeAddress("[2001:db8::5]"))
+	assert.Equal(t, "2001:db8::5", NormalizeAddress(" 2001:db8::5 "))
+	assert.Equal(t, "server.example.com", NormalizeAddress("server.example.com"))
+
+	assert.Equal(t, "[2001:db8::5]", URLHost("2001:db8::5"))
+	assert.Equal(t, "10.0.0.5", URLHost("10.0.0.5"))
+}
+
+func TestValidateAddress(t *testing.T) {
+	for _, address := range []string{"server.example.com", "10.0.0.5", "2001:db8::5", "[2001:db8::5]", "fe80::1%eth0"} {
+		assert.NoError(t, ValidateAddress(address), address)
+	}
+	for _, address := range []string{"", "server:22", "10.0.0.5:22", "[2001:db8::5", "user@server"} {
+		assert.Error(t, ValidateAddress(address), address)
+	}
+}
+
+func TestConfig_NormalizeAddresses(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ActiveConnection = "v6"
+	cfg.Connections = map[string]ConnectionConfig{"v6": {Address: "[2001:db8::5]"}}
+	cfg.normalizeAddresses()
+
+	address, err := cfg.GetActiveAddress()
+	assert.NoError(t, err)
+	assert.Equal(t, "2001:db8::5", address)
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// SourceAutoConnect marks an active_connection chosen by auto_connect rules
+// for the current command
+const SourceAutoConnect = "auto_connect"
+
+// AutoConnectTimeout bounds each reachability probe, which runs before every
+// command that talks to the host
+const AutoConnectTimeout = time.Second
+
+// AutoConnectConfig picks the active connection at startup from rules tried
+// in order, e.g. the Tailscale address while the tailnet is up and the public
+// one otherwise. --connection and L8S_CONNECTION still win.
+type AutoConnectConfig struct {
+	Rules           []AutoConnectRule `yaml:"rules,omitempty"`
+	UpdateSSHConfig bool              `yaml:"update_ssh_config,omitempty"` // Switch as 'l8s connection switch' does instead of for one command
+}
+
+// AutoConnectRule selects Connection when all of its conditions hold; a rule
+// without conditions always matches and serves as the fallback
+type AutoConnectRule struct {
+	Connection string `yaml:"connection"`
+	Reachable  string `yaml:"reachable,omitempty"` // host[:port] that must accept TCP connections (port 22 by default), e.g. 100.64.0.5
+	Interface  string `yaml:"interface,omitempty"` // Local network interface that must be up, e.g. tailscale0 or wg0
+}
+
+// validate checks every rule names a configured connection and a usable address
+func (a AutoConnectConfig) validate(connections map[string]ConnectionConfig) error {
+	for i, rule := range a.Rules {
+		if _, ok := connections[rule.Connection]; !ok {
+			return fmt.Errorf("rules[%d]: unknown connection '%s'", i, rule.Connection)
+		}
+		if rule.Reachable == "" {
+			continue
+		}
+		host, port, err := net.SplitHostPort(rule.probeAddress())
+		if err == nil {
+			err = ValidateAddress(host)
+		}
+		if n, convErr := strconv.Atoi(port); err == nil && (convErr != nil || n < 1 || n > 65535) {
+			err = fmt.Errorf("invalid port '%s'", port)
+		}
+		if err != nil {
+			return fmt.Errorf("rules[%d]: reachable: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Pick returns the first rule that matches
+func (a AutoConnectConfig) Pick(matches func(AutoConnectRule) bool) (AutoConnectRule, bool) {
+	for _, rule := range a.Rules {
+		if matches(rule) {
+			return rule, true
+		}
+	}
+	return AutoConnectRule{}, false
+}
+
+// Matches reports whether the rule's conditions hold on this machine,
+// waiting at most timeout for the reachability probe
+func (r AutoConnectRule) Matches(timeout time.Duration) bool {
+	if r.Interface != "" {
+		iface, err := net.InterfaceByName(r.Interface)
+		if err != nil || iface.Flags&net.FlagUp == 0 {
+			return false
+		}
+	}
+	if r.Reachable != "" {
+		conn, err := net.DialTimeout("tcp", r.probeAddress(), timeout)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+	}
+	return true
+}
+
+// probeAddress returns Reachable with the SSH port added when it has none
+func (r AutoConnectRule) probeAddress() string {
+	if _, _, err := net.SplitHostPort(r.Reachable); err == nil {
+		return r.Reachable
+	}
+	return net.JoinHostPort(NormalizeAddress(r.Reachable), "22")
+}
+
+// UseConnection makes this and every later Load in the process use the named
+// connection, as --connection does, recording auto_connect as the source.
+// Nothing is written to the config file.
+func (c *Config) UseConnection(name string) {
+	flagOverrides["active_connection"] = flagOverride{value: name, flag: SourceAutoConnect, source: SourceAutoConnect}
+	previous := c.ActiveConnection
+	c.ActiveConnection = name
+	c.recordConfiguredConnection(previous)
+	if c.sources == nil {
+		c.sources = make(map[string]string)
+	}
+	c.sources["active_connection"] = SourceAutoConnect
+}
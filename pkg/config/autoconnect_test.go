@@ -0,0 +1,104 @@
+package config
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoConnectConfig_Validate(t *testing.T) {
+	connections := map[string]ConnectionConfig{
+		"tailscale": {Address: "100.64.0.5"},
+		"public":    {Address: "server.example.com"},
+	}
+
+	valid := AutoConnectConfig{Rules: []AutoConnectRule{
+		{Connection: "tailscale", Reachable: "100.64.0.5", Interface: "tailscale0"},
+		{Connection: "tailscale", Reachable: "[fd7a:115c::5]:2222"},
+		{Connection: "public"},
+	}}
+	assert.NoError(t, valid.validate(connections))
+
+	assert.ErrorContains(t, AutoConnectConfig{Rules: []AutoConnectRule{{Connection: "office"}}}.validate(connections),
+		"rules[0]: unknown connection 'office'")
+	assert.ErrorContains(t, AutoConnectConfig{Rules: []AutoConnectRule{{Connection: "public", Reachable: "100.64.0.5:ssh"}}}.validate(connections),
+		"rules[0]: reachable: invalid port 'ssh'")
+	assert.ErrorContains(t, AutoConnectConfig{Rules: []AutoConnectRule{{Connection: "public", Reachable: "user@host"}}}.validate(connections),
+		"rules[0]: reachable:")
+}
+
+func TestAutoConnectConfig_Pick(t *testing.T) {
+	auto := AutoConnectConfig{Rules: []AutoConnectRule{
+		{Connection: "tailscale", Reachable: "100.64.0.5"},
+		{Connection: "public"},
+	}}
+
+	rule, ok := auto.Pick(func(r AutoConnectRule) bool { return true })
+	assert.True(t, ok)
+	assert.Equal(t, "tailscale", rule.Connection)
+
+	rule, ok = auto.Pick(func(r AutoConnectRule) bool { return r.Reachable == "" })
+	assert.True(t, ok)
+	assert.Equal(t, "public", rule.Connection)
+
+	_, ok = auto.Pick(func(r AutoConnectRule) bool { return false })
+	assert.False(t, ok)
+}
+
+func TestAutoConnectRule_Matches(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+	assert.False(t, AutoConnectRule{Reachable: address}.Matches(200*time.Millisecond))
+
+	listener, err = net.Listen("tcp", address)
+	require.NoError(t, err)
+	defer listener.Close()
+	assert.True(t, AutoConnectRule{Reachable: address}.Matches(200*time.Millisecond))
+
+	assert.True(t, AutoConnectRule{}.Matches(200*time.Millisecond))
+	assert.False(t, AutoConnectRule{Interface: "l8s-no-such-if0"}.Matches(200*time.Millisecond))
+	assert.Equal(t, "100.64.0.5:22", AutoConnectRule{Reachable: "100.64.0.5"}.probeAddress())
+	assert.Equal(t, "[fd7a:115c::5]:22", AutoConnectRule{Reachable: "fd7a:115c::5"}.probeAddress())
+}
+
+func TestConfig_UseConnection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `active_connection: public
+connections:
+  public:
+    address: server.example.com
+  tailscale:
+    address: 100.64.0.5
+remote_user: podman
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+	t.Cleanup(func() { flagOverrides = make(map[string]flagOverride) })
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	cfg.UseConnection("tailscale")
+	assert.Equal(t, "tailscale", cfg.ActiveConnection)
+	assert.True(t, cfg.Overridden("active_connection"))
+	configured, err := cfg.ConfiguredConnection()
+	require.NoError(t, err)
+	assert.Equal(t, "server.example.com", configured.Address)
+
+	// Later loads in the same process agree, and the file is untouched
+	reloaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "tailscale", reloaded.ActiveConnection)
+	assert.Equal(t, SourceAutoConnect, reloaded.Source("active_connection"))
+	configured, err = reloaded.ConfiguredConnection()
+	require.NoError(t, err)
+	assert.Equal(t, "server.example.com", configured.Address)
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, data, string(written))
+}
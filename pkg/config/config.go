@@ -4,20 +4,105 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
+	"l8s/pkg/embed"
+	"l8s/pkg/notify"
 )
 
 // ConnectionConfig holds configuration for a network connection to the Podman host
 type ConnectionConfig struct {
-	Address     string `yaml:"address"`     // IP address or hostname
-	Description string `yaml:"description,omitempty"`
+	Address     string        `yaml:"address"` // Hostname, IPv4 address or IPv6 literal
+	ProxyJump   string        `yaml:"proxy_jump,omitempty"` // Bastion to reach the host through, as for ssh -J, e.g. admin@bastion.example.com
+	Description string        `yaml:"description,omitempty"`
+	Quota       QuotaConfig   `yaml:"quota,omitempty"`   // Limits enforced when creating containers
+	Ingress     IngressConfig `yaml:"ingress,omitempty"` // HTTP reverse proxy for container web ports
 	// Future fields can be added here as needed
 }
 
+// QuotaConfig limits how much of a shared host l8s containers may use.
+// Zero values mean unlimited.
+type QuotaConfig struct {
+	MaxContainers int     `yaml:"max_containers,omitempty"` // Containers on this connection
+	MaxMemory     string  `yaml:"max_memory,omitempty"`     // Sum of container memory limits, e.g. "32g"
+	MaxCPUs       float64 `yaml:"max_cpus,omitempty"`       // Sum of container CPU limits
+	PortBudget    int     `yaml:"port_budget,omitempty"`    // SSH ports usable, counted from the start of the SSH port range
+}
+
+// IngressConfig routes <name>.<domain> on a connection's host to each
+// container's web port through a Caddy container managed by l8s
+type IngressConfig struct {
+	Enabled bool   `yaml:"enabled,omitempty"`
+	Domain  string `yaml:"domain,omitempty"` // Wildcard DNS for *.<domain> must point at the host
+	TLS     string `yaml:"tls,omitempty"`    // off (default), internal (self-signed) or auto (Let's Encrypt)
+}
+
+// ServeConfig configures the control API started by 'l8s serve'
+type ServeConfig struct {
+	Listen string      `yaml:"listen,omitempty"` // Address to listen on, e.g. ":8080" (default 127.0.0.1:8080)
+	Token  string      `yaml:"token,omitempty"`  // Bearer token API clients must send
+	Slack  SlackConfig `yaml:"slack,omitempty"`  // /l8s slash commands
+}
+
+// SlackConfig enables Slack slash commands on 'l8s serve'. Slack users act
+// on containers they own, matched by the l8s.owner label.
+type SlackConfig struct {
+	SigningSecret string            `yaml:"signing_secret,omitempty"` // From the Slack app; enables the bridge
+	Users         map[string]string `yaml:"users,omitempty"`          // Slack user ID to l8s owner, e.g. U024BE7LH: alice
+	Admins        []string          `yaml:"admins,omitempty"`         // Slack user IDs that may act on any container
+}
+
+// MountConfig mounts a directory on the Podman host, or a named volume, into
+// containers. Read-only mounts let containers share large reference data.
+type MountConfig struct {
+	Source   string `yaml:"source"`              // Absolute path on the remote host, or a volume name
+	Target   string `yaml:"target"`              // Absolute path inside the container
+	ReadOnly bool   `yaml:"read_only,omitempty"` // Mount without write access
+}
+
+// BackupConfig holds the scheduled volume backup policy
+type BackupConfig struct {
+	Schedule    string `yaml:"schedule,omitempty"`    // Cron expression, e.g. "0 3 * * *"
+	Destination string `yaml:"destination,omitempty"` // Local path, s3://bucket/prefix or ssh://host/path
+	Retention   int    `yaml:"retention,omitempty"`   // Snapshots to keep per container (0 keeps all)
+}
+
+// SSHGuardConfig rate limits logins on container SSH ports, which are
+// published on the remote host's interface
+type SSHGuardConfig struct {
+	MaxAuthTries   int    `yaml:"max_auth_tries,omitempty"`   // Attempts per connection (sshd default 6)
+	LoginGraceTime string `yaml:"login_grace_time,omitempty"` // Time to authenticate, e.g. 30s or 2m
+	MaxStartups    string `yaml:"max_startups,omitempty"`     // Unauthenticated connections, start:rate:full, e.g. 10:30:60
+	Fail2ban       bool   `yaml:"fail2ban,omitempty"`         // Ban addresses with repeated failures
+}
+
+// WebhookConfig is an endpoint notified of container lifecycle events
+type WebhookConfig struct {
+	URL      string            `yaml:"url"`
+	Events   []string          `yaml:"events,omitempty"`   // create, remove, rebuild; empty means all
+	Template string            `yaml:"template,omitempty"` // Go template producing the JSON payload
+	Headers  map[string]string `yaml:"headers,omitempty"`
+}
+
+// Hook converts the configuration for the notifier
+func (w WebhookConfig) Hook() notify.Hook {
+	return notify.Hook{URL: w.URL, Events: w.Events, Template: w.Template, Headers: w.Headers}
+}
+
+// Container runtimes
+const (
+	RuntimePodman = "podman"
+	RuntimeFake   = "fake" // Simulated containers; nothing runs
+)
+
 // Config holds the l8s application configuration
 type Config struct {
+	// Format of the file, upgraded automatically (see CurrentVersion)
+	Version int `yaml:"version"`
+
 	// Active connection selector
 	ActiveConnection string                      `yaml:"active_connection"`
 	
@@ -34,22 +119,115 @@ type Config struct {
 	CAPublicKeyPath  string `yaml:"ca_public_key_path,omitempty"`
 	KnownHostsPath   string `yaml:"known_hosts_path,omitempty"`
 	
+	// Container runtime: podman (default) or fake, which simulates
+	// containers in a local state file for demos and tests
+	Runtime string `yaml:"runtime,omitempty"`
+
 	// Shared settings
 	SSHPortStart int    `yaml:"ssh_port_start"`
 	WebPortStart int    `yaml:"web_port_start"`
+	PortRange    int    `yaml:"port_range,omitempty"` // Ports allocated from each start (default DefaultPortRange)
+	SSHPortRanges []string `yaml:"ssh_port_ranges,omitempty"` // e.g. ["2200-2299", "4400-4499"]; replaces ssh_port_start and port_range
 	AudioEnabled bool   `yaml:"audio_enabled"` // Whether audio support is enabled
 	AudioPort    int    `yaml:"audio_port"`    // PulseAudio TCP port (default 4713)
 	BaseImage    string `yaml:"base_image"`
+	BaseFlavor   string `yaml:"base_flavor,omitempty"` // Distribution the image is built from: fedora (default), debian or ubuntu
 	ContainerPrefix string `yaml:"container_prefix"`
 	ContainerUser   string `yaml:"container_user"`
 	SSHPublicKey    string `yaml:"ssh_public_key"`
 	DotfilesPath    string `yaml:"dotfiles_path,omitempty"`
 	GitHubToken     string `yaml:"github_token,omitempty"`
+
+	// Resource limits applied to every container (empty/zero means unlimited)
+	ContainerMemory string  `yaml:"container_memory,omitempty"` // e.g. "4g"
+	ContainerCPUs   float64 `yaml:"container_cpus,omitempty"`   // e.g. 2 or 1.5
+
+	// Runtime options for new containers; 'l8s create' flags override them
+	ShmSize string            `yaml:"shm_size,omitempty"` // /dev/shm size, e.g. "2g" (podman defaults to 64m)
+	Tmpfs   []string          `yaml:"tmpfs,omitempty"`    // tmpfs mounts as path[:size], e.g. "/tmp:4g"
+	Ulimits map[string]string `yaml:"ulimits,omitempty"`  // e.g. nofile: "1048576" or nproc: "4096:8192" (soft:hard)
+	Sysctls map[string]string `yaml:"sysctls,omitempty"`  // Namespaced kernel parameters, e.g. net.core.somaxconn: "4096"
+
+	// Privileged tooling opt-ins for debuggers and profilers (gdb, strace, perf)
+	CapAdd            []string `yaml:"cap_add,omitempty"`            // Extra capabilities, e.g. SYS_PTRACE or PERFMON
+	SeccompUnconfined bool     `yaml:"seccomp_unconfined,omitempty"` // Run without the default seccomp filter
+	SELinuxOpts       []string `yaml:"selinux_opts,omitempty"`       // Label options, e.g. disable or type:spc_t
+
+	// Let podman and buildah run inside new containers
+	NestedContainers bool `yaml:"nested_containers,omitempty"`
+
+	// Run systemd as PID 1 in new containers so services can be managed with systemctl
+	Systemd bool `yaml:"systemd,omitempty"`
+
+	// Host directories bind-mounted into every new container
+	Mounts []MountConfig `yaml:"mounts,omitempty"`
+
+	// Login rate limiting for container SSH ports
+	SSHGuard SSHGuardConfig `yaml:"ssh_guard,omitempty"`
+
+	// Host environment variables passed into l8s ssh and exec sessions, e.g.
+	// LANG, LC_* or HTTP_PROXY; NAME=value entries set a fixed value
+	ExecEnv []string `yaml:"exec_env,omitempty"`
+
+	// HTTP proxy for package managers, git and npm in new containers
+	Proxy ProxyConfig `yaml:"proxy,omitempty"`
+
+	// Extra images built with 'l8s build --variant' and used by 'l8s create --image-variant'
+	ImageVariants map[string]ImageVariantConfig `yaml:"image_variants,omitempty"`
+
+	// Record packages installed in containers with dnf or apt and install them again after rebuilds
+	PersistPackages bool `yaml:"persist_packages,omitempty"`
+
+	// Volume backup policy
+	Backup BackupConfig `yaml:"backup,omitempty"`
+
+	// Removal trash settings
+	RemoveToTrash      bool `yaml:"remove_to_trash,omitempty"`      // Move volumes to trash instead of deleting
+	TrashRetentionDays int  `yaml:"trash_retention_days,omitempty"` // Days before trashed volumes are purged
+
+	// Lifecycle notifications
+	Webhooks []WebhookConfig `yaml:"webhooks,omitempty"`
+
+	// Control API for dashboards and chatops bots
+	Serve ServeConfig `yaml:"serve,omitempty"`
+
+	// Container and branch naming for 'l8s create --ticket'
+	Ticket TicketConfig `yaml:"ticket,omitempty"`
+
+	// Mirror the host repository's origin remote into new containers
+	Origin OriginConfig `yaml:"origin,omitempty"`
+
+	// SSH commit signing inside containers
+	Signing SigningConfig `yaml:"signing,omitempty"`
+
+	// Pre-push hook refusing pushes to protected branches and origin
+	PushGuard PushGuardConfig `yaml:"push_guard,omitempty"`
+
+	// Rules choosing the active connection at startup, e.g. Tailscale when reachable
+	AutoConnect AutoConnectConfig `yaml:"auto_connect,omitempty"`
+
+	// Pastes larger than this ask for confirmation, e.g. "50m" (default 10m)
+	PasteMaxSize string `yaml:"paste_max_size,omitempty"`
+
+	// Record command counts and durations locally for 'l8s stats --usage'
+	UsageStats bool `yaml:"usage_stats,omitempty"`
+
+	// age identity for decrypting "age:" values (default: age.key in ConfigDir)
+	AgeIdentity string `yaml:"age_identity,omitempty"`
+
+	// sources maps dotted keys to where their values were set (see Settings)
+	sources map[string]string
+	// encrypted records keys whose values were decrypted at load time
+	encrypted map[string]bool
+	// configuredConnection is the file's active_connection when an override
+	// replaced it (see ConfiguredConnection)
+	configuredConnection string
 }
 
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	return &Config{
+		Version:          CurrentVersion,
 		ActiveConnection: "",
 		Connections:      make(map[string]ConnectionConfig),
 		RemoteUser:       "",
@@ -57,14 +235,15 @@ func DefaultConfig() *Config {
 		SSHKeyPath:       "",
 		
 		// Shared defaults
-		SSHPortStart: 2200,
-		WebPortStart: 3000,
-		AudioEnabled: true,
-		AudioPort:    4713,
-		BaseImage:    "localhost/l8s-fedora:latest",
-		ContainerPrefix: "dev",
-		SSHPublicKey:    "", // Empty means auto-detect
-		ContainerUser:   "dev",
+		SSHPortStart:       2200,
+		WebPortStart:       3000,
+		AudioEnabled:       true,
+		AudioPort:          4713,
+		BaseImage:          "localhost/l8s-fedora:latest",
+		ContainerPrefix:    "dev",
+		SSHPublicKey:       "", // Empty means auto-detect
+		ContainerUser:      "dev",
+		TrashRetentionDays: 7,
 	}
 }
 
@@ -88,6 +267,17 @@ func (c *Config) Validate() error {
 	if activeConn.Address == "" {
 		return fmt.Errorf("address is required for connection '%s'", c.ActiveConnection)
 	}
+	for name, conn := range c.Connections {
+		if conn.Address == "" {
+			continue
+		}
+		if err := ValidateAddress(conn.Address); err != nil {
+			return fmt.Errorf("connection '%s': %w", name, err)
+		}
+		if strings.ContainsAny(conn.ProxyJump, " \t") || strings.HasPrefix(conn.ProxyJump, "-") {
+			return fmt.Errorf("connection '%s': proxy_jump must be [user@]host[:port], comma separated for several hops", name)
+		}
+	}
 	
 	// Validate host settings (same for all connections)
 	if c.RemoteUser == "" {
@@ -104,10 +294,28 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("web_port_start must be between 1024 and 65000")
 	}
 
+	if c.PortRange < 0 || c.PortRange > 10000 {
+		return fmt.Errorf("port_range must be between 1 and 10000")
+	}
+	if err := validatePortRanges(c.SSHPortRanges); err != nil {
+		return fmt.Errorf("ssh_port_ranges: %w", err)
+	}
+	for _, r := range c.SSHRanges() {
+		if c.WebPortStart >= r.First && c.WebPortStart <= r.Last {
+			return fmt.Errorf("web_port_start %d falls inside SSH port range %s", c.WebPortStart, r)
+		}
+	}
+
 	// Validate base image
 	if c.BaseImage == "" {
 		return fmt.Errorf("base_image cannot be empty")
 	}
+	if _, err := embed.ContainerfileFor(c.BaseFlavor); err != nil {
+		return fmt.Errorf("base_flavor: %w", err)
+	}
+	if c.Runtime != "" && c.Runtime != RuntimePodman && c.Runtime != RuntimeFake {
+		return fmt.Errorf("runtime must be '%s' or '%s', got '%s'", RuntimePodman, RuntimeFake, c.Runtime)
+	}
 
 	// Validate container prefix
 	if c.ContainerPrefix == "" {
@@ -129,16 +337,328 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("container_user must be a valid Linux username")
 	}
 
+	// Validate backup policy
+	if c.Backup.Schedule != "" && !isValidCronExpression(c.Backup.Schedule) {
+		return fmt.Errorf("backup.schedule must be a valid cron expression (e.g. \"0 3 * * *\")")
+	}
+	if c.Backup.Retention < 0 {
+		return fmt.Errorf("backup.retention cannot be negative")
+	}
+	if c.TrashRetentionDays < 0 {
+		return fmt.Errorf("trash_retention_days cannot be negative")
+	}
+	if err := c.Ticket.validate(); err != nil {
+		return fmt.Errorf("ticket.%w", err)
+	}
+	if err := c.Origin.validate(); err != nil {
+		return fmt.Errorf("origin.%w", err)
+	}
+	if err := c.Signing.validate(); err != nil {
+		return fmt.Errorf("signing.%w", err)
+	}
+	if err := c.PushGuard.validate(); err != nil {
+		return fmt.Errorf("push_guard.%w", err)
+	}
+	if err := c.AutoConnect.validate(c.Connections); err != nil {
+		return fmt.Errorf("auto_connect.%w", err)
+	}
+	if err := c.Proxy.validate(); err != nil {
+		return fmt.Errorf("proxy.%w", err)
+	}
+	if err := validateImageVariants(c.ImageVariants); err != nil {
+		return fmt.Errorf("image_variants.%w", err)
+	}
+
+	// Validate resource limits and quotas
+	if _, err := ParseSize(c.ContainerMemory); err != nil {
+		return fmt.Errorf("container_memory: %w", err)
+	}
+	if c.ContainerCPUs < 0 {
+		return fmt.Errorf("container_cpus cannot be negative")
+	}
+	if _, err := ParseSize(c.ShmSize); err != nil {
+		return fmt.Errorf("shm_size: %w", err)
+	}
+	if _, err := ParseSize(c.PasteMaxSize); err != nil {
+		return fmt.Errorf("paste_max_size: %w", err)
+	}
+	for _, entry := range c.Tmpfs {
+		if _, _, err := ParseTmpfs(entry); err != nil {
+			return fmt.Errorf("tmpfs: %w", err)
+		}
+	}
+	for name, value := range c.Ulimits {
+		if _, _, err := ParseUlimit(name, value); err != nil {
+			return fmt.Errorf("ulimits: %w", err)
+		}
+	}
+	for key, value := range c.Sysctls {
+		if err := ValidateSysctl(key, value); err != nil {
+			return fmt.Errorf("sysctls: %w", err)
+		}
+	}
+	for _, capability := range c.CapAdd {
+		if _, err := NormalizeCapability(capability); err != nil {
+			return fmt.Errorf("cap_add: %w", err)
+		}
+	}
+	for _, opt := range c.SELinuxOpts {
+		if err := ValidateSELinuxOpt(opt); err != nil {
+			return fmt.Errorf("selinux_opts: %w", err)
+		}
+	}
+	for name, conn := range c.Connections {
+		if err := c.validateQuota(conn.Quota); err != nil {
+			return fmt.Errorf("connections.%s.quota: %w", name, err)
+		}
+		if err := validateIngress(conn.Ingress); err != nil {
+			return fmt.Errorf("connections.%s.ingress: %w", name, err)
+		}
+	}
+
+	if err := ValidateMounts(c.Mounts); err != nil {
+		return err
+	}
+	if err := validateSSHGuard(c.SSHGuard); err != nil {
+		return fmt.Errorf("ssh_guard.%w", err)
+	}
+	if err := validateExecEnv(c.ExecEnv); err != nil {
+		return fmt.Errorf("exec_env: %w", err)
+	}
+
+	// Validate webhooks
+	for i, w := range c.Webhooks {
+		if err := w.Hook().Validate(); err != nil {
+			return fmt.Errorf("webhooks[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// validateQuota checks a connection quota against the configured resource limits
+func (c *Config) validateQuota(q QuotaConfig) error {
+	if q.MaxContainers < 0 || q.MaxCPUs < 0 || q.PortBudget < 0 {
+		return fmt.Errorf("limits cannot be negative")
+	}
+	if _, err := ParseSize(q.MaxMemory); err != nil {
+		return fmt.Errorf("max_memory: %w", err)
+	}
+	// Usage is the sum of per-container limits, so those must be set
+	if q.MaxMemory != "" && c.ContainerMemory == "" {
+		return fmt.Errorf("max_memory requires container_memory to be set")
+	}
+	if q.MaxCPUs > 0 && c.ContainerCPUs == 0 {
+		return fmt.Errorf("max_cpus requires container_cpus to be set")
+	}
+	return nil
+}
+
+// volumeNamePattern matches podman volume names usable as mount sources
+var volumeNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ValidateMounts checks that mounts use an absolute path or volume name as
+// source, an absolute target, and that no two share a target
+func ValidateMounts(mounts []MountConfig) error {
+	targets := make(map[string]bool, len(mounts))
+	for i, m := range mounts {
+		if !strings.HasPrefix(m.Source, "/") && !volumeNamePattern.MatchString(m.Source) {
+			return fmt.Errorf("mounts[%d]: source must be an absolute path or a volume name", i)
+		}
+		if !strings.HasPrefix(m.Target, "/") {
+			return fmt.Errorf("mounts[%d]: target must be an absolute path", i)
+		}
+		// Mounts are recorded in a container label as source:target pairs
+		if strings.ContainsAny(m.Source+m.Target, ",:") {
+			return fmt.Errorf("mounts[%d]: paths cannot contain ',' or ':'", i)
+		}
+		target := filepath.Clean(m.Target)
+		if target == "/" || target == "/workspace" || strings.HasPrefix(target, "/home/") && strings.Count(target, "/") == 2 {
+			return fmt.Errorf("mounts[%d]: target %s would hide the container's own files", i, m.Target)
+		}
+		if targets[target] {
+			return fmt.Errorf("mounts[%d]: target %s is mounted twice", i, m.Target)
+		}
+		targets[target] = true
+	}
+	return nil
+}
+
+// validateIngress checks a connection's ingress settings
+func validateIngress(in IngressConfig) error {
+	switch in.TLS {
+	case "", "off", "internal", "auto":
+	default:
+		return fmt.Errorf("tls must be off, internal or auto")
+	}
+	if in.Enabled && in.Domain == "" {
+		return fmt.Errorf("domain is required when ingress is enabled")
+	}
+	if strings.ContainsAny(in.Domain, " /:*") {
+		return fmt.Errorf("domain must be a plain host name such as dev.example.com")
+	}
+	return nil
+}
+
+// ParseSize parses a memory size such as "512m" or "4g" into bytes using
+// binary units like podman's --memory. An empty string parses as 0.
+func ParseSize(input string) (int64, error) {
+	size := strings.ToLower(strings.TrimSpace(input))
+	if size == "" {
+		return 0, nil
+	}
+	size = strings.TrimSuffix(size, "b")
+	multiplier := int64(1)
+	if n := len(size); n > 0 {
+		switch size[n-1] {
+		case 'k':
+			multiplier = 1 << 10
+		case 'm':
+			multiplier = 1 << 20
+		case 'g':
+			multiplier = 1 << 30
+		case 't':
+			multiplier = 1 << 40
+		}
+		if multiplier > 1 {
+			size = size[:n-1]
+		}
+	}
+	value, err := strconv.ParseFloat(size, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size '%s' (use e.g. 512m or 4g)", input)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
+// DefaultPasteMaxSize is the paste size above which l8s paste asks first
+const DefaultPasteMaxSize = 10 << 20
+
+// PasteLimit returns paste_max_size in bytes, or DefaultPasteMaxSize when unset
+func (c *Config) PasteLimit() int64 {
+	if size, err := ParseSize(c.PasteMaxSize); err == nil && size > 0 {
+		return size
+	}
+	return DefaultPasteMaxSize
+}
+
+// ParseTmpfs splits a tmpfs entry such as "/tmp:4g" into its container path
+// and size in bytes (0 when no size is given)
+func ParseTmpfs(entry string) (string, int64, error) {
+	path, sizeStr, _ := strings.Cut(entry, ":")
+	if !strings.HasPrefix(path, "/") || strings.Contains(path, ",") {
+		return "", 0, fmt.Errorf("invalid tmpfs '%s': path must be absolute (use /path or /path:size)", entry)
+	}
+	size, err := ParseSize(sizeStr)
+	if err != nil {
+		return "", 0, err
+	}
+	return filepath.Clean(path), size, nil
+}
+
+// ulimitNames are the resource limits accepted in ulimits, as in 'podman --ulimit'
+var ulimitNames = map[string]bool{
+	"as": true, "core": true, "cpu": true, "data": true, "fsize": true, "locks": true,
+	"memlock": true, "msgqueue": true, "nice": true, "nofile": true, "nproc": true,
+	"rss": true, "rtprio": true, "rttime": true, "sigpending": true, "stack": true,
+}
+
+// ParseUlimit parses a ulimit value given as soft[:hard]; hard defaults to soft
+func ParseUlimit(name, value string) (uint64, uint64, error) {
+	if !ulimitNames[name] {
+		return 0, 0, fmt.Errorf("unknown ulimit '%s' (e.g. nofile, nproc, memlock)", name)
+	}
+	softStr, hardStr, hasHard := strings.Cut(value, ":")
+	soft, err := strconv.ParseUint(softStr, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid %s limit '%s' (use soft or soft:hard)", name, value)
+	}
+	hard := soft
+	if hasHard {
+		if hard, err = strconv.ParseUint(hardStr, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid %s limit '%s' (use soft or soft:hard)", name, value)
+		}
+	}
+	if soft > hard {
+		return 0, 0, fmt.Errorf("%s soft limit %d is above the hard limit %d", name, soft, hard)
+	}
+	return soft, hard, nil
+}
+
+// sysctlKeyPattern matches kernel parameter names such as net.core.somaxconn
+var sysctlKeyPattern = regexp.MustCompile(`^[a-z0-9_]+(\.[a-zA-Z0-9_-]+)+$`)
+
+// ValidateSysctl checks a kernel parameter setting. Whether the parameter may
+// be set per container is left to podman.
+func ValidateSysctl(key, value string) error {
+	if !sysctlKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid sysctl name '%s'", key)
+	}
+	// Sysctls are recorded in a container label as key=value pairs
+	if value == "" || strings.ContainsAny(value, ",=") {
+		return fmt.Errorf("invalid value for %s: must be non-empty without ',' or '='", key)
+	}
+	return nil
+}
+
+// capabilityPattern matches Linux capability names without the CAP_ prefix
+var capabilityPattern = regexp.MustCompile(`^[A-Z][A-Z_]*$`)
+
+// NormalizeCapability upper-cases a capability name and strips any CAP_
+// prefix, so "sys_ptrace" and "CAP_SYS_PTRACE" both become SYS_PTRACE
+func NormalizeCapability(name string) (string, error) {
+	normalized := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(name)), "CAP_")
+	if !capabilityPattern.MatchString(normalized) {
+		return "", fmt.Errorf("invalid capability '%s' (e.g. SYS_PTRACE)", name)
+	}
+	return normalized, nil
+}
+
+// ValidateSELinuxOpt checks an SELinux label option as accepted by
+// 'podman --security-opt label=...': disable, nested, or user, role, type,
+// level or filetype followed by ':' and a value
+func ValidateSELinuxOpt(opt string) error {
+	switch opt {
+	case "disable", "nested":
+		return nil
+	}
+	key, value, ok := strings.Cut(opt, ":")
+	switch key {
+	case "user", "role", "type", "level", "filetype":
+		if ok && value != "" && !strings.Contains(opt, ";") {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid SELinux option '%s' (use disable, nested or type:<type>, level:<level>, ...)", opt)
+}
+
+// sshdTimePattern matches sshd time formats such as 30, 30s or 1m30s
+var sshdTimePattern = regexp.MustCompile(`^([0-9]+[sSmMhHdDwW]?)+$`)
+
+// maxStartupsPattern matches sshd MaxStartups: a count or start:rate:full
+var maxStartupsPattern = regexp.MustCompile(`^[0-9]+(:[0-9]+:[0-9]+)?$`)
+
+// validateSSHGuard checks login rate limits; errors name the offending key
+func validateSSHGuard(g SSHGuardConfig) error {
+	if g.MaxAuthTries < 0 {
+		return fmt.Errorf("max_auth_tries cannot be negative")
+	}
+	if g.LoginGraceTime != "" && !sshdTimePattern.MatchString(g.LoginGraceTime) {
+		return fmt.Errorf("login_grace_time: invalid time '%s' (e.g. 30s or 2m)", g.LoginGraceTime)
+	}
+	if g.MaxStartups != "" && !maxStartupsPattern.MatchString(g.MaxStartups) {
+		return fmt.Errorf("max_startups: invalid value '%s' (use a count or start:rate:full, e.g. 10:30:60)", g.MaxStartups)
+	}
 	return nil
 }
 
 // GetConfigPath returns the default config file path
 func GetConfigPath() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".config", "l8s", "config.yaml")
+	return filepath.Join(ConfigDir(), "config.yaml")
 }
 
-// Load loads configuration from the specified path
+// Load loads configuration from the specified path and applies L8S_*
+// environment and command line flag overrides on top (see EnvPrefix)
 func Load(path string) (*Config, error) {
 	// Expand tilde if present
 	path = expandPath(path)
@@ -148,7 +668,17 @@ func Load(path string) (*Config, error) {
 
 	// Check if config file exists
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		// No config file, validate defaults
+		// No config file, validate defaults and environment overrides
+		if err := config.applyEnv(os.LookupEnv); err != nil {
+			return nil, fmt.Errorf("invalid environment override: %w", err)
+		}
+		if err := config.applyFlags(); err != nil {
+			return nil, err
+		}
+		if err := config.decryptSecrets(); err != nil {
+			return nil, fmt.Errorf("failed to decrypt config: %w", err)
+		}
+		config.normalizeAddresses()
 		if err := config.Validate(); err != nil {
 			return nil, fmt.Errorf("invalid configuration: %w", err)
 		}
@@ -161,10 +691,31 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	// Older formats are upgraded in memory; Migrate rewrites the file itself
+	data, _, err = Upgrade(data)
+	if err != nil {
+		return nil, err
+	}
+
 	// Parse YAML
 	if err := yaml.Unmarshal(data, config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	config.recordSources(data, path)
+
+	// Environment overrides take precedence over the file, and flags over both
+	configured := config.ActiveConnection
+	if err := config.applyEnv(os.LookupEnv); err != nil {
+		return nil, fmt.Errorf("invalid environment override: %w", err)
+	}
+	if err := config.applyFlags(); err != nil {
+		return nil, err
+	}
+	config.recordConfiguredConnection(configured)
+	if err := config.decryptSecrets(); err != nil {
+		return nil, fmt.Errorf("failed to decrypt config: %w", err)
+	}
+	config.normalizeAddresses()
 
 	// Validate configuration
 	if err := config.Validate(); err != nil {
@@ -174,10 +725,13 @@ func Load(path string) (*Config, error) {
 	// Expand paths in config
 	config.SSHPublicKey = expandPath(config.SSHPublicKey)
 	config.DotfilesPath = expandPath(config.DotfilesPath)
+	config.Signing.Key = expandPath(config.Signing.Key)
 	config.SSHKeyPath = expandPath(config.SSHKeyPath)
 	config.CAPrivateKeyPath = expandPath(config.CAPrivateKeyPath)
 	config.CAPublicKeyPath = expandPath(config.CAPublicKeyPath)
 	config.KnownHostsPath = expandPath(config.KnownHostsPath)
+	config.Backup.Destination = expandPath(config.Backup.Destination)
+	config.expandVariantPaths(filepath.Dir(path))
 	
 	// Set defaults
 	if config.RemoteSocket == "" {
@@ -218,7 +772,7 @@ func expandPath(path string) string {
 		return path
 	}
 
-	if strings.HasPrefix(path, "~/") {
+	if strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
 		home, err := os.UserHomeDir()
 		if err != nil {
 			home = os.Getenv("HOME")
@@ -279,6 +833,27 @@ func isValidUsername(username string) bool {
 	return true
 }
 
+// isValidCronExpression checks for a five-field cron expression or a macro like @daily
+func isValidCronExpression(expr string) bool {
+	switch expr {
+	case "@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly":
+		return true
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return false
+	}
+	for _, field := range fields {
+		for _, ch := range field {
+			if !isDigit(ch) && !isLowerLetter(ch) && !strings.ContainsRune("*/,-", ch) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // isLowerLetter checks if a rune is a lowercase letter
 func isLowerLetter(ch rune) bool {
 	return ch >= 'a' && ch <= 'z'
@@ -289,6 +864,24 @@ func isDigit(ch rune) bool {
 	return ch >= '0' && ch <= '9'
 }
 
+// ActiveQuota returns the quota of the active connection (zero value if none)
+func (c *Config) ActiveQuota() QuotaConfig {
+	conn, err := c.GetActiveConnection()
+	if err != nil {
+		return QuotaConfig{}
+	}
+	return conn.Quota
+}
+
+// ActiveProxyJump returns the jump host of the active connection, if any
+func (c *Config) ActiveProxyJump() string {
+	conn, err := c.GetActiveConnection()
+	if err != nil {
+		return ""
+	}
+	return conn.ProxyJump
+}
+
 // GetActiveConnection returns the active connection configuration
 func (c *Config) GetActiveConnection() (*ConnectionConfig, error) {
 	if c.ActiveConnection == "" {
@@ -303,6 +896,28 @@ func (c *Config) GetActiveConnection() (*ConnectionConfig, error) {
 	return &conn, nil
 }
 
+// ConfiguredConnection returns the active connection named in the config
+// file, ignoring --connection, L8S_CONNECTION and auto_connect. SSH config
+// entries point at it so a one-off override never rewrites them; only
+// 'l8s connection switch' does.
+func (c *Config) ConfiguredConnection() (*ConnectionConfig, error) {
+	if c.configuredConnection == "" {
+		return c.GetActiveConnection()
+	}
+	conn, exists := c.Connections[c.configuredConnection]
+	if !exists {
+		return nil, fmt.Errorf("configured connection '%s' not found in configuration", c.configuredConnection)
+	}
+	return &conn, nil
+}
+
+// recordConfiguredConnection remembers name if an override replaced it
+func (c *Config) recordConfiguredConnection(name string) {
+	if name != "" && name != c.ActiveConnection && c.configuredConnection == "" {
+		c.configuredConnection = name
+	}
+}
+
 // GetActiveAddress returns just the address of the active connection
 func (c *Config) GetActiveAddress() (string, error) {
 	conn, err := c.GetActiveConnection()
@@ -314,12 +929,7 @@ func (c *Config) GetActiveAddress() (string, error) {
 
 // SetActiveConnection updates the active connection
 func (c *Config) SetActiveConnection(name string) error {
-	if _, exists := c.Connections[name]; !exists {
-		return fmt.Errorf("connection '%s' not found in configuration", name)
-	}
-	
-	c.ActiveConnection = name
-	return c.Save(GetConfigPath())
+	return c.SetActiveConnectionWithPath(name, GetConfigPath())
 }
 
 // SetActiveConnectionWithPath updates the active connection and saves to a specific path (useful for testing)
@@ -329,7 +939,38 @@ func (c *Config) SetActiveConnectionWithPath(name string, path string) error {
 	}
 	
 	c.ActiveConnection = name
-	return c.Save(path)
+	c.configuredConnection = ""
+
+	// Only rewrite active_connection so environment and flag overrides
+	// applied by Load never leak into the file
+	data, err := os.ReadFile(expandPath(path))
+	if os.IsNotExist(err) {
+		return c.Save(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	updated, err := SetValue(data, "active_connection", name)
+	if err != nil {
+		return err
+	}
+	return WriteFile(path, updated)
+}
+
+// Source returns where the value of the dotted key came from: SourceDefault,
+// the config file path, or the overriding variable or flag
+func (c *Config) Source(key string) string {
+	if source, ok := c.sources[key]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// Overridden reports whether key was set by an environment variable, a flag
+// or, for active_connection, an auto_connect rule
+func (c *Config) Overridden(key string) bool {
+	source := c.Source(key)
+	return strings.HasPrefix(source, "env ") || strings.HasPrefix(source, "flag ") || source == SourceAutoConnect
 }
 
 // ListConnections returns all configured connections
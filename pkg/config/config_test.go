@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -53,6 +54,7 @@ ssh_public_key: "~/.ssh/custom_key.pub"
 container_user: "lucian"
 `,
 			expectedConfig: &Config{
+				Version:          CurrentVersion, // upgraded in memory
 				ActiveConnection: "default",
 				Connections: map[string]ConnectionConfig{
 					"default": {
@@ -71,6 +73,7 @@ container_user: "lucian"
 				ContainerPrefix: "work",
 				SSHPublicKey:    filepath.Join(home, ".ssh/custom_key.pub"),
 				ContainerUser:   "lucian",
+				TrashRetentionDays: 7, // default
 			},
 			wantErr: false,
 		},
@@ -110,6 +113,7 @@ remote_user: "root"
 container_user: "developer"
 `,
 			expectedConfig: &Config{
+				Version:          CurrentVersion, // upgraded in memory
 				ActiveConnection: "prod",
 				Connections: map[string]ConnectionConfig{
 					"prod": {
@@ -127,6 +131,7 @@ container_user: "developer"
 				ContainerPrefix: "dev", // default
 				SSHPublicKey:    "", // default
 				ContainerUser:   "developer",
+				TrashRetentionDays: 7, // default
 			},
 			wantErr: false,
 		},
@@ -145,6 +150,7 @@ remote_user: "admin"
 container_prefix: "test"
 `,
 			expectedConfig: &Config{
+				Version:          CurrentVersion, // upgraded in memory
 				ActiveConnection: "vpn",
 				Connections: map[string]ConnectionConfig{
 					"default": {
@@ -167,6 +173,7 @@ container_prefix: "test"
 				ContainerPrefix: "test",
 				SSHPublicKey:    "",
 				ContainerUser:   "dev",
+				TrashRetentionDays: 7, // default
 			},
 			wantErr: false,
 		},
@@ -201,6 +208,8 @@ container_prefix: "test"
 				}
 			}
 
+			// Where values came from is covered by TestSettingsSources
+			cfg.sources = nil
 			assert.Equal(t, tt.expectedConfig, cfg)
 		})
 	}
@@ -301,6 +310,26 @@ func TestValidateConfig(t *testing.T) {
 			wantErr: true,
 			errMsg:  "address is required",
 		},
+		{
+			name: "unknown runtime",
+			config: &Config{
+				ActiveConnection: "default",
+				Connections: map[string]ConnectionConfig{
+					"default": {
+						Address: "server.example.com",
+					},
+				},
+				RemoteUser:      "admin",
+				SSHPortStart:    2200,
+				WebPortStart:    3000,
+				BaseImage:       "localhost/l8s-fedora:latest",
+				ContainerPrefix: "dev",
+				ContainerUser:   "dev",
+				Runtime:         "docker",
+			},
+			wantErr: true,
+			errMsg:  "runtime must be 'podman' or 'fake'",
+		},
 		{
 			name: "missing remote_user",
 			config: &Config{
@@ -452,6 +481,131 @@ func TestValidateConfig(t *testing.T) {
 			wantErr: true,
 			errMsg:  "container_user must be a valid Linux username",
 		},
+		{
+			name: "invalid backup schedule",
+			config: &Config{
+				ActiveConnection: "default",
+				Connections: map[string]ConnectionConfig{
+					"default": {
+						Address: "server.example.com",
+					},
+				},
+				RemoteUser:      "admin",
+				SSHPortStart:    2200,
+				WebPortStart:    3000,
+				BaseImage:       "localhost/l8s-fedora:latest",
+				ContainerPrefix: "dev",
+				ContainerUser:   "dev",
+				Backup: BackupConfig{
+					Schedule:    "every night",
+					Destination: "/var/backups/l8s",
+				},
+			},
+			wantErr: true,
+			errMsg:  "backup.schedule must be a valid cron expression",
+		},
+		{
+			name: "memory quota without container limit",
+			config: &Config{
+				ActiveConnection: "default",
+				Connections: map[string]ConnectionConfig{
+					"default": {
+						Address: "server.example.com",
+						Quota:   QuotaConfig{MaxContainers: 5, MaxMemory: "32g"},
+					},
+				},
+				RemoteUser:      "admin",
+				SSHPortStart:    2200,
+				WebPortStart:    3000,
+				BaseImage:       "localhost/l8s-fedora:latest",
+				ContainerPrefix: "dev",
+				ContainerUser:   "dev",
+			},
+			wantErr: true,
+			errMsg:  "max_memory requires container_memory",
+		},
+		{
+			name: "webhook with unknown event",
+			config: &Config{
+				ActiveConnection: "default",
+				Connections: map[string]ConnectionConfig{
+					"default": {
+						Address: "server.example.com",
+					},
+				},
+				RemoteUser:      "admin",
+				SSHPortStart:    2200,
+				WebPortStart:    3000,
+				BaseImage:       "localhost/l8s-fedora:latest",
+				ContainerPrefix: "dev",
+				ContainerUser:   "dev",
+				Webhooks: []WebhookConfig{
+					{URL: "https://hooks.slack.com/services/T000/B000/XXXX", Events: []string{"deploy"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "webhooks[0]: unknown event 'deploy'",
+		},
+		{
+			name: "ssh port ranges overlapping web ports",
+			config: &Config{
+				ActiveConnection: "default",
+				Connections: map[string]ConnectionConfig{
+					"default": {
+						Address: "server.example.com",
+					},
+				},
+				RemoteUser:      "admin",
+				SSHPortStart:    2200,
+				WebPortStart:    3000,
+				SSHPortRanges:   []string{"2200-2299", "2950-3050"},
+				BaseImage:       "localhost/l8s-fedora:latest",
+				ContainerPrefix: "dev",
+				ContainerUser:   "dev",
+			},
+			wantErr: true,
+			errMsg:  "web_port_start 3000 falls inside SSH port range 2950-3050",
+		},
+		{
+			name: "overlapping ssh port ranges",
+			config: &Config{
+				ActiveConnection: "default",
+				Connections: map[string]ConnectionConfig{
+					"default": {
+						Address: "server.example.com",
+					},
+				},
+				RemoteUser:      "admin",
+				SSHPortStart:    2200,
+				WebPortStart:    3000,
+				SSHPortRanges:   []string{"2200-2299", "2250-2350"},
+				BaseImage:       "localhost/l8s-fedora:latest",
+				ContainerPrefix: "dev",
+				ContainerUser:   "dev",
+			},
+			wantErr: true,
+			errMsg:  "ssh_port_ranges: port ranges 2200-2299 and 2250-2350 overlap",
+		},
+		{
+			name: "proxy jump with spaces",
+			config: &Config{
+				ActiveConnection: "default",
+				Connections: map[string]ConnectionConfig{
+					"default": {
+						Address:   "10.0.0.5",
+						ProxyJump: "-o ProxyCommand=evil bastion",
+					},
+				},
+				RemoteUser:      "admin",
+				SSHPortStart:    2200,
+				WebPortStart:    3000,
+				BaseImage:       "localhost/l8s-fedora:latest",
+				ContainerPrefix: "dev",
+				ContainerUser:   "dev",
+			},
+			wantErr: true,
+			errMsg:  "connection 'default': proxy_jump must be [user@]host[:port]",
+		},
 	}
 
 	for _, tt := range tests {
@@ -470,6 +624,112 @@ func TestValidateConfig(t *testing.T) {
 	}
 }
 
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "", want: 0},
+		{input: "1024", want: 1024},
+		{input: "512m", want: 512 << 20},
+		{input: "4g", want: 4 << 30},
+		{input: "4GB", want: 4 << 30},
+		{input: "1.5g", want: 3 << 29},
+		{input: "lots", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSize(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPasteLimit(t *testing.T) {
+	cfg := &Config{}
+	assert.Equal(t, int64(DefaultPasteMaxSize), cfg.PasteLimit())
+
+	cfg.PasteMaxSize = "50m"
+	assert.Equal(t, int64(50<<20), cfg.PasteLimit())
+}
+
+func TestParseTmpfs(t *testing.T) {
+	path, size, err := ParseTmpfs("/tmp:4g")
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp", path)
+	assert.Equal(t, int64(4<<30), size)
+
+	path, size, err = ParseTmpfs("/cache/")
+	require.NoError(t, err)
+	assert.Equal(t, "/cache", path)
+	assert.Zero(t, size)
+
+	for _, entry := range []string{"tmp", "/tmp:lots", "/a,b"} {
+		_, _, err := ParseTmpfs(entry)
+		assert.Error(t, err, entry)
+	}
+}
+
+func TestParseUlimit(t *testing.T) {
+	soft, hard, err := ParseUlimit("nofile", "1048576")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1048576), soft)
+	assert.Equal(t, uint64(1048576), hard)
+
+	soft, hard, err = ParseUlimit("nproc", "4096:8192")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4096), soft)
+	assert.Equal(t, uint64(8192), hard)
+
+	for _, tt := range [][2]string{{"files", "10"}, {"nofile", "many"}, {"nofile", "10:x"}, {"nofile", "20:10"}} {
+		_, _, err := ParseUlimit(tt[0], tt[1])
+		assert.Error(t, err, tt)
+	}
+}
+
+func TestValidateSysctl(t *testing.T) {
+	assert.NoError(t, ValidateSysctl("net.core.somaxconn", "4096"))
+	assert.NoError(t, ValidateSysctl("net.ipv4.ip_local_port_range", "1024 65000"))
+	assert.Error(t, ValidateSysctl("somaxconn", "4096"))
+	assert.Error(t, ValidateSysctl("net.core.somaxconn", ""))
+	assert.Error(t, ValidateSysctl("net.core.somaxconn", "1,2"))
+}
+
+func TestNormalizeCapability(t *testing.T) {
+	for _, name := range []string{"SYS_PTRACE", "sys_ptrace", "CAP_SYS_PTRACE"} {
+		got, err := NormalizeCapability(name)
+		require.NoError(t, err)
+		assert.Equal(t, "SYS_PTRACE", got)
+	}
+	_, err := NormalizeCapability("sys-ptrace")
+	assert.Error(t, err)
+}
+
+func TestValidateSELinuxOpt(t *testing.T) {
+	for _, opt := range []string{"disable", "nested", "type:spc_t", "level:s0:c1,c2"} {
+		assert.NoError(t, ValidateSELinuxOpt(opt), opt)
+	}
+	for _, opt := range []string{"", "type", "type:", "colour:red", "type:a;b"} {
+		assert.Error(t, ValidateSELinuxOpt(opt), opt)
+	}
+}
+
+func TestValidateSSHGuard(t *testing.T) {
+	assert.NoError(t, validateSSHGuard(SSHGuardConfig{}))
+	assert.NoError(t, validateSSHGuard(SSHGuardConfig{MaxAuthTries: 3, LoginGraceTime: "1m30s", MaxStartups: "10:30:60", Fail2ban: true}))
+	assert.NoError(t, validateSSHGuard(SSHGuardConfig{LoginGraceTime: "30", MaxStartups: "5"}))
+	assert.Error(t, validateSSHGuard(SSHGuardConfig{MaxAuthTries: -1}))
+	assert.Error(t, validateSSHGuard(SSHGuardConfig{LoginGraceTime: "30 seconds"}))
+	assert.Error(t, validateSSHGuard(SSHGuardConfig{MaxStartups: "10:30"}))
+}
+
 func TestConnectionMethods(t *testing.T) {
 	t.Run("GetActiveConnection", func(t *testing.T) {
 		cfg := &Config{
@@ -556,4 +816,225 @@ func TestConnectionMethods(t *testing.T) {
 		assert.Equal(t, "192.168.1.100", conns["default"].Address)
 		assert.Equal(t, "10.0.0.50", conns["vpn"].Address)
 	})
-}
\ No newline at end of file
+	
+	t.Run("ActiveProxyJump", func(t *testing.T) {
+		cfg := &Config{
+			ActiveConnection: "office",
+			Connections: map[string]ConnectionConfig{
+				"office": {
+					Address:   "10.0.0.5",
+					ProxyJump: "me@bastion.example.com",
+				},
+				"direct": {
+					Address: "192.168.1.100",
+				},
+			},
+		}
+		
+		assert.Equal(t, "me@bastion.example.com", cfg.ActiveProxyJump())
+		cfg.ActiveConnection = "direct"
+		assert.Empty(t, cfg.ActiveProxyJump())
+	})
+}
+func TestCheck(t *testing.T) {
+	valid := `active_connection: home
+connections:
+  home:
+    address: 10.0.0.1
+remote_user: podman
+`
+	assert.Empty(t, Check([]byte(valid)))
+
+	typo := valid + `ssh_port_strat: 2300
+connections_extra: true
+backup:
+  retension: 3
+`
+	problems := Check([]byte(typo))
+	require.Len(t, problems, 3)
+	assert.Equal(t, "line 6: unknown key 'ssh_port_strat' (did you mean 'ssh_port_start'?)", problems[0].String())
+	assert.Equal(t, 7, problems[1].Line)
+	assert.Equal(t, "line 9: unknown key 'backup.retension' (did you mean 'retention'?)", problems[2].String())
+
+	invalid := Check([]byte("active_connection: home\n"))
+	require.Len(t, invalid, 1)
+	assert.Contains(t, invalid[0].Message, "at least one connection")
+}
+
+func TestSettingsSources(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `active_connection: home
+connections:
+  home:
+    address: 10.0.0.1
+remote_user: podman
+github_token: secret
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	settings, err := cfg.Settings()
+	require.NoError(t, err)
+
+	byKey := make(map[string]Setting)
+	for _, s := range settings {
+		byKey[s.Key] = s
+	}
+	assert.Equal(t, path, byKey["connections.home.address"].Source)
+	assert.Equal(t, "10.0.0.1", byKey["connections.home.address"].Value)
+	assert.Equal(t, SourceDefault, byKey["ssh_port_start"].Source)
+	assert.Equal(t, "2200", byKey["ssh_port_start"].Value)
+	assert.Equal(t, "********", byKey["github_token"].Value)
+}
+
+func TestSetValue(t *testing.T) {
+	data := []byte(`# l8s config
+active_connection: home
+connections:
+  home:
+    address: 10.0.0.1 # LAN
+remote_user: podman
+base_image: localhost/l8s-fedora:latest
+`)
+
+	t.Run("replaces existing value and keeps comments", func(t *testing.T) {
+		out, err := SetValue(data, "connections.home.address", "10.0.0.2")
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "# l8s config")
+		assert.Contains(t, string(out), "address: 10.0.0.2 # LAN")
+		assert.Empty(t, Check(out))
+	})
+
+	t.Run("creates missing keys with inferred types", func(t *testing.T) {
+		out, err := SetValue(data, "backup.retention", "3")
+		require.NoError(t, err)
+		cfg := DefaultConfig()
+		require.NoError(t, yaml.Unmarshal(out, cfg))
+		assert.Equal(t, 3, cfg.Backup.Retention)
+	})
+
+	t.Run("quotes values YAML would reinterpret", func(t *testing.T) {
+		out, err := SetValue(data, "dotfiles_path", "")
+		require.NoError(t, err)
+		assert.Contains(t, string(out), `dotfiles_path: ""`)
+	})
+
+	t.Run("rejects non-scalar targets", func(t *testing.T) {
+		_, err := SetValue(data, "connections", "x")
+		assert.Error(t, err)
+		_, err = SetValue(data, "base_image.tag", "x")
+		assert.Error(t, err)
+		_, err = SetValue(data, "backup..retention", "1")
+		assert.Error(t, err)
+	})
+
+	t.Run("typos are caught by Check", func(t *testing.T) {
+		out, err := SetValue(data, "base_imag", "foo")
+		require.NoError(t, err)
+		assert.NotEmpty(t, Check(out))
+	})
+}
+
+func TestEnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `active_connection: home
+connections:
+  home:
+    address: 10.0.0.1
+  work:
+    address: 10.0.0.2
+remote_user: podman
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	t.Run("overrides file and defaults", func(t *testing.T) {
+		t.Setenv("L8S_REMOTE_USER", "ci")
+		t.Setenv("L8S_SSH_PORT_START", "2400")
+		t.Setenv("L8S_AUDIO_ENABLED", "false")
+		t.Setenv("L8S_BACKUP_RETENTION", "2")
+		t.Setenv("L8S_CONNECTION", "work")
+
+		cfg, err := Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, "ci", cfg.RemoteUser)
+		assert.Equal(t, 2400, cfg.SSHPortStart)
+		assert.False(t, cfg.AudioEnabled)
+		assert.Equal(t, 2, cfg.Backup.Retention)
+		assert.Equal(t, "work", cfg.ActiveConnection)
+
+		settings, err := cfg.Settings()
+		require.NoError(t, err)
+		for _, s := range settings {
+			if s.Key == "remote_user" {
+				assert.Equal(t, "env L8S_REMOTE_USER", s.Source)
+			}
+		}
+	})
+
+	t.Run("full name beats alias", func(t *testing.T) {
+		t.Setenv("L8S_CONNECTION", "work")
+		t.Setenv("L8S_ACTIVE_CONNECTION", "home")
+
+		cfg, err := Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, "home", cfg.ActiveConnection)
+	})
+
+	t.Run("invalid values are rejected", func(t *testing.T) {
+		t.Setenv("L8S_SSH_PORT_START", "lots")
+
+		_, err := Load(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "L8S_SSH_PORT_START")
+	})
+
+	t.Run("variables cover nested keys", func(t *testing.T) {
+		vars := EnvVars()
+		assert.Equal(t, "backup.destination", vars["L8S_BACKUP_DESTINATION"])
+		assert.Equal(t, "active_connection", vars["L8S_CONNECTION"])
+		assert.NotContains(t, vars, "L8S_CONNECTIONS")
+	})
+}
+
+func TestFlagOverrides(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `active_connection: home
+connections:
+  home:
+    address: 10.0.0.1
+  work:
+    address: 10.0.0.2
+remote_user: podman
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+	t.Cleanup(func() { flagOverrides = make(map[string]flagOverride) })
+
+	assert.Error(t, SetFlagOverride("no_such_key", "x", "--nope"))
+
+	t.Setenv("L8S_CONNECTION", "home")
+	require.NoError(t, SetFlagOverride("active_connection", "work", "--connection"))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "work", cfg.ActiveConnection)
+	assert.Equal(t, "flag --connection", cfg.Source("active_connection"))
+	assert.True(t, cfg.Overridden("active_connection"))
+	assert.False(t, cfg.Overridden("remote_user"))
+	configured, err := cfg.ConfiguredConnection()
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", configured.Address)
+
+	// Switching connections must not persist the override or other settings
+	require.NoError(t, cfg.SetActiveConnectionWithPath("home", path))
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(written), "active_connection: home")
+	assert.NotContains(t, string(written), "ssh_port_start")
+	configured, err = cfg.ConfiguredConnection()
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", configured.Address)
+}
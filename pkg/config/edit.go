@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetValue sets the dotted key in configuration data to value, keeping the
+// rest of the document (including comments) intact. Missing parent mappings
+// are created; lists cannot be set this way.
+func SetValue(data []byte, key, value string) ([]byte, error) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("invalid key '%s'", key)
+		}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	node := doc.Content[0]
+	for i, part := range parts {
+		if node.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("cannot set '%s': '%s' is not a mapping", key, strings.Join(parts[:i], "."))
+		}
+		var child *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == part {
+				child = node.Content[j+1]
+				break
+			}
+		}
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: part}, child)
+		}
+		node = child
+	}
+
+	if node.Kind == yaml.SequenceNode || (node.Kind == yaml.MappingNode && len(node.Content) > 0) {
+		return nil, fmt.Errorf("cannot set '%s' to a single value; use 'l8s config edit'", key)
+	}
+
+	// Let YAML infer the type again so numbers and booleans stay unquoted;
+	// anything else it would reinterpret (null, timestamps) is quoted
+	*node = yaml.Node{Kind: yaml.ScalarNode, Value: value, LineComment: node.LineComment}
+	switch node.ShortTag() {
+	case "!!str", "!!int", "!!float", "!!bool":
+	default:
+		node.Style = yaml.DoubleQuotedStyle
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return out, nil
+}
+
+// WriteFile atomically replaces the config file at path with data
+func WriteFile(path string, data []byte) error {
+	path = expandPath(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
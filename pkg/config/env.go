@@ -0,0 +1,187 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts every configuration override variable. The rest of the
+// name is the upper-cased YAML key with dots replaced by underscores, e.g.
+// L8S_BASE_IMAGE for base_image or L8S_BACKUP_DESTINATION for backup.destination.
+//
+// Precedence, highest first: command line flags (see SetFlagOverride), L8S_*
+// variables, the config file, built-in defaults. Maps and lists (connections, webhooks) can only be
+// set in the config file.
+const EnvPrefix = "L8S_"
+
+// envAliases are short names for commonly overridden settings. The full
+// variable wins if both are set.
+var envAliases = map[string]string{
+	"L8S_CONNECTION": "active_connection",
+}
+
+// envField is a scalar config field that can be overridden from the environment
+type envField struct {
+	key   string
+	index []int
+}
+
+// envFields lists the overridable fields of t keyed by variable name
+func envFields(t reflect.Type, prefix string, index []int, fields map[string]envField) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := yamlName(field)
+		if !ok {
+			continue
+		}
+		key := joinKey(prefix, name)
+		if key == "version" {
+			continue // Describes the file, not a setting
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			envFields(field.Type, key, fieldIndex, fields)
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Float64:
+			env := EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+			fields[env] = envField{key: key, index: fieldIndex}
+		}
+	}
+}
+
+// EnvVars returns every supported override variable mapped to its config key
+func EnvVars() map[string]string {
+	fields := make(map[string]envField)
+	envFields(reflect.TypeOf(Config{}), "", nil, fields)
+
+	vars := make(map[string]string, len(fields)+len(envAliases))
+	for env, field := range fields {
+		vars[env] = field.key
+	}
+	for alias, key := range envAliases {
+		vars[alias] = key
+	}
+	return vars
+}
+
+// applyEnv overrides fields from L8S_* variables found by lookup
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	fields := make(map[string]envField)
+	envFields(reflect.TypeOf(Config{}), "", nil, fields)
+
+	// Aliases first so the full variable name takes precedence
+	names := make([]string, 0, len(envAliases)+len(fields))
+	for alias := range envAliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	full := make([]string, 0, len(fields))
+	for env := range fields {
+		full = append(full, env)
+	}
+	sort.Strings(full)
+	names = append(names, full...)
+
+	byKey := make(map[string]envField, len(fields))
+	for _, field := range fields {
+		byKey[field.key] = field
+	}
+
+	for _, env := range names {
+		value, ok := lookup(env)
+		if !ok {
+			continue
+		}
+		field, isField := fields[env]
+		if !isField {
+			field = byKey[envAliases[env]]
+		}
+		if err := setField(reflect.ValueOf(c).Elem().FieldByIndex(field.index), value); err != nil {
+			return fmt.Errorf("%s: %w", env, err)
+		}
+		if c.sources == nil {
+			c.sources = make(map[string]string)
+		}
+		c.sources[field.key] = "env " + env
+	}
+	return nil
+}
+
+// flagOverride is a setting forced by a command line flag
+type flagOverride struct {
+	value  string
+	flag   string
+	source string // Recorded as where the value came from
+}
+
+// flagOverrides are applied by Load after environment overrides
+var flagOverrides = make(map[string]flagOverride)
+
+// SetFlagOverride makes every subsequent Load use value for the dotted key,
+// recording flag (e.g. "--connection") as its source. Nothing is written to
+// the config file.
+func SetFlagOverride(key, value, flag string) error {
+	fields := make(map[string]envField)
+	envFields(reflect.TypeOf(Config{}), "", nil, fields)
+	for _, field := range fields {
+		if field.key == key {
+			flagOverrides[key] = flagOverride{value: value, flag: flag, source: "flag " + flag}
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown setting '%s'", key)
+}
+
+// applyFlags applies overrides registered with SetFlagOverride
+func (c *Config) applyFlags() error {
+	if len(flagOverrides) == 0 {
+		return nil
+	}
+	fields := make(map[string]envField)
+	envFields(reflect.TypeOf(Config{}), "", nil, fields)
+	for _, field := range fields {
+		override, ok := flagOverrides[field.key]
+		if !ok {
+			continue
+		}
+		if err := setField(reflect.ValueOf(c).Elem().FieldByIndex(field.index), override.value); err != nil {
+			return fmt.Errorf("%s: %w", override.flag, err)
+		}
+		if c.sources == nil {
+			c.sources = make(map[string]string)
+		}
+		c.sources[field.key] = override.source
+	}
+	return nil
+}
+
+// setField parses value into a scalar field
+func setField(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean '%s'", value)
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer '%s'", value)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number '%s'", value)
+		}
+		v.SetFloat(f)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// execEnvName matches an exec_env variable name; a trailing * matches any
+// suffix, as in ssh's SendEnv
+var execEnvName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\*?$`)
+
+// SplitExecEnv splits an exec_env entry, which is either a host variable to
+// pass through (LANG, or a pattern such as LC_*) or NAME=value to set a fixed value
+func SplitExecEnv(entry string) (name, value string, fixed bool) {
+	return strings.Cut(entry, "=")
+}
+
+// validateExecEnv checks exec_env entries
+func validateExecEnv(entries []string) error {
+	for _, entry := range entries {
+		name, _, fixed := SplitExecEnv(entry)
+		if !execEnvName.MatchString(name) || fixed && strings.HasSuffix(name, "*") {
+			return fmt.Errorf("invalid entry '%s' (use a name such as LANG, a pattern such as LC_*, or NAME=value)", entry)
+		}
+	}
+	return nil
+}
+
+// ExecEnvNames returns the variable names exec_env lets into containers
+func ExecEnvNames(entries []string) []string {
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name, _, _ := SplitExecEnv(entry)
+		names = append(names, name)
+	}
+	return names
+}
+
+// ResolveExecEnv returns the NAME=value pairs exec_env passes from environ
+// (as from os.Environ) and the fixed values it sets
+func ResolveExecEnv(entries, environ []string) []string {
+	var pairs []string
+	for _, entry := range entries {
+		name, value, fixed := SplitExecEnv(entry)
+		if fixed {
+			pairs = append(pairs, name+"="+value)
+			continue
+		}
+		prefix, pattern := strings.CutSuffix(name, "*")
+		for _, kv := range environ {
+			key, _, _ := strings.Cut(kv, "=")
+			if key == name || pattern && strings.HasPrefix(key, prefix) {
+				pairs = append(pairs, kv)
+			}
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateExecEnv(t *testing.T) {
+	assert.NoError(t, validateExecEnv(nil))
+	assert.NoError(t, validateExecEnv([]string{"LANG", "LC_*", "HTTP_PROXY", "EDITOR=vim", "EMPTY="}))
+
+	for _, entry := range []string{"", "2FAST", "MY-VAR", "*", "LC_*=C", "A*B"} {
+		assert.Error(t, validateExecEnv([]string{entry}), entry)
+	}
+}
+
+func TestResolveExecEnv(t *testing.T) {
+	environ := []string{"LANG=en_US.UTF-8", "LC_ALL=C", "LC_TIME=en_GB", "HOME=/Users/dev", "LANGUAGE=en"}
+
+	assert.Equal(t,
+		[]string{"LANG=en_US.UTF-8", "LC_ALL=C", "LC_TIME=en_GB", "EDITOR=vim"},
+		ResolveExecEnv([]string{"LANG", "LC_*", "HTTP_PROXY", "EDITOR=vim"}, environ))
+	assert.Empty(t, ResolveExecEnv(nil, environ))
+
+	assert.Equal(t, []string{"LANG", "LC_*", "EDITOR"}, ExecEnvNames([]string{"LANG", "LC_*", "EDITOR=vim"}))
+}
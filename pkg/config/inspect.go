@@ -0,0 +1,269 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SourceDefault marks settings that come from DefaultConfig
+const SourceDefault = "default"
+
+// secretKeys are masked when settings are displayed
+var secretKeys = map[string]bool{
+	"github_token": true,
+	"serve.token":  true,
+
+	"serve.slack.signing_secret": true,
+}
+
+// IsSecret reports whether the value of key should not be displayed
+func IsSecret(key string) bool {
+	return secretKeys[key]
+}
+
+// Setting is one flattened configuration value and where it came from
+type Setting struct {
+	Key    string // Dotted path, e.g. connections.home.address
+	Value  string
+	Source string // SourceDefault or the file that set it
+}
+
+// Problem is an issue found while checking configuration data
+type Problem struct {
+	Line    int // 0 when not tied to a line
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Line > 0 {
+		return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+	}
+	return p.Message
+}
+
+// Check strictly parses configuration data, upgraded to the current format,
+// reporting unknown keys (with a suggestion for likely typos) followed by any
+// validation failure
+func Check(data []byte) []Problem {
+	// Only check the upgraded document when migrations changed it, so line
+	// numbers match the file otherwise
+	upgraded, migration, err := Upgrade(data)
+	if err != nil {
+		return []Problem{{Message: err.Error()}}
+	}
+	if migration != nil && len(migration.Changes) > 0 {
+		data = upgraded
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return []Problem{{Message: err.Error()}}
+	}
+
+	var problems []Problem
+	if len(root.Content) > 0 {
+		problems = unknownKeys(root.Content[0], reflect.TypeOf(Config{}), "")
+	}
+
+	cfg := DefaultConfig()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return append(problems, Problem{Message: err.Error()})
+	}
+	if err := cfg.decryptSecrets(); err != nil {
+		return append(problems, Problem{Message: err.Error()})
+	}
+	if err := cfg.Validate(); err != nil {
+		problems = append(problems, Problem{Message: err.Error()})
+	}
+	return problems
+}
+
+// Settings flattens the configuration into dotted keys in file order,
+// recording where each value came from. Secrets and values that were
+// stored encrypted are masked.
+func (c *Config) Settings() ([]Setting, error) {
+	var node yaml.Node
+	if err := node.Encode(c); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	var settings []Setting
+	flatten(&node, "", func(key, value string) {
+		source := c.Source(key)
+		if (IsSecret(key) || c.encrypted[key]) && value != "" {
+			value = "********"
+		}
+		settings = append(settings, Setting{Key: key, Value: value, Source: source})
+	})
+	return settings, nil
+}
+
+// recordSources notes source as the origin of every value set in data
+func (c *Config) recordSources(data []byte, source string) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return
+	}
+	if c.sources == nil {
+		c.sources = make(map[string]string)
+	}
+	flatten(&node, "", func(key, _ string) {
+		c.sources[key] = source
+	})
+}
+
+// flatten walks a YAML tree calling fn for each leaf with its dotted key.
+// Lists of scalars are reported as a single comma separated value.
+func flatten(node *yaml.Node, prefix string, fn func(key, value string)) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			flatten(child, prefix, fn)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			flatten(node.Content[i+1], joinKey(prefix, node.Content[i].Value), fn)
+		}
+	case yaml.SequenceNode:
+		var scalars []string
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				scalars = nil
+				break
+			}
+			scalars = append(scalars, item.Value)
+		}
+		if scalars != nil {
+			fn(prefix, strings.Join(scalars, ", "))
+			return
+		}
+		for i, item := range node.Content {
+			flatten(item, fmt.Sprintf("%s[%d]", prefix, i), fn)
+		}
+	case yaml.ScalarNode:
+		fn(prefix, node.Value)
+	case yaml.AliasNode:
+		flatten(node.Alias, prefix, fn)
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// unknownKeys reports mapping keys that do not correspond to a field of t
+func unknownKeys(node *yaml.Node, t reflect.Type, prefix string) []Problem {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var problems []Problem
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fieldType, ok := fields[key.Value]
+			if !ok {
+				message := fmt.Sprintf("unknown key '%s'", joinKey(prefix, key.Value))
+				if suggestion := closestKey(key.Value, fields); suggestion != "" {
+					message += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+				}
+				problems = append(problems, Problem{Line: key.Line, Message: message})
+				continue
+			}
+			problems = append(problems, unknownKeys(value, fieldType, joinKey(prefix, key.Value))...)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			problems = append(problems, unknownKeys(node.Content[i+1], t.Elem(), joinKey(prefix, node.Content[i].Value))...)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return nil
+		}
+		for i, item := range node.Content {
+			problems = append(problems, unknownKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", prefix, i))...)
+		}
+	}
+	return problems
+}
+
+// yamlName returns the YAML key of a struct field, or false if the field is
+// not serialized
+func yamlName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return name, true
+}
+
+// yamlFields maps the YAML key of each serialized field of t to its type
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		if name, ok := yamlName(t.Field(i)); ok {
+			fields[name] = t.Field(i).Type
+		}
+	}
+	return fields
+}
+
+// closestKey returns the known key nearest to key if it is a plausible typo
+func closestKey(key string, fields map[string]reflect.Type) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Allow roughly one edit per three characters, and at least two
+	best, bestDistance := "", max(2, len(key)/3)+1
+	for _, name := range names {
+		if d := editDistance(key, name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
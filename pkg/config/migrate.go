@@ -0,0 +1,194 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config format written by this l8s. Files with an
+// older (or missing) version are upgraded by the migrations below.
+const CurrentVersion = 1
+
+// migration upgrades a config document to version
+type migration struct {
+	version     int
+	description string
+	apply       func(root *yaml.Node) error
+}
+
+// migrations run in order on any file older than their version. Append new
+// ones here and bump CurrentVersion; never edit a released migration.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "move remote_host into the connections map",
+		apply:       migrateRemoteHost,
+	},
+}
+
+// Migration describes an upgrade applied to a config file
+type Migration struct {
+	From, To int
+	Changes  []string // Descriptions of migrations that changed the file
+	Backup   string   // Copy of the original file, empty when nothing was written
+}
+
+// Upgrade applies pending migrations to configuration data. It returns the
+// data unchanged when it is already current, and an error when it was written
+// by a newer l8s.
+func Upgrade(data []byte) ([]byte, *Migration, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return data, nil, nil
+	}
+	root := doc.Content[0]
+
+	version := 0
+	if node := mappingValue(root, "version"); node != nil {
+		v, err := strconv.Atoi(node.Value)
+		if err != nil || v < 0 {
+			return nil, nil, fmt.Errorf("invalid config version '%s'", node.Value)
+		}
+		version = v
+	}
+	if version > CurrentVersion {
+		return nil, nil, fmt.Errorf("config version %d is newer than this l8s supports (%d); upgrade l8s", version, CurrentVersion)
+	}
+	if version == CurrentVersion {
+		return data, nil, nil
+	}
+
+	result := &Migration{From: version, To: CurrentVersion}
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		before, _ := yaml.Marshal(&doc)
+		if err := m.apply(root); err != nil {
+			return nil, nil, fmt.Errorf("config migration to version %d failed: %w", m.version, err)
+		}
+		if after, _ := yaml.Marshal(&doc); string(after) != string(before) {
+			result.Changes = append(result.Changes, m.description)
+		}
+	}
+
+	// Keep the version first so it is the first thing seen in the file,
+	// below any comment heading it
+	removeKey(root, "version")
+	key := &yaml.Node{Kind: yaml.ScalarNode, Value: "version"}
+	if len(root.Content) > 0 {
+		key.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+	root.Content = append([]*yaml.Node{
+		key,
+		{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(CurrentVersion)},
+	}, root.Content...)
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return out, result, nil
+}
+
+// Migrate upgrades the config file at path in place, first copying the
+// original to <path>.v<version>.bak. It returns nil when the file is missing
+// or already current.
+func Migrate(path string) (*Migration, error) {
+	path = expandPath(path)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	upgraded, result, err := Upgrade(data)
+	if err != nil || result == nil {
+		return nil, err
+	}
+
+	result.Backup = fmt.Sprintf("%s.v%d.bak", path, result.From)
+	if err := os.WriteFile(result.Backup, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to back up config file: %w", err)
+	}
+	if err := WriteFile(path, upgraded); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// migrateRemoteHost converts the single-host layout (remote_host at the top
+// level) into a "default" connection
+func migrateRemoteHost(root *yaml.Node) error {
+	host := mappingValue(root, "remote_host")
+	if host == nil {
+		return nil
+	}
+	removeKey(root, "remote_host")
+	if host.Value == "" {
+		return nil
+	}
+
+	connections := mappingValue(root, "connections")
+	if connections == nil || connections.Tag == "!!null" {
+		removeKey(root, "connections")
+		connections = &yaml.Node{Kind: yaml.MappingNode}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "connections"}, connections)
+	}
+	if connections.Kind != yaml.MappingNode {
+		return fmt.Errorf("connections is not a mapping")
+	}
+	if mappingValue(connections, "default") != nil {
+		return fmt.Errorf("remote_host is set but a 'default' connection already exists")
+	}
+	connections.Content = append(connections.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: "default"},
+		&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "address"},
+			{Kind: yaml.ScalarNode, Value: host.Value, Style: host.Style},
+		}},
+	)
+
+	if active := mappingValue(root, "active_connection"); active == nil {
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "active_connection"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "default"})
+	} else if active.Value == "" {
+		active.Value = "default"
+	}
+	return nil
+}
+
+// mappingValue returns the value node for key in a mapping, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// removeKey deletes key and its value from a mapping. A comment above the
+// key moves to the next one so it is not lost.
+func removeKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			if comment := node.Content[i].HeadComment; comment != "" && i+2 < len(node.Content) {
+				next := node.Content[i+2]
+				next.HeadComment = strings.TrimSpace(comment + "\n" + next.HeadComment)
+			}
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
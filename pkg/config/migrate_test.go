@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestUpgrade(t *testing.T) {
+	legacy := `# my settings
+remote_host: "server.example.com" # the box under the desk
+remote_user: podman
+container_prefix: work
+`
+	data, migration, err := Upgrade([]byte(legacy))
+	require.NoError(t, err)
+	require.NotNil(t, migration)
+	assert.Equal(t, 0, migration.From)
+	assert.Equal(t, CurrentVersion, migration.To)
+	assert.Equal(t, []string{"move remote_host into the connections map"}, migration.Changes)
+
+	assert.Contains(t, string(data), "# my settings")
+	assert.NotContains(t, string(data), "remote_host")
+	assert.Empty(t, Check(data))
+
+	cfg := DefaultConfig()
+	require.NoError(t, yaml.Unmarshal(data, cfg))
+	assert.Equal(t, CurrentVersion, cfg.Version)
+	assert.Equal(t, "default", cfg.ActiveConnection)
+	assert.Equal(t, "server.example.com", cfg.Connections["default"].Address)
+	assert.Equal(t, "work", cfg.ContainerPrefix)
+
+	// Current files are left alone
+	again, migration, err := Upgrade(data)
+	require.NoError(t, err)
+	assert.Nil(t, migration)
+	assert.Equal(t, string(data), string(again))
+
+	// Files from a newer l8s are refused rather than misread
+	_, _, err = Upgrade([]byte("version: 99\n"))
+	assert.ErrorContains(t, err, "newer than this l8s supports")
+
+	// A legacy host cannot silently replace an existing default connection
+	_, _, err = Upgrade([]byte("remote_host: a\nconnections:\n  default:\n    address: b\n"))
+	assert.ErrorContains(t, err, "'default' connection already exists")
+}
+
+func TestMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	original := "remote_host: server.example.com\nremote_user: podman\n"
+	require.NoError(t, os.WriteFile(path, []byte(original), 0644))
+
+	migration, err := Migrate(path)
+	require.NoError(t, err)
+	require.NotNil(t, migration)
+	assert.Equal(t, path+".v0.bak", migration.Backup)
+
+	backup, err := os.ReadFile(migration.Backup)
+	require.NoError(t, err)
+	assert.Equal(t, original, string(backup))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "server.example.com", cfg.Connections["default"].Address)
+
+	// Nothing to do the second time, or without a config file
+	migration, err = Migrate(path)
+	require.NoError(t, err)
+	assert.Nil(t, migration)
+	migration, err = Migrate(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.NoError(t, err)
+	assert.Nil(t, migration)
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Origin credential modes for the mirrored origin remote
+const (
+	OriginAuthAgent = "agent" // SSH remotes, authenticated with the forwarded SSH agent
+	OriginAuthToken = "token" // HTTPS remotes, authenticated with github_token
+)
+
+// OriginConfig controls whether containers get an origin remote pointing at
+// the host repository's upstream, so tools in the container can fetch from it
+type OriginConfig struct {
+	Mirror bool   `yaml:"mirror,omitempty"` // Add origin to every new container
+	Auth   string `yaml:"auth,omitempty"`   // agent or token (default: agent for SSH URLs, token for HTTPS)
+}
+
+// validate checks the credential mode
+func (o OriginConfig) validate() error {
+	switch o.Auth {
+	case "", OriginAuthAgent, OriginAuthToken:
+		return nil
+	}
+	return fmt.Errorf("auth must be %s or %s", OriginAuthAgent, OriginAuthToken)
+}
+
+// AuthFor returns the credential mode for an origin URL
+func (o OriginConfig) AuthFor(url string) string {
+	if o.Auth != "" {
+		return o.Auth
+	}
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		return OriginAuthToken
+	}
+	return OriginAuthAgent
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOriginConfig(t *testing.T) {
+	var defaults OriginConfig
+	assert.NoError(t, defaults.validate())
+	assert.Equal(t, OriginAuthAgent, defaults.AuthFor("git@github.com:acme/api.git"))
+	assert.Equal(t, OriginAuthAgent, defaults.AuthFor("ssh://git@example.com/acme/api.git"))
+	assert.Equal(t, OriginAuthToken, defaults.AuthFor("https://github.com/acme/api.git"))
+
+	forced := OriginConfig{Mirror: true, Auth: OriginAuthToken}
+	assert.NoError(t, forced.validate())
+	assert.Equal(t, OriginAuthToken, forced.AuthFor("git@github.com:acme/api.git"))
+
+	assert.ErrorContains(t, OriginConfig{Auth: "password"}.validate(), "auth must be")
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// migratedMarker is left in the legacy directory once its contents are copied
+const migratedMarker = "MIGRATED"
+
+// xdgDir returns $env/l8s, or ~/fallback/l8s when env is unset or relative
+// (the XDG spec says relative paths must be ignored)
+func xdgDir(env, fallback string) string {
+	if dir := os.Getenv(env); dir != "" && filepath.IsAbs(dir) {
+		return filepath.Join(dir, "l8s")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, fallback, "l8s")
+}
+
+// ConfigDir holds config.yaml, the SSH CA, known_hosts and user dotfiles:
+// $XDG_CONFIG_HOME/l8s, defaulting to ~/.config/l8s
+func ConfigDir() string {
+	return xdgDir("XDG_CONFIG_HOME", ".config")
+}
+
+// CacheDir holds data that can be regenerated: $XDG_CACHE_HOME/l8s,
+// defaulting to ~/.cache/l8s
+func CacheDir() string {
+	return xdgDir("XDG_CACHE_HOME", ".cache")
+}
+
+// StateDir holds logs and history worth keeping across runs:
+// $XDG_STATE_HOME/l8s, defaulting to ~/.local/state/l8s
+func StateDir() string {
+	return xdgDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+// DataDir holds files l8s installs for the user, like shell plugins:
+// $XDG_DATA_HOME/l8s, defaulting to ~/.local/share/l8s
+func DataDir() string {
+	return xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+}
+
+// LegacyConfigDir is where l8s kept everything before honoring XDG variables
+func LegacyConfigDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "l8s")
+}
+
+// MigrateLegacy copies the legacy config directory to ConfigDir when
+// XDG_CONFIG_HOME points elsewhere and nothing is there yet. Paths in
+// config.yaml that pointed into the legacy directory are rewritten. The
+// legacy copy is kept (SSH config entries may still reference its
+// known_hosts) and marked so migration only ever happens once. It returns
+// the directory migrated from, or "" if nothing was done.
+func MigrateLegacy() (string, error) {
+	legacy, target := LegacyConfigDir(), ConfigDir()
+	if legacy == target {
+		return "", nil
+	}
+	if _, err := os.Stat(filepath.Join(legacy, "config.yaml")); err != nil {
+		return "", nil
+	}
+	if _, err := os.Stat(filepath.Join(legacy, migratedMarker)); err == nil {
+		return "", nil
+	}
+	if _, err := os.Stat(filepath.Join(target, "config.yaml")); err == nil {
+		return "", nil
+	}
+
+	if err := copyTree(legacy, target); err != nil {
+		return "", fmt.Errorf("failed to migrate %s to %s: %w", legacy, target, err)
+	}
+
+	configPath := filepath.Join(target, "config.yaml")
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read migrated config: %w", err)
+	}
+	data, err = rewritePaths(data, legacy, target)
+	if err != nil {
+		return "", err
+	}
+	if err := WriteFile(configPath, data); err != nil {
+		return "", err
+	}
+
+	note := fmt.Sprintf("l8s configuration moved to %s\n", target)
+	if err := os.WriteFile(filepath.Join(legacy, migratedMarker), []byte(note), 0644); err != nil {
+		return "", fmt.Errorf("failed to mark legacy config as migrated: %w", err)
+	}
+	return legacy, nil
+}
+
+// pathKeys are settings that may point into the config directory
+var pathKeys = []string{"ca_private_key_path", "ca_public_key_path", "known_hosts_path", "dotfiles_path"}
+
+// rewritePaths points path settings under from at the same file under to
+func rewritePaths(data []byte, from, to string) ([]byte, error) {
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse migrated config: %w", err)
+	}
+	values := map[string]string{
+		"ca_private_key_path": cfg.CAPrivateKeyPath,
+		"ca_public_key_path":  cfg.CAPublicKeyPath,
+		"known_hosts_path":    cfg.KnownHostsPath,
+		"dotfiles_path":       cfg.DotfilesPath,
+	}
+
+	for _, key := range pathKeys {
+		if values[key] == "" {
+			continue
+		}
+		rel, err := filepath.Rel(from, expandPath(values[key]))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if data, err = SetValue(data, key, filepath.Join(to, rel)); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// copyTree recursively copies src to dst preserving file modes. Files that
+// already exist in dst are left alone.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		// Never clobber files that already exist in the new location
+		out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+		if errors.Is(err, fs.ErrExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
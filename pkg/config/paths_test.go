@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXDGDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "relative/ignored")
+	t.Setenv("XDG_STATE_HOME", "/xdg/state")
+	t.Setenv("XDG_DATA_HOME", "")
+
+	assert.Equal(t, filepath.Join(home, ".config", "l8s"), ConfigDir())
+	assert.Equal(t, filepath.Join(home, ".cache", "l8s"), CacheDir())
+	assert.Equal(t, "/xdg/state/l8s", StateDir())
+	assert.Equal(t, filepath.Join(home, ".local", "share", "l8s"), DataDir())
+	assert.Equal(t, filepath.Join(home, ".config", "l8s", "config.yaml"), GetConfigPath())
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	xdg := filepath.Join(home, "xdg")
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	legacy := filepath.Join(home, ".config", "l8s")
+	require.NoError(t, os.MkdirAll(filepath.Join(legacy, "ca"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, "ca", "ca_key"), []byte("key"), 0600))
+	config := "remote_user: podman\n" +
+		"ca_private_key_path: " + filepath.Join(legacy, "ca", "ca_key") + "\n" +
+		"known_hosts_path: ~/.config/l8s/known_hosts\n" +
+		"dotfiles_path: /elsewhere/dotfiles\n"
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, "config.yaml"), []byte(config), 0644))
+
+	from, err := MigrateLegacy()
+	require.NoError(t, err)
+	assert.Equal(t, legacy, from)
+
+	target := filepath.Join(xdg, "l8s")
+	info, err := os.Stat(filepath.Join(target, "ca", "ca_key"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	data, err := os.ReadFile(filepath.Join(target, "config.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "ca_private_key_path: "+filepath.Join(target, "ca", "ca_key"))
+	assert.Contains(t, string(data), "known_hosts_path: "+filepath.Join(target, "known_hosts"))
+	assert.Contains(t, string(data), "dotfiles_path: /elsewhere/dotfiles")
+
+	// Legacy files stay behind, marked so migration is not repeated
+	assert.FileExists(t, filepath.Join(legacy, "config.yaml"))
+	assert.FileExists(t, filepath.Join(legacy, migratedMarker))
+	from, err = MigrateLegacy()
+	require.NoError(t, err)
+	assert.Empty(t, from)
+}
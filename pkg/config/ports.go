@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultPortRange is how many ports are allocated from ssh_port_start and
+// web_port_start when port_range is not set
+const DefaultPortRange = 1000
+
+// PortRange is an inclusive range of host ports
+type PortRange struct {
+	First int
+	Last  int
+}
+
+func (r PortRange) String() string {
+	return fmt.Sprintf("%d-%d", r.First, r.Last)
+}
+
+// Size is the number of ports in the range
+func (r PortRange) Size() int {
+	return r.Last - r.First + 1
+}
+
+// ParsePortRange parses a range such as "2200-2299", or a single port
+func ParsePortRange(s string) (PortRange, error) {
+	first, last, isRange := strings.Cut(strings.TrimSpace(s), "-")
+	var r PortRange
+	var err error
+	if r.First, err = strconv.Atoi(strings.TrimSpace(first)); err != nil {
+		return PortRange{}, fmt.Errorf("invalid port range '%s': expected FIRST-LAST, e.g. 2200-2299", s)
+	}
+	r.Last = r.First
+	if isRange {
+		if r.Last, err = strconv.Atoi(strings.TrimSpace(last)); err != nil {
+			return PortRange{}, fmt.Errorf("invalid port range '%s': expected FIRST-LAST, e.g. 2200-2299", s)
+		}
+	}
+	if r.First < 1024 || r.Last > 65535 {
+		return PortRange{}, fmt.Errorf("port range '%s' must be within 1024-65535", s)
+	}
+	if r.First > r.Last {
+		return PortRange{}, fmt.Errorf("port range '%s' ends before it starts", s)
+	}
+	return r, nil
+}
+
+// validatePortRanges checks that every range parses and none overlap
+func validatePortRanges(specs []string) error {
+	var ranges []PortRange
+	for _, spec := range specs {
+		r, err := ParsePortRange(spec)
+		if err != nil {
+			return err
+		}
+		for _, other := range ranges {
+			if r.First <= other.Last && other.First <= r.Last {
+				return fmt.Errorf("port ranges %s and %s overlap", other, r)
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return nil
+}
+
+// SSHRanges returns the ranges containers get SSH ports from, in the
+// order they are used: ssh_port_ranges when set, otherwise port_range ports
+// from ssh_port_start, stopping short of web_port_start when that follows.
+// Validate must have passed.
+func (c *Config) SSHRanges() []PortRange {
+	if len(c.SSHPortRanges) > 0 {
+		ranges := make([]PortRange, 0, len(c.SSHPortRanges))
+		for _, spec := range c.SSHPortRanges {
+			if r, err := ParsePortRange(spec); err == nil {
+				ranges = append(ranges, r)
+			}
+		}
+		return ranges
+	}
+	return []PortRange{portRange(c.SSHPortStart, []int{c.WebPortStart}, c.PortRange)}
+}
+
+// FirstSSHPort is where SSH port allocation starts
+func (c *Config) FirstSSHPort() int {
+	return c.SSHRanges()[0].First
+}
+
+// WebPortRange returns the range containers get web ports from. A container's
+// web port is as far into this range as its SSH port is into the SSH ranges,
+// so it holds at least as many ports, stopping short of any SSH range that
+// follows web_port_start.
+func (c *Config) WebPortRange() PortRange {
+	size := c.PortRange
+	if size <= 0 {
+		size = DefaultPortRange
+	}
+	ssh := c.SSHRanges()
+	starts := make([]int, 0, len(ssh))
+	total := 0
+	for _, r := range ssh {
+		total += r.Size()
+		starts = append(starts, r.First)
+	}
+	return portRange(c.WebPortStart, starts, max(size, total))
+}
+
+// LimitPortRanges returns the first n ports of ranges, e.g. to apply a port
+// budget; n <= 0 returns ranges unchanged
+func LimitPortRanges(ranges []PortRange, n int) []PortRange {
+	if n <= 0 {
+		return ranges
+	}
+	var limited []PortRange
+	for _, r := range ranges {
+		if n <= 0 {
+			break
+		}
+		if r.Size() > n {
+			r.Last = r.First + n - 1
+		}
+		limited = append(limited, r)
+		n -= r.Size()
+	}
+	return limited
+}
+
+// portRange returns the range of size ports from start, ending before the
+// first of next that follows start
+func portRange(start int, next []int, size int) PortRange {
+	if size <= 0 {
+		size = DefaultPortRange
+	}
+	end := start + size - 1
+	for _, n := range next {
+		if n > start && n <= end {
+			end = n - 1
+		}
+	}
+	return PortRange{First: start, Last: min(end, 65535)}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePortRange(t *testing.T) {
+	r, err := ParsePortRange("2200-2299")
+	require.NoError(t, err)
+	assert.Equal(t, PortRange{First: 2200, Last: 2299}, r)
+	assert.Equal(t, 100, r.Size())
+
+	r, err = ParsePortRange(" 4400 ")
+	require.NoError(t, err)
+	assert.Equal(t, PortRange{First: 4400, Last: 4400}, r)
+
+	for _, spec := range []string{"", "22-80", "2299-2200", "2200-70000", "ssh"} {
+		_, err := ParsePortRange(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestValidatePortRanges(t *testing.T) {
+	assert.NoError(t, validatePortRanges([]string{"2200-2299", "4400-4499"}))
+	assert.ErrorContains(t, validatePortRanges([]string{"2200-2299", "2250-2350"}), "overlap")
+}
+
+func TestSSHRanges(t *testing.T) {
+	cfg := &Config{SSHPortStart: 2200, WebPortStart: 3000}
+	assert.Equal(t, []PortRange{{2200, 2999}}, cfg.SSHRanges(), "stops short of the web ports")
+	assert.Equal(t, PortRange{3000, 3999}, cfg.WebPortRange())
+
+	cfg.PortRange = 100
+	assert.Equal(t, []PortRange{{2200, 2299}}, cfg.SSHRanges())
+
+	cfg = &Config{SSHPortStart: 65000, WebPortStart: 3000}
+	assert.Equal(t, 65535, cfg.SSHRanges()[0].Last)
+
+	cfg = &Config{
+		SSHPortStart:  2200,
+		WebPortStart:  3000,
+		PortRange:     100,
+		SSHPortRanges: []string{"2200-2299", "4400-4499"},
+	}
+	assert.Equal(t, []PortRange{{2200, 2299}, {4400, 4499}}, cfg.SSHRanges())
+	assert.Equal(t, 2200, cfg.FirstSSHPort())
+	assert.Equal(t, PortRange{3000, 3199}, cfg.WebPortRange(), "a web port for every SSH port")
+}
+
+func TestLimitPortRanges(t *testing.T) {
+	ranges := []PortRange{{2200, 2299}, {4400, 4499}}
+	assert.Equal(t, ranges, LimitPortRanges(ranges, 0))
+	assert.Equal(t, []PortRange{{2200, 2209}}, LimitPortRanges(ranges, 10))
+	assert.Equal(t, []PortRange{{2200, 2299}, {4400, 4409}}, LimitPortRanges(ranges, 110))
+}
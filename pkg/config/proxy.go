@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// defaultNoProxy keeps traffic to the container itself off the proxy
+const defaultNoProxy = "localhost,127.0.0.1,::1"
+
+// ProxyConfig sets the HTTP proxy used inside new containers. Unset values
+// are taken from the host's HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+type ProxyConfig struct {
+	Disabled bool   `yaml:"disabled,omitempty"` // Configure no proxy, even when the host has one
+	HTTP     string `yaml:"http,omitempty"`     // e.g. http://proxy.corp.example:3128
+	HTTPS    string `yaml:"https,omitempty"`    // Proxy for https URLs (default: http)
+	NoProxy  string `yaml:"no_proxy,omitempty"` // Comma-separated hosts and domains to reach directly
+}
+
+// validate checks the proxies are URLs with a host
+func (p ProxyConfig) validate() error {
+	for key, value := range map[string]string{"http": p.HTTP, "https": p.HTTPS} {
+		if value == "" {
+			continue
+		}
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%s: '%s' is not a proxy URL such as http://proxy:3128", key, value)
+		}
+	}
+	return nil
+}
+
+// Resolve fills unset values from the host's environment, read through
+// getenv; no proxy at all is returned as the zero value. Host proxies on a
+// loopback address are ignored: in the container they would point at the
+// container itself.
+func (p ProxyConfig) Resolve(getenv func(string) string) ProxyConfig {
+	if p.Disabled {
+		return ProxyConfig{}
+	}
+	hostProxy := func(name string) string {
+		value := getenv(name)
+		if value == "" {
+			value = getenv(strings.ToLower(name))
+		}
+		if isLoopbackProxy(value) {
+			return ""
+		}
+		return value
+	}
+
+	resolved := ProxyConfig{HTTP: p.HTTP, HTTPS: p.HTTPS, NoProxy: p.NoProxy}
+	if resolved.HTTP == "" {
+		resolved.HTTP = hostProxy("HTTP_PROXY")
+	}
+	if resolved.HTTPS == "" {
+		resolved.HTTPS = hostProxy("HTTPS_PROXY")
+	}
+	if resolved.HTTPS == "" {
+		resolved.HTTPS = resolved.HTTP
+	}
+	if resolved.HTTP == "" && resolved.HTTPS == "" {
+		return ProxyConfig{}
+	}
+	if resolved.NoProxy == "" {
+		resolved.NoProxy = getenv("NO_PROXY")
+	}
+	if resolved.NoProxy == "" {
+		resolved.NoProxy = getenv("no_proxy")
+	}
+	if resolved.NoProxy == "" {
+		resolved.NoProxy = defaultNoProxy
+	}
+	return resolved
+}
+
+// isLoopbackProxy reports whether a proxy URL points at this machine
+func isLoopbackProxy(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		// Proxies are often given without a scheme, e.g. localhost:3128
+		u, err = url.Parse("http://" + value)
+		if err != nil {
+			return false
+		}
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyConfig_Resolve(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+
+	t.Run("host environment", func(t *testing.T) {
+		resolved := ProxyConfig{}.Resolve(env(map[string]string{
+			"https_proxy": "http://proxy.corp:3128",
+			"NO_PROXY":    ".corp,10.0.0.0/8",
+		}))
+		assert.Equal(t, ProxyConfig{HTTPS: "http://proxy.corp:3128", NoProxy: ".corp,10.0.0.0/8"}, resolved)
+	})
+
+	t.Run("config wins and https defaults to http", func(t *testing.T) {
+		resolved := ProxyConfig{HTTP: "http://configured:8080"}.Resolve(env(map[string]string{
+			"HTTP_PROXY": "http://host:3128",
+		}))
+		assert.Equal(t, ProxyConfig{HTTP: "http://configured:8080", HTTPS: "http://configured:8080", NoProxy: defaultNoProxy}, resolved)
+	})
+
+	t.Run("loopback host proxies are ignored", func(t *testing.T) {
+		for _, proxy := range []string{"http://127.0.0.1:3128", "localhost:8888", "http://[::1]:3128"} {
+			assert.Equal(t, ProxyConfig{}, ProxyConfig{}.Resolve(env(map[string]string{"HTTP_PROXY": proxy})), proxy)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		resolved := ProxyConfig{Disabled: true, HTTP: "http://configured:8080"}.Resolve(env(map[string]string{
+			"HTTP_PROXY": "http://host:3128",
+		}))
+		assert.Equal(t, ProxyConfig{}, resolved)
+	})
+}
+
+func TestProxyConfig_Validate(t *testing.T) {
+	assert.NoError(t, ProxyConfig{HTTP: "http://proxy:3128", HTTPS: "https://user:pw@proxy.corp"}.validate())
+	assert.Error(t, ProxyConfig{HTTP: "proxy:3128"}.validate())
+	assert.Error(t, ProxyConfig{HTTPS: "not a url"}.validate())
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DefaultProtectedBranches are refused by the container's pre-push hook
+// unless push_guard.protected says otherwise
+var DefaultProtectedBranches = []string{"main", "master"}
+
+// branchGlob matches branch names and shell globs safe to embed in the hook
+var branchGlob = regexp.MustCompile(`^[A-Za-z0-9._/*-]+$`)
+
+// PushGuardConfig controls the pre-push hook installed in container
+// repositories, which keeps tools running there from pushing to production
+// branches or upstream. 'L8S_ALLOW_PUSH=1 git push' overrides it.
+type PushGuardConfig struct {
+	Disabled    bool     `yaml:"disabled,omitempty"`     // Do not install the hook
+	Protected   []string `yaml:"protected,omitempty"`    // Branch globs no push may update (default main, master)
+	AllowOrigin bool     `yaml:"allow_origin,omitempty"` // Permit pushes to origin other than protected branches
+}
+
+// validate checks the branch globs can be used in the hook
+func (p PushGuardConfig) validate() error {
+	for _, branch := range p.Protected {
+		if !branchGlob.MatchString(branch) {
+			return fmt.Errorf("protected: '%s' is not a branch name or glob", branch)
+		}
+	}
+	return nil
+}
+
+// ProtectedBranches returns the branch globs the hook refuses
+func (p PushGuardConfig) ProtectedBranches() []string {
+	if len(p.Protected) == 0 {
+		return DefaultProtectedBranches
+	}
+	return p.Protected
+}
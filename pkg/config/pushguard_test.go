@@ -0,0 +1,19 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushGuardConfig(t *testing.T) {
+	var defaults PushGuardConfig
+	assert.NoError(t, defaults.validate())
+	assert.Equal(t, []string{"main", "master"}, defaults.ProtectedBranches())
+
+	custom := PushGuardConfig{Protected: []string{"main", "release/*"}}
+	assert.NoError(t, custom.validate())
+	assert.Equal(t, []string{"main", "release/*"}, custom.ProtectedBranches())
+
+	assert.ErrorContains(t, PushGuardConfig{Protected: []string{"main; rm -rf ~"}}.validate(), "not a branch name")
+}
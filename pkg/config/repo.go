@@ -0,0 +1,140 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RepoConfigFile is the per-repository settings file read from the repository root
+const RepoConfigFile = ".l8s.yaml"
+
+// RepoConfig holds settings a repository declares for its containers
+type RepoConfig struct {
+	// Host directories bind-mounted in addition to the global mounts
+	Mounts []MountConfig `yaml:"mounts,omitempty"`
+
+	// Git identity for commits made in the container, overriding the host's
+	Git GitIdentityConfig `yaml:"git,omitempty"`
+
+	// Packages installed after create and again after each rebuild
+	Packages PackagesConfig `yaml:"packages,omitempty"`
+
+	// Toolchain manager provisioned in the container and run against the
+	// repository after create: nix (flake.nix or shell.nix), devbox or mise
+	// (.tool-versions or .mise.toml; the default when either exists)
+	Toolchain string `yaml:"toolchain,omitempty"`
+}
+
+// Toolchain managers for .l8s.yaml's toolchain
+const (
+	ToolchainNix    = "nix"
+	ToolchainDevbox = "devbox"
+	ToolchainMise   = "mise" // Also installs the versions asdf's .tool-versions pins
+)
+
+// miseFiles pin tool versions for mise; .tool-versions is asdf's format
+var miseFiles = []string{".tool-versions", ".mise.toml", "mise.toml"}
+
+// validateToolchain checks the toolchain is one l8s can provision
+func validateToolchain(toolchain string) error {
+	switch toolchain {
+	case "", ToolchainNix, ToolchainDevbox, ToolchainMise:
+		return nil
+	}
+	return fmt.Errorf("toolchain must be %s, %s or %s", ToolchainNix, ToolchainDevbox, ToolchainMise)
+}
+
+// RepoToolchain returns the toolchain manager for a repository: the one
+// .l8s.yaml names, else mise when the repository pins tool versions for it
+func (rc *RepoConfig) RepoToolchain(repoRoot string) string {
+	if rc.Toolchain != "" {
+		return rc.Toolchain
+	}
+	for _, name := range miseFiles {
+		if _, err := os.Stat(filepath.Join(repoRoot, name)); err == nil {
+			return ToolchainMise
+		}
+	}
+	return ""
+}
+
+// packageSpec matches package names and version specs such as black==24.1,
+// typescript@5 or requests[socks]; whitespace and ';' separate them in labels
+var packageSpec = regexp.MustCompile(`^[A-Za-z0-9@._+:/=<>!~^\[\],*-]+$`)
+
+// PackagesConfig lists packages to install in containers, by installer.
+// System packages go to dnf or apt, whichever the image uses.
+type PackagesConfig struct {
+	Dnf   []string `yaml:"dnf,omitempty"`
+	Apt   []string `yaml:"apt,omitempty"`
+	Pip   []string `yaml:"pip,omitempty"`   // Installed with pip --user
+	Npm   []string `yaml:"npm,omitempty"`   // Installed globally under ~/.local
+	Cargo []string `yaml:"cargo,omitempty"` // Installed with cargo install
+}
+
+// validate checks every entry is a single package spec
+func (p PackagesConfig) validate() error {
+	for installer, specs := range map[string][]string{"dnf": p.Dnf, "apt": p.Apt, "pip": p.Pip, "npm": p.Npm, "cargo": p.Cargo} {
+		for _, spec := range specs {
+			if !packageSpec.MatchString(spec) {
+				return fmt.Errorf("packages.%s: '%s' is not a package name", installer, spec)
+			}
+		}
+	}
+	return nil
+}
+
+// GitIdentityConfig is a git user.name and user.email; either may be left
+// empty to keep the host's value
+type GitIdentityConfig struct {
+	Name  string `yaml:"name,omitempty"`
+	Email string `yaml:"email,omitempty"`
+}
+
+// validate checks the email looks like one
+func (g GitIdentityConfig) validate() error {
+	if g.Email != "" && (!strings.Contains(g.Email, "@") || strings.ContainsAny(g.Email, " <>")) {
+		return fmt.Errorf("git.email '%s' is not a valid email address", g.Email)
+	}
+	if strings.ContainsAny(g.Name, "<>\n") {
+		return fmt.Errorf("git.name cannot contain '<', '>' or newlines")
+	}
+	return nil
+}
+
+// LoadRepoConfig reads .l8s.yaml from a repository root. A missing file
+// yields an empty configuration.
+func LoadRepoConfig(repoRoot string) (*RepoConfig, error) {
+	path := filepath.Join(repoRoot, RepoConfigFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &RepoConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", RepoConfigFile, err)
+	}
+
+	var rc RepoConfig
+	if err := yaml.Unmarshal(data, &rc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", RepoConfigFile, err)
+	}
+	if err := ValidateMounts(rc.Mounts); err != nil {
+		return nil, fmt.Errorf("%s: %w", RepoConfigFile, err)
+	}
+	if err := rc.Git.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", RepoConfigFile, err)
+	}
+	if err := rc.Packages.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", RepoConfigFile, err)
+	}
+	if err := validateToolchain(rc.Toolchain); err != nil {
+		return nil, fmt.Errorf("%s: %w", RepoConfigFile, err)
+	}
+	return &rc, nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRepoConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	rc, err := LoadRepoConfig(dir)
+	require.NoError(t, err)
+	assert.Empty(t, rc.Mounts)
+
+	path := filepath.Join(dir, RepoConfigFile)
+	require.NoError(t, os.WriteFile(path, []byte("mounts:\n  - source: /srv/data\n    target: /data\n"), 0644))
+	rc, err = LoadRepoConfig(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []MountConfig{{Source: "/srv/data", Target: "/data"}}, rc.Mounts)
+
+	require.NoError(t, os.WriteFile(path, []byte("mounts:\n  - source: /srv/data\n    target: data\n"), 0644))
+	_, err = LoadRepoConfig(dir)
+	assert.ErrorContains(t, err, "absolute")
+}
+
+func TestLoadRepoConfigGitIdentity(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, RepoConfigFile)
+
+	require.NoError(t, os.WriteFile(path, []byte("git:\n  name: Jane Doe\n  email: jane@oss.example.org\n"), 0644))
+	rc, err := LoadRepoConfig(dir)
+	require.NoError(t, err)
+	assert.Equal(t, GitIdentityConfig{Name: "Jane Doe", Email: "jane@oss.example.org"}, rc.Git)
+
+	require.NoError(t, os.WriteFile(path, []byte("git:\n  email: jane\n"), 0644))
+	_, err = LoadRepoConfig(dir)
+	assert.ErrorContains(t, err, "not a valid email")
+
+	require.NoError(t, os.WriteFile(path, []byte("git:\n  name: Jane <jane@example.org>\n"), 0644))
+	_, err = LoadRepoConfig(dir)
+	assert.ErrorContains(t, err, "git.name")
+}
+
+func TestLoadRepoConfigPackages(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, RepoConfigFile)
+
+	require.NoError(t, os.WriteFile(path, []byte("packages:\n  dnf: [ripgrep, jq]\n  pip: [black==24.1]\n  npm: [typescript@5]\n"), 0644))
+	rc, err := LoadRepoConfig(dir)
+	require.NoError(t, err)
+	assert.Equal(t, PackagesConfig{Dnf: []string{"ripgrep", "jq"}, Pip: []string{"black==24.1"}, Npm: []string{"typescript@5"}}, rc.Packages)
+
+	require.NoError(t, os.WriteFile(path, []byte("packages:\n  apt: [\"jq; rm -rf /\"]\n"), 0644))
+	_, err = LoadRepoConfig(dir)
+	assert.ErrorContains(t, err, "packages.apt")
+}
+
+func TestValidateMounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		mounts  []MountConfig
+		wantErr string
+	}{
+		{name: "valid", mounts: []MountConfig{{Source: "/scratch", Target: "/scratch"}, {Source: "/srv/data", Target: "/data"}}},
+		{name: "read-only volume", mounts: []MountConfig{{Source: "models", Target: "/models", ReadOnly: true}}},
+		{name: "relative source", mounts: []MountConfig{{Source: "./data", Target: "/data"}}, wantErr: "volume name"},
+		{name: "relative target", mounts: []MountConfig{{Source: "/scratch", Target: "scratch"}}, wantErr: "absolute"},
+		{name: "colon in path", mounts: []MountConfig{{Source: "/a:b", Target: "/b"}}, wantErr: "cannot contain"},
+		{name: "hides workspace", mounts: []MountConfig{{Source: "/scratch", Target: "/workspace/"}}, wantErr: "hide"},
+		{name: "hides home", mounts: []MountConfig{{Source: "/scratch", Target: "/home/dev"}}, wantErr: "hide"},
+		{name: "under home is fine", mounts: []MountConfig{{Source: "/scratch", Target: "/home/dev/scratch"}}},
+		{name: "duplicate target", mounts: []MountConfig{{Source: "/a", Target: "/x"}, {Source: "/b", Target: "/x/"}}, wantErr: "twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMounts(tt.mounts)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadRepoConfigToolchain(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, RepoConfigFile)
+
+	require.NoError(t, os.WriteFile(path, []byte("toolchain: nix\n"), 0644))
+	rc, err := LoadRepoConfig(dir)
+	require.NoError(t, err)
+	assert.Equal(t, ToolchainNix, rc.Toolchain)
+
+	require.NoError(t, os.WriteFile(path, []byte("toolchain: conda\n"), 0644))
+	_, err = LoadRepoConfig(dir)
+	assert.ErrorContains(t, err, "toolchain must be")
+}
+
+func TestRepoToolchain(t *testing.T) {
+	dir := t.TempDir()
+	assert.Empty(t, (&RepoConfig{}).RepoToolchain(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".tool-versions"), []byte("nodejs 20.11.0\n"), 0644))
+	assert.Equal(t, ToolchainMise, (&RepoConfig{}).RepoToolchain(dir))
+	assert.Equal(t, ToolchainNix, (&RepoConfig{Toolchain: ToolchainNix}).RepoToolchain(dir))
+}
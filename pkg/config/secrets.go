@@ -0,0 +1,209 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"l8s/pkg/logging"
+)
+
+// Encrypted values are stored in place of plaintext in config.yaml:
+//
+//	github_token: "age:YWdlLWVuY3J5cHRpb24ub3JnL3YxCi0+..."  # base64 of age ciphertext
+//	github_token: "keychain:github_token"                   # OS keychain entry
+//
+// Any string setting may use either form; it is decrypted by Load.
+const (
+	AgePrefix      = "age:"
+	KeychainPrefix = "keychain:"
+
+	// KeychainService is the service name l8s entries are stored under
+	KeychainService = "l8s"
+)
+
+// AgeIdentityPath is the age identity used to decrypt "age:" values: the
+// age_identity setting, or age.key in ConfigDir. Keep it out of any dotfiles
+// repository that syncs config.yaml.
+func (c *Config) AgeIdentityPath() string {
+	if c.AgeIdentity != "" {
+		return expandPath(c.AgeIdentity)
+	}
+	return filepath.Join(ConfigDir(), "age.key")
+}
+
+// decrypters resolve encrypted values by prefix; replaced in tests
+var decrypters = map[string]func(c *Config, value string) (string, error){
+	AgePrefix:      decryptAge,
+	KeychainPrefix: func(_ *Config, account string) (string, error) { return keychainLookup(account) },
+}
+
+// decryptAge decrypts a base64 encoded age ciphertext with the age CLI
+func decryptAge(c *Config, value string) (string, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid age value: %w", err)
+	}
+	if _, err := exec.LookPath("age"); err != nil {
+		return "", fmt.Errorf("age not found; install it from https://age-encryption.org")
+	}
+
+	cmd := exec.Command("age", "--decrypt", "--identity", c.AgeIdentityPath())
+	cmd.Stdin = bytes.NewReader(ciphertext)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("age decryption failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// EncryptAge encrypts plaintext to the public key of the age identity and
+// returns the "age:" value to store in config.yaml
+func (c *Config) EncryptAge(plaintext string) (string, error) {
+	if _, err := exec.LookPath("age"); err != nil {
+		return "", fmt.Errorf("age not found; install it from https://age-encryption.org")
+	}
+
+	identity := c.AgeIdentityPath()
+	if _, err := os.Stat(identity); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(identity), 0700); err != nil {
+			return "", fmt.Errorf("failed to create identity directory: %w", err)
+		}
+		if out, err := exec.Command("age-keygen", "-o", identity).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to generate age identity: %s", strings.TrimSpace(string(out)))
+		}
+	}
+
+	recipient, err := exec.Command("age-keygen", "-y", identity).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read age identity %s: %w", identity, err)
+	}
+
+	cmd := exec.Command("age", "--encrypt", "--recipient", strings.TrimSpace(string(recipient)))
+	cmd.Stdin = strings.NewReader(plaintext)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("age encryption failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	return AgePrefix + base64.StdEncoding.EncodeToString(out), nil
+}
+
+// errKeychainUnsupported is returned on systems with neither the macOS
+// keychain nor a Secret Service
+var errKeychainUnsupported = fmt.Errorf("keychain: secrets are not supported on %s; use age: encryption instead", runtime.GOOS)
+
+// keychainLookup reads a secret from the macOS keychain or the Secret Service
+func keychainLookup(account string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", KeychainService, "-a", account, "-w")
+	case "windows":
+		return "", errKeychainUnsupported
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", KeychainService, "account", account)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("keychain entry '%s' not found: %w", account, err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// StoreKeychain saves a secret in the OS keychain and returns the
+// "keychain:" value to store in config.yaml. The secret is written to the
+// tool's stdin, never its arguments, which other users can see in ps.
+func StoreKeychain(account, secret string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// A trailing -w without a value makes security prompt for the
+		// password, then for it again
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", KeychainService, "-a", account, "-w")
+		cmd.Stdin = strings.NewReader(secret + "\n" + secret + "\n")
+	case "windows":
+		return "", errKeychainUnsupported
+	default:
+		cmd = exec.Command("secret-tool", "store", "--label", "l8s "+account, "service", KeychainService, "account", account)
+		cmd.Stdin = strings.NewReader(secret)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to store keychain entry: %s", strings.TrimSpace(string(out)))
+	}
+	return KeychainPrefix + account, nil
+}
+
+// decryptSecrets replaces every encrypted string setting with its plaintext
+// and remembers its key so Settings keeps it masked. Secret values are also
+// registered so trace logging redacts them.
+func (c *Config) decryptSecrets() error {
+	return walkStrings(reflect.ValueOf(c).Elem(), "", func(key string, v reflect.Value) error {
+		if IsSecret(key) {
+			logging.AddSecret(v.String())
+		}
+		for prefix, decrypt := range decrypters {
+			rest, ok := strings.CutPrefix(v.String(), prefix)
+			if !ok {
+				continue
+			}
+			plaintext, err := decrypt(c, rest)
+			if err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			v.SetString(plaintext)
+			if c.encrypted == nil {
+				c.encrypted = make(map[string]bool)
+			}
+			c.encrypted[key] = true
+			logging.AddSecret(plaintext)
+			break
+		}
+		return nil
+	})
+}
+
+// walkStrings calls fn with each settable string reachable from v
+func walkStrings(v reflect.Value, prefix string, fn func(key string, v reflect.Value) error) error {
+	switch v.Kind() {
+	case reflect.String:
+		return fn(prefix, v)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			name, ok := yamlName(v.Type().Field(i))
+			if !ok {
+				continue
+			}
+			if err := walkStrings(v.Field(i), joinKey(prefix, name), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := walkStrings(v.Index(i), fmt.Sprintf("%s[%d]", prefix, i), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// Map values are not addressable, so walk a copy and store it back
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			if err := walkStrings(elem, joinKey(prefix, fmt.Sprint(iter.Key())), fn); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+	return nil
+}
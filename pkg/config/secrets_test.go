@@ -0,0 +1,62 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecryptSecrets(t *testing.T) {
+	original := decrypters
+	t.Cleanup(func() { decrypters = original })
+	decrypters = map[string]func(*Config, string) (string, error){
+		AgePrefix: func(_ *Config, value string) (string, error) {
+			if value == "bad" {
+				return "", errors.New("no identity matched")
+			}
+			return "age-" + value, nil
+		},
+		KeychainPrefix: func(_ *Config, account string) (string, error) {
+			return "keychain-" + account, nil
+		},
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `active_connection: home
+connections:
+  home:
+    address: 10.0.0.1
+remote_user: podman
+github_token: keychain:github
+webhooks:
+  - url: https://hooks.example.com/x
+    headers:
+      Authorization: age:c2VjcmV0
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "keychain-github", cfg.GitHubToken)
+	assert.Equal(t, "age-c2VjcmV0", cfg.Webhooks[0].Headers["Authorization"])
+	assert.Equal(t, "10.0.0.1", cfg.Connections["home"].Address)
+
+	settings, err := cfg.Settings()
+	require.NoError(t, err)
+	for _, s := range settings {
+		if s.Key == "webhooks[0].headers.Authorization" {
+			assert.Equal(t, "********", s.Value)
+		}
+	}
+
+	bad := []byte("github_token: age:bad\n")
+	require.NoError(t, os.WriteFile(path, bad, 0644))
+	_, err = Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "github_token: no identity matched")
+}
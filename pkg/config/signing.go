@@ -0,0 +1,35 @@
+package config
+
+import "fmt"
+
+// Commit signing modes for containers
+const (
+	SigningAgent = "agent" // Sign with a key held by the SSH agent that 'l8s ssh' forwards
+	SigningKey   = "key"   // Install a dedicated signing key in each container
+)
+
+// SigningConfig makes commits and tags created in containers SSH-signed, so
+// work done by tools inside still shows as verified
+type SigningConfig struct {
+	Mode string `yaml:"mode,omitempty"` // agent or key; empty disables signing
+	Key  string `yaml:"key,omitempty"`  // agent: public key (default ssh_public_key); key: private key to install
+}
+
+// Enabled reports whether containers sign commits
+func (s SigningConfig) Enabled() bool {
+	return s.Mode != ""
+}
+
+// validate checks the mode and that key mode names a key to install
+func (s SigningConfig) validate() error {
+	switch s.Mode {
+	case "", SigningAgent:
+		return nil
+	case SigningKey:
+		if s.Key == "" {
+			return fmt.Errorf("key is required with mode %s", SigningKey)
+		}
+		return nil
+	}
+	return fmt.Errorf("mode must be %s or %s", SigningAgent, SigningKey)
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigningConfig(t *testing.T) {
+	var off SigningConfig
+	assert.False(t, off.Enabled())
+	assert.NoError(t, off.validate())
+
+	agent := SigningConfig{Mode: SigningAgent}
+	assert.True(t, agent.Enabled())
+	assert.NoError(t, agent.validate())
+
+	assert.NoError(t, SigningConfig{Mode: SigningKey, Key: "~/.ssh/l8s_signing"}.validate())
+	assert.ErrorContains(t, SigningConfig{Mode: SigningKey}.validate(), "key is required")
+	assert.ErrorContains(t, SigningConfig{Mode: "gpg"}.validate(), "mode must be")
+}
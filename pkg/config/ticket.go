@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultTicketPattern matches Jira-style IDs such as PROJ-123
+const DefaultTicketPattern = `^[A-Z][A-Z0-9]+-[0-9]+$`
+
+// DefaultTicketBranch names the branch after the ticket itself
+const DefaultTicketBranch = "{ticket}"
+
+// TicketConfig controls how 'l8s create --ticket' names containers and
+// branches. Templates substitute {ticket} with the ticket ID.
+type TicketConfig struct {
+	Pattern string `yaml:"pattern,omitempty"` // Regexp ticket IDs must match (default ^[A-Z][A-Z0-9]+-[0-9]+$)
+	Branch  string `yaml:"branch,omitempty"`  // Branch template, e.g. feature/{ticket} (default {ticket})
+	URL     string `yaml:"url,omitempty"`     // Link template, e.g. https://example.atlassian.net/browse/{ticket}
+}
+
+// validate checks the pattern compiles and the branch template uses the ticket
+func (t TicketConfig) validate() error {
+	if _, err := regexp.Compile(t.pattern()); err != nil {
+		return fmt.Errorf("pattern: %w", err)
+	}
+	if t.Branch != "" && !strings.Contains(t.Branch, "{ticket}") {
+		return fmt.Errorf("branch must contain {ticket}")
+	}
+	return nil
+}
+
+func (t TicketConfig) pattern() string {
+	if t.Pattern == "" {
+		return DefaultTicketPattern
+	}
+	return t.Pattern
+}
+
+// Check reports whether id is a valid ticket ID
+func (t TicketConfig) Check(id string) error {
+	re, err := regexp.Compile(t.pattern())
+	if err != nil {
+		return fmt.Errorf("invalid ticket.pattern: %w", err)
+	}
+	if !re.MatchString(id) {
+		return fmt.Errorf("'%s' is not a valid ticket ID (expected to match %s)", id, t.pattern())
+	}
+	return nil
+}
+
+// BranchFor returns the branch name for a ticket
+func (t TicketConfig) BranchFor(id string) string {
+	branch := t.Branch
+	if branch == "" {
+		branch = DefaultTicketBranch
+	}
+	return strings.ReplaceAll(branch, "{ticket}", id)
+}
+
+// LinkFor returns the ticket's URL, or "" when no url template is set
+func (t TicketConfig) LinkFor(id string) string {
+	if t.URL == "" {
+		return ""
+	}
+	return strings.ReplaceAll(t.URL, "{ticket}", id)
+}
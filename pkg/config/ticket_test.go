@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketConfig(t *testing.T) {
+	var defaults TicketConfig
+	assert.NoError(t, defaults.Check("JIRA-123"))
+	assert.ErrorContains(t, defaults.Check("jira-123"), "not a valid ticket ID")
+	assert.Equal(t, "JIRA-123", defaults.BranchFor("JIRA-123"))
+	assert.Empty(t, defaults.LinkFor("JIRA-123"))
+
+	custom := TicketConfig{
+		Pattern: `^#?[0-9]+$`,
+		Branch:  "feature/{ticket}",
+		URL:     "https://tracker.example.com/issues/{ticket}",
+	}
+	assert.NoError(t, custom.validate())
+	assert.NoError(t, custom.Check("482"))
+	assert.Equal(t, "feature/482", custom.BranchFor("482"))
+	assert.Equal(t, "https://tracker.example.com/issues/482", custom.LinkFor("482"))
+
+	assert.ErrorContains(t, TicketConfig{Pattern: "("}.validate(), "pattern")
+	assert.ErrorContains(t, TicketConfig{Branch: "feature/x"}.validate(), "{ticket}")
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// variantName matches image variant names, which become part of image tags
+var variantName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// ImageVariantConfig is an image built from the base Containerfile with a
+// fragment appended, e.g. for machine learning or web work
+type ImageVariantConfig struct {
+	Containerfile string `yaml:"containerfile"`   // Fragment appended to the base Containerfile
+	Image         string `yaml:"image,omitempty"` // Tag to build (default: base_image with -<variant> added)
+}
+
+// validateImageVariants checks variant names and that each has a fragment
+func validateImageVariants(variants map[string]ImageVariantConfig) error {
+	for _, name := range VariantNames(variants) {
+		if !variantName.MatchString(name) {
+			return fmt.Errorf("%s: variant names must consist of lowercase letters, numbers and hyphens", name)
+		}
+		if strings.TrimSpace(variants[name].Containerfile) == "" {
+			return fmt.Errorf("%s: containerfile is required", name)
+		}
+	}
+	return nil
+}
+
+// VariantNames returns the names of the configured image variants, sorted
+func VariantNames(variants map[string]ImageVariantConfig) []string {
+	names := make([]string, 0, len(variants))
+	for name := range variants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// VariantImage returns the image a variant is built as and created from.
+// Without an explicit image the variant name is added to base_image's
+// repository, so localhost/l8s-fedora:latest becomes localhost/l8s-fedora-ml:latest.
+func (c *Config) VariantImage(name string) string {
+	if image := c.ImageVariants[name].Image; image != "" {
+		return image
+	}
+	repo, tag := c.BaseImage, ""
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo, tag = repo[:i], repo[i:]
+	}
+	return repo + "-" + name + tag
+}
+
+// expandVariantPaths expands ~ in variant fragments and resolves relative
+// ones against dir, the directory of the config file
+func (c *Config) expandVariantPaths(dir string) {
+	for name, variant := range c.ImageVariants {
+		variant.Containerfile = expandPath(variant.Containerfile)
+		if !filepath.IsAbs(variant.Containerfile) {
+			variant.Containerfile = filepath.Join(dir, variant.Containerfile)
+		}
+		c.ImageVariants[name] = variant
+	}
+}
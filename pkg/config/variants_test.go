@@ -0,0 +1,47 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateImageVariants(t *testing.T) {
+	assert.NoError(t, validateImageVariants(nil))
+	assert.NoError(t, validateImageVariants(map[string]ImageVariantConfig{"ml": {Containerfile: "ml.Containerfile"}}))
+
+	err := validateImageVariants(map[string]ImageVariantConfig{"ML": {Containerfile: "ml.Containerfile"}})
+	assert.ErrorContains(t, err, "ML: variant names")
+	err = validateImageVariants(map[string]ImageVariantConfig{"web": {Image: "localhost/web:latest"}})
+	assert.ErrorContains(t, err, "web: containerfile is required")
+}
+
+func TestVariantImage(t *testing.T) {
+	cfg := &Config{
+		BaseImage: "localhost/l8s-fedora:latest",
+		ImageVariants: map[string]ImageVariantConfig{
+			"ml":  {Containerfile: "ml.Containerfile"},
+			"web": {Containerfile: "web.Containerfile", Image: "registry.local:5000/web:1"},
+		},
+	}
+	assert.Equal(t, "localhost/l8s-fedora-ml:latest", cfg.VariantImage("ml"))
+	assert.Equal(t, "registry.local:5000/web:1", cfg.VariantImage("web"))
+
+	cfg.BaseImage = "registry.local:5000/l8s"
+	assert.Equal(t, "registry.local:5000/l8s-ml", cfg.VariantImage("ml"))
+}
+
+func TestExpandVariantPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cfg := &Config{ImageVariants: map[string]ImageVariantConfig{
+		"abs":      {Containerfile: "/srv/abs.Containerfile"},
+		"home":     {Containerfile: "~/variants/home.Containerfile"},
+		"relative": {Containerfile: "relative.Containerfile"},
+	}}
+	cfg.expandVariantPaths("/etc/l8s")
+	assert.Equal(t, "/srv/abs.Containerfile", cfg.ImageVariants["abs"].Containerfile)
+	assert.Equal(t, filepath.Join(home, "variants", "home.Containerfile"), cfg.ImageVariants["home"].Containerfile)
+	assert.Equal(t, filepath.Join("/etc/l8s", "relative.Containerfile"), cfg.ImageVariants["relative"].Containerfile)
+}
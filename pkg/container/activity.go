@@ -0,0 +1,58 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// activityFile is written by the idle agent shipped in the image
+const activityFile = "/var/lib/l8s/activity"
+
+// Activity is the last recorded use of a container
+type Activity struct {
+	LastActive time.Time
+	Load       float64 // 1 minute load average
+	Sessions   int     // Open SSH sessions
+}
+
+// Idle returns how long the container has gone without interactive use
+func (a *Activity) Idle(now time.Time) time.Duration {
+	if a.Sessions > 0 && now.Sub(a.LastActive) < time.Minute {
+		return 0
+	}
+	return now.Sub(a.LastActive)
+}
+
+// parseActivity parses the "<epoch> <load> <sessions>" status line
+func parseActivity(line string) (*Activity, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("malformed activity record %q", strings.TrimSpace(line))
+	}
+	epoch, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("malformed activity timestamp: %w", err)
+	}
+	load, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return nil, fmt.Errorf("malformed load average: %w", err)
+	}
+	sessions, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed session count: %w", err)
+	}
+	return &Activity{LastActive: time.Unix(epoch, 0), Load: load, Sessions: sessions}, nil
+}
+
+// GetActivity reads the idle agent's status from a running container
+func (m *Manager) GetActivity(ctx context.Context, name string) (*Activity, error) {
+	containerName := m.config.ContainerPrefix + "-" + name
+	out, err := m.client.ExecContainerOutput(ctx, containerName, []string{"cat", activityFile})
+	if err != nil {
+		return nil, fmt.Errorf("no activity recorded (image may predate the idle agent): %w", err)
+	}
+	return parseActivity(out)
+}
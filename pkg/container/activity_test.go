@@ -0,0 +1,52 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseActivity(t *testing.T) {
+	a, err := parseActivity("1700000000 0.42 2\n")
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1700000000, 0), a.LastActive)
+	assert.Equal(t, 0.42, a.Load)
+	assert.Equal(t, 2, a.Sessions)
+
+	for _, bad := range []string{"", "1700000000 0.42", "soon 0.42 1", "1700000000 high 1", "1700000000 0.1 many"} {
+		_, err := parseActivity(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestActivityIdle(t *testing.T) {
+	now := time.Now()
+
+	idle := &Activity{LastActive: now.Add(-3 * time.Hour)}
+	assert.Equal(t, 3*time.Hour, idle.Idle(now))
+
+	active := &Activity{LastActive: now.Add(-30 * time.Second), Sessions: 1}
+	assert.Equal(t, time.Duration(0), active.Idle(now))
+}
+
+func TestManager_GetActivity(t *testing.T) {
+	m := new(MockPodmanClient)
+	m.On("ExecContainerOutput", mock.Anything, "dev-myproject", []string{"cat", activityFile}).
+		Return("1700000000 1.50 0\n", nil)
+	m.On("ExecContainerOutput", mock.Anything, "dev-old", []string{"cat", activityFile}).
+		Return("", errors.New("No such file or directory"))
+
+	manager := NewManager(m, Config{ContainerPrefix: "dev"})
+
+	a, err := manager.GetActivity(context.Background(), "myproject")
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, a.Load)
+
+	_, err = manager.GetActivity(context.Background(), "old")
+	assert.Error(t, err)
+}
@@ -0,0 +1,261 @@
+package container
+
+import (
+	"context"
+	"fmt"
+
+	"l8s/pkg/cleanup"
+	"l8s/pkg/logging"
+	"l8s/pkg/ssh"
+)
+
+// ForeignContainer describes any container on the host, including ones l8s
+// did not create
+type ForeignContainer struct {
+	Name    string
+	Image   string
+	Status  string
+	User    string  // User the container runs as, empty for the image default
+	SSHPort int     // Host port published for 22/tcp, 0 if none
+	WebPort int     // Host port published for 3000/tcp, 0 if none
+	Mounts  []Mount // Named volumes and bind mounts
+	Labels  map[string]string
+}
+
+// AdoptOptions controls how 'l8s adopt' takes over a container
+type AdoptOptions struct {
+	SSHPort int    // Host port for SSH; 0 keeps the published 22/tcp port or allocates one
+	SSHKey  string // Public key written to the container user's authorized_keys
+}
+
+// AdoptBackupName is the name the original container is kept under, stopped,
+// once it has been adopted
+func AdoptBackupName(source string) string {
+	return source + "-pre-l8s"
+}
+
+// adoptedImage is the image an adopted container's filesystem is committed to
+func adoptedImage(containerName string) string {
+	return "localhost/" + containerName + "-adopted:latest"
+}
+
+// AdoptContainer brings a container l8s did not create under its management.
+// Podman cannot relabel a container, so its filesystem is committed to an
+// image and a labelled l8s container is created from that image. Volumes
+// mounted at the user's home and /workspace are copied into the l8s volumes;
+// other volumes and bind mounts are mounted again as they were. The original
+// is stopped and kept as AdoptBackupName(source) with its volumes, and is put
+// back if anything fails.
+func (m *Manager) AdoptContainer(ctx context.Context, source, name string, opts AdoptOptions) (_ *Container, err error) {
+	cleaner := cleanup.New(m.logger)
+	defer cleaner.CleanupOnError(context.WithoutCancel(ctx), &err)
+
+	if err := validateContainerName(name); err != nil {
+		return nil, err
+	}
+	containerName := m.config.ContainerPrefix + "-" + name
+
+	foreign, err := m.client.DescribeContainer(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container %s: %w", source, err)
+	}
+	if foreign.Labels[LabelManaged] == "true" {
+		return nil, fmt.Errorf("container '%s' is already managed by l8s", source)
+	}
+	if containerName != source {
+		exists, err := m.client.ContainerExists(ctx, containerName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check container existence: %w", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("container '%s' already exists", name)
+		}
+	}
+
+	if err := m.checkQuota(ctx); err != nil {
+		return nil, err
+	}
+
+	// Keep the ports clients already use where there are any
+	sshPort := opts.SSHPort
+	if sshPort == 0 {
+		sshPort = foreign.SSHPort
+	}
+	if sshPort == 0 {
+		if sshPort, err = m.client.FindAvailablePort(m.config.SSHPortStart); err != nil {
+			return nil, fmt.Errorf("failed to find available SSH port: %w", err)
+		}
+		if err := m.checkPortBudget(sshPort); err != nil {
+			return nil, err
+		}
+	}
+	webPort := foreign.WebPort
+	if webPort == 0 {
+		if webPort, err = m.client.FindAvailablePort(m.config.WebPortStart + m.portOffset(sshPort)); err != nil {
+			return nil, fmt.Errorf("failed to find available web port: %w", err)
+		}
+	}
+
+	image := adoptedImage(containerName)
+	m.logger.Debug("committing container",
+		logging.WithField("container", source),
+		logging.WithField("image", image))
+	if err := m.client.CommitContainer(ctx, source, image); err != nil {
+		return nil, err
+	}
+
+	// l8s runs sshd from the image and connects as container_user
+	if _, err := m.client.RunImage(ctx, image, []string{"test", "-x", "/usr/sbin/sshd"}); err != nil {
+		return nil, fmt.Errorf("container %s has no SSH server at /usr/sbin/sshd; install openssh-server in it first", source)
+	}
+	if _, err := m.client.RunImage(ctx, image, []string{"id", "-u", m.config.ContainerUser}); err != nil {
+		hint := "create it or set container_user to the container's user"
+		if foreign.User != "" {
+			hint = fmt.Sprintf("it runs as '%s'; create the user or set container_user", foreign.User)
+		}
+		return nil, fmt.Errorf("container %s has no user '%s'; %s", source, m.config.ContainerUser, hint)
+	}
+
+	// Volumes copied below and those podman creates with the container are
+	// new; volumes that already existed are never removed
+	if existingVolumes, err := m.client.ListVolumes(ctx); err != nil {
+		m.logger.Warn("failed to list volumes, they are kept if adoption fails",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	} else {
+		cleaner.Add("remove_volumes", func(ctx context.Context) error {
+			return m.removeNewVolumes(ctx, containerName, existingVolumes)
+		})
+	}
+
+	// Volumes at home and /workspace become the container's l8s volumes
+	home := fmt.Sprintf("/home/%s", m.config.ContainerUser)
+	volumes := containerVolumes(containerName)
+	var mounts []Mount
+	for _, mount := range foreign.Mounts {
+		var dst string
+		switch mount.Target {
+		case home:
+			dst = volumes[0]
+		case "/workspace":
+			dst = volumes[1]
+		default:
+			mounts = append(mounts, mount)
+			continue
+		}
+		if !mount.IsVolume() {
+			return nil, fmt.Errorf("%s is bind mounted from %s; only named volumes can become l8s volumes", mount.Target, mount.Source)
+		}
+		if err := m.client.CopyVolume(ctx, mount.Source, dst); err != nil {
+			return nil, fmt.Errorf("failed to copy volume: %w", err)
+		}
+	}
+
+	// Free the name and ports, keeping the original to fall back on
+	backup := AdoptBackupName(source)
+	if err := m.client.StopContainer(ctx, source); err != nil {
+		m.logger.Debug("container stop failed (may already be stopped)",
+			logging.WithError(err))
+	}
+	if err := m.client.RenameContainer(ctx, source, backup); err != nil {
+		return nil, fmt.Errorf("failed to rename %s: %w", source, err)
+	}
+	cleaner.Add("restore_original", func(ctx context.Context) error {
+		if err := m.client.RenameContainer(ctx, backup, source); err != nil {
+			return err
+		}
+		if foreign.Status == "running" {
+			return m.client.StartContainer(ctx, source)
+		}
+		return nil
+	})
+
+	labels := map[string]string{
+		LabelManaged:      "true",
+		LabelSSHPort:      fmt.Sprintf("%d", sshPort),
+		LabelWebPort:      fmt.Sprintf("%d", webPort),
+		LabelAdoptedFrom:  source,
+		LabelAdoptedImage: image,
+	}
+	for k, v := range ownerLabels(opts.SSHKey) {
+		labels[k] = v
+	}
+	if len(mounts) > 0 {
+		labels[LabelMounts] = FormatMounts(mounts)
+	}
+
+	m.logger.Info("adopting container",
+		logging.WithField("source", source),
+		logging.WithField("container", containerName),
+		logging.WithField("ssh_port", sshPort),
+		logging.WithField("web_port", webPort))
+
+	config := ContainerConfig{
+		Name:          containerName,
+		SSHPort:       sshPort,
+		WebPort:       webPort,
+		SSHPublicKey:  opts.SSHKey,
+		BaseImage:     image,
+		ContainerUser: m.config.ContainerUser,
+		AudioEnabled:  m.config.AudioEnabled,
+		AudioPort:     m.config.AudioPort,
+		Labels:        labels,
+	}
+	m.applyResourceLimits(&config)
+	m.applyMounts(&config, CreateOptions{})
+	m.applyRuntimeOptions(&config, nil)
+	m.applyProxy(&config)
+
+	container, err := m.client.CreateContainer(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container: %w", err)
+	}
+	cleaner.Add("remove_container", func(ctx context.Context) error {
+		return m.client.RemoveContainer(ctx, containerName, false)
+	})
+
+	if err := m.setupSSHCertificatesBeforeStart(ctx, containerName); err != nil {
+		m.logger.Warn("failed to setup SSH certificates",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	if err := m.client.StartContainer(ctx, containerName); err != nil {
+		return nil, fmt.Errorf("failed to start container: %w", err)
+	}
+
+	if err := m.fixVolumeOwnership(ctx, containerName); err != nil {
+		m.logger.Warn("failed to fix volume ownership",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	if err := m.persistHostKeys(ctx, containerName); err != nil {
+		m.logger.Warn("failed to persist SSH host keys",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	if opts.SSHKey != "" {
+		if err := m.setupSSH(ctx, containerName, opts.SSHKey); err != nil {
+			return nil, fmt.Errorf("failed to setup SSH: %w", err)
+		}
+	}
+
+	cleaner.Add("remove_ssh_config", func(ctx context.Context) error {
+		return ssh.RemoveSSHConfig(name)
+	})
+	if err := ssh.AddSSHConfig(name, "", sshPort, m.config.ContainerUser); err != nil {
+		m.logger.Warn("failed to add SSH config entry",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	m.logger.Info("container adopted successfully",
+		logging.WithField("source", source),
+		logging.WithField("container", containerName))
+
+	container.SSHPort = sshPort
+	container.WebPort = webPort
+	return container, nil
+}
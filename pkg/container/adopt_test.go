@@ -0,0 +1,109 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_AdoptContainer(t *testing.T) {
+	foreign := &ForeignContainer{
+		Name:    "devbox",
+		Image:   "docker.io/library/ubuntu:24.04",
+		Status:  "running",
+		SSHPort: 2222,
+		Mounts: []Mount{
+			{Source: "devbox-code", Target: "/workspace"},
+			{Source: "/srv/datasets", Target: "/data", ReadOnly: true},
+		},
+	}
+	newManager := func(m *MockPodmanClient) *Manager {
+		return NewManager(m, Config{
+			ContainerPrefix: "dev",
+			SSHPortStart:    2200,
+			WebPortStart:    3000,
+			ContainerUser:   "dev",
+		})
+	}
+
+	t.Run("recreates the container from a committed image", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		m := new(MockPodmanClient)
+		m.On("DescribeContainer", mock.Anything, "devbox").Return(foreign, nil)
+		m.On("ContainerExists", mock.Anything, "dev-devbox").Return(false, nil)
+		m.On("FindAvailablePort", 3022).Return(3022, nil)
+		m.On("CommitContainer", mock.Anything, "devbox", "localhost/dev-devbox-adopted:latest").Return(nil)
+		m.On("RunImage", mock.Anything, "localhost/dev-devbox-adopted:latest", mock.Anything).Return("", nil)
+		m.On("ListVolumes", mock.Anything).Return([]string{"devbox-code"}, nil)
+		m.On("CopyVolume", mock.Anything, "devbox-code", "dev-devbox-workspace").Return(nil)
+		m.On("StopContainer", mock.Anything, "devbox").Return(nil)
+		m.On("RenameContainer", mock.Anything, "devbox", "devbox-pre-l8s").Return(nil)
+		m.On("CreateContainer", mock.Anything, mock.MatchedBy(func(config ContainerConfig) bool {
+			return config.Name == "dev-devbox" &&
+				config.BaseImage == "localhost/dev-devbox-adopted:latest" &&
+				config.SSHPort == 2222 &&
+				config.Labels[LabelManaged] == "true" &&
+				config.Labels[LabelAdoptedFrom] == "devbox" &&
+				len(config.Mounts) == 1 && config.Mounts[0].Target == "/data"
+		})).Return(&Container{Name: "dev-devbox"}, nil)
+		m.On("StartContainer", mock.Anything, "dev-devbox").Return(nil)
+		m.On("ExecContainer", mock.Anything, "dev-devbox", mock.Anything).Return(nil)
+		m.On("ExecContainerOutput", mock.Anything, "dev-devbox", mock.Anything).Return("", nil)
+		m.On("ExecContainerWithInput", mock.Anything, "dev-devbox", mock.Anything, mock.Anything).Return(nil)
+
+		cont, err := newManager(m).AdoptContainer(context.Background(), "devbox", "devbox", AdoptOptions{SSHKey: "ssh-ed25519 AAAA test"})
+		require.NoError(t, err)
+		assert.Equal(t, 2222, cont.SSHPort)
+		assert.Equal(t, 3022, cont.WebPort)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("puts the original back when creation fails", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("DescribeContainer", mock.Anything, "devbox").Return(foreign, nil)
+		m.On("ContainerExists", mock.Anything, "dev-devbox").Return(false, nil)
+		m.On("FindAvailablePort", 3022).Return(3022, nil)
+		m.On("CommitContainer", mock.Anything, "devbox", mock.Anything).Return(nil)
+		m.On("RunImage", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+		m.On("ListVolumes", mock.Anything).Return([]string{"devbox-code"}, nil).Once()
+		m.On("ListVolumes", mock.Anything).Return([]string{"devbox-code", "dev-devbox-workspace"}, nil).Once()
+		m.On("CopyVolume", mock.Anything, "devbox-code", "dev-devbox-workspace").Return(nil)
+		m.On("StopContainer", mock.Anything, "devbox").Return(nil)
+		m.On("RenameContainer", mock.Anything, "devbox", "devbox-pre-l8s").Return(nil)
+		m.On("CreateContainer", mock.Anything, mock.Anything).Return(nil, errors.New("port is already allocated"))
+		m.On("RenameContainer", mock.Anything, "devbox-pre-l8s", "devbox").Return(nil)
+		m.On("StartContainer", mock.Anything, "devbox").Return(nil)
+		m.On("RemoveVolume", mock.Anything, "dev-devbox-workspace").Return(nil)
+
+		_, err := newManager(m).AdoptContainer(context.Background(), "devbox", "devbox", AdoptOptions{})
+		assert.Error(t, err)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("refuses containers without sshd before touching them", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("DescribeContainer", mock.Anything, "devbox").Return(foreign, nil)
+		m.On("ContainerExists", mock.Anything, "dev-devbox").Return(false, nil)
+		m.On("FindAvailablePort", 3022).Return(3022, nil)
+		m.On("CommitContainer", mock.Anything, "devbox", mock.Anything).Return(nil)
+		m.On("RunImage", mock.Anything, mock.Anything, []string{"test", "-x", "/usr/sbin/sshd"}).
+			Return("", errors.New("exit status 1"))
+
+		_, err := newManager(m).AdoptContainer(context.Background(), "devbox", "devbox", AdoptOptions{})
+		assert.ErrorContains(t, err, "no SSH server")
+		m.AssertNotCalled(t, "StopContainer", mock.Anything, mock.Anything)
+	})
+
+	t.Run("refuses containers l8s already manages", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("DescribeContainer", mock.Anything, "dev-api").
+			Return(&ForeignContainer{Name: "dev-api", Labels: map[string]string{LabelManaged: "true"}}, nil)
+
+		_, err := newManager(m).AdoptContainer(context.Background(), "dev-api", "api", AdoptOptions{})
+		assert.ErrorContains(t, err, "already managed")
+	})
+}
@@ -0,0 +1,114 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"l8s/pkg/embed"
+)
+
+// AgentsDir holds one workspace per agent. It is on the workspace volume so
+// artifacts survive rebuilds.
+const AgentsDir = "/workspace/agents"
+
+// AgentSubdirs are created in every agent workspace: pasted clipboard
+// content, files the agent produces for the host, and throwaway work
+var AgentSubdirs = []string{"clipboard", "artifacts", "scratch"}
+
+var agentName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateAgentName checks an agent name is usable as a directory name
+func ValidateAgentName(name string) error {
+	if !agentName.MatchString(name) {
+		return fmt.Errorf("invalid agent name '%s': use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// AgentDir returns the workspace of an agent
+func AgentDir(name string) string {
+	return path.Join(AgentsDir, name)
+}
+
+// AgentClipboardLink is where 'l8s paste' and the agent's commands have always
+// found pasted content; it links to the workspace's clipboard directory
+func AgentClipboardLink(name string) string {
+	return fmt.Sprintf("/tmp/%s-clipboard", name)
+}
+
+// AgentInitScript creates an agent's workspace, run as the container user.
+// Content already pasted to the clipboard link is moved into the workspace.
+func AgentInitScript(name string) string {
+	dir := AgentDir(name)
+	dirs := make([]string, len(AgentSubdirs))
+	for i, sub := range AgentSubdirs {
+		dirs[i] = path.Join(dir, sub)
+	}
+	clipboard := path.Join(dir, "clipboard")
+	link := AgentClipboardLink(name)
+	return strings.Join([]string{
+		"mkdir -p " + strings.Join(dirs, " "),
+		fmt.Sprintf("if [ -d %s ] && [ ! -L %s ]; then cp -a %s/. %s/ && rm -rf %s; fi", link, link, link, clipboard, link),
+		fmt.Sprintf("ln -sfn %s %s", clipboard, link),
+	}, " && ")
+}
+
+// InstallAgentSettings copies an agent's settings directory, ~/.<agent>, from
+// the dotfiles the container was set up with. It reports false when the
+// dotfiles have no settings for the agent.
+func (m *Manager) InstallAgentSettings(ctx context.Context, name, agent string) (bool, error) {
+	containerName := m.config.ContainerPrefix + "-" + name
+	settingsDir := "." + agent
+
+	stagingDir, err := os.MkdirTemp("", "l8s-agent-settings-*")
+	if err != nil {
+		return false, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	var source fs.FS
+	if dotfilesPath, useEmbedded := m.getDotfilesPath(""); useEmbedded {
+		if source, err = embed.GetDotfilesFS(); err != nil {
+			return false, fmt.Errorf("failed to get embedded dotfiles: %w", err)
+		}
+	} else {
+		source = os.DirFS(dotfilesPath)
+	}
+	if info, err := fs.Stat(source, settingsDir); err != nil || !info.IsDir() {
+		return false, nil
+	}
+
+	err = fs.WalkDir(source, settingsDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(stagingDir, filepath.FromSlash(p))
+		if d.IsDir() {
+			return os.MkdirAll(dest, 0755)
+		}
+		data, err := fs.ReadFile(source, p)
+		if err != nil {
+			return err
+		}
+		// Embedded files lose their executable bit; hooks are shell scripts
+		mode := os.FileMode(0644)
+		if strings.HasSuffix(p, ".sh") {
+			mode = 0755
+		}
+		return os.WriteFile(dest, data, mode)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to stage %s settings: %w", agent, err)
+	}
+
+	if err := CopyDotfilesToContainer(ctx, m.client, containerName, stagingDir, m.config.ContainerUser); err != nil {
+		return false, fmt.Errorf("failed to install %s settings: %w", agent, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,54 @@
+package container
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"l8s/pkg/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAgentName(t *testing.T) {
+	assert.NoError(t, ValidateAgentName("claude"))
+	assert.NoError(t, ValidateAgentName("code-review_2"))
+	assert.Error(t, ValidateAgentName("../etc"))
+	assert.Error(t, ValidateAgentName("Claude"))
+	assert.Error(t, ValidateAgentName(""))
+}
+
+func TestAgentInitScript(t *testing.T) {
+	assert.Equal(t, "mkdir -p /workspace/agents/claude/clipboard /workspace/agents/claude/artifacts /workspace/agents/claude/scratch && "+
+		"if [ -d /tmp/claude-clipboard ] && [ ! -L /tmp/claude-clipboard ]; then "+
+		"cp -a /tmp/claude-clipboard/. /workspace/agents/claude/clipboard/ && rm -rf /tmp/claude-clipboard; fi && "+
+		"ln -sfn /workspace/agents/claude/clipboard /tmp/claude-clipboard", AgentInitScript("claude"))
+}
+
+func TestInstallAgentSettings(t *testing.T) {
+	dotfiles := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dotfiles, ".aider", "hooks"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dotfiles, ".aider", "settings.yml"), []byte("model: x\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dotfiles, ".aider", "hooks", "stop.sh"), []byte("#!/bin/sh\n"), 0644))
+
+	ctx := context.Background()
+	client := &MockPodmanClient{}
+	client.On("ExecContainer", ctx, "dev-app", mock.AnythingOfType("[]string")).Return(nil)
+	client.On("CopyToContainer", ctx, "dev-app", mock.AnythingOfType("string"), "/home/dev/.aider/settings.yml").Return(nil)
+	client.On("CopyToContainer", ctx, "dev-app", mock.AnythingOfType("string"), "/home/dev/.aider/hooks/stop.sh").Return(nil)
+
+	t.Setenv("L8S_DOTFILES", dotfiles)
+	m := &Manager{config: Config{ContainerPrefix: "dev", ContainerUser: "dev"}, client: client,
+		logger: logging.Default()}
+	installed, err := m.InstallAgentSettings(ctx, "app", "aider")
+	require.NoError(t, err)
+	assert.True(t, installed)
+	client.AssertExpectations(t)
+
+	installed, err = m.InstallAgentSettings(ctx, "app", "codex")
+	require.NoError(t, err)
+	assert.False(t, installed)
+}
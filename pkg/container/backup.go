@@ -0,0 +1,56 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"l8s/pkg/backup"
+	"l8s/pkg/logging"
+)
+
+// BackupContainer snapshots every volume of a container into store, tagging
+// each file with ts so volumes taken together can be pruned together
+func (m *Manager) BackupContainer(ctx context.Context, name string, store backup.Store, ts time.Time) ([]backup.Entry, error) {
+	containerName := m.config.ContainerPrefix + "-" + name
+
+	if _, err := m.client.GetContainerInfo(ctx, containerName); err != nil {
+		return nil, fmt.Errorf("failed to get container info: %w", err)
+	}
+
+	var entries []backup.Entry
+	for _, volume := range containerVolumes(containerName) {
+		volumeKind := strings.TrimPrefix(volume, containerName+"-")
+		path := backup.FileName(containerName, volumeKind, ts)
+
+		m.logger.Debug("backing up volume",
+			logging.WithField("volume", volume),
+			logging.WithField("destination", store.String()))
+
+		// Stream the export straight into the store without buffering on disk
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(m.client.ExportVolume(ctx, volume, pw))
+		}()
+
+		if err := store.Put(ctx, path, pr); err != nil {
+			pr.CloseWithError(err)
+			return entries, fmt.Errorf("failed to back up volume %s: %w", volume, err)
+		}
+
+		entries = append(entries, backup.Entry{
+			Container: containerName,
+			Volume:    volumeKind,
+			Timestamp: ts,
+			Path:      path,
+		})
+	}
+
+	m.logger.Info("container backed up",
+		logging.WithField("container", containerName),
+		logging.WithField("destination", store.String()))
+
+	return entries, nil
+}
@@ -0,0 +1,233 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"l8s/pkg/logging"
+	"l8s/pkg/ssh"
+)
+
+const (
+	// blueGreenSuffix is appended to the replacement container name until cutover
+	blueGreenSuffix = "-next"
+
+	// sshReadyTimeout bounds how long we wait for sshd in a new container
+	sshReadyTimeout = 30 * time.Second
+)
+
+// waitForSSHFunc allows tests to replace the SSH readiness probe
+var waitForSSHFunc = waitForSSH
+
+// waitForSSH polls host:port until an SSH banner is received or the timeout
+// expires. With a jump host, host:port is reached through it.
+func waitForSSH(ctx context.Context, jump, host string, port int, timeout time.Duration) error {
+	if host == "" {
+		host = "localhost"
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	deadline := time.Now().Add(timeout)
+
+	var lastErr error
+	for time.Now().Before(deadline) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if jump != "" {
+			if lastErr = readSSHBannerVia(ctx, jump, address); lastErr == nil {
+				return nil
+			}
+			time.Sleep(500 * time.Millisecond)
+			continue
+		}
+
+		conn, err := net.DialTimeout("tcp", address, 2*time.Second)
+		if err == nil {
+			_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			banner, readErr := bufio.NewReader(conn).ReadString('\n')
+			conn.Close()
+			if readErr == nil && strings.HasPrefix(banner, "SSH-") {
+				return nil
+			}
+			lastErr = fmt.Errorf("unexpected response from %s", address)
+		} else {
+			lastErr = err
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return fmt.Errorf("SSH not ready on %s after %s: %w", address, timeout, lastErr)
+}
+
+// BlueGreenRebuildContainer rebuilds a container without taking the old one down first.
+// The replacement is created alongside the original on new ports and sharing the same
+// volumes. Only after SSH is reachable and the optional health command succeeds is the
+// old container removed and the replacement renamed into place. On any failure before
+// cutover the replacement is discarded and the original container is left untouched.
+func (m *Manager) BlueGreenRebuildContainer(ctx context.Context, name, healthCmd string) error {
+	containerName := m.config.ContainerPrefix + "-" + name
+	nextName := containerName + blueGreenSuffix
+
+	// Step 1: Get current container configuration
+	containerInfo, err := m.client.GetContainerInfo(ctx, containerName)
+	if err != nil {
+		return fmt.Errorf("failed to get container info: %w", err)
+	}
+	// Published ports are fixed host ports, so the replacement could not start
+	// while the original holds them
+	if ports := containerInfo.Labels[LabelPorts]; ports != "" {
+		return fmt.Errorf("container '%s' publishes ports %s, which the replacement cannot bind while the original runs; rebuild without --blue-green", name, ports)
+	}
+
+	// A leftover replacement from an interrupted run would block creation
+	exists, err := m.client.ContainerExists(ctx, nextName)
+	if err != nil {
+		return fmt.Errorf("failed to check container existence: %w", err)
+	}
+	if exists {
+		m.logger.Warn("removing stale replacement container",
+			logging.WithField("container", nextName))
+		if err := m.client.RemoveContainer(ctx, nextName, false); err != nil {
+			return fmt.Errorf("failed to remove stale replacement container: %w", err)
+		}
+	}
+
+	// Step 2: Allocate temporary ports for the replacement
+	sshPort, err := m.client.FindAvailablePort(m.config.SSHPortStart)
+	if err != nil {
+		return fmt.Errorf("failed to find available SSH port: %w", err)
+	}
+	webPortOffset := m.portOffset(sshPort)
+	webPort, err := m.client.FindAvailablePort(m.config.WebPortStart + webPortOffset)
+	if err != nil {
+		return fmt.Errorf("failed to find available web port: %w", err)
+	}
+
+	// Carry existing labels forward so user metadata survives the rebuild
+	labels := make(map[string]string, len(containerInfo.Labels)+3)
+	for k, v := range containerInfo.Labels {
+		labels[k] = v
+	}
+	labels[LabelManaged] = "true"
+	labels[LabelSSHPort] = fmt.Sprintf("%d", sshPort)
+	labels[LabelWebPort] = fmt.Sprintf("%d", webPort)
+
+	m.logger.Info("creating replacement container",
+		logging.WithField("container", nextName),
+		logging.WithField("ssh_port", sshPort),
+		logging.WithField("web_port", webPort))
+
+	config := ContainerConfig{
+		Name:          nextName,
+		VolumeName:    containerName, // Share volumes with the running container
+		SSHPort:       sshPort,
+		WebPort:       webPort,
+		SSHPublicKey:  "", // authorized_keys already exists in the home volume
+		BaseImage:     m.imageForLabels(labels),
+		ContainerUser: m.config.ContainerUser,
+		AudioEnabled:  m.config.AudioEnabled,
+		AudioPort:     m.config.AudioPort,
+		Labels:        labels,
+	}
+	m.applyResourceLimits(&config)
+	m.applyMounts(&config, CreateOptions{})
+	m.applyRuntimeOptions(&config, nil)
+	m.applyProxy(&config)
+
+	if _, err := m.client.CreateContainer(ctx, config); err != nil {
+		return fmt.Errorf("failed to create replacement container: %w", err)
+	}
+
+	// discard removes the replacement while keeping the shared volumes
+	discard := func(cause error) error {
+		if rmErr := m.client.RemoveContainer(ctx, nextName, false); rmErr != nil {
+			m.logger.Warn("failed to remove replacement container",
+				logging.WithError(rmErr),
+				logging.WithField("container", nextName))
+		}
+		return cause
+	}
+
+	// Step 3: Sign host keys for the final name so they stay valid after the rename
+	if err := m.setupSSHCertificates(ctx, nextName, containerName); err != nil {
+		m.logger.Warn("failed to setup SSH certificates",
+			logging.WithError(err),
+			logging.WithField("container", nextName))
+	}
+
+	// Step 4: Start the replacement and verify it
+	if err := m.client.StartContainer(ctx, nextName); err != nil {
+		return discard(fmt.Errorf("failed to start replacement container: %w", err))
+	}
+
+	if err := waitForSSHFunc(ctx, m.config.ProxyJump, m.config.RemoteHost, sshPort, sshReadyTimeout); err != nil {
+		return discard(fmt.Errorf("replacement container failed SSH readiness check: %w", err))
+	}
+	if config.Runtime.Systemd {
+		if err := m.waitForSystemd(ctx, nextName); err != nil {
+			return discard(fmt.Errorf("replacement container failed systemd readiness check: %w", err))
+		}
+	}
+
+	if err := m.persistHostKeys(ctx, nextName); err != nil {
+		m.logger.Warn("failed to persist SSH host keys",
+			logging.WithError(err),
+			logging.WithField("container", nextName))
+	}
+
+	if err := m.copyDotfiles(ctx, nextName, labelCreateOptions(labels)); err != nil {
+		m.logger.Warn("failed to copy dotfiles during rebuild",
+			logging.WithError(err),
+			logging.WithField("container", nextName))
+	}
+	if err := m.persistPackages(ctx, nextName); err != nil {
+		m.logger.Warn("failed to reinstall persisted packages during rebuild",
+			logging.WithError(err),
+			logging.WithField("container", nextName))
+	}
+
+	if healthCmd != "" {
+		m.logger.Debug("running health command",
+			logging.WithField("container", nextName),
+			logging.WithField("command", healthCmd))
+		checkCmd := []string{"su", "-", m.config.ContainerUser, "-c", healthCmd}
+		if err := m.client.ExecContainer(ctx, nextName, checkCmd); err != nil {
+			return discard(fmt.Errorf("health command failed in replacement container: %w", err))
+		}
+	}
+
+	// Step 5: Cut over - remove the old container and take over its name
+	if err := m.client.StopContainer(ctx, containerName); err != nil {
+		m.logger.Debug("container stop failed (may already be stopped)",
+			logging.WithError(err))
+	}
+	if err := m.client.RemoveContainer(ctx, containerName, false); err != nil {
+		return discard(fmt.Errorf("failed to remove old container: %w", err))
+	}
+	if err := m.client.RenameContainer(ctx, nextName, containerName); err != nil {
+		// The old container is gone; leave the healthy replacement running under its temp name
+		return fmt.Errorf("failed to rename replacement container %s: %w", nextName, err)
+	}
+
+	// Step 6: Point SSH config at the new port. The git remote uses the SSH alias,
+	// so it follows automatically.
+	if err := ssh.AddSSHConfig(name, "", sshPort, m.config.ContainerUser); err != nil {
+		m.logger.Warn("failed to update SSH config entry",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	m.logger.Info("container rebuilt with blue/green cutover",
+		logging.WithField("container", containerName),
+		logging.WithField("old_ssh_port", containerInfo.SSHPort),
+		logging.WithField("ssh_port", sshPort))
+
+	return nil
+}
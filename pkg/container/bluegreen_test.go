@@ -0,0 +1,126 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestManager_BlueGreenRebuildContainer(t *testing.T) {
+	tests := []struct {
+		name        string
+		healthCmd   string
+		sshReadyErr error
+		setupMocks  func(*MockPodmanClient)
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:      "successful cutover",
+			healthCmd: "test -d /workspace/project",
+			setupMocks: func(m *MockPodmanClient) {
+				m.On("ExecContainer", mock.Anything, "dev-myproject-next",
+					[]string{"su", "-", "dev", "-c", "test -d /workspace/project"}).Return(nil)
+				m.On("StopContainer", mock.Anything, "dev-myproject").Return(nil)
+				m.On("RemoveContainer", mock.Anything, "dev-myproject", false).Return(nil)
+				m.On("RenameContainer", mock.Anything, "dev-myproject-next", "dev-myproject").Return(nil)
+			},
+		},
+		{
+			name:        "SSH never becomes ready",
+			sshReadyErr: errors.New("connection refused"),
+			setupMocks: func(m *MockPodmanClient) {
+				m.On("RemoveContainer", mock.Anything, "dev-myproject-next", false).Return(nil)
+			},
+			wantErr:     true,
+			errContains: "SSH readiness",
+		},
+		{
+			name:      "health command fails",
+			healthCmd: "make check",
+			setupMocks: func(m *MockPodmanClient) {
+				m.On("ExecContainer", mock.Anything, "dev-myproject-next",
+					[]string{"su", "-", "dev", "-c", "make check"}).Return(errors.New("exit status 2"))
+				m.On("RemoveContainer", mock.Anything, "dev-myproject-next", false).Return(nil)
+			},
+			wantErr:     true,
+			errContains: "health command failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+
+			origWait := waitForSSHFunc
+			defer func() { waitForSSHFunc = origWait }()
+			waitForSSHFunc = func(ctx context.Context, jump, host string, port int, timeout time.Duration) error {
+				assert.Equal(t, 2201, port)
+				return tt.sshReadyErr
+			}
+
+			mockClient := new(MockPodmanClient)
+			mockClient.On("GetContainerInfo", mock.Anything, "dev-myproject").Return(&Container{
+				Name:    "dev-myproject",
+				Status:  "running",
+				SSHPort: 2200,
+				WebPort: 3000,
+				Labels: map[string]string{
+					"l8s.managed":  "true",
+					"l8s.ssh.port": "2200",
+					"l8s.web.port": "3000",
+					"l8s.custom":   "kept",
+				},
+			}, nil)
+			mockClient.On("ContainerExists", mock.Anything, "dev-myproject-next").Return(false, nil)
+			mockClient.On("FindAvailablePort", 2200).Return(2201, nil)
+			mockClient.On("FindAvailablePort", 3001).Return(3001, nil)
+			mockClient.On("CreateContainer", mock.Anything, mock.MatchedBy(func(config ContainerConfig) bool {
+				return config.Name == "dev-myproject-next" &&
+					config.VolumeName == "dev-myproject" &&
+					config.SSHPort == 2201 &&
+					config.Labels["l8s.ssh.port"] == "2201" &&
+					config.Labels["l8s.custom"] == "kept"
+			})).Return(&Container{Name: "dev-myproject-next"}, nil)
+			mockClient.On("StartContainer", mock.Anything, "dev-myproject-next").Return(nil)
+			tt.setupMocks(mockClient)
+
+			// Dotfile deployment is best effort
+			mockClient.On("CopyToContainer", mock.Anything, "dev-myproject-next",
+				mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockClient.On("ExecContainer", mock.Anything, "dev-myproject-next",
+				mock.Anything).Return(nil).Maybe()
+			mockClient.On("ExecContainerWithInput", mock.Anything, "dev-myproject-next",
+				mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			// So is host key persistence
+			mockClient.On("ExecContainerOutput", mock.Anything, "dev-myproject-next",
+				mock.Anything).Return("saved\n", nil).Maybe()
+
+			manager := NewManager(mockClient, Config{
+				ContainerPrefix: "dev",
+				SSHPortStart:    2200,
+				WebPortStart:    3000,
+				BaseImage:       "localhost/l8s-fedora:latest",
+				ContainerUser:   "dev",
+			})
+
+			err := manager.BlueGreenRebuildContainer(context.Background(), "myproject", tt.healthCmd)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				// The original container must never be touched on failure
+				mockClient.AssertNotCalled(t, "RemoveContainer", mock.Anything, "dev-myproject", mock.Anything)
+				mockClient.AssertNotCalled(t, "StopContainer", mock.Anything, "dev-myproject")
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package container
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"l8s/pkg/logging"
+)
+
+// buildLogsKept is how many build logs are kept in the cache directory
+const buildLogsKept = 10
+
+// buildTailLines is how much of a failed quiet build's output is shown
+const buildTailLines = 20
+
+// BuildOptions controls how much of the image build may come from the
+// layer cache on the remote
+type BuildOptions struct {
+	NoCache bool // Rebuild every layer (--no-cache)
+	Refresh bool // Rebuild from the Containerfile's cache busting point for the latest packages
+	Quiet   bool // Keep build output off the terminal; it still goes to the log
+
+	Variant  string // Image variant being built
+	Fragment string // Containerfile fragment of the variant
+}
+
+// BuildStats counts the steps of a build and how many came from the layer cache
+type BuildStats struct {
+	Steps   int
+	Cached  int
+	LogPath string // Full build output, kept in the cache directory
+}
+
+var (
+	// buildStepLine matches build step headers other than FROM, which is never cached
+	buildStepLine = regexp.MustCompile(`^STEP \d+/\d+: (\S+)`)
+	// buildCacheLine is printed by podman for a step reused from the cache
+	buildCacheLine = regexp.MustCompile(`^--> Using cache `)
+)
+
+// buildCacheBust returns the CACHEBUST build argument. It is a hash of the
+// build context and arguments, so layers after the cache busting point are
+// reused until something that goes into them changes; refresh adds the time
+// to rebuild them anyway.
+func buildCacheBust(files []string, args []string, opts BuildOptions) (string, error) {
+	h := sha256.New()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash build context: %w", err)
+		}
+		fmt.Fprintf(h, "%d\n", len(data))
+		h.Write(data)
+	}
+	for _, arg := range args {
+		fmt.Fprintf(h, "%s\n", arg)
+	}
+	bust := fmt.Sprintf("%x", h.Sum(nil))[:16]
+	if opts.Refresh {
+		bust += fmt.Sprintf("-%d", time.Now().Unix())
+	}
+	return bust, nil
+}
+
+// buildOutput passes build output to the terminal and the log file, and
+// each line to the structured logger, while counting steps and cache hits.
+// The last lines are kept to explain failures of quiet builds.
+type buildOutput struct {
+	out    io.Writer
+	log    io.Writer
+	logger *slog.Logger
+	stats  *BuildStats
+	line   []byte
+	tail   []string
+}
+
+func (b *buildOutput) Write(p []byte) (int, error) {
+	if b.log != nil {
+		// The log is best effort; a full disk should not fail the build
+		_, _ = b.log.Write(p)
+	}
+	b.line = append(b.line, p...)
+	for {
+		i := bytes.IndexByte(b.line, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(b.line[:i])
+		if m := buildStepLine.FindStringSubmatch(line); m != nil && m[1] != "FROM" {
+			b.stats.Steps++
+		} else if buildCacheLine.MatchString(line) {
+			b.stats.Cached++
+		}
+		if b.logger != nil {
+			b.logger.Debug("build output", logging.WithField("line", line))
+		}
+		b.tail = append(b.tail, line)
+		if len(b.tail) > buildTailLines {
+			b.tail = b.tail[1:]
+		}
+		b.line = b.line[i+1:]
+	}
+	return b.out.Write(p)
+}
+
+// Tail returns the last lines of output
+func (b *buildOutput) Tail() string {
+	return strings.Join(b.tail, "\n")
+}
+
+// createBuildLog opens a new log file for a build in dir, removing all but
+// the most recent buildLogsKept logs
+func createBuildLog(dir, flavor string) (*os.File, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create build log directory: %w", err)
+	}
+	file, err := os.Create(filepath.Join(dir, fmt.Sprintf("%s-%s.log", time.Now().Format("20060102-150405"), flavor)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create build log: %w", err)
+	}
+	logs, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	// Timestamped names sort oldest first
+	sort.Strings(logs)
+	for len(logs) > buildLogsKept {
+		os.Remove(logs[0])
+		logs = logs[1:]
+	}
+	return file, nil
+}
+
+// SetBuildOptions sets the layer caching of the next image build
+func (m *Manager) SetBuildOptions(opts BuildOptions) {
+	m.buildOptions = opts
+}
+
+// LastBuildStats returns the cache statistics of the last image build
+func (m *Manager) LastBuildStats() BuildStats {
+	return m.buildStats
+}
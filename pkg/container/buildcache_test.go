@@ -0,0 +1,77 @@
+package container
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCacheBust(t *testing.T) {
+	dir := t.TempDir()
+	containerfile := filepath.Join(dir, "Containerfile")
+	require.NoError(t, os.WriteFile(containerfile, []byte("FROM fedora:latest\n"), 0644))
+	files := []string{containerfile}
+
+	bust, err := buildCacheBust(files, []string{"dev", "fedora"}, BuildOptions{})
+	require.NoError(t, err)
+	again, err := buildCacheBust(files, []string{"dev", "fedora"}, BuildOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, bust, again, "an unchanged context reuses the cache")
+
+	other, err := buildCacheBust(files, []string{"alice", "fedora"}, BuildOptions{})
+	require.NoError(t, err)
+	assert.NotEqual(t, bust, other)
+
+	require.NoError(t, os.WriteFile(containerfile, []byte("FROM fedora:41\n"), 0644))
+	changed, err := buildCacheBust(files, []string{"dev", "fedora"}, BuildOptions{})
+	require.NoError(t, err)
+	assert.NotEqual(t, bust, changed)
+
+	refreshed, err := buildCacheBust(files, []string{"dev", "fedora"}, BuildOptions{Refresh: true})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(refreshed, changed+"-"))
+
+	_, err = buildCacheBust([]string{filepath.Join(dir, "missing")}, nil, BuildOptions{})
+	assert.Error(t, err)
+}
+
+func TestBuildOutput(t *testing.T) {
+	var out, log bytes.Buffer
+	var stats BuildStats
+	w := &buildOutput{out: &out, log: &log, stats: &stats}
+
+	output := "STEP 1/4: FROM fedora:latest\nSTEP 2/4: RUN dnf install -y git\n--> Using cache 3f2a1b\n--> 3f2a1b\nSTEP 3/4: ARG CACHEBUST=1\n--> Using cache 9c8d7e\nSTEP 4/4: RUN dnf update -y\nUpdating...\n"
+	// Written in pieces, as ssh delivers it
+	for _, chunk := range []string{output[:20], output[20:71], output[71:]} {
+		_, err := w.Write([]byte(chunk))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, output, out.String())
+	assert.Equal(t, output, log.String())
+	assert.Equal(t, BuildStats{Steps: 3, Cached: 2}, stats)
+	assert.Equal(t, "STEP 4/4: RUN dnf update -y\nUpdating...", strings.Join(strings.Split(w.Tail(), "\n")[6:], "\n"))
+}
+
+func TestCreateBuildLog(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < buildLogsKept+2; i++ {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("20240101-0000%02d-fedora.log", i)), nil, 0600))
+	}
+
+	file, err := createBuildLog(dir, "debian")
+	require.NoError(t, err)
+	defer file.Close()
+	assert.True(t, strings.HasSuffix(file.Name(), "-debian.log"))
+
+	logs, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	assert.Len(t, logs, buildLogsKept)
+	assert.Contains(t, logs, file.Name())
+	assert.NotContains(t, logs, filepath.Join(dir, "20240101-000000-fedora.log"))
+}
@@ -0,0 +1,186 @@
+package container
+
+import (
+	"context"
+	"fmt"
+
+	"l8s/pkg/cleanup"
+	"l8s/pkg/logging"
+	"l8s/pkg/ssh"
+)
+
+// volumeSuffixes are the named volumes every l8s container owns
+var volumeSuffixes = []string{"-home", "-workspace"}
+
+// containerVolumes returns the named volumes belonging to a container
+func containerVolumes(containerName string) []string {
+	volumes := make([]string, 0, len(volumeSuffixes))
+	for _, suffix := range volumeSuffixes {
+		volumes = append(volumes, containerName+suffix)
+	}
+	return volumes
+}
+
+// CloneContainer duplicates the volumes and configuration of source into a new
+// container with freshly allocated ports and its own SSH config entry
+func (m *Manager) CloneContainer(ctx context.Context, source, name string) (*Container, error) {
+	cleaner := cleanup.New(m.logger)
+
+	if err := validateContainerName(name); err != nil {
+		return nil, err
+	}
+
+	sourceName := m.config.ContainerPrefix + "-" + source
+	containerName := m.config.ContainerPrefix + "-" + name
+
+	sourceInfo, err := m.client.GetContainerInfo(ctx, sourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source container info: %w", err)
+	}
+
+	exists, err := m.client.ContainerExists(ctx, containerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check container existence: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("container '%s' already exists", name)
+	}
+
+	if err := m.checkQuota(ctx); err != nil {
+		return nil, err
+	}
+
+	// Copy volumes first so a failure leaves nothing half-configured. A copy
+	// can fail after creating its volume, so cleanup is registered up front;
+	// volumes that already existed are never removed.
+	if existingVolumes, err := m.client.ListVolumes(ctx); err != nil {
+		m.logger.Warn("failed to list volumes, they are kept if cloning fails",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	} else {
+		cleaner.Add("remove_volumes", func(ctx context.Context) error {
+			return m.removeNewVolumes(ctx, containerName, existingVolumes)
+		})
+	}
+	sourceVolumes := containerVolumes(sourceName)
+	for i, dst := range containerVolumes(containerName) {
+		src := sourceVolumes[i]
+		m.logger.Debug("copying volume",
+			logging.WithField("source", src),
+			logging.WithField("destination", dst))
+		if err := m.client.CopyVolume(ctx, src, dst); err != nil {
+			cleaner.Cleanup(ctx)
+			return nil, fmt.Errorf("failed to copy volume: %w", err)
+		}
+	}
+
+	// The clone belongs to whoever made it; the source's key fingerprint still
+	// applies because authorized_keys was copied along with the home volume
+	labels := make(map[string]string, len(sourceInfo.Labels))
+	for k, v := range sourceInfo.Labels {
+		labels[k] = v
+	}
+	labels[LabelOwner] = CurrentUser()
+	// Published host ports stay with the source, which may still be using them
+	delete(labels, LabelPorts)
+	// The clone's remote goes wherever it is cloned from, not the source's worktree
+	delete(labels, LabelWorktree)
+
+	container, err := m.createFromVolumes(ctx, name, labels, cleaner)
+	if err != nil {
+		return nil, err
+	}
+
+	m.logger.Info("container cloned successfully",
+		logging.WithField("source", sourceName),
+		logging.WithField("container", containerName))
+
+	return container, nil
+}
+
+// createFromVolumes creates and starts a container for name on fresh ports using
+// volumes that already exist. Cleanup of the container is registered on cleaner.
+func (m *Manager) createFromVolumes(ctx context.Context, name string, labels map[string]string, cleaner *cleanup.Cleaner) (*Container, error) {
+	containerName := m.config.ContainerPrefix + "-" + name
+
+	sshPort, err := m.client.FindAvailablePort(m.config.SSHPortStart)
+	if err != nil {
+		cleaner.Cleanup(ctx)
+		return nil, fmt.Errorf("failed to find available SSH port: %w", err)
+	}
+	if err := m.checkPortBudget(sshPort); err != nil {
+		cleaner.Cleanup(ctx)
+		return nil, err
+	}
+	webPortOffset := m.portOffset(sshPort)
+	webPort, err := m.client.FindAvailablePort(m.config.WebPortStart + webPortOffset)
+	if err != nil {
+		cleaner.Cleanup(ctx)
+		return nil, fmt.Errorf("failed to find available web port: %w", err)
+	}
+
+	containerLabels := make(map[string]string, len(labels)+3)
+	for k, v := range labels {
+		containerLabels[k] = v
+	}
+	containerLabels[LabelManaged] = "true"
+	containerLabels[LabelSSHPort] = fmt.Sprintf("%d", sshPort)
+	containerLabels[LabelWebPort] = fmt.Sprintf("%d", webPort)
+
+	m.logger.Info("creating container from existing volumes",
+		logging.WithField("container", containerName),
+		logging.WithField("ssh_port", sshPort),
+		logging.WithField("web_port", webPort))
+
+	config := ContainerConfig{
+		Name:          containerName,
+		SSHPort:       sshPort,
+		WebPort:       webPort,
+		SSHPublicKey:  "", // authorized_keys already lives in the home volume
+		BaseImage:     m.imageForLabels(labels),
+		ContainerUser: m.config.ContainerUser,
+		AudioEnabled:  m.config.AudioEnabled,
+		AudioPort:     m.config.AudioPort,
+		Labels:        containerLabels,
+	}
+	m.applyResourceLimits(&config)
+	m.applyMounts(&config, CreateOptions{})
+	m.applyRuntimeOptions(&config, nil)
+	m.applyProxy(&config)
+
+	container, err := m.client.CreateContainer(ctx, config)
+	if err != nil {
+		cleaner.Cleanup(ctx)
+		return nil, fmt.Errorf("failed to create container: %w", err)
+	}
+	cleaner.Add("remove_container", func(ctx context.Context) error {
+		return m.client.RemoveContainer(ctx, containerName, false)
+	})
+
+	if err := m.setupSSHCertificatesBeforeStart(ctx, containerName); err != nil {
+		m.logger.Warn("failed to setup SSH certificates",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	if err := m.client.StartContainer(ctx, containerName); err != nil {
+		cleaner.Cleanup(ctx)
+		return nil, fmt.Errorf("failed to start container: %w", err)
+	}
+
+	if err := m.fixVolumeOwnership(ctx, containerName); err != nil {
+		m.logger.Warn("failed to fix volume ownership",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	if err := ssh.AddSSHConfig(name, "", sshPort, m.config.ContainerUser); err != nil {
+		m.logger.Warn("failed to add SSH config entry",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	container.SSHPort = sshPort
+	container.WebPort = webPort
+	return container, nil
+}
@@ -0,0 +1,99 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_CloneContainer(t *testing.T) {
+	sourceInfo := &Container{
+		Name:    "dev-myproject",
+		Status:  "running",
+		SSHPort: 2200,
+		WebPort: 3000,
+		Labels: map[string]string{
+			"l8s.managed":  "true",
+			"l8s.ssh.port": "2200",
+			"l8s.web.port": "3000",
+			"l8s.worktree": "/home/dev/src/myproject",
+		},
+	}
+
+	t.Run("copies volumes and creates container on new ports", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		m := new(MockPodmanClient)
+		m.On("GetContainerInfo", mock.Anything, "dev-myproject").Return(sourceInfo, nil)
+		m.On("ContainerExists", mock.Anything, "dev-experiment").Return(false, nil)
+		m.On("FindAvailablePort", 2200).Return(2201, nil)
+		m.On("FindAvailablePort", 3001).Return(3001, nil)
+		m.On("ListVolumes", mock.Anything).Return([]string{"dev-myproject-home", "dev-myproject-workspace"}, nil)
+		m.On("CopyVolume", mock.Anything, "dev-myproject-home", "dev-experiment-home").Return(nil)
+		m.On("CopyVolume", mock.Anything, "dev-myproject-workspace", "dev-experiment-workspace").Return(nil)
+		m.On("CreateContainer", mock.Anything, mock.MatchedBy(func(config ContainerConfig) bool {
+			return config.Name == "dev-experiment" &&
+				config.SSHPort == 2201 &&
+				config.WebPort == 3001 &&
+				config.Labels["l8s.ssh.port"] == "2201" &&
+				config.Labels["l8s.worktree"] == ""
+		})).Return(&Container{Name: "dev-experiment"}, nil)
+		m.On("StartContainer", mock.Anything, "dev-experiment").Return(nil)
+		m.On("ExecContainer", mock.Anything, "dev-experiment", mock.Anything).Return(nil)
+
+		manager := NewManager(m, Config{
+			ContainerPrefix: "dev",
+			SSHPortStart:    2200,
+			WebPortStart:    3000,
+			ContainerUser:   "dev",
+		})
+
+		cont, err := manager.CloneContainer(context.Background(), "myproject", "experiment")
+		require.NoError(t, err)
+		assert.Equal(t, 2201, cont.SSHPort)
+		assert.Equal(t, 3001, cont.WebPort)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("removes copied volumes when a later copy fails", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("GetContainerInfo", mock.Anything, "dev-myproject").Return(sourceInfo, nil)
+		m.On("ContainerExists", mock.Anything, "dev-experiment").Return(false, nil)
+		m.On("ListVolumes", mock.Anything).Return([]string{"dev-myproject-home"}, nil).Once()
+		m.On("CopyVolume", mock.Anything, "dev-myproject-home", "dev-experiment-home").Return(nil)
+		m.On("CopyVolume", mock.Anything, "dev-myproject-workspace", "dev-experiment-workspace").
+			Return(errors.New("no space left on device"))
+		// The failed copy had already created its volume
+		m.On("ListVolumes", mock.Anything).
+			Return([]string{"dev-myproject-home", "dev-experiment-home", "dev-experiment-workspace"}, nil).Once()
+		m.On("RemoveVolume", mock.Anything, "dev-experiment-home").Return(nil)
+		m.On("RemoveVolume", mock.Anything, "dev-experiment-workspace").Return(nil)
+
+		manager := NewManager(m, Config{
+			ContainerPrefix: "dev",
+			SSHPortStart:    2200,
+			WebPortStart:    3000,
+			ContainerUser:   "dev",
+		})
+
+		_, err := manager.CloneContainer(context.Background(), "myproject", "experiment")
+		assert.Error(t, err)
+		m.AssertExpectations(t)
+		m.AssertNotCalled(t, "CreateContainer", mock.Anything, mock.Anything)
+	})
+
+	t.Run("refuses to overwrite an existing container", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("GetContainerInfo", mock.Anything, "dev-myproject").Return(sourceInfo, nil)
+		m.On("ContainerExists", mock.Anything, "dev-experiment").Return(true, nil)
+
+		manager := NewManager(m, Config{ContainerPrefix: "dev"})
+
+		_, err := manager.CloneContainer(context.Background(), "myproject", "experiment")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "already exists")
+	})
+}
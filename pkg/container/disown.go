@@ -0,0 +1,112 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"l8s/pkg/cleanup"
+	"l8s/pkg/logging"
+	"l8s/pkg/ssh"
+)
+
+// disownedImage is the image a disowned container's filesystem is committed to
+func disownedImage(containerName string) string {
+	return "localhost/" + containerName + "-disowned:latest"
+}
+
+// unmanagedLabels returns labels without any l8s labels. l8s.managed is set
+// to false rather than dropped so a copy inherited from the committed image
+// cannot mark the container as managed again.
+func unmanagedLabels(labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if !strings.HasPrefix(k, "l8s.") {
+			result[k] = v
+		}
+	}
+	result[LabelManaged] = "false"
+	return result
+}
+
+// DisownContainer releases a container from l8s, leaving it on the host with
+// its name, ports, volumes and filesystem for manual management. Podman
+// cannot remove labels, so the container is committed to an image and
+// recreated from it without l8s labels; the original is only removed once the
+// replacement exists. The SSH config entry is removed; git remotes are left
+// to the caller.
+func (m *Manager) DisownContainer(ctx context.Context, name string) (err error) {
+	cleaner := cleanup.New(m.logger)
+	defer cleaner.CleanupOnError(context.WithoutCancel(ctx), &err)
+
+	containerName := m.config.ContainerPrefix + "-" + name
+	containerInfo, err := m.client.GetContainerInfo(ctx, containerName)
+	if err != nil {
+		return fmt.Errorf("failed to get container info: %w", err)
+	}
+	if containerInfo.SSHPort == 0 {
+		return fmt.Errorf("container has no SSH port configured")
+	}
+
+	// Mounts, limits and runtime options are read from the l8s labels, so
+	// the configuration is built before they are stripped
+	image := disownedImage(containerName)
+	if err := m.client.CommitContainer(ctx, containerName, image); err != nil {
+		return err
+	}
+	config := m.recreateConfig(containerInfo, containerInfo.Labels)
+	config.BaseImage = image
+	config.Labels = unmanagedLabels(config.Labels)
+
+	if err := m.client.StopContainer(ctx, containerName); err != nil {
+		m.logger.Debug("container stop failed (may already be stopped)",
+			logging.WithError(err))
+	}
+	previous := containerName + "-disowning"
+	if err := m.client.RenameContainer(ctx, containerName, previous); err != nil {
+		return fmt.Errorf("failed to rename container: %w", err)
+	}
+	cleaner.Add("restore_container", func(ctx context.Context) error {
+		if err := m.client.RenameContainer(ctx, previous, containerName); err != nil {
+			return err
+		}
+		if containerInfo.Status == "running" {
+			return m.client.StartContainer(ctx, containerName)
+		}
+		return nil
+	})
+
+	m.logger.Debug("recreating container without l8s labels",
+		logging.WithField("container", containerName),
+		logging.WithField("image", image))
+	if _, err := m.client.CreateContainer(ctx, config); err != nil {
+		return fmt.Errorf("failed to create container: %w", err)
+	}
+	cleaner.Add("remove_container", func(ctx context.Context) error {
+		return m.client.RemoveContainer(ctx, containerName, false)
+	})
+
+	if containerInfo.Status == "running" {
+		if err := m.client.StartContainer(ctx, containerName); err != nil {
+			return fmt.Errorf("failed to start container: %w", err)
+		}
+	}
+
+	// Named volumes outlive the container they were created with
+	if err := m.client.RemoveContainer(ctx, previous, false); err != nil {
+		m.logger.Warn("failed to remove the original container",
+			logging.WithError(err),
+			logging.WithField("container", previous))
+	}
+
+	if err := ssh.RemoveSSHConfig(name); err != nil {
+		m.logger.Warn("failed to remove SSH config entry",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	m.logger.Info("container disowned",
+		logging.WithField("container", containerName))
+
+	return nil
+}
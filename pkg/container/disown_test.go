@@ -0,0 +1,78 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmanagedLabels(t *testing.T) {
+	labels := unmanagedLabels(map[string]string{
+		LabelManaged:             "true",
+		LabelSSHPort:             "2200",
+		LabelUserPrefix + "team": "payments",
+		"com.example.maintainer": "ops",
+	})
+	assert.Equal(t, map[string]string{
+		LabelManaged:             "false",
+		"com.example.maintainer": "ops",
+	}, labels)
+}
+
+func TestManager_DisownContainer(t *testing.T) {
+	info := &Container{
+		Name:    "dev-myproject",
+		Status:  "running",
+		SSHPort: 2200,
+		WebPort: 3000,
+		Labels: map[string]string{
+			LabelManaged: "true",
+			LabelSSHPort: "2200",
+			LabelWebPort: "3000",
+			LabelOwner:   "alice",
+		},
+	}
+	newManager := func(m *MockPodmanClient) *Manager {
+		return NewManager(m, Config{ContainerPrefix: "dev", ContainerUser: "dev"})
+	}
+
+	t.Run("recreates the container without l8s labels", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		m := new(MockPodmanClient)
+		m.On("GetContainerInfo", mock.Anything, "dev-myproject").Return(info, nil)
+		m.On("CommitContainer", mock.Anything, "dev-myproject", "localhost/dev-myproject-disowned:latest").Return(nil)
+		m.On("StopContainer", mock.Anything, "dev-myproject").Return(nil)
+		m.On("RenameContainer", mock.Anything, "dev-myproject", "dev-myproject-disowning").Return(nil)
+		m.On("CreateContainer", mock.Anything, mock.MatchedBy(func(config ContainerConfig) bool {
+			return config.Name == "dev-myproject" &&
+				config.BaseImage == "localhost/dev-myproject-disowned:latest" &&
+				config.SSHPort == 2200 &&
+				config.Labels[LabelManaged] == "false" &&
+				config.Labels[LabelOwner] == ""
+		})).Return(&Container{Name: "dev-myproject"}, nil)
+		m.On("StartContainer", mock.Anything, "dev-myproject").Return(nil)
+		m.On("RemoveContainer", mock.Anything, "dev-myproject-disowning", false).Return(nil)
+
+		require.NoError(t, newManager(m).DisownContainer(context.Background(), "myproject"))
+		m.AssertExpectations(t)
+	})
+
+	t.Run("restores the original when the replacement cannot be created", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("GetContainerInfo", mock.Anything, "dev-myproject").Return(info, nil)
+		m.On("CommitContainer", mock.Anything, "dev-myproject", mock.Anything).Return(nil)
+		m.On("StopContainer", mock.Anything, "dev-myproject").Return(nil)
+		m.On("RenameContainer", mock.Anything, "dev-myproject", "dev-myproject-disowning").Return(nil)
+		m.On("CreateContainer", mock.Anything, mock.Anything).Return(nil, errors.New("image not known"))
+		m.On("RenameContainer", mock.Anything, "dev-myproject-disowning", "dev-myproject").Return(nil)
+		m.On("StartContainer", mock.Anything, "dev-myproject").Return(nil)
+
+		assert.Error(t, newManager(m).DisownContainer(context.Background(), "myproject"))
+		m.AssertExpectations(t)
+		m.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,156 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// FileChange is a file added (A), changed (C) or deleted (D) in a
+// container's filesystem, as reported by podman diff
+type FileChange struct {
+	Path string
+	Kind string
+}
+
+// Drift is how a container differs from the image a rebuild would create it
+// from. Volumes, the home directory and /workspace, are not included: they
+// survive rebuilds.
+type Drift struct {
+	Image    string
+	Added    []string     // Packages installed in the container but not in the image
+	Removed  []string     // Packages in the image removed from the container
+	Declared []string     // Added packages a rebuild installs again, from .l8s.yaml
+	Files    []FileChange // Changed configuration and locally installed files
+}
+
+// Empty reports whether a rebuild would lose nothing
+func (d *Drift) Empty() bool {
+	return len(d.Added) == len(d.Declared) && len(d.Removed) == 0 && len(d.Files) == 0
+}
+
+// packageListScript prints the names of installed packages, one per line
+const packageListScript = `if command -v dpkg-query >/dev/null 2>&1; then dpkg-query -W -f '${Package}\n'; else rpm -qa --qf '%{NAME}\n'; fi`
+
+// driftPaths are where file changes matter: configuration and software
+// installed outside the package manager. Changes elsewhere are package
+// contents, caches or runtime state.
+var driftPaths = []string{"/etc/", "/usr/local/", "/opt/", "/root/"}
+
+// driftIgnored are files l8s, podman or the package manager rewrite in
+// every container, so they say nothing about what a rebuild loses
+var driftIgnored = []string{
+	"/etc/ssh/", "/etc/environment", "/etc/hostname", "/etc/hosts", "/etc/resolv.conf",
+	"/etc/ld.so.cache", "/etc/.pwd.lock", "/etc/passwd", "/etc/group", "/etc/shadow", "/etc/gshadow",
+	"/etc/subuid", "/etc/subgid", "/etc/dnf/dnf.conf", "/etc/apt/apt.conf.d/95l8s-proxy",
+	"/etc/gitconfig", "/etc/npmrc", "/etc/profile.d/l8s-", "/root/.cache/", "/root/.npm/",
+	"/etc/l8s/", "/etc/dnf/libdnf5-plugins/actions.d/l8s.actions", "/etc/apt/apt.conf.d/95l8s-record-packages",
+	recordPackagesPath,
+}
+
+// Drift compares a running container's packages and files with the image a
+// rebuild would use
+func (m *Manager) Drift(ctx context.Context, name string) (*Drift, error) {
+	containerName := m.config.ContainerPrefix + "-" + name
+	cont, err := m.client.GetContainerInfo(ctx, containerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container info: %w", err)
+	}
+	if cont.Status != "running" {
+		return nil, fmt.Errorf("container '%s' is not running", name)
+	}
+	image := m.imageForLabels(cont.Labels)
+
+	installed, err := m.client.ExecContainerOutput(ctx, containerName, []string{"sh", "-c", packageListScript})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages in the container: %w", err)
+	}
+	baseline, err := m.client.RunImage(ctx, image, []string{"sh", "-c", packageListScript})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages in %s: %w", image, err)
+	}
+	changes, err := m.client.DiffContainer(ctx, containerName)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := &Drift{Image: image, Files: driftFiles(changes)}
+	drift.Added, drift.Removed = comparePackages(strings.Fields(installed), strings.Fields(baseline))
+	declared := make(map[string]bool)
+	packages := ParsePackages(cont.Labels[LabelPackages])
+	for _, spec := range append(packages.Dnf, packages.Apt...) {
+		// apt pins versions as name=version
+		name, _, _ := strings.Cut(spec, "=")
+		declared[name] = true
+	}
+	for _, pkg := range drift.Added {
+		if declared[pkg] {
+			drift.Declared = append(drift.Declared, pkg)
+		}
+	}
+	return drift, nil
+}
+
+// comparePackages returns the packages only in installed and only in
+// baseline, sorted
+func comparePackages(installed, baseline []string) (added, removed []string) {
+	inBaseline := make(map[string]bool, len(baseline))
+	for _, pkg := range baseline {
+		inBaseline[pkg] = true
+	}
+	inContainer := make(map[string]bool, len(installed))
+	for _, pkg := range installed {
+		if !inContainer[pkg] && !inBaseline[pkg] {
+			added = append(added, pkg)
+		}
+		inContainer[pkg] = true
+	}
+	for pkg := range inBaseline {
+		if !inContainer[pkg] {
+			removed = append(removed, pkg)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// driftFiles keeps the changes under driftPaths that l8s did not make,
+// dropping directories whose only change is the files listed below them
+func driftFiles(changes []FileChange) []FileChange {
+	var files []FileChange
+	for _, change := range changes {
+		if !hasAnyPrefix(change.Path, driftPaths) || hasAnyPrefix(change.Path, driftIgnored) {
+			continue
+		}
+		files = append(files, change)
+	}
+	parents := make(map[string]bool)
+	for _, change := range files {
+		for dir := path.Dir(change.Path); dir != "/"; dir = path.Dir(dir) {
+			parents[dir] = true
+		}
+	}
+
+	var kept []FileChange
+	for _, change := range files {
+		if change.Kind == "C" && parents[change.Path] {
+			continue
+		}
+		kept = append(kept, change)
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Path < kept[j].Path })
+	return kept
+}
+
+// hasAnyPrefix reports whether p starts with one of prefixes
+func hasAnyPrefix(p string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComparePackages(t *testing.T) {
+	added, removed := comparePackages(
+		[]string{"bash", "htop", "ripgrep", "htop"},
+		[]string{"bash", "vim-enhanced", "ripgrep"},
+	)
+	assert.Equal(t, []string{"htop"}, added)
+	assert.Equal(t, []string{"vim-enhanced"}, removed)
+}
+
+func TestDriftFiles(t *testing.T) {
+	files := driftFiles([]FileChange{
+		{Path: "/etc", Kind: "C"},
+		{Path: "/etc/nginx", Kind: "C"},
+		{Path: "/etc/nginx/nginx.conf", Kind: "C"},
+		{Path: "/etc/nginx-extra", Kind: "A"},
+		{Path: "/etc/ssh/sshd_config", Kind: "C"},
+		{Path: "/etc/hosts", Kind: "C"},
+		{Path: "/usr/local/bin/tool", Kind: "A"},
+		{Path: "/usr/bin/htop", Kind: "A"},
+		{Path: "/var/log/dnf.log", Kind: "C"},
+		{Path: "/opt/old", Kind: "D"},
+	})
+	assert.Equal(t, []FileChange{
+		{Path: "/etc/nginx-extra", Kind: "A"},
+		{Path: "/etc/nginx/nginx.conf", Kind: "C"},
+		{Path: "/opt/old", Kind: "D"},
+		{Path: "/usr/local/bin/tool", Kind: "A"},
+	}, files)
+}
+
+func TestDrift(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("compares with the variant image", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		manager := NewManager(m, Config{
+			ContainerPrefix: "dev",
+			BaseImage:       "localhost/l8s-fedora:latest",
+			ImageVariants:   map[string]ImageVariant{"ml": {Image: "localhost/l8s-fedora-ml:latest"}},
+		})
+		m.On("GetContainerInfo", mock.Anything, "dev-myproject").Return(&Container{
+			Name:   "dev-myproject",
+			Status: "running",
+			Labels: map[string]string{LabelImageVariant: "ml", LabelPackages: "dnf=jq;pip=black"},
+		}, nil)
+		m.On("ExecContainerOutput", mock.Anything, "dev-myproject", []string{"sh", "-c", packageListScript}).Return("bash\nhtop\njq\n", nil)
+		m.On("RunImage", mock.Anything, "localhost/l8s-fedora-ml:latest", []string{"sh", "-c", packageListScript}).Return("bash\nvim-enhanced\n", nil)
+		m.On("DiffContainer", mock.Anything, "dev-myproject").Return([]FileChange{{Path: "/usr/local/bin/tool", Kind: "A"}}, nil)
+
+		drift, err := manager.Drift(ctx, "myproject")
+		require.NoError(t, err)
+		assert.Equal(t, "localhost/l8s-fedora-ml:latest", drift.Image)
+		assert.Equal(t, []string{"htop", "jq"}, drift.Added)
+		assert.Equal(t, []string{"jq"}, drift.Declared)
+		assert.Equal(t, []string{"vim-enhanced"}, drift.Removed)
+		assert.Equal(t, []FileChange{{Path: "/usr/local/bin/tool", Kind: "A"}}, drift.Files)
+		assert.False(t, drift.Empty())
+	})
+
+	t.Run("stopped container", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		manager := NewManager(m, Config{ContainerPrefix: "dev"})
+		m.On("GetContainerInfo", mock.Anything, "dev-myproject").Return(&Container{Status: "exited"}, nil)
+
+		_, err := manager.Drift(ctx, "myproject")
+		assert.ErrorContains(t, err, "not running")
+	})
+}
@@ -0,0 +1,142 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ImageUsage is an image's storage on the podman host
+type ImageUsage struct {
+	Name       string // repository:tag; empty for a dangling image
+	ID         string
+	Size       int64
+	UniqueSize int64 // Not shared with other images' layers
+	Containers int   // Containers using the image
+}
+
+// ContainerUsage is the size of a container's writable layer
+type ContainerUsage struct {
+	Name string
+	Size int64
+}
+
+// VolumeUsage is a named volume's size
+type VolumeUsage struct {
+	Name  string
+	Size  int64
+	InUse bool // Mounted by a container
+}
+
+// DiskUsage is the storage of every image, container and volume on the host
+type DiskUsage struct {
+	Images     []ImageUsage
+	Containers []ContainerUsage
+	Volumes    []VolumeUsage
+}
+
+// ContainerDisk is the storage one l8s container uses
+type ContainerDisk struct {
+	Name    string // Full container name
+	Status  string
+	Layer   int64         // Writable layer: changes outside the volumes, lost on rebuild
+	Volumes []VolumeUsage // The home and workspace volumes
+}
+
+// Total is the container's writable layer and volumes together
+func (c ContainerDisk) Total() int64 {
+	total := c.Layer
+	for _, v := range c.Volumes {
+		total += v.Size
+	}
+	return total
+}
+
+// DiskReport is the storage l8s uses on the host and what could be freed
+type DiskReport struct {
+	Images     []ImageUsage    // The base image and image variants
+	Containers []ContainerDisk // Largest first
+	Shared     []VolumeUsage   // Caches mounted into several containers, e.g. the Nix store
+	Dangling   []ImageUsage    // Untagged images no container uses, left by rebuilds
+	Unused     []VolumeUsage   // l8s volumes no container mounts
+	Trash      []VolumeUsage   // Volumes of containers removed with --trash
+}
+
+// Reclaimable is the space pruning dangling images and unused and trashed volumes frees
+func (r *DiskReport) Reclaimable() int64 {
+	var total int64
+	for _, image := range r.Dangling {
+		total += image.UniqueSize
+	}
+	for _, volumes := range [][]VolumeUsage{r.Unused, r.Trash} {
+		for _, v := range volumes {
+			total += v.Size
+		}
+	}
+	return total
+}
+
+// DiskUsage reports the storage of l8s's images, containers and volumes
+func (m *Manager) DiskUsage(ctx context.Context) (*DiskReport, error) {
+	usage, err := m.client.DiskUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := m.client.ListContainers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	report := &DiskReport{}
+	ours := map[string]bool{m.config.BaseImage: true}
+	for _, variant := range m.config.ImageVariants {
+		ours[variant.Image] = true
+	}
+	for _, image := range usage.Images {
+		switch {
+		case ours[image.Name]:
+			report.Images = append(report.Images, image)
+		case image.Name == "" && image.Containers == 0:
+			report.Dangling = append(report.Dangling, image)
+		}
+	}
+
+	layers := make(map[string]int64, len(usage.Containers))
+	for _, c := range usage.Containers {
+		layers[c.Name] = c.Size
+	}
+	volumes := make(map[string]VolumeUsage, len(usage.Volumes))
+	for _, v := range usage.Volumes {
+		volumes[v.Name] = v
+	}
+	owned := make(map[string]bool)
+	for _, c := range containers {
+		disk := ContainerDisk{Name: c.Name, Status: c.Status, Layer: layers[c.Name]}
+		for _, name := range containerVolumes(c.Name) {
+			if v, ok := volumes[name]; ok {
+				disk.Volumes = append(disk.Volumes, v)
+				owned[name] = true
+			}
+		}
+		report.Containers = append(report.Containers, disk)
+	}
+	sort.SliceStable(report.Containers, func(i, j int) bool {
+		return report.Containers[i].Total() > report.Containers[j].Total()
+	})
+
+	for _, v := range usage.Volumes {
+		switch {
+		case owned[v.Name]:
+		case strings.HasPrefix(v.Name, trashPrefix):
+			report.Trash = append(report.Trash, v)
+		case !strings.HasPrefix(v.Name, m.config.ContainerPrefix+"-"):
+			// Not created by l8s
+		case v.InUse:
+			report.Shared = append(report.Shared, v)
+		default:
+			report.Unused = append(report.Unused, v)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,58 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskUsage(t *testing.T) {
+	client := new(MockPodmanClient)
+	manager := NewManager(client, Config{
+		ContainerPrefix: "dev",
+		BaseImage:       "localhost/l8s-fedora:latest",
+		ImageVariants:   map[string]ImageVariant{"ml": {Image: "localhost/l8s-fedora-ml:latest"}},
+	})
+	client.On("DiskUsage", mock.Anything).Return(&DiskUsage{
+		Images: []ImageUsage{
+			{Name: "localhost/l8s-fedora:latest", Size: 900, UniqueSize: 900, Containers: 1},
+			{Name: "localhost/l8s-fedora-ml:latest", Size: 1500, UniqueSize: 600, Containers: 1},
+			{Name: "", Size: 800, UniqueSize: 300},
+			{Name: "", Size: 800, UniqueSize: 200, Containers: 1},
+			{Name: "docker.io/library/postgres:16", Size: 400, UniqueSize: 400},
+		},
+		Containers: []ContainerUsage{{Name: "dev-small", Size: 5}, {Name: "dev-big", Size: 50}, {Name: "postgres", Size: 1}},
+		Volumes: []VolumeUsage{
+			{Name: "dev-small-home", Size: 10, InUse: true},
+			{Name: "dev-small-workspace", Size: 20, InUse: true},
+			{Name: "dev-big-home", Size: 100, InUse: true},
+			{Name: "dev-big-workspace", Size: 200, InUse: true},
+			{Name: "dev-nix-store", Size: 70, InUse: true},
+			{Name: "dev-gone-home", Size: 30},
+			{Name: "l8s-trash.1760000000.dev-old-home", Size: 40},
+			{Name: "pgdata", Size: 90},
+		},
+	}, nil)
+	client.On("ListContainers", mock.Anything).Return([]*Container{
+		{Name: "dev-small", Status: "running"},
+		{Name: "dev-big", Status: "exited"},
+	}, nil)
+
+	report, err := manager.DiskUsage(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Images, 2)
+	assert.Equal(t, "localhost/l8s-fedora-ml:latest", report.Images[1].Name)
+	require.Len(t, report.Containers, 2)
+	assert.Equal(t, "dev-big", report.Containers[0].Name)
+	assert.Equal(t, int64(350), report.Containers[0].Total())
+	assert.Equal(t, int64(35), report.Containers[1].Total())
+	assert.Equal(t, []VolumeUsage{{Name: "dev-nix-store", Size: 70, InUse: true}}, report.Shared)
+	assert.Equal(t, []ImageUsage{{Size: 800, UniqueSize: 300}}, report.Dangling)
+	assert.Equal(t, []VolumeUsage{{Name: "dev-gone-home", Size: 30}}, report.Unused)
+	assert.Equal(t, []VolumeUsage{{Name: "l8s-trash.1760000000.dev-old-home", Size: 40}}, report.Trash)
+	assert.Equal(t, int64(370), report.Reclaimable())
+}
@@ -0,0 +1,426 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// fakeState is the simulated host persisted between invocations
+type fakeState struct {
+	Containers map[string]*Container `json:"containers"`
+	Volumes    []string              `json:"volumes"`
+}
+
+// FakePodmanClient simulates a Podman host in a local JSON file so the CLI
+// can be demoed and tested end to end without a remote server. Containers
+// only exist as records: exec succeeds without running anything and there
+// is no sshd to connect to.
+type FakePodmanClient struct {
+	mu   sync.Mutex
+	path string
+	now  func() time.Time
+}
+
+// NewFakePodmanClient returns a fake runtime backed by the state file at path
+func NewFakePodmanClient(path string) *FakePodmanClient {
+	return &FakePodmanClient{path: path, now: time.Now}
+}
+
+func (c *FakePodmanClient) load() (*fakeState, error) {
+	state := &fakeState{Containers: make(map[string]*Container)}
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fake runtime state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse fake runtime state %s: %w", c.path, err)
+	}
+	if state.Containers == nil {
+		state.Containers = make(map[string]*Container)
+	}
+	return state, nil
+}
+
+func (c *FakePodmanClient) save(state *fakeState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fake runtime state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write fake runtime state: %w", err)
+	}
+	return nil
+}
+
+// update loads the state, applies fn and saves the result if fn succeeds
+func (c *FakePodmanClient) update(fn func(*fakeState) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	state, err := c.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(state); err != nil {
+		return err
+	}
+	return c.save(state)
+}
+
+// view loads the state for reading
+func (c *FakePodmanClient) view() (*fakeState, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.load()
+}
+
+func (c *FakePodmanClient) lookup(state *fakeState, name string) (*Container, error) {
+	cont, ok := state.Containers[name]
+	if !ok {
+		return nil, fmt.Errorf("no container with name or ID %q found: no such container", name)
+	}
+	return cont, nil
+}
+
+func (c *FakePodmanClient) ContainerExists(ctx context.Context, name string) (bool, error) {
+	state, err := c.view()
+	if err != nil {
+		return false, err
+	}
+	_, ok := state.Containers[name]
+	return ok, nil
+}
+
+func (c *FakePodmanClient) CreateContainer(ctx context.Context, config ContainerConfig) (*Container, error) {
+	cont := &Container{
+		Name:      config.Name,
+		Status:    "created",
+		SSHPort:   config.SSHPort,
+		WebPort:   config.WebPort,
+		CreatedAt: c.now().UTC(),
+		Labels:    config.Labels,
+		Image:     config.BaseImage,
+	}
+	volumeName := config.VolumeName
+	if volumeName == "" {
+		volumeName = config.Name
+	}
+
+	err := c.update(func(state *fakeState) error {
+		if _, exists := state.Containers[config.Name]; exists {
+			return fmt.Errorf("the container name %q is already in use", config.Name)
+		}
+		state.Containers[config.Name] = cont
+		for _, volume := range []string{volumeName + "-home", volumeName + "-workspace"} {
+			state.Volumes = addVolume(state.Volumes, volume)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cont, nil
+}
+
+func (c *FakePodmanClient) setStatus(name, status string) error {
+	return c.update(func(state *fakeState) error {
+		cont, err := c.lookup(state, name)
+		if err != nil {
+			return err
+		}
+		cont.Status = status
+		switch status {
+		case "running":
+			cont.StartedAt = c.now().UTC()
+		case "exited":
+			// Simulated containers only stop when asked to, and cleanly
+			cont.FinishedAt = c.now().UTC()
+			cont.ExitCode = 0
+			cont.StoppedByUser = true
+		}
+		return nil
+	})
+}
+
+func (c *FakePodmanClient) StartContainer(ctx context.Context, name string) error {
+	return c.setStatus(name, "running")
+}
+
+func (c *FakePodmanClient) StopContainer(ctx context.Context, name string) error {
+	return c.setStatus(name, "exited")
+}
+
+func (c *FakePodmanClient) RemoveContainer(ctx context.Context, name string, removeVolumes bool) error {
+	return c.update(func(state *fakeState) error {
+		if _, err := c.lookup(state, name); err != nil {
+			return err
+		}
+		delete(state.Containers, name)
+		if removeVolumes {
+			state.Volumes = removeVolume(state.Volumes, name+"-home")
+			state.Volumes = removeVolume(state.Volumes, name+"-workspace")
+		}
+		return nil
+	})
+}
+
+func (c *FakePodmanClient) ListContainers(ctx context.Context) ([]*Container, error) {
+	state, err := c.view()
+	if err != nil {
+		return nil, err
+	}
+	var result []*Container
+	for _, cont := range state.Containers {
+		if cont.Labels[LabelManaged] == "true" {
+			result = append(result, cont)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+func (c *FakePodmanClient) GetContainerInfo(ctx context.Context, name string) (*Container, error) {
+	state, err := c.view()
+	if err != nil {
+		return nil, err
+	}
+	cont, err := c.lookup(state, name)
+	if err != nil {
+		return nil, err
+	}
+	if cont.Labels[LabelManaged] != "true" {
+		return nil, fmt.Errorf("container '%s' is not managed by l8s", name)
+	}
+	return cont, nil
+}
+
+// InspectContainer returns the simulated container record, which has far
+// fewer fields than a real podman inspect document
+func (c *FakePodmanClient) InspectContainer(ctx context.Context, name string) ([]byte, error) {
+	cont, err := c.GetContainerInfo(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(cont, "", "    ")
+}
+
+func (c *FakePodmanClient) FindAvailablePort(startPort int) (int, error) {
+	state, err := c.view()
+	if err != nil {
+		return 0, err
+	}
+	inUse := make(map[int]bool)
+	for _, cont := range state.Containers {
+		inUse[cont.SSHPort] = true
+		inUse[cont.WebPort] = true
+	}
+	for port := startPort; port < startPort+1000; port++ {
+		if !inUse[port] {
+			return port, nil
+		}
+	}
+	return 0, &PortExhaustedError{Ranges: [][2]int{{startPort, startPort + 999}}}
+}
+
+// running fails unless the container exists and is running, like podman exec
+func (c *FakePodmanClient) running(name string) error {
+	state, err := c.view()
+	if err != nil {
+		return err
+	}
+	cont, err := c.lookup(state, name)
+	if err != nil {
+		return err
+	}
+	if cont.Status != "running" {
+		return fmt.Errorf("can only create exec sessions on running containers: container state improper")
+	}
+	return nil
+}
+
+func (c *FakePodmanClient) ExecContainer(ctx context.Context, name string, cmd []string) error {
+	return c.running(name)
+}
+
+func (c *FakePodmanClient) ExecContainerOutput(ctx context.Context, name string, cmd []string) (string, error) {
+	return "", c.running(name)
+}
+
+func (c *FakePodmanClient) ExecContainerStream(ctx context.Context, name string, cmd []string, stdout, stderr io.Writer) error {
+	return c.running(name)
+}
+
+func (c *FakePodmanClient) ExecContainerWithInput(ctx context.Context, name string, cmd []string, input string) error {
+	return c.running(name)
+}
+
+func (c *FakePodmanClient) CopyToContainer(ctx context.Context, name string, src, dst string) error {
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	return c.running(name)
+}
+
+func (c *FakePodmanClient) RenameContainer(ctx context.Context, name, newName string) error {
+	return c.update(func(state *fakeState) error {
+		cont, err := c.lookup(state, name)
+		if err != nil {
+			return err
+		}
+		if _, exists := state.Containers[newName]; exists {
+			return fmt.Errorf("the container name %q is already in use", newName)
+		}
+		delete(state.Containers, name)
+		cont.Name = newName
+		state.Containers[newName] = cont
+		return nil
+	})
+}
+
+func (c *FakePodmanClient) CopyVolume(ctx context.Context, src, dst string) error {
+	return c.update(func(state *fakeState) error {
+		if !hasVolume(state.Volumes, src) {
+			return fmt.Errorf("no such volume %s", src)
+		}
+		state.Volumes = addVolume(state.Volumes, dst)
+		return nil
+	})
+}
+
+func (c *FakePodmanClient) RemoveVolume(ctx context.Context, name string) error {
+	return c.update(func(state *fakeState) error {
+		state.Volumes = removeVolume(state.Volumes, name)
+		return nil
+	})
+}
+
+// ExportVolume writes nothing; simulated volumes have no contents
+func (c *FakePodmanClient) ExportVolume(ctx context.Context, name string, w io.Writer) error {
+	state, err := c.view()
+	if err != nil {
+		return err
+	}
+	if !hasVolume(state.Volumes, name) {
+		return fmt.Errorf("no such volume %s", name)
+	}
+	return nil
+}
+
+func (c *FakePodmanClient) ListVolumes(ctx context.Context) ([]string, error) {
+	state, err := c.view()
+	if err != nil {
+		return nil, err
+	}
+	return append([]string(nil), state.Volumes...), nil
+}
+
+// DiffContainer reports no changes; simulated containers have no filesystem
+func (c *FakePodmanClient) DiffContainer(ctx context.Context, name string) ([]FileChange, error) {
+	return nil, c.running(name)
+}
+
+// DiskUsage reports the simulated containers and volumes, which take no space
+func (c *FakePodmanClient) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	state, err := c.view()
+	if err != nil {
+		return nil, err
+	}
+	usage := &DiskUsage{}
+	inUse := make(map[string]bool)
+	for name := range state.Containers {
+		usage.Containers = append(usage.Containers, ContainerUsage{Name: name})
+		for _, volume := range containerVolumes(name) {
+			inUse[volume] = true
+		}
+	}
+	sort.Slice(usage.Containers, func(i, j int) bool { return usage.Containers[i].Name < usage.Containers[j].Name })
+	for _, volume := range state.Volumes {
+		usage.Volumes = append(usage.Volumes, VolumeUsage{Name: volume, InUse: inUse[volume]})
+	}
+	return usage, nil
+}
+
+// RunImage prints nothing; simulated images have no contents
+func (c *FakePodmanClient) RunImage(ctx context.Context, image string, cmd []string) (string, error) {
+	return "", nil
+}
+
+// DescribeContainer returns any simulated container, managed by l8s or not
+func (c *FakePodmanClient) DescribeContainer(ctx context.Context, name string) (*ForeignContainer, error) {
+	state, err := c.view()
+	if err != nil {
+		return nil, err
+	}
+	cont, err := c.lookup(state, name)
+	if err != nil {
+		return nil, err
+	}
+	return &ForeignContainer{
+		Name:    cont.Name,
+		Image:   cont.Image,
+		Status:  cont.Status,
+		SSHPort: cont.SSHPort,
+		WebPort: cont.WebPort,
+		Labels:  cont.Labels,
+	}, nil
+}
+
+// CommitContainer records nothing; simulated containers have no filesystem
+func (c *FakePodmanClient) CommitContainer(ctx context.Context, name, image string) error {
+	state, err := c.view()
+	if err != nil {
+		return err
+	}
+	_, err = c.lookup(state, name)
+	return err
+}
+
+// RemoveImage does nothing; CommitContainer keeps no images
+func (c *FakePodmanClient) RemoveImage(ctx context.Context, image string) error {
+	return nil
+}
+
+func (c *FakePodmanClient) Version(ctx context.Context) (*RemoteVersion, error) {
+	return &RemoteVersion{Podman: "5.0.0-fake", APIVersion: "5.0.0", MinAPIVersion: "4.0.0", OSArch: "fake/fake"}, nil
+}
+
+func hasVolume(volumes []string, name string) bool {
+	for _, v := range volumes {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+func addVolume(volumes []string, name string) []string {
+	if hasVolume(volumes, name) {
+		return volumes
+	}
+	volumes = append(volumes, name)
+	sort.Strings(volumes)
+	return volumes
+}
+
+func removeVolume(volumes []string, name string) []string {
+	for i, v := range volumes {
+		if v == name {
+			return append(volumes[:i], volumes[i+1:]...)
+		}
+	}
+	return volumes
+}
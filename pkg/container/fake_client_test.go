@@ -0,0 +1,66 @@
+package container
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakePodmanClientLifecycle(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state.json")
+	client := NewFakePodmanClient(path)
+
+	_, err := client.CreateContainer(ctx, ContainerConfig{
+		Name:    "dev-demo",
+		SSHPort: 2200,
+		WebPort: 3000,
+		Labels:  map[string]string{LabelManaged: "true"},
+	})
+	require.NoError(t, err)
+	_, err = client.CreateContainer(ctx, ContainerConfig{Name: "dev-demo"})
+	assert.Error(t, err)
+
+	// A new client sees the same state, as a later l8s invocation would
+	client = NewFakePodmanClient(path)
+	port, err := client.FindAvailablePort(2200)
+	require.NoError(t, err)
+	assert.Equal(t, 2201, port)
+
+	assert.Error(t, client.ExecContainer(ctx, "dev-demo", []string{"true"}), "exec needs a running container")
+	require.NoError(t, client.StartContainer(ctx, "dev-demo"))
+	assert.NoError(t, client.ExecContainer(ctx, "dev-demo", []string{"true"}))
+
+	cont, err := client.GetContainerInfo(ctx, "dev-demo")
+	require.NoError(t, err)
+	assert.Equal(t, "running", cont.Status)
+	assert.Equal(t, 2200, cont.SSHPort)
+	assert.False(t, cont.StartedAt.IsZero())
+
+	require.NoError(t, client.StopContainer(ctx, "dev-demo"))
+	cont, err = client.GetContainerInfo(ctx, "dev-demo")
+	require.NoError(t, err)
+	assert.Equal(t, "exited", cont.Status)
+	assert.False(t, cont.FinishedAt.IsZero())
+	assert.True(t, cont.StoppedByUser)
+
+	volumes, err := client.ListVolumes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev-demo-home", "dev-demo-workspace"}, volumes)
+
+	require.NoError(t, client.RenameContainer(ctx, "dev-demo", "dev-renamed"))
+	list, err := client.ListContainers(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "dev-renamed", list[0].Name)
+
+	require.NoError(t, client.RemoveContainer(ctx, "dev-renamed", false))
+	exists, err := client.ContainerExists(ctx, "dev-renamed")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	_, err = client.GetContainerInfo(ctx, "dev-renamed")
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"l8s/pkg/logging"
+)
+
+// RemnantContainer is a container left behind by a failed create or rebuild
+type RemnantContainer struct {
+	Name   string // Full container name
+	Reason string
+}
+
+// Remnants are what interrupted or failed operations left on the podman host
+type Remnants struct {
+	Containers []RemnantContainer
+	Volumes    []string // Container volumes with no container to mount them
+}
+
+// Empty reports whether there is nothing to clean up
+func (r *Remnants) Empty() bool {
+	return len(r.Containers) == 0 && len(r.Volumes) == 0
+}
+
+// removeNewVolumes removes the volumes of a container that were not in
+// existing, undoing a failed create without touching reused volumes
+func (m *Manager) removeNewVolumes(ctx context.Context, containerName string, existing []string) error {
+	current, err := m.client.ListVolumes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list volumes: %w", err)
+	}
+	var errs []error
+	for _, volume := range containerVolumes(containerName) {
+		if slices.Contains(current, volume) && !slices.Contains(existing, volume) {
+			m.logger.Debug("removing volume", logging.WithField("volume", volume))
+			if err := m.client.RemoveVolume(ctx, volume); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FindRemnants looks for containers that never started, blue/green
+// replacements that were never swapped in, and volumes whose container is
+// gone. Volumes of a remnant container count as remnants too. The trash is
+// left alone; it is purged after trash_retention_days.
+func (m *Manager) FindRemnants(ctx context.Context) (*Remnants, error) {
+	containers, err := m.client.ListContainers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	volumes, err := m.client.ListVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		names[c.Name] = true
+	}
+	remnants := &Remnants{}
+	kept := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		switch {
+		case strings.HasSuffix(c.Name, blueGreenSuffix) && names[strings.TrimSuffix(c.Name, blueGreenSuffix)]:
+			// A replacement is only a remnant while the container it replaces
+			// still exists; otherwise it may hold the only copy of the work
+			remnants.Containers = append(remnants.Containers, RemnantContainer{Name: c.Name, Reason: "blue/green replacement never swapped in"})
+		case c.Status == "created":
+			remnants.Containers = append(remnants.Containers, RemnantContainer{Name: c.Name, Reason: "never started"})
+		default:
+			kept[c.Name] = true
+		}
+	}
+	sort.Slice(remnants.Containers, func(i, j int) bool { return remnants.Containers[i].Name < remnants.Containers[j].Name })
+
+	for _, volume := range volumes {
+		if !strings.HasPrefix(volume, m.config.ContainerPrefix+"-") {
+			continue
+		}
+		for _, suffix := range volumeSuffixes {
+			// A blue/green replacement mounts the volumes of the container it replaces
+			if owner, ok := strings.CutSuffix(volume, suffix); ok && !kept[owner] && !kept[owner+blueGreenSuffix] {
+				remnants.Volumes = append(remnants.Volumes, volume)
+				break
+			}
+		}
+	}
+	sort.Strings(remnants.Volumes)
+	return remnants, nil
+}
+
+// RemoveRemnants removes what FindRemnants found, continuing past failures.
+// Containers are removed before the volumes they mount.
+func (m *Manager) RemoveRemnants(ctx context.Context, remnants *Remnants) error {
+	var errs []error
+	for _, c := range remnants.Containers {
+		if err := m.client.RemoveContainer(ctx, c.Name, false); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Name, err))
+		}
+	}
+	for _, volume := range remnants.Volumes {
+		if err := m.client.RemoveVolume(ctx, volume); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", volume, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,58 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateContainerRollsBack(t *testing.T) {
+	client := new(MockPodmanClient)
+	client.On("ContainerExists", mock.Anything, "dev-myproject").Return(false, nil)
+	client.On("FindAvailablePort", 2200).Return(2200, nil)
+	client.On("FindAvailablePort", 3000).Return(3000, nil)
+	// The home volume was kept by an earlier 'l8s remove --keep-volumes'
+	client.On("ListVolumes", mock.Anything).Return([]string{"dev-myproject-home"}, nil).Once()
+	client.On("CreateContainer", mock.Anything, mock.Anything).Return(&Container{Name: "dev-myproject"}, nil)
+	client.On("StartContainer", mock.Anything, "dev-myproject").Return(assert.AnError)
+	client.On("RemoveContainer", mock.Anything, "dev-myproject", false).Return(nil)
+	client.On("ListVolumes", mock.Anything).Return([]string{"dev-myproject-home", "dev-myproject-workspace"}, nil).Once()
+	client.On("RemoveVolume", mock.Anything, "dev-myproject-workspace").Return(nil)
+
+	manager := NewManager(client, Config{ContainerPrefix: "dev", SSHPortStart: 2200, WebPortStart: 3000, ContainerUser: "dev"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // Cleanup still runs after Ctrl-C
+	_, err := manager.CreateContainer(ctx, "myproject", "ssh-key", CreateOptions{})
+	require.ErrorContains(t, err, "failed to start container")
+	client.AssertExpectations(t)
+	client.AssertNotCalled(t, "RemoveVolume", mock.Anything, "dev-myproject-home")
+}
+
+func TestFindRemnants(t *testing.T) {
+	client := new(MockPodmanClient)
+	client.On("ListContainers", mock.Anything).Return([]*Container{
+		{Name: "dev-app", Status: "running"},
+		{Name: "dev-app-next", Status: "exited"},
+		{Name: "dev-half", Status: "created"},
+		{Name: "dev-solo-next", Status: "running"},
+	}, nil)
+	client.On("ListVolumes", mock.Anything).Return([]string{
+		"dev-app-home", "dev-app-workspace",
+		"dev-half-home", "dev-half-workspace",
+		"dev-solo-home", "dev-solo-workspace",
+		"dev-gone-home", "dev-nix-store", "l8s-trash.1760000000.dev-old-home", "pgdata",
+	}, nil)
+	manager := NewManager(client, Config{ContainerPrefix: "dev"})
+
+	remnants, err := manager.FindRemnants(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []RemnantContainer{
+		{Name: "dev-app-next", Reason: "blue/green replacement never swapped in"},
+		{Name: "dev-half", Reason: "never started"},
+	}, remnants.Containers)
+	// dev-solo-next is all that is left of dev-solo, so its volumes stay
+	assert.Equal(t, []string{"dev-gone-home", "dev-half-home", "dev-half-workspace"}, remnants.Volumes)
+}
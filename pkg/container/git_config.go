@@ -47,6 +47,47 @@ func ReadHostGitIdentity() (GitIdentity, error) {
 	}, nil
 }
 
+// Override returns the identity with the non-empty fields of o replacing its own
+func (g GitIdentity) Override(o GitIdentity) GitIdentity {
+	if o.Name != "" {
+		g.Name = o.Name
+	}
+	if o.Email != "" {
+		g.Email = o.Email
+	}
+	return g
+}
+
+// GitIdentityLabels records a repository's identity override on a container
+// so rebuilds, which cannot read its .l8s.yaml, keep applying it
+func GitIdentityLabels(g GitIdentity) map[string]string {
+	labels := make(map[string]string)
+	if g.Name != "" {
+		labels[LabelGitName] = g.Name
+	}
+	if g.Email != "" {
+		labels[LabelGitEmail] = g.Email
+	}
+	return labels
+}
+
+// GitIdentityFromLabels returns the identity override recorded on a container
+func GitIdentityFromLabels(labels map[string]string) GitIdentity {
+	return GitIdentity{Name: labels[LabelGitName], Email: labels[LabelGitEmail]}
+}
+
+// labelCreateOptions returns the git identity, signing, packages and
+// toolchain recorded on a container being rebuilt, for its dotfiles to be
+// copied again. The signing key itself is already in the container's home volume.
+func labelCreateOptions(labels map[string]string) CreateOptions {
+	return CreateOptions{
+		GitIdentity: GitIdentityFromLabels(labels),
+		Signing:     SigningFromLabels(labels),
+		Packages:    ParsePackages(labels[LabelPackages]),
+		Toolchain:   labels[LabelToolchain],
+	}
+}
+
 // escapeShellArg escapes a string for use in shell commands
 func escapeShellArg(s string) string {
 	// Replace single quotes with '\''
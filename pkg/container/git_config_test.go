@@ -244,4 +244,16 @@ func TestApplyGitConfigToContainer(t *testing.T) {
 			mockClient.AssertExpectations(t)
 		})
 	}
-}
\ No newline at end of file
+}
+func TestGitIdentityOverride(t *testing.T) {
+	host := GitIdentity{Name: "Jane Doe", Email: "jane@work.example.com"}
+	assert.Equal(t, host, host.Override(GitIdentity{}))
+	assert.Equal(t, GitIdentity{Name: "Jane Doe", Email: "jane@oss.example.org"},
+		host.Override(GitIdentity{Email: "jane@oss.example.org"}))
+
+	override := GitIdentity{Name: "jd", Email: "jd@oss.example.org"}
+	labels := GitIdentityLabels(override)
+	assert.Equal(t, map[string]string{LabelGitName: "jd", LabelGitEmail: "jd@oss.example.org"}, labels)
+	assert.Equal(t, override, GitIdentityFromLabels(labels))
+	assert.Empty(t, GitIdentityLabels(GitIdentity{}))
+}
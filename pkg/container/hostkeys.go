@@ -0,0 +1,64 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"l8s/pkg/logging"
+)
+
+// hostKeyDir is where host keys are kept in the home volume, relative to the
+// container user's home
+const hostKeyDir = ".l8s/host-keys"
+
+// hostKeyScript saves the container's host keys to the home volume on first
+// start and restores them on later starts, then has sshd reload them if they
+// changed. Prints "saved", "restored" or "unchanged".
+const hostKeyScript = `set -e
+dir=%s
+if ! ls "$dir"/ssh_host_*_key >/dev/null 2>&1; then
+	mkdir -p "$dir"
+	cp -p /etc/ssh/ssh_host_*_key /etc/ssh/ssh_host_*_key.pub "$dir"/
+	chmod 700 "$dir"
+	echo saved
+	exit 0
+fi
+changed=
+for key in "$dir"/ssh_host_*_key; do
+	name=$(basename "$key")
+	if ! cmp -s "$key" /etc/ssh/"$name"; then
+		install -m 600 -o root -g root "$key" /etc/ssh/"$name"
+		install -m 644 -o root -g root "$key".pub /etc/ssh/"$name".pub
+		changed=1
+	fi
+done
+if [ -z "$changed" ]; then
+	echo unchanged
+	exit 0
+fi
+if [ "$(cat /proc/1/comm)" = systemd ]; then
+	systemctl reload sshd 2>/dev/null || systemctl reload ssh
+else
+	pkill -HUP -o -x sshd
+fi
+echo restored
+`
+
+// persistHostKeys keeps a container's SSH host keys stable across rebuilds by
+// keeping them in the home volume. Without an SSH CA the keys come from the
+// image and would change with every image build, so clients would see host key
+// mismatches after each rebuild. With a CA, certificates are re-signed instead.
+func (m *Manager) persistHostKeys(ctx context.Context, containerName string) error {
+	if m.config.CAPrivateKeyPath != "" && m.config.CAPublicKeyPath != "" {
+		return nil
+	}
+	dir := fmt.Sprintf("/home/%s/%s", m.config.ContainerUser, hostKeyDir)
+	out, err := m.client.ExecContainerOutput(ctx, containerName, []string{"sh", "-c", fmt.Sprintf(hostKeyScript, dir)})
+	if err != nil {
+		return fmt.Errorf("failed to persist host keys: %w", err)
+	}
+	m.logger.Debug("host keys "+strings.TrimSpace(out),
+		logging.WithField("container", containerName))
+	return nil
+}
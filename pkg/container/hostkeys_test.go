@@ -0,0 +1,41 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestManager_PersistHostKeys(t *testing.T) {
+	t.Run("keeps keys in the home volume", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("ExecContainerOutput", mock.Anything, "dev-app", mock.MatchedBy(func(cmd []string) bool {
+			return len(cmd) == 3 && strings.Contains(cmd[2], "dir=/home/dev/.l8s/host-keys")
+		})).Return("restored\n", nil)
+
+		manager := NewManager(m, Config{ContainerPrefix: "dev", ContainerUser: "dev"})
+		assert.NoError(t, manager.persistHostKeys(context.Background(), "dev-app"))
+		m.AssertExpectations(t)
+	})
+
+	t.Run("skipped with an SSH CA", func(t *testing.T) {
+		m := new(MockPodmanClient)
+
+		manager := NewManager(m, Config{ContainerPrefix: "dev", ContainerUser: "dev",
+			CAPrivateKeyPath: "/ca/ca_key", CAPublicKeyPath: "/ca/ca_key.pub"})
+		assert.NoError(t, manager.persistHostKeys(context.Background(), "dev-app"))
+		m.AssertNotCalled(t, "ExecContainerOutput", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("exec failure", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("ExecContainerOutput", mock.Anything, "dev-app", mock.Anything).Return("", errors.New("exit status 1"))
+
+		manager := NewManager(m, Config{ContainerPrefix: "dev", ContainerUser: "dev"})
+		assert.ErrorContains(t, manager.persistHostKeys(context.Background(), "dev-app"), "persist host keys")
+	})
+}
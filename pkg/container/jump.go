@@ -0,0 +1,112 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"l8s/pkg/config"
+)
+
+// hostSSHArgs returns ssh arguments that run command on target, going through
+// the jump host when one is configured
+func hostSSHArgs(jump, target string, command ...string) []string {
+	var args []string
+	if jump != "" {
+		args = append(args, "-J", jump)
+	}
+	return append(append(args, target), command...)
+}
+
+// jumpSocket serves a local unix socket that reaches the remote Podman API
+// through a jump host, for hosts the Podman bindings cannot dial directly.
+// Each connection to the socket runs 'podman system dial-stdio' on the host
+// over 'ssh -J', so nothing outlives the connections made through it.
+func jumpSocket(jump, target, remoteSocket string) (string, error) {
+	dir := filepath.Join(config.StateDir(), "jump")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create jump socket directory: %w", err)
+	}
+	pruneStaleSockets(dir)
+
+	path := filepath.Join(dir, fmt.Sprintf("%d.sock", os.Getpid()))
+	_ = os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return "", fmt.Errorf("failed to listen on jump socket: %w", err)
+	}
+
+	args := hostSSHArgs(jump, target, "podman", "--url", "unix://"+remoteSocket, "system", "dial-stdio")
+	go serveJump(listener, "ssh", append([]string{"-o", "BatchMode=yes", "-o", "LogLevel=ERROR"}, args...))
+	return path, nil
+}
+
+// serveJump bridges each connection on listener to the stdin and stdout of a
+// new name args process until the listener is closed
+func serveJump(listener net.Listener, name string, args []string) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			cmd := exec.Command(name, args...)
+			cmd.Stdin = conn
+			cmd.Stdout = conn
+			cmd.Stderr = os.Stderr // Authentication and host key errors are the user's to see
+			// Do not wait for the client to hang up once the process is gone
+			cmd.WaitDelay = time.Second
+			_ = cmd.Run()
+		}()
+	}
+}
+
+// pruneStaleSockets removes jump sockets left behind by l8s processes that
+// have exited; a socket nobody answers on belongs to none
+func pruneStaleSockets(dir string) {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.sock"))
+	for _, path := range paths {
+		conn, err := net.DialTimeout("unix", path, 100*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			continue
+		}
+		_ = os.Remove(path)
+	}
+}
+
+// readSSHBannerVia connects to address through the jump host with 'ssh -W'
+// and reports whether an SSH server answers there
+func readSSHBannerVia(ctx context.Context, jump, address string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "ssh", "-o", "BatchMode=yes", "-o", "LogLevel=ERROR", "-W", address, jump)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	banner, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("no answer from %s via %s: %w", address, jump, err)
+	}
+	if !strings.HasPrefix(banner, "SSH-") {
+		return fmt.Errorf("unexpected response from %s", address)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package container
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostSSHArgs(t *testing.T) {
+	assert.Equal(t, []string{"dev@10.0.0.5", "podman", "ps"}, hostSSHArgs("", "dev@10.0.0.5", "podman", "ps"))
+	assert.Equal(t, []string{"-J", "me@bastion", "dev@10.0.0.5", "podman", "ps"}, hostSSHArgs("me@bastion", "dev@10.0.0.5", "podman", "ps"))
+}
+
+func TestServeJump(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jump.sock")
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// cat stands in for ssh running dial-stdio: bytes go to the process and come back
+	go serveJump(listener, "cat", nil)
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("GET /_ping"))
+	require.NoError(t, err)
+	conn.(*net.UnixConn).CloseWrite()
+
+	reply, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Equal(t, "GET /_ping", string(reply))
+}
+
+func TestPruneStaleSockets(t *testing.T) {
+	dir := t.TempDir()
+
+	live, err := net.Listen("unix", filepath.Join(dir, "1.sock"))
+	require.NoError(t, err)
+	defer live.Close()
+
+	stale, err := net.Listen("unix", filepath.Join(dir, "2.sock"))
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	pruneStaleSockets(dir)
+
+	assert.FileExists(t, filepath.Join(dir, "1.sock"))
+	_, err = os.Stat(filepath.Join(dir, "2.sock"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -3,6 +3,7 @@ package container
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/fs"
 	"log/slog"
 	"os"
@@ -13,8 +14,10 @@ import (
 	"time"
 
 	"l8s/pkg/cleanup"
+	"l8s/pkg/config"
 	"l8s/pkg/embed"
 	"l8s/pkg/logging"
+	"l8s/pkg/progress"
 	"l8s/pkg/ssh"
 )
 
@@ -23,11 +26,39 @@ type Manager struct {
 	client PodmanClient
 	config Config
 	logger *slog.Logger
-	cliDotfilesPath string
+	buildFlavor  string
+	buildOptions BuildOptions
+	buildStats   BuildStats
+	imageVariant string // Variant the next build is for
+}
+
+// CreateOptions are the settings of one container beyond the configured
+// defaults, typically from 'l8s create' flags and the repository's
+// .l8s.yaml. The zero value creates a container from the configuration alone.
+// Settings a rebuild needs are recorded in the container's labels.
+type CreateOptions struct {
+	DotfilesPath string          // --dotfiles-path; wins over the configured dotfiles
+	ImageVariant string          // Configured image variant to create from
+	Mounts       []Mount         // Bind mounts declared by the repository
+	Runtime      *RuntimeOptions // Replaces the configured runtime options
+	Ticket       string          // Ticket the container is for
+	Worktree     string          // Host worktree the container is for
+	Subdir       string          // Monorepo subdirectory shells and commands start in
+	Sparse       []string        // Further directories the working tree is limited to
+	OriginURL    string          // Remote the container's repository tracks as origin
+	OriginAuth   string          // config.OriginAuth* mode origin authenticates with
+	GitIdentity  GitIdentity     // Overrides the host's git identity
+	Signing      Signing         // Commit signing; an empty Mode signs nothing
+	SigningKey   []byte          // Private key to install in key mode
+	Packages     Packages        // Packages installed once the container starts
+	Toolchain    string          // Toolchain manager to provision
 }
 
 // NewManager creates a new container manager
 func NewManager(client PodmanClient, config Config) *Manager {
+	if logging.TraceEnabled() {
+		client = &tracingClient{client: client}
+	}
 	return &Manager{
 		client: client,
 		config: config,
@@ -36,20 +67,29 @@ func NewManager(client PodmanClient, config Config) *Manager {
 }
 
 // CreateContainer creates a new development container
-func (m *Manager) CreateContainer(ctx context.Context, name, sshKey string) (*Container, error) {
+func (m *Manager) CreateContainer(ctx context.Context, name, sshKey string, opts CreateOptions) (_ *Container, err error) {
 	// Create cleanup handler
 	cleaner := cleanup.New(m.logger)
+	op := progress.Start("create", 6)
+	defer func() {
+		op.Done(err)
+	}()
+	// Any failure undoes everything created so far. Cleanup still runs when
+	// ctx was cancelled, e.g. by Ctrl-C, which is often why creation failed.
+	cleanupCtx := context.WithoutCancel(ctx)
+	defer cleaner.CleanupOnError(cleanupCtx, &err)
 	defer func() {
 		if err := recover(); err != nil {
 			m.logger.Error("panic during container creation",
 				logging.WithField("panic", err),
 				logging.WithField("container", name))
-			cleaner.Cleanup(ctx)
+			cleaner.Cleanup(cleanupCtx)
 			panic(err)
 		}
 	}()
 
 	// Validate container name
+	op.Step("allocate", "Checking name, quota and ports")
 	if err := validateContainerName(name); err != nil {
 		return nil, err
 	}
@@ -64,15 +104,21 @@ func (m *Manager) CreateContainer(ctx context.Context, name, sshKey string) (*Co
 		return nil, fmt.Errorf("container '%s' already exists", name)
 	}
 
+	if err := m.checkQuota(ctx); err != nil {
+		return nil, err
+	}
 
 	// Find available SSH port
 	sshPort, err := m.client.FindAvailablePort(m.config.SSHPortStart)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find available SSH port: %w", err)
 	}
+	if err := m.checkPortBudget(sshPort); err != nil {
+		return nil, err
+	}
 
 	// Find available web port with consistent offset from SSH port
-	webPortOffset := sshPort - m.config.SSHPortStart
+	webPortOffset := m.portOffset(sshPort)
 	webPort, err := m.client.FindAvailablePort(m.config.WebPortStart + webPortOffset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find available web port: %w", err)
@@ -89,7 +135,7 @@ func (m *Manager) CreateContainer(ctx context.Context, name, sshKey string) (*Co
 		SSHPort:       sshPort,
 		WebPort:       webPort,
 		SSHPublicKey:  sshKey,
-		BaseImage:     m.config.BaseImage,
+		BaseImage:     m.imageFor(opts.ImageVariant),
 		ContainerUser: m.config.ContainerUser,
 		AudioEnabled:  m.config.AudioEnabled,
 		AudioPort:     m.config.AudioPort,
@@ -99,17 +145,65 @@ func (m *Manager) CreateContainer(ctx context.Context, name, sshKey string) (*Co
 			LabelWebPort:   fmt.Sprintf("%d", webPort),
 		},
 	}
+	for k, v := range ownerLabels(sshKey) {
+		config.Labels[k] = v
+	}
+	if opts.Ticket != "" {
+		config.Labels[LabelTicket] = opts.Ticket
+	}
+	if opts.Subdir != "" {
+		config.Labels[LabelSubdir] = opts.Subdir
+	}
+	if opts.Worktree != "" {
+		config.Labels[LabelWorktree] = opts.Worktree
+	}
+	if opts.OriginURL != "" {
+		config.Labels[LabelOrigin] = opts.OriginAuth
+	}
+	for k, v := range GitIdentityLabels(opts.GitIdentity) {
+		config.Labels[k] = v
+	}
+	for k, v := range SigningLabels(opts.Signing) {
+		config.Labels[k] = v
+	}
+	if !opts.Packages.Empty() {
+		config.Labels[LabelPackages] = FormatPackages(opts.Packages)
+	}
+	if opts.Toolchain != "" {
+		config.Labels[LabelToolchain] = opts.Toolchain
+	}
+	if opts.ImageVariant != "" {
+		config.Labels[LabelImageVariant] = opts.ImageVariant
+	}
+	m.applyResourceLimits(&config)
+	m.applyMounts(&config, opts)
+	m.applyRuntimeOptions(&config, opts.Runtime)
+	m.applyProxy(&config)
+
+	// podman creates the volumes before the container, so a failed create can
+	// leave them behind. Volumes that already existed, e.g. kept by
+	// 'l8s remove --keep-volumes', are reused and never removed.
+	if existingVolumes, err := m.client.ListVolumes(ctx); err != nil {
+		m.logger.Warn("failed to list volumes, they are kept if creation fails",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	} else {
+		cleaner.Add("remove_volumes", func(ctx context.Context) error {
+			return m.removeNewVolumes(ctx, containerName, existingVolumes)
+		})
+	}
 
 	// Create the container
+	op.Step("create_container", "Creating container")
 	container, err := m.client.CreateContainer(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create container: %w", err)
 	}
 
-	// Add cleanup handler for container
+	// Add cleanup handler for container; its new volumes are removed above
 	cleaner.Add("remove_container", func(ctx context.Context) error {
 		m.logger.Debug("removing container", logging.WithField("container", containerName))
-		return m.client.RemoveContainer(ctx, containerName, true)
+		return m.client.RemoveContainer(ctx, containerName, false)
 	})
 
 	// Set up SSH host certificates BEFORE starting the container
@@ -122,8 +216,8 @@ func (m *Manager) CreateContainer(ctx context.Context, name, sshKey string) (*Co
 	}
 
 	// Start the container
+	op.Step("start_container", "Starting container")
 	if err := m.client.StartContainer(ctx, containerName); err != nil {
-		cleaner.Cleanup(ctx)
 		return nil, fmt.Errorf("failed to start container: %w", err)
 	}
 
@@ -134,26 +228,74 @@ func (m *Manager) CreateContainer(ctx context.Context, name, sshKey string) (*Co
 			logging.WithField("container", containerName))
 	}
 
+	if err := m.persistHostKeys(ctx, containerName); err != nil {
+		m.logger.Warn("failed to persist SSH host keys",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
 	// Set up SSH
+	op.Step("setup_ssh", "Setting up SSH access")
 	if err := m.setupSSH(ctx, containerName, sshKey); err != nil {
-		cleaner.Cleanup(ctx)
 		return nil, fmt.Errorf("failed to setup SSH: %w", err)
 	}
 
+	m.verifyHardening(ctx, containerName)
+
+	if config.Runtime.Nested {
+		if err := m.setupNestedContainers(ctx, containerName); err != nil {
+			m.logger.Warn("failed to configure nested containers",
+				logging.WithError(err),
+				logging.WithField("container", containerName))
+		}
+	}
+
 	// Copy dotfiles
-	if err := m.copyDotfiles(ctx, containerName); err != nil {
+	op.Step("copy_dotfiles", "Copying dotfiles")
+	if err := m.copyDotfiles(ctx, containerName, opts); err != nil {
 		// Log error but don't fail container creation
 		m.logger.Warn("failed to copy dotfiles",
 			logging.WithError(err),
 			logging.WithField("container", containerName))
 	}
 
+	if err := m.configureProxy(ctx, containerName); err != nil {
+		m.logger.Warn("failed to configure proxy",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	if err := m.installPackages(ctx, containerName, opts.Packages); err != nil {
+		m.logger.Warn("failed to install packages",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	if err := m.provisionToolchain(ctx, containerName, opts.Toolchain); err != nil {
+		m.logger.Warn("failed to provision toolchain",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	if err := m.persistPackages(ctx, containerName); err != nil {
+		m.logger.Warn("failed to set up package persistence",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
 	// Initialize empty git repository
-	if err := m.initializeGitRepository(ctx, containerName); err != nil {
-		cleaner.Cleanup(ctx)
+	op.Step("init_repository", "Initializing repository")
+	if err := m.initializeGitRepository(ctx, containerName, opts); err != nil {
 		return nil, fmt.Errorf("failed to initialize repository: %w", err)
 	}
 
+	// Add cleanup handler for SSH config before writing it, so a partly
+	// written entry is removed too
+	cleaner.Add("remove_ssh_config", func(ctx context.Context) error {
+		m.logger.Debug("removing SSH config entry", logging.WithField("container", containerName))
+		return ssh.RemoveSSHConfig(name)
+	})
+
 	// Add SSH config entry
 	// Note: AddSSHConfig will load remote host from config
 	if err := ssh.AddSSHConfig(name, "", sshPort, m.config.ContainerUser); err != nil {
@@ -163,12 +305,6 @@ func (m *Manager) CreateContainer(ctx context.Context, name, sshKey string) (*Co
 			logging.WithField("container", containerName))
 	}
 
-	// Add cleanup handler for SSH config
-	cleaner.Add("remove_ssh_config", func(ctx context.Context) error {
-		m.logger.Debug("removing SSH config entry", logging.WithField("container", containerName))
-		return ssh.RemoveSSHConfig(name)
-	})
-
 	// Add git remote on host
 	if err := m.addGitRemote(name, containerName, sshPort); err != nil {
 		// Log error but don't fail container creation
@@ -177,9 +313,6 @@ func (m *Manager) CreateContainer(ctx context.Context, name, sshKey string) (*Co
 			logging.WithField("container", containerName))
 	}
 
-	// Success - clear cleanup handlers
-	cleaner = cleanup.New(m.logger)
-
 	m.logger.Info("container created successfully",
 		logging.WithField("name", name),
 		logging.WithField("container", containerName),
@@ -269,18 +402,52 @@ func (m *Manager) GetContainerInfo(ctx context.Context, name string) (*Container
 	return m.client.GetContainerInfo(ctx, containerName)
 }
 
+// WaitForSSH blocks until sshd in a container answers or the readiness timeout expires.
+// For systemd containers it also waits for boot to finish.
+func (m *Manager) WaitForSSH(ctx context.Context, name string) error {
+	cont, err := m.GetContainerInfo(ctx, name)
+	if err != nil || m.config.Simulated {
+		return err
+	}
+	if err := waitForSSHFunc(ctx, m.config.ProxyJump, m.config.RemoteHost, cont.SSHPort, sshReadyTimeout); err != nil {
+		return err
+	}
+	if cont.Labels[LabelSystemd] == "true" {
+		return m.waitForSystemd(ctx, cont.Name)
+	}
+	return nil
+}
+
+// InspectContainer returns the raw podman inspect JSON for a container
+func (m *Manager) InspectContainer(ctx context.Context, name string) ([]byte, error) {
+	containerName := m.config.ContainerPrefix + "-" + name
+	return m.client.InspectContainer(ctx, containerName)
+}
+
 // ExecContainer executes a command in the container
 func (m *Manager) ExecContainer(ctx context.Context, name string, cmd []string) error {
 	containerName := m.config.ContainerPrefix + "-" + name
 	return m.client.ExecContainer(ctx, containerName, cmd)
 }
 
+// ExecContainerOutput executes a command in a container and returns its stdout
+func (m *Manager) ExecContainerOutput(ctx context.Context, name string, cmd []string) (string, error) {
+	containerName := m.config.ContainerPrefix + "-" + name
+	return m.client.ExecContainerOutput(ctx, containerName, cmd)
+}
+
 // ExecContainerWithInput executes a command in a container with stdin input
 func (m *Manager) ExecContainerWithInput(ctx context.Context, name string, cmd []string, input []byte) error {
 	containerName := m.config.ContainerPrefix + "-" + name
 	return m.client.ExecContainerWithInput(ctx, containerName, cmd, string(input))
 }
 
+// ExecContainerStream executes a command in a container, streaming its output
+func (m *Manager) ExecContainerStream(ctx context.Context, name string, cmd []string, stdout, stderr io.Writer) error {
+	containerName := m.config.ContainerPrefix + "-" + name
+	return m.client.ExecContainerStream(ctx, containerName, cmd, stdout, stderr)
+}
+
 // setupSSH sets up SSH access in the container
 func (m *Manager) setupSSH(ctx context.Context, containerName, publicKey string) error {
 	m.logger.Debug("setting up SSH",
@@ -337,6 +504,11 @@ func (m *Manager) setupSSH(ctx context.Context, containerName, publicKey string)
 // setupSSHCertificatesBeforeStart sets up SSH certificates before container starts
 // This uses podman cp to copy files into the stopped container
 func (m *Manager) setupSSHCertificatesBeforeStart(ctx context.Context, containerName string) error {
+	return m.setupSSHCertificates(ctx, containerName, containerName)
+}
+
+// setupSSHCertificates signs a host key for the SSH host hostAlias and copies it into containerName
+func (m *Manager) setupSSHCertificates(ctx context.Context, containerName, hostAlias string) error {
 	// Check if CA is configured
 	if m.config.CAPrivateKeyPath == "" || m.config.CAPublicKeyPath == "" {
 		m.logger.Debug("SSH CA not configured, skipping certificate setup",
@@ -374,14 +546,11 @@ func (m *Manager) setupSSHCertificatesBeforeStart(ctx context.Context, container
 		return fmt.Errorf("failed to generate host key: %w\nOutput: %s", err, output)
 	}
 
-	// Extract container name without prefix for signing
-	shortName := strings.TrimPrefix(containerName, m.config.ContainerPrefix+"-")
-	
 	// Get remote host from config
 	remoteHost := m.config.RemoteHost
 
 	// Sign the host key with CA
-	if err := ca.SignHostKey(hostKeyPath, shortName, remoteHost); err != nil {
+	if err := ca.SignHostKey(hostKeyPath, hostAlias, remoteHost); err != nil {
 		return fmt.Errorf("failed to sign host key: %w", err)
 	}
 
@@ -405,7 +574,9 @@ PermitRootLogin no
 PubkeyAuthentication yes
 AuthorizedKeysFile .ssh/authorized_keys
 PasswordAuthentication no
-ChallengeResponseAuthentication no
+KbdInteractiveAuthentication no
+PermitEmptyPasswords no
+PermitUserEnvironment no
 UsePAM yes
 
 # Allow our container user
@@ -416,8 +587,8 @@ UseDNS no
 ClientAliveInterval 60
 ClientAliveCountMax 3
 
-# Subsystems
-Subsystem sftp /usr/libexec/openssh/sftp-server
+` + m.config.SSHGuard.sshdConfig() + acceptEnvConfig(m.config.ExecEnv) + `# Subsystems (in-process, so the path is the same on every base flavor)
+Subsystem sftp internal-sftp
 `
 	
 	// Write sshd_config to temp file
@@ -455,16 +626,16 @@ Subsystem sftp /usr/libexec/openssh/sftp-server
 }
 
 // copyDotfiles copies dotfiles to the container
-func (m *Manager) copyDotfiles(ctx context.Context, containerName string) error {
+func (m *Manager) copyDotfiles(ctx context.Context, containerName string, opts CreateOptions) error {
 	// Get dotfiles path based on priority system
-	dotfilesPath, useEmbedded := m.getDotfilesPath()
+	dotfilesPath, useEmbedded := m.getDotfilesPath(opts.DotfilesPath)
 	
 	if useEmbedded {
 		// Use embedded dotfiles
 		m.logger.Info("using embedded dotfiles",
 			logging.WithField("container", containerName))
 		
-		return m.copyEmbeddedDotfiles(ctx, containerName)
+		return m.copyEmbeddedDotfiles(ctx, containerName, opts)
 	}
 	
 	// Use user-specified dotfiles
@@ -478,19 +649,19 @@ func (m *Manager) copyDotfiles(ctx context.Context, containerName string) error
 	}
 	
 	// Apply host git configuration
-	return m.applyHostGitConfig(ctx, containerName)
+	return m.applyHostGitConfig(ctx, containerName, opts)
 }
 
 // applyHostGitConfig reads git config from host and applies it to the container
-func (m *Manager) applyHostGitConfig(ctx context.Context, containerName string) error {
-	// Read host git identity
+func (m *Manager) applyHostGitConfig(ctx context.Context, containerName string, opts CreateOptions) error {
+	// Read host git identity; the repository's override, if any, wins
 	identity, err := ReadHostGitIdentity()
 	if err != nil {
 		// Log warning but don't fail
 		m.logger.Warn("failed to read host git identity",
 			logging.WithError(err))
-		return nil
 	}
+	identity = identity.Override(opts.GitIdentity)
 	
 	// If we have any git config, apply it
 	if identity.Name != "" || identity.Email != "" {
@@ -506,12 +677,20 @@ func (m *Manager) applyHostGitConfig(ctx context.Context, containerName string)
 				logging.WithField("container", containerName))
 		}
 	}
-	
+
+	if err := m.applySigningConfig(ctx, containerName, opts.Signing, opts.SigningKey); err != nil {
+		m.logger.Warn("failed to set up commit signing",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
 	return nil
 }
 
 // initializeGitRepository initializes an empty git repository in the container
-func (m *Manager) initializeGitRepository(ctx context.Context, containerName string) error {
+func (m *Manager) initializeGitRepository(ctx context.Context, containerName string, opts CreateOptions) error {
+	if m.config.Simulated {
+		return nil
+	}
 	// Check if project directory already exists
 	checkCmd := []string{"test", "-d", "/workspace/project"}
 	if err := m.client.ExecContainer(ctx, containerName, checkCmd); err == nil {
@@ -545,11 +724,29 @@ func (m *Manager) initializeGitRepository(ctx context.Context, containerName str
 
 	// Configure git to accept pushes with working tree updates
 	configCmd := []string{"su", "-", m.config.ContainerUser, "-c",
-		"cd /workspace/project && git config receive.denyCurrentBranch updateInstead"}
+		"cd /workspace/project && git config receive.denyCurrentBranch updateInstead && git config receive.shallowUpdate true"}
 	if err := m.client.ExecContainer(ctx, containerName, configCmd); err != nil {
 		return fmt.Errorf("failed to configure git for push: %w", err)
 	}
 
+	if dirs := opts.sparseDirs(); len(dirs) > 0 {
+		sparseCmd := []string{"su", "-", m.config.ContainerUser, "-c", opts.sparseCheckoutScript()}
+		if err := m.client.ExecContainer(ctx, containerName, sparseCmd); err != nil {
+			return fmt.Errorf("failed to set up sparse checkout of %s: %w", strings.Join(dirs, ", "), err)
+		}
+	}
+
+	if err := m.installPushGuard(ctx, containerName); err != nil {
+		return err
+	}
+
+	if opts.OriginURL != "" {
+		originCmd := []string{"su", "-", m.config.ContainerUser, "-c", opts.originScript()}
+		if err := m.client.ExecContainer(ctx, containerName, originCmd); err != nil {
+			return fmt.Errorf("failed to add origin remote: %w", err)
+		}
+	}
+
 	// Set default branch to main
 	setBranchCmd := []string{"su", "-", m.config.ContainerUser, "-c",
 		"cd /workspace/project && git config init.defaultBranch main"}
@@ -605,8 +802,9 @@ func validateContainerName(name string) error {
 	return nil
 }
 
-// SSHIntoContainer executes SSH into the container
-func (m *Manager) SSHIntoContainer(ctx context.Context, name string) error {
+// SSHIntoContainer executes SSH into the container, optionally running a
+// command or setting up port forwards
+func (m *Manager) SSHIntoContainer(ctx context.Context, name string, opts SSHOptions) error {
 	containerName := m.config.ContainerPrefix + "-" + name
 	
 	// Get container info
@@ -620,33 +818,37 @@ func (m *Manager) SSHIntoContainer(ctx context.Context, name string) error {
 		return fmt.Errorf("container '%s' is not running", name)
 	}
 	
+	if opts.WorkDir == "" {
+		opts.WorkDir = WorkDir(cont.Labels)
+	}
+	opts.ForwardAgent = opts.ForwardAgent || forwardsAgent(cont.Labels)
+
 	// Execute SSH command with cd to workspace
-	// Use -t to force TTY allocation for interactive session
-	// The command changes to workspace directory and starts an interactive shell
-	sshCmd := exec.Command("ssh", "-t", fmt.Sprintf("%s-%s", m.config.ContainerPrefix, name), 
-		"cd /workspace/project 2>/dev/null; exec $SHELL -l")
+	// Without a command this starts an interactive login shell
+	sshCmd := exec.Command("ssh", sshArgs(fmt.Sprintf("%s-%s", m.config.ContainerPrefix, name), opts)...)
 	sshCmd.Stdin = os.Stdin
 	sshCmd.Stdout = os.Stdout
 	sshCmd.Stderr = os.Stderr
+	if opts.Stdout != nil {
+		sshCmd.Stdout = opts.Stdout
+	}
+	if opts.Stderr != nil {
+		sshCmd.Stderr = opts.Stderr
+	}
 	
 	return sshCmd.Run()
 }
 
-// SetCLIDotfilesPath sets the CLI dotfiles path (highest priority)
-func (m *Manager) SetCLIDotfilesPath(path string) {
-	m.cliDotfilesPath = path
-}
-
 // getDotfilesPath returns the dotfiles path to use based on priority:
 // 1. CLI flag (--dotfiles-path)
 // 2. Environment variable (L8S_DOTFILES)
 // 3. Config file (dotfiles_path field)
 // 4. User dotfiles (~/.config/l8s/dotfiles/)
 // 5. Embedded defaults (returns empty path, true)
-func (m *Manager) getDotfilesPath() (string, bool) {
+func (m *Manager) getDotfilesPath(cliPath string) (string, bool) {
 	// 1. CLI flag takes highest priority
-	if m.cliDotfilesPath != "" {
-		return m.cliDotfilesPath, false
+	if cliPath != "" {
+		return cliPath, false
 	}
 	
 	// 2. Environment variable
@@ -660,12 +862,9 @@ func (m *Manager) getDotfilesPath() (string, bool) {
 	}
 	
 	// 4. User dotfiles directory
-	home, err := os.UserHomeDir()
-	if err == nil {
-		userDotfiles := filepath.Join(home, ".config", "l8s", "dotfiles")
-		if _, err := os.Stat(userDotfiles); err == nil {
-			return userDotfiles, false
-		}
+	userDotfiles := filepath.Join(config.ConfigDir(), "dotfiles")
+	if _, err := os.Stat(userDotfiles); err == nil {
+		return userDotfiles, false
 	}
 	
 	// 5. Use embedded defaults
@@ -673,7 +872,7 @@ func (m *Manager) getDotfilesPath() (string, bool) {
 }
 
 // copyEmbeddedDotfiles copies embedded dotfiles to the container
-func (m *Manager) copyEmbeddedDotfiles(ctx context.Context, containerName string) error {
+func (m *Manager) copyEmbeddedDotfiles(ctx context.Context, containerName string, opts CreateOptions) error {
 	// Get embedded filesystem
 	embedFS, err := embed.GetDotfilesFS()
 	if err != nil {
@@ -766,13 +965,36 @@ func (m *Manager) copyEmbeddedDotfiles(ctx context.Context, containerName string
 	}
 	
 	// Apply host git configuration
-	return m.applyHostGitConfig(ctx, containerName)
+	return m.applyHostGitConfig(ctx, containerName, opts)
 }
 
 // BuildImage builds the container image on the remote server
 func (m *Manager) BuildImage(ctx context.Context, containerfile string) error {
+	if m.config.Simulated {
+		return nil
+	}
 	// Build the image on the remote server using embedded Containerfile
-	return BuildImage(ctx, m.config.BaseImage)
+	flavor := m.config.BaseFlavor
+	if m.buildFlavor != "" {
+		flavor = m.buildFlavor
+	}
+	image, opts := m.config.BaseImage, m.buildOptions
+	if m.imageVariant != "" {
+		variant, ok := m.config.ImageVariants[m.imageVariant]
+		if !ok {
+			return fmt.Errorf("image variant '%s' is not configured", m.imageVariant)
+		}
+		image = variant.Image
+		opts.Variant, opts.Fragment = m.imageVariant, variant.Containerfile
+	}
+	stats, err := BuildImage(ctx, image, flavor, opts)
+	m.buildStats = stats
+	return err
+}
+
+// SetBuildFlavor overrides the configured base flavor for the next build (--flavor)
+func (m *Manager) SetBuildFlavor(flavor string) {
+	m.buildFlavor = flavor
 }
 
 // fixVolumeOwnership ensures the home and workspace directories have proper ownership
@@ -806,7 +1028,85 @@ func (m *Manager) RebuildContainer(ctx context.Context, name string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get container info: %w", err)
 	}
+
+	if err := m.recreateContainer(ctx, containerInfo, containerInfo.Labels, true); err != nil {
+		return err
+	}
+
+	m.logger.Info("container rebuilt successfully",
+		logging.WithField("container", containerName),
+		logging.WithField("ssh_port", containerInfo.SSHPort))
+
+	return nil
+}
+
+// UpdateLabels changes user labels on a container. Podman cannot modify labels
+// in place, so the container is recreated on the same ports and volumes and
+// returned to its previous running state.
+func (m *Manager) UpdateLabels(ctx context.Context, name string, set map[string]string, unset []string) error {
+	containerName := m.config.ContainerPrefix + "-" + name
+
+	containerInfo, err := m.client.GetContainerInfo(ctx, containerName)
+	if err != nil {
+		return fmt.Errorf("failed to get container info: %w", err)
+	}
+
+	labels := make(map[string]string, len(containerInfo.Labels)+len(set))
+	for k, v := range containerInfo.Labels {
+		labels[k] = v
+	}
+	for k, v := range set {
+		labels[k] = v
+	}
+	for _, k := range unset {
+		delete(labels, k)
+	}
+
+	m.logger.Debug("updating container labels",
+		logging.WithField("container", containerName),
+		logging.WithField("set", set),
+		logging.WithField("unset", unset))
+
+	return m.recreateContainer(ctx, containerInfo, labels, containerInfo.Status == "running")
+}
+
+// recreateConfig returns the configuration that recreates a container on the
+// same name, ports and volumes with the given labels
+func (m *Manager) recreateConfig(containerInfo *Container, labels map[string]string) ContainerConfig {
+	// Carry labels forward so user metadata survives the rebuild
+	newLabels := make(map[string]string, len(labels)+3)
+	for k, v := range labels {
+		newLabels[k] = v
+	}
+	newLabels[LabelManaged] = "true"
+	newLabels[LabelSSHPort] = fmt.Sprintf("%d", containerInfo.SSHPort)
+	if containerInfo.WebPort > 0 {
+		newLabels[LabelWebPort] = fmt.Sprintf("%d", containerInfo.WebPort)
+	}
 	
+	config := ContainerConfig{
+		Name:          containerInfo.Name,
+		SSHPort:       containerInfo.SSHPort, // Preserve the same SSH port
+		WebPort:       containerInfo.WebPort, // Preserve the same web port
+		SSHPublicKey:  "",                    // Empty - authorized_keys already exists in volume
+		BaseImage:     m.imageForLabels(labels), // Use current configured image
+		ContainerUser: m.config.ContainerUser,
+		AudioEnabled:  m.config.AudioEnabled,
+		AudioPort:     m.config.AudioPort,
+		Labels:        newLabels,
+	}
+	m.applyResourceLimits(&config)
+	m.applyMounts(&config, CreateOptions{})
+	m.applyRuntimeOptions(&config, nil)
+	m.applyProxy(&config)
+	return config
+}
+
+// recreateContainer replaces a container with a fresh one from the configured
+// image, keeping its name, ports and volumes and applying the given labels
+func (m *Manager) recreateContainer(ctx context.Context, containerInfo *Container, labels map[string]string, start bool) error {
+	containerName := containerInfo.Name
+
 	// Extract SSH and web ports to preserve
 	sshPort := containerInfo.SSHPort
 	if sshPort == 0 {
@@ -840,25 +1140,7 @@ func (m *Manager) RebuildContainer(ctx context.Context, name string) error {
 		logging.WithField("ssh_port", sshPort),
 		logging.WithField("web_port", webPort))
 	
-	labels := map[string]string{
-		LabelManaged:  "true",
-		LabelSSHPort:  fmt.Sprintf("%d", sshPort),
-	}
-	if webPort > 0 {
-		labels[LabelWebPort] = fmt.Sprintf("%d", webPort)
-	}
-	
-	config := ContainerConfig{
-		Name:          containerName,
-		SSHPort:       sshPort,  // Preserve the same SSH port
-		WebPort:       webPort,  // Preserve the same web port
-		SSHPublicKey:  "",       // Empty - authorized_keys already exists in volume
-		BaseImage:     m.config.BaseImage,  // Use current configured image
-		ContainerUser: m.config.ContainerUser,
-		AudioEnabled:  m.config.AudioEnabled,
-		AudioPort:     m.config.AudioPort,
-		Labels:        labels,
-	}
+	config := m.recreateConfig(containerInfo, labels)
 
 	if _, err := m.client.CreateContainer(ctx, config); err != nil {
 		return fmt.Errorf("failed to create container: %w", err)
@@ -873,6 +1155,10 @@ func (m *Manager) RebuildContainer(ctx context.Context, name string) error {
 			logging.WithError(err),
 			logging.WithField("container", containerName))
 	}
+
+	if !start {
+		return nil
+	}
 	
 	// Step 6: Start the new container
 	if err := m.client.StartContainer(ctx, containerName); err != nil {
@@ -893,18 +1179,45 @@ func (m *Manager) RebuildContainer(ctx context.Context, name string) error {
 			logging.WithField("container", containerName))
 	}
 
+	if err := m.persistHostKeys(ctx, containerName); err != nil {
+		m.logger.Warn("failed to persist SSH host keys",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
 	// Step 9: Redeploy dotfiles to pick up any new files or changes
 	// This ensures new dotfiles like the team script are deployed
-	if err := m.copyDotfiles(ctx, containerName); err != nil {
+	opts := labelCreateOptions(labels)
+	if err := m.copyDotfiles(ctx, containerName, opts); err != nil {
 		// Log error but don't fail container rebuild
 		m.logger.Warn("failed to copy dotfiles during rebuild",
 			logging.WithError(err),
 			logging.WithField("container", containerName))
 	}
 
-	m.logger.Info("container rebuilt successfully",
-		logging.WithField("container", containerName),
-		logging.WithField("ssh_port", sshPort))
+	if err := m.configureProxy(ctx, containerName); err != nil {
+		m.logger.Warn("failed to configure proxy during rebuild",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	if err := m.installPackages(ctx, containerName, opts.Packages); err != nil {
+		m.logger.Warn("failed to install packages during rebuild",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	if err := m.provisionToolchain(ctx, containerName, opts.Toolchain); err != nil {
+		m.logger.Warn("failed to provision toolchain during rebuild",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	if err := m.persistPackages(ctx, containerName); err != nil {
+		m.logger.Warn("failed to reinstall persisted packages during rebuild",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
 
 	return nil
-}
\ No newline at end of file
+}
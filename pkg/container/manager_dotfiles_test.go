@@ -18,6 +18,7 @@ func TestManagerGetDotfilesPath(t *testing.T) {
 	tests := []struct {
 		name          string
 		setupFunc     func() (*Manager, func())
+		cliPath       string
 		expectedPath  string
 		expectEmbedded bool
 	}{
@@ -31,10 +32,10 @@ func TestManagerGetDotfilesPath(t *testing.T) {
 				m := &Manager{
 					config: cfg,
 					logger: logging.Default(),
-					cliDotfilesPath: "/cli/path",
 				}
 				return m, func() {}
 			},
+			cliPath: "/cli/path",
 			expectedPath: "/cli/path",
 			expectEmbedded: false,
 		},
@@ -116,7 +117,7 @@ func TestManagerGetDotfilesPath(t *testing.T) {
 			m, cleanup := tt.setupFunc()
 			defer cleanup()
 			
-			path, useEmbedded := m.getDotfilesPath()
+			path, useEmbedded := m.getDotfilesPath(tt.cliPath)
 			
 			if path != tt.expectedPath {
 				t.Errorf("getDotfilesPath() path = %v, want %v", path, tt.expectedPath)
@@ -226,7 +227,7 @@ func TestCopyDotfilesWithPriority(t *testing.T) {
 			m, mockClient, cleanup := tt.setupFunc()
 			defer cleanup()
 			
-			err := m.copyDotfiles(ctx, "test-container")
+			err := m.copyDotfiles(ctx, "test-container", CreateOptions{})
 			if err != nil {
 				t.Errorf("copyDotfiles() error = %v", err)
 			}
@@ -271,7 +272,7 @@ func TestCopyEmbeddedDotfiles(t *testing.T) {
 	}
 	
 	// Test copyEmbeddedDotfiles
-	err := m.copyEmbeddedDotfiles(ctx, "test-container")
+	err := m.copyEmbeddedDotfiles(ctx, "test-container", CreateOptions{})
 	if err != nil {
 		t.Errorf("copyEmbeddedDotfiles() error = %v", err)
 	}
@@ -310,7 +311,7 @@ func TestCopyEmbeddedDotfiles(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
-func TestSetCLIDotfilesPath(t *testing.T) {
+func TestCLIDotfilesPath(t *testing.T) {
 	cfg := Config{
 		ContainerUser: "testuser",
 		DotfilesPath: "/config/path",
@@ -321,17 +322,14 @@ func TestSetCLIDotfilesPath(t *testing.T) {
 		logger: logging.Default(),
 	}
 	
-	// Initially, should use config path
-	path, useEmbedded := m.getDotfilesPath()
+	// Without the flag, should use config path
+	path, useEmbedded := m.getDotfilesPath("")
 	if path != "/config/path" || useEmbedded {
 		t.Errorf("Expected config path before CLI override")
 	}
 	
-	// Set CLI path
-	m.SetCLIDotfilesPath("/cli/override")
-	
-	// Now should use CLI path
-	path, useEmbedded = m.getDotfilesPath()
+	// The flag overrides it
+	path, useEmbedded = m.getDotfilesPath("/cli/override")
 	if path != "/cli/override" || useEmbedded {
 		t.Errorf("Expected CLI path after override, got %s", path)
 	}
@@ -5,6 +5,9 @@ package container
 import (
 	"context"
 	"fmt"
+	"io"
+
+	"l8s/pkg/config"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -77,18 +80,126 @@ func (m *MockPodmanClient) ExecContainer(ctx context.Context, name string, cmd [
 	return args.Error(0)
 }
 
+// ExecContainerOutput mocks the ExecContainerOutput method
+func (m *MockPodmanClient) ExecContainerOutput(ctx context.Context, name string, cmd []string) (string, error) {
+	args := m.Called(ctx, name, cmd)
+	return args.String(0), args.Error(1)
+}
+
 // ExecContainerWithInput mocks the ExecContainerWithInput method
 func (m *MockPodmanClient) ExecContainerWithInput(ctx context.Context, name string, cmd []string, input string) error {
 	args := m.Called(ctx, name, cmd, input)
 	return args.Error(0)
 }
 
+// ExecContainerStream mocks the ExecContainerStream method
+func (m *MockPodmanClient) ExecContainerStream(ctx context.Context, name string, cmd []string, stdout, stderr io.Writer) error {
+	args := m.Called(ctx, name, cmd, stdout, stderr)
+	return args.Error(0)
+}
+
 // CopyToContainer mocks the CopyToContainer method
 func (m *MockPodmanClient) CopyToContainer(ctx context.Context, name string, src, dst string) error {
 	args := m.Called(ctx, name, src, dst)
 	return args.Error(0)
 }
 
+// RenameContainer mocks the RenameContainer method
+func (m *MockPodmanClient) RenameContainer(ctx context.Context, name, newName string) error {
+	args := m.Called(ctx, name, newName)
+	return args.Error(0)
+}
+
+// CopyVolume mocks the CopyVolume method
+func (m *MockPodmanClient) CopyVolume(ctx context.Context, src, dst string) error {
+	args := m.Called(ctx, src, dst)
+	return args.Error(0)
+}
+
+// RemoveVolume mocks the RemoveVolume method
+func (m *MockPodmanClient) RemoveVolume(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+// ExportVolume mocks the ExportVolume method
+func (m *MockPodmanClient) ExportVolume(ctx context.Context, name string, w io.Writer) error {
+	args := m.Called(ctx, name, w)
+	return args.Error(0)
+}
+
+// ListVolumes mocks the ListVolumes method
+func (m *MockPodmanClient) ListVolumes(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// DiffContainer mocks the DiffContainer method
+func (m *MockPodmanClient) DiffContainer(ctx context.Context, name string) ([]FileChange, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]FileChange), args.Error(1)
+}
+
+// DescribeContainer mocks the DescribeContainer method
+func (m *MockPodmanClient) DescribeContainer(ctx context.Context, name string) (*ForeignContainer, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ForeignContainer), args.Error(1)
+}
+
+// CommitContainer mocks the CommitContainer method
+func (m *MockPodmanClient) CommitContainer(ctx context.Context, name, image string) error {
+	args := m.Called(ctx, name, image)
+	return args.Error(0)
+}
+
+// RemoveImage mocks the RemoveImage method
+func (m *MockPodmanClient) RemoveImage(ctx context.Context, image string) error {
+	args := m.Called(ctx, image)
+	return args.Error(0)
+}
+
+// DiskUsage mocks the DiskUsage method
+func (m *MockPodmanClient) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DiskUsage), args.Error(1)
+}
+
+// RunImage mocks the RunImage method
+func (m *MockPodmanClient) RunImage(ctx context.Context, image string, cmd []string) (string, error) {
+	args := m.Called(ctx, image, cmd)
+	return args.String(0), args.Error(1)
+}
+
+// InspectContainer mocks the InspectContainer method
+func (m *MockPodmanClient) InspectContainer(ctx context.Context, name string) ([]byte, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+// Version mocks the Version method
+func (m *MockPodmanClient) Version(ctx context.Context) (*RemoteVersion, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*RemoteVersion), args.Error(1)
+}
+
 // RealPodmanClient is a stub for test builds
 type RealPodmanClient struct {
 	conn context.Context
@@ -99,6 +210,11 @@ func NewPodmanClient() (*RealPodmanClient, error) {
 	return &RealPodmanClient{conn: context.Background()}, nil
 }
 
+// NewPodmanClientFor creates a mock client for testing
+func NewPodmanClientFor(cfg *config.Config) (*RealPodmanClient, error) {
+	return &RealPodmanClient{conn: context.Background()}, nil
+}
+
 // All methods below are stubs for test builds
 
 func (c *RealPodmanClient) ContainerExists(ctx context.Context, name string) (bool, error) {
@@ -159,16 +275,64 @@ func (c *RealPodmanClient) ExecContainer(ctx context.Context, name string, cmd [
 	return fmt.Errorf("not implemented in test build")
 }
 
+func (c *RealPodmanClient) ExecContainerOutput(ctx context.Context, name string, cmd []string) (string, error) {
+	return "", fmt.Errorf("not implemented in test build")
+}
+
 func (c *RealPodmanClient) ExecContainerWithInput(ctx context.Context, name string, cmd []string, input string) error {
 	return fmt.Errorf("not implemented in test build")
 }
 
+func (c *RealPodmanClient) ExecContainerStream(ctx context.Context, name string, cmd []string, stdout, stderr io.Writer) error {
+	return fmt.Errorf("not implemented in test build")
+}
+
 func (c *RealPodmanClient) CopyToContainer(ctx context.Context, name string, src, dst string) error {
 	return fmt.Errorf("not implemented in test build")
 }
 
+func (c *RealPodmanClient) RenameContainer(ctx context.Context, name, newName string) error {
+	return fmt.Errorf("not implemented in test build")
+}
+func (c *RealPodmanClient) CopyVolume(ctx context.Context, src, dst string) error {
+	return fmt.Errorf("not implemented in test build")
+}
 
-// BuildImage is a stub for test builds
-func BuildImage(ctx context.Context, imageName string) error {
+func (c *RealPodmanClient) RemoveVolume(ctx context.Context, name string) error {
+	return fmt.Errorf("not implemented in test build")
+}
+func (c *RealPodmanClient) ExportVolume(ctx context.Context, name string, w io.Writer) error {
+	return fmt.Errorf("not implemented in test build")
+}
+func (c *RealPodmanClient) ListVolumes(ctx context.Context) ([]string, error) {
+	return nil, fmt.Errorf("not implemented in test build")
+}
+func (c *RealPodmanClient) InspectContainer(ctx context.Context, name string) ([]byte, error) {
+	return nil, fmt.Errorf("not implemented in test build")
+}
+func (c *RealPodmanClient) DiffContainer(ctx context.Context, name string) ([]FileChange, error) {
+	return nil, fmt.Errorf("not implemented in test build")
+}
+func (c *RealPodmanClient) RunImage(ctx context.Context, image string, cmd []string) (string, error) {
+	return "", fmt.Errorf("not implemented in test build")
+}
+func (c *RealPodmanClient) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	return nil, fmt.Errorf("not implemented in test build")
+}
+func (c *RealPodmanClient) DescribeContainer(ctx context.Context, name string) (*ForeignContainer, error) {
+	return nil, fmt.Errorf("not implemented in test build")
+}
+func (c *RealPodmanClient) CommitContainer(ctx context.Context, name, image string) error {
+	return fmt.Errorf("not implemented in test build")
+}
+func (c *RealPodmanClient) RemoveImage(ctx context.Context, image string) error {
 	return fmt.Errorf("not implemented in test build")
+}
+func (c *RealPodmanClient) Version(ctx context.Context) (*RemoteVersion, error) {
+	return nil, fmt.Errorf("not implemented in test build")
+}
+
+// BuildImage is a stub for test builds
+func BuildImage(ctx context.Context, imageName, flavor string, opts BuildOptions) (BuildStats, error) {
+	return BuildStats{}, fmt.Errorf("not implemented in test build")
 }
\ No newline at end of file
@@ -0,0 +1,77 @@
+package container
+
+import (
+	"strings"
+)
+
+// Mount attaches a directory on the Podman host, or a named volume, to a container
+type Mount struct {
+	Source   string // Absolute path on the host, or a volume name
+	Target   string // Path in the container
+	ReadOnly bool
+}
+
+// IsVolume reports whether the source is a named volume rather than a host path
+func (m Mount) IsVolume() bool {
+	return !strings.HasPrefix(m.Source, "/")
+}
+
+// FormatMounts encodes mounts for LabelMounts as comma separated
+// source:target pairs, with ":ro" appended for read-only mounts
+func FormatMounts(mounts []Mount) string {
+	pairs := make([]string, len(mounts))
+	for i, m := range mounts {
+		pairs[i] = m.Source + ":" + m.Target
+		if m.ReadOnly {
+			pairs[i] += ":ro"
+		}
+	}
+	return strings.Join(pairs, ",")
+}
+
+// ParseMounts decodes a LabelMounts value, skipping malformed entries
+func ParseMounts(value string) []Mount {
+	var mounts []Mount
+	for _, pair := range strings.Split(value, ",") {
+		fields := strings.Split(pair, ":")
+		if len(fields) < 2 || len(fields) > 3 || fields[0] == "" || fields[1] == "" {
+			continue
+		}
+		mounts = append(mounts, Mount{
+			Source:   fields[0],
+			Target:   fields[1],
+			ReadOnly: len(fields) == 3 && fields[2] == "ro",
+		})
+	}
+	return mounts
+}
+
+// applyMounts sets the bind mounts for a container: the configured ones, those
+// declared by the repository in opts and the toolchain's. Mounts are chosen
+// when a container is created and recorded in its labels, so rebuilds and
+// clones keep them even though the repository's .l8s.yaml is not available then.
+func (m *Manager) applyMounts(config *ContainerConfig, opts CreateOptions) {
+	if config.Labels == nil {
+		config.Labels = make(map[string]string)
+	}
+	if value, ok := config.Labels[LabelMounts]; ok {
+		config.Mounts = ParseMounts(value)
+		return
+	}
+
+	seen := make(map[string]bool)
+	mounts := append(append([]Mount{}, m.config.Mounts...), opts.Mounts...)
+	if mount, ok := m.toolchainMount(opts.Toolchain); ok {
+		mounts = append(mounts, mount)
+	}
+	for _, mount := range mounts {
+		if seen[mount.Target] {
+			continue
+		}
+		seen[mount.Target] = true
+		config.Mounts = append(config.Mounts, mount)
+	}
+	if len(config.Mounts) > 0 {
+		config.Labels[LabelMounts] = FormatMounts(config.Mounts)
+	}
+}
@@ -0,0 +1,47 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatParseMounts(t *testing.T) {
+	mounts := []Mount{
+		{Source: "/scratch/nvme", Target: "/scratch"},
+		{Source: "/srv/datasets", Target: "/data", ReadOnly: true},
+		{Source: "models", Target: "/models", ReadOnly: true},
+	}
+	value := FormatMounts(mounts)
+	assert.Equal(t, "/scratch/nvme:/scratch,/srv/datasets:/data:ro,models:/models:ro", value)
+	assert.Equal(t, mounts, ParseMounts(value))
+
+	assert.Nil(t, ParseMounts(""))
+	assert.Equal(t, []Mount{{Source: "/a", Target: "/b"}}, ParseMounts("bogus,/a:/b,:/c,/d:/e:ro:x"))
+
+	assert.True(t, mounts[2].IsVolume())
+	assert.False(t, mounts[0].IsVolume())
+}
+
+func TestManager_ApplyMounts(t *testing.T) {
+	m := NewManager(nil, Config{Mounts: []Mount{{Source: "/scratch", Target: "/scratch"}}})
+	opts := CreateOptions{Mounts: []Mount{
+		{Source: "/srv/data", Target: "/data"},
+		{Source: "/other", Target: "/scratch"}, // global mount wins
+	}}
+
+	config := ContainerConfig{}
+	m.applyMounts(&config, opts)
+	assert.Equal(t, []Mount{{Source: "/scratch", Target: "/scratch"}, {Source: "/srv/data", Target: "/data"}}, config.Mounts)
+	assert.Equal(t, "/scratch:/scratch,/srv/data:/data", config.Labels[LabelMounts])
+
+	// Recreated containers keep the mounts recorded at creation
+	recreated := ContainerConfig{Labels: map[string]string{LabelMounts: "/old:/old"}}
+	m.applyMounts(&recreated, opts)
+	assert.Equal(t, []Mount{{Source: "/old", Target: "/old"}}, recreated.Mounts)
+
+	// No label is added when nothing is mounted
+	plain := ContainerConfig{}
+	NewManager(nil, Config{}).applyMounts(&plain, CreateOptions{})
+	assert.NotContains(t, plain.Labels, LabelMounts)
+}
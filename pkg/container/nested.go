@@ -0,0 +1,68 @@
+package container
+
+import (
+	"context"
+	"fmt"
+)
+
+// nestedStorageConf makes rootless podman inside the container use
+// fuse-overlayfs, since the kernel overlay driver cannot be stacked on the
+// container's own overlay root
+const nestedStorageConf = `[storage]
+driver = "overlay"
+
+[storage.options.overlay]
+mount_program = "/usr/bin/fuse-overlayfs"
+`
+
+// nestedContainersConf shares the outer container's namespaces and skips
+// cgroup management, which is not delegated to the container
+const nestedContainersConf = `[containers]
+netns = "host"
+userns = "host"
+ipcns = "host"
+utsns = "host"
+cgroupns = "host"
+cgroups = "disabled"
+log_driver = "k8s-file"
+
+[engine]
+cgroup_manager = "cgroupfs"
+events_logger = "file"
+`
+
+// nestedSubIDRange is used when the image has no subordinate IDs for the user
+const nestedSubIDRange = "100000:65536"
+
+// setupNestedContainers prepares a container created with --nested-containers
+// so the container user can run podman and buildah. The configuration lives in
+// the home volume, so it survives rebuilds; subordinate IDs come from the image
+// (Fedora's useradd allocates them) with a fallback range added here.
+func (m *Manager) setupNestedContainers(ctx context.Context, containerName string) error {
+	user := m.config.ContainerUser
+
+	if _, err := m.client.ExecContainerOutput(ctx, containerName, []string{"sh", "-c", "command -v podman && command -v fuse-overlayfs"}); err != nil {
+		return fmt.Errorf("podman and fuse-overlayfs are not installed in the image\nRebuild the image with 'l8s build' to get them")
+	}
+
+	subIDs := fmt.Sprintf("for f in /etc/subuid /etc/subgid; do grep -q '^%[1]s:' $f || echo '%[1]s:%[2]s' >> $f; done", user, nestedSubIDRange)
+	if err := m.client.ExecContainer(ctx, containerName, []string{"sh", "-c", subIDs}); err != nil {
+		return fmt.Errorf("failed to allocate subordinate IDs: %w", err)
+	}
+
+	configDir := fmt.Sprintf("/home/%s/.config/containers", user)
+	mkdirCmd := []string{"su", "-", user, "-c", "mkdir -p " + configDir}
+	if err := m.client.ExecContainer(ctx, containerName, mkdirCmd); err != nil {
+		return fmt.Errorf("failed to create %s: %w", configDir, err)
+	}
+	for file, content := range map[string]string{
+		"storage.conf":    nestedStorageConf,
+		"containers.conf": nestedContainersConf,
+	} {
+		writeCmd := []string{"su", "-", user, "-c", fmt.Sprintf("cat > %s/%s", configDir, file)}
+		if err := m.client.ExecContainerWithInput(ctx, containerName, writeCmd, content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+	return nil
+}
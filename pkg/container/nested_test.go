@@ -0,0 +1,36 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_SetupNestedContainers(t *testing.T) {
+	t.Run("writes user configuration", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("ExecContainerOutput", mock.Anything, "dev-app", mock.Anything).Return("/usr/bin/podman\n/usr/bin/fuse-overlayfs\n", nil)
+		m.On("ExecContainer", mock.Anything, "dev-app", mock.Anything).Return(nil)
+		m.On("ExecContainerWithInput", mock.Anything, "dev-app",
+			[]string{"su", "-", "dev", "-c", "cat > /home/dev/.config/containers/storage.conf"}, nestedStorageConf).Return(nil)
+		m.On("ExecContainerWithInput", mock.Anything, "dev-app",
+			[]string{"su", "-", "dev", "-c", "cat > /home/dev/.config/containers/containers.conf"}, nestedContainersConf).Return(nil)
+
+		manager := NewManager(m, Config{ContainerPrefix: "dev", ContainerUser: "dev"})
+		require.NoError(t, manager.setupNestedContainers(context.Background(), "dev-app"))
+		m.AssertExpectations(t)
+	})
+
+	t.Run("image without podman", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("ExecContainerOutput", mock.Anything, "dev-app", mock.Anything).Return("", errors.New("exit status 1"))
+
+		manager := NewManager(m, Config{ContainerPrefix: "dev", ContainerUser: "dev"})
+		err := manager.setupNestedContainers(context.Background(), "dev-app")
+		assert.ErrorContains(t, err, "l8s build")
+	})
+}
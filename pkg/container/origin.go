@@ -0,0 +1,32 @@
+package container
+
+import (
+	"fmt"
+
+	"l8s/pkg/config"
+)
+
+// originCredentialHelper answers git's credential requests with GITHUB_TOKEN,
+// which the container's .zshrc exports from github_token, so the token is
+// never written to the repository's config
+const originCredentialHelper = `!f() { test "$1" = get && echo username=x-access-token && echo "password=$GITHUB_TOKEN"; }; f`
+
+// originScript adds OriginURL as the origin remote, with credentials for the
+// OriginAuth mode. SSH remotes rely on the agent forwarded by 'l8s ssh' and
+// accept the host key on first use.
+func (o CreateOptions) originScript() string {
+	script := fmt.Sprintf("cd %s && git remote add origin %s", ProjectDir, ShellQuote(o.OriginURL))
+	switch o.OriginAuth {
+	case config.OriginAuthAgent:
+		script += " && git config core.sshCommand " + ShellQuote("ssh -o StrictHostKeyChecking=accept-new")
+	case config.OriginAuthToken:
+		script += " && git config credential.helper " + ShellQuote(originCredentialHelper)
+	}
+	return script
+}
+
+// forwardsAgent reports whether SSH sessions into a container forward the
+// local SSH agent, which its mirrored origin or commit signing uses
+func forwardsAgent(labels map[string]string) bool {
+	return labels[LabelOrigin] == config.OriginAuthAgent || labels[LabelSigning] == config.SigningAgent
+}
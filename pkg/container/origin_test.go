@@ -0,0 +1,26 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOriginScript(t *testing.T) {
+	opts := CreateOptions{OriginURL: "git@github.com:acme/api.git", OriginAuth: "agent"}
+	assert.Equal(t, "cd /workspace/project && git remote add origin git@github.com:acme/api.git && "+
+		"git config core.sshCommand 'ssh -o StrictHostKeyChecking=accept-new'", opts.originScript())
+
+	opts = CreateOptions{OriginURL: "https://github.com/acme/api.git", OriginAuth: "token"}
+	assert.Equal(t, "cd /workspace/project && git remote add origin https://github.com/acme/api.git && "+
+		`git config credential.helper '!f() { test "$1" = get && echo username=x-access-token && echo "password=$GITHUB_TOKEN"; }; f'`,
+		opts.originScript())
+}
+
+func TestForwardsAgent(t *testing.T) {
+	assert.True(t, forwardsAgent(map[string]string{LabelOrigin: "agent"}))
+	assert.False(t, forwardsAgent(map[string]string{LabelOrigin: "token"}))
+	assert.True(t, forwardsAgent(map[string]string{LabelOrigin: "token", LabelSigning: "agent"}))
+	assert.False(t, forwardsAgent(map[string]string{LabelSigning: "key"}))
+	assert.False(t, forwardsAgent(nil))
+}
@@ -0,0 +1,31 @@
+package container
+
+import (
+	"os"
+	"os/user"
+
+	"l8s/pkg/ssh"
+)
+
+// CurrentUser returns the local username recorded as container owner
+func CurrentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// ownerLabels identifies the creator of a container by local username and,
+// when known, the fingerprint of the SSH key used to reach it
+func ownerLabels(sshKey string) map[string]string {
+	labels := map[string]string{LabelOwner: CurrentUser()}
+	if sshKey != "" {
+		if fp, err := ssh.Fingerprint(sshKey); err == nil {
+			labels[LabelOwnerKey] = fp
+		}
+	}
+	return labels
+}
@@ -0,0 +1,157 @@
+package container
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// Packages lists packages to install in a container, by installer
+type Packages struct {
+	Dnf   []string
+	Apt   []string
+	Pip   []string
+	Npm   []string
+	Cargo []string
+}
+
+// Empty reports whether there is nothing to install
+func (p Packages) Empty() bool {
+	return len(p.Dnf)+len(p.Apt)+len(p.Pip)+len(p.Npm)+len(p.Cargo) == 0
+}
+
+// installerPackages are the packages for one installer
+type installerPackages struct {
+	name  string
+	specs []string
+}
+
+// installers lists each installer's packages, in install order
+func (p Packages) installers() []installerPackages {
+	return []installerPackages{{"dnf", p.Dnf}, {"apt", p.Apt}, {"pip", p.Pip}, {"npm", p.Npm}, {"cargo", p.Cargo}}
+}
+
+// String describes the packages, e.g. "dnf: ripgrep jq, pip: black"
+func (p Packages) String() string {
+	var parts []string
+	for _, in := range p.installers() {
+		if len(in.specs) > 0 {
+			parts = append(parts, in.name+": "+strings.Join(in.specs, " "))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatPackages encodes packages for LabelPackages as installer=spec...
+// entries separated by ';', e.g. "dnf=ripgrep jq;pip=black"
+func FormatPackages(p Packages) string {
+	var entries []string
+	for _, in := range p.installers() {
+		if len(in.specs) > 0 {
+			entries = append(entries, in.name+"="+strings.Join(in.specs, " "))
+		}
+	}
+	return strings.Join(entries, ";")
+}
+
+// ParsePackages decodes a LabelPackages value, skipping unknown installers
+func ParsePackages(value string) Packages {
+	var p Packages
+	for _, entry := range strings.Split(value, ";") {
+		name, specs, _ := strings.Cut(entry, "=")
+		fields := strings.Fields(specs)
+		switch name {
+		case "dnf":
+			p.Dnf = fields
+		case "apt":
+			p.Apt = fields
+		case "pip":
+			p.Pip = fields
+		case "npm":
+			p.Npm = fields
+		case "cargo":
+			p.Cargo = fields
+		}
+	}
+	return p
+}
+
+// systemPackagesScript installs the dnf or apt packages, whichever the image
+// uses, unless they are already there. Downloads are kept in the home volume,
+// so reinstalling them after a rebuild needs no network.
+func systemPackagesScript(p Packages, user string) string {
+	if len(p.Dnf) == 0 && len(p.Apt) == 0 {
+		return ""
+	}
+	cacheDir := fmt.Sprintf("/home/%s/.cache/l8s", user)
+	dnf, apt := ":", ":"
+	if len(p.Dnf) > 0 {
+		specs := strings.Join(quoteAll(p.Dnf), " ")
+		dnf = fmt.Sprintf("rpm -q %[1]s >/dev/null 2>&1 || dnf install -y --setopt=keepcache=True --setopt=cachedir=%[2]s/dnf %[1]s", specs, cacheDir)
+	}
+	if len(p.Apt) > 0 {
+		specs := strings.Join(quoteAll(p.Apt), " ")
+		apt = fmt.Sprintf(`dpkg -s %[1]s >/dev/null 2>&1 || {
+		mkdir -p %[2]s/apt/partial
+		apt-get update
+		DEBIAN_FRONTEND=noninteractive apt-get install -y -o Dir::Cache::archives=%[2]s/apt %[1]s
+	}`, specs, cacheDir)
+	}
+	// The cache directory is created for the user, who keeps their stamp there
+	return fmt.Sprintf(`set -e
+mkdir -p %[1]s
+chown %[2]s: /home/%[2]s/.cache %[1]s
+if command -v dnf >/dev/null 2>&1; then
+	%[3]s
+elif command -v apt-get >/dev/null 2>&1; then
+	%[4]s
+fi
+`, cacheDir, user, dnf, apt)
+}
+
+// userPackagesScript installs the pip, npm and cargo packages into the home
+// volume. A checksum of the list is kept there too, so rebuilds skip the
+// install when the list has not changed.
+func userPackagesScript(p Packages) string {
+	if len(p.Pip)+len(p.Npm)+len(p.Cargo) == 0 {
+		return ""
+	}
+	sum := fmt.Sprintf("%x", sha256.Sum256([]byte(FormatPackages(Packages{Pip: p.Pip, Npm: p.Npm, Cargo: p.Cargo}))))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `set -e
+stamp="$HOME/.cache/l8s/packages.sum"
+[ "$(cat "$stamp" 2>/dev/null)" = %s ] && exit 0
+`, sum)
+	if len(p.Pip) > 0 {
+		// Debian marks the system Python as externally managed; --user installs are still safe
+		fmt.Fprintf(&b, "PIP_BREAK_SYSTEM_PACKAGES=1 python3 -m pip install --user %s\n", strings.Join(quoteAll(p.Pip), " "))
+	}
+	if len(p.Npm) > 0 {
+		fmt.Fprintf(&b, "npm install -g --prefix \"$HOME/.local\" %s\n", strings.Join(quoteAll(p.Npm), " "))
+	}
+	if len(p.Cargo) > 0 {
+		fmt.Fprintf(&b, "cargo install %s\n", strings.Join(quoteAll(p.Cargo), " "))
+	}
+	fmt.Fprintf(&b, "mkdir -p \"$(dirname \"$stamp\")\"\necho %s > \"$stamp\"\n", sum)
+	return b.String()
+}
+
+// installPackages installs the packages set for the container, system
+// packages as root and the rest as the container user. They are recorded in
+// its labels and installed again after every rebuild.
+func (m *Manager) installPackages(ctx context.Context, containerName string, p Packages) error {
+	if script := systemPackagesScript(p, m.config.ContainerUser); script != "" {
+		if _, err := m.client.ExecContainerOutput(ctx, containerName, []string{"sh", "-c", script}); err != nil {
+			return fmt.Errorf("failed to install system packages: %w", err)
+		}
+	}
+	if script := userPackagesScript(p); script != "" {
+		installCmd := []string{"su", "-", m.config.ContainerUser, "-c", script}
+		if _, err := m.client.ExecContainerOutput(ctx, containerName, installCmd); err != nil {
+			return fmt.Errorf("failed to install pip, npm or cargo packages: %w", err)
+		}
+	}
+	return nil
+}
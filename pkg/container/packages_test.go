@@ -0,0 +1,57 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatParsePackages(t *testing.T) {
+	p := Packages{Dnf: []string{"ripgrep", "jq"}, Pip: []string{"black==24.1", "requests[socks]"}, Cargo: []string{"just"}}
+
+	value := FormatPackages(p)
+	assert.Equal(t, "dnf=ripgrep jq;pip=black==24.1 requests[socks];cargo=just", value)
+	assert.Equal(t, p, ParsePackages(value))
+	assert.Equal(t, "dnf: ripgrep jq, pip: black==24.1 requests[socks], cargo: just", p.String())
+
+	assert.True(t, ParsePackages("").Empty())
+	assert.True(t, ParsePackages("brew=wget").Empty())
+}
+
+func TestPackageScripts(t *testing.T) {
+	assert.Empty(t, systemPackagesScript(Packages{Pip: []string{"black"}}, "dev"))
+	assert.Empty(t, userPackagesScript(Packages{Dnf: []string{"jq"}}))
+
+	system := systemPackagesScript(Packages{Dnf: []string{"jq"}, Apt: []string{"jq", "fd-find"}}, "dev")
+	assert.Contains(t, system, "rpm -q jq >/dev/null 2>&1 || dnf install -y --setopt=keepcache=True --setopt=cachedir=/home/dev/.cache/l8s/dnf jq")
+	assert.Contains(t, system, "apt-get install -y -o Dir::Cache::archives=/home/dev/.cache/l8s/apt jq fd-find")
+	assert.Contains(t, system, "chown dev: /home/dev/.cache /home/dev/.cache/l8s")
+
+	user := userPackagesScript(Packages{Pip: []string{"black"}, Npm: []string{"typescript@5"}, Cargo: []string{"just"}})
+	assert.Contains(t, user, "python3 -m pip install --user black\n")
+	assert.Contains(t, user, `npm install -g --prefix "$HOME/.local" typescript@5`)
+	assert.Contains(t, user, "cargo install just\n")
+
+	// The stamp only changes with the pip, npm and cargo lists
+	assert.Equal(t, user, userPackagesScript(Packages{Pip: []string{"black"}, Npm: []string{"typescript@5"}, Cargo: []string{"just"}, Dnf: []string{"jq"}}))
+	assert.NotEqual(t, user, userPackagesScript(Packages{Pip: []string{"black"}, Npm: []string{"typescript@5"}}))
+}
+
+func TestManager_InstallPackages(t *testing.T) {
+	client := new(MockPodmanClient)
+	manager := NewManager(client, Config{ContainerUser: "dev"})
+	require.NoError(t, manager.installPackages(context.Background(), "dev-app", Packages{}))
+
+	client.On("ExecContainerOutput", mock.Anything, "dev-app", mock.MatchedBy(func(cmd []string) bool {
+		return cmd[0] == "sh"
+	})).Return("", nil).Once()
+	client.On("ExecContainerOutput", mock.Anything, "dev-app", mock.MatchedBy(func(cmd []string) bool {
+		return cmd[0] == "su" && cmd[2] == "dev"
+	})).Return("", nil).Once()
+
+	require.NoError(t, manager.installPackages(context.Background(), "dev-app", Packages{Dnf: []string{"jq"}, Pip: []string{"black"}}))
+	client.AssertExpectations(t)
+}
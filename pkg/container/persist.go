@@ -0,0 +1,98 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"path"
+)
+
+// recordPackagesPath is the hook that writes the persisted package manifest
+const recordPackagesPath = "/usr/local/libexec/l8s-record-packages"
+
+// imagePackagesPath lists the packages the container started with; anything
+// installed later is an ad-hoc install
+const imagePackagesPath = "/etc/l8s/image-packages"
+
+// PersistedPackagesPath is the manifest of ad-hoc installs in the home volume
+func PersistedPackagesPath(user string) string {
+	return fmt.Sprintf("/home/%s/.local/share/l8s/packages", user)
+}
+
+// recordPackagesScript is the package manager hook. It writes the packages
+// installed since the container was created, dependencies included, to the
+// manifest.
+func recordPackagesScript(user string) string {
+	manifest := PersistedPackagesPath(user)
+	return fmt.Sprintf(`#!/bin/sh
+# Written by l8s: records packages installed in this container so they are
+# installed again after a rebuild
+[ -f %[1]s ] || exit 0
+mkdir -p %[2]s
+(%[3]s) | LC_ALL=C sort -u | LC_ALL=C comm -23 - %[1]s > %[4]s.tmp && mv %[4]s.tmp %[4]s
+chown %[5]s: /home/%[5]s/.local /home/%[5]s/.local/share
+chown -R %[5]s: /home/%[5]s/.local/share/l8s
+`, imagePackagesPath, path.Dir(manifest), packageListScript, manifest, user)
+}
+
+// persistPackagesScript installs the hook: a libdnf5 actions file after each
+// dnf transaction, or DPkg::Post-Invoke for apt. The image's package list is
+// taken the first time, before any ad-hoc install.
+func persistPackagesScript(user string) string {
+	return fmt.Sprintf(`set -e
+mkdir -p /etc/l8s /usr/local/libexec
+[ -f %[1]s ] || (%[2]s) | LC_ALL=C sort -u > %[1]s
+cat > %[3]s <<'L8S_EOF'
+%[4]sL8S_EOF
+chmod 755 %[3]s
+if command -v dnf >/dev/null 2>&1; then
+	rpm -q libdnf5-plugin-actions >/dev/null 2>&1 || dnf install -y libdnf5-plugin-actions
+	mkdir -p /etc/dnf/libdnf5-plugins/actions.d
+	echo 'post_transaction::::%[3]s' > /etc/dnf/libdnf5-plugins/actions.d/l8s.actions
+elif command -v apt-get >/dev/null 2>&1; then
+	echo 'DPkg::Post-Invoke { "%[3]s || true"; };' > /etc/apt/apt.conf.d/95l8s-record-packages
+fi
+`, imagePackagesPath, packageListScript, recordPackagesPath, recordPackagesScript(user))
+}
+
+// replayPackagesScript installs the packages in the manifest that the
+// container does not have, skipping any that are no longer available
+func replayPackagesScript(user string) string {
+	return fmt.Sprintf(`set -e
+manifest=%[1]s
+[ -s "$manifest" ] || exit 0
+missing=""
+for pkg in $(cat "$manifest"); do
+	if command -v dnf >/dev/null 2>&1; then
+		rpm -q "$pkg" >/dev/null 2>&1 || missing="$missing $pkg"
+	else
+		dpkg -s "$pkg" >/dev/null 2>&1 || missing="$missing $pkg"
+	fi
+done
+[ -n "$missing" ] || exit 0
+echo "Reinstalling:$missing"
+if command -v dnf >/dev/null 2>&1; then
+	mkdir -p /home/%[2]s/.cache/l8s
+	chown %[2]s: /home/%[2]s/.cache /home/%[2]s/.cache/l8s
+	dnf install -y --skip-unavailable --setopt=keepcache=True --setopt=cachedir=/home/%[2]s/.cache/l8s/dnf $missing
+elif command -v apt-get >/dev/null 2>&1; then
+	apt-get update
+	DEBIAN_FRONTEND=noninteractive apt-get install -y $missing
+fi
+`, PersistedPackagesPath(user), user)
+}
+
+// persistPackages installs the recording hook when persist_packages is on,
+// then reinstalls the packages recorded before a rebuild. Replayed packages
+// are recorded again, so they survive the next rebuild too.
+func (m *Manager) persistPackages(ctx context.Context, containerName string) error {
+	if !m.config.PersistPackages {
+		return nil
+	}
+	if _, err := m.client.ExecContainerOutput(ctx, containerName, []string{"sh", "-c", persistPackagesScript(m.config.ContainerUser)}); err != nil {
+		return fmt.Errorf("failed to install the package recording hook: %w", err)
+	}
+	if _, err := m.client.ExecContainerOutput(ctx, containerName, []string{"sh", "-c", replayPackagesScript(m.config.ContainerUser)}); err != nil {
+		return fmt.Errorf("failed to reinstall recorded packages: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPersistPackagesScripts(t *testing.T) {
+	assert.Equal(t, "/home/dev/.local/share/l8s/packages", PersistedPackagesPath("dev"))
+
+	record := recordPackagesScript("dev")
+	assert.Contains(t, record, "comm -23 - /etc/l8s/image-packages > /home/dev/.local/share/l8s/packages.tmp")
+	assert.Contains(t, record, "chown -R dev: /home/dev/.local/share/l8s")
+
+	install := persistPackagesScript("dev")
+	assert.Contains(t, install, "[ -f /etc/l8s/image-packages ] ||")
+	assert.Contains(t, install, "post_transaction::::/usr/local/libexec/l8s-record-packages")
+	assert.Contains(t, install, `DPkg::Post-Invoke { "/usr/local/libexec/l8s-record-packages || true"; };`)
+	assert.Contains(t, install, record)
+
+	replay := replayPackagesScript("dev")
+	assert.Contains(t, replay, "manifest=/home/dev/.local/share/l8s/packages")
+	assert.Contains(t, replay, "dnf install -y --skip-unavailable")
+	assert.Contains(t, replay, "apt-get install -y $missing")
+}
+
+func TestPersistPackages(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		client := new(MockPodmanClient)
+		manager := NewManager(client, Config{ContainerPrefix: "dev", ContainerUser: "dev"})
+		assert.NoError(t, manager.persistPackages(context.Background(), "dev-app"))
+		client.AssertNotCalled(t, "ExecContainerOutput", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		client := new(MockPodmanClient)
+		manager := NewManager(client, Config{ContainerPrefix: "dev", ContainerUser: "dev", PersistPackages: true})
+		client.On("ExecContainerOutput", mock.Anything, "dev-app", []string{"sh", "-c", persistPackagesScript("dev")}).Return("", nil).Once()
+		client.On("ExecContainerOutput", mock.Anything, "dev-app", []string{"sh", "-c", replayPackagesScript("dev")}).Return("Reinstalling: jq", nil).Once()
+		assert.NoError(t, manager.persistPackages(context.Background(), "dev-app"))
+		client.AssertExpectations(t)
+	})
+}
+
+func TestDriftIgnoresPackageHook(t *testing.T) {
+	files := driftFiles([]FileChange{
+		{Path: "/etc/l8s/image-packages", Kind: "A"},
+		{Path: recordPackagesPath, Kind: "A"},
+		{Path: "/etc/dnf/libdnf5-plugins/actions.d/l8s.actions", Kind: "A"},
+		{Path: "/etc/motd", Kind: "C"},
+	})
+	assert.Equal(t, []FileChange{{Path: "/etc/motd", Kind: "C"}}, files)
+}
@@ -7,11 +7,15 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -19,12 +23,17 @@ import (
 	"github.com/containers/common/libnetwork/types"
 	"github.com/containers/podman/v5/pkg/bindings"
 	"github.com/containers/podman/v5/pkg/bindings/containers"
+	"github.com/containers/podman/v5/pkg/bindings/images"
 	"github.com/containers/podman/v5/pkg/bindings/system"
 	"github.com/containers/podman/v5/pkg/specgen"
 	"github.com/containers/podman/v5/pkg/api/handlers"
+	"github.com/containers/podman/v5/version"
 	dockerContainer "github.com/docker/docker/api/types/container"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
 	"l8s/pkg/config"
 	"l8s/pkg/embed"
+	"l8s/pkg/logging"
+	"l8s/pkg/progress"
 )
 
 // RealPodmanClient implements PodmanClient using actual Podman bindings
@@ -38,6 +47,10 @@ type RealPodmanClient struct {
 	conn       context.Context
 	remoteHost string
 	remoteUser string
+	proxyJump  string       // ssh -J destination the remote host is reached through
+	sshPorts   [][2]int     // First and last port of each range SSH ports are allocated from
+	webPorts   [2]int       // First and last port web ports are allocated from
+	ports      *portCounter // Where SSH port allocation continues
 }
 
 // NewPodmanClient creates a new Podman client
@@ -47,7 +60,11 @@ func NewPodmanClient() (*RealPodmanClient, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
-	
+	return NewPodmanClientFor(cfg)
+}
+
+// NewPodmanClientFor creates a Podman client for the active connection of cfg
+func NewPodmanClientFor(cfg *config.Config) (*RealPodmanClient, error) {
 	// Get active connection address
 	address, err := cfg.GetActiveAddress()
 	if err != nil {
@@ -71,9 +88,20 @@ Note: l8s ONLY supports remote container management for security isolation.`, er
 	// See docs/REMOTE_SERVER_SETUP.md for detailed setup instructions
 	connectionURI := fmt.Sprintf("ssh://%s@%s%s",
 		cfg.RemoteUser,
-		address,
+		config.URLHost(address),
 		cfg.RemoteSocket,
 	)
+
+	// The bindings cannot go through a bastion, so hosts behind one are
+	// reached over a local socket tunnelled with ssh -J
+	jump := cfg.ActiveProxyJump()
+	if jump != "" {
+		socket, err := jumpSocket(jump, fmt.Sprintf("%s@%s", cfg.RemoteUser, address), cfg.RemoteSocket)
+		if err != nil {
+			return nil, err
+		}
+		connectionURI = "unix://" + socket
+	}
 	
 	// Verify ssh-agent is running
 	if _, exists := os.LookupEnv("SSH_AUTH_SOCK"); !exists {
@@ -89,6 +117,14 @@ l8s requires ssh-agent for secure remote connections.`, cfg.SSHKeyPath)
 	// Create connection using ssh-agent for authentication
 	ctx := context.Background()
 	conn, err := bindings.NewConnection(ctx, connectionURI)
+	if err != nil && strings.Contains(err.Error(), "server API version is too old") {
+		return nil, fmt.Errorf(`remote Podman on %s is too old for l8s.
+
+Error: %w
+
+l8s is tested with Podman %d.x-%d.x. Check the remote version with:
+  ssh %s@%s podman --version`, address, err, MinPodmanMajor, MaxPodmanMajor, cfg.RemoteUser, address)
+	}
 	if err != nil {
 		// Check if this is an SSH authentication error
 		errStr := err.Error()
@@ -117,7 +153,7 @@ Also verify you can SSH directly to the server:
 	}
 	
 	// Test connection
-	_, err = system.Info(conn, nil)
+	info, err := system.Info(conn, nil)
 	if err != nil {
 		// Check if this is also an SSH authentication error
 		errStr := err.Error()
@@ -165,14 +201,45 @@ For detailed setup instructions, see: docs/REMOTE_SERVER_SETUP.md`,
 			cfg.RemoteUser, address,
 			cfg.RemoteUser, address)
 	}
+	if info.Version.Version != "" {
+		if warning := PodmanVersionWarning(info.Version.Version); warning != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+	}
 	
+	var sshPorts [][2]int
+	for _, r := range cfg.SSHRanges() {
+		sshPorts = append(sshPorts, [2]int{r.First, r.Last})
+	}
+	web := cfg.WebPortRange()
 	return &RealPodmanClient{
 		conn:       conn,
 		remoteHost: address,
 		remoteUser: cfg.RemoteUser,
+		proxyJump:  jump,
+		sshPorts:   sshPorts,
+		webPorts:   [2]int{web.First, web.Last},
+		ports:      &portCounter{path: filepath.Join(config.StateDir(), PortCounterFile)},
 	}, nil
 }
 
+// Version reports the remote Podman and API versions
+func (c *RealPodmanClient) Version(ctx context.Context) (*RemoteVersion, error) {
+	report, err := system.Version(c.conn, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote Podman version: %w", err)
+	}
+	remote := &RemoteVersion{
+		MinAPIVersion: version.APIVersion[version.Libpod][version.MinimalAPI].String(),
+	}
+	if report.Server != nil {
+		remote.Podman = report.Server.Version
+		remote.APIVersion = report.Server.APIVersion
+		remote.OSArch = report.Server.OsArch
+	}
+	return remote, nil
+}
+
 // ContainerExists checks if a container exists
 func (c *RealPodmanClient) ContainerExists(ctx context.Context, name string) (bool, error) {
 	exists, err := containers.Exists(c.conn, name, nil)
@@ -203,10 +270,23 @@ func (c *RealPodmanClient) CreateContainer(ctx context.Context, config Container
 			Protocol:      "tcp",
 		},
 	}
+	for _, p := range config.Runtime.Ports {
+		s.PortMappings = append(s.PortMappings, types.PortMapping{
+			HostPort:      uint16(p.HostPort),
+			ContainerPort: uint16(p.ContainerPort),
+			Range:         uint16(p.Range),
+			Protocol:      p.Protocol,
+		})
+	}
 
 	// Create volumes
-	homeVolume := config.Name + "-home"
-	workspaceVolume := config.Name + "-workspace"
+	// Volumes are keyed on VolumeName so a replacement container can share them
+	volumeName := config.VolumeName
+	if volumeName == "" {
+		volumeName = config.Name
+	}
+	homeVolume := volumeName + "-home"
+	workspaceVolume := volumeName + "-workspace"
 	
 	s.Volumes = []*specgen.NamedVolume{
 		&specgen.NamedVolume{
@@ -221,11 +301,91 @@ func (c *RealPodmanClient) CreateContainer(ctx context.Context, config Container
 		},
 	}
 
+	// Attach host directories and shared volumes such as scratch space or datasets
+	for _, mount := range config.Mounts {
+		options := []string{}
+		if mount.ReadOnly {
+			options = append(options, "ro")
+		}
+		if mount.IsVolume() {
+			s.Volumes = append(s.Volumes, &specgen.NamedVolume{
+				Name:    mount.Source,
+				Dest:    mount.Target,
+				Options: options,
+			})
+			continue
+		}
+		s.Mounts = append(s.Mounts, spec.Mount{
+			Type:        "bind",
+			Source:      mount.Source,
+			Destination: mount.Target,
+			Options:     append(options, "rbind"),
+		})
+	}
+
+	// Larger /dev/shm and tmpfs mounts for browsers and test runners
+	if config.Runtime.ShmSize > 0 {
+		shmSize := config.Runtime.ShmSize
+		s.ShmSize = &shmSize
+	}
+	for _, t := range config.Runtime.Tmpfs {
+		options := []string{"rw", "nosuid", "nodev"}
+		if t.Size > 0 {
+			options = append(options, fmt.Sprintf("size=%d", t.Size))
+		}
+		s.Mounts = append(s.Mounts, spec.Mount{
+			Type:        "tmpfs",
+			Source:      "tmpfs",
+			Destination: t.Path,
+			Options:     options,
+		})
+	}
+
+	// Raise limits such as nofile for large builds and file watchers
+	for _, u := range config.Runtime.Ulimits {
+		s.Rlimits = append(s.Rlimits, spec.POSIXRlimit{
+			Type: "RLIMIT_" + strings.ToUpper(u.Name),
+			Soft: u.Soft,
+			Hard: u.Hard,
+		})
+	}
+	if len(config.Runtime.Sysctls) > 0 {
+		s.Sysctl = config.Runtime.Sysctls
+	}
+
+	// Debugger and profiler opt-ins (ptrace, perf); fail2ban bans with
+	// nftables in the container's network namespace
+	s.CapAdd = config.Runtime.CapAdd
+	if config.Runtime.Fail2ban && !slices.Contains(s.CapAdd, "NET_ADMIN") {
+		s.CapAdd = append(s.CapAdd, "NET_ADMIN")
+	}
+	if config.Runtime.SeccompUnconfined {
+		s.SeccompProfilePath = "unconfined"
+	}
+	// Nested podman needs /dev/fuse for fuse-overlayfs and no SELinux
+	// confinement; user namespaces use the image's subordinate ID ranges
+	selinuxOpts := config.Runtime.SELinuxOpts
+	if config.Runtime.Nested {
+		s.Devices = append(s.Devices, spec.LinuxDevice{Path: "/dev/fuse"})
+		selinuxOpts = append([]string{"disable"}, selinuxOpts...)
+	}
+	for _, opt := range selinuxOpts {
+		if opt == "nested" {
+			nested := true
+			s.LabelNested = &nested
+			continue
+		}
+		s.SelinuxOpts = append(s.SelinuxOpts, opt)
+	}
+
 	// Set environment variables
 	s.Env = map[string]string{
 		"USER": config.ContainerUser,
 		"HOME": fmt.Sprintf("/home/%s", config.ContainerUser),
 	}
+	for k, v := range config.Env {
+		s.Env[k] = v
+	}
 
 	// Audio uses TCP tunnel via SSH RemoteForward, not Unix socket
 	// Container connects to host gateway which tunnels to Mac's PulseAudio
@@ -233,8 +393,40 @@ func (c *RealPodmanClient) CreateContainer(ctx context.Context, config Container
 		s.Env["PULSE_SERVER"] = fmt.Sprintf("tcp:host.containers.internal:%d", config.AudioPort)
 	}
 
-	// Set command to run SSH daemon
-	s.Command = []string{"/usr/sbin/sshd", "-D"}
+	// Apply resource limits
+	if config.Memory > 0 || config.CPUs > 0 {
+		s.ResourceLimits = &spec.LinuxResources{}
+		if config.Memory > 0 {
+			memory := config.Memory
+			s.ResourceLimits.Memory = &spec.LinuxMemory{Limit: &memory}
+		}
+		if config.CPUs > 0 {
+			period := uint64(100000)
+			quota := int64(config.CPUs * float64(period))
+			s.ResourceLimits.CPU = &spec.LinuxCPU{Period: &period, Quota: &quota}
+		}
+	}
+
+	// Run the SSH daemon, with the idle agent alongside when the image ships it.
+	// Under systemd both are units enabled in the image. sshd logs to
+	// sshdLogFile, which fail2ban and 'l8s info' read.
+	if config.Runtime.Systemd {
+		s.Command = []string{"/sbin/init"}
+		if config.Runtime.Fail2ban {
+			s.Command = append(s.Command, "systemd.wants=fail2ban.service")
+		}
+		s.Systemd = "always"
+	} else {
+		// podman's init (catatonit) is PID 1 so sshd never is: it reaps
+		// orphaned user processes and forwards stop signals
+		useInit := true
+		s.Init = &useInit
+		script := "[ -x /usr/local/bin/l8s-idle-agent ] && /usr/local/bin/l8s-idle-agent & "
+		if config.Runtime.Fail2ban {
+			script += "mkdir -p /run/fail2ban; fail2ban-client -q start; "
+		}
+		s.Command = []string{"/bin/sh", "-c", script + "exec /usr/sbin/sshd -D -E " + sshdLogFile}
+	}
 
 	// Create the container
 	createResponse, err := containers.CreateWithSpec(c.conn, s, nil)
@@ -294,15 +486,215 @@ func (c *RealPodmanClient) RemoveContainer(ctx context.Context, name string, rem
 		
 		// Use exec to run podman volume rm commands
 		// We ignore errors as volumes might not exist or might have been removed
-		exec.Command("ssh", fmt.Sprintf("%s@%s", c.remoteUser, c.remoteHost), 
-			"sudo", "podman", "volume", "rm", "-f", homeVolume).Run()
-		exec.Command("ssh", fmt.Sprintf("%s@%s", c.remoteUser, c.remoteHost), 
-			"sudo", "podman", "volume", "rm", "-f", workspaceVolume).Run()
+		c.sshCommand(context.Background(), "sudo", "podman", "volume", "rm", "-f", homeVolume).Run()
+		c.sshCommand(context.Background(), "sudo", "podman", "volume", "rm", "-f", workspaceVolume).Run()
 	}
 	
 	return nil
 }
 
+// sshCommand runs command on the remote host over ssh, through the jump host
+// when one is configured
+func (c *RealPodmanClient) sshCommand(ctx context.Context, command ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, "ssh", hostSSHArgs(c.proxyJump, fmt.Sprintf("%s@%s", c.remoteUser, c.remoteHost), command...)...)
+}
+
+// RenameContainer renames an existing container
+func (c *RealPodmanClient) RenameContainer(ctx context.Context, name, newName string) error {
+	return containers.Rename(c.conn, name, &containers.RenameOptions{
+		Name: &newName,
+	})
+}
+
+// CopyVolume creates dst and fills it with the contents of src on the remote host
+func (c *RealPodmanClient) CopyVolume(ctx context.Context, src, dst string) error {
+	// Volume export/import streams a tar archive, preserving ownership and modes
+	script := fmt.Sprintf("sudo podman volume create %s >/dev/null && sudo podman volume export %s | sudo podman volume import %s -",
+		dst, src, dst)
+	output, err := c.sshCommand(ctx, script).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to copy volume %s to %s: %w\nOutput: %s", src, dst, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// RemoveVolume removes a named volume on the remote host
+func (c *RealPodmanClient) RemoveVolume(ctx context.Context, name string) error {
+	output, err := c.sshCommand(ctx, "sudo", "podman", "volume", "rm", "-f", name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to remove volume %s: %w\nOutput: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ExportVolume streams a tar archive of a named volume to w
+func (c *RealPodmanClient) ExportVolume(ctx context.Context, name string, w io.Writer) error {
+	var stderr bytes.Buffer
+	cmd := c.sshCommand(ctx, "sudo", "podman", "volume", "export", name)
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to export volume %s: %w\nOutput: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// ListVolumes returns the names of all volumes on the remote host
+func (c *RealPodmanClient) ListVolumes(ctx context.Context) ([]string, error) {
+	output, err := c.sshCommand(ctx, "sudo", "podman", "volume", "ls", "--format", "'{{.Name}}'").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// InspectContainer returns the full podman inspect document of an l8s-managed container as JSON
+func (c *RealPodmanClient) InspectContainer(ctx context.Context, name string) ([]byte, error) {
+	inspect, err := containers.Inspect(c.conn, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	if managed, ok := inspect.Config.Labels[LabelManaged]; !ok || managed != "true" {
+		return nil, fmt.Errorf("container '%s' is not managed by l8s", name)
+	}
+	return json.MarshalIndent(inspect, "", "    ")
+}
+
+// DiffContainer lists the files changed in a container since it was created from its image
+func (c *RealPodmanClient) DiffContainer(ctx context.Context, name string) ([]FileChange, error) {
+	diffType := "container"
+	changes, err := containers.Diff(c.conn, name, &containers.DiffOptions{DiffType: &diffType})
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff container: %w", err)
+	}
+	result := make([]FileChange, len(changes))
+	for i, change := range changes {
+		result[i] = FileChange{Path: change.Path, Kind: change.Kind.String()}
+	}
+	return result, nil
+}
+
+// RunImage runs cmd in a throwaway container from image and returns its stdout
+func (c *RealPodmanClient) RunImage(ctx context.Context, image string, cmd []string) (string, error) {
+	s := specgen.NewSpecGenerator(image, false)
+	s.Name = fmt.Sprintf("l8s-run-%d", time.Now().UnixNano())
+	// Kept alive for the exec instead of starting sshd
+	s.Entrypoint = []string{"sleep"}
+	s.Command = []string{"infinity"}
+	created, err := containers.CreateWithSpec(c.conn, s, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create container from %s: %w", image, err)
+	}
+	defer func() {
+		force := true
+		containers.Remove(c.conn, created.ID, &containers.RemoveOptions{Force: &force})
+	}()
+	if err := containers.Start(c.conn, created.ID, nil); err != nil {
+		return "", fmt.Errorf("failed to start container from %s: %w", image, err)
+	}
+	return c.ExecContainerOutput(ctx, created.ID, cmd)
+}
+
+// DescribeContainer inspects any container on the host, including ones l8s
+// did not create, for 'l8s adopt'
+func (c *RealPodmanClient) DescribeContainer(ctx context.Context, name string) (*ForeignContainer, error) {
+	inspect, err := containers.Inspect(c.conn, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	foreign := &ForeignContainer{
+		Name:   strings.TrimPrefix(inspect.Name, "/"),
+		Image:  inspect.ImageName,
+		Status: inspect.State.Status,
+		User:   inspect.Config.User,
+		Labels: inspect.Config.Labels,
+	}
+	if inspect.NetworkSettings != nil {
+		for port, target := range map[string]*int{"22/tcp": &foreign.SSHPort, "3000/tcp": &foreign.WebPort} {
+			if hostPorts, ok := inspect.NetworkSettings.Ports[port]; ok && len(hostPorts) > 0 {
+				if p, err := strconv.Atoi(hostPorts[0].HostPort); err == nil {
+					*target = p
+				}
+			}
+		}
+	}
+	for _, m := range inspect.Mounts {
+		switch m.Type {
+		case "volume":
+			foreign.Mounts = append(foreign.Mounts, Mount{Source: m.Name, Target: m.Destination, ReadOnly: !m.RW})
+		case "bind":
+			foreign.Mounts = append(foreign.Mounts, Mount{Source: m.Source, Target: m.Destination, ReadOnly: !m.RW})
+		}
+	}
+
+	return foreign, nil
+}
+
+// CommitContainer saves a container's filesystem as image. Volumes are not
+// part of the image.
+func (c *RealPodmanClient) CommitContainer(ctx context.Context, name, image string) error {
+	repo, tag, _ := strings.Cut(image, ":")
+	if tag == "" {
+		tag = "latest"
+	}
+	if _, err := containers.Commit(c.conn, name, &containers.CommitOptions{Repo: &repo, Tag: &tag}); err != nil {
+		return fmt.Errorf("failed to commit container %s to %s: %w", name, image, err)
+	}
+	return nil
+}
+
+// RemoveImage deletes image. An image a container was created from only
+// loses its name, and its layers go with the container.
+func (c *RealPodmanClient) RemoveImage(ctx context.Context, image string) error {
+	_, errs := images.Remove(c.conn, []string{image}, nil)
+	if len(errs) == 0 {
+		return nil
+	}
+	err := errors.Join(errs...)
+	if !strings.Contains(err.Error(), "in use by a container") {
+		return fmt.Errorf("failed to remove image %s: %w", image, err)
+	}
+	repo, tag, _ := strings.Cut(image, ":")
+	if tag == "" {
+		tag = "latest"
+	}
+	if err := images.Untag(c.conn, image, tag, repo, nil); err != nil {
+		return fmt.Errorf("failed to untag image %s: %w", image, err)
+	}
+	return nil
+}
+
+// DiskUsage reports the storage used by every image, container and volume
+// on the host, as podman system df -v does
+func (c *RealPodmanClient) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	report, err := system.DiskUsage(c.conn, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disk usage: %w", err)
+	}
+	usage := &DiskUsage{}
+	for _, image := range report.Images {
+		name := ""
+		if image.Repository != "<none>" {
+			name = image.Repository + ":" + image.Tag
+		}
+		usage.Images = append(usage.Images, ImageUsage{
+			Name:       name,
+			ID:         image.ImageID,
+			Size:       image.Size,
+			UniqueSize: image.UniqueSize,
+			Containers: image.Containers,
+		})
+	}
+	for _, cont := range report.Containers {
+		usage.Containers = append(usage.Containers, ContainerUsage{Name: cont.Names, Size: cont.RWSize})
+	}
+	for _, volume := range report.Volumes {
+		usage.Volumes = append(usage.Volumes, VolumeUsage{Name: volume.VolumeName, Size: volume.Size, InUse: volume.Links > 0})
+	}
+	return usage, nil
+}
+
 // ListContainers lists all l8s-managed containers
 func (c *RealPodmanClient) ListContainers(ctx context.Context) ([]*Container, error) {
 	// List containers with l8s.managed label
@@ -360,6 +752,7 @@ func (c *RealPodmanClient) ListContainers(ctx context.Context) ([]*Container, er
 			WebPort:   webPort,
 			CreatedAt: c.Created,
 			Labels:    c.Labels,
+			Image:     c.Image,
 		}
 		result = append(result, container)
 	}
@@ -420,45 +813,83 @@ func (c *RealPodmanClient) GetContainerInfo(ctx context.Context, name string) (*
 		WebPort:   webPort,
 		CreatedAt: inspect.Created,
 		Labels:    inspect.Config.Labels,
+		Image:     inspect.ImageName,
+
+		StartedAt:     inspect.State.StartedAt,
+		FinishedAt:    inspect.State.FinishedAt,
+		RestartCount:  int(inspect.RestartCount),
+		ExitCode:      int(inspect.State.ExitCode),
+		OOMKilled:     inspect.State.OOMKilled,
+		StoppedByUser: inspect.State.StoppedByUser,
+	}
+	if inspect.State.Health != nil {
+		container.Health = inspect.State.Health.Status
 	}
 
 	return container, nil
 }
 
-// FindAvailablePort finds an available port starting from the given port
+// FindAvailablePort finds a free port in the configured ranges startPort
+// falls in, trying startPort first and wrapping around at the end of the
+// ranges. SSH ports instead continue from where the last allocation left off,
+// so a busy host is not scanned from the start every time; web ports are
+// asked for at their SSH port's offset and keep it when it is free. Ports of
+// stopped containers stay reserved so they can start again.
 func (c *RealPodmanClient) FindAvailablePort(startPort int) (int, error) {
-	// Get all running containers to check which ports are in use on the remote
-	containers, err := c.ListContainers(context.Background())
+	ranges := portRangesFor(startPort, c.sshPorts, c.webPorts)
+	key := net.JoinHostPort(c.remoteHost, strconv.Itoa(ranges[0][0]))
+	counted := startPort == c.sshPorts[0][0]
+	first := startPort
+	if counted {
+		if next := c.ports.next(key); next != 0 {
+			first = next
+		}
+	}
+
+	port, err := allocatePort(ranges, first, func(port int) (bool, error) {
+		return c.portInUse(context.Background(), port)
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to list containers: %w", err)
+		return 0, err
 	}
-	
-	// Build a map of ports in use by running containers
-	portsInUse := make(map[int]bool)
-	for _, container := range containers {
-		// Only consider running containers
-		if container.Status == "running" {
-			if container.SSHPort > 0 {
-				portsInUse[container.SSHPort] = true
-			}
-			if container.WebPort > 0 {
-				portsInUse[container.WebPort] = true
-			}
-		}
+
+	if counted {
+		// Losing the counter only costs a longer search next time
+		_ = c.ports.advance(key, nextPort(ranges, port))
 	}
-	
-	// Find the first available port
-	for port := startPort; port < startPort+100; port++ {
-		if !portsInUse[port] {
-			return port, nil
+	return port, nil
+}
+
+// portInUse reports whether any l8s container, running or not, has port as
+// its SSH or web port. Each check is a label query, so its cost does not
+// grow with the number of containers.
+func (c *RealPodmanClient) portInUse(ctx context.Context, port int) (bool, error) {
+	all := true
+	for _, label := range []string{LabelSSHPort, LabelWebPort} {
+		list, err := containers.List(c.conn, &containers.ListOptions{
+			All: &all,
+			Filters: map[string][]string{
+				"label": {fmt.Sprintf("%s=%d", label, port)},
+			},
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to check port %d: %w", port, err)
+		}
+		if len(list) > 0 {
+			return true, nil
 		}
 	}
-	
-	return 0, fmt.Errorf("no available ports found in range %d-%d", startPort, startPort+100)
+	return false, nil
 }
 
 // ExecContainer executes a command in a container
 func (c *RealPodmanClient) ExecContainer(ctx context.Context, name string, cmd []string) error {
+	_, err := c.ExecContainerOutput(ctx, name, cmd)
+	return err
+}
+
+// ExecContainerOutput executes a command in a container and returns its stdout
+func (c *RealPodmanClient) ExecContainerOutput(ctx context.Context, name string, cmd []string) (string, error) {
 	// Create output buffers
 	var stdout, stderr bytes.Buffer
 	
@@ -475,7 +906,7 @@ func (c *RealPodmanClient) ExecContainer(ctx context.Context, name string, cmd [
 
 	execID, err := containers.ExecCreate(c.conn, name, execConfig)
 	if err != nil {
-		return fmt.Errorf("failed to create exec session: %w", err)
+		return "", fmt.Errorf("failed to create exec session: %w", err)
 	}
 
 	// Start and attach to capture output
@@ -491,19 +922,19 @@ func (c *RealPodmanClient) ExecContainer(ctx context.Context, name string, cmd [
 	if err := containers.ExecStartAndAttach(c.conn, execID, attachOptions); err != nil {
 		// If attach fails, try regular start
 		if err := containers.ExecStart(c.conn, execID, nil); err != nil {
-			return fmt.Errorf("failed to start exec session: %w", err)
+			return "", fmt.Errorf("failed to start exec session: %w", err)
 		}
 		
 		// Wait for completion
 		for {
 			inspect, err := containers.ExecInspect(c.conn, execID, nil)
 			if err != nil {
-				return fmt.Errorf("failed to inspect exec session: %w", err)
+				return "", fmt.Errorf("failed to inspect exec session: %w", err)
 			}
 
 			if !inspect.Running {
 				if inspect.ExitCode != 0 {
-					return fmt.Errorf("command exited with code %d", inspect.ExitCode)
+					return "", fmt.Errorf("command exited with code %d", inspect.ExitCode)
 				}
 				break
 			}
@@ -515,17 +946,55 @@ func (c *RealPodmanClient) ExecContainer(ctx context.Context, name string, cmd [
 	// Check exit status
 	inspect, err := containers.ExecInspect(c.conn, execID, nil)
 	if err != nil {
-		return fmt.Errorf("failed to inspect exec session: %w", err)
+		return "", fmt.Errorf("failed to inspect exec session: %w", err)
 	}
 
 	if inspect.ExitCode != 0 {
 		errOutput := strings.TrimSpace(stderr.String())
 		if errOutput != "" {
-			return fmt.Errorf("command exited with code %d: %s", inspect.ExitCode, errOutput)
+			return "", fmt.Errorf("command exited with code %d: %s", inspect.ExitCode, errOutput)
 		}
-		return fmt.Errorf("command exited with code %d", inspect.ExitCode)
+		return "", fmt.Errorf("command exited with code %d", inspect.ExitCode)
 	}
 
+	return stdout.String(), nil
+}
+
+// ExecContainerStream executes a command in a container, copying its output
+// to stdout and stderr as it is produced
+func (c *RealPodmanClient) ExecContainerStream(ctx context.Context, name string, cmd []string, stdout, stderr io.Writer) error {
+	attachStderr := true
+	attachStdout := true
+	execConfig := &handlers.ExecCreateConfig{
+		ExecOptions: dockerContainer.ExecOptions{
+			Cmd:          cmd,
+			AttachStderr: attachStderr,
+			AttachStdout: attachStdout,
+		},
+	}
+
+	execID, err := containers.ExecCreate(c.conn, name, execConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create exec session: %w", err)
+	}
+
+	attachOptions := &containers.ExecStartAndAttachOptions{
+		OutputStream: &stdout,
+		ErrorStream:  &stderr,
+		AttachOutput: &attachStdout,
+		AttachError:  &attachStderr,
+	}
+	if err := containers.ExecStartAndAttach(c.conn, execID, attachOptions); err != nil {
+		return fmt.Errorf("failed to attach to exec session: %w", err)
+	}
+
+	inspect, err := containers.ExecInspect(c.conn, execID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to inspect exec session: %w", err)
+	}
+	if inspect.ExitCode != 0 {
+		return fmt.Errorf("command exited with code %d", inspect.ExitCode)
+	}
 	return nil
 }
 
@@ -640,56 +1109,126 @@ func (c *RealPodmanClient) CopyToContainer(ctx context.Context, name string, src
 	return nil
 }
 
-// BuildImage builds the container image on the remote server using the embedded Containerfile
-func BuildImage(ctx context.Context, imageName string) error {
+// BuildImage builds the container image on the remote server using the embedded
+// Containerfile for a distribution flavor (empty means fedora), reporting how
+// many steps came from the layer cache
+func BuildImage(ctx context.Context, imageName, flavor string, opts BuildOptions) (stats BuildStats, err error) {
+	op := progress.Start("build", 3)
+	defer func() {
+		op.Done(err)
+	}()
+
 	// Load configuration to get remote details
+	op.Step("prepare", "Preparing build context")
 	cfg, err := config.Load(config.GetConfigPath())
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return stats, fmt.Errorf("failed to load config: %w", err)
 	}
 	
 	// Get active connection address
 	address, err := cfg.GetActiveAddress()
 	if err != nil {
-		return fmt.Errorf("failed to get active connection: %w", err)
+		return stats, fmt.Errorf("failed to get active connection: %w", err)
 	}
 
 	// Extract the embedded Containerfile to a temporary location
-	containerfilePath, err := embed.ExtractContainerfile()
+	containerfilePath, err := embed.ExtractContainerfileFlavor(flavor)
 	if err != nil {
-		return fmt.Errorf("failed to extract embedded Containerfile: %w", err)
+		return stats, fmt.Errorf("failed to extract embedded Containerfile: %w", err)
 	}
 	defer os.RemoveAll(filepath.Dir(containerfilePath)) // Clean up temp dir
+	if opts.Fragment != "" {
+		if err := appendFragment(containerfilePath, opts.Fragment, opts.Variant); err != nil {
+			return stats, err
+		}
+	}
 
 	// Create a temporary directory on the remote server
 	tempDir := fmt.Sprintf("/tmp/l8s-build-%d", time.Now().Unix())
-	mkdirCmd := fmt.Sprintf("ssh %s@%s 'mkdir -p %s'", cfg.RemoteUser, address, tempDir)
-	if err := runCommand(mkdirCmd); err != nil {
-		return fmt.Errorf("failed to create temp directory on remote: %w", err)
+	target := fmt.Sprintf("%s@%s", cfg.RemoteUser, address)
+	jump := cfg.ActiveProxyJump()
+	if err := runCommand("ssh", hostSSHArgs(jump, target, "mkdir -p "+tempDir)...); err != nil {
+		return stats, fmt.Errorf("failed to create temp directory on remote: %w", err)
 	}
 	
-	// Copy the Containerfile to the remote server
-	remotePath := filepath.Join(tempDir, "Containerfile")
-	scpCmd := fmt.Sprintf("scp %s %s@%s:%s", containerfilePath, cfg.RemoteUser, address, remotePath)
-	if err := runCommand(scpCmd); err != nil {
-		return fmt.Errorf("failed to copy Containerfile to remote: %w", err)
+	// Copy the Containerfile and its build context to the remote server
+	op.Step("upload", "Uploading build context")
+	contextFiles := []string{containerfilePath}
+	for _, file := range embed.BuildContextFiles {
+		contextFiles = append(contextFiles, filepath.Join(filepath.Dir(containerfilePath), file))
+	}
+	terminal := io.Writer(os.Stdout)
+	if opts.Quiet {
+		terminal = io.Discard
+	}
+	// scp needs IPv6 literals bracketed to tell the address from the path
+	scpTarget := fmt.Sprintf("%s@%s:%s/", cfg.RemoteUser, config.URLHost(address), tempDir)
+	var scpArgs []string
+	if jump != "" {
+		scpArgs = append(scpArgs, "-J", jump)
+	}
+	scpArgs = append(append(scpArgs, contextFiles...), scpTarget)
+	if err := runCommandTo(terminal, "scp", scpArgs...); err != nil {
+		return stats, fmt.Errorf("failed to copy Containerfile to remote: %w", err)
 	}
 	
 	// Build the image on the remote server using sudo podman with container user and cache busting
-	buildCmd := fmt.Sprintf("ssh %s@%s 'sudo podman build --build-arg CONTAINER_USER=%s --build-arg CACHEBUST=%d -t %s %s && rm -rf %s'", 
-		cfg.RemoteUser, address, cfg.ContainerUser, time.Now().Unix(), imageName, tempDir, tempDir)
+	// The flavor is recorded as an image label so it can be checked later
+	if flavor == "" {
+		flavor = embed.DefaultFlavor
+	}
+	cacheBust, err := buildCacheBust(contextFiles, []string{cfg.ContainerUser, flavor}, opts)
+	if err != nil {
+		return stats, err
+	}
+	var extraArgs string
+	if opts.NoCache {
+		extraArgs = "--no-cache "
+	}
+	logName := flavor
+	if opts.Variant != "" {
+		extraArgs += fmt.Sprintf("--label %s=%s ", LabelImageVariant, opts.Variant)
+		logName += "-" + opts.Variant
+	}
+	buildCmd := fmt.Sprintf("sudo podman build %s--build-arg CONTAINER_USER=%s --build-arg CACHEBUST=%s --label %s=%s -t %s %s && rm -rf %s", 
+		extraArgs, cfg.ContainerUser, cacheBust, LabelFlavor, flavor, imageName, tempDir, tempDir)
 	
-	if err := runCommand(buildCmd); err != nil {
-		return fmt.Errorf("failed to build image on remote: %w", err)
+	op.Step("build", "Building image")
+	output := &buildOutput{out: op.BuildWriter(terminal), logger: logging.Default(), stats: &stats}
+	if logFile, err := createBuildLog(filepath.Join(config.CacheDir(), "build-logs"), logName); err != nil {
+		logging.Warn("build output will not be saved", logging.WithError(err))
+	} else {
+		defer logFile.Close()
+		output.log = logFile
+		stats.LogPath = logFile.Name()
+	}
+	execCmd := exec.Command("ssh", hostSSHArgs(jump, target, buildCmd)...)
+	execCmd.Stdout = output
+	execCmd.Stderr = output
+	if err := logging.Run(execCmd); err != nil {
+		err = fmt.Errorf("failed to build image on remote: %w", err)
+		if opts.Quiet {
+			err = fmt.Errorf("%w\n%s", err, output.Tail())
+		}
+		if stats.LogPath != "" {
+			err = fmt.Errorf("%w\nFull build log: %s", err, stats.LogPath)
+		}
+		return stats, err
 	}
 
-	return nil
+	return stats, nil
+}
+
+// runCommand executes a command and returns any error. Commands are run
+// directly rather than through sh so builds also work from Windows hosts.
+func runCommand(name string, args ...string) error {
+	return runCommandTo(os.Stdout, name, args...)
 }
 
-// runCommand executes a shell command and returns any error
-func runCommand(cmd string) error {
-	execCmd := exec.Command("sh", "-c", cmd)
-	execCmd.Stdout = os.Stdout
+// runCommandTo executes a command with its stdout sent to stdout
+func runCommandTo(stdout io.Writer, name string, args ...string) error {
+	execCmd := exec.Command(name, args...)
+	execCmd.Stdout = stdout
 	execCmd.Stderr = os.Stderr
-	return execCmd.Run()
+	return logging.Run(execCmd)
 }
\ No newline at end of file
@@ -0,0 +1,184 @@
+package container
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"l8s/pkg/config"
+)
+
+// PortCounterFile is the file in the l8s state directory that remembers
+// where port allocation continues
+const PortCounterFile = "ports.json"
+
+// PortExhaustedError reports that every port in the allocation ranges is taken
+type PortExhaustedError struct {
+	Ranges [][2]int // First and last port of each range
+}
+
+func (e *PortExhaustedError) Error() string {
+	ranges := make([]string, len(e.Ranges))
+	for i, r := range e.Ranges {
+		ranges[i] = fmt.Sprintf("%d-%d", r[0], r[1])
+	}
+	return fmt.Sprintf("no free ports: all of %s are taken", strings.Join(ranges, ", "))
+}
+
+// portCounter persists the next port to try in each range, so allocation
+// continues after the last port handed out instead of scanning from the
+// start of the range every time
+type portCounter struct {
+	path string
+}
+
+// next returns the port allocation in the range keyed by key continues from,
+// or 0 if none is recorded
+func (p *portCounter) next(key string) int {
+	counters, err := p.read()
+	if err != nil {
+		return 0
+	}
+	return counters[key]
+}
+
+// advance records that allocation in the range keyed by key continues from port
+func (p *portCounter) advance(key string, port int) error {
+	counters, err := p.read()
+	if err != nil {
+		// A corrupt file only costs a scan from the start of the range
+		counters = make(map[string]int)
+	}
+	counters[key] = port
+	return p.write(counters)
+}
+
+func (p *portCounter) read() (map[string]int, error) {
+	counters := make(map[string]int)
+	data, err := os.ReadFile(p.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return counters, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read port counters: %w", err)
+	}
+	if err := json.Unmarshal(data, &counters); err != nil {
+		return nil, fmt.Errorf("failed to parse port counters %s: %w", p.path, err)
+	}
+	return counters, nil
+}
+
+// write replaces the file atomically so concurrent runs never see a partial file
+func (p *portCounter) write(counters map[string]int) error {
+	data, err := json.MarshalIndent(counters, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode port counters: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p.path), ".ports-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write port counters: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write port counters: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write port counters: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p.path); err != nil {
+		return fmt.Errorf("failed to write port counters: %w", err)
+	}
+	return nil
+}
+
+// allocatePort returns the first port inUse reports free, going through
+// ranges in order from first and wrapping around to the start of the first
+// range. Each candidate is verified rather than trusted, since containers
+// come and go without the counter knowing.
+func allocatePort(ranges [][2]int, first int, inUse func(port int) (bool, error)) (int, error) {
+	size := 0
+	for _, r := range ranges {
+		size += r[1] - r[0] + 1
+	}
+	offset, ok := rangeOffset(ranges, first)
+	if !ok {
+		offset = 0
+	}
+	for i := 0; i < size; i++ {
+		port := portAt(ranges, (offset+i)%size)
+		used, err := inUse(port)
+		if err != nil {
+			return 0, err
+		}
+		if !used {
+			return port, nil
+		}
+	}
+	return 0, &PortExhaustedError{Ranges: ranges}
+}
+
+// rangeOffset returns how many ports of ranges come before port, or false if
+// port is in none of them
+func rangeOffset(ranges [][2]int, port int) (int, bool) {
+	offset := 0
+	for _, r := range ranges {
+		if port >= r[0] && port <= r[1] {
+			return offset + port - r[0], true
+		}
+		offset += r[1] - r[0] + 1
+	}
+	return 0, false
+}
+
+// portAt returns the port offset ports into ranges
+func portAt(ranges [][2]int, offset int) int {
+	for _, r := range ranges {
+		if size := r[1] - r[0] + 1; offset >= size {
+			offset -= size
+			continue
+		}
+		return r[0] + offset
+	}
+	return 0
+}
+
+// nextPort returns the port after port in ranges, wrapping around to the
+// start of the first range
+func nextPort(ranges [][2]int, port int) int {
+	size := 0
+	for _, r := range ranges {
+		size += r[1] - r[0] + 1
+	}
+	offset, _ := rangeOffset(ranges, port)
+	return portAt(ranges, (offset+1)%size)
+}
+
+// portRangesFor returns the SSH ranges when startPort is in one of them, the
+// web range when it is in that, or config.DefaultPortRange ports from
+// startPort when it is in neither
+func portRangesFor(startPort int, ssh [][2]int, web [2]int) [][2]int {
+	if _, ok := rangeOffset(ssh, startPort); ok {
+		return ssh
+	}
+	if startPort >= web[0] && startPort <= web[1] {
+		return [][2]int{web}
+	}
+	return [][2]int{{startPort, min(startPort+config.DefaultPortRange-1, 65535)}}
+}
+
+// portOffset returns how far into the SSH port ranges sshPort is. A
+// container's web port is the same distance from WebPortStart.
+func (m *Manager) portOffset(sshPort int) int {
+	if offset, ok := rangeOffset(m.config.SSHPortRanges, sshPort); ok {
+		return offset
+	}
+	return sshPort - m.config.SSHPortStart
+}
@@ -0,0 +1,100 @@
+package container
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocatePort(t *testing.T) {
+	used := map[int]bool{2200: true, 2203: true, 2204: true}
+	inUse := func(port int) (bool, error) { return used[port], nil }
+	ranges := [][2]int{{2200, 2204}}
+
+	port, err := allocatePort(ranges, 2200, inUse)
+	require.NoError(t, err)
+	assert.Equal(t, 2201, port)
+
+	port, err = allocatePort(ranges, 2203, inUse)
+	require.NoError(t, err)
+	assert.Equal(t, 2201, port, "wraps around to the start of the range")
+
+	port, err = allocatePort(ranges, 9999, inUse)
+	require.NoError(t, err)
+	assert.Equal(t, 2201, port, "a stale counter outside the range starts over")
+
+	_, err = allocatePort([][2]int{{2200, 2200}, {4400, 4400}}, 2200, func(int) (bool, error) { return true, nil })
+	var exhausted *PortExhaustedError
+	require.ErrorAs(t, err, &exhausted)
+	assert.Equal(t, [][2]int{{2200, 2200}, {4400, 4400}}, exhausted.Ranges)
+	assert.EqualError(t, err, "no free ports: all of 2200-2200, 4400-4400 are taken")
+
+	_, err = allocatePort(ranges, 2200, func(int) (bool, error) { return false, errors.New("connection reset") })
+	assert.ErrorContains(t, err, "connection reset")
+}
+
+func TestAllocatePort_MultipleRanges(t *testing.T) {
+	ranges := [][2]int{{2200, 2201}, {4400, 4401}}
+	used := map[int]bool{2200: true, 2201: true}
+	inUse := func(port int) (bool, error) { return used[port], nil }
+
+	port, err := allocatePort(ranges, 2201, inUse)
+	require.NoError(t, err)
+	assert.Equal(t, 4400, port, "continues into the next range")
+
+	used[4400], used[4401] = true, true
+	delete(used, 2200)
+	port, err = allocatePort(ranges, 4401, inUse)
+	require.NoError(t, err)
+	assert.Equal(t, 2200, port, "wraps around to the first range")
+
+	assert.Equal(t, 4400, nextPort(ranges, 2201))
+	assert.Equal(t, 2200, nextPort(ranges, 4401))
+}
+
+func TestRangeOffset(t *testing.T) {
+	ranges := [][2]int{{2200, 2299}, {4400, 4499}}
+
+	offset, ok := rangeOffset(ranges, 2250)
+	assert.True(t, ok)
+	assert.Equal(t, 50, offset)
+	offset, ok = rangeOffset(ranges, 4400)
+	assert.True(t, ok)
+	assert.Equal(t, 100, offset)
+	_, ok = rangeOffset(ranges, 3000)
+	assert.False(t, ok)
+
+	assert.Equal(t, 2250, portAt(ranges, 50))
+	assert.Equal(t, 4401, portAt(ranges, 101))
+	assert.Zero(t, portAt(ranges, 200))
+}
+
+func TestPortCounter(t *testing.T) {
+	counter := &portCounter{path: filepath.Join(t.TempDir(), "state", PortCounterFile)}
+	assert.Zero(t, counter.next("10.0.0.5:2200"))
+
+	require.NoError(t, counter.advance("10.0.0.5:2200", 2207))
+	require.NoError(t, counter.advance("100.64.0.5:2200", 2201))
+	assert.Equal(t, 2207, counter.next("10.0.0.5:2200"))
+	assert.Equal(t, 2201, counter.next("100.64.0.5:2200"))
+}
+
+func TestPortRangesFor(t *testing.T) {
+	ssh, web := [][2]int{{2200, 2299}, {4400, 4499}}, [2]int{3000, 3199}
+
+	assert.Equal(t, ssh, portRangesFor(4400, ssh, web))
+	assert.Equal(t, [][2]int{{3000, 3199}}, portRangesFor(3017, ssh, web))
+	assert.Equal(t, [][2]int{{8000, 8999}}, portRangesFor(8000, ssh, web))
+}
+
+func TestManager_PortOffset(t *testing.T) {
+	m := &Manager{config: Config{SSHPortStart: 2200, SSHPortRanges: [][2]int{{2200, 2299}, {4400, 4499}}}}
+	assert.Equal(t, 5, m.portOffset(2205))
+	assert.Equal(t, 105, m.portOffset(4405))
+
+	m = &Manager{config: Config{SSHPortStart: 2200}}
+	assert.Equal(t, 7, m.portOffset(2207), "no ranges means one range from SSHPortStart")
+}
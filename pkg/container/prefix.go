@@ -0,0 +1,126 @@
+package container
+
+import (
+	"context"
+	"fmt"
+
+	"l8s/pkg/cleanup"
+	"l8s/pkg/logging"
+)
+
+// migratedImage is the image a container's filesystem is committed to when it
+// moves to a new prefix
+func migratedImage(containerName string) string {
+	return "localhost/" + containerName + "-migrated:latest"
+}
+
+// MigratePrefix moves the container for name from oldPrefix to newPrefix.
+// Podman cannot rename volumes, so they are copied to the new names and the
+// container is recreated under the new name from a commit of its filesystem,
+// keeping its ports and labels. The original and its volumes are removed only
+// once the new container exists. SSH config entries and git remotes are left
+// to the caller, which knows when the new prefix becomes active.
+func (m *Manager) MigratePrefix(ctx context.Context, name, oldPrefix, newPrefix string) (_ *Container, err error) {
+	cleaner := cleanup.New(m.logger)
+	defer cleaner.CleanupOnError(context.WithoutCancel(ctx), &err)
+
+	oldName := oldPrefix + "-" + name
+	newName := newPrefix + "-" + name
+
+	containerInfo, err := m.client.GetContainerInfo(ctx, oldName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container info: %w", err)
+	}
+	if containerInfo.SSHPort == 0 {
+		return nil, fmt.Errorf("container has no SSH port configured")
+	}
+	exists, err := m.client.ContainerExists(ctx, newName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check container existence: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("container '%s' already exists", newName)
+	}
+
+	// Stop first so the volumes are copied in a consistent state
+	if err := m.client.StopContainer(ctx, oldName); err != nil {
+		m.logger.Debug("container stop failed (may already be stopped)",
+			logging.WithError(err))
+	}
+	if containerInfo.Status == "running" {
+		cleaner.Add("restart_container", func(ctx context.Context) error {
+			return m.client.StartContainer(ctx, oldName)
+		})
+	}
+
+	image := migratedImage(newName)
+	if err := m.client.CommitContainer(ctx, oldName, image); err != nil {
+		return nil, err
+	}
+	cleaner.Add("remove_image", func(ctx context.Context) error {
+		return m.client.RemoveImage(ctx, image)
+	})
+
+	oldVolumes := containerVolumes(oldName)
+	for i, dst := range containerVolumes(newName) {
+		src := oldVolumes[i]
+		m.logger.Debug("copying volume",
+			logging.WithField("source", src),
+			logging.WithField("destination", dst))
+		if err := m.client.CopyVolume(ctx, src, dst); err != nil {
+			return nil, fmt.Errorf("failed to copy volume: %w", err)
+		}
+		volume := dst
+		cleaner.Add("remove_volume_"+volume, func(ctx context.Context) error {
+			return m.client.RemoveVolume(ctx, volume)
+		})
+	}
+
+	config := m.recreateConfig(containerInfo, containerInfo.Labels)
+	config.Name = newName
+	config.BaseImage = image
+
+	m.logger.Info("migrating container",
+		logging.WithField("container", oldName),
+		logging.WithField("new_name", newName))
+
+	container, err := m.client.CreateContainer(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container: %w", err)
+	}
+	cleaner.Add("remove_container", func(ctx context.Context) error {
+		return m.client.RemoveContainer(ctx, newName, false)
+	})
+
+	// Host certificates name the SSH host, which changes with the prefix
+	if err := m.setupSSHCertificates(ctx, newName, newName); err != nil {
+		m.logger.Warn("failed to setup SSH certificates",
+			logging.WithError(err),
+			logging.WithField("container", newName))
+	}
+
+	if containerInfo.Status == "running" {
+		if err := m.client.StartContainer(ctx, newName); err != nil {
+			return nil, fmt.Errorf("failed to start container: %w", err)
+		}
+	}
+
+	if err := m.client.RemoveContainer(ctx, oldName, true); err != nil {
+		m.logger.Warn("failed to remove the original container",
+			logging.WithError(err),
+			logging.WithField("container", oldName))
+	}
+	// The new container keeps the layers it needs
+	if err := m.client.RemoveImage(ctx, image); err != nil {
+		m.logger.Warn("failed to remove the migration image",
+			logging.WithError(err),
+			logging.WithField("image", image))
+	}
+
+	m.logger.Info("container migrated",
+		logging.WithField("container", newName))
+
+	container.SSHPort = containerInfo.SSHPort
+	container.WebPort = containerInfo.WebPort
+	return container, nil
+}
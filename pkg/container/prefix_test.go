@@ -0,0 +1,78 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_MigratePrefix(t *testing.T) {
+	info := &Container{
+		Name:    "dev-api",
+		Status:  "running",
+		SSHPort: 2201,
+		WebPort: 3001,
+		Labels:  map[string]string{LabelManaged: "true", LabelOwner: "alice"},
+	}
+	newManager := func(m *MockPodmanClient) *Manager {
+		return NewManager(m, Config{ContainerPrefix: "dev", ContainerUser: "dev"})
+	}
+
+	t.Run("recreates the container and volumes under the new prefix", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("GetContainerInfo", mock.Anything, "dev-api").Return(info, nil)
+		m.On("ContainerExists", mock.Anything, "work-api").Return(false, nil)
+		m.On("StopContainer", mock.Anything, "dev-api").Return(nil)
+		m.On("CommitContainer", mock.Anything, "dev-api", "localhost/work-api-migrated:latest").Return(nil)
+		m.On("CopyVolume", mock.Anything, "dev-api-home", "work-api-home").Return(nil)
+		m.On("CopyVolume", mock.Anything, "dev-api-workspace", "work-api-workspace").Return(nil)
+		m.On("CreateContainer", mock.Anything, mock.MatchedBy(func(config ContainerConfig) bool {
+			return config.Name == "work-api" &&
+				config.BaseImage == "localhost/work-api-migrated:latest" &&
+				config.SSHPort == 2201 && config.WebPort == 3001 &&
+				config.Labels[LabelOwner] == "alice"
+		})).Return(&Container{Name: "work-api"}, nil)
+		m.On("StartContainer", mock.Anything, "work-api").Return(nil)
+		m.On("RemoveContainer", mock.Anything, "dev-api", true).Return(nil)
+		m.On("RemoveImage", mock.Anything, "localhost/work-api-migrated:latest").Return(nil)
+
+		cont, err := newManager(m).MigratePrefix(context.Background(), "api", "dev", "work")
+		require.NoError(t, err)
+		assert.Equal(t, 2201, cont.SSHPort)
+		assert.Equal(t, 3001, cont.WebPort)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("restarts the original and drops copies when creation fails", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("GetContainerInfo", mock.Anything, "dev-api").Return(info, nil)
+		m.On("ContainerExists", mock.Anything, "work-api").Return(false, nil)
+		m.On("StopContainer", mock.Anything, "dev-api").Return(nil)
+		m.On("CommitContainer", mock.Anything, "dev-api", mock.Anything).Return(nil)
+		m.On("CopyVolume", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		m.On("CreateContainer", mock.Anything, mock.Anything).Return(nil, errors.New("port is already allocated"))
+		m.On("RemoveVolume", mock.Anything, "work-api-home").Return(nil)
+		m.On("RemoveVolume", mock.Anything, "work-api-workspace").Return(nil)
+		m.On("RemoveImage", mock.Anything, "localhost/work-api-migrated:latest").Return(nil)
+		m.On("StartContainer", mock.Anything, "dev-api").Return(nil)
+
+		_, err := newManager(m).MigratePrefix(context.Background(), "api", "dev", "work")
+		assert.Error(t, err)
+		m.AssertExpectations(t)
+		m.AssertNotCalled(t, "RemoveContainer", mock.Anything, "dev-api", mock.Anything)
+	})
+
+	t.Run("refuses to overwrite an existing container", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("GetContainerInfo", mock.Anything, "dev-api").Return(info, nil)
+		m.On("ContainerExists", mock.Anything, "work-api").Return(true, nil)
+
+		_, err := newManager(m).MigratePrefix(context.Background(), "api", "dev", "work")
+		assert.ErrorContains(t, err, "already exists")
+		m.AssertNotCalled(t, "StopContainer", mock.Anything, mock.Anything)
+	})
+}
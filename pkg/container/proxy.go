@@ -0,0 +1,95 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// proxyEnvFile is read by pam_env for every SSH session, which sshd starts
+// without the container's own environment
+const proxyEnvFile = "/etc/environment"
+
+// env returns the proxy variables in both cases, since curl only reads
+// http_proxy in lower case and other tools only the upper case names
+func (p Proxy) env() map[string]string {
+	if p.HTTP == "" && p.HTTPS == "" {
+		return nil
+	}
+	env := make(map[string]string)
+	for name, value := range map[string]string{"HTTP_PROXY": p.HTTP, "HTTPS_PROXY": p.HTTPS, "NO_PROXY": p.NoProxy} {
+		if value != "" {
+			env[name] = value
+			env[strings.ToLower(name)] = value
+		}
+	}
+	return env
+}
+
+// proxyScript returns the shell script that configures the proxy for SSH
+// sessions, dnf or apt, git and npm. It runs as root on every create and
+// rebuild, since all of it lives outside the persistent volumes.
+func proxyScript(p Proxy) string {
+	env := p.env()
+	if env == nil {
+		return ""
+	}
+	packageProxy := p.HTTPS
+	if p.HTTP != "" {
+		packageProxy = p.HTTP
+	}
+
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	fmt.Fprintf(&b, "sed -i '/^\\(HTTPS\\?\\|NO\\)_PROXY=/Id' %s 2>/dev/null || true\n", proxyEnvFile)
+	for _, name := range []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy", "NO_PROXY", "no_proxy"} {
+		if value, ok := env[name]; ok {
+			fmt.Fprintf(&b, "echo %s >> %s\n", ShellQuote(name+"="+value), proxyEnvFile)
+		}
+	}
+	fmt.Fprintf(&b, `if [ -f /etc/dnf/dnf.conf ]; then
+	sed -i '/^proxy=/d' /etc/dnf/dnf.conf
+	echo %s >> /etc/dnf/dnf.conf
+fi
+if [ -d /etc/apt/apt.conf.d ]; then
+	printf 'Acquire::http::Proxy "%%s";\nAcquire::https::Proxy "%%s";\n' %s %s > /etc/apt/apt.conf.d/95l8s-proxy
+fi
+if command -v git >/dev/null; then
+	git config --system http.proxy %s
+fi
+if command -v npm >/dev/null; then
+	npm config set --location=global proxy %s
+	npm config set --location=global https-proxy %s
+	npm config set --location=global noproxy %s
+fi
+`, ShellQuote("proxy="+packageProxy), ShellQuote(p.HTTP), ShellQuote(p.HTTPS), ShellQuote(packageProxy),
+		ShellQuote(p.HTTP), ShellQuote(p.HTTPS), ShellQuote(p.NoProxy))
+	return b.String()
+}
+
+// applyProxy sets the proxy variables in the container's environment, which
+// podman exec and processes started by the image inherit
+func (m *Manager) applyProxy(config *ContainerConfig) {
+	env := m.config.Proxy.env()
+	if env == nil {
+		return
+	}
+	if config.Env == nil {
+		config.Env = make(map[string]string)
+	}
+	for k, v := range env {
+		config.Env[k] = v
+	}
+}
+
+// configureProxy writes the proxy into the container's system configuration
+func (m *Manager) configureProxy(ctx context.Context, containerName string) error {
+	script := proxyScript(m.config.Proxy)
+	if script == "" {
+		return nil
+	}
+	if err := m.client.ExecContainer(ctx, containerName, []string{"sh", "-c", script}); err != nil {
+		return fmt.Errorf("failed to configure proxy: %w", err)
+	}
+	return nil
+}
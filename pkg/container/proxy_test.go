@@ -0,0 +1,28 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyEnv(t *testing.T) {
+	assert.Nil(t, Proxy{NoProxy: "localhost"}.env())
+	assert.Equal(t, map[string]string{
+		"HTTP_PROXY": "http://proxy:3128", "http_proxy": "http://proxy:3128",
+		"HTTPS_PROXY": "http://proxy:3128", "https_proxy": "http://proxy:3128",
+		"NO_PROXY": "localhost,.corp", "no_proxy": "localhost,.corp",
+	}, Proxy{HTTP: "http://proxy:3128", HTTPS: "http://proxy:3128", NoProxy: "localhost,.corp"}.env())
+}
+
+func TestProxyScript(t *testing.T) {
+	assert.Empty(t, proxyScript(Proxy{}))
+
+	script := proxyScript(Proxy{HTTP: "http://proxy:3128", HTTPS: "http://secure:3129", NoProxy: "localhost,.corp"})
+	assert.Contains(t, script, "echo HTTP_PROXY=http://proxy:3128 >> /etc/environment")
+	assert.Contains(t, script, "echo no_proxy=localhost,.corp >> /etc/environment")
+	assert.Contains(t, script, "echo proxy=http://proxy:3128 >> /etc/dnf/dnf.conf")
+	assert.Contains(t, script, "git config --system http.proxy http://proxy:3128")
+	assert.Contains(t, script, "npm config set --location=global https-proxy http://secure:3129")
+	assert.Contains(t, script, "npm config set --location=global noproxy localhost,.corp")
+}
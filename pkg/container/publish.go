@@ -0,0 +1,119 @@
+package container
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PortMapping publishes container ports on the Podman host beyond the SSH and
+// web ports l8s allocates, e.g. UDP ranges for mosh, WebRTC or game servers
+type PortMapping struct {
+	HostPort      int
+	ContainerPort int
+	Range         int    // Consecutive ports from HostPort and ContainerPort, at least 1
+	Protocol      string // tcp or udp
+}
+
+// HostPorts formats the host side, e.g. 60000-60010
+func (p PortMapping) HostPorts() string {
+	return formatPorts(p.HostPort, p.Range)
+}
+
+// ContainerPorts formats the container side, e.g. 60000-60010
+func (p PortMapping) ContainerPorts() string {
+	return formatPorts(p.ContainerPort, p.Range)
+}
+
+// String formats the mapping the way --publish takes it, e.g. 60000-60010:60000-60010/udp
+func (p PortMapping) String() string {
+	return p.HostPorts() + ":" + p.ContainerPorts() + "/" + p.Protocol
+}
+
+// Overlaps reports whether both mappings publish a host port for the same protocol
+func (p PortMapping) Overlaps(other PortMapping) bool {
+	return p.Protocol == other.Protocol &&
+		p.HostPort < other.HostPort+other.Range && other.HostPort < p.HostPort+p.Range
+}
+
+func formatPorts(first, n int) string {
+	if n <= 1 {
+		return strconv.Itoa(first)
+	}
+	return fmt.Sprintf("%d-%d", first, first+n-1)
+}
+
+// ParsePortMapping parses a --publish value of the form
+// [host[-end]:]container[-end][/tcp|/udp]. The host side defaults to the
+// container ports and the protocol to tcp; both sides must span as many ports.
+func ParsePortMapping(spec string) (PortMapping, error) {
+	ports, protocol, hasProtocol := strings.Cut(spec, "/")
+	if !hasProtocol {
+		protocol = "tcp"
+	}
+	protocol = strings.ToLower(protocol)
+	if protocol != "tcp" && protocol != "udp" {
+		return PortMapping{}, fmt.Errorf("invalid protocol '%s' in '%s': use tcp or udp", protocol, spec)
+	}
+
+	hostSpec, containerSpec, hasHost := strings.Cut(ports, ":")
+	if !hasHost {
+		containerSpec = hostSpec
+	}
+	containerPort, n, err := parsePorts(containerSpec)
+	if err != nil {
+		return PortMapping{}, fmt.Errorf("invalid port mapping '%s': %w", spec, err)
+	}
+	hostPort, hostN := containerPort, n
+	if hasHost {
+		if hostPort, hostN, err = parsePorts(hostSpec); err != nil {
+			return PortMapping{}, fmt.Errorf("invalid port mapping '%s': %w", spec, err)
+		}
+	}
+	if hostN != n {
+		return PortMapping{}, fmt.Errorf("invalid port mapping '%s': host and container ranges differ in size", spec)
+	}
+	return PortMapping{HostPort: hostPort, ContainerPort: containerPort, Range: n, Protocol: protocol}, nil
+}
+
+// parsePorts parses a port or first-last range, returning the first port and
+// how many ports it spans
+func parsePorts(s string) (int, int, error) {
+	firstStr, lastStr, isRange := strings.Cut(s, "-")
+	first, err := strconv.Atoi(firstStr)
+	if err != nil || first < 1 || first > 65535 {
+		return 0, 0, fmt.Errorf("'%s' is not a port between 1 and 65535", firstStr)
+	}
+	if !isRange {
+		return first, 1, nil
+	}
+	last, err := strconv.Atoi(lastStr)
+	if err != nil || last < 1 || last > 65535 {
+		return 0, 0, fmt.Errorf("'%s' is not a port between 1 and 65535", lastStr)
+	}
+	if last < first {
+		return 0, 0, fmt.Errorf("range %s ends before it starts", s)
+	}
+	return first, last - first + 1, nil
+}
+
+// FormatPortMappings encodes port mappings for LabelPorts as comma separated
+// host:container/protocol entries
+func FormatPortMappings(mappings []PortMapping) string {
+	entries := make([]string, len(mappings))
+	for i, p := range mappings {
+		entries[i] = p.String()
+	}
+	return strings.Join(entries, ",")
+}
+
+// ParsePortMappings decodes a LabelPorts value, skipping malformed entries
+func ParsePortMappings(value string) []PortMapping {
+	var mappings []PortMapping
+	for _, entry := range strings.Split(value, ",") {
+		if p, err := ParsePortMapping(entry); err == nil {
+			mappings = append(mappings, p)
+		}
+	}
+	return mappings
+}
@@ -0,0 +1,79 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePortMapping(t *testing.T) {
+	tests := []struct {
+		spec string
+		want PortMapping
+	}{
+		{"8080", PortMapping{HostPort: 8080, ContainerPort: 8080, Range: 1, Protocol: "tcp"}},
+		{"8443:443", PortMapping{HostPort: 8443, ContainerPort: 443, Range: 1, Protocol: "tcp"}},
+		{"60001/udp", PortMapping{HostPort: 60001, ContainerPort: 60001, Range: 1, Protocol: "udp"}},
+		{"60000-60010/UDP", PortMapping{HostPort: 60000, ContainerPort: 60000, Range: 11, Protocol: "udp"}},
+		{"50000-50009:40000-40009/udp", PortMapping{HostPort: 50000, ContainerPort: 40000, Range: 10, Protocol: "udp"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParsePortMapping(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, spec := range []string{"", "http", "0", "70000", "9000/sctp", "9010-9000", "9000-9010:80"} {
+		_, err := ParsePortMapping(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestFormatParsePortMappings(t *testing.T) {
+	mappings := []PortMapping{
+		{HostPort: 60000, ContainerPort: 60000, Range: 11, Protocol: "udp"},
+		{HostPort: 8443, ContainerPort: 443, Range: 1, Protocol: "tcp"},
+	}
+	value := FormatPortMappings(mappings)
+	assert.Equal(t, "60000-60010:60000-60010/udp,8443:443/tcp", value)
+	assert.Equal(t, mappings, ParsePortMappings(value))
+	assert.Nil(t, ParsePortMappings(""))
+
+	udp := PortMapping{HostPort: 60000, Range: 11, Protocol: "udp"}
+	assert.True(t, udp.Overlaps(PortMapping{HostPort: 60010, Range: 1, Protocol: "udp"}))
+	assert.False(t, udp.Overlaps(PortMapping{HostPort: 60010, Range: 1, Protocol: "tcp"}))
+	assert.False(t, udp.Overlaps(PortMapping{HostPort: 60011, Range: 5, Protocol: "udp"}))
+}
+
+func TestManager_ApplyRuntimeOptions_Ports(t *testing.T) {
+	m := NewManager(nil, Config{})
+	flags := &RuntimeOptions{Ports: []PortMapping{{HostPort: 60000, ContainerPort: 60000, Range: 11, Protocol: "udp"}}}
+
+	config := ContainerConfig{}
+	m.applyRuntimeOptions(&config, flags)
+	assert.Equal(t, "60000-60010:60000-60010/udp", config.Labels[LabelPorts])
+
+	// Rebuilds publish what the container was created with
+	recreated := ContainerConfig{Labels: map[string]string{LabelPorts: "8443:443/tcp"}}
+	m.applyRuntimeOptions(&recreated, flags)
+	assert.Equal(t, []PortMapping{{HostPort: 8443, ContainerPort: 443, Range: 1, Protocol: "tcp"}}, recreated.Runtime.Ports)
+}
+
+func TestManager_BlueGreenRebuildContainer_PublishedPorts(t *testing.T) {
+	mockClient := new(MockPodmanClient)
+	mockClient.On("GetContainerInfo", mock.Anything, "dev-myproject").Return(&Container{
+		Name:   "dev-myproject",
+		Status: "running",
+		Labels: map[string]string{LabelManaged: "true", LabelPorts: "60000-60010:60000-60010/udp"},
+	}, nil)
+
+	manager := NewManager(mockClient, Config{ContainerPrefix: "dev"})
+	err := manager.BlueGreenRebuildContainer(context.Background(), "myproject", "")
+	assert.ErrorContains(t, err, "rebuild without --blue-green")
+	mockClient.AssertNotCalled(t, "CreateContainer", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,68 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// prePushHookPath is where the push guard is installed in container repositories
+const prePushHookPath = ProjectDir + "/.git/hooks/pre-push"
+
+// PrePushHook returns the hook script refusing pushes to the guard's remotes
+// and protected branches. git runs it with the remote name and URL as
+// arguments and the refs being pushed on stdin.
+func PrePushHook(guard PushGuard) string {
+	var b strings.Builder
+	b.WriteString(`#!/bin/sh
+# Installed by l8s to keep tools in this container from pushing to production
+# branches or upstream. Run 'L8S_ALLOW_PUSH=1 git push ...' to push anyway.
+[ "$L8S_ALLOW_PUSH" = 1 ] && exit 0
+`)
+	if len(guard.BlockedRemotes) > 0 {
+		fmt.Fprintf(&b, `for blocked in %s; do
+	url=$(git remote get-url "$blocked" 2>/dev/null)
+	if [ "$1" = "$blocked" ] || { [ -n "$url" ] && [ "$2" = "$url" ]; }; then
+		echo "l8s: pushing to $blocked is blocked in this container (L8S_ALLOW_PUSH=1 overrides)" >&2
+		exit 1
+	fi
+done
+`, strings.Join(quoteAll(guard.BlockedRemotes), " "))
+	}
+	if len(guard.Protected) > 0 {
+		// Globs are validated branch patterns and must stay unquoted to match
+		fmt.Fprintf(&b, `while read -r local_ref local_sha remote_ref remote_sha; do
+	case "${remote_ref#refs/heads/}" in
+	%s)
+		echo "l8s: pushing to protected branch ${remote_ref#refs/heads/} is blocked (L8S_ALLOW_PUSH=1 overrides)" >&2
+		exit 1
+		;;
+	esac
+done
+`, strings.Join(guard.Protected, "|"))
+	}
+	b.WriteString("exit 0\n")
+	return b.String()
+}
+
+func quoteAll(words []string) []string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = ShellQuote(w)
+	}
+	return quoted
+}
+
+// installPushGuard writes the pre-push hook into a new container's repository
+func (m *Manager) installPushGuard(ctx context.Context, containerName string) error {
+	guard := m.config.PushGuard
+	if guard.Disabled || (len(guard.Protected) == 0 && len(guard.BlockedRemotes) == 0) {
+		return nil
+	}
+	installCmd := []string{"su", "-", m.config.ContainerUser, "-c",
+		fmt.Sprintf("mkdir -p %s/.git/hooks && cat > %s && chmod 755 %s", ProjectDir, prePushHookPath, prePushHookPath)}
+	if err := m.client.ExecContainerWithInput(ctx, containerName, installCmd, PrePushHook(guard)); err != nil {
+		return fmt.Errorf("failed to install pre-push hook: %w", err)
+	}
+	return nil
+}
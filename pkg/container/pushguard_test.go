@@ -0,0 +1,44 @@
+package container
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrePushHook(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "-q", dir).Run())
+	require.NoError(t, exec.Command("git", "-C", dir, "remote", "add", "origin", "git@github.com:acme/api.git").Run())
+	hook := filepath.Join(dir, "pre-push")
+	require.NoError(t, os.WriteFile(hook, []byte(PrePushHook(PushGuard{
+		Protected:      []string{"main", "release/*"},
+		BlockedRemotes: []string{"origin"},
+	})), 0755))
+
+	push := func(remote, url, branch string, env ...string) error {
+		cmd := exec.Command("sh", hook, remote, url)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), env...)
+		cmd.Stdin = strings.NewReader("refs/heads/x 1111 refs/heads/" + branch + " 0000\n")
+		return cmd.Run()
+	}
+
+	assert.NoError(t, push("fork", "git@github.com:me/api.git", "feature"))
+	assert.Error(t, push("fork", "git@github.com:me/api.git", "main"))
+	assert.Error(t, push("fork", "git@github.com:me/api.git", "release/1.2"))
+	assert.Error(t, push("origin", "git@github.com:acme/api.git", "feature"))
+	assert.Error(t, push("git@github.com:acme/api.git", "git@github.com:acme/api.git", "feature"))
+	assert.NoError(t, push("origin", "git@github.com:acme/api.git", "main", "L8S_ALLOW_PUSH=1"))
+}
+
+func TestPrePushHookProtectedOnly(t *testing.T) {
+	script := PrePushHook(PushGuard{Protected: []string{"main"}})
+	assert.NotContains(t, script, "for blocked in")
+	assert.Contains(t, script, "\tmain)\n")
+}
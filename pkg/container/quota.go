@@ -0,0 +1,100 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// QuotaError reports that creating a container would exceed a connection quota
+type QuotaError struct {
+	Resource string // containers, memory, cpus or ports
+	Used     string
+	Limit    string
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("%s quota exceeded: %s in use, limit is %s", e.Resource, e.Used, e.Limit)
+}
+
+// applyResourceLimits sets the configured per-container limits and records them
+// in labels so quota usage can be computed from the container list
+func (m *Manager) applyResourceLimits(config *ContainerConfig) {
+	config.Memory = m.config.Memory
+	config.CPUs = m.config.CPUs
+	if config.Labels == nil {
+		config.Labels = make(map[string]string)
+	}
+	delete(config.Labels, LabelMemory)
+	delete(config.Labels, LabelCPUs)
+	if m.config.Memory > 0 {
+		config.Labels[LabelMemory] = strconv.FormatInt(m.config.Memory, 10)
+	}
+	if m.config.CPUs > 0 {
+		config.Labels[LabelCPUs] = strconv.FormatFloat(m.config.CPUs, 'f', -1, 64)
+	}
+}
+
+// checkQuota verifies that one more container fits within the connection quota
+func (m *Manager) checkQuota(ctx context.Context) error {
+	q := m.config.Quota
+	if q.MaxContainers == 0 && q.MaxMemory == 0 && q.MaxCPUs == 0 {
+		return nil
+	}
+
+	containers, err := m.client.ListContainers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check quota: %w", err)
+	}
+
+	if q.MaxContainers > 0 && len(containers) >= q.MaxContainers {
+		return &QuotaError{
+			Resource: "container",
+			Used:     fmt.Sprintf("%d containers", len(containers)),
+			Limit:    fmt.Sprintf("%d", q.MaxContainers),
+		}
+	}
+
+	// Containers created without limits do not count towards memory or CPU usage
+	var memory int64
+	var cpus float64
+	for _, c := range containers {
+		if v, err := strconv.ParseInt(c.Labels[LabelMemory], 10, 64); err == nil {
+			memory += v
+		}
+		if v, err := strconv.ParseFloat(c.Labels[LabelCPUs], 64); err == nil {
+			cpus += v
+		}
+	}
+
+	if q.MaxMemory > 0 && memory+m.config.Memory > q.MaxMemory {
+		return &QuotaError{
+			Resource: "memory",
+			Used:     fmt.Sprintf("%d MiB", memory>>20),
+			Limit:    fmt.Sprintf("%d MiB (new container needs %d MiB)", q.MaxMemory>>20, m.config.Memory>>20),
+		}
+	}
+	if q.MaxCPUs > 0 && cpus+m.config.CPUs > q.MaxCPUs {
+		return &QuotaError{
+			Resource: "CPU",
+			Used:     fmt.Sprintf("%g CPUs", cpus),
+			Limit:    fmt.Sprintf("%g (new container needs %g)", q.MaxCPUs, m.config.CPUs),
+		}
+	}
+
+	return nil
+}
+
+// checkPortBudget verifies an allocated SSH port lies within the port budget
+func (m *Manager) checkPortBudget(sshPort int) error {
+	budget := m.config.Quota.PortBudget
+	offset := m.portOffset(sshPort)
+	if budget == 0 || offset < budget {
+		return nil
+	}
+	return &QuotaError{
+		Resource: "port",
+		Used:     fmt.Sprintf("%d ports", offset),
+		Limit:    fmt.Sprintf("%d ports", budget),
+	}
+}
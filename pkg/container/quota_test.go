@@ -0,0 +1,75 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_CheckQuota(t *testing.T) {
+	existing := []*Container{
+		{Name: "dev-a", Labels: map[string]string{LabelMemory: "4294967296", LabelCPUs: "2"}},
+		{Name: "dev-b", Labels: map[string]string{LabelMemory: "4294967296", LabelCPUs: "2"}},
+	}
+
+	tests := []struct {
+		name     string
+		quota    Quota
+		resource string // empty when the container fits
+	}{
+		{name: "no quota", quota: Quota{}},
+		{name: "room for one more", quota: Quota{MaxContainers: 3, MaxMemory: 12 << 30, MaxCPUs: 6}},
+		{name: "container limit reached", quota: Quota{MaxContainers: 2}, resource: "container"},
+		{name: "memory limit reached", quota: Quota{MaxMemory: 10 << 30}, resource: "memory"},
+		{name: "cpu limit reached", quota: Quota{MaxCPUs: 5}, resource: "CPU"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockPodmanClient)
+			m.On("ListContainers", mock.Anything).Return(existing, nil).Maybe()
+
+			manager := NewManager(m, Config{
+				ContainerPrefix: "dev",
+				Memory:          4 << 30,
+				CPUs:            2,
+				Quota:           tt.quota,
+			})
+
+			err := manager.checkQuota(context.Background())
+			if tt.resource == "" {
+				require.NoError(t, err)
+				return
+			}
+			var quotaErr *QuotaError
+			require.True(t, errors.As(err, &quotaErr))
+			assert.Equal(t, tt.resource, quotaErr.Resource)
+		})
+	}
+}
+
+func TestManager_CheckPortBudget(t *testing.T) {
+	manager := NewManager(new(MockPodmanClient), Config{
+		SSHPortStart: 2200,
+		Quota:        Quota{PortBudget: 10},
+	})
+
+	assert.NoError(t, manager.checkPortBudget(2209))
+	assert.Error(t, manager.checkPortBudget(2210))
+}
+
+func TestManager_ApplyResourceLimits(t *testing.T) {
+	manager := NewManager(new(MockPodmanClient), Config{Memory: 2 << 30})
+
+	config := ContainerConfig{Labels: map[string]string{LabelCPUs: "4"}}
+	manager.applyResourceLimits(&config)
+
+	assert.Equal(t, int64(2<<30), config.Memory)
+	assert.Equal(t, "2147483648", config.Labels[LabelMemory])
+	_, hasCPUs := config.Labels[LabelCPUs]
+	assert.False(t, hasCPUs, "stale CPU label should be dropped")
+}
@@ -0,0 +1,190 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxUnpushedScan bounds how far back each branch is walked looking for a saved commit
+const maxUnpushedScan = 100
+
+// Inspector is what the removal guards need from a container manager. Names
+// are short, without the prefix.
+type Inspector interface {
+	GetContainerInfo(ctx context.Context, name string) (*Container, error)
+	ExecContainerOutput(ctx context.Context, name string, cmd []string) (string, error)
+}
+
+// Guard names a check made before a container is removed
+type Guard string
+
+const (
+	GuardProtected Guard = "protected" // The container is protected
+	GuardOwner     Guard = "owner"     // Someone else created the container
+	GuardUnsaved   Guard = "unsaved"   // Work exists only inside the container
+	GuardUnchecked Guard = "unchecked" // The container could not be checked for unsaved work
+)
+
+// RemoveRefusedError is returned when a guard refuses to remove a container
+type RemoveRefusedError struct {
+	Guard  Guard
+	Reason string // Names the container
+}
+
+func (e *RemoveRefusedError) Error() string {
+	return e.Reason
+}
+
+// RemoveGuard describes who is removing a container and which checks they
+// override. l8s remove and the HTTP API both apply it, so neither destroys a
+// protected container or unsaved work that the other would refuse to.
+type RemoveGuard struct {
+	ContainerUser  string // Runs git in the container's project
+	Force          bool   // Remove someone else's container or one holding unsaved work
+	ForceProtected bool   // Remove protected containers
+	KeepVolumes    bool   // Volumes are kept or trashed, so no work can be lost
+	SkipUnsaved    bool   // The remover accepts losing work that exists only in the container
+
+	// IsProtected reports whether the container is protected; nil checks
+	// the protected label
+	IsProtected func(c *Container) bool
+	// IsMine reports whether the remover created the container; nil matches
+	// the owner label against CurrentUser
+	IsMine func(c *Container) bool
+	// Saved reports whether a commit found in the container also exists
+	// outside it; nil counts every commit as unsaved
+	Saved func(c *Container, sha string) bool
+}
+
+// CheckRemove applies the removal guards to container name. It returns an
+// error, a *RemoveRefusedError when a guard refuses, or warnings describing
+// the guards that were overridden. Missing containers pass; the removal
+// itself reports them.
+func CheckRemove(ctx context.Context, mgr Inspector, name string, guard RemoveGuard) ([]string, error) {
+	c, err := mgr.GetContainerInfo(ctx, name)
+	if err != nil {
+		return nil, nil
+	}
+	var warnings []string
+
+	isProtected := guard.IsProtected
+	if isProtected == nil {
+		isProtected = func(c *Container) bool { return c.Labels[LabelProtected] == "true" }
+	}
+	if isProtected(c) {
+		if !guard.ForceProtected {
+			return nil, &RemoveRefusedError{Guard: GuardProtected, Reason: fmt.Sprintf("container %s is protected", c.Name)}
+		}
+		warnings = append(warnings, fmt.Sprintf("%s is protected; removing it anyway", c.Name))
+	}
+
+	// Containers created before ownership was recorded are treated as unowned
+	isMine := guard.IsMine
+	if isMine == nil {
+		isMine = func(c *Container) bool { return c.Labels[LabelOwner] == CurrentUser() }
+	}
+	if owner := c.Labels[LabelOwner]; owner != "" && !isMine(c) {
+		if !guard.Force {
+			return nil, &RemoveRefusedError{Guard: GuardOwner, Reason: fmt.Sprintf("container %s was created by %s", c.Name, owner)}
+		}
+		warnings = append(warnings, fmt.Sprintf("%s was created by %s", c.Name, owner))
+	}
+
+	if guard.KeepVolumes || guard.SkipUnsaved {
+		return warnings, nil
+	}
+
+	// A container that cannot be checked, e.g. because it is stopped, may
+	// hold unsaved work too
+	work, err := FindUnsavedWork(ctx, mgr, name, c, guard)
+	switch {
+	case err != nil && !guard.Force:
+		return nil, &RemoveRefusedError{Guard: GuardUnchecked, Reason: fmt.Sprintf("could not check container %s for unsaved work: %v", c.Name, err)}
+	case err != nil:
+		warnings = append(warnings, fmt.Sprintf("Could not check container for unsaved work: %v", err))
+	case !work.Empty() && !guard.Force:
+		return nil, &RemoveRefusedError{Guard: GuardUnsaved, Reason: fmt.Sprintf("container %s has work that would be lost:\n%s", c.Name, work.Summary())}
+	case !work.Empty():
+		warnings = append(warnings, fmt.Sprintf("Removing despite unsaved work:\n%s", work.Summary()))
+	}
+	return warnings, nil
+}
+
+// UnsavedWork describes container repository state that removal would destroy
+type UnsavedWork struct {
+	Uncommitted []string            // git status --porcelain lines
+	Unpushed    map[string][]string // branch -> "shortsha subject" for unsaved commits
+}
+
+// Empty reports whether nothing would be lost
+func (u *UnsavedWork) Empty() bool {
+	return len(u.Uncommitted) == 0 && len(u.Unpushed) == 0
+}
+
+// Summary renders a human readable description of what would be lost
+func (u *UnsavedWork) Summary() string {
+	var b strings.Builder
+	if len(u.Uncommitted) > 0 {
+		fmt.Fprintf(&b, "  Uncommitted changes (%d files):\n", len(u.Uncommitted))
+		for _, line := range u.Uncommitted {
+			fmt.Fprintf(&b, "    %s\n", line)
+		}
+	}
+	for branch, commits := range u.Unpushed {
+		fmt.Fprintf(&b, "  Commits on '%s' not found locally (%d):\n", branch, len(commits))
+		for _, commit := range commits {
+			fmt.Fprintf(&b, "    %s\n", commit)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// FindUnsavedWork checks the repository in container name, described by c,
+// for uncommitted changes and for commits guard.Saved does not know
+func FindUnsavedWork(ctx context.Context, mgr Inspector, name string, c *Container, guard RemoveGuard) (*UnsavedWork, error) {
+	work := &UnsavedWork{Unpushed: make(map[string][]string)}
+	saved := func(sha string) bool {
+		return guard.Saved != nil && guard.Saved(c, sha)
+	}
+	git := func(args string) (string, error) {
+		return mgr.ExecContainerOutput(ctx, name, []string{"su", "-", guard.ContainerUser, "-c",
+			"cd /workspace/project && git " + args})
+	}
+
+	status, err := git("status --porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check git status in container: %w", err)
+	}
+	for _, line := range strings.Split(strings.TrimRight(status, "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			work.Uncommitted = append(work.Uncommitted, line)
+		}
+	}
+
+	refs, err := git("for-each-ref --format='%(refname:short) %(objectname)' refs/heads")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches in container: %w", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(refs), "\n") {
+		branch, sha, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok || saved(sha) {
+			continue
+		}
+
+		// Walk back until we reach a commit that is saved
+		log, err := git(fmt.Sprintf("log --format='%%H %%h %%s' -n %d %s", maxUnpushedScan, sha))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history of '%s' in container: %w", branch, err)
+		}
+		for _, entry := range strings.Split(strings.TrimSpace(log), "\n") {
+			full, rest, ok := strings.Cut(entry, " ")
+			if !ok || saved(full) {
+				break
+			}
+			work.Unpushed[branch] = append(work.Unpushed[branch], rest)
+		}
+	}
+
+	return work, nil
+}
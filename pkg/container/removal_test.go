@@ -0,0 +1,113 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInspector answers git commands run in a container from a table keyed
+// by the git arguments
+type fakeInspector struct {
+	info    *Container
+	infoErr error
+	git     map[string]string
+	gitErr  error
+}
+
+func (f *fakeInspector) GetContainerInfo(ctx context.Context, name string) (*Container, error) {
+	return f.info, f.infoErr
+}
+
+func (f *fakeInspector) ExecContainerOutput(ctx context.Context, name string, cmd []string) (string, error) {
+	if f.gitErr != nil {
+		return "", f.gitErr
+	}
+	args := strings.TrimPrefix(cmd[len(cmd)-1], "cd /workspace/project && git ")
+	return f.git[args], nil
+}
+
+func TestFindUnsavedWork(t *testing.T) {
+	refs := "for-each-ref --format='%(refname:short) %(objectname)' refs/heads"
+	saved := map[string]bool{"aaa111": true, "bbb222": true}
+	guard := RemoveGuard{
+		ContainerUser: "dev",
+		Saved:         func(_ *Container, sha string) bool { return saved[sha] },
+	}
+
+	t.Run("clean container", func(t *testing.T) {
+		mgr := &fakeInspector{git: map[string]string{refs: "main aaa111\n"}}
+		work, err := FindUnsavedWork(context.Background(), mgr, "myproject", &Container{}, guard)
+		require.NoError(t, err)
+		assert.True(t, work.Empty())
+	})
+
+	t.Run("uncommitted changes and unsaved commits", func(t *testing.T) {
+		mgr := &fakeInspector{git: map[string]string{
+			"status --porcelain":                    " M main.go\n?? notes.txt\n",
+			refs:                                    "main ccc333\nfeature bbb222\n",
+			"log --format='%H %h %s' -n 100 ccc333": "ccc333 ccc Second\nddd444 ddd First\naaa111 aaa Base\n",
+		}}
+		work, err := FindUnsavedWork(context.Background(), mgr, "myproject", &Container{}, guard)
+		require.NoError(t, err)
+		assert.Len(t, work.Uncommitted, 2)
+		assert.Equal(t, map[string][]string{"main": {"ccc Second", "ddd First"}}, work.Unpushed)
+		assert.False(t, work.Empty())
+	})
+
+	t.Run("container not reachable", func(t *testing.T) {
+		mgr := &fakeInspector{gitErr: errors.New("container is not running")}
+		_, err := FindUnsavedWork(context.Background(), mgr, "myproject", &Container{}, guard)
+		assert.Error(t, err)
+	})
+}
+
+func TestCheckRemove(t *testing.T) {
+	protected := &Container{Name: "dev-pet", Labels: map[string]string{LabelProtected: "true"}}
+	theirs := &Container{Name: "dev-shared", Labels: map[string]string{LabelOwner: "someone-else"}}
+	plain := &Container{Name: "dev-cattle", Labels: map[string]string{LabelOwner: CurrentUser()}}
+	dirty := map[string]string{"status --porcelain": " M main.go\n"}
+
+	tests := []struct {
+		name      string
+		mgr       *fakeInspector
+		guard     RemoveGuard
+		wantGuard Guard
+		wantWarn  int
+	}{
+		{name: "missing container", mgr: &fakeInspector{infoErr: errors.New("not found")}},
+		{name: "clean container", mgr: &fakeInspector{info: plain}},
+		{name: "protected", mgr: &fakeInspector{info: protected}, wantGuard: GuardProtected},
+		{name: "protected, forced", mgr: &fakeInspector{info: protected}, guard: RemoveGuard{ForceProtected: true}, wantWarn: 1},
+		{name: "protected, --force alone", mgr: &fakeInspector{info: protected}, guard: RemoveGuard{Force: true}, wantGuard: GuardProtected},
+		{name: "someone else's", mgr: &fakeInspector{info: theirs}, wantGuard: GuardOwner},
+		{name: "someone else's, forced", mgr: &fakeInspector{info: theirs}, guard: RemoveGuard{Force: true}, wantWarn: 1},
+		{name: "someone else's, claimed by IsMine", mgr: &fakeInspector{info: theirs},
+			guard: RemoveGuard{IsMine: func(*Container) bool { return true }}},
+		{name: "unsaved work", mgr: &fakeInspector{info: plain, git: dirty}, wantGuard: GuardUnsaved},
+		{name: "unsaved work, forced", mgr: &fakeInspector{info: plain, git: dirty}, guard: RemoveGuard{Force: true}, wantWarn: 1},
+		{name: "unsaved work, volumes kept", mgr: &fakeInspector{info: plain, git: dirty}, guard: RemoveGuard{KeepVolumes: true}},
+		{name: "unreachable", mgr: &fakeInspector{info: plain, gitErr: errors.New("container is not running")}, wantGuard: GuardUnchecked},
+		{name: "unreachable, forced", mgr: &fakeInspector{info: plain, gitErr: errors.New("container is not running")},
+			guard: RemoveGuard{Force: true}, wantWarn: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := CheckRemove(context.Background(), tt.mgr, "x", tt.guard)
+			if tt.wantGuard != "" {
+				var refused *RemoveRefusedError
+				require.ErrorAs(t, err, &refused)
+				assert.Equal(t, tt.wantGuard, refused.Guard)
+				assert.Contains(t, refused.Error(), "container "+tt.mgr.info.Name)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, warnings, tt.wantWarn)
+		})
+	}
+}
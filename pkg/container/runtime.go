@@ -0,0 +1,227 @@
+package container
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RuntimeOptions tune how podman runs a container beyond l8s' defaults
+type RuntimeOptions struct {
+	ShmSize int64             // /dev/shm size in bytes (0 keeps podman's 64MB default)
+	Tmpfs   []Tmpfs           // Extra tmpfs mounts
+	Ulimits []Ulimit          // Resource limits for processes in the container
+	Sysctls map[string]string // Namespaced kernel parameters
+	Ports   []PortMapping     // Published ports beyond SSH and web
+
+	// Opt-ins for debuggers and profilers
+	CapAdd            []string // Capabilities without the CAP_ prefix, e.g. SYS_PTRACE
+	SeccompUnconfined bool
+	SELinuxOpts       []string // e.g. disable or type:spc_t
+
+	// Nested lets podman and buildah run inside the container
+	Nested bool
+
+	// Systemd runs systemd as PID 1, with sshd as one of its units
+	Systemd bool
+
+	// Fail2ban bans addresses with repeated sshd login failures
+	Fail2ban bool
+}
+
+// Tmpfs is an in-memory filesystem mounted in a container
+type Tmpfs struct {
+	Path string
+	Size int64 // Bytes (0 lets the kernel pick half of RAM)
+}
+
+// Ulimit is a resource limit such as nofile or nproc
+type Ulimit struct {
+	Name string // As in 'podman --ulimit', e.g. nofile
+	Soft uint64
+	Hard uint64
+}
+
+// FormatUlimits encodes ulimits for LabelUlimits as comma separated name=soft:hard entries
+func FormatUlimits(ulimits []Ulimit) string {
+	entries := make([]string, len(ulimits))
+	for i, u := range ulimits {
+		entries[i] = fmt.Sprintf("%s=%d:%d", u.Name, u.Soft, u.Hard)
+	}
+	return strings.Join(entries, ",")
+}
+
+// ParseUlimits decodes a LabelUlimits value, skipping malformed entries
+func ParseUlimits(value string) []Ulimit {
+	var ulimits []Ulimit
+	for _, entry := range strings.Split(value, ",") {
+		name, limits, ok := strings.Cut(entry, "=")
+		softStr, hardStr, hasHard := strings.Cut(limits, ":")
+		if !ok || name == "" || !hasHard {
+			continue
+		}
+		soft, softErr := strconv.ParseUint(softStr, 10, 64)
+		hard, hardErr := strconv.ParseUint(hardStr, 10, 64)
+		if softErr != nil || hardErr != nil {
+			continue
+		}
+		ulimits = append(ulimits, Ulimit{Name: name, Soft: soft, Hard: hard})
+	}
+	return ulimits
+}
+
+// FormatSysctls encodes sysctls for LabelSysctls as comma separated key=value
+// entries in key order
+func FormatSysctls(sysctls map[string]string) string {
+	keys := make([]string, 0, len(sysctls))
+	for k := range sysctls {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	entries := make([]string, len(keys))
+	for i, k := range keys {
+		entries[i] = k + "=" + sysctls[k]
+	}
+	return strings.Join(entries, ",")
+}
+
+// ParseSysctls decodes a LabelSysctls value, skipping malformed entries
+func ParseSysctls(value string) map[string]string {
+	sysctls := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		if k, v, ok := strings.Cut(entry, "="); ok && k != "" {
+			sysctls[k] = v
+		}
+	}
+	return sysctls
+}
+
+// FormatTmpfs encodes tmpfs mounts for LabelTmpfs as comma separated path[:size] entries
+func FormatTmpfs(mounts []Tmpfs) string {
+	entries := make([]string, len(mounts))
+	for i, t := range mounts {
+		entries[i] = t.Path
+		if t.Size > 0 {
+			entries[i] += ":" + strconv.FormatInt(t.Size, 10)
+		}
+	}
+	return strings.Join(entries, ",")
+}
+
+// ParseTmpfs decodes a LabelTmpfs value, skipping malformed entries
+func ParseTmpfs(value string) []Tmpfs {
+	var mounts []Tmpfs
+	for _, entry := range strings.Split(value, ",") {
+		path, sizeStr, hasSize := strings.Cut(entry, ":")
+		if !strings.HasPrefix(path, "/") {
+			continue
+		}
+		t := Tmpfs{Path: path}
+		if hasSize {
+			size, err := strconv.ParseInt(sizeStr, 10, 64)
+			if err != nil {
+				continue
+			}
+			t.Size = size
+		}
+		mounts = append(mounts, t)
+	}
+	return mounts
+}
+
+// applyRuntimeOptions sets runtime options for a container. Options are
+// recorded in labels at creation so rebuilds keep what the container was
+// created with; options without a label fall back to override, e.g. values
+// given as 'l8s create' flags, or else the configured defaults.
+func (m *Manager) applyRuntimeOptions(config *ContainerConfig, override *RuntimeOptions) {
+	opts := m.config.Runtime
+	if override != nil {
+		opts = *override
+	}
+	if config.Labels == nil {
+		config.Labels = make(map[string]string)
+	}
+
+	if value, ok := config.Labels[LabelShmSize]; ok {
+		if size, err := strconv.ParseInt(value, 10, 64); err == nil {
+			opts.ShmSize = size
+		}
+	} else if opts.ShmSize > 0 {
+		config.Labels[LabelShmSize] = strconv.FormatInt(opts.ShmSize, 10)
+	}
+
+	if value, ok := config.Labels[LabelTmpfs]; ok {
+		opts.Tmpfs = ParseTmpfs(value)
+	} else if len(opts.Tmpfs) > 0 {
+		config.Labels[LabelTmpfs] = FormatTmpfs(opts.Tmpfs)
+	}
+
+	if value, ok := config.Labels[LabelUlimits]; ok {
+		opts.Ulimits = ParseUlimits(value)
+	} else if len(opts.Ulimits) > 0 {
+		config.Labels[LabelUlimits] = FormatUlimits(opts.Ulimits)
+	}
+
+	if value, ok := config.Labels[LabelSysctls]; ok {
+		opts.Sysctls = ParseSysctls(value)
+	} else if len(opts.Sysctls) > 0 {
+		config.Labels[LabelSysctls] = FormatSysctls(opts.Sysctls)
+	}
+
+	if value, ok := config.Labels[LabelPorts]; ok {
+		opts.Ports = ParsePortMappings(value)
+	} else if len(opts.Ports) > 0 {
+		config.Labels[LabelPorts] = FormatPortMappings(opts.Ports)
+	}
+
+	if value, ok := config.Labels[LabelCapAdd]; ok {
+		opts.CapAdd = splitNonEmpty(value, ",")
+	} else if len(opts.CapAdd) > 0 {
+		config.Labels[LabelCapAdd] = strings.Join(opts.CapAdd, ",")
+	}
+
+	if value, ok := config.Labels[LabelSeccomp]; ok {
+		opts.SeccompUnconfined = value == "unconfined"
+	} else if opts.SeccompUnconfined {
+		config.Labels[LabelSeccomp] = "unconfined"
+	}
+
+	// SELinux levels such as s0:c1,c2 contain commas, so options are ';' separated
+	if value, ok := config.Labels[LabelSELinux]; ok {
+		opts.SELinuxOpts = splitNonEmpty(value, ";")
+	} else if len(opts.SELinuxOpts) > 0 {
+		config.Labels[LabelSELinux] = strings.Join(opts.SELinuxOpts, ";")
+	}
+
+	if value, ok := config.Labels[LabelNested]; ok {
+		opts.Nested = value == "true"
+	} else if opts.Nested {
+		config.Labels[LabelNested] = "true"
+	}
+
+	if value, ok := config.Labels[LabelSystemd]; ok {
+		opts.Systemd = value == "true"
+	} else if opts.Systemd {
+		config.Labels[LabelSystemd] = "true"
+	}
+
+	if value, ok := config.Labels[LabelFail2ban]; ok {
+		opts.Fail2ban = value == "true"
+	} else if opts.Fail2ban {
+		config.Labels[LabelFail2ban] = "true"
+	}
+
+	config.Runtime = opts
+}
+
+// splitNonEmpty splits a label value, dropping empty entries
+func splitNonEmpty(value, sep string) []string {
+	var parts []string
+	for _, part := range strings.Split(value, sep) {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,67 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatParseTmpfs(t *testing.T) {
+	mounts := []Tmpfs{{Path: "/tmp", Size: 4 << 30}, {Path: "/cache"}}
+	value := FormatTmpfs(mounts)
+	assert.Equal(t, "/tmp:4294967296,/cache", value)
+	assert.Equal(t, mounts, ParseTmpfs(value))
+	assert.Nil(t, ParseTmpfs(""))
+	assert.Equal(t, []Tmpfs{{Path: "/ok"}}, ParseTmpfs("relative,/bad:x,/ok"))
+}
+
+func TestFormatParseUlimitsSysctls(t *testing.T) {
+	ulimits := []Ulimit{{Name: "nofile", Soft: 1048576, Hard: 1048576}, {Name: "nproc", Soft: 4096, Hard: 8192}}
+	value := FormatUlimits(ulimits)
+	assert.Equal(t, "nofile=1048576:1048576,nproc=4096:8192", value)
+	assert.Equal(t, ulimits, ParseUlimits(value))
+	assert.Nil(t, ParseUlimits("nofile=10,core=x:1"))
+
+	sysctls := map[string]string{"net.core.somaxconn": "4096", "kernel.shmmax": "68719476736"}
+	value = FormatSysctls(sysctls)
+	assert.Equal(t, "kernel.shmmax=68719476736,net.core.somaxconn=4096", value)
+	assert.Equal(t, sysctls, ParseSysctls(value))
+}
+
+func TestManager_ApplyRuntimeOptions(t *testing.T) {
+	m := NewManager(nil, Config{Runtime: RuntimeOptions{ShmSize: 1 << 30}})
+
+	// New containers get the configured defaults, recorded in labels
+	config := ContainerConfig{}
+	m.applyRuntimeOptions(&config, nil)
+	assert.Equal(t, int64(1<<30), config.Runtime.ShmSize)
+	assert.Equal(t, "1073741824", config.Labels[LabelShmSize])
+	assert.NotContains(t, config.Labels, LabelTmpfs)
+
+	// Create flags replace the defaults
+	flags := &RuntimeOptions{ShmSize: 2 << 30, Tmpfs: []Tmpfs{{Path: "/tmp"}}}
+	config = ContainerConfig{}
+	m.applyRuntimeOptions(&config, flags)
+	assert.Equal(t, "2147483648", config.Labels[LabelShmSize])
+	assert.Equal(t, "/tmp", config.Labels[LabelTmpfs])
+
+	// Recreated containers keep what they were created with
+	recreated := ContainerConfig{Labels: map[string]string{LabelShmSize: "536870912", LabelTmpfs: "/scratch:1024"}}
+	m.applyRuntimeOptions(&recreated, flags)
+	assert.Equal(t, RuntimeOptions{ShmSize: 512 << 20, Tmpfs: []Tmpfs{{Path: "/scratch", Size: 1024}}}, recreated.Runtime)
+
+	// Debug opt-ins round-trip through labels; SELinux levels may contain commas
+	flags = &RuntimeOptions{CapAdd: []string{"SYS_PTRACE", "PERFMON"}, SeccompUnconfined: true, SELinuxOpts: []string{"level:s0:c1,c2", "type:spc_t"}, Nested: true, Systemd: true, Fail2ban: true}
+	config = ContainerConfig{}
+	m.applyRuntimeOptions(&config, flags)
+	assert.Equal(t, "SYS_PTRACE,PERFMON", config.Labels[LabelCapAdd])
+	assert.Equal(t, "unconfined", config.Labels[LabelSeccomp])
+	assert.Equal(t, "level:s0:c1,c2;type:spc_t", config.Labels[LabelSELinux])
+	assert.Equal(t, "true", config.Labels[LabelNested])
+	assert.Equal(t, "true", config.Labels[LabelSystemd])
+	assert.Equal(t, "true", config.Labels[LabelFail2ban])
+
+	recreated = ContainerConfig{Labels: config.Labels}
+	NewManager(nil, Config{}).applyRuntimeOptions(&recreated, nil)
+	assert.Equal(t, config.Runtime, recreated.Runtime)
+}
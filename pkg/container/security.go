@@ -0,0 +1,121 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"l8s/pkg/logging"
+)
+
+// SecurityCheck is one result of a container security audit
+type SecurityCheck struct {
+	Name   string
+	OK     bool
+	Detail string // What was found, and the fix when the check failed
+}
+
+// initProcesses are PID 1 commands that reap zombies and forward signals,
+// so sshd and user workloads run as ordinary children
+var initProcesses = map[string]bool{
+	"catatonit":   true, // podman --init
+	"tini":        true,
+	"docker-init": true,
+	"s6-svscan":   true,
+	"systemd":     true,
+	"init":        true,
+}
+
+// AuditSecurity checks a container's init process and effective sshd configuration
+func (m *Manager) AuditSecurity(ctx context.Context, name string) ([]SecurityCheck, error) {
+	return m.auditSecurity(ctx, m.config.ContainerPrefix+"-"+name)
+}
+
+func (m *Manager) auditSecurity(ctx context.Context, containerName string) ([]SecurityCheck, error) {
+	pid1, err := m.client.ExecContainerOutput(ctx, containerName, []string{"cat", "/proc/1/comm"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PID 1: %w", err)
+	}
+	// sshd -T prints the effective configuration without starting a daemon
+	effective, err := m.client.ExecContainerOutput(ctx, containerName, []string{"/usr/sbin/sshd", "-T"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sshd configuration: %w", err)
+	}
+	return evaluateSecurity(strings.TrimSpace(pid1), parseSSHDConfig(effective), m.config.ContainerUser), nil
+}
+
+// verifyHardening audits a new container and logs any failed check
+func (m *Manager) verifyHardening(ctx context.Context, containerName string) {
+	if m.config.Simulated {
+		return // Nothing to audit
+	}
+	checks, err := m.auditSecurity(ctx, containerName)
+	if err != nil {
+		m.logger.Warn("failed to audit container security",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+		return
+	}
+	for _, check := range checks {
+		if !check.OK {
+			m.logger.Warn("security check failed; see 'l8s security audit'",
+				logging.WithField("container", containerName),
+				logging.WithField("check", check.Name),
+				logging.WithField("detail", check.Detail))
+		}
+	}
+}
+
+// parseSSHDConfig parses 'sshd -T' output, which has one lowercase keyword and
+// its value per line; keywords such as allowusers may repeat
+func parseSSHDConfig(output string) map[string][]string {
+	settings := make(map[string][]string)
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if ok {
+			settings[key] = append(settings[key], strings.TrimSpace(value))
+		}
+	}
+	return settings
+}
+
+// evaluateSecurity turns PID 1 and the sshd settings into audit results
+func evaluateSecurity(pid1 string, sshd map[string][]string, user string) []SecurityCheck {
+	checks := []SecurityCheck{initCheck(pid1)}
+
+	for _, c := range []struct{ name, keyword, want string }{
+		{"root login disabled", "permitrootlogin", "no"},
+		{"password authentication disabled", "passwordauthentication", "no"},
+		{"keyboard-interactive authentication disabled", "kbdinteractiveauthentication", "no"},
+		{"empty passwords rejected", "permitemptypasswords", "no"},
+		{"public key authentication enabled", "pubkeyauthentication", "yes"},
+	} {
+		got := strings.Join(sshd[c.keyword], " ")
+		check := SecurityCheck{Name: c.name, OK: got == c.want, Detail: fmt.Sprintf("%s %s", c.keyword, got)}
+		if !check.OK {
+			check.Detail += fmt.Sprintf(" (want %s; rebuild the container to regenerate sshd_config)", c.want)
+		}
+		checks = append(checks, check)
+	}
+
+	var allowed []string
+	for _, value := range sshd["allowusers"] {
+		allowed = append(allowed, strings.Fields(value)...)
+	}
+	check := SecurityCheck{Name: "only " + user + " may log in", OK: len(allowed) == 1 && allowed[0] == user}
+	if len(allowed) == 0 {
+		check.Detail = "AllowUsers is not set, so any account with a key may log in"
+	} else {
+		check.Detail = "allowusers " + strings.Join(allowed, " ")
+	}
+	return append(checks, check)
+}
+
+// initCheck verifies sshd is not PID 1
+func initCheck(pid1 string) SecurityCheck {
+	check := SecurityCheck{Name: "runs under an init", OK: initProcesses[pid1], Detail: "PID 1 is " + pid1}
+	if !check.OK {
+		check.Detail += " (no zombie reaping or signal forwarding; rebuild the container to run it under an init)"
+	}
+	return check
+}
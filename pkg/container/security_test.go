@@ -0,0 +1,64 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const hardenedSSHD = `port 22
+permitrootlogin no
+pubkeyauthentication yes
+passwordauthentication no
+kbdinteractiveauthentication no
+permitemptypasswords no
+allowusers dev
+`
+
+func TestManager_AuditSecurity(t *testing.T) {
+	tests := []struct {
+		name   string
+		pid1   string
+		sshd   string
+		failed []string
+	}{
+		{name: "hardened", pid1: "catatonit\n", sshd: hardenedSSHD},
+		{name: "systemd", pid1: "systemd\n", sshd: hardenedSSHD},
+		{name: "sshd as PID 1", pid1: "sshd\n", sshd: hardenedSSHD, failed: []string{"runs under an init"}},
+		{
+			name:   "root and password login",
+			pid1:   "catatonit\n",
+			sshd:   "permitrootlogin prohibit-password\npubkeyauthentication yes\npasswordauthentication yes\nkbdinteractiveauthentication no\npermitemptypasswords no\nallowusers dev\n",
+			failed: []string{"root login disabled", "password authentication disabled"},
+		},
+		{
+			name:   "extra users allowed",
+			pid1:   "catatonit\n",
+			sshd:   hardenedSSHD + "allowusers root\n",
+			failed: []string{"only dev may log in"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(MockPodmanClient)
+			m.On("ExecContainerOutput", mock.Anything, "dev-app", []string{"cat", "/proc/1/comm"}).Return(tt.pid1, nil)
+			m.On("ExecContainerOutput", mock.Anything, "dev-app", []string{"/usr/sbin/sshd", "-T"}).Return(tt.sshd, nil)
+
+			manager := NewManager(m, Config{ContainerPrefix: "dev", ContainerUser: "dev"})
+			checks, err := manager.AuditSecurity(context.Background(), "app")
+			require.NoError(t, err)
+
+			var failed []string
+			for _, check := range checks {
+				if !check.OK {
+					failed = append(failed, check.Name)
+				}
+			}
+			assert.Equal(t, tt.failed, failed)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"l8s/pkg/config"
+	"l8s/pkg/logging"
+)
+
+// Files in the container user's ~/.ssh used for signing
+const (
+	signingKeyFile     = "l8s_signing_key"
+	allowedSignersFile = "l8s_allowed_signers"
+)
+
+// SigningCheckScript makes a signed commit in a scratch repository and
+// verifies it, failing if either step does
+const SigningCheckScript = `d=$(mktemp -d) && cd "$d" && git init -q && git commit -q --allow-empty -m 'l8s signing check' && git verify-commit HEAD; s=$?; rm -rf "$d"; exit $s`
+
+// Signing is the SSH commit signing set up in a container
+type Signing struct {
+	Mode      string // config.SigningAgent or config.SigningKey
+	PublicKey string // The signing key, in authorized_keys format
+}
+
+// SigningLabels records signing on a container so rebuilds, which rewrite
+// .gitconfig, can configure it again
+func SigningLabels(s Signing) map[string]string {
+	if s.Mode == "" {
+		return map[string]string{}
+	}
+	return map[string]string{LabelSigning: s.Mode, LabelSignKey: s.PublicKey}
+}
+
+// SigningFromLabels returns the signing recorded on a container
+func SigningFromLabels(labels map[string]string) Signing {
+	return Signing{Mode: labels[LabelSigning], PublicKey: labels[LabelSignKey]}
+}
+
+// signingScript configures git to sign commits and tags with the SSH key and
+// to trust that key when verifying
+func (m *Manager) signingScript(s Signing) string {
+	sshDir := fmt.Sprintf("/home/%s/.ssh", m.config.ContainerUser)
+	signingKey := "key::" + s.PublicKey
+	if s.Mode == config.SigningKey {
+		signingKey = sshDir + "/" + signingKeyFile
+	}
+	allowed := sshDir + "/" + allowedSignersFile
+	return strings.Join([]string{
+		"mkdir -p " + sshDir,
+		"chmod 700 " + sshDir,
+		fmt.Sprintf("echo %s > %s", ShellQuote(`* namespaces="git" `+s.PublicKey), allowed),
+		"git config --global gpg.format ssh",
+		"git config --global user.signingkey " + ShellQuote(signingKey),
+		"git config --global gpg.ssh.allowedSignersFile " + allowed,
+		"git config --global commit.gpgsign true",
+		"git config --global tag.gpgsign true",
+	}, " && ")
+}
+
+// applySigningConfig installs privateKey, if there is one to install, and
+// configures git to sign with it. In agent mode, or when a rebuild finds the
+// key already in the home volume, privateKey is nil.
+func (m *Manager) applySigningConfig(ctx context.Context, containerName string, s Signing, privateKey []byte) error {
+	if s.Mode == "" {
+		return nil
+	}
+	if len(privateKey) > 0 {
+		installCmd := []string{"su", "-", m.config.ContainerUser, "-c",
+			fmt.Sprintf("umask 077 && mkdir -p ~/.ssh && cat > ~/.ssh/%s", signingKeyFile)}
+		if err := m.client.ExecContainerWithInput(ctx, containerName, installCmd, string(privateKey)); err != nil {
+			return fmt.Errorf("failed to install signing key: %w", err)
+		}
+	}
+
+	m.logger.Info("configuring commit signing",
+		logging.WithField("container", containerName),
+		logging.WithField("mode", s.Mode))
+	configCmd := []string{"su", "-", m.config.ContainerUser, "-c", m.signingScript(s)}
+	if err := m.client.ExecContainer(ctx, containerName, configCmd); err != nil {
+		return fmt.Errorf("failed to configure commit signing: %w", err)
+	}
+	return nil
+}
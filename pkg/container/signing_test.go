@@ -0,0 +1,53 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	"l8s/pkg/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testSigningKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIExample jane@laptop"
+
+func TestSigningScript(t *testing.T) {
+	m := &Manager{config: Config{ContainerUser: "dev"}}
+	script := m.signingScript(Signing{Mode: "agent", PublicKey: testSigningKey})
+	assert.Contains(t, script, `echo '* namespaces="git" `+testSigningKey+`' > /home/dev/.ssh/l8s_allowed_signers`)
+	assert.Contains(t, script, "git config --global gpg.format ssh")
+	assert.Contains(t, script, "git config --global user.signingkey 'key::"+testSigningKey+"'")
+	assert.Contains(t, script, "git config --global commit.gpgsign true")
+
+	assert.Contains(t, m.signingScript(Signing{Mode: "key", PublicKey: testSigningKey}), "git config --global user.signingkey /home/dev/.ssh/l8s_signing_key")
+}
+
+func TestSigningLabels(t *testing.T) {
+	s := Signing{Mode: "agent", PublicKey: testSigningKey}
+	labels := SigningLabels(s)
+	assert.Equal(t, map[string]string{LabelSigning: "agent", LabelSignKey: testSigningKey}, labels)
+	assert.Equal(t, s, SigningFromLabels(labels))
+	assert.Empty(t, SigningLabels(Signing{}))
+}
+
+func TestApplySigningConfigInstallsKey(t *testing.T) {
+	ctx := context.Background()
+	client := &MockPodmanClient{}
+	client.On("ExecContainerWithInput", ctx, "dev-app",
+		[]string{"su", "-", "dev", "-c", "umask 077 && mkdir -p ~/.ssh && cat > ~/.ssh/l8s_signing_key"}, "private").Return(nil)
+	client.On("ExecContainer", ctx, "dev-app", mock.AnythingOfType("[]string")).Return(nil)
+
+	m := &Manager{config: Config{ContainerUser: "dev"}, client: client, logger: logging.Default()}
+	assert.NoError(t, m.applySigningConfig(ctx, "dev-app", Signing{}, nil))
+	client.AssertNotCalled(t, "ExecContainerWithInput", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	signing := Signing{Mode: "key", PublicKey: testSigningKey}
+	assert.NoError(t, m.applySigningConfig(ctx, "dev-app", signing, []byte("private")))
+	client.AssertExpectations(t)
+
+	// Rebuilds only reconfigure git; the key is already in the home volume
+	opts := labelCreateOptions(SigningLabels(signing))
+	assert.NoError(t, m.applySigningConfig(ctx, "dev-app", opts.Signing, opts.SigningKey))
+	client.AssertNumberOfCalls(t, "ExecContainerWithInput", 1)
+}
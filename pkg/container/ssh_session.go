@@ -0,0 +1,82 @@
+package container
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// SSHOptions customises an SSH session into a container
+type SSHOptions struct {
+	LocalForwards   []string  // ssh -L specs, e.g. 8080:localhost:3000
+	RemoteForwards  []string  // ssh -R specs
+	DynamicForwards []string  // ssh -D specs, e.g. 1080
+	Command         []string  // Run this in /workspace/project instead of a login shell
+	TTY             bool      // Force TTY allocation for Command
+	Stdout          io.Writer // Defaults to os.Stdout
+	Stderr          io.Writer // Defaults to os.Stderr
+	WorkDir         string    // Defaults to /workspace/project
+	ForwardAgent    bool      // Forward the local SSH agent (ssh -A)
+	ForwardOnly     bool      // Only hold the forwards open, running nothing (ssh -N)
+}
+
+// safeShellWord matches arguments that need no quoting in a remote shell
+var safeShellWord = regexp.MustCompile(`^[a-zA-Z0-9_./:=@%+,-]+$`)
+
+// ShellQuote quotes s for a POSIX shell
+func ShellQuote(s string) string {
+	if s != "" && safeShellWord.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// sshArgs builds the ssh argument list for a session to host
+func sshArgs(host string, opts SSHOptions) []string {
+	var args []string
+	for _, spec := range opts.LocalForwards {
+		args = append(args, "-L", spec)
+	}
+	unlink := false
+	for _, spec := range opts.RemoteForwards {
+		args = append(args, "-R", spec)
+		unlink = unlink || strings.HasPrefix(spec, "/")
+	}
+	if unlink {
+		// A socket left behind by an earlier session would block the forward
+		args = append(args, "-o", "StreamLocalBindUnlink=yes")
+	}
+	for _, spec := range opts.DynamicForwards {
+		args = append(args, "-D", spec)
+	}
+
+	if opts.ForwardAgent {
+		args = append(args, "-A")
+	}
+
+	if opts.ForwardOnly {
+		return append(args, "-N", host)
+	}
+
+	// Interactive sessions always need a TTY; one-off commands only on request
+	// so their output can be piped cleanly
+	if len(opts.Command) == 0 || opts.TTY {
+		args = append(args, "-t")
+	}
+	args = append(args, host)
+
+	workdir := opts.WorkDir
+	if workdir == "" {
+		workdir = ProjectDir
+	}
+	cd := "cd " + ShellQuote(workdir) + " 2>/dev/null; "
+	if len(opts.Command) == 0 {
+		return append(args, cd+"exec $SHELL -l")
+	}
+
+	quoted := make([]string, len(opts.Command))
+	for i, arg := range opts.Command {
+		quoted[i] = ShellQuote(arg)
+	}
+	return append(args, cd+strings.Join(quoted, " "))
+}
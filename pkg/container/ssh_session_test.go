@@ -0,0 +1,61 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSHArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SSHOptions
+		want []string
+	}{
+		{
+			name: "interactive shell",
+			want: []string{"-t", "dev-app", "cd /workspace/project 2>/dev/null; exec $SHELL -l"},
+		},
+		{
+			name: "command without tty",
+			opts: SSHOptions{Command: []string{"go", "test", "./..."}},
+			want: []string{"dev-app", "cd /workspace/project 2>/dev/null; go test ./..."},
+		},
+		{
+			name: "command with tty and quoting",
+			opts: SSHOptions{Command: []string{"echo", "it's here", ""}, TTY: true},
+			want: []string{"-t", "dev-app", `cd /workspace/project 2>/dev/null; echo 'it'\''s here' ''`},
+		},
+		{
+			name: "monorepo subdirectory",
+			opts: SSHOptions{Command: []string{"make"}, WorkDir: "/workspace/project/services/api"},
+			want: []string{"dev-app", "cd /workspace/project/services/api 2>/dev/null; make"},
+		},
+		{
+			name: "agent forwarding",
+			opts: SSHOptions{ForwardAgent: true},
+			want: []string{"-A", "-t", "dev-app", "cd /workspace/project 2>/dev/null; exec $SHELL -l"},
+		},
+		{
+			name: "forwards",
+			opts: SSHOptions{
+				LocalForwards:   []string{"8080:localhost:8080"},
+				RemoteForwards:  []string{"9000:localhost:9000"},
+				DynamicForwards: []string{"1080"},
+			},
+			want: []string{"-L", "8080:localhost:8080", "-R", "9000:localhost:9000", "-D", "1080",
+				"-t", "dev-app", "cd /workspace/project 2>/dev/null; exec $SHELL -l"},
+		},
+		{
+			name: "socket forward only",
+			opts: SSHOptions{RemoteForwards: []string{"/tmp/app.sock:127.0.0.1:4000"}, ForwardOnly: true},
+			want: []string{"-R", "/tmp/app.sock:127.0.0.1:4000", "-o", "StreamLocalBindUnlink=yes", "-N", "dev-app"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sshArgs("dev-app", tt.opts))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// sshdLogFile is where sshd logs inside containers (-E), for fail2ban and 'l8s info'
+const sshdLogFile = "/var/log/sshd.log"
+
+// sshdFailurePattern matches sshd log lines for failed login attempts.
+// Rejected public keys only show up as a preauth disconnect.
+const sshdFailurePattern = `Invalid user |Failed (password|publickey|keyboard-interactive)|maximum authentication attempts exceeded|Connection closed by authenticating user`
+
+// SSHAuthStats summarizes login failures on a container's SSH port
+type SSHAuthStats struct {
+	Failures    int  // Failed attempts logged by sshd
+	Fail2ban    bool // Whether fail2ban is running
+	Banned      int  // Addresses banned now
+	TotalBanned int  // Addresses banned since fail2ban started
+}
+
+// sshdConfig renders the rate limits as sshd_config lines
+func (g SSHGuard) sshdConfig() string {
+	var b strings.Builder
+	if g.MaxAuthTries > 0 {
+		fmt.Fprintf(&b, "MaxAuthTries %d\n", g.MaxAuthTries)
+	}
+	if g.LoginGraceTime != "" {
+		fmt.Fprintf(&b, "LoginGraceTime %s\n", g.LoginGraceTime)
+	}
+	if g.MaxStartups != "" {
+		fmt.Fprintf(&b, "MaxStartups %s\n", g.MaxStartups)
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "# Login rate limiting\n" + b.String() + "\n"
+}
+
+// acceptEnvConfig renders the AcceptEnv line letting exec_env variables
+// through to sessions
+func acceptEnvConfig(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return "# Environment passed by l8s (exec_env)\nAcceptEnv " + strings.Join(names, " ") + "\n\n"
+}
+
+// SSHAuthStats counts failed logins in a running container and, when fail2ban
+// runs there, the addresses it has banned
+func (m *Manager) SSHAuthStats(ctx context.Context, name string) (*SSHAuthStats, error) {
+	containerName := m.config.ContainerPrefix + "-" + name
+
+	// grep -c exits non-zero when nothing matches
+	out, err := m.client.ExecContainerOutput(ctx, containerName, []string{"sh", "-c",
+		fmt.Sprintf("grep -cE '%s' %s 2>/dev/null || true", sshdFailurePattern, sshdLogFile)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sshd log: %w", err)
+	}
+	stats := &SSHAuthStats{}
+	stats.Failures, _ = strconv.Atoi(strings.TrimSpace(out))
+
+	status, err := m.client.ExecContainerOutput(ctx, containerName, []string{"fail2ban-client", "status", "sshd"})
+	if err == nil {
+		stats.Fail2ban = true
+		stats.Banned = fail2banCount(status, "Currently banned")
+		stats.TotalBanned = fail2banCount(status, "Total banned")
+	}
+	return stats, nil
+}
+
+// fail2banCount reads a "<field>: N" line from 'fail2ban-client status <jail>'
+func fail2banCount(status, field string) int {
+	match := regexp.MustCompile(regexp.QuoteMeta(field) + `:\s*([0-9]+)`).FindStringSubmatch(status)
+	if match == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(match[1])
+	return n
+}
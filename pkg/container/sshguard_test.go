@@ -0,0 +1,56 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSHGuard_SSHDConfig(t *testing.T) {
+	assert.Empty(t, SSHGuard{}.sshdConfig())
+	assert.Equal(t, "# Login rate limiting\nMaxAuthTries 3\nLoginGraceTime 30s\nMaxStartups 10:30:60\n\n",
+		SSHGuard{MaxAuthTries: 3, LoginGraceTime: "30s", MaxStartups: "10:30:60"}.sshdConfig())
+}
+
+func TestAcceptEnvConfig(t *testing.T) {
+	assert.Empty(t, acceptEnvConfig(nil))
+	assert.Equal(t, "# Environment passed by l8s (exec_env)\nAcceptEnv LANG LC_* EDITOR\n\n",
+		acceptEnvConfig([]string{"LANG", "LC_*", "EDITOR"}))
+}
+
+func TestManager_SSHAuthStats(t *testing.T) {
+	fail2banStatus := `Status for the jail: sshd
+|- Filter
+|  |- Currently failed:	1
+|  |- Total failed:	12
+|  ` + "`" + `- File list:	/var/log/sshd.log
+` + "`" + `- Actions
+   |- Currently banned:	2
+   |- Total banned:	5
+   ` + "`" + `- Banned IP list:	192.0.2.1 192.0.2.7
+`
+
+	t.Run("with fail2ban", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("ExecContainerOutput", mock.Anything, "dev-app", mock.MatchedBy(func(cmd []string) bool { return cmd[0] == "sh" })).Return("12\n", nil)
+		m.On("ExecContainerOutput", mock.Anything, "dev-app", []string{"fail2ban-client", "status", "sshd"}).Return(fail2banStatus, nil)
+
+		stats, err := NewManager(m, Config{ContainerPrefix: "dev"}).SSHAuthStats(context.Background(), "app")
+		require.NoError(t, err)
+		assert.Equal(t, &SSHAuthStats{Failures: 12, Fail2ban: true, Banned: 2, TotalBanned: 5}, stats)
+	})
+
+	t.Run("without fail2ban or log", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("ExecContainerOutput", mock.Anything, "dev-app", mock.MatchedBy(func(cmd []string) bool { return cmd[0] == "sh" })).Return("", nil)
+		m.On("ExecContainerOutput", mock.Anything, "dev-app", []string{"fail2ban-client", "status", "sshd"}).Return("", errors.New("exit status 255"))
+
+		stats, err := NewManager(m, Config{ContainerPrefix: "dev"}).SSHAuthStats(context.Background(), "app")
+		require.NoError(t, err)
+		assert.Equal(t, &SSHAuthStats{}, stats)
+	})
+}
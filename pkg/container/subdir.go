@@ -0,0 +1,68 @@
+package container
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ProjectDir is where the repository is checked out in every container
+const ProjectDir = "/workspace/project"
+
+// workdirFile in the container user's home holds the directory login shells
+// start in; the embedded .zshrc reads it
+const workdirFile = ".l8s_workdir"
+
+// CleanSubdir normalises a monorepo subdirectory given to 'l8s create
+// --subdir', rejecting paths that leave the repository
+func CleanSubdir(subdir string) (string, error) {
+	cleaned := path.Clean(strings.ReplaceAll(strings.TrimSpace(subdir), "\\", "/"))
+	if cleaned == "." || cleaned == "" {
+		return "", nil
+	}
+	if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("subdirectory '%s' must be a path inside the repository", subdir)
+	}
+	return cleaned, nil
+}
+
+// WorkDir returns the directory sessions in a container start in: the
+// recorded subdirectory of the project, or the project itself
+func WorkDir(labels map[string]string) string {
+	if subdir := labels[LabelSubdir]; subdir != "" {
+		return path.Join(ProjectDir, subdir)
+	}
+	return ProjectDir
+}
+
+// sparseDirs returns the directories the working tree is limited to: a
+// monorepo Subdir, which shells and commands also start in, and any Sparse
+// directories. History is pushed in full; only the checkout is sparse.
+func (o CreateOptions) sparseDirs() []string {
+	var dirs []string
+	if o.Subdir != "" {
+		dirs = append(dirs, o.Subdir)
+	}
+	return append(dirs, o.Sparse...)
+}
+
+// SparseCheckoutArgs returns the shell quoted git arguments that limit a
+// working tree to dirs in cone mode
+func SparseCheckoutArgs(dirs []string) string {
+	quoted := make([]string, len(dirs))
+	for i, dir := range dirs {
+		quoted[i] = ShellQuote(dir)
+	}
+	return "sparse-checkout set --cone -- " + strings.Join(quoted, " ")
+}
+
+// sparseCheckoutScript restricts the working tree before anything is pushed,
+// so the push never materialises the rest of the tree, and records where
+// shells should start
+func (o CreateOptions) sparseCheckoutScript() string {
+	script := fmt.Sprintf("cd %s && git %s", ProjectDir, SparseCheckoutArgs(o.sparseDirs()))
+	if o.Subdir != "" {
+		script += fmt.Sprintf(" && echo %s > ~/%s", ShellQuote(path.Join(ProjectDir, o.Subdir)), workdirFile)
+	}
+	return script
+}
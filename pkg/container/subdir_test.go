@@ -0,0 +1,46 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanSubdir(t *testing.T) {
+	for in, want := range map[string]string{
+		"services/api":    "services/api",
+		"./services/api/": "services/api",
+		`services\api`:    "services/api",
+		".":               "",
+		"":                "",
+	} {
+		got, err := CleanSubdir(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, bad := range []string{"/etc", "..", "../other", "services/../../x"} {
+		_, err := CleanSubdir(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestWorkDir(t *testing.T) {
+	assert.Equal(t, "/workspace/project", WorkDir(nil))
+	assert.Equal(t, "/workspace/project/services/api", WorkDir(map[string]string{LabelSubdir: "services/api"}))
+}
+
+func TestSparseCheckoutScript(t *testing.T) {
+	opts := CreateOptions{Subdir: "services/my api"}
+	assert.Equal(t, "cd /workspace/project && git sparse-checkout set --cone -- 'services/my api' && "+
+		"echo '/workspace/project/services/my api' > ~/.l8s_workdir", opts.sparseCheckoutScript())
+}
+
+func TestSparseCheckoutScriptWithDirs(t *testing.T) {
+	opts := CreateOptions{Sparse: []string{"libs/common", "docs"}}
+	assert.Equal(t, "cd /workspace/project && git sparse-checkout set --cone -- libs/common docs", opts.sparseCheckoutScript())
+
+	opts.Subdir = "services/api"
+	assert.Equal(t, "cd /workspace/project && git sparse-checkout set --cone -- services/api libs/common docs && "+
+		"echo /workspace/project/services/api > ~/.l8s_workdir", opts.sparseCheckoutScript())
+}
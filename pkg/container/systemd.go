@@ -0,0 +1,37 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"l8s/pkg/logging"
+)
+
+// systemdBootTimeout bounds how long 'systemctl is-system-running --wait' may block
+const systemdBootTimeout = "60"
+
+// waitForSystemd blocks until systemd in a container has finished booting.
+// sshd answers before all units are up, so containers created with --systemd
+// are only ready once boot completes. A degraded system (some unit failed)
+// still counts as ready; the failed units are for the user to look at.
+func (m *Manager) waitForSystemd(ctx context.Context, containerName string) error {
+	out, err := m.client.ExecContainerOutput(ctx, containerName, []string{"sh", "-c",
+		"timeout " + systemdBootTimeout + " systemctl is-system-running --wait; true"})
+	if err != nil {
+		return fmt.Errorf("failed to query systemd: %w", err)
+	}
+	switch state := strings.TrimSpace(out); state {
+	case "running":
+		return nil
+	case "degraded":
+		m.logger.Warn("systemd reports failed units; see 'systemctl --failed'",
+			logging.WithField("container", containerName))
+		return nil
+	default:
+		if state == "" {
+			state = "unknown"
+		}
+		return fmt.Errorf("systemd did not finish booting (state: %s)", state)
+	}
+}
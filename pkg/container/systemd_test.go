@@ -0,0 +1,36 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestManager_WaitForSystemd(t *testing.T) {
+	tests := []struct {
+		state   string
+		wantErr bool
+	}{
+		{state: "running\n"},
+		{state: "degraded\n"},
+		{state: "starting\n", wantErr: true},
+		{state: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			m := new(MockPodmanClient)
+			m.On("ExecContainerOutput", mock.Anything, "dev-app", mock.Anything).Return(tt.state, nil)
+
+			manager := NewManager(m, Config{ContainerPrefix: "dev"})
+			err := manager.waitForSystemd(context.Background(), "dev-app")
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "did not finish booting")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package container
+
+import (
+	"context"
+	"fmt"
+
+	"l8s/pkg/config"
+)
+
+// nixStoreSuffix names the volume holding /nix, shared by every container
+// with the same prefix so store paths are downloaded and built only once
+const nixStoreSuffix = "-nix-store"
+
+// nixInstallURL is the official installer, run in single-user mode
+const nixInstallURL = "https://nixos.org/nix/install"
+
+// devboxInstallURL is Jetify's installer for the devbox binary
+const devboxInstallURL = "https://get.jetify.com/devbox"
+
+// miseCacheSuffix names the volume holding mise's download cache, shared by
+// every container with the same prefix
+const miseCacheSuffix = "-mise-cache"
+
+// miseInstallURL installs the mise binary to ~/.local/bin
+const miseInstallURL = "https://mise.run"
+
+// usesNix reports whether the toolchain manager is built on Nix
+func usesNix(toolchain string) bool {
+	return toolchain == config.ToolchainNix || toolchain == config.ToolchainDevbox
+}
+
+// miseCacheDir is where mise keeps downloads for the container user
+func miseCacheDir(user string) string {
+	return fmt.Sprintf("/home/%s/.cache/mise", user)
+}
+
+// toolchainMount returns the shared volume the toolchain manager keeps its
+// store or downloads in
+func (m *Manager) toolchainMount(toolchain string) (Mount, bool) {
+	switch {
+	case usesNix(toolchain):
+		return Mount{Source: m.config.ContainerPrefix + nixStoreSuffix, Target: "/nix"}, true
+	case toolchain == config.ToolchainMise:
+		return Mount{Source: m.config.ContainerPrefix + miseCacheSuffix, Target: miseCacheDir(m.config.ContainerUser)}, true
+	}
+	return Mount{}, false
+}
+
+// toolchainRootScript prepares the parts of the toolchain outside the
+// persistent volumes, which a rebuild starts over with: ownership of the
+// shared volume, the shell profile and the devbox binary
+func toolchainRootScript(toolchain, user string) string {
+	switch {
+	case usesNix(toolchain):
+		script := fmt.Sprintf(`set -e
+chown %[1]s: /nix
+ln -sf /home/%[1]s/.nix-profile/etc/profile.d/nix.sh /etc/profile.d/l8s-nix.sh
+`, user)
+		if toolchain == config.ToolchainDevbox {
+			script += fmt.Sprintf("command -v devbox >/dev/null 2>&1 || curl -fsSL %s | bash -s -- -f\n", devboxInstallURL)
+		}
+		return script
+	case toolchain == config.ToolchainMise:
+		// Shims rather than 'mise activate', so non-interactive commands get the pinned versions too
+		return fmt.Sprintf(`set -e
+chown %[1]s: %[2]s
+echo 'export PATH="$HOME/.local/bin:$HOME/.local/share/mise/shims:$PATH"' > /etc/profile.d/l8s-mise.sh
+`, user, miseCacheDir(user))
+	}
+	return ""
+}
+
+// toolchainUserScript installs the toolchain manager into the home volume,
+// unless it is already there: single-user Nix with flakes enabled, or mise
+func toolchainUserScript(toolchain string) string {
+	switch {
+	case usesNix(toolchain):
+		return fmt.Sprintf(`set -e
+[ -x "$HOME/.nix-profile/bin/nix" ] || curl -fsSL %s | sh -s -- --no-daemon --no-modify-profile
+mkdir -p "$HOME/.config/nix"
+grep -qs flakes "$HOME/.config/nix/nix.conf" || echo 'experimental-features = nix-command flakes' >> "$HOME/.config/nix/nix.conf"
+`, nixInstallURL)
+	case toolchain == config.ToolchainMise:
+		return fmt.Sprintf(`set -e
+[ -x "$HOME/.local/bin/mise" ] || curl -fsSL %s | sh
+`, miseInstallURL)
+	}
+	return ""
+}
+
+// provisionToolchain installs the container's toolchain manager
+func (m *Manager) provisionToolchain(ctx context.Context, containerName, toolchain string) error {
+	if script := toolchainRootScript(toolchain, m.config.ContainerUser); script != "" {
+		if _, err := m.client.ExecContainerOutput(ctx, containerName, []string{"sh", "-c", script}); err != nil {
+			return fmt.Errorf("failed to prepare %s: %w", toolchain, err)
+		}
+	}
+	if script := toolchainUserScript(toolchain); script != "" {
+		installCmd := []string{"su", "-", m.config.ContainerUser, "-c", script}
+		if _, err := m.client.ExecContainerOutput(ctx, containerName, installCmd); err != nil {
+			return fmt.Errorf("failed to install %s: %w", toolchain, err)
+		}
+	}
+	return nil
+}
+
+// ToolchainSetupScript returns the script that sets up the repository's
+// environment with its toolchain manager, fetching or building everything
+// the environment needs so the first shell starts quickly
+func ToolchainSetupScript(toolchain string) string {
+	switch toolchain {
+	case config.ToolchainNix:
+		return fmt.Sprintf(`. "$HOME/.nix-profile/etc/profile.d/nix.sh"
+cd %s
+if [ -f flake.nix ]; then
+	nix develop --command true
+elif [ -f shell.nix ] || [ -f default.nix ]; then
+	nix-shell --run true
+else
+	echo "no flake.nix or shell.nix in the repository" >&2
+	exit 1
+fi
+`, ProjectDir)
+	case config.ToolchainDevbox:
+		return fmt.Sprintf(`. "$HOME/.nix-profile/etc/profile.d/nix.sh"
+cd %s
+devbox install
+`, ProjectDir)
+	case config.ToolchainMise:
+		// The repository's config is trusted: it is the code the container was created for
+		return fmt.Sprintf(`set -e
+cd %s
+"$HOME/.local/bin/mise" trust --all
+"$HOME/.local/bin/mise" install --yes
+`, ProjectDir)
+	}
+	return ""
+}
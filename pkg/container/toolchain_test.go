@@ -0,0 +1,49 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolchainMount(t *testing.T) {
+	manager := NewManager(new(MockPodmanClient), Config{ContainerPrefix: "dev", ContainerUser: "dev"})
+	_, ok := manager.toolchainMount("")
+	assert.False(t, ok)
+
+	var config ContainerConfig
+	manager.applyMounts(&config, CreateOptions{Toolchain: "devbox"})
+	assert.Equal(t, []Mount{{Source: "dev-nix-store", Target: "/nix"}}, config.Mounts)
+	assert.Equal(t, "dev-nix-store:/nix", config.Labels[LabelMounts])
+}
+
+func TestToolchainScripts(t *testing.T) {
+	assert.Empty(t, toolchainRootScript("", "dev"))
+	assert.Empty(t, toolchainUserScript(""))
+	assert.Empty(t, ToolchainSetupScript(""))
+
+	root := toolchainRootScript("nix", "dev")
+	assert.Contains(t, root, "chown dev: /nix")
+	assert.NotContains(t, root, "devbox")
+	assert.Contains(t, toolchainRootScript("devbox", "dev"), "command -v devbox")
+
+	user := toolchainUserScript("nix")
+	assert.Contains(t, user, "--no-daemon")
+	assert.Contains(t, user, "experimental-features = nix-command flakes")
+
+	assert.Contains(t, ToolchainSetupScript("nix"), "nix develop --command true")
+	assert.Contains(t, ToolchainSetupScript("devbox"), "devbox install")
+}
+
+func TestToolchainMise(t *testing.T) {
+	manager := NewManager(new(MockPodmanClient), Config{ContainerPrefix: "dev", ContainerUser: "dev"})
+	mount, ok := manager.toolchainMount("mise")
+	assert.True(t, ok)
+	assert.Equal(t, Mount{Source: "dev-mise-cache", Target: "/home/dev/.cache/mise"}, mount)
+
+	root := toolchainRootScript("mise", "dev")
+	assert.Contains(t, root, "chown dev: /home/dev/.cache/mise")
+	assert.Contains(t, root, "mise/shims")
+	assert.Contains(t, toolchainUserScript("mise"), "https://mise.run")
+	assert.Contains(t, ToolchainSetupScript("mise"), "mise\" install --yes")
+}
@@ -0,0 +1,144 @@
+package container
+
+import (
+	"context"
+	"io"
+
+	"l8s/pkg/logging"
+)
+
+// tracingClient logs every PodmanClient call with its duration at trace level
+type tracingClient struct {
+	client PodmanClient
+}
+
+func (t *tracingClient) ContainerExists(ctx context.Context, name string) (_ bool, err error) {
+	defer logging.TraceCall("podman", "ContainerExists", name)(&err)
+	return t.client.ContainerExists(ctx, name)
+}
+
+func (t *tracingClient) CreateContainer(ctx context.Context, config ContainerConfig) (_ *Container, err error) {
+	defer logging.TraceCall("podman", "CreateContainer", config.Name, config.BaseImage)(&err)
+	return t.client.CreateContainer(ctx, config)
+}
+
+func (t *tracingClient) StartContainer(ctx context.Context, name string) (err error) {
+	defer logging.TraceCall("podman", "StartContainer", name)(&err)
+	return t.client.StartContainer(ctx, name)
+}
+
+func (t *tracingClient) StopContainer(ctx context.Context, name string) (err error) {
+	defer logging.TraceCall("podman", "StopContainer", name)(&err)
+	return t.client.StopContainer(ctx, name)
+}
+
+func (t *tracingClient) RemoveContainer(ctx context.Context, name string, removeVolumes bool) (err error) {
+	defer logging.TraceCall("podman", "RemoveContainer", name, removeVolumes)(&err)
+	return t.client.RemoveContainer(ctx, name, removeVolumes)
+}
+
+func (t *tracingClient) ListContainers(ctx context.Context) (_ []*Container, err error) {
+	defer logging.TraceCall("podman", "ListContainers")(&err)
+	return t.client.ListContainers(ctx)
+}
+
+func (t *tracingClient) GetContainerInfo(ctx context.Context, name string) (_ *Container, err error) {
+	defer logging.TraceCall("podman", "GetContainerInfo", name)(&err)
+	return t.client.GetContainerInfo(ctx, name)
+}
+
+func (t *tracingClient) InspectContainer(ctx context.Context, name string) (_ []byte, err error) {
+	defer logging.TraceCall("podman", "InspectContainer", name)(&err)
+	return t.client.InspectContainer(ctx, name)
+}
+
+func (t *tracingClient) FindAvailablePort(startPort int) (_ int, err error) {
+	defer logging.TraceCall("podman", "FindAvailablePort", startPort)(&err)
+	return t.client.FindAvailablePort(startPort)
+}
+
+func (t *tracingClient) ExecContainer(ctx context.Context, name string, cmd []string) (err error) {
+	defer logging.TraceCall("podman", "ExecContainer", name, cmd)(&err)
+	return t.client.ExecContainer(ctx, name, cmd)
+}
+
+func (t *tracingClient) ExecContainerOutput(ctx context.Context, name string, cmd []string) (_ string, err error) {
+	defer logging.TraceCall("podman", "ExecContainerOutput", name, cmd)(&err)
+	return t.client.ExecContainerOutput(ctx, name, cmd)
+}
+
+// ExecContainerWithInput only traces the input's size; it often carries keys
+func (t *tracingClient) ExecContainerWithInput(ctx context.Context, name string, cmd []string, input string) (err error) {
+	defer logging.TraceCall("podman", "ExecContainerWithInput", name, cmd, []byte(input))(&err)
+	return t.client.ExecContainerWithInput(ctx, name, cmd, input)
+}
+
+func (t *tracingClient) ExecContainerStream(ctx context.Context, name string, cmd []string, stdout, stderr io.Writer) (err error) {
+	defer logging.TraceCall("podman", "ExecContainerStream", name, cmd)(&err)
+	return t.client.ExecContainerStream(ctx, name, cmd, stdout, stderr)
+}
+
+func (t *tracingClient) CopyToContainer(ctx context.Context, name string, src, dst string) (err error) {
+	defer logging.TraceCall("podman", "CopyToContainer", name, src, dst)(&err)
+	return t.client.CopyToContainer(ctx, name, src, dst)
+}
+
+func (t *tracingClient) RenameContainer(ctx context.Context, name, newName string) (err error) {
+	defer logging.TraceCall("podman", "RenameContainer", name, newName)(&err)
+	return t.client.RenameContainer(ctx, name, newName)
+}
+
+func (t *tracingClient) CopyVolume(ctx context.Context, src, dst string) (err error) {
+	defer logging.TraceCall("podman", "CopyVolume", src, dst)(&err)
+	return t.client.CopyVolume(ctx, src, dst)
+}
+
+func (t *tracingClient) RemoveVolume(ctx context.Context, name string) (err error) {
+	defer logging.TraceCall("podman", "RemoveVolume", name)(&err)
+	return t.client.RemoveVolume(ctx, name)
+}
+
+func (t *tracingClient) ExportVolume(ctx context.Context, name string, w io.Writer) (err error) {
+	defer logging.TraceCall("podman", "ExportVolume", name)(&err)
+	return t.client.ExportVolume(ctx, name, w)
+}
+
+func (t *tracingClient) ListVolumes(ctx context.Context) (_ []string, err error) {
+	defer logging.TraceCall("podman", "ListVolumes")(&err)
+	return t.client.ListVolumes(ctx)
+}
+
+func (t *tracingClient) DiffContainer(ctx context.Context, name string) (_ []FileChange, err error) {
+	defer logging.TraceCall("podman", "DiffContainer", name)(&err)
+	return t.client.DiffContainer(ctx, name)
+}
+
+func (t *tracingClient) RunImage(ctx context.Context, image string, cmd []string) (_ string, err error) {
+	defer logging.TraceCall("podman", "RunImage", image, cmd)(&err)
+	return t.client.RunImage(ctx, image, cmd)
+}
+
+func (t *tracingClient) DescribeContainer(ctx context.Context, name string) (_ *ForeignContainer, err error) {
+	defer logging.TraceCall("podman", "DescribeContainer", name)(&err)
+	return t.client.DescribeContainer(ctx, name)
+}
+
+func (t *tracingClient) CommitContainer(ctx context.Context, name, image string) (err error) {
+	defer logging.TraceCall("podman", "CommitContainer", name, image)(&err)
+	return t.client.CommitContainer(ctx, name, image)
+}
+
+func (t *tracingClient) RemoveImage(ctx context.Context, image string) (err error) {
+	defer logging.TraceCall("podman", "RemoveImage", image)(&err)
+	return t.client.RemoveImage(ctx, image)
+}
+
+func (t *tracingClient) DiskUsage(ctx context.Context) (_ *DiskUsage, err error) {
+	defer logging.TraceCall("podman", "DiskUsage")(&err)
+	return t.client.DiskUsage(ctx)
+}
+
+func (t *tracingClient) Version(ctx context.Context) (_ *RemoteVersion, err error) {
+	defer logging.TraceCall("podman", "Version")(&err)
+	return t.client.Version(ctx)
+}
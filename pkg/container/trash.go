@@ -0,0 +1,357 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"l8s/pkg/cleanup"
+	"l8s/pkg/config"
+	"l8s/pkg/logging"
+)
+
+// trashPrefix namespaces volumes of removed containers awaiting purge
+const trashPrefix = "l8s-trash."
+
+// TrashedContainer describes the volumes left behind by a trashed container
+type TrashedContainer struct {
+	Name      string // Full container name, e.g. dev-myproject
+	TrashedAt time.Time
+	Volumes   []string // Trash volume names
+}
+
+// TrashRecordFile is the file in the l8s state directory that keeps what
+// trashed volumes cannot: the labels of the containers they belonged to
+const TrashRecordFile = "trash.json"
+
+// trashRecord is what RestoreContainer needs besides the volumes
+type trashRecord struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// trashRecords persists trash records keyed by trashKey
+type trashRecords struct {
+	path string
+}
+
+func trashRecordsFile() *trashRecords {
+	return &trashRecords{path: filepath.Join(config.StateDir(), TrashRecordFile)}
+}
+
+// trashKey identifies one trashing of a container on a host
+func trashKey(host, containerName string, ts time.Time) string {
+	return fmt.Sprintf("%s/%s@%d", host, containerName, ts.Unix())
+}
+
+func (r *trashRecords) get(key string) (trashRecord, bool, error) {
+	records, err := r.read()
+	if err != nil {
+		return trashRecord{}, false, err
+	}
+	record, ok := records[key]
+	return record, ok, nil
+}
+
+func (r *trashRecords) put(key string, record trashRecord) error {
+	records, err := r.read()
+	if err != nil {
+		return err
+	}
+	records[key] = record
+	return r.write(records)
+}
+
+func (r *trashRecords) drop(key string) error {
+	records, err := r.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := records[key]; !ok {
+		return nil
+	}
+	delete(records, key)
+	return r.write(records)
+}
+
+func (r *trashRecords) read() (map[string]trashRecord, error) {
+	records := make(map[string]trashRecord)
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash records: %w", err)
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse trash records %s: %w", r.path, err)
+	}
+	return records, nil
+}
+
+// write replaces the file atomically so concurrent runs never see a partial file
+func (r *trashRecords) write(records map[string]trashRecord) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trash records: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), ".trash-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write trash records: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write trash records: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write trash records: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("failed to write trash records: %w", err)
+	}
+	return nil
+}
+
+// trashVolumeName returns the trash namespace name for a volume
+func trashVolumeName(volume string, ts time.Time) string {
+	return fmt.Sprintf("%s%d.%s", trashPrefix, ts.Unix(), volume)
+}
+
+// parseTrashVolumeName splits a trash volume name into container name and time
+func parseTrashVolumeName(name string) (string, time.Time, bool) {
+	rest, ok := strings.CutPrefix(name, trashPrefix)
+	if !ok {
+		return "", time.Time{}, false
+	}
+	stamp, volume, ok := strings.Cut(rest, ".")
+	if !ok {
+		return "", time.Time{}, false
+	}
+	unix, err := strconv.ParseInt(stamp, 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	for _, suffix := range volumeSuffixes {
+		if containerName, ok := strings.CutSuffix(volume, suffix); ok && containerName != "" {
+			return containerName, time.Unix(unix, 0), true
+		}
+	}
+	return "", time.Time{}, false
+}
+
+// TrashContainer removes a container but moves its volumes into the trash
+// namespace so it can be restored with RestoreContainer until purged. The
+// volumes are copied before the container is removed, so a failed copy
+// leaves the container as it was.
+func (m *Manager) TrashContainer(ctx context.Context, name string) (err error) {
+	containerName := m.config.ContainerPrefix + "-" + name
+
+	info, err := m.client.GetContainerInfo(ctx, containerName)
+	if err != nil {
+		return fmt.Errorf("container '%s' not found: %w", name, err)
+	}
+
+	// Stop the container so its volumes are copied in a consistent state
+	cleaner := cleanup.New(m.logger)
+	defer cleaner.CleanupOnError(context.WithoutCancel(ctx), &err)
+	if info.Status == "running" {
+		if err := m.client.StopContainer(ctx, containerName); err != nil {
+			return fmt.Errorf("failed to stop container: %w", err)
+		}
+		cleaner.Add("restart_container", func(ctx context.Context) error {
+			return m.client.StartContainer(ctx, containerName)
+		})
+	}
+
+	// Podman cannot rename volumes, so copy into the trash name and drop the
+	// originals once the container is gone
+	ts := time.Now()
+	for _, volume := range containerVolumes(containerName) {
+		trashName := trashVolumeName(volume, ts)
+		cleaner.Add("remove_volume_"+trashName, func(ctx context.Context) error {
+			return m.client.RemoveVolume(ctx, trashName)
+		})
+		if err := m.client.CopyVolume(ctx, volume, trashName); err != nil {
+			return fmt.Errorf("failed to move volume %s to trash (container kept): %w", volume, err)
+		}
+	}
+
+	records := trashRecordsFile()
+	key := trashKey(m.config.RemoteHost, containerName, ts)
+	if err := records.put(key, trashRecord{Labels: info.Labels}); err != nil {
+		return err
+	}
+	cleaner.Add("forget_trash_record", func(ctx context.Context) error {
+		return records.drop(key)
+	})
+
+	if err := m.RemoveContainer(ctx, name, false); err != nil {
+		return err
+	}
+	for _, volume := range containerVolumes(containerName) {
+		if err := m.client.RemoveVolume(ctx, volume); err != nil {
+			m.logger.Warn("failed to remove trashed volume",
+				logging.WithError(err),
+				logging.WithField("volume", volume))
+		}
+	}
+
+	m.logger.Info("container moved to trash",
+		logging.WithField("container", containerName))
+
+	return nil
+}
+
+// ListTrash returns trashed containers, most recently trashed first
+func (m *Manager) ListTrash(ctx context.Context) ([]*TrashedContainer, error) {
+	volumes, err := m.client.ListVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*TrashedContainer)
+	for _, volume := range volumes {
+		containerName, ts, ok := parseTrashVolumeName(volume)
+		if !ok || !strings.HasPrefix(containerName, m.config.ContainerPrefix+"-") {
+			continue
+		}
+		key := fmt.Sprintf("%s@%d", containerName, ts.Unix())
+		entry, exists := byKey[key]
+		if !exists {
+			entry = &TrashedContainer{Name: containerName, TrashedAt: ts}
+			byKey[key] = entry
+		}
+		entry.Volumes = append(entry.Volumes, volume)
+	}
+
+	trashed := make([]*TrashedContainer, 0, len(byKey))
+	for _, entry := range byKey {
+		sort.Strings(entry.Volumes)
+		trashed = append(trashed, entry)
+	}
+	sort.Slice(trashed, func(i, j int) bool {
+		return trashed[i].TrashedAt.After(trashed[j].TrashedAt)
+	})
+	return trashed, nil
+}
+
+// RestoreContainer recreates a trashed container from its most recent trash volumes
+func (m *Manager) RestoreContainer(ctx context.Context, name string) (*Container, error) {
+	containerName := m.config.ContainerPrefix + "-" + name
+
+	trashed, err := m.ListTrash(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trash: %w", err)
+	}
+	var entry *TrashedContainer
+	for _, t := range trashed {
+		if t.Name == containerName {
+			entry = t
+			break
+		}
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("no trashed container named '%s'", name)
+	}
+
+	exists, err := m.client.ContainerExists(ctx, containerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check container existence: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("container '%s' already exists", name)
+	}
+	if err := m.checkQuota(ctx); err != nil {
+		return nil, err
+	}
+
+	// Copy trash volumes back to their original names; trash is only
+	// dropped once the container is running again
+	cleaner := cleanup.New(m.logger)
+	for _, trashVolume := range entry.Volumes {
+		volume := strings.TrimPrefix(trashVolume, fmt.Sprintf("%s%d.", trashPrefix, entry.TrashedAt.Unix()))
+		if err := m.client.CopyVolume(ctx, trashVolume, volume); err != nil {
+			cleaner.Cleanup(ctx)
+			return nil, fmt.Errorf("failed to restore volume %s: %w", volume, err)
+		}
+		cleaner.Add("remove_volume_"+volume, func(ctx context.Context) error {
+			return m.client.RemoveVolume(ctx, volume)
+		})
+	}
+
+	// The container comes back with the labels it was trashed with, which
+	// select its image and record its owner, worktree and settings
+	records := trashRecordsFile()
+	key := trashKey(m.config.RemoteHost, containerName, entry.TrashedAt)
+	record, ok, err := records.get(key)
+	if err != nil {
+		m.logger.Warn("failed to read trash record, restoring without labels",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	} else if !ok {
+		m.logger.Warn("no trash record found, restoring without labels",
+			logging.WithField("container", containerName))
+	}
+
+	container, err := m.createFromVolumes(ctx, name, record.Labels, cleaner)
+	if err != nil {
+		return nil, err
+	}
+	if err := records.drop(key); err != nil {
+		m.logger.Warn("failed to remove trash record",
+			logging.WithError(err),
+			logging.WithField("container", containerName))
+	}
+
+	for _, trashVolume := range entry.Volumes {
+		if err := m.client.RemoveVolume(ctx, trashVolume); err != nil {
+			m.logger.Warn("failed to remove trash volume",
+				logging.WithError(err),
+				logging.WithField("volume", trashVolume))
+		}
+	}
+
+	m.logger.Info("container restored from trash",
+		logging.WithField("container", containerName))
+
+	return container, nil
+}
+
+// PurgeTrash permanently deletes trashed volumes older than retention
+func (m *Manager) PurgeTrash(ctx context.Context, retention time.Duration) ([]*TrashedContainer, error) {
+	trashed, err := m.ListTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var purged []*TrashedContainer
+	cutoff := time.Now().Add(-retention)
+	for _, entry := range trashed {
+		if entry.TrashedAt.After(cutoff) {
+			continue
+		}
+		for _, volume := range entry.Volumes {
+			if err := m.client.RemoveVolume(ctx, volume); err != nil {
+				return purged, err
+			}
+		}
+		if err := trashRecordsFile().drop(trashKey(m.config.RemoteHost, entry.Name, entry.TrashedAt)); err != nil {
+			m.logger.Warn("failed to remove trash record",
+				logging.WithError(err),
+				logging.WithField("container", entry.Name))
+		}
+		purged = append(purged, entry)
+	}
+	return purged, nil
+}
@@ -0,0 +1,174 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashVolumeName(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	name := trashVolumeName("dev-my-project-workspace", ts)
+	assert.Equal(t, "l8s-trash.1700000000.dev-my-project-workspace", name)
+
+	containerName, parsed, ok := parseTrashVolumeName(name)
+	require.True(t, ok)
+	assert.Equal(t, "dev-my-project", containerName)
+	assert.True(t, ts.Equal(parsed))
+
+	for _, invalid := range []string{"dev-foo-home", "l8s-trash.abc.dev-foo-home", "l8s-trash.1700000000.dev-foo-cache"} {
+		_, _, ok := parseTrashVolumeName(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
+
+func TestManager_TrashAndRestore(t *testing.T) {
+	old := time.Now().Add(-30 * 24 * time.Hour).Unix()
+	recent := time.Now().Add(-time.Hour).Unix()
+	volumes := []string{
+		"dev-alpha-home",
+		trashVolumeName("dev-beta-home", time.Unix(recent, 0)),
+		trashVolumeName("dev-beta-workspace", time.Unix(recent, 0)),
+		trashVolumeName("dev-gamma-home", time.Unix(old, 0)),
+		trashVolumeName("dev-gamma-workspace", time.Unix(old, 0)),
+	}
+
+	t.Run("list groups volumes per container", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("ListVolumes", mock.Anything).Return(volumes, nil)
+		manager := NewManager(m, Config{ContainerPrefix: "dev"})
+
+		trashed, err := manager.ListTrash(context.Background())
+		require.NoError(t, err)
+		require.Len(t, trashed, 2)
+		assert.Equal(t, "dev-beta", trashed[0].Name)
+		assert.Len(t, trashed[0].Volumes, 2)
+	})
+
+	t.Run("purge only removes expired entries", func(t *testing.T) {
+		m := new(MockPodmanClient)
+		m.On("ListVolumes", mock.Anything).Return(volumes, nil)
+		m.On("RemoveVolume", mock.Anything, trashVolumeName("dev-gamma-home", time.Unix(old, 0))).Return(nil)
+		m.On("RemoveVolume", mock.Anything, trashVolumeName("dev-gamma-workspace", time.Unix(old, 0))).Return(nil)
+		manager := NewManager(m, Config{ContainerPrefix: "dev"})
+
+		purged, err := manager.PurgeTrash(context.Background(), 7*24*time.Hour)
+		require.NoError(t, err)
+		require.Len(t, purged, 1)
+		assert.Equal(t, "dev-gamma", purged[0].Name)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("restore copies volumes back and recreates container", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		t.Setenv("XDG_STATE_HOME", t.TempDir())
+		labels := map[string]string{LabelOwner: "alice", LabelWorktree: "/src/beta"}
+		require.NoError(t, trashRecordsFile().put(trashKey("", "dev-beta", time.Unix(recent, 0)), trashRecord{Labels: labels}))
+		m := new(MockPodmanClient)
+		m.On("ListVolumes", mock.Anything).Return(volumes, nil)
+		m.On("ContainerExists", mock.Anything, "dev-beta").Return(false, nil)
+		for _, suffix := range []string{"-home", "-workspace"} {
+			trashName := trashVolumeName("dev-beta"+suffix, time.Unix(recent, 0))
+			m.On("CopyVolume", mock.Anything, trashName, "dev-beta"+suffix).Return(nil)
+			m.On("RemoveVolume", mock.Anything, trashName).Return(nil)
+		}
+		m.On("FindAvailablePort", 2200).Return(2200, nil)
+		m.On("FindAvailablePort", 3000).Return(3000, nil)
+		m.On("CreateContainer", mock.Anything, mock.MatchedBy(func(config ContainerConfig) bool {
+			return config.Name == "dev-beta" && config.Labels[LabelOwner] == "alice" &&
+				config.Labels[LabelWorktree] == "/src/beta"
+		})).Return(&Container{Name: "dev-beta"}, nil)
+		m.On("StartContainer", mock.Anything, "dev-beta").Return(nil)
+		m.On("ExecContainer", mock.Anything, "dev-beta", mock.Anything).Return(nil)
+
+		manager := NewManager(m, Config{
+			ContainerPrefix: "dev",
+			SSHPortStart:    2200,
+			WebPortStart:    3000,
+			ContainerUser:   "dev",
+		})
+
+		cont, err := manager.RestoreContainer(context.Background(), "beta")
+		require.NoError(t, err)
+		assert.Equal(t, 2200, cont.SSHPort)
+		m.AssertExpectations(t)
+
+		_, ok, err := trashRecordsFile().get(trashKey("", "dev-beta", time.Unix(recent, 0)))
+		require.NoError(t, err)
+		assert.False(t, ok, "record is dropped once restored")
+	})
+}
+
+func TestManager_TrashContainer(t *testing.T) {
+	labels := map[string]string{LabelOwner: "alice"}
+	trashed := func(volume string) any {
+		return mock.MatchedBy(func(name string) bool {
+			source, _, ok := parseTrashVolumeName(name)
+			return ok && strings.HasSuffix(name, volume) && source == "dev-alpha"
+		})
+	}
+
+	t.Run("volumes are copied before the container is removed", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		t.Setenv("XDG_STATE_HOME", t.TempDir())
+		var calls []string
+		record := func(call string) func(mock.Arguments) {
+			return func(mock.Arguments) { calls = append(calls, call) }
+		}
+		m := new(MockPodmanClient)
+		m.On("GetContainerInfo", mock.Anything, "dev-alpha").
+			Return(&Container{Name: "dev-alpha", Status: "running", Labels: labels}, nil)
+		m.On("StopContainer", mock.Anything, "dev-alpha").Return(nil).Run(record("stop"))
+		for _, volume := range []string{"dev-alpha-home", "dev-alpha-workspace"} {
+			m.On("CopyVolume", mock.Anything, volume, trashed(volume)).Return(nil).Run(record("copy " + volume))
+			m.On("RemoveVolume", mock.Anything, volume).Return(nil).Run(record("remove " + volume))
+		}
+		m.On("ContainerExists", mock.Anything, "dev-alpha").Return(true, nil)
+		m.On("RemoveContainer", mock.Anything, "dev-alpha", false).Return(nil).Run(record("remove container"))
+
+		manager := NewManager(m, Config{ContainerPrefix: "dev"})
+		require.NoError(t, manager.TrashContainer(context.Background(), "alpha"))
+		assert.Equal(t, []string{
+			"stop",
+			"copy dev-alpha-home",
+			"copy dev-alpha-workspace",
+			"remove container",
+			"remove dev-alpha-home",
+			"remove dev-alpha-workspace",
+		}, calls)
+
+		records, err := trashRecordsFile().read()
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		for _, r := range records {
+			assert.Equal(t, labels, r.Labels)
+		}
+	})
+
+	t.Run("a failed copy keeps the container", func(t *testing.T) {
+		t.Setenv("XDG_STATE_HOME", t.TempDir())
+		m := new(MockPodmanClient)
+		m.On("GetContainerInfo", mock.Anything, "dev-alpha").
+			Return(&Container{Name: "dev-alpha", Status: "running", Labels: labels}, nil)
+		m.On("StopContainer", mock.Anything, "dev-alpha").Return(nil)
+		m.On("CopyVolume", mock.Anything, "dev-alpha-home", trashed("dev-alpha-home")).Return(nil)
+		m.On("CopyVolume", mock.Anything, "dev-alpha-workspace", trashed("dev-alpha-workspace")).
+			Return(errors.New("disk full"))
+		m.On("RemoveVolume", mock.Anything, trashed("dev-alpha-home")).Return(nil)
+		m.On("RemoveVolume", mock.Anything, trashed("dev-alpha-workspace")).Return(nil)
+		m.On("StartContainer", mock.Anything, "dev-alpha").Return(nil)
+
+		manager := NewManager(m, Config{ContainerPrefix: "dev"})
+		err := manager.TrashContainer(context.Background(), "alpha")
+		assert.ErrorContains(t, err, "container kept")
+		m.AssertExpectations(t)
+		m.AssertNotCalled(t, "RemoveContainer", mock.Anything, mock.Anything, mock.Anything)
+		m.AssertNotCalled(t, "RemoveVolume", mock.Anything, "dev-alpha-home")
+	})
+}
@@ -2,6 +2,7 @@ package container
 
 import (
 	"context"
+	"io"
 	"time"
 )
 
@@ -13,6 +14,16 @@ type Container struct {
 	WebPort   int
 	CreatedAt time.Time
 	Labels    map[string]string
+	Image     string
+	Health    string // Healthcheck status (starting, healthy, unhealthy); empty without a healthcheck
+
+	// Set by GetContainerInfo, from inspect
+	StartedAt     time.Time // Last start; zero if the container never ran
+	FinishedAt    time.Time // Last stop; zero if it never stopped
+	RestartCount  int       // Restarts by podman's restart policy
+	ExitCode      int       // Exit code of the last stop
+	OOMKilled     bool      // The kernel killed it for running out of memory
+	StoppedByUser bool      // The last stop was requested, e.g. by l8s stop
 }
 
 // ContainerConfig holds configuration for creating a container
@@ -24,8 +35,14 @@ type ContainerConfig struct {
 	BaseImage     string
 	ContainerUser string
 	Labels        map[string]string
-	AudioEnabled  bool // Whether audio tunneling is enabled
-	AudioPort     int  // Port for audio tunnel (default 4713)
+	AudioEnabled  bool    // Whether audio tunneling is enabled
+	AudioPort     int     // Port for audio tunnel (default 4713)
+	VolumeName    string  // Base name for named volumes (defaults to Name)
+	Memory        int64   // Memory limit in bytes (0 for unlimited)
+	CPUs          float64 // CPU limit (0 for unlimited)
+	Mounts        []Mount // Host directories bind-mounted into the container
+	Runtime       RuntimeOptions
+	Env           map[string]string // Extra environment variables, e.g. proxy settings
 }
 
 // PodmanClient defines the interface for Podman operations
@@ -37,19 +54,36 @@ type PodmanClient interface {
 	RemoveContainer(ctx context.Context, name string, removeVolumes bool) error
 	ListContainers(ctx context.Context) ([]*Container, error)
 	GetContainerInfo(ctx context.Context, name string) (*Container, error)
+	InspectContainer(ctx context.Context, name string) ([]byte, error)
 	FindAvailablePort(startPort int) (int, error)
 	ExecContainer(ctx context.Context, name string, cmd []string) error
+	ExecContainerOutput(ctx context.Context, name string, cmd []string) (string, error)
 	ExecContainerWithInput(ctx context.Context, name string, cmd []string, input string) error
+	ExecContainerStream(ctx context.Context, name string, cmd []string, stdout, stderr io.Writer) error
 	CopyToContainer(ctx context.Context, name string, src, dst string) error
+	RenameContainer(ctx context.Context, name, newName string) error
+	CopyVolume(ctx context.Context, src, dst string) error
+	RemoveVolume(ctx context.Context, name string) error
+	ExportVolume(ctx context.Context, name string, w io.Writer) error
+	ListVolumes(ctx context.Context) ([]string, error)
+	DiffContainer(ctx context.Context, name string) ([]FileChange, error)
+	RunImage(ctx context.Context, image string, cmd []string) (string, error)
+	DescribeContainer(ctx context.Context, name string) (*ForeignContainer, error)
+	CommitContainer(ctx context.Context, name, image string) error
+	RemoveImage(ctx context.Context, image string) error
+	DiskUsage(ctx context.Context) (*DiskUsage, error)
+	Version(ctx context.Context) (*RemoteVersion, error)
 }
 
 // Config holds configuration for the container manager
 type Config struct {
 	SSHPortStart int
+	SSHPortRanges [][2]int // Ranges SSH ports are allocated from, in order; empty allocates from SSHPortStart
 	WebPortStart int
 	AudioEnabled bool
 	AudioPort    int
 	BaseImage        string
+	BaseFlavor       string // Containerfile flavor for 'l8s build'
 	ContainerPrefix  string
 	ContainerUser    string
 	DotfilesPath     string
@@ -57,12 +91,90 @@ type Config struct {
 	CAPublicKeyPath  string
 	KnownHostsPath   string
 	RemoteHost       string
+	ProxyJump        string // ssh -J destination the remote host is reached through
 	GitHubToken      string
+	Memory           int64   // Per-container memory limit in bytes
+	CPUs             float64 // Per-container CPU limit
+	Mounts           []Mount // Host directories bind-mounted into new containers
+	Runtime          RuntimeOptions
+	Quota            Quota
+	SSHGuard         SSHGuard
+	ExecEnv          []string // Variables sshd accepts from clients, as in AcceptEnv
+	PushGuard        PushGuard
+	Proxy            Proxy
+	ImageVariants    map[string]ImageVariant // By name, from image_variants
+	PersistPackages  bool                    // Record ad-hoc package installs and replay them after rebuilds
+	Simulated        bool                    // Fake runtime: no image to build and no sshd to wait for
+}
+
+// SSHGuard holds sshd login rate limits (zero values keep sshd's defaults)
+type SSHGuard struct {
+	MaxAuthTries   int
+	LoginGraceTime string
+	MaxStartups    string
+}
+
+// PushGuard configures the pre-push hook of container repositories
+type PushGuard struct {
+	Disabled       bool
+	Protected      []string // Branch globs no push may update
+	BlockedRemotes []string // Remotes no push may go to
+}
+
+// Proxy is the HTTP proxy configured in containers (empty means none)
+type Proxy struct {
+	HTTP    string
+	HTTPS   string
+	NoProxy string
+}
+
+// Quota limits the containers l8s may create on a connection (zero means unlimited)
+type Quota struct {
+	MaxContainers int
+	MaxMemory     int64 // Bytes, summed over per-container limits
+	MaxCPUs       float64
+	PortBudget    int // SSH ports usable, counted from the start of the SSH port ranges
 }
 
 // Labels used for container metadata
 const (
-	LabelManaged  = "l8s.managed"
-	LabelSSHPort  = "l8s.ssh.port"
-	LabelWebPort  = "l8s.web.port"
+	LabelManaged   = "l8s.managed"
+	LabelSSHPort   = "l8s.ssh.port"
+	LabelWebPort   = "l8s.web.port"
+	LabelProtected = "l8s.protected"
+	LabelNote      = "l8s.note"
+	LabelOwner     = "l8s.owner"     // Local username of the creator
+	LabelOwnerKey  = "l8s.owner.key" // SHA256 fingerprint of the creator's SSH key
+	LabelMemory    = "l8s.memory"    // Memory limit in bytes
+	LabelCPUs      = "l8s.cpus"      // CPU limit
+	LabelGroup     = "l8s.group"     // Group managed with 'l8s group'
+	LabelTicket    = "l8s.ticket"    // Ticket ID from 'l8s create --ticket'
+	LabelSubdir    = "l8s.subdir"    // Monorepo subdirectory from 'l8s create --subdir'
+	LabelWorktree  = "l8s.worktree"  // Host path of the worktree the container was created from
+	LabelOrigin    = "l8s.origin"    // Credential mode of the mirrored origin remote: agent or token
+	LabelGitName   = "l8s.git-name"  // user.name override from the repository's .l8s.yaml
+	LabelGitEmail  = "l8s.git-email" // user.email override from the repository's .l8s.yaml
+	LabelSigning   = "l8s.signing"   // Commit signing mode: agent or key
+	LabelSignKey   = "l8s.sign-key"  // Public key commits are signed with
+	LabelMounts    = "l8s.mounts"    // Host mounts as source:target[:ro] entries
+	LabelPackages  = "l8s.packages"  // Packages from .l8s.yaml as installer=spec... entries, ';' separated
+	LabelToolchain = "l8s.toolchain" // Toolchain manager from .l8s.yaml, e.g. nix or devbox
+	LabelImageVariant = "l8s.image-variant" // Image variant from 'l8s create --image-variant'
+	LabelShmSize   = "l8s.shm-size"  // /dev/shm size in bytes
+	LabelTmpfs     = "l8s.tmpfs"     // tmpfs mounts as path[:bytes] entries
+	LabelUlimits   = "l8s.ulimits"   // Resource limits as name=soft:hard entries
+	LabelSysctls   = "l8s.sysctls"   // Kernel parameters as key=value entries
+	LabelCapAdd    = "l8s.cap-add"   // Added capabilities, comma separated
+	LabelSeccomp   = "l8s.seccomp"   // "unconfined" when the seccomp filter is off
+	LabelSELinux   = "l8s.selinux"   // SELinux label options, ';' separated
+	LabelNested    = "l8s.nested"    // "true" when podman can run inside the container
+	LabelSystemd   = "l8s.systemd"   // "true" when systemd is PID 1
+	LabelFail2ban  = "l8s.fail2ban"  // "true" when fail2ban guards sshd
+	LabelPorts     = "l8s.ports"     // Published ports as host:container/protocol entries
+	LabelFlavor    = "l8s.flavor"    // Image label: distribution the image was built from
+	LabelAdoptedFrom  = "l8s.adopted-from"  // Container taken over with 'l8s adopt'
+	LabelAdoptedImage = "l8s.adopted-image" // Image committed by 'l8s adopt'; rebuilds start from it
+
+	// LabelUserPrefix namespaces labels set with 'l8s label set'
+	LabelUserPrefix = "l8s.label."
 )
\ No newline at end of file
@@ -0,0 +1,64 @@
+package container
+
+import (
+	"fmt"
+	"os"
+
+	"l8s/pkg/logging"
+)
+
+// ImageVariant is an image built from the base Containerfile with a fragment appended
+type ImageVariant struct {
+	Image         string // Tag the variant is built as
+	Containerfile string // Path of the fragment on this machine
+}
+
+// SetImageVariant selects the image variant the next build produces. The CLI
+// checks the name is configured.
+func (m *Manager) SetImageVariant(name string) {
+	m.imageVariant = name
+}
+
+// imageFor returns the image of a variant, or the base image for none. A
+// variant that is no longer configured, e.g. on a rebuild, falls back to the
+// base image.
+func (m *Manager) imageFor(variant string) string {
+	if variant == "" {
+		return m.config.BaseImage
+	}
+	v, ok := m.config.ImageVariants[variant]
+	if !ok {
+		m.logger.Warn("image variant is not configured, using the base image",
+			logging.WithField("variant", variant),
+			logging.WithField("image", m.config.BaseImage))
+		return m.config.BaseImage
+	}
+	return v.Image
+}
+
+// imageForLabels returns the image a container with the given labels is
+// recreated from: the image committed when it was adopted, or its variant's
+func (m *Manager) imageForLabels(labels map[string]string) string {
+	if image := labels[LabelAdoptedImage]; image != "" {
+		return image
+	}
+	return m.imageFor(labels[LabelImageVariant])
+}
+
+// appendFragment adds a variant's Containerfile fragment to the end of the
+// extracted base Containerfile, where it builds on the finished base image
+func appendFragment(containerfilePath, fragmentPath, variant string) error {
+	fragment, err := os.ReadFile(fragmentPath)
+	if err != nil {
+		return fmt.Errorf("failed to read Containerfile fragment for variant '%s': %w", variant, err)
+	}
+	file, err := os.OpenFile(containerfilePath, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open Containerfile: %w", err)
+	}
+	defer file.Close()
+	if _, err := fmt.Fprintf(file, "\n# Image variant: %s\n%s\n", variant, fragment); err != nil {
+		return fmt.Errorf("failed to append Containerfile fragment: %w", err)
+	}
+	return nil
+}
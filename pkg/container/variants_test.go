@@ -0,0 +1,37 @@
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageFor(t *testing.T) {
+	manager := NewManager(new(MockPodmanClient), Config{
+		BaseImage:     "localhost/l8s-fedora:latest",
+		ImageVariants: map[string]ImageVariant{"ml": {Image: "localhost/l8s-fedora-ml:latest"}},
+	})
+	assert.Equal(t, "localhost/l8s-fedora:latest", manager.imageFor(""))
+	assert.Equal(t, "localhost/l8s-fedora-ml:latest", manager.imageFor("ml"))
+	// A variant removed from the config falls back to the base image
+	assert.Equal(t, "localhost/l8s-fedora:latest", manager.imageFor("web"))
+}
+
+func TestAppendFragment(t *testing.T) {
+	dir := t.TempDir()
+	containerfile := filepath.Join(dir, "Containerfile")
+	fragment := filepath.Join(dir, "ml.Containerfile")
+	require.NoError(t, os.WriteFile(containerfile, []byte("FROM fedora\nCMD [\"sshd\"]\n"), 0644))
+	require.NoError(t, os.WriteFile(fragment, []byte("RUN dnf install -y python3-torch"), 0644))
+
+	require.NoError(t, appendFragment(containerfile, fragment, "ml"))
+	content, err := os.ReadFile(containerfile)
+	require.NoError(t, err)
+	assert.Equal(t, "FROM fedora\nCMD [\"sshd\"]\n\n# Image variant: ml\nRUN dnf install -y python3-torch\n", string(content))
+
+	err = appendFragment(containerfile, filepath.Join(dir, "missing"), "web")
+	assert.ErrorContains(t, err, "variant 'web'")
+}
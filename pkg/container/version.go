@@ -0,0 +1,49 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Podman major versions l8s is tested against. Others may work, but
+// mismatched bindings tend to fail with unhelpful errors.
+const (
+	MinPodmanMajor = 4
+	MaxPodmanMajor = 5
+)
+
+// RemoteVersion describes the Podman service on the remote host
+type RemoteVersion struct {
+	Podman        string // Podman version
+	APIVersion    string // Libpod API version it serves
+	MinAPIVersion string // Oldest API version l8s's bindings accept
+	OSArch        string
+}
+
+// Warning explains why the remote Podman may not work with l8s, or returns
+// "" when its major version is in the tested range
+func (v *RemoteVersion) Warning() string {
+	return PodmanVersionWarning(v.Podman)
+}
+
+// PodmanVersionWarning explains why a Podman version is outside the range
+// l8s is tested against, or returns "" when it is inside it
+func PodmanVersionWarning(version string) string {
+	majorStr, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	major, err := strconv.Atoi(majorStr)
+	if err != nil {
+		return fmt.Sprintf("unrecognized remote Podman version '%s'", version)
+	}
+	if major < MinPodmanMajor || major > MaxPodmanMajor {
+		return fmt.Sprintf("remote Podman %s is outside the versions l8s is tested with (%d.x-%d.x); some commands may fail",
+			version, MinPodmanMajor, MaxPodmanMajor)
+	}
+	return ""
+}
+
+// RemoteVersion reports the Podman and API versions of the remote host
+func (m *Manager) RemoteVersion(ctx context.Context) (*RemoteVersion, error) {
+	return m.client.Version(ctx)
+}
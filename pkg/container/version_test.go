@@ -0,0 +1,19 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPodmanVersionWarning(t *testing.T) {
+	for _, version := range []string{"4.9.4", "5.2.1", "v5.0.0"} {
+		assert.Empty(t, PodmanVersionWarning(version), version)
+	}
+	assert.Contains(t, PodmanVersionWarning("3.4.4"), "outside the versions l8s is tested with (4.x-5.x)")
+	assert.Contains(t, PodmanVersionWarning("6.0.0"), "outside the versions")
+	assert.Contains(t, PodmanVersionWarning(""), "unrecognized")
+
+	v := &RemoteVersion{Podman: "5.4.0"}
+	assert.Empty(t, v.Warning())
+}
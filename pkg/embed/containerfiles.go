@@ -5,18 +5,95 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 //go:embed containers/Containerfile
 var Containerfile string
 
+//go:embed containers/Containerfile.debian
+var ContainerfileDebian string
+
+//go:embed containers/Containerfile.ubuntu
+var ContainerfileUbuntu string
+
 //go:embed containers/Containerfile.test
 var ContainerfileTest string
 
+// DefaultFlavor is the distribution used when base_flavor is not set
+const DefaultFlavor = "fedora"
+
+// Flavors lists the distributions the base image can be built from
+var Flavors = []string{"fedora", "debian", "ubuntu"}
+
+// ContainerfileFor returns the embedded Containerfile for a distribution flavor
+func ContainerfileFor(flavor string) (string, error) {
+	switch flavor {
+	case "", "fedora":
+		return Containerfile, nil
+	case "debian":
+		return ContainerfileDebian, nil
+	case "ubuntu":
+		return ContainerfileUbuntu, nil
+	}
+	return "", fmt.Errorf("unknown base flavor '%s' (available: %s)", flavor, strings.Join(Flavors, ", "))
+}
+
+// IdleAgent records SSH activity inside containers; it is copied into the
+// image build context next to the Containerfile
+//
+//go:embed containers/l8s-idle-agent
+var IdleAgent string
+
+// IdleAgentFile is the name of the idle agent in the build context
+const IdleAgentFile = "l8s-idle-agent"
+
+// NotifyClient sends desktop notifications from inside containers through
+// 'l8s notify'; it is copied into the build context like the idle agent
+//
+//go:embed containers/l8s-notify
+var NotifyClient string
+
+// NotifyClientFile is the name of the notify client in the build context
+const NotifyClientFile = "l8s-notify"
+
+// BuildContextFiles lists the files written next to the Containerfile
+var BuildContextFiles = []string{IdleAgentFile, NotifyClientFile}
+
 // ExtractContainerfile writes the embedded Containerfile to a temporary file
 // and returns the path to that file. The caller is responsible for cleaning up.
+// The idle agent and notify client are written alongside it so the
+// Containerfile can COPY them.
 func ExtractContainerfile() (string, error) {
-	return extractToTemp(Containerfile, "Containerfile")
+	return ExtractContainerfileFlavor(DefaultFlavor)
+}
+
+// ExtractContainerfileFlavor is ExtractContainerfile for a distribution flavor
+func ExtractContainerfileFlavor(flavor string) (string, error) {
+	if err := verifyForUse(); err != nil {
+		return "", err
+	}
+	content, err := ContainerfileFor(flavor)
+	if err != nil {
+		return "", err
+	}
+	path, err := extractToTemp(content, "Containerfile")
+	if err != nil {
+		return "", err
+	}
+
+	agentPath := filepath.Join(filepath.Dir(path), IdleAgentFile)
+	if err := os.WriteFile(agentPath, []byte(IdleAgent), 0755); err != nil {
+		os.RemoveAll(filepath.Dir(path))
+		return "", fmt.Errorf("failed to write idle agent: %w", err)
+	}
+	clientPath := filepath.Join(filepath.Dir(path), NotifyClientFile)
+	if err := os.WriteFile(clientPath, []byte(NotifyClient), 0755); err != nil {
+		os.RemoveAll(filepath.Dir(path))
+		return "", fmt.Errorf("failed to write notify client: %w", err)
+	}
+
+	return path, nil
 }
 
 // ExtractContainerfileTest writes the embedded test Containerfile to a temporary file
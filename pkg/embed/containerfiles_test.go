@@ -64,6 +64,34 @@ func TestExtractContainerfile(t *testing.T) {
 		}
 	})
 	
+	t.Run("ExtractContainerfile writes idle agent into build context", func(t *testing.T) {
+		path, err := ExtractContainerfile()
+		if err != nil {
+			t.Fatalf("ExtractContainerfile failed: %v", err)
+		}
+		defer os.RemoveAll(filepath.Dir(path))
+
+		info, err := os.Stat(filepath.Join(filepath.Dir(path), IdleAgentFile))
+		if err != nil {
+			t.Fatalf("Idle agent not extracted: %v", err)
+		}
+		if info.Mode()&0100 == 0 {
+			t.Error("Idle agent is not executable")
+		}
+		if !strings.Contains(Containerfile, "COPY "+IdleAgentFile) {
+			t.Error("Containerfile doesn't install the idle agent")
+		}
+		for _, file := range BuildContextFiles {
+			info, err := os.Stat(filepath.Join(filepath.Dir(path), file))
+			if err != nil {
+				t.Fatalf("%s not extracted: %v", file, err)
+			}
+			if info.Mode()&0100 == 0 {
+				t.Errorf("%s is not executable", file)
+			}
+		}
+	})
+
 	t.Run("ExtractContainerfileTest creates temp file", func(t *testing.T) {
 		path, err := ExtractContainerfileTest()
 		if err != nil {
@@ -109,4 +137,30 @@ func TestExtractContainerfile(t *testing.T) {
 			t.Error("Multiple extractions should create different temp directories")
 		}
 	})
-}
\ No newline at end of file
+}
+func TestContainerfileFlavors(t *testing.T) {
+	bases := map[string]string{
+		"fedora": "FROM fedora:latest",
+		"debian": "FROM debian:stable",
+		"ubuntu": "FROM ubuntu:24.04",
+	}
+	for _, flavor := range Flavors {
+		content, err := ContainerfileFor(flavor)
+		if err != nil {
+			t.Fatalf("ContainerfileFor(%q) failed: %v", flavor, err)
+		}
+		if !strings.HasPrefix(content, bases[flavor]) {
+			t.Errorf("%s Containerfile doesn't start with %q", flavor, bases[flavor])
+		}
+		// Every flavor must provide what l8s relies on at runtime
+		for _, want := range []string{"CONTAINER_USER", "AllowUsers", "l8s-idle-agent", "l8s-notify", "/workspace", "CACHEBUST"} {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s Containerfile doesn't contain %q", flavor, want)
+			}
+		}
+	}
+
+	if _, err := ContainerfileFor("arch"); err == nil {
+		t.Error("expected an error for an unknown flavor")
+	}
+}
@@ -0,0 +1,11 @@
+package embed
+
+import (
+	_ "embed"
+)
+
+// Dashboard is the web UI served by 'l8s serve'. It is a single page that
+// talks to the control API with the token the user enters.
+//
+//go:embed dashboard/index.html
+var Dashboard []byte
@@ -8,7 +8,11 @@ import (
 //go:embed all:dotfiles
 var dotfilesFS embed.FS
 
-// GetDotfilesFS returns the embedded dotfiles filesystem
+// GetDotfilesFS returns the embedded dotfiles filesystem, after checking
+// the release signature
 func GetDotfilesFS() (fs.FS, error) {
+	if err := verifyForUse(); err != nil {
+		return nil, err
+	}
 	return fs.Sub(dotfilesFS, "dotfiles")
 }
\ No newline at end of file
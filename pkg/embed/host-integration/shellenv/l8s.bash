@@ -0,0 +1,40 @@
+# l8s shell functions for bash
+# Load with: eval "$(l8s shellenv bash)"
+
+# l8s cd <name> changes to the worktree of a container; everything else runs l8s
+l8s() {
+    if [[ "$1" == "cd" ]]; then
+        if [[ $# -ne 2 ]]; then
+            echo "usage: l8s cd <name>" >&2
+            return 2
+        fi
+        local dir
+        dir="$(command l8s which "$2")" || return
+        builtin cd -- "$dir"
+        return
+    fi
+    command l8s "$@"
+}
+
+# Container names without the prefix
+_l8s_containers() {
+    command l8s list --quiet 2>/dev/null | sed "s/^${_l8s_prefix:-dev}-//"
+}
+
+# Commands, subcommands and flags come from l8s itself; container names are
+# added for the commands that take one
+_l8s_complete() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    if [[ $COMP_CWORD -eq 2 && "$cur" != -* ]]; then
+        case "${COMP_WORDS[1]}" in
+            cd|which|start|stop|open|info|inspect|drift|history|clone|disown|protect|unprotect|note|mount|umount|scan|tail|label|security)
+                COMPREPLY=($(compgen -W "$(_l8s_containers)" -- "$cur"))
+                return
+                ;;
+        esac
+    fi
+    local IFS=$'\n'
+    COMPREPLY=($(command l8s __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null | grep -v '^:' | cut -f1))
+}
+
+complete -o default -F _l8s_complete l8s
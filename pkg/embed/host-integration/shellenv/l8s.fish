@@ -0,0 +1,38 @@
+# l8s shell functions for fish
+# Load with: l8s shellenv fish | source
+
+# l8s cd <name> changes to the worktree of a container; everything else runs l8s
+function l8s --description 'Manage l8s containers; l8s cd <name> changes to a container worktree'
+    if test "$argv[1]" = cd
+        if test (count $argv) -ne 2
+            echo "usage: l8s cd <name>" >&2
+            return 2
+        end
+        set -l dir (command l8s which $argv[2]); or return
+        builtin cd -- $dir
+        return
+    end
+    command l8s $argv
+end
+
+# Container names without the prefix
+function __l8s_containers
+    command l8s list --quiet 2>/dev/null | string replace -r -- "^$_l8s_prefix-" ''
+end
+
+# True when completing the argument right after one of the given commands
+function __l8s_container_arg
+    set -l tokens (commandline -opc)
+    test (count $tokens) -eq 2; and contains -- $tokens[2] $argv
+end
+
+# Commands, subcommands and flags come from l8s itself
+function __l8s_complete
+    set -l tokens (commandline -opc)
+    set -e tokens[1]
+    command l8s __complete $tokens (commandline -ct) 2>/dev/null | string match -v -r '^:'
+end
+
+complete -c l8s -e
+complete -c l8s -n '__l8s_container_arg cd which start stop open info inspect drift history clone disown protect unprotect note mount umount scan tail label security' -f -a '(__l8s_containers)'
+complete -c l8s -n 'not __l8s_container_arg cd which start stop open info inspect drift history clone disown protect unprotect note mount umount scan tail label security' -a '(__l8s_complete)'
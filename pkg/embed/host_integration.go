@@ -12,11 +12,41 @@ import (
 //go:embed all:host-integration
 var hostIntegrationFS embed.FS
 
-// GetHostIntegrationFS returns the embedded host integration filesystem
+// GetHostIntegrationFS returns the embedded host integration filesystem,
+// after checking the release signature
 func GetHostIntegrationFS() (fs.FS, error) {
+	if err := verifyForUse(); err != nil {
+		return nil, err
+	}
 	return fs.Sub(hostIntegrationFS, "host-integration")
 }
 
+// ShellEnv returns the shell functions and completion l8s shellenv prints for
+// shell (bash, zsh or fish). The zsh completion is the Oh My Zsh plugin's.
+func ShellEnv(shell string) ([]byte, error) {
+	switch shell {
+	case "bash", "zsh", "fish":
+	default:
+		return nil, fmt.Errorf("unsupported shell %q; use bash, zsh or fish", shell)
+	}
+	hostFS, err := GetHostIntegrationFS()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get host integration filesystem: %w", err)
+	}
+	script, err := fs.ReadFile(hostFS, "shellenv/l8s."+shell)
+	if err != nil {
+		return nil, err
+	}
+	if shell == "zsh" {
+		completion, err := fs.ReadFile(hostFS, "oh-my-zsh/l8s/_l8s")
+		if err != nil {
+			return nil, err
+		}
+		script = append(script, completion...)
+	}
+	return script, nil
+}
+
 // ExtractZSHPlugin extracts the ZSH plugin files to the specified directory
 func ExtractZSHPlugin(destDir string) error {
 	hostFS, err := GetHostIntegrationFS()
@@ -0,0 +1,72 @@
+package embed
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShellEnv(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		script, err := ShellEnv(shell)
+		if err != nil {
+			t.Fatalf("ShellEnv(%q) error = %v", shell, err)
+		}
+		if !strings.Contains(string(script), "command l8s which") {
+			t.Errorf("ShellEnv(%q) does not define l8s cd", shell)
+		}
+	}
+
+	zsh, _ := ShellEnv("zsh")
+	if !strings.Contains(string(zsh), "compdef _l8s l8s") {
+		t.Error("zsh shellenv does not register the completion")
+	}
+
+	if _, err := ShellEnv("tcsh"); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
+
+func TestShellEnvBashCd(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not installed")
+	}
+	script, err := ShellEnv("bash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A stand-in l8s that knows one container
+	bin := t.TempDir()
+	worktree := t.TempDir()
+	fake := "#!/bin/sh\nif [ \"$1\" = which ] && [ \"$2\" = api ]; then echo " + worktree + "; exit 0; fi\necho \"unknown container $2\" >&2\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(bin, "l8s"), []byte(fake), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(commands string) (string, error) {
+		cmd := exec.Command(bash, "-c", string(script)+"\n"+commands)
+		cmd.Env = append(os.Environ(), "PATH="+bin+":"+os.Getenv("PATH"))
+		out, err := cmd.CombinedOutput()
+		return strings.TrimSpace(string(out)), err
+	}
+
+	out, err := run("l8s cd api && pwd")
+	if err != nil {
+		t.Fatalf("l8s cd failed: %v: %s", err, out)
+	}
+	if want, _ := filepath.EvalSymlinks(worktree); out != worktree && out != want {
+		t.Errorf("l8s cd api: pwd = %q, want %q", out, worktree)
+	}
+
+	out, err = run("cd / && l8s cd web; echo \"$? $PWD\"")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(out, "1 /") {
+		t.Errorf("l8s cd of an unknown container should fail and stay put, got %q", out)
+	}
+}
@@ -0,0 +1,148 @@
+package embed
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// containersFS holds the Containerfiles and their build context, for listing
+// and checksums; builds use the variables in containerfiles.go
+//
+//go:embed containers
+var containersFS embed.FS
+
+// ReleaseKey is the base64 ed25519 public key releases are signed with. It is
+// pinned in source rather than set by the build, so a binary cannot vouch for
+// its own assets; 'make release' refuses a SIGNING_KEY that does not match.
+const ReleaseKey = ""
+
+// ManifestSignature is set with -ldflags by 'make release SIGNING_KEY=...' to
+// the base64 ed25519 signature of Manifest; development builds are unsigned
+var ManifestSignature = ""
+
+// ErrUnsigned is returned by Verify for builds without a signature, whose
+// embedded assets cannot be checked
+var ErrUnsigned = errors.New("embedded files are unsigned (development build)")
+
+// Asset is an embedded file, with its path under pkg/embed
+type Asset struct {
+	Path string
+	Data []byte
+}
+
+// Sum returns the hex sha256 of the asset
+func (a Asset) Sum() string {
+	return fmt.Sprintf("%x", sha256.Sum256(a.Data))
+}
+
+// Executable reports whether the asset is a script, which is written executable
+func (a Asset) Executable() bool {
+	return bytes.HasPrefix(a.Data, []byte("#!")) || path.Ext(a.Path) == ".sh"
+}
+
+// Assets returns every embedded file sorted by path: the Containerfiles and
+// their build context, the dotfiles copied into containers, the host
+// integration and the dashboard
+func Assets() ([]Asset, error) {
+	var assets []Asset
+	for _, root := range []struct {
+		fsys fs.FS
+		dir  string
+	}{
+		{containersFS, "containers"},
+		{dotfilesFS, "dotfiles"},
+		{hostIntegrationFS, "host-integration"},
+	} {
+		err := fs.WalkDir(root.fsys, root.dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			data, err := fs.ReadFile(root.fsys, p)
+			if err != nil {
+				return err
+			}
+			assets = append(assets, Asset{Path: p, Data: data})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read embedded %s: %w", root.dir, err)
+		}
+	}
+	assets = append(assets, Asset{Path: "dashboard/index.html", Data: Dashboard})
+	sort.Slice(assets, func(i, j int) bool { return assets[i].Path < assets[j].Path })
+	return assets, nil
+}
+
+// Manifest returns the checksums of assets in sha256sum's format, which is
+// what release builds sign
+func Manifest(assets []Asset) []byte {
+	var b bytes.Buffer
+	for _, a := range assets {
+		fmt.Fprintf(&b, "%s  %s\n", a.Sum(), a.Path)
+	}
+	return b.Bytes()
+}
+
+// Signed reports whether this build carries a signature for its assets
+func Signed() bool {
+	return ManifestSignature != ""
+}
+
+var (
+	verifyOnce sync.Once
+	verifyErr  error
+)
+
+// Verify checks the embedded assets against the release signature and the
+// pinned release key, once per run. Unsigned builds get ErrUnsigned.
+func Verify() error {
+	verifyOnce.Do(func() {
+		if !Signed() {
+			verifyErr = ErrUnsigned
+			return
+		}
+		assets, err := Assets()
+		if err != nil {
+			verifyErr = err
+			return
+		}
+		verifyErr = verifyManifest(Manifest(assets), ReleaseKey, ManifestSignature)
+	})
+	return verifyErr
+}
+
+// verifyForUse checks the embedded assets before they are used. Unsigned
+// development builds may use them; l8s version and l8s embed list report
+// them as unsigned.
+func verifyForUse() error {
+	if err := Verify(); err != nil && !errors.Is(err, ErrUnsigned) {
+		return err
+	}
+	return nil
+}
+
+// verifyManifest checks an ed25519 signature of manifest, both given in base64
+func verifyManifest(manifest []byte, key, signature string) error {
+	publicKey, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("embedded assets are signed but no valid release key is pinned in pkg/embed")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return fmt.Errorf("embedded asset signature is not base64: %w", err)
+	}
+	if !ed25519.Verify(publicKey, manifest, sig) {
+		return fmt.Errorf("embedded assets do not match their release signature; reinstall l8s from a release")
+	}
+	return nil
+}
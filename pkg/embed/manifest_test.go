@@ -0,0 +1,82 @@
+package embed
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAssets(t *testing.T) {
+	assets, err := Assets()
+	if err != nil {
+		t.Fatalf("Assets() error = %v", err)
+	}
+	paths := make(map[string]Asset, len(assets))
+	for i, a := range assets {
+		if i > 0 && assets[i-1].Path >= a.Path {
+			t.Errorf("assets not sorted: %s before %s", assets[i-1].Path, a.Path)
+		}
+		paths[a.Path] = a
+	}
+	for _, want := range []string{
+		"containers/Containerfile",
+		"containers/l8s-idle-agent",
+		"dotfiles/.zshrc",
+		"host-integration/oh-my-zsh/l8s/_l8s",
+		"dashboard/index.html",
+	} {
+		if _, ok := paths[want]; !ok {
+			t.Errorf("missing asset %s", want)
+		}
+	}
+	if string(paths["containers/Containerfile"].Data) != Containerfile {
+		t.Error("containers/Containerfile differs from the embedded Containerfile")
+	}
+	if !paths["containers/l8s-idle-agent"].Executable() {
+		t.Error("idle agent should be executable")
+	}
+	if paths["containers/Containerfile"].Executable() {
+		t.Error("Containerfile should not be executable")
+	}
+}
+
+func TestManifest(t *testing.T) {
+	manifest := string(Manifest([]Asset{{Path: "a/empty", Data: nil}, {Path: "b/file", Data: []byte("x")}}))
+	want := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  a/empty\n" +
+		"2d711642b726b04401627ca9fbac32f5c8530fb1903cc4db02258717921a4881  b/file\n"
+	if manifest != want {
+		t.Errorf("Manifest() = %q, want %q", manifest, want)
+	}
+}
+
+func TestVerifyManifest(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := []byte("abc  containers/Containerfile\n")
+	key := base64.StdEncoding.EncodeToString(publicKey)
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, manifest))
+
+	if err := verifyManifest(manifest, key, sig); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+	if err := verifyManifest([]byte("abd  containers/Containerfile\n"), key, sig); err == nil || !strings.Contains(err.Error(), "do not match") {
+		t.Errorf("tampered manifest: err = %v", err)
+	}
+	if err := verifyManifest(manifest, "", sig); err == nil {
+		t.Error("missing key accepted")
+	}
+}
+
+func TestVerifyUnsigned(t *testing.T) {
+	if err := Verify(); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("Verify() of an unsigned build = %v, want ErrUnsigned", err)
+	}
+	if err := verifyForUse(); err != nil {
+		t.Errorf("unsigned assets refused for use: %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package errors
+
+import (
+	stderrors "errors"
+)
+
+// ExitCoder is implemented by errors that carry a process exit status, such
+// as a failed task run by 'l8s ci create-ephemeral'
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// ExitCode returns the exit status l8s should exit with for err: 0 for nil,
+// the wrapped ExitCoder's status if there is one, and 1 otherwise
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var coder ExitCoder
+	if stderrors.As(err, &coder) && coder.ExitCode() > 0 {
+		return coder.ExitCode()
+	}
+	return 1
+}
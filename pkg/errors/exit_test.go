@@ -0,0 +1,29 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+)
+
+type codeError int
+
+func (e codeError) Error() string { return fmt.Sprintf("exit status %d", int(e)) }
+func (e codeError) ExitCode() int { return int(e) }
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{fmt.Errorf("plain failure"), 1},
+		{codeError(3), 3},
+		{fmt.Errorf("task failed: %w", codeError(42)), 42},
+		{codeError(-1), 1}, // killed by a signal
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
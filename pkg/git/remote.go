@@ -6,7 +6,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"l8s/pkg/logging"
 )
 
 // CloneRepository clones a git repository to the specified path
@@ -33,7 +36,7 @@ func CloneRepository(gitURL, branch, targetPath string) error {
 
 	// Execute git clone
 	cmd := exec.Command("git", args...)
-	output, err := cmd.CombinedOutput()
+	output, err := logging.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to clone repository: %w\nOutput: %s", err, string(output))
 	}
@@ -68,7 +71,7 @@ func AddRemote(repoPath, remoteName, remoteURL string) error {
 	// Add the remote
 	cmd := exec.Command("git", "remote", "add", remoteName, remoteURL)
 	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+	output, err := logging.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to add remote: %w\nOutput: %s", err, string(output))
 	}
@@ -100,7 +103,7 @@ func RemoveRemote(repoPath, remoteName string) error {
 	// Remove the remote
 	cmd := exec.Command("git", "remote", "remove", remoteName)
 	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+	output, err := logging.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to remove remote: %w\nOutput: %s", err, string(output))
 	}
@@ -108,6 +111,26 @@ func RemoveRemote(repoPath, remoteName string) error {
 	return nil
 }
 
+// SetRemoteURL points an existing remote at a new URL, keeping its
+// remote-tracking branches and upstream settings
+func SetRemoteURL(repoPath, remoteName, remoteURL string) error {
+	if remoteName == "" {
+		return fmt.Errorf("remote name is required")
+	}
+	if remoteURL == "" {
+		return fmt.Errorf("remote URL is required")
+	}
+
+	cmd := exec.Command("git", "remote", "set-url", remoteName, remoteURL)
+	cmd.Dir = repoPath
+	output, err := logging.CombinedOutput(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to set remote URL: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
 // SetUpstream sets the upstream branch for the current branch
 func SetUpstream(repoPath, branch, remoteName string) error {
 	// Check if repository exists
@@ -118,7 +141,7 @@ func SetUpstream(repoPath, branch, remoteName string) error {
 	// Check if branch exists locally
 	cmd := exec.Command("git", "show-ref", "--verify", fmt.Sprintf("refs/heads/%s", branch))
 	cmd.Dir = repoPath
-	if err := cmd.Run(); err != nil {
+	if err := logging.Run(cmd); err != nil {
 		return fmt.Errorf("branch '%s' does not exist", branch)
 	}
 
@@ -132,7 +155,7 @@ func SetUpstream(repoPath, branch, remoteName string) error {
 	for _, args := range configs {
 		cmd := exec.Command("git", args...)
 		cmd.Dir = repoPath
-		output, err := cmd.CombinedOutput()
+		output, err := logging.CombinedOutput(cmd)
 		if err != nil {
 			return fmt.Errorf("failed to set upstream: %w\nOutput: %s", err, string(output))
 		}
@@ -151,7 +174,7 @@ func GetCurrentBranch(repoPath string) (string, error) {
 	// Get current branch
 	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
 	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+	output, err := logging.CombinedOutput(cmd)
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w\nOutput: %s", err, string(output))
 	}
@@ -174,7 +197,7 @@ func ListRemotes(repoPath string) (map[string]string, error) {
 	// List remotes with URLs
 	cmd := exec.Command("git", "remote", "-v")
 	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+	output, err := logging.CombinedOutput(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list remotes: %w\nOutput: %s", err, string(output))
 	}
@@ -269,7 +292,7 @@ func ChangeUpstreamToOrigin(repoPath, branch string) error {
 	// Check if branch exists before trying to set upstream
 	cmd := exec.Command("git", "show-ref", "--verify", fmt.Sprintf("refs/heads/%s", branch))
 	cmd.Dir = repoPath
-	if err := cmd.Run(); err != nil {
+	if err := logging.Run(cmd); err != nil {
 		// Branch doesn't exist, nothing to do
 		return nil
 	}
@@ -311,7 +334,7 @@ func GetRepositoryRoot(path string) (string, error) {
 	}
 	cmd.Dir = absPath
 	
-	output, err := cmd.Output()
+	output, err := logging.Output(cmd)
 	if err != nil {
 		// Check if it's a command not found error or a legitimate "not a git repo" error
 		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
@@ -354,7 +377,7 @@ func PushBranch(repoPath, branch, remoteName string, force bool) error {
 	// Execute git push
 	cmd := exec.Command("git", args...)
 	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+	output, err := logging.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to push branch: %w\nOutput: %s", err, string(output))
 	}
@@ -362,6 +385,199 @@ func PushBranch(repoPath, branch, remoteName string, force bool) error {
 	return nil
 }
 
+// PushShallow pushes the last depth commits of a branch to a remote. The
+// history is cut in a temporary shallow clone, so the receiving repository
+// must have receive.shallowUpdate enabled.
+func PushShallow(repoPath, branch, remoteName string, depth int) error {
+	if depth < 1 {
+		return fmt.Errorf("shallow depth must be at least 1")
+	}
+	remotes, err := ListRemotes(repoPath)
+	if err != nil {
+		return err
+	}
+	remoteURL, exists := remotes[remoteName]
+	if !exists {
+		return fmt.Errorf("remote '%s' does not exist", remoteName)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "l8s-shallow-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// file:// is required for --depth to apply to a local clone. Windows
+	// paths such as C:/repo need a leading slash to be a URL path.
+	sourcePath := filepath.ToSlash(repoPath)
+	if !strings.HasPrefix(sourcePath, "/") {
+		sourcePath = "/" + sourcePath
+	}
+	source := (&url.URL{Scheme: "file", Path: sourcePath}).String()
+	cmd := exec.Command("git", "clone", "--quiet", "--bare", "--single-branch",
+		"--branch", branch, "--depth", strconv.Itoa(depth), source, tmpDir)
+	if output, err := logging.CombinedOutput(cmd); err != nil {
+		return fmt.Errorf("failed to create shallow copy: %w\nOutput: %s", err, string(output))
+	}
+
+	cmd = exec.Command("git", "push", remoteURL, fmt.Sprintf("%s:%s", branch, branch))
+	cmd.Dir = tmpDir
+	if output, err := logging.CombinedOutput(cmd); err != nil {
+		return fmt.Errorf("failed to push branch: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// ValidateBranchName checks that name is a valid branch name, as git
+// check-ref-format --branch does. Valid names may still contain shell
+// metacharacters, so quote them in commands.
+func ValidateBranchName(name string) error {
+	cmd := exec.Command("git", "check-ref-format", "--branch", name)
+	if output, err := logging.CombinedOutput(cmd); err != nil {
+		return fmt.Errorf("invalid branch name '%s': %s", name, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ResolveRef returns the commit SHA a branch, tag or revision points to
+func ResolveRef(repoPath, ref string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	cmd.Dir = repoPath
+	output, err := logging.Output(cmd)
+	if err != nil {
+		return "", fmt.Errorf("unknown revision '%s'", ref)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// PushRef pushes any revision to branch on a remote, replacing the branch if
+// it already exists
+func PushRef(repoPath, ref, remoteName, branch string) error {
+	cmd := exec.Command("git", "push", "--force", remoteName, fmt.Sprintf("%s:refs/heads/%s", ref, branch))
+	cmd.Dir = repoPath
+	if output, err := logging.CombinedOutput(cmd); err != nil {
+		return fmt.Errorf("failed to push %s: %w\nOutput: %s", ref, err, string(output))
+	}
+	return nil
+}
+
+// CreateBranch creates branch at startPoint without checking it out
+func CreateBranch(repoPath, branch, startPoint string) error {
+	cmd := exec.Command("git", "branch", branch, startPoint)
+	cmd.Dir = repoPath
+	if output, err := logging.CombinedOutput(cmd); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w\nOutput: %s", branch, err, string(output))
+	}
+	return nil
+}
+
+// Worktree is an entry of 'git worktree list'
+type Worktree struct {
+	Path   string
+	HEAD   string // Commit checked out
+	Branch string // Short branch name; empty when detached
+	Bare   bool
+}
+
+// ListWorktrees returns the main worktree and every linked worktree of the
+// repository containing repoPath
+func ListWorktrees(repoPath string) ([]Worktree, error) {
+	cmd := exec.Command("git", "worktree", "list", "--porcelain")
+	cmd.Dir = repoPath
+	output, err := logging.Output(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	return parseWorktrees(string(output)), nil
+}
+
+// parseWorktrees parses 'git worktree list --porcelain' output
+func parseWorktrees(output string) []Worktree {
+	var worktrees []Worktree
+	for _, block := range strings.Split(strings.TrimSpace(output), "\n\n") {
+		var wt Worktree
+		for _, line := range strings.Split(block, "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "worktree":
+				wt.Path = value
+			case "HEAD":
+				wt.HEAD = value
+			case "branch":
+				wt.Branch = strings.TrimPrefix(value, "refs/heads/")
+			case "bare":
+				wt.Bare = true
+			}
+		}
+		if wt.Path != "" {
+			worktrees = append(worktrees, wt)
+		}
+	}
+	return worktrees
+}
+
+// AheadBehind counts the commits reachable from a but not b (ahead) and from
+// b but not a (behind). Both must exist in the repository.
+func AheadBehind(repoPath, a, b string) (ahead, behind int, err error) {
+	cmd := exec.Command("git", "rev-list", "--left-right", "--count", a+"..."+b)
+	cmd.Dir = repoPath
+	output, err := logging.Output(cmd)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare %s and %s: %w", a, b, err)
+	}
+	if _, err := fmt.Sscan(string(output), &ahead, &behind); err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", output)
+	}
+	return ahead, behind, nil
+}
+
+// worktreeContainerFile pins a worktree to a container that is not named
+// after its path, such as one created with 'l8s create --ticket'. It is kept
+// in the worktree's own git directory so every worktree can have its own.
+const worktreeContainerFile = "l8s-container"
+
+// worktreeGitDir returns the git directory of the worktree at worktreePath
+func worktreeGitDir(worktreePath string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--absolute-git-dir")
+	cmd.Dir = worktreePath
+	output, err := logging.Output(cmd)
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository")
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// WorktreeContainer returns the container name (without prefix) pinned to a
+// worktree, or "" when the worktree uses its path-derived name
+func WorktreeContainer(worktreePath string) string {
+	dir, err := worktreeGitDir(worktreePath)
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(dir, worktreeContainerFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// SetWorktreeContainer pins a worktree to a container name (without prefix).
+// An empty name removes the pin.
+func SetWorktreeContainer(worktreePath, name string) error {
+	dir, err := worktreeGitDir(worktreePath)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, worktreeContainerFile)
+	if name == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(path, []byte(name+"\n"), 0644)
+}
+
 // InitRepository initializes a new git repository
 func InitRepository(repoPath string, allowPush bool, defaultBranch string) error {
 	// Create directory if it doesn't exist
@@ -372,7 +588,7 @@ func InitRepository(repoPath string, allowPush bool, defaultBranch string) error
 	// Initialize git repository
 	cmd := exec.Command("git", "init")
 	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+	output, err := logging.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to initialize repository: %w\nOutput: %s", err, string(output))
 	}
@@ -381,11 +597,11 @@ func InitRepository(repoPath string, allowPush bool, defaultBranch string) error
 	if defaultBranch != "" {
 		cmd = exec.Command("git", "config", "init.defaultBranch", defaultBranch)
 		cmd.Dir = repoPath
-		if err := cmd.Run(); err != nil {
+		if err := logging.Run(cmd); err != nil {
 			// Try alternative method for older git versions
 			cmd = exec.Command("git", "symbolic-ref", "HEAD", fmt.Sprintf("refs/heads/%s", defaultBranch))
 			cmd.Dir = repoPath
-			_ = cmd.Run() // Ignore error, not critical
+			_ = logging.Run(cmd) // Ignore error, not critical
 		}
 	}
 
@@ -393,7 +609,7 @@ func InitRepository(repoPath string, allowPush bool, defaultBranch string) error
 	if allowPush {
 		cmd = exec.Command("git", "config", "receive.denyCurrentBranch", "updateInstead")
 		cmd.Dir = repoPath
-		output, err = cmd.CombinedOutput()
+		output, err = logging.CombinedOutput(cmd)
 		if err != nil {
 			return fmt.Errorf("failed to configure push settings: %w\nOutput: %s", err, string(output))
 		}
@@ -402,11 +618,18 @@ func InitRepository(repoPath string, allowPush bool, defaultBranch string) error
 	return nil
 }
 
+// HasCommit reports whether the commit exists in the repository's object database
+func HasCommit(repoPath, sha string) bool {
+	cmd := exec.Command("git", "cat-file", "-e", sha+"^{commit}")
+	cmd.Dir = repoPath
+	return logging.Run(cmd) == nil
+}
+
 // GetWorktreeRoot returns the root directory of the current git worktree
 // This handles both main worktrees and linked worktrees
 func GetWorktreeRoot() (string, error) {
 	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
-	output, err := cmd.Output()
+	output, err := logging.Output(cmd)
 	if err != nil {
 		return "", fmt.Errorf("not in a git repository")
 	}
@@ -257,6 +257,20 @@ func TestRemoveRemote(t *testing.T) {
 	}
 }
 
+func TestSetRemoteURL(t *testing.T) {
+	repoPath := createTestRepo(t)
+	cmd := exec.Command("git", "remote", "add", "myproject", "ssh://dev@192.168.1.100:2200/workspace/project")
+	cmd.Dir = repoPath
+	require.NoError(t, cmd.Run())
+
+	require.NoError(t, SetRemoteURL(repoPath, "myproject", "ssh://dev@10.0.0.50:2200/workspace/project"))
+	remotes, err := ListRemotes(repoPath)
+	require.NoError(t, err)
+	assert.Equal(t, "ssh://dev@10.0.0.50:2200/workspace/project", remotes["myproject"])
+
+	assert.ErrorContains(t, SetRemoteURL(repoPath, "nonexistent", "ssh://dev@10.0.0.50:2200/x"), "failed to set remote URL")
+}
+
 func TestSetUpstream(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -406,6 +420,15 @@ func TestValidateGitURL(t *testing.T) {
 }
 
 
+func TestValidateBranchName(t *testing.T) {
+	for _, name := range []string{"main", "feature/PROJ-123", "fix;reboot"} {
+		assert.NoError(t, ValidateBranchName(name), name)
+	}
+	for _, name := range []string{"", "-rf", "two words", "a..b", "ends.lock"} {
+		assert.Error(t, ValidateBranchName(name), name)
+	}
+}
+
 func TestChangeUpstreamToOrigin(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -765,6 +788,132 @@ func TestPushBranch(t *testing.T) {
 	}
 }
 
+func TestPushShallow(t *testing.T) {
+	// The repository is cloned through a file:// URL, which must escape the space
+	repoPath := filepath.Join(t.TempDir(), "my repo")
+	require.NoError(t, os.Rename(createTestRepo(t), repoPath))
+	for _, msg := range []string{"second", "third"} {
+		cmd := exec.Command("git", "commit", "--allow-empty", "-m", msg)
+		cmd.Dir = repoPath
+		require.NoError(t, cmd.Run())
+	}
+
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remoteDir).Run())
+	cmd := exec.Command("git", "config", "receive.shallowUpdate", "true")
+	cmd.Dir = remoteDir
+	require.NoError(t, cmd.Run())
+	cmd = exec.Command("git", "remote", "add", "container", remoteDir)
+	cmd.Dir = repoPath
+	require.NoError(t, cmd.Run())
+
+	require.Error(t, PushShallow(repoPath, "main", "missing", 1))
+	require.Error(t, PushShallow(repoPath, "main", "container", 0))
+	require.NoError(t, PushShallow(repoPath, "main", "container", 2))
+
+	cmd = exec.Command("git", "rev-list", "--count", "main")
+	cmd.Dir = remoteDir
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "2", strings.TrimSpace(string(out)))
+}
+
+func TestResolveRefAndPushRef(t *testing.T) {
+	repoPath := createTestRepo(t)
+	first, err := ResolveRef(repoPath, "HEAD")
+	require.NoError(t, err)
+	assert.Len(t, first, 40)
+
+	cmd := exec.Command("git", "commit", "--allow-empty", "-m", "second")
+	cmd.Dir = repoPath
+	require.NoError(t, cmd.Run())
+	parent, err := ResolveRef(repoPath, "main~1")
+	require.NoError(t, err)
+	assert.Equal(t, first, parent)
+
+	_, err = ResolveRef(repoPath, "no-such-branch")
+	assert.ErrorContains(t, err, "unknown revision 'no-such-branch'")
+
+	remoteDir := filepath.Join(t.TempDir(), "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", remoteDir).Run())
+	cmd = exec.Command("git", "remote", "add", "container", remoteDir)
+	cmd.Dir = repoPath
+	require.NoError(t, cmd.Run())
+
+	// The target branch is replaced even when it moves backwards
+	require.NoError(t, PushRef(repoPath, "main", "container", "l8s-ci"))
+	require.NoError(t, PushRef(repoPath, first, "container", "l8s-ci"))
+	pushed, err := ResolveRef(remoteDir, "l8s-ci")
+	require.NoError(t, err)
+	assert.Equal(t, first, pushed)
+}
+
+func TestWorktreeContainer(t *testing.T) {
+	repoPath := createTestRepo(t)
+	assert.Empty(t, WorktreeContainer(repoPath))
+
+	require.NoError(t, SetWorktreeContainer(repoPath, "api-jira-123"))
+	assert.Equal(t, "api-jira-123", WorktreeContainer(repoPath))
+
+	// Linked worktrees have their own pin
+	linked := filepath.Join(t.TempDir(), "linked")
+	cmd := exec.Command("git", "worktree", "add", "-q", "-b", "other", linked)
+	cmd.Dir = repoPath
+	require.NoError(t, cmd.Run())
+	assert.Empty(t, WorktreeContainer(linked))
+
+	require.NoError(t, SetWorktreeContainer(repoPath, ""))
+	require.NoError(t, SetWorktreeContainer(repoPath, ""), "unpinning twice is fine")
+	assert.Empty(t, WorktreeContainer(repoPath))
+}
+
+func TestListWorktreesAndAheadBehind(t *testing.T) {
+	repoPath := createTestRepo(t)
+	base, err := ResolveRef(repoPath, "HEAD")
+	require.NoError(t, err)
+
+	linked := filepath.Join(t.TempDir(), "linked")
+	cmd := exec.Command("git", "worktree", "add", "-q", "-b", "feature", linked)
+	cmd.Dir = repoPath
+	require.NoError(t, cmd.Run())
+	cmd = exec.Command("git", "commit", "--allow-empty", "-m", "feature work")
+	cmd.Dir = linked
+	require.NoError(t, cmd.Run())
+
+	worktrees, err := ListWorktrees(linked)
+	require.NoError(t, err)
+	require.Len(t, worktrees, 2)
+	assert.Equal(t, "main", worktrees[0].Branch)
+	assert.Equal(t, base, worktrees[0].HEAD)
+	assert.Equal(t, "feature", worktrees[1].Branch)
+
+	ahead, behind, err := AheadBehind(repoPath, "feature", "main")
+	require.NoError(t, err)
+	assert.Equal(t, [2]int{1, 0}, [2]int{ahead, behind})
+}
+
+func TestParseWorktrees(t *testing.T) {
+	output := "worktree /srv/repo.git\nbare\n\n" +
+		"worktree /home/me/api\nHEAD 1111\nbranch refs/heads/feature/x\n\n" +
+		"worktree /home/me/api-hotfix\nHEAD 2222\ndetached\n"
+	assert.Equal(t, []Worktree{
+		{Path: "/srv/repo.git", Bare: true},
+		{Path: "/home/me/api", HEAD: "1111", Branch: "feature/x"},
+		{Path: "/home/me/api-hotfix", HEAD: "2222"},
+	}, parseWorktrees(output))
+}
+
+func TestCreateBranch(t *testing.T) {
+	repoPath := createTestRepo(t)
+	require.NoError(t, CreateBranch(repoPath, "feature/JIRA-1", "HEAD"))
+	branch, err := GetCurrentBranch(repoPath)
+	require.NoError(t, err)
+	assert.Equal(t, "main", branch, "the new branch is not checked out")
+	_, err = ResolveRef(repoPath, "refs/heads/feature/JIRA-1")
+	assert.NoError(t, err)
+	assert.Error(t, CreateBranch(repoPath, "feature/JIRA-1", "HEAD"))
+}
+
 func TestInitRepository(t *testing.T) {
 	tests := []struct {
 		name             string
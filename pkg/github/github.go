@@ -0,0 +1,123 @@
+// Package github talks to the GitHub REST API for commands that work from
+// pull requests
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Repository identifies a repository on a GitHub host
+type Repository struct {
+	Host  string // github.com or a GitHub Enterprise host
+	Owner string
+	Name  string
+}
+
+// remotePattern matches https, ssh:// and scp-style remote URLs
+var remotePattern = regexp.MustCompile(`^(?:https?://|ssh://)?(?:[^@/]+@)?([^/:]+)(?::\d+)?[:/]([^/]+)/([^/]+?)(?:\.git)?/?$`)
+
+// ParseRemote extracts the repository from a git remote URL such as
+// git@github.com:owner/repo.git or https://github.com/owner/repo
+func ParseRemote(url string) (Repository, error) {
+	m := remotePattern.FindStringSubmatch(strings.TrimSpace(url))
+	if m == nil {
+		return Repository{}, fmt.Errorf("'%s' is not a GitHub repository URL", url)
+	}
+	return Repository{Host: m[1], Owner: m[2], Name: m[3]}, nil
+}
+
+// FullName returns owner/name
+func (r Repository) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+// CloneURL returns the HTTPS clone URL, which works with a token where
+// the container has no SSH key for the host
+func (r Repository) CloneURL() string {
+	return fmt.Sprintf("https://%s/%s.git", r.Host, r.FullName())
+}
+
+// APIURL returns the REST API root for the repository's host
+func (r Repository) APIURL() string {
+	if r.Host == "github.com" {
+		return "https://api.github.com"
+	}
+	return fmt.Sprintf("https://%s/api/v3", r.Host)
+}
+
+// PullRequest holds the fields of a pull request l8s uses
+type PullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+	User    struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Head struct {
+		Ref  string `json:"ref"`
+		SHA  string `json:"sha"`
+		Repo *struct {
+			FullName string `json:"full_name"`
+		} `json:"repo"` // nil when the fork was deleted
+	} `json:"head"`
+}
+
+// Client is a minimal GitHub REST client
+type Client struct {
+	BaseURL string // API root, e.g. https://api.github.com
+	Token   string // Optional; required for private repositories
+	client  *http.Client
+}
+
+// NewClient returns a client for the API at baseURL
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Token:   token,
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// GetPullRequest fetches pull request number in repo
+func (c *Client) GetPullRequest(ctx context.Context, repo Repository, number int) (*PullRequest, error) {
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d", c.BaseURL, repo.FullName(), number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query GitHub: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound && c.Token == "":
+		return nil, fmt.Errorf("pull request #%d not found in %s (set github_token for private repositories)", number, repo.FullName())
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("pull request #%d not found in %s", number, repo.FullName())
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, fmt.Errorf("GitHub rejected the configured github_token")
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("GitHub returned %s for pull request #%d", resp.Status, number)
+	}
+
+	var pr PullRequest
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return nil, fmt.Errorf("failed to decode pull request: %w", err)
+	}
+	return &pr, nil
+}
@@ -0,0 +1,63 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		url  string
+		want Repository
+	}{
+		{"git@github.com:lucianHymer/l8s.git", Repository{"github.com", "lucianHymer", "l8s"}},
+		{"https://github.com/lucianHymer/l8s", Repository{"github.com", "lucianHymer", "l8s"}},
+		{"https://token@github.com/lucianHymer/l8s.git/", Repository{"github.com", "lucianHymer", "l8s"}},
+		{"ssh://git@ghe.example.com:2222/team/api.git", Repository{"ghe.example.com", "team", "api"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRemote(tt.url)
+		require.NoError(t, err, tt.url)
+		assert.Equal(t, tt.want, got, tt.url)
+	}
+
+	_, err := ParseRemote("/srv/git/project")
+	assert.Error(t, err)
+
+	ghe := Repository{Host: "ghe.example.com", Owner: "team", Name: "api"}
+	assert.Equal(t, "https://ghe.example.com/api/v3", ghe.APIURL())
+	assert.Equal(t, "https://ghe.example.com/team/api.git", ghe.CloneURL())
+}
+
+func TestGetPullRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/team/api/pulls/42" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"number": 42, "title": "Add caching", "state": "open",
+			"user": {"login": "bob"},
+			"head": {"ref": "bob/cache", "sha": "abc123", "repo": {"full_name": "bob/api"}}}`))
+	}))
+	defer server.Close()
+
+	repo := Repository{Host: "github.com", Owner: "team", Name: "api"}
+	client := NewClient(server.URL, "secret")
+	pr, err := client.GetPullRequest(context.Background(), repo, 42)
+	require.NoError(t, err)
+	assert.Equal(t, "Add caching", pr.Title)
+	assert.Equal(t, "bob", pr.User.Login)
+	assert.Equal(t, "bob/cache", pr.Head.Ref)
+	assert.Equal(t, "bob/api", pr.Head.Repo.FullName)
+
+	_, err = client.GetPullRequest(context.Background(), repo, 7)
+	assert.ErrorContains(t, err, "pull request #7 not found in team/api")
+	_, err = NewClient(server.URL, "").GetPullRequest(context.Background(), repo, 7)
+	assert.ErrorContains(t, err, "set github_token")
+}
@@ -0,0 +1,53 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DirName, "local", "dev-app.jsonl")
+	log := NewLog(path)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	entries, err := log.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	require.NoError(t, log.Append(Entry{Time: now, Command: []string{"dnf", "install", "-y", "jq"}, Duration: 3 * time.Second}))
+	require.NoError(t, log.Append(Entry{Time: now.Add(time.Minute), Command: []string{"false"}, Failed: true}))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	entries, err = log.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, []string{"dnf", "install", "-y", "jq"}, entries[0].Command)
+	assert.Equal(t, 3*time.Second, entries[0].Duration)
+	assert.True(t, entries[0].Time.Equal(now))
+	assert.False(t, entries[0].Failed)
+	assert.True(t, entries[1].Failed)
+
+	require.NoError(t, log.Clear())
+	require.NoError(t, log.Clear())
+	entries, err = log.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLogSkipsDamagedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dev-app.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"time\":\"2026-10-01T12:00:00Z\",\"command\":[\"ls\"]}\n{\"time\":\"2026-10\n"), 0600))
+
+	entries, err := NewLog(path).Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, []string{"ls"}, entries[0].Command)
+}
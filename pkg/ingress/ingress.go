@@ -0,0 +1,133 @@
+package ingress
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"l8s/pkg/logging"
+)
+
+// The proxy is a Caddy container on the Podman host serving each container's
+// web port at <name>.<domain>
+const (
+	// ContainerName is the proxy container on the remote host
+	ContainerName = "l8s-ingress"
+	// Image is the Caddy image the proxy runs
+	Image = "docker.io/library/caddy:2"
+	// ConfigDir holds the generated Caddyfile on the remote host
+	ConfigDir = "/etc/l8s/ingress"
+	// dataVolume keeps certificates across proxy restarts
+	dataVolume = "l8s-ingress-data"
+)
+
+// Route maps a host name to a web port published on the host
+type Route struct {
+	Host string
+	Port int
+}
+
+// Caddyfile renders the proxy configuration for routes. tls is "internal"
+// for Caddy's self-signed CA, "auto" for Let's Encrypt, or anything else for
+// plain HTTP.
+func Caddyfile(routes []Route, tls string) string {
+	sorted := append([]Route(nil), routes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Host < sorted[j].Host })
+
+	var b strings.Builder
+	b.WriteString("# Generated by l8s; changes are overwritten by 'l8s ingress sync'\n")
+	if tls != "auto" && tls != "internal" {
+		b.WriteString("{\n\tauto_https off\n}\n")
+	}
+	for _, r := range sorted {
+		site := r.Host
+		if tls != "auto" && tls != "internal" {
+			site = "http://" + r.Host
+		}
+		fmt.Fprintf(&b, "\n%s {\n", site)
+		if tls == "internal" {
+			b.WriteString("\ttls internal\n")
+		}
+		fmt.Fprintf(&b, "\treverse_proxy localhost:%d\n}\n", r.Port)
+	}
+	return b.String()
+}
+
+// Remote runs proxy commands on the Podman host over SSH
+type Remote struct {
+	User    string
+	Address string
+}
+
+// run executes a command on the remote host, feeding it stdin if given
+func (r Remote) run(stdin []byte, command string) (string, error) {
+	cmd := exec.Command("ssh", fmt.Sprintf("%s@%s", r.User, r.Address), command)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := logging.Output(cmd)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", strings.TrimSpace(stderr.String()), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Running reports whether the proxy container is running
+func (r Remote) Running() (bool, error) {
+	out, err := r.run(nil, fmt.Sprintf("sudo podman ps --filter name=^%s$ --format '{{.Names}}'", ContainerName))
+	if err != nil {
+		return false, fmt.Errorf("failed to check ingress: %w", err)
+	}
+	return out == ContainerName, nil
+}
+
+// Enable writes the Caddyfile and starts the proxy, replacing any existing one.
+// The proxy uses host networking so it can reach the published web ports on
+// localhost and bind 80/443 directly.
+func (r Remote) Enable(caddyfile string) error {
+	if err := r.writeConfig(caddyfile); err != nil {
+		return err
+	}
+	start := fmt.Sprintf("sudo podman rm -f %[1]s >/dev/null 2>&1; "+
+		"sudo podman run -d --name %[1]s --restart always --network host --label l8s.ingress=true "+
+		"-v %[2]s:/etc/caddy:Z -v %[3]s:/data %[4]s caddy run --config /etc/caddy/Caddyfile",
+		ContainerName, ConfigDir, dataVolume, Image)
+	if _, err := r.run(nil, start); err != nil {
+		return fmt.Errorf("failed to start ingress: %w", err)
+	}
+	return nil
+}
+
+// Sync rewrites the Caddyfile and reloads the running proxy
+func (r Remote) Sync(caddyfile string) error {
+	if err := r.writeConfig(caddyfile); err != nil {
+		return err
+	}
+	reload := fmt.Sprintf("sudo podman exec %s caddy reload --config /etc/caddy/Caddyfile", ContainerName)
+	if _, err := r.run(nil, reload); err != nil {
+		return fmt.Errorf("failed to reload ingress: %w", err)
+	}
+	return nil
+}
+
+// Disable stops and removes the proxy. Certificates are kept in case it is
+// enabled again.
+func (r Remote) Disable() error {
+	if _, err := r.run(nil, fmt.Sprintf("sudo podman rm -f --ignore %s", ContainerName)); err != nil {
+		return fmt.Errorf("failed to remove ingress: %w", err)
+	}
+	return nil
+}
+
+// writeConfig uploads the Caddyfile
+func (r Remote) writeConfig(caddyfile string) error {
+	write := fmt.Sprintf("sudo mkdir -p %[1]s && sudo tee %[1]s/Caddyfile >/dev/null", ConfigDir)
+	if _, err := r.run([]byte(caddyfile), write); err != nil {
+		return fmt.Errorf("failed to write ingress config: %w", err)
+	}
+	return nil
+}
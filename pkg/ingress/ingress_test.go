@@ -0,0 +1,41 @@
+package ingress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaddyfile(t *testing.T) {
+	routes := []Route{
+		{Host: "web.dev.example.com", Port: 3001},
+		{Host: "api.dev.example.com", Port: 3000},
+	}
+
+	plain := Caddyfile(routes, "off")
+	assert.Equal(t, `# Generated by l8s; changes are overwritten by 'l8s ingress sync'
+{
+	auto_https off
+}
+
+http://api.dev.example.com {
+	reverse_proxy localhost:3000
+}
+
+http://web.dev.example.com {
+	reverse_proxy localhost:3001
+}
+`, plain)
+
+	internal := Caddyfile(routes[:1], "internal")
+	assert.Equal(t, `# Generated by l8s; changes are overwritten by 'l8s ingress sync'
+
+web.dev.example.com {
+	tls internal
+	reverse_proxy localhost:3001
+}
+`, internal)
+
+	auto := Caddyfile(nil, "auto")
+	assert.NotContains(t, auto, "auto_https")
+}
@@ -12,11 +12,24 @@ type Config struct {
 	Level  string
 	Format string
 	Output string
+
+	// LevelVar, when set, is set to Level and controls the terminal level
+	// from then on, so it can be changed without building another logger
+	LevelVar *slog.LevelVar
+
+	// File also receives every record from debug up (trace too when Level
+	// is trace) as JSON so failed runs can be diagnosed later. Empty disables
+	// file logging.
+	File        string
+	MaxFileSize int64 // Bytes before the file is rotated (DefaultMaxFileSize when zero)
+	MaxBackups  int   // Rotated files to keep (DefaultMaxBackups when zero)
 }
 
 func NewLogger(cfg Config) (*slog.Logger, error) {
 	var level slog.Level
 	switch strings.ToLower(cfg.Level) {
+	case "trace":
+		level = LevelTrace
 	case "debug":
 		level = slog.LevelDebug
 	case "info":
@@ -39,9 +52,18 @@ func NewLogger(cfg Config) (*slog.Logger, error) {
 		output = os.Stderr
 	}
 
+	var leveler slog.Leveler = level
+	if cfg.LevelVar != nil {
+		cfg.LevelVar.Set(level)
+		leveler = cfg.LevelVar
+	}
+
 	opts := &slog.HandlerOptions{
-		Level: level,
+		Level: leveler,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.LevelKey && a.Value.Any() == LevelTrace {
+				return slog.String(slog.LevelKey, "TRACE")
+			}
 			if a.Key == slog.SourceKey {
 				source, ok := a.Value.Any().(*slog.Source)
 				if ok && source != nil {
@@ -62,5 +84,20 @@ func NewLogger(cfg Config) (*slog.Logger, error) {
 		handler = slog.NewTextHandler(output, opts)
 	}
 
-	return slog.New(handler), nil
+	if cfg.File == "" {
+		return slog.New(handler), nil
+	}
+
+	maxBackups := cfg.MaxBackups
+	if maxBackups == 0 {
+		maxBackups = DefaultMaxBackups
+	}
+	file, err := OpenRotatingFile(cfg.File, cfg.MaxFileSize, maxBackups)
+	if err != nil {
+		// Still usable, just without the file
+		return slog.New(handler), err
+	}
+	fileOpts := *opts
+	fileOpts.Level = min(level, slog.LevelDebug)
+	return slog.New(multiHandler{handler, slog.NewJSONHandler(file, &fileOpts)}), nil
 }
\ No newline at end of file
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "l8s.log")
+	file, err := OpenRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, file.Close())
+
+	read := func(p string) string {
+		data, err := os.ReadFile(p)
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, "fourth\n", read(path))
+	assert.Equal(t, "third\n", read(path+".1"))
+	assert.Equal(t, "second\n", read(path+".2"))
+	assert.NoFileExists(t, path+".3")
+
+	// Reopening appends to the existing file
+	file, err = OpenRotatingFile(path, 100, 2)
+	require.NoError(t, err)
+	_, err = file.Write([]byte("fifth\n"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	assert.Equal(t, "fourth\nfifth\n", read(path))
+}
+
+func TestNewLoggerWithFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "l8s.log")
+	logger, err := NewLogger(Config{Level: "warn", Output: "stderr", File: path})
+	require.NoError(t, err)
+
+	// The file records debug messages that stderr filters out
+	logger.Debug("connecting", "host", "example.com")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(data), `"msg":"connecting"`), string(data))
+	assert.Contains(t, string(data), `"host":"example.com"`)
+
+	// A file that cannot be opened leaves a working stderr logger
+	blocker := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(blocker, nil, 0644))
+	logger, err = NewLogger(Config{File: filepath.Join(blocker, "l8s.log")})
+	assert.Error(t, err)
+	assert.NotNil(t, logger)
+}
+
+func TestNewLoggerLevelVar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "l8s.log")
+	level := new(slog.LevelVar)
+	logger, err := NewLogger(Config{Level: "info", LevelVar: level, File: path})
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelInfo, level.Level())
+
+	// Lowering the level later needs no second logger, or second file handle
+	assert.False(t, logger.Handler().(multiHandler)[0].Enabled(context.Background(), slog.LevelDebug))
+	level.Set(slog.LevelDebug)
+	assert.True(t, logger.Handler().(multiHandler)[0].Enabled(context.Background(), slog.LevelDebug))
+}
@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LevelTrace is below debug and logs every remote call, SSH command and git
+// invocation with its duration (L8S_LOG_LEVEL=trace)
+const LevelTrace = slog.LevelDebug - 4
+
+// redacted replaces secret values in traced arguments
+const redacted = "********"
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+
+	// secretPatterns match credentials that were never registered, such as
+	// tokens embedded in URLs or passed as key=value arguments
+	secretPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})`),
+		regexp.MustCompile(`://[^/\s:@]+:[^/\s@]+@`),
+		regexp.MustCompile(`(?i)\b((?:[a-z_]*_)?(?:token|password|passwd|secret|api_?key))=\S+`),
+	}
+)
+
+// AddSecret registers a value that must never appear in trace output
+func AddSecret(value string) {
+	if len(value) < 4 {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secrets = append(secrets, value)
+}
+
+// Redact masks registered secrets and anything that looks like a credential
+func Redact(s string) string {
+	secretsMu.RLock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	secretsMu.RUnlock()
+
+	s = secretPatterns[0].ReplaceAllString(s, redacted)
+	s = secretPatterns[1].ReplaceAllString(s, "://"+redacted+"@")
+	return secretPatterns[2].ReplaceAllString(s, "${1}="+redacted)
+}
+
+// TraceEnabled reports whether trace logging is on, so callers can skip
+// work that only feeds trace output
+func TraceEnabled() bool {
+	return defaultLogger.Enabled(context.Background(), LevelTrace)
+}
+
+// Trace logs at LevelTrace
+func Trace(msg string, args ...any) {
+	defaultLogger.Log(context.Background(), LevelTrace, msg, args...)
+}
+
+// TraceCall logs a finished operation with its redacted arguments, duration
+// and error. Use it as
+//
+//	defer logging.TraceCall("podman", "StartContainer", name)(&err)
+func TraceCall(kind, op string, args ...any) func(*error) {
+	if !TraceEnabled() {
+		return func(*error) {}
+	}
+	start := time.Now()
+	return func(errp *error) {
+		attrs := []any{
+			slog.String("op", op),
+			slog.String("args", Redact(formatArgs(args))),
+			slog.Duration("duration", time.Since(start)),
+		}
+		if errp != nil && *errp != nil {
+			attrs = append(attrs, slog.String("error", Redact((*errp).Error())))
+		}
+		Trace(kind, attrs...)
+	}
+}
+
+// formatArgs renders call arguments compactly for trace output
+func formatArgs(args []any) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			parts[i] = v
+		case []string:
+			parts[i] = strings.Join(v, " ")
+		case []byte:
+			parts[i] = "<" + strconv.Itoa(len(v)) + " bytes>"
+		default:
+			parts[i] = slog.AnyValue(v).String()
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// traceCmd starts tracing an external command
+func traceCmd(cmd *exec.Cmd) func(*error) {
+	kind := "exec"
+	if len(cmd.Args) > 0 {
+		kind = cmd.Args[0]
+	}
+	var args []any
+	if len(cmd.Args) > 1 {
+		args = append(args, cmd.Args[1:])
+	}
+	if cmd.Dir != "" {
+		args = append(args, "(in "+cmd.Dir+")")
+	}
+	op := "run"
+	if len(cmd.Args) > 1 {
+		op = cmd.Args[1]
+	}
+	return TraceCall(kind, op, args...)
+}
+
+// Run runs cmd like cmd.Run, tracing the command line and duration
+func Run(cmd *exec.Cmd) (err error) {
+	defer traceCmd(cmd)(&err)
+	return cmd.Run()
+}
+
+// Output runs cmd like cmd.Output, tracing the command line and duration
+func Output(cmd *exec.Cmd) (_ []byte, err error) {
+	defer traceCmd(cmd)(&err)
+	return cmd.Output()
+}
+
+// CombinedOutput runs cmd like cmd.CombinedOutput, tracing the command line
+// and duration
+func CombinedOutput(cmd *exec.Cmd) (_ []byte, err error) {
+	defer traceCmd(cmd)(&err)
+	return cmd.CombinedOutput()
+}
@@ -0,0 +1,170 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Lifecycle events that can trigger a webhook
+const (
+	EventCreate  = "create"
+	EventRemove  = "remove"
+	EventRebuild = "rebuild"
+)
+
+// Events lists every event name accepted in webhook configuration
+var Events = []string{EventCreate, EventRemove, EventRebuild}
+
+// DefaultTemplate renders a payload that Slack incoming webhooks accept as-is
+// while still carrying structured fields for generic receivers
+const DefaultTemplate = `{"text": {{json .Text}}, "event": {{json .Event}}, "container": {{json .Container}}, "connection": {{json .Connection}}, "user": {{json .User}}, "time": {{json .Time}}}`
+
+// Event describes a container lifecycle change
+type Event struct {
+	Event      string
+	Container  string
+	Connection string
+	User       string
+	Time       string // RFC 3339
+	Text       string // Human readable summary
+}
+
+// Hook is a configured webhook endpoint
+type Hook struct {
+	URL      string
+	Events   []string // Empty means all events
+	Template string   // Go text/template producing JSON; DefaultTemplate when empty
+	Headers  map[string]string
+}
+
+// Notifier posts lifecycle events to webhooks
+type Notifier struct {
+	hooks  []Hook
+	client *http.Client
+}
+
+// New returns a Notifier for the given hooks
+func New(hooks []Hook) *Notifier {
+	return &Notifier{
+		hooks:  hooks,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// templateFuncs are available in payload templates
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// Validate checks that a hook is usable
+func (h Hook) Validate() error {
+	if !strings.HasPrefix(h.URL, "http://") && !strings.HasPrefix(h.URL, "https://") {
+		return fmt.Errorf("url must start with http:// or https://")
+	}
+	for _, e := range h.Events {
+		if !isEvent(e) {
+			return fmt.Errorf("unknown event '%s' (valid: %s)", e, strings.Join(Events, ", "))
+		}
+	}
+	_, err := h.Render(Event{Event: EventCreate, Container: "dev-example", Text: "example"})
+	return err
+}
+
+// Render produces the JSON payload for an event
+func (h Hook) Render(e Event) ([]byte, error) {
+	text := h.Template
+	if text == "" {
+		text = DefaultTemplate
+	}
+	tmpl, err := template.New("webhook").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, e); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("template did not produce valid JSON: %s", buf.String())
+	}
+	return buf.Bytes(), nil
+}
+
+// wants reports whether the hook subscribes to an event
+func (h Hook) wants(event string) bool {
+	if len(h.Events) == 0 {
+		return true
+	}
+	for _, e := range h.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Send posts an event to every subscribed hook. Delivery failures are
+// collected so one unreachable endpoint does not block the others.
+func (n *Notifier) Send(ctx context.Context, e Event) error {
+	if e.Time == "" {
+		e.Time = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	var errs []error
+	for _, h := range n.hooks {
+		if !h.wants(e.Event) {
+			continue
+		}
+		if err := n.post(ctx, h, e); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", h.URL, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// post delivers one event to one hook
+func (n *Notifier) post(ctx context.Context, h Hook, e Event) error {
+	payload, err := h.Render(e)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// isEvent reports whether name is a known event
+func isEvent(name string) bool {
+	for _, e := range Events {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
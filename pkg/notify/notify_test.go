@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookRender(t *testing.T) {
+	e := Event{Event: EventCreate, Container: "dev-api", User: "alice", Text: `alice created "dev-api"`}
+
+	t.Run("default template is valid JSON", func(t *testing.T) {
+		payload, err := Hook{}.Render(e)
+		require.NoError(t, err)
+
+		var got map[string]string
+		require.NoError(t, json.Unmarshal(payload, &got))
+		assert.Equal(t, `alice created "dev-api"`, got["text"])
+		assert.Equal(t, "create", got["event"])
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		payload, err := Hook{Template: `{"msg": {{json .Container}}}`}.Render(e)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"msg": "dev-api"}`, string(payload))
+	})
+
+	t.Run("template producing invalid JSON", func(t *testing.T) {
+		_, err := Hook{Template: `{"msg": {{.Container}}}`}.Render(e)
+		assert.ErrorContains(t, err, "valid JSON")
+	})
+}
+
+func TestHookValidate(t *testing.T) {
+	assert.NoError(t, Hook{URL: "https://hooks.example.com/x", Events: []string{"create", "remove"}}.Validate())
+	assert.Error(t, Hook{URL: "hooks.example.com"}.Validate())
+	assert.Error(t, Hook{URL: "https://hooks.example.com", Events: []string{"deploy"}}.Validate())
+	assert.Error(t, Hook{URL: "https://hooks.example.com", Template: "{{"}.Validate())
+}
+
+func TestNotifierSend(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r.Header.Get("X-Token")+" "+string(body))
+	}))
+	defer server.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	n := New([]Hook{
+		{URL: server.URL, Events: []string{EventRemove}, Template: `{"c": {{json .Container}}}`, Headers: map[string]string{"X-Token": "secret"}},
+		{URL: server.URL, Events: []string{EventCreate}},
+		{URL: failing.URL, Events: []string{EventRemove}},
+	})
+
+	err := n.Send(context.Background(), Event{Event: EventRemove, Container: "dev-api"})
+	assert.ErrorContains(t, err, "500")
+	assert.Equal(t, []string{`secret {"c": "dev-api"}`}, received)
+}
@@ -0,0 +1,213 @@
+// Package progress emits machine-readable progress events for long operations
+// so wrappers such as editors and GUIs can render their own progress UI.
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	EventStarted  = "step_started"
+	EventProgress = "step_progress"
+	EventFinished = "step_finished"
+	EventFailed   = "step_failed"
+	EventDone     = "done"
+)
+
+// Modes accepted by --progress
+const (
+	ModeText = "text"
+	ModeJSON = "json"
+)
+
+// Event is a single progress event, written as one JSON line
+type Event struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Event     string    `json:"event"`
+	Step      string    `json:"step,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Percent   int       `json:"percent"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Reporter receives progress events
+type Reporter interface {
+	Report(e Event)
+}
+
+type nopReporter struct{}
+
+func (nopReporter) Report(Event) {}
+
+// jsonReporter writes events as JSON lines
+type jsonReporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (r *jsonReporter) Report(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_ = r.enc.Encode(e)
+}
+
+// New returns a reporter for mode writing to w. Text mode reports nothing
+// since the regular human readable output already describes progress.
+func New(mode string, w io.Writer) (Reporter, error) {
+	switch mode {
+	case "", ModeText:
+		return nopReporter{}, nil
+	case ModeJSON:
+		return &jsonReporter{enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("invalid progress mode '%s' (expected %s or %s)", mode, ModeText, ModeJSON)
+	}
+}
+
+var defaultReporter Reporter = nopReporter{}
+
+// SetDefault sets the reporter used by Start
+func SetDefault(r Reporter) {
+	defaultReporter = r
+}
+
+// Default returns the reporter used by Start
+func Default() Reporter {
+	return defaultReporter
+}
+
+// Configure installs the reporter for mode on stderr as the default
+func Configure(mode string) error {
+	r, err := New(mode, os.Stderr)
+	if err != nil {
+		return err
+	}
+	SetDefault(r)
+	return nil
+}
+
+// Operation tracks the steps of one long running operation. Overall percent
+// advances as steps finish; a step may report finer progress within its share.
+type Operation struct {
+	reporter Reporter
+	name     string
+	total    int
+	done     int
+	step     string
+}
+
+// Start begins an operation of total steps on the default reporter
+func Start(name string, total int) *Operation {
+	return StartWith(defaultReporter, name, total)
+}
+
+// StartWith begins an operation on reporter r
+func StartWith(r Reporter, name string, total int) *Operation {
+	if total < 1 {
+		total = 1
+	}
+	return &Operation{reporter: r, name: name, total: total}
+}
+
+func (o *Operation) emit(event, step, message string, percent int, err error) {
+	e := Event{
+		Time:      time.Now().UTC(),
+		Operation: o.name,
+		Event:     event,
+		Step:      step,
+		Message:   message,
+		Percent:   percent,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	o.reporter.Report(e)
+}
+
+func (o *Operation) percent() int {
+	return o.done * 100 / o.total
+}
+
+// Step finishes the current step (if any) and starts the next one
+func (o *Operation) Step(step, message string) {
+	o.finishStep()
+	o.step = step
+	o.emit(EventStarted, step, message, o.percent(), nil)
+}
+
+// Progress reports completion of the current step as a fraction of its share
+func (o *Operation) Progress(current, total int) {
+	if o.step == "" || total <= 0 {
+		return
+	}
+	if current > total {
+		current = total
+	}
+	percent := (o.done*total + current) * 100 / (o.total * total)
+	o.emit(EventProgress, o.step, "", percent, nil)
+}
+
+func (o *Operation) finishStep() {
+	if o.step == "" {
+		return
+	}
+	if o.done < o.total {
+		o.done++
+	}
+	o.emit(EventFinished, o.step, "", o.percent(), nil)
+	o.step = ""
+}
+
+// Done ends the operation, reporting err against the current step if non-nil
+func (o *Operation) Done(err error) {
+	if err != nil {
+		o.emit(EventFailed, o.step, "", o.percent(), err)
+		o.emit(EventDone, "", "", o.percent(), err)
+		return
+	}
+	o.finishStep()
+	o.done = o.total
+	o.emit(EventDone, "", "", 100, nil)
+}
+
+// buildStepPattern matches podman/buildah build step headers, e.g. "STEP 3/12: RUN ..."
+var buildStepPattern = regexp.MustCompile(`(?m)^STEP (\d+)/(\d+)`)
+
+// BuildWriter passes build output through to w while reporting "STEP n/m"
+// headers as progress of the operation's current step
+func (o *Operation) BuildWriter(w io.Writer) io.Writer {
+	return &buildWriter{op: o, out: w}
+}
+
+type buildWriter struct {
+	op   *Operation
+	out  io.Writer
+	line []byte
+}
+
+func (b *buildWriter) Write(p []byte) (int, error) {
+	b.line = append(b.line, p...)
+	for {
+		i := bytes.IndexByte(b.line, '\n')
+		if i < 0 {
+			break
+		}
+		if m := buildStepPattern.FindSubmatch(b.line[:i]); m != nil {
+			current, _ := strconv.Atoi(string(m[1]))
+			total, _ := strconv.Atoi(string(m[2]))
+			b.op.Progress(current-1, total)
+		}
+		b.line = b.line[i+1:]
+	}
+	return b.out.Write(p)
+}
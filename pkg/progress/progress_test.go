@@ -0,0 +1,96 @@
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, buf *bytes.Buffer) []Event {
+	var events []Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var e Event
+		require.NoError(t, json.Unmarshal([]byte(line), &e), line)
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("text", io.Discard)
+	assert.NoError(t, err)
+	_, err = New("json", io.Discard)
+	assert.NoError(t, err)
+	_, err = New("xml", io.Discard)
+	assert.Error(t, err)
+}
+
+func TestOperationSteps(t *testing.T) {
+	var buf bytes.Buffer
+	r, err := New(ModeJSON, &buf)
+	require.NoError(t, err)
+
+	op := StartWith(r, "create", 2)
+	op.Step("one", "First")
+	op.Step("two", "Second")
+	op.Done(nil)
+
+	events := decode(t, &buf)
+	require.Len(t, events, 5)
+	assert.Equal(t, EventStarted, events[0].Event)
+	assert.Equal(t, "one", events[0].Step)
+	assert.Equal(t, "First", events[0].Message)
+	assert.Equal(t, 0, events[0].Percent)
+	assert.Equal(t, EventFinished, events[1].Event)
+	assert.Equal(t, 50, events[1].Percent)
+	assert.Equal(t, EventStarted, events[2].Event)
+	assert.Equal(t, EventFinished, events[3].Event)
+	assert.Equal(t, 100, events[3].Percent)
+	assert.Equal(t, EventDone, events[4].Event)
+	assert.Equal(t, "create", events[4].Operation)
+}
+
+func TestOperationFailure(t *testing.T) {
+	var buf bytes.Buffer
+	r, _ := New(ModeJSON, &buf)
+
+	op := StartWith(r, "build", 3)
+	op.Step("prepare", "")
+	op.Step("upload", "")
+	op.Done(errors.New("scp failed"))
+
+	events := decode(t, &buf)
+	failed := events[len(events)-2]
+	assert.Equal(t, EventFailed, failed.Event)
+	assert.Equal(t, "upload", failed.Step)
+	assert.Equal(t, "scp failed", failed.Error)
+	assert.Equal(t, 33, failed.Percent)
+	assert.Equal(t, EventDone, events[len(events)-1].Event)
+}
+
+func TestBuildWriter(t *testing.T) {
+	var buf, out bytes.Buffer
+	r, _ := New(ModeJSON, &buf)
+
+	op := StartWith(r, "build", 2)
+	op.Step("upload", "")
+	op.Step("build", "")
+	buf.Reset()
+
+	w := op.BuildWriter(&out)
+	_, _ = w.Write([]byte("STEP 1/4: FROM fedora\nSTEP 3/"))
+	_, _ = w.Write([]byte("4: RUN dnf install\nother output\n"))
+
+	assert.Equal(t, "STEP 1/4: FROM fedora\nSTEP 3/4: RUN dnf install\nother output\n", out.String())
+	events := decode(t, &buf)
+	require.Len(t, events, 2)
+	assert.Equal(t, EventProgress, events[0].Event)
+	assert.Equal(t, 50, events[0].Percent)
+	assert.Equal(t, 75, events[1].Percent)
+}
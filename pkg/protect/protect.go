@@ -0,0 +1,111 @@
+// Package protect records which containers are protected from remove and
+// rebuild-all. Protection is kept on this machine rather than as a container
+// label, since Podman can only change labels by recreating the container.
+package protect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FileName is the list kept in the l8s state directory
+const FileName = "protected.json"
+
+// List records protection per container. Containers on different
+// connections can share a name, so entries are kept per connection.
+type List struct {
+	path string
+}
+
+// NewList returns a list backed by the file at path
+func NewList(path string) *List {
+	return &List{path: path}
+}
+
+// Set records whether a container is protected. Recording false, rather than
+// forgetting the container, also overrides the label older versions set.
+func (l *List) Set(connection, container string, protected bool) error {
+	entries, err := l.read()
+	if err != nil {
+		return err
+	}
+	entries[key(connection, container)] = protected
+	return l.write(entries)
+}
+
+// Lookup returns whether a container is protected, and whether anything was
+// recorded for it at all
+func (l *List) Lookup(connection, container string) (protected, ok bool, err error) {
+	entries, err := l.read()
+	if err != nil {
+		return false, false, err
+	}
+	protected, ok = entries[key(connection, container)]
+	return protected, ok, nil
+}
+
+// Forget drops the entry of a removed container, so a new container of the
+// same name starts out unprotected
+func (l *List) Forget(connection, container string) error {
+	entries, err := l.read()
+	if err != nil {
+		return err
+	}
+	k := key(connection, container)
+	if _, ok := entries[k]; !ok {
+		return nil
+	}
+	delete(entries, k)
+	return l.write(entries)
+}
+
+func key(connection, container string) string {
+	return connection + "/" + container
+}
+
+func (l *List) read() (map[string]bool, error) {
+	entries := make(map[string]bool)
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read protected containers: %w", err)
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse protected containers %s: %w", l.path, err)
+	}
+	return entries, nil
+}
+
+// write replaces the file atomically so concurrent runs never see a partial file
+func (l *List) write(entries map[string]bool) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode protected containers: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), ".protected-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write protected containers: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write protected containers: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write protected containers: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), l.path); err != nil {
+		return fmt.Errorf("failed to write protected containers: %w", err)
+	}
+	return nil
+}
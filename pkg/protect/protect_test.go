@@ -0,0 +1,37 @@
+package protect
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	list := NewList(filepath.Join(t.TempDir(), "state", FileName))
+
+	_, ok, err := list.Lookup("home", "dev-api")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, list.Set("home", "dev-api", true))
+	require.NoError(t, list.Set("work", "dev-api", false))
+
+	protected, ok, err := list.Lookup("home", "dev-api")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, protected)
+
+	// An explicit false is recorded, not forgotten
+	protected, ok, err = list.Lookup("work", "dev-api")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, protected)
+
+	require.NoError(t, list.Forget("home", "dev-api"))
+	require.NoError(t, list.Forget("home", "dev-missing"))
+	_, ok, err = list.Lookup("home", "dev-api")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,156 @@
+package repos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FileName is the index kept in the l8s state directory
+const FileName = "repos.json"
+
+// Entry records the local repository a container's git remote was added to
+type Entry struct {
+	Connection string `json:"connection"`
+	Container  string `json:"container"` // Full name, prefix included
+	Path       string `json:"path"`
+}
+
+// Index remembers which local repositories have remotes for l8s containers,
+// so commands that change how containers are reached can update them.
+// Containers on different connections can share a name, so entries are kept
+// per connection.
+type Index struct {
+	path string
+}
+
+// NewIndex returns an index backed by the file at path
+func NewIndex(path string) *Index {
+	return &Index{path: path}
+}
+
+// Record notes that the container's remote lives in the repository at e.Path,
+// replacing what was recorded for it before
+func (i *Index) Record(e Entry) error {
+	entries, err := i.read()
+	if err != nil {
+		return err
+	}
+	entries[key(e.Connection, e.Container)] = e
+	return i.write(entries)
+}
+
+// Lookup returns the entry recorded for a container
+func (i *Index) Lookup(connection, container string) (Entry, bool, error) {
+	entries, err := i.read()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	e, ok := entries[key(connection, container)]
+	return e, ok, nil
+}
+
+// Forget drops the entry of a container
+func (i *Index) Forget(connection, container string) error {
+	entries, err := i.read()
+	if err != nil {
+		return err
+	}
+	k := key(connection, container)
+	if _, ok := entries[k]; !ok {
+		return nil
+	}
+	delete(entries, k)
+	return i.write(entries)
+}
+
+// Entries returns every recorded container, by connection then name
+func (i *Index) Entries() ([]Entry, error) {
+	entries, err := i.read()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(a, b int) bool {
+		if list[a].Connection != list[b].Connection {
+			return list[a].Connection < list[b].Connection
+		}
+		return list[a].Container < list[b].Container
+	})
+	return list, nil
+}
+
+// Paths returns the recorded repositories that still exist, each once
+func (i *Index) Paths() ([]string, error) {
+	entries, err := i.read()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var paths []string
+	for _, e := range entries {
+		if seen[e.Path] {
+			continue
+		}
+		seen[e.Path] = true
+		if _, err := os.Stat(e.Path); err == nil {
+			paths = append(paths, e.Path)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func key(connection, container string) string {
+	return connection + "/" + container
+}
+
+func (i *Index) read() (map[string]Entry, error) {
+	entries := make(map[string]Entry)
+	data, err := os.ReadFile(i.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repository index: %w", err)
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse repository index %s: %w", i.path, err)
+	}
+	return entries, nil
+}
+
+// write replaces the file atomically so concurrent runs never see a partial file
+func (i *Index) write(entries map[string]Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode repository index: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(i.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(i.path), ".repos-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write repository index: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write repository index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write repository index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), i.path); err != nil {
+		return fmt.Errorf("failed to write repository index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package repos
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndex(t *testing.T) {
+	dir := t.TempDir()
+	index := NewIndex(filepath.Join(dir, "state", FileName))
+
+	entries, err := index.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	require.NoError(t, index.Record(Entry{Connection: "home", Container: "dev-api", Path: dir}))
+	require.NoError(t, index.Record(Entry{Connection: "home", Container: "dev-web", Path: dir}))
+	require.NoError(t, index.Record(Entry{Connection: "work", Container: "dev-api", Path: filepath.Join(dir, "gone")}))
+	// Recording a container again replaces its entry
+	require.NoError(t, index.Record(Entry{Connection: "home", Container: "dev-web", Path: dir}))
+
+	entries, err = index.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, Entry{Connection: "home", Container: "dev-api", Path: dir}, entries[0])
+	assert.Equal(t, "work", entries[2].Connection)
+
+	// Repositories deleted from disk are skipped
+	paths, err := index.Paths()
+	require.NoError(t, err)
+	assert.Equal(t, []string{dir}, paths)
+
+	entry, ok, err := index.Lookup("work", "dev-api")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(dir, "gone"), entry.Path)
+
+	require.NoError(t, index.Forget("work", "dev-api"))
+	require.NoError(t, index.Forget("work", "dev-api"))
+	_, ok, err = index.Lookup("work", "dev-api")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	entries, err = index.Entries()
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
@@ -0,0 +1,122 @@
+package scan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"l8s/pkg/logging"
+)
+
+const (
+	// TrivyImage runs the scanner on the remote host so nothing has to be installed there
+	TrivyImage = "docker.io/aquasec/trivy:latest"
+	// cacheVolume keeps the vulnerability database between scans
+	cacheVolume = "l8s-trivy-cache"
+)
+
+// Severities ordered from most to least severe
+var Severities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "UNKNOWN"}
+
+// Vulnerability is a single finding reported by trivy
+type Vulnerability struct {
+	ID               string `json:"VulnerabilityID"`
+	Package          string `json:"PkgName"`
+	InstalledVersion string `json:"InstalledVersion"`
+	FixedVersion     string `json:"FixedVersion"`
+	Severity         string `json:"Severity"`
+	Title            string `json:"Title"`
+}
+
+// trivyReport is the subset of trivy's JSON output l8s reads
+type trivyReport struct {
+	Results []struct {
+		Target          string          `json:"Target"`
+		Vulnerabilities []Vulnerability `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+// ParseReport extracts vulnerabilities from trivy JSON output, most severe
+// first, dropping duplicates reported for several targets
+func ParseReport(data []byte) ([]Vulnerability, error) {
+	var report trivyReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse scan report: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var vulns []Vulnerability
+	for _, result := range report.Results {
+		for _, v := range result.Vulnerabilities {
+			key := v.ID + "/" + v.Package + "/" + v.InstalledVersion
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			vulns = append(vulns, v)
+		}
+	}
+
+	sort.SliceStable(vulns, func(i, j int) bool {
+		ri, rj := severityRank(vulns[i].Severity), severityRank(vulns[j].Severity)
+		if ri != rj {
+			return ri < rj
+		}
+		return vulns[i].ID < vulns[j].ID
+	})
+	return vulns, nil
+}
+
+// severityRank orders severities, unknown values last
+func severityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(Severities)
+}
+
+// ParseSeverities validates a comma separated severity list such as "CRITICAL,HIGH"
+func ParseSeverities(list string) ([]string, error) {
+	var severities []string
+	for _, s := range strings.Split(list, ",") {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if s == "" {
+			continue
+		}
+		if severityRank(s) == len(Severities) {
+			return nil, fmt.Errorf("unknown severity '%s' (use %s)", s, strings.Join(Severities, ", "))
+		}
+		severities = append(severities, s)
+	}
+	if len(severities) == 0 {
+		return nil, fmt.Errorf("at least one severity is required")
+	}
+	return severities, nil
+}
+
+// Image scans an image in the remote host's podman storage with trivy run
+// as a container there. The image is exported to a temporary archive so the
+// scanner does not need access to the podman socket.
+func Image(user, address, image string, severities []string) ([]Vulnerability, error) {
+	archive := fmt.Sprintf("/tmp/l8s-scan-%d.tar", time.Now().UnixNano())
+	script := fmt.Sprintf("sudo podman image save -o %[1]s %[2]s >/dev/null && "+
+		"sudo podman run --rm -v %[1]s:/image.tar:ro,Z -v %[3]s:/root/.cache %[4]s "+
+		"image --quiet --format json --severity %[5]s --input /image.tar; "+
+		"status=$?; sudo rm -f %[1]s; exit $status",
+		archive, image, cacheVolume, TrivyImage, strings.Join(severities, ","))
+
+	cmd := exec.Command("ssh", fmt.Sprintf("%s@%s", user, address), script)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := logging.Output(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("scan of %s failed: %s", image, strings.TrimSpace(stderr.String()))
+	}
+	return ParseReport(out)
+}
@@ -0,0 +1,44 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReport(t *testing.T) {
+	data := []byte(`{
+  "Results": [
+    {"Target": "fedora", "Vulnerabilities": [
+      {"VulnerabilityID": "CVE-2024-2", "PkgName": "glibc", "InstalledVersion": "2.38", "FixedVersion": "2.39", "Severity": "HIGH"},
+      {"VulnerabilityID": "CVE-2024-1", "PkgName": "openssl", "InstalledVersion": "3.1", "Severity": "CRITICAL"}
+    ]},
+    {"Target": "node", "Vulnerabilities": [
+      {"VulnerabilityID": "CVE-2024-1", "PkgName": "openssl", "InstalledVersion": "3.1", "Severity": "CRITICAL"}
+    ]},
+    {"Target": "clean"}
+  ]
+}`)
+
+	vulns, err := ParseReport(data)
+	require.NoError(t, err)
+	require.Len(t, vulns, 2)
+	assert.Equal(t, "CVE-2024-1", vulns[0].ID)
+	assert.Equal(t, "CRITICAL", vulns[0].Severity)
+	assert.Equal(t, "2.39", vulns[1].FixedVersion)
+
+	_, err = ParseReport([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestParseSeverities(t *testing.T) {
+	severities, err := ParseSeverities("critical, HIGH")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CRITICAL", "HIGH"}, severities)
+
+	_, err = ParseSeverities("severe")
+	assert.Error(t, err)
+	_, err = ParseSeverities("")
+	assert.Error(t, err)
+}
@@ -67,8 +67,9 @@ func (ca *CA) Generate() error {
 	return nil
 }
 
-// SignHostKey signs an SSH host key with the CA certificate
-func (ca *CA) SignHostKey(hostKeyPath, containerName, remoteHost string) error {
+// SignHostKey signs an SSH host key with the CA certificate for hostAlias, the
+// container's SSH config host (e.g. dev-myproject), and remoteHost
+func (ca *CA) SignHostKey(hostKeyPath, hostAlias, remoteHost string) error {
 	// Check if CA exists
 	if !ca.Exists() {
 		return fmt.Errorf("CA key not found. Run 'l8s init' to generate")
@@ -79,12 +80,12 @@ func (ca *CA) SignHostKey(hostKeyPath, containerName, remoteHost string) error {
 
 	// Prepare principals (valid hostnames for the certificate)
 	// Include both the container name and the remote host with wildcard ports
-	principals := fmt.Sprintf("%s-%s,%s", "dev", containerName, remoteHost)
+	principals := fmt.Sprintf("%s,%s", hostAlias, remoteHost)
 
 	// Sign the host key with CA
 	cmd := exec.Command("ssh-keygen",
 		"-s", ca.PrivateKeyPath,           // Sign with CA private key
-		"-I", fmt.Sprintf("dev-%s", containerName), // Certificate ID
+		"-I", hostAlias,                   // Certificate ID
 		"-h",                               // Host certificate (not user)
 		"-V", "+3650d",                    // Valid for 10 years
 		"-n", principals,                   // Valid principals (hostnames)
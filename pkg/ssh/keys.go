@@ -2,10 +2,14 @@ package ssh
 
 import (
 	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,7 +19,7 @@ import (
 // ReadPublicKey reads an SSH public key from a file
 func ReadPublicKey(path string) (string, error) {
 	// Expand tilde if present
-	if strings.HasPrefix(path, "~/") {
+	if strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
 		path = filepath.Join(GetHomeDir(), path[2:])
 	}
 
@@ -61,6 +65,21 @@ func ValidatePublicKey(key string) error {
 	return nil
 }
 
+// Fingerprint returns the OpenSSH SHA256 fingerprint of a public key, as
+// printed by ssh-keygen -l (e.g. "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8")
+func Fingerprint(publicKey string) (string, error) {
+	parts := strings.Fields(publicKey)
+	if len(parts) < 2 {
+		return "", fmt.Errorf("invalid SSH public key format: missing key data")
+	}
+	blob, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("invalid SSH public key data: %w", err)
+	}
+	sum := sha256.Sum256(blob)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:]), nil
+}
+
 // GenerateAuthorizedKeys generates authorized_keys content from a public key
 func GenerateAuthorizedKeys(publicKey string) string {
 	return "# Managed by l8s\n" + publicKey + "\n"
@@ -74,7 +93,7 @@ func GenerateAudioSSHConfigEntry(remoteHost, remoteUser string, audioPort int, k
 	// - L8s CA known_hosts for any container references
 	// Use accept-new to auto-accept first connection to the host
 	if knownHostsPath != "" {
-		return fmt.Sprintf(`Host l8s-audio
+		return forPlatform(fmt.Sprintf(`Host l8s-audio
     HostName %s
     User %s
     RemoteForward %d localhost:%d
@@ -87,11 +106,11 @@ func GenerateAudioSSHConfigEntry(remoteHost, remoteUser string, audioPort int, k
     ServerAliveCountMax 6
     ConnectTimeout 10
     TCPKeepAlive yes
-`, remoteHost, remoteUser, audioPort, audioPort, knownHostsPath)
+`, remoteHost, remoteUser, audioPort, audioPort, quoteConfigPath(knownHostsPath)), runtime.GOOS)
 	}
 
 	// Fallback to insecure mode if no CA configured
-	return fmt.Sprintf(`Host l8s-audio
+	return forPlatform(fmt.Sprintf(`Host l8s-audio
     HostName %s
     User %s
     RemoteForward %d localhost:%d
@@ -104,7 +123,7 @@ func GenerateAudioSSHConfigEntry(remoteHost, remoteUser string, audioPort int, k
     ServerAliveCountMax 6
     ConnectTimeout 10
     TCPKeepAlive yes
-`, remoteHost, remoteUser, audioPort, audioPort)
+`, remoteHost, remoteUser, audioPort, audioPort), runtime.GOOS)
 }
 
 // GenerateSSHConfigEntry generates an SSH config entry for a container
@@ -117,7 +136,7 @@ func GenerateSSHConfigEntry(containerName string, sshPort int, containerUser, pr
 
 	// If knownHostsPath is provided, use strict checking with CA
 	if knownHostsPath != "" {
-		return fmt.Sprintf(`Host %s
+		return forPlatform(fmt.Sprintf(`Host %s
     HostName %s
     Port %d
     User %s
@@ -130,11 +149,11 @@ func GenerateSSHConfigEntry(containerName string, sshPort int, containerUser, pr
     ServerAliveCountMax 6
     ConnectTimeout 10
     TCPKeepAlive yes
-`, hostAlias, remoteHost, sshPort, containerUser, knownHostsPath)
+`, hostAlias, remoteHost, sshPort, containerUser, quoteConfigPath(knownHostsPath)), runtime.GOOS)
 	}
 
 	// Fallback to insecure mode if no CA configured
-	return fmt.Sprintf(`Host %s
+	return forPlatform(fmt.Sprintf(`Host %s
     HostName %s
     Port %d
     User %s
@@ -147,7 +166,68 @@ func GenerateSSHConfigEntry(containerName string, sshPort int, containerUser, pr
     ServerAliveCountMax 6
     ConnectTimeout 10
     TCPKeepAlive yes
-`, hostAlias, remoteHost, sshPort, containerUser)
+`, hostAlias, remoteHost, sshPort, containerUser), runtime.GOOS)
+}
+
+// ExecEnvConfig renders exec_env entries as SendEnv lines for host variables
+// and SetEnv lines for fixed values, to append to a Host entry
+func ExecEnvConfig(entries []string) string {
+	var send, set []string
+	for _, entry := range entries {
+		name, value, fixed := config.SplitExecEnv(entry)
+		switch {
+		case !fixed:
+			send = append(send, name)
+		case strings.ContainsAny(value, " \t\""):
+			set = append(set, `"`+name+"="+strings.ReplaceAll(value, `"`, `\"`)+`"`)
+		default:
+			set = append(set, entry)
+		}
+	}
+	var b strings.Builder
+	if len(send) > 0 {
+		fmt.Fprintf(&b, "    SendEnv %s\n", strings.Join(send, " "))
+	}
+	if len(set) > 0 {
+		fmt.Fprintf(&b, "    SetEnv %s\n", strings.Join(set, " "))
+	}
+	return b.String()
+}
+
+// ProxyJumpConfig renders a connection's proxy_jump as a ProxyJump line to
+// append to a Host entry, or nothing when the host is reached directly
+func ProxyJumpConfig(jump string) string {
+	if jump == "" {
+		return ""
+	}
+	return fmt.Sprintf("    ProxyJump %s\n", jump)
+}
+
+// quoteConfigPath quotes paths containing spaces, common under Windows
+// profile directories, so ssh reads them as a single argument
+func quoteConfigPath(path string) string {
+	if strings.ContainsAny(path, " \t") {
+		return `"` + path + `"`
+	}
+	return path
+}
+
+// forPlatform adapts a generated SSH config entry to the host OS. Windows
+// OpenSSH has no connection multiplexing (ControlMaster needs Unix sockets)
+// and no /dev/null, so those lines are dropped or rewritten there.
+func forPlatform(entry, goos string) string {
+	if goos != "windows" {
+		return entry
+	}
+	lines := strings.Split(entry, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "Control") {
+			continue
+		}
+		kept = append(kept, strings.Replace(line, "/dev/null", "NUL", 1))
+	}
+	return strings.Join(kept, "\n")
 }
 
 // AddSSHConfigEntry adds an SSH config entry to the SSH config file
@@ -260,6 +340,53 @@ func RemoveSSHConfigEntry(configPath, containerName string) error {
 	return nil
 }
 
+// ConfigHost is a Host entry of an SSH config file
+type ConfigHost struct {
+	Name     string
+	HostName string
+	Port     int
+}
+
+// ListSSHConfigHosts returns the single-pattern Host entries of the SSH
+// config file at configPath; a missing file has none
+func ListSSHConfigHosts(configPath string) ([]ConfigHost, error) {
+	content, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSH config file: %w", err)
+	}
+
+	var hosts []ConfigHost
+	var current *ConfigHost
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch strings.ToLower(fields[0]) {
+		case "host":
+			current = nil
+			if len(fields) == 2 {
+				hosts = append(hosts, ConfigHost{Name: fields[1]})
+				current = &hosts[len(hosts)-1]
+			}
+		case "match":
+			current = nil
+		case "hostname":
+			if current != nil {
+				current.HostName = fields[1]
+			}
+		case "port":
+			if current != nil {
+				current.Port, _ = strconv.Atoi(fields[1])
+			}
+		}
+	}
+	return hosts, nil
+}
+
 // FindSSHPublicKey finds an SSH public key in standard locations
 func FindSSHPublicKey() (string, error) {
 	homeDir := GetHomeDir()
@@ -286,20 +413,24 @@ func FindSSHPublicKey() (string, error) {
 	return "", fmt.Errorf("no SSH public key found in %s", sshDir)
 }
 
-// IsPortAvailable checks if a port is available for use
+// IsPortAvailable checks if a port is available for use on both IPv4 and IPv6
 func IsPortAvailable(port int) bool {
-	// Try to listen on the port
+	// Try to listen on the port; on dual-stack hosts this covers both families
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
 		return false
 	}
 	listener.Close()
 	
-	// Also check if we can dial it (in case something is listening but not accepting)
-	conn, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", port), 100*time.Millisecond)
-	if err == nil {
-		conn.Close()
-		return false
+	// Also check if we can dial it (in case something is listening but not
+	// accepting), on each loopback address since localhost may resolve to
+	// either family and a listener may be bound to only one
+	for _, host := range []string{"127.0.0.1", "::1"} {
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), 100*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			return false
+		}
 	}
 	
 	return true
@@ -309,8 +440,11 @@ func IsPortAvailable(port int) bool {
 func GetHomeDir() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
-		// Fallback to HOME environment variable
+		// Fallback to HOME, or USERPROFILE on Windows
 		home = os.Getenv("HOME")
+		if home == "" {
+			home = os.Getenv("USERPROFILE")
+		}
 		if home == "" {
 			home = "/"
 		}
@@ -349,25 +483,30 @@ func AddSSHConfig(name, hostname string, port int, user string) error {
 		return err
 	}
 	
-	address, err := cfg.GetActiveAddress()
+	// Entries follow the configured connection, not a one-off --connection
+	conn, err := cfg.ConfiguredConnection()
 	if err != nil {
 		return err
 	}
 	
 	sshConfigPath := filepath.Join(GetHomeDir(), ".ssh", "config")
 	entry := GenerateSSHConfigEntry(
-		fmt.Sprintf("dev-%s", name), 
+		fmt.Sprintf("%s-%s", cfg.ContainerPrefix, name), 
 		port, 
 		user, 
-		"dev",
-		address, // Use connection address
+		cfg.ContainerPrefix,
+		conn.Address, // Use connection address
 		cfg.KnownHostsPath, // Pass known hosts path for CA trust
 	)
-	return AddSSHConfigEntry(sshConfigPath, entry)
+	return AddSSHConfigEntry(sshConfigPath, entry+ProxyJumpConfig(conn.ProxyJump)+ExecEnvConfig(cfg.ExecEnv))
 }
 
 // RemoveSSHConfig removes an SSH config entry for a container
 func RemoveSSHConfig(name string) error {
+	prefix := "dev"
+	if cfg, err := config.Load(config.GetConfigPath()); err == nil && cfg.ContainerPrefix != "" {
+		prefix = cfg.ContainerPrefix
+	}
 	sshConfigPath := filepath.Join(GetHomeDir(), ".ssh", "config")
-	return RemoveSSHConfigEntry(sshConfigPath, fmt.Sprintf("dev-%s", name))
+	return RemoveSSHConfigEntry(sshConfigPath, fmt.Sprintf("%s-%s", prefix, name))
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package ssh
 
 import (
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -132,6 +133,19 @@ func TestValidatePublicKey(t *testing.T) {
 	}
 }
 
+func TestFingerprint(t *testing.T) {
+	// Key blob is base64("test"), so the fingerprint is sha256("test")
+	fp, err := Fingerprint("ssh-ed25519 dGVzdA== user@host")
+	require.NoError(t, err)
+	assert.Equal(t, "SHA256:n4bQgYhMfWWaL+qgxVrQFaO/TxsrC4Is0V1sFbDwCgg", fp)
+
+	_, err = Fingerprint("ssh-ed25519")
+	assert.Error(t, err)
+
+	_, err = Fingerprint("ssh-ed25519 not-base64!")
+	assert.Error(t, err)
+}
+
 func TestGenerateAuthorizedKeys(t *testing.T) {
 	publicKey := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9pKe4 user@example.com"
 	
@@ -196,6 +210,27 @@ func TestSSHConfigEntry(t *testing.T) {
     ServerAliveCountMax 6
     ConnectTimeout 10
     TCPKeepAlive yes
+`,
+		},
+		{
+			name:          "IPv6 remote host is not bracketed",
+			containerName: "dev-v6",
+			sshPort:       2202,
+			containerUser: "dev",
+			remoteHost:    "2001:db8::5",
+			want: `Host dev-v6
+    HostName 2001:db8::5
+    Port 2202
+    User dev
+    StrictHostKeyChecking no
+    UserKnownHostsFile /dev/null
+    ControlMaster auto
+    ControlPath ~/.ssh/control-%r@%h:%p
+    ControlPersist 1h
+    ServerAliveInterval 30
+    ServerAliveCountMax 6
+    ConnectTimeout 10
+    TCPKeepAlive yes
 `,
 		},
 	}
@@ -208,6 +243,34 @@ func TestSSHConfigEntry(t *testing.T) {
 	}
 }
 
+func TestSSHConfigEntryForPlatform(t *testing.T) {
+	entry := GenerateSSHConfigEntry("dev-test", 2201, "dev", "dev", "remote.server.io", "")
+
+	assert.Equal(t, entry, forPlatform(entry, "darwin"))
+
+	windows := forPlatform(entry, "windows")
+	assert.NotContains(t, windows, "Control")
+	assert.Contains(t, windows, "UserKnownHostsFile NUL\n")
+	assert.Contains(t, windows, "ServerAliveInterval 30\n")
+	assert.True(t, strings.HasSuffix(windows, "TCPKeepAlive yes\n"))
+}
+
+func TestExecEnvConfig(t *testing.T) {
+	assert.Empty(t, ExecEnvConfig(nil))
+	assert.Equal(t, "    SendEnv LANG LC_*\n    SetEnv EDITOR=vim \"GREETING=hello world\"\n",
+		ExecEnvConfig([]string{"LANG", "EDITOR=vim", "LC_*", "GREETING=hello world"}))
+}
+
+func TestProxyJumpConfig(t *testing.T) {
+	assert.Empty(t, ProxyJumpConfig(""))
+	assert.Equal(t, "    ProxyJump me@bastion.example.com:2222\n", ProxyJumpConfig("me@bastion.example.com:2222"))
+}
+
+func TestQuoteConfigPath(t *testing.T) {
+	assert.Equal(t, "/home/dev/.config/l8s/known_hosts", quoteConfigPath("/home/dev/.config/l8s/known_hosts"))
+	assert.Equal(t, `"C:\Users\Jo Dev\.config\l8s\known_hosts"`, quoteConfigPath(`C:\Users\Jo Dev\.config\l8s\known_hosts`))
+}
+
 func TestManageSSHConfig(t *testing.T) {
 	t.Run("add new entry to empty config", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -298,6 +361,25 @@ Host dev-myproject
 	})
 }
 
+func TestListSSHConfigHosts(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	hosts, err := ListSSHConfigHosts(configPath)
+	require.NoError(t, err)
+	assert.Empty(t, hosts)
+
+	content := "Host github.com\n    User git\n\nHost *.corp bastion\n    Port 2022\n\n" +
+		GenerateSSHConfigEntry("dev-myproject", 2201, "dev", "dev", "10.0.0.1", "") +
+		"\nMatch host dev-*\n    Port 22\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0600))
+
+	hosts, err = ListSSHConfigHosts(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, []ConfigHost{
+		{Name: "github.com"},
+		{Name: "dev-myproject", HostName: "10.0.0.1", Port: 2201},
+	}, hosts)
+}
+
 func TestFindSSHPublicKey(t *testing.T) {
 	t.Run("find existing keys", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -347,13 +429,46 @@ func TestFindSSHPublicKey(t *testing.T) {
 	})
 }
 
+func TestAddSSHConfigIgnoresConnectionOverride(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	configDir := filepath.Join(home, ".config", "l8s")
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	data := `active_connection: home
+connections:
+  home:
+    address: 10.0.0.1
+  work:
+    address: 10.0.0.2
+    proxy_jump: bastion
+remote_user: podman
+`
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(data), 0644))
+
+	// A one-off --connection or L8S_CONNECTION must not repoint entries
+	t.Setenv("L8S_CONNECTION", "work")
+	require.NoError(t, AddSSHConfig("myproject", "", 2200, "dev"))
+
+	written, err := os.ReadFile(filepath.Join(home, ".ssh", "config"))
+	require.NoError(t, err)
+	assert.Contains(t, string(written), "HostName 10.0.0.1")
+	assert.NotContains(t, string(written), "10.0.0.2")
+	assert.NotContains(t, string(written), "ProxyJump")
+}
+
 func TestIsPortAvailable(t *testing.T) {
 	// Test with a likely available high port
 	available := IsPortAvailable(55555)
 	assert.True(t, available, "Port 55555 should be available")
 	
-	// Note: Testing unavailable ports is tricky in unit tests
-	// as it requires actually binding to a port
+	// A listener on IPv6 loopback only still makes the port unavailable
+	listener, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skip("IPv6 loopback not available")
+	}
+	defer listener.Close()
+	assert.False(t, IsPortAvailable(listener.Addr().(*net.TCPAddr).Port))
 }
 
 func TestCopySSHKeyToContainer(t *testing.T) {
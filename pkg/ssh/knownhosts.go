@@ -0,0 +1,159 @@
+package ssh
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"l8s/pkg/config"
+)
+
+// KnownHostsPruner finds known_hosts lines left behind by removed containers:
+// host keys recorded for container SSH ports that no container uses any more,
+// and CA trust lines for old CA keys or hosts that are no longer configured
+type KnownHostsPruner struct {
+	Host        string             // Address containers on the active connection are reached at
+	Ports       []config.PortRange // Container SSH port ranges
+	ActivePorts map[int]bool       // SSH ports of existing containers on Host
+	CAKey       string             // Current CA public key; empty keeps every @cert-authority line
+	CAHosts     []string           // Connection addresses the CA is trusted for
+}
+
+// Stale reports whether a known_hosts line belongs to a removed container or
+// an outdated CA. Comments, @revoked lines and other hosts are never stale.
+func (p KnownHostsPruner) Stale(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || strings.HasPrefix(fields[0], "#") {
+		return false
+	}
+
+	switch fields[0] {
+	case "@cert-authority":
+		return len(fields) >= 4 && p.staleCA(fields[1], fields[2]+" "+fields[3])
+	case "@revoked":
+		return false
+	}
+
+	for _, name := range strings.Split(fields[0], ",") {
+		if port, ok := p.containerPort(name); ok && !p.ActivePorts[port] {
+			return true
+		}
+	}
+	return false
+}
+
+// staleCA checks a @cert-authority line as written by CA.WriteKnownHostsEntry
+func (p KnownHostsPruner) staleCA(patterns, key string) bool {
+	if p.CAKey == "" {
+		return false
+	}
+	if current := strings.Fields(p.CAKey); len(current) >= 2 && key != current[0]+" "+current[1] {
+		return true
+	}
+	found := false
+	for _, pattern := range strings.Split(patterns, ",") {
+		if !strings.HasPrefix(pattern, "[") {
+			continue
+		}
+		host, _, _ := strings.Cut(strings.TrimPrefix(pattern, "["), "]")
+		found = true
+		for _, h := range p.CAHosts {
+			if h == host {
+				return false
+			}
+		}
+	}
+	return found
+}
+
+// containerPort returns the port of a known_hosts name of the form
+// [Host]:port in the container port ranges, plain or hashed
+func (p KnownHostsPruner) containerPort(name string) (int, bool) {
+	if strings.HasPrefix(name, "|1|") {
+		for _, r := range p.Ports {
+			for port := r.First; port <= r.Last; port++ {
+				if hashedHostMatches(name, fmt.Sprintf("[%s]:%d", p.Host, port)) {
+					return port, true
+				}
+			}
+		}
+		return 0, false
+	}
+
+	host, portStr, ok := strings.Cut(strings.TrimPrefix(name, "["), "]:")
+	if !ok || !strings.HasPrefix(name, "[") || host != p.Host {
+		return 0, false
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return 0, false
+	}
+	for _, r := range p.Ports {
+		if port >= r.First && port <= r.Last {
+			return port, true
+		}
+	}
+	return 0, false
+}
+
+// hashedHostMatches checks a HashKnownHosts name (|1|salt|hash) against a host
+func hashedHostMatches(hashed, host string) bool {
+	parts := strings.Split(hashed, "|")
+	if len(parts) != 4 {
+		return false
+	}
+	salt, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha1.New, salt)
+	mac.Write([]byte(host))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)) == parts[3]
+}
+
+// Prune removes stale lines from a known_hosts file and returns them. With
+// dryRun the file is left alone. A missing file has nothing to prune.
+func (p KnownHostsPruner) Prune(path string, dryRun bool) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var kept, removed []string
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		if p.Stale(line) {
+			removed = append(removed, line)
+		} else {
+			kept = append(kept, line)
+		}
+	}
+	if len(removed) == 0 || dryRun {
+		return removed, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	content := strings.Join(kept, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	// Write beside the original and rename so ssh never sees a partial file
+	tmp := path + ".l8s-tmp"
+	if err := os.WriteFile(tmp, []byte(content), info.Mode().Perm()); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return removed, nil
+}
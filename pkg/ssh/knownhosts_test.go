@@ -0,0 +1,86 @@
+package ssh
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"l8s/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hashHost hashes a known_hosts name the way ssh-keygen -H does
+func hashHost(salt []byte, host string) string {
+	mac := hmac.New(sha1.New, salt)
+	mac.Write([]byte(host))
+	return "|1|" + base64.StdEncoding.EncodeToString(salt) + "|" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestKnownHostsPruner(t *testing.T) {
+	const key = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9pKe4"
+	const oldCA = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOldCAoldCAoldCAoldCAoldCAoldCAoldCAoldCA"
+	p := KnownHostsPruner{
+		Host:        "10.0.0.5",
+		Ports:       []config.PortRange{{First: 2200, Last: 2299}, {First: 4400, Last: 4499}},
+		ActivePorts: map[int]bool{2200: true},
+		CAKey:       key + " l8s-ca",
+		CAHosts:     []string{"10.0.0.5"},
+	}
+
+	tests := []struct {
+		name  string
+		line  string
+		stale bool
+	}{
+		{"active container", "[10.0.0.5]:2200 " + key, false},
+		{"removed container", "[10.0.0.5]:2201 " + key, true},
+		{"hashed removed container", hashHost([]byte("0123456789abcdefghij"), "[10.0.0.5]:2205") + " " + key, true},
+		{"hashed active container", hashHost([]byte("0123456789abcdefghij"), "[10.0.0.5]:2200") + " " + key, false},
+		{"remote host sshd", "10.0.0.5 " + key, false},
+		{"port outside range", "[10.0.0.5]:22 " + key, false},
+		{"removed container in second range", "[10.0.0.5]:4401 " + key, true},
+		{"port between ranges", "[10.0.0.5]:3000 " + key, false},
+		{"other host", "[github.com]:2201 " + key, false},
+		{"comment", "# [10.0.0.5]:2201 " + key, false},
+		{"current CA", "@cert-authority dev-*,[10.0.0.5]:* " + key, false},
+		{"old CA key", "@cert-authority dev-*,[10.0.0.5]:* " + oldCA, true},
+		{"CA for removed connection", "@cert-authority dev-*,[10.9.9.9]:* " + key, true},
+		{"revoked", "@revoked [10.0.0.5]:2201 " + key, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.stale, p.Stale(tt.line))
+		})
+	}
+
+	t.Run("prune rewrites the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "known_hosts")
+		content := "[10.0.0.5]:2200 " + key + "\n[10.0.0.5]:2201 " + key + "\ngithub.com " + key + "\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+		removed, err := p.Prune(path, true)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"[10.0.0.5]:2201 " + key}, removed)
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, content, string(data), "dry run must not change the file")
+
+		removed, err = p.Prune(path, false)
+		require.NoError(t, err)
+		assert.Len(t, removed, 1)
+		data, _ = os.ReadFile(path)
+		assert.Equal(t, "[10.0.0.5]:2200 "+key+"\ngithub.com "+key+"\n", string(data))
+		info, _ := os.Stat(path)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		removed, err := p.Prune(filepath.Join(t.TempDir(), "none"), false)
+		assert.NoError(t, err)
+		assert.Empty(t, removed)
+	})
+}
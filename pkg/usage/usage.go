@@ -0,0 +1,137 @@
+package usage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// FileName is the statistics file kept in the l8s state directory
+const FileName = "usage.json"
+
+// Entry aggregates every recorded run of one command
+type Entry struct {
+	Command  string        `json:"command"`
+	Count    int           `json:"count"`
+	Failures int           `json:"failures"`
+	Total    time.Duration `json:"total_ns"`
+	Max      time.Duration `json:"max_ns"`
+	LastRun  time.Time     `json:"last_run"`
+}
+
+// Average is the mean duration of a run
+func (e Entry) Average() time.Duration {
+	if e.Count == 0 {
+		return 0
+	}
+	return e.Total / time.Duration(e.Count)
+}
+
+// Store keeps command statistics in a local JSON file. Nothing is ever sent
+// over the network.
+type Store struct {
+	path string
+}
+
+// NewStore returns a store backed by the file at path
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Record adds a run of command that took d
+func (s *Store) Record(command string, d time.Duration, failed bool, at time.Time) error {
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	e := entries[command]
+	e.Command = command
+	e.Count++
+	if failed {
+		e.Failures++
+	}
+	e.Total += d
+	if d > e.Max {
+		e.Max = d
+	}
+	e.LastRun = at
+	entries[command] = e
+
+	return s.write(entries)
+}
+
+// Entries returns recorded statistics, most total time first
+func (s *Store) Entries() ([]Entry, error) {
+	entries, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Total != list[j].Total {
+			return list[i].Total > list[j].Total
+		}
+		return list[i].Command < list[j].Command
+	})
+	return list, nil
+}
+
+// Reset deletes all recorded statistics
+func (s *Store) Reset() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove usage statistics: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) read() (map[string]Entry, error) {
+	entries := make(map[string]Entry)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage statistics: %w", err)
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse usage statistics %s: %w", s.path, err)
+	}
+	return entries, nil
+}
+
+// write replaces the file atomically so concurrent runs never see a partial file
+func (s *Store) write(entries map[string]Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode usage statistics: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".usage-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write usage statistics: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write usage statistics: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write usage statistics: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write usage statistics: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package usage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "state", FileName))
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	entries, err := store.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	require.NoError(t, store.Record("create", 40*time.Second, false, now))
+	require.NoError(t, store.Record("create", 20*time.Second, true, now.Add(time.Hour)))
+	require.NoError(t, store.Record("list", time.Second, false, now))
+
+	entries, err = store.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	create := entries[0]
+	assert.Equal(t, "create", create.Command)
+	assert.Equal(t, 2, create.Count)
+	assert.Equal(t, 1, create.Failures)
+	assert.Equal(t, 60*time.Second, create.Total)
+	assert.Equal(t, 40*time.Second, create.Max)
+	assert.Equal(t, 30*time.Second, create.Average())
+	assert.True(t, create.LastRun.Equal(now.Add(time.Hour)))
+	assert.Equal(t, "list", entries[1].Command)
+
+	require.NoError(t, store.Reset())
+	require.NoError(t, store.Reset())
+	entries, err = store.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}